@@ -2,10 +2,12 @@ package handler
 
 import (
 	"database/sql"
+	"log"
 	"net/http"
 	"os"
 
 	"detailingpass/pkg/auth"
+	"detailingpass/pkg/db"
 	"detailingpass/pkg/server"
 
 	"github.com/labstack/echo/v4"
@@ -29,7 +31,21 @@ CREATE TABLE IF NOT EXISTS packages (
     is_active BOOLEAN DEFAULT 1,
     sort_order INTEGER DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    features TEXT,
+    included TEXT,
+    excluded TEXT,
+    required_skills TEXT
+);
+
+CREATE TABLE IF NOT EXISTS package_faqs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    package_id INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    sort_order INTEGER DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE CASCADE
 );
 
 CREATE TABLE IF NOT EXISTS gallery_groups (
@@ -43,7 +59,9 @@ CREATE TABLE IF NOT EXISTS gallery_groups (
     is_featured BOOLEAN DEFAULT 0,
     sort_order INTEGER DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    package_id INTEGER,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE SET NULL
 );
 
 CREATE TABLE IF NOT EXISTS media (
@@ -53,8 +71,21 @@ CREATE TABLE IF NOT EXISTS media (
     kind TEXT DEFAULT 'gallery',
     sort_order INTEGER DEFAULT 0,
     alt_text TEXT,
+    is_private BOOLEAN DEFAULT 0,
+    pair_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (gallery_group_id) REFERENCES gallery_groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (pair_id) REFERENCES media(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS media_variants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    media_id INTEGER NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    url TEXT NOT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (gallery_group_id) REFERENCES gallery_groups(id) ON DELETE CASCADE
+    FOREIGN KEY (media_id) REFERENCES media(id) ON DELETE CASCADE
 );
 
 CREATE TABLE IF NOT EXISTS reviews (
@@ -64,7 +95,28 @@ CREATE TABLE IF NOT EXISTS reviews (
     body TEXT,
     source TEXT,
     is_featured BOOLEAN DEFAULT 0,
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    status TEXT DEFAULT 'approved',
+    booking_id INTEGER,
+    gallery_group_id INTEGER,
+    follow_up_status TEXT,
+    follow_up_notes TEXT,
+    external_id TEXT,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL,
+    FOREIGN KEY (gallery_group_id) REFERENCES gallery_groups(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS review_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    booking_id INTEGER NOT NULL,
+    gallery_group_id INTEGER,
+    email TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    used_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE,
+    FOREIGN KEY (gallery_group_id) REFERENCES gallery_groups(id) ON DELETE SET NULL
 );
 
 CREATE TABLE IF NOT EXISTS bookings (
@@ -82,13 +134,445 @@ CREATE TABLE IF NOT EXISTS bookings (
     internal_notes TEXT,
     clerk_user_id TEXT,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    promo_code TEXT,
+    discount_type TEXT,
+    discount_value INTEGER NOT NULL DEFAULT 0,
+    membership_id INTEGER REFERENCES memberships(id) ON DELETE SET NULL,
+    series_id INTEGER REFERENCES booking_series(id) ON DELETE SET NULL,
+    service_address TEXT,
+    service_zip TEXT,
+    service_lat REAL,
+    service_lng REAL,
+    travel_minutes INTEGER NOT NULL DEFAULT 0,
+    travel_fee INTEGER NOT NULL DEFAULT 0,
+    staff_id INTEGER REFERENCES staff(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS contact_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    service_interest TEXT,
+    message TEXT NOT NULL,
+    ip_address TEXT,
+    is_read BOOLEAN DEFAULT 0,
+    replied_at DATETIME,
+    booking_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS contact_replies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    message_id INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    sent_by TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (message_id) REFERENCES contact_messages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS addons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT,
+    price INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT 1,
+    sort_order INTEGER DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS quotes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    vehicle_details TEXT,
+    notes TEXT,
+    status TEXT DEFAULT 'draft',
+    version INTEGER DEFAULT 0,
+    expires_at DATETIME,
+    sent_at DATETIME,
+    accepted_at DATETIME,
+    booking_id INTEGER,
+    contact_message_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    promo_code_id INTEGER REFERENCES promo_codes(id) ON DELETE SET NULL,
+    promo_code TEXT,
+    discount_type TEXT,
+    discount_value INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL,
+    FOREIGN KEY (contact_message_id) REFERENCES contact_messages(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS quote_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quote_id INTEGER NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'custom',
+    package_id INTEGER,
+    addon_id INTEGER,
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_price INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (quote_id) REFERENCES quotes(id) ON DELETE CASCADE,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE SET NULL,
+    FOREIGN KEY (addon_id) REFERENCES addons(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS quote_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quote_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    items TEXT NOT NULL,
+    total INTEGER NOT NULL,
+    expires_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (quote_id) REFERENCES quotes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    rate INTEGER NOT NULL,
+    is_default BOOLEAN DEFAULT 0,
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    number TEXT UNIQUE,
+    booking_id INTEGER,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    vehicle_details TEXT,
+    status TEXT DEFAULT 'draft',
+    tax_name TEXT,
+    tax_rate INTEGER NOT NULL DEFAULT 0,
+    discount_type TEXT,
+    discount_value INTEGER NOT NULL DEFAULT 0,
+    notes TEXT,
+    issued_at DATETIME,
+    due_at DATETIME,
+    sent_at DATETIME,
+    paid_at DATETIME,
+    payment_method TEXT,
+    voided_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoice_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'custom',
+    package_id INTEGER,
+    addon_id INTEGER,
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_price INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE SET NULL,
+    FOREIGN KEY (addon_id) REFERENCES addons(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    year INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    booking_id INTEGER,
+    kind TEXT NOT NULL DEFAULT 'deposit',
+    provider TEXT NOT NULL,
+    checkout_id TEXT,
+    payment_ref TEXT,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'usd',
+    status TEXT NOT NULL DEFAULT 'pending',
+    refund_ref TEXT,
+    refunded_amount INTEGER NOT NULL DEFAULT 0,
+    paid_at DATETIME,
+    refunded_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS payment_events (
+    id TEXT PRIMARY KEY,
+    type TEXT,
+    received_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT UNIQUE NOT NULL,
+    description TEXT,
+    discount_type TEXT NOT NULL,
+    discount_value INTEGER NOT NULL,
+    starts_at DATETIME,
+    ends_at DATETIME,
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    max_uses_per_customer INTEGER NOT NULL DEFAULT 0,
+    min_spend INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS promo_code_packages (
+    promo_code_id INTEGER NOT NULL,
+    package_id INTEGER NOT NULL,
+    PRIMARY KEY (promo_code_id, package_id),
+    FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id) ON DELETE CASCADE,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    promo_code_id INTEGER NOT NULL,
+    booking_id INTEGER,
+    quote_id INTEGER,
+    email TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id) ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL,
+    FOREIGN KEY (quote_id) REFERENCES quotes(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS gift_certificates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE,
+    initial_value INTEGER NOT NULL,
+    balance INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'active',
+    source TEXT NOT NULL DEFAULT 'manual',
+    purchaser_name TEXT,
+    purchaser_email TEXT,
+    recipient_name TEXT,
+    recipient_email TEXT,
+    message TEXT,
+    payment_id INTEGER,
+    expires_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS gift_certificate_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    gift_certificate_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    balance_after INTEGER NOT NULL,
+    invoice_id INTEGER,
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (gift_certificate_id) REFERENCES gift_certificates(id) ON DELETE CASCADE,
+    FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS membership_plans (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT,
+    package_id INTEGER,
+    services_per_period INTEGER NOT NULL,
+    period_months INTEGER NOT NULL DEFAULT 1,
+    price INTEGER NOT NULL,
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS memberships (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    plan_id INTEGER NOT NULL,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    clerk_user_id TEXT,
+    status TEXT NOT NULL DEFAULT 'active',
+    started_at DATETIME NOT NULL,
+    paid_through DATETIME NOT NULL,
+    cancelled_at DATETIME,
+    cancel_reason TEXT,
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (plan_id) REFERENCES membership_plans(id)
+);
+
+CREATE TABLE IF NOT EXISTS membership_renewals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    membership_id INTEGER NOT NULL,
+    period_start DATETIME NOT NULL,
+    period_end DATETIME NOT NULL,
+    amount INTEGER NOT NULL,
+    payment_method TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (membership_id) REFERENCES memberships(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS booking_series (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    vehicle_details TEXT,
+    service_interest TEXT,
+    notes TEXT,
+    rrule TEXT NOT NULL,
+    starts_at DATETIME NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active',
+    booked_through DATETIME NOT NULL,
+    previous_id INTEGER,
+    cancelled_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (previous_id) REFERENCES booking_series(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS booking_series_occurrences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    series_id INTEGER NOT NULL,
+    scheduled_at DATETIME NOT NULL,
+    booking_id INTEGER,
+    status TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (series_id, scheduled_at),
+    FOREIGN KEY (series_id) REFERENCES booking_series(id) ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    vehicle_details TEXT,
+    service_interest TEXT,
+    notes TEXT,
+    date_from DATETIME NOT NULL,
+    date_to DATETIME NOT NULL,
+    slot_id TEXT,
+    clerk_user_id TEXT,
+    status TEXT NOT NULL DEFAULT 'waiting',
+    booking_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS waitlist_offers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entry_id INTEGER NOT NULL,
+    slot_start DATETIME NOT NULL,
+    slot_end DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open',
+    booking_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (entry_id) REFERENCES waitlist_entries(id) ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS slot_holds (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT NOT NULL UNIQUE,
+    slot_start DATETIME NOT NULL,
+    slot_end DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    ip_address TEXT,
+    status TEXT NOT NULL DEFAULT 'held',
+    booking_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS staff (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    email TEXT,
+    phone TEXT,
+    role TEXT NOT NULL DEFAULT 'technician',
+    skills TEXT,
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS staff_hours (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    staff_id INTEGER NOT NULL,
+    weekday INTEGER NOT NULL,
+    start_minute INTEGER NOT NULL,
+    end_minute INTEGER NOT NULL,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
+    UNIQUE (staff_id, weekday)
+);
+
+CREATE TABLE IF NOT EXISTS staff_time_off (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    staff_id INTEGER NOT NULL,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    reason TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_package_faqs_package_id ON package_faqs(package_id);
 CREATE INDEX IF NOT EXISTS idx_gallery_groups_featured ON gallery_groups(is_featured);
 CREATE INDEX IF NOT EXISTS idx_media_gallery_group_id ON media(gallery_group_id);
+CREATE INDEX IF NOT EXISTS idx_media_variants_media_id ON media_variants(media_id);
 CREATE INDEX IF NOT EXISTS idx_bookings_requested_start ON bookings(requested_start);
 CREATE INDEX IF NOT EXISTS idx_bookings_status ON bookings(status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_source_external_id ON reviews(source, external_id);
+CREATE INDEX IF NOT EXISTS idx_contact_messages_created_at ON contact_messages(created_at);
+CREATE INDEX IF NOT EXISTS idx_contact_messages_ip ON contact_messages(ip_address, created_at);
+CREATE INDEX IF NOT EXISTS idx_contact_replies_message_id ON contact_replies(message_id);
+CREATE INDEX IF NOT EXISTS idx_quotes_status ON quotes(status);
+CREATE INDEX IF NOT EXISTS idx_quote_items_quote_id ON quote_items(quote_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_versions_quote_version ON quote_versions(quote_id, version);
+CREATE INDEX IF NOT EXISTS idx_invoices_status ON invoices(status);
+CREATE INDEX IF NOT EXISTS idx_invoices_booking_id ON invoices(booking_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_email ON invoices(email);
+CREATE INDEX IF NOT EXISTS idx_invoice_items_invoice_id ON invoice_items(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_payments_booking_id ON payments(booking_id);
+CREATE INDEX IF NOT EXISTS idx_payments_checkout_id ON payments(checkout_id);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_code ON promo_redemptions(promo_code_id, email);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_booking_id ON promo_redemptions(booking_id);
+CREATE INDEX IF NOT EXISTS idx_gift_certificates_payment_id ON gift_certificates(payment_id);
+CREATE INDEX IF NOT EXISTS idx_gift_certificate_transactions_certificate ON gift_certificate_transactions(gift_certificate_id);
+CREATE INDEX IF NOT EXISTS idx_gift_certificate_transactions_invoice_id ON gift_certificate_transactions(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_memberships_clerk_user_id ON memberships(clerk_user_id);
+CREATE INDEX IF NOT EXISTS idx_memberships_email ON memberships(email);
+CREATE INDEX IF NOT EXISTS idx_membership_renewals_membership_id ON membership_renewals(membership_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_membership_id ON bookings(membership_id);
+CREATE INDEX IF NOT EXISTS idx_booking_series_status ON booking_series(status, booked_through);
+CREATE INDEX IF NOT EXISTS idx_booking_series_occurrences_booking_id ON booking_series_occurrences(booking_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_series_id ON bookings(series_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_status ON waitlist_entries(status, date_from, date_to);
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers_entry_id ON waitlist_offers(entry_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers_open ON waitlist_offers(status, slot_start);
+CREATE INDEX IF NOT EXISTS idx_slot_holds_status ON slot_holds(status, slot_start);
+CREATE INDEX IF NOT EXISTS idx_staff_time_off_staff_id ON staff_time_off(staff_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_bookings_staff_id ON bookings(staff_id, requested_start);
 `
 
 // Seed data for Ford vehicle gallery
@@ -274,19 +758,24 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 
 	// Static files
 	e.Static("/static", "web/static")
-	e.Static("/uploads", "web/static/uploads")
 	e.File("/favicon.png", "public/favicon.png")
 	e.File("/favicon.ico", "public/favicon.png")
 	e.File("/robots.txt", "public/robots.txt")
-	e.File("/sitemap.xml", "public/sitemap.xml")
 
 	// Initialize database
-	db, _ := sql.Open("sqlite", ":memory:")
-	db.Exec(schema)
-	db.Exec(seedData)
+	conn, _ := sql.Open("sqlite", ":memory:")
+	conn.Exec(schema)
+	db.ApplyColumnMigrations(r.Context(), conn)
+	conn.Exec(seedData)
 
 	// Setup routes
-	server.SetupRoutes(e, db)
+	// Each request gets a fresh database, so there are no scheduled jobs
+	// to run
+	if _, err := server.SetupRoutes(e, conn); err != nil {
+		log.Printf("Failed to set up server: %v", err)
+		http.Error(w, "Server misconfigured", http.StatusInternalServerError)
+		return
+	}
 
 	// Serve the request
 	e.ServeHTTP(w, r)
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/imaging"
+	"detailingpass/pkg/storage"
+
+	_ "modernc.org/sqlite"
+)
+
+// mediaFile abstracts where a media original lives: a file under the static
+// directory or an object in media storage.
+type mediaFile interface {
+	Read(ctx context.Context) ([]byte, error)
+	// Write stores a file alongside the original and returns its media ref.
+	Write(ctx context.Context, name string, data []byte, contentType string) (string, error)
+	Name() string
+}
+
+// Backfills responsive variants for media rows that predate upload
+// processing (the seeded web/static/images/gallery photos and any earlier
+// uploads). Originals are rewritten without EXIF metadata unless
+// -keep-originals is set. Uploads are read through the storage backend
+// configured by STORAGE_DRIVER.
+func main() {
+	defaultDB := os.Getenv("DATABASE_PATH")
+	if defaultDB == "" {
+		defaultDB = "./data/detailing.db"
+	}
+
+	dbPath := flag.String("db", defaultDB, "path to the SQLite database")
+	staticDir := flag.String("static-dir", "web/static", "directory served at /static")
+	keepOriginals := flag.Bool("keep-originals", false, "do not rewrite originals with metadata stripped")
+	dryRun := flag.Bool("dry-run", false, "report what would be processed without writing anything")
+	flag.Parse()
+
+	conn, err := sql.Open("sqlite", *dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	queries := db.New(conn)
+
+	media, err := queries.ListMediaWithoutVariants(ctx)
+	if err != nil {
+		log.Fatalf("Failed to list media: %v", err)
+	}
+
+	store, err := storage.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %v", err)
+	}
+
+	fmt.Printf("Found %d media items without variants\n", len(media))
+
+	processed, skipped := 0, 0
+	for _, m := range media {
+		file, ok := resolveMediaFile(m.Url, *staticDir, store)
+		if !ok {
+			fmt.Printf("  Skipping %s (not a local file or stored upload)\n", m.Url)
+			skipped++
+			continue
+		}
+
+		data, err := file.Read(ctx)
+		if err != nil {
+			fmt.Printf("  Skipping %s: %v\n", m.Url, err)
+			skipped++
+			continue
+		}
+
+		result, err := imaging.Process(data, imaging.DefaultWidths)
+		if err != nil {
+			fmt.Printf("  Skipping %s: %v\n", m.Url, err)
+			skipped++
+			continue
+		}
+
+		if *dryRun {
+			fmt.Printf("  Would process %s (%dx%d, %d variants)\n", m.Url, result.Width, result.Height, len(result.Variants))
+			processed++
+			continue
+		}
+
+		if !*keepOriginals {
+			if _, err := file.Write(ctx, file.Name(), result.Original, result.ContentType); err != nil {
+				log.Fatalf("Failed to rewrite %s: %v", m.Url, err)
+			}
+		}
+
+		for _, v := range result.Variants {
+			ref, err := file.Write(ctx, imaging.VariantName(file.Name(), v.Width, result.Ext), v.Data, result.ContentType)
+			if err != nil {
+				log.Fatalf("Failed to write variant for %s: %v", m.Url, err)
+			}
+
+			_, err = queries.CreateMediaVariant(ctx, db.CreateMediaVariantParams{
+				MediaID: m.ID,
+				Width:   int64(v.Width),
+				Height:  int64(v.Height),
+				Url:     ref,
+			})
+			if err != nil {
+				log.Fatalf("Failed to record variant for %s: %v", m.Url, err)
+			}
+		}
+
+		fmt.Printf("  ✅ %s (%d variants)\n", m.Url, len(result.Variants))
+		processed++
+	}
+
+	fmt.Printf("\n✅ Backfill complete: %d processed, %d skipped\n", processed, skipped)
+}
+
+// resolveMediaFile maps a media ref onto where its bytes live. "/static/"
+// paths are files under staticDir; storage keys (and "/uploads/" paths from
+// before storage keys) are read from store.
+func resolveMediaFile(ref, staticDir string, store storage.Storage) (mediaFile, bool) {
+	if strings.HasPrefix(ref, "/static/") {
+		rel, err := storage.CleanKey(strings.TrimPrefix(ref, "/static/"))
+		if err != nil {
+			return nil, false
+		}
+		return staticFile{dir: staticDir, rel: rel}, true
+	}
+
+	if strings.HasPrefix(ref, "/uploads/") {
+		ref = strings.TrimPrefix(ref, "/uploads/")
+	} else if strings.HasPrefix(ref, "/") || strings.Contains(ref, "://") {
+		return nil, false
+	}
+	key, err := storage.CleanKey(ref)
+	if err != nil {
+		return nil, false
+	}
+	return storedFile{store: store, key: key}, true
+}
+
+type staticFile struct {
+	dir string
+	rel string
+}
+
+func (f staticFile) Name() string { return path.Base(f.rel) }
+
+func (f staticFile) Read(ctx context.Context) ([]byte, error) {
+	return os.ReadFile(filepath.Join(f.dir, filepath.FromSlash(f.rel)))
+}
+
+func (f staticFile) Write(ctx context.Context, name string, data []byte, contentType string) (string, error) {
+	rel := path.Join(path.Dir(f.rel), name)
+	if err := os.WriteFile(filepath.Join(f.dir, filepath.FromSlash(rel)), data, 0644); err != nil {
+		return "", err
+	}
+	return "/static/" + rel, nil
+}
+
+type storedFile struct {
+	store storage.Storage
+	key   string
+}
+
+func (f storedFile) Name() string { return path.Base(f.key) }
+
+func (f storedFile) Read(ctx context.Context) ([]byte, error) {
+	r, err := f.store.Get(ctx, f.key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (f storedFile) Write(ctx context.Context, name string, data []byte, contentType string) (string, error) {
+	key := path.Join(path.Dir(f.key), name)
+	if err := f.store.Put(ctx, key, data, contentType); err != nil {
+		return "", err
+	}
+	return key, nil
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
 
+	"detailingpass/pkg/db"
 	"detailingpass/pkg/server"
 
 	_ "embed"
@@ -19,9 +21,13 @@ import (
 //go:embed schema.sql
 var schema string
 
-func runMigrations(db *sql.DB) error {
-	_, err := db.Exec(schema)
-	return err
+func runMigrations(conn *sql.DB) error {
+	db.ApplyColumnMigrations(context.Background(), conn)
+	if _, err := conn.Exec(schema); err != nil {
+		return err
+	}
+	db.ApplyColumnMigrations(context.Background(), conn)
+	return nil
 }
 
 func main() {
@@ -47,18 +53,18 @@ func main() {
 		log.Fatalf("Failed to create data directory: %v", err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	conn, err := db.Open(dbPath)
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
-	defer db.Close()
+	defer conn.Close()
 
-	if err := db.Ping(); err != nil {
+	if err := conn.Ping(); err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
 	// Run migrations
-	if err := runMigrations(db); err != nil {
+	if err := runMigrations(conn); err != nil {
 		log.Printf("Warning: Failed to run migrations: %v", err)
 	}
 
@@ -74,14 +80,18 @@ func main() {
 
 	// Static files
 	e.Static("/static", "web/static")
-	e.Static("/uploads", "web/static/uploads")
 	e.File("/favicon.png", "public/favicon.png")
 	e.File("/favicon.ico", "public/favicon.png")
 	e.File("/robots.txt", "public/robots.txt")
-	e.File("/sitemap.xml", "public/sitemap.xml")
 
 	// Setup routes
-	server.SetupRoutes(e, db)
+	h, err := server.SetupRoutes(e, conn)
+	if err != nil {
+		log.Fatalf("Failed to set up server: %v", err)
+	}
+
+	// Repeat bookings and other scheduled work
+	go h.RunJobs(context.Background())
 
 	// Start server
 	addr := fmt.Sprintf("0.0.0.0:%s", port)
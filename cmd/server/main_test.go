@@ -0,0 +1,49 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestRunMigrations(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup string
+	}{
+		{name: "fresh database"},
+		{name: "promo codes from before min spend", setup: `CREATE TABLE promo_codes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			code TEXT UNIQUE NOT NULL,
+			discount_type TEXT NOT NULL,
+			discount_value INTEGER NOT NULL
+		)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := sql.Open("sqlite", ":memory:")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			conn.SetMaxOpenConns(1)
+			if tt.setup != "" {
+				if _, err := conn.Exec(tt.setup); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// The columns are there after the first startup, and a second
+			// changes nothing
+			for run := 1; run <= 2; run++ {
+				if err := runMigrations(conn); err != nil {
+					t.Fatalf("run %d: %v", run, err)
+				}
+				if _, err := conn.Exec(`SELECT min_spend, staff_id FROM promo_codes, bookings`); err != nil {
+					t.Errorf("run %d: columns missing: %v", run, err)
+				}
+			}
+		})
+	}
+}
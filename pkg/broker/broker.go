@@ -0,0 +1,59 @@
+// Package broker fans events out to subscribers in the same process. It
+// is for telling open pages that something changed, not for delivering
+// work: a subscriber that falls behind misses events rather than holding
+// up the publisher, and nothing is shared between server instances.
+package broker
+
+import "sync"
+
+// Broker publishes events of type T to every current subscriber.
+type Broker[T any] struct {
+	mu     sync.Mutex
+	subs   map[chan T]struct{}
+	buffer int
+}
+
+// New returns a broker whose subscribers can fall buffer events behind
+// before they start missing them.
+func New[T any](buffer int) *Broker[T] {
+	return &Broker[T]{subs: make(map[chan T]struct{}), buffer: buffer}
+}
+
+// Subscribe returns a channel of the events published from now on, and a
+// function that ends the subscription and closes the channel.
+func (b *Broker[T]) Subscribe() (<-chan T, func()) {
+	ch := make(chan T, b.buffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends event to every subscriber without waiting. Subscribers
+// whose buffer is full don't get it.
+func (b *Broker[T]) Publish(event T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribers is how many subscriptions are open.
+func (b *Broker[T]) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
@@ -0,0 +1,74 @@
+package broker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPublishToSlowSubscriber(t *testing.T) {
+	b := New[int](2)
+	slow, unsubscribeSlow := b.Subscribe()
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := b.Subscribe()
+	defer unsubscribeFast()
+
+	// The slow subscriber never reads, so its buffer fills after two
+	// events; publishing must carry on regardless.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 5; i++ {
+			b.Publish(i)
+			if got := <-fast; got != i {
+				t.Errorf("fast subscriber got %d, want %d", got, i)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a subscriber that isn't reading")
+	}
+
+	// It kept the events that fit and missed the rest
+	for _, want := range []int{1, 2} {
+		select {
+		case got := <-slow:
+			if got != want {
+				t.Errorf("slow subscriber got %d, want %d", got, want)
+			}
+		default:
+			t.Fatalf("slow subscriber missing event %d", want)
+		}
+	}
+	select {
+	case got := <-slow:
+		t.Errorf("slow subscriber got %d after its buffer filled", got)
+	default:
+	}
+
+	// Once it catches up it gets new events again
+	b.Publish(6)
+	if got := <-slow; got != 6 {
+		t.Errorf("slow subscriber got %d, want 6", got)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	b := New[string](1)
+	ch, unsubscribe := b.Subscribe()
+	if n := b.Subscribers(); n != 1 {
+		t.Fatalf("Subscribers() = %d, want 1", n)
+	}
+
+	unsubscribe()
+	unsubscribe() // a second call is harmless
+	if n := b.Subscribers(); n != 0 {
+		t.Errorf("Subscribers() = %d, want 0", n)
+	}
+	if _, ok := <-ch; ok {
+		t.Error("channel still open after unsubscribing")
+	}
+	// Nothing is sent on the closed channel
+	b.Publish("booked")
+}
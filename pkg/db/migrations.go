@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ColumnMigrations adds columns introduced after a table first shipped.
+// CREATE TABLE IF NOT EXISTS leaves existing tables untouched, so these run
+// on every startup, both before the schema (so its indexes find the
+// columns) and after it (so a table the schema has just created from an
+// older copy still gets them); "duplicate column" and "no such table"
+// errors are expected and ignored.
+var ColumnMigrations = []string{
+	"ALTER TABLE media ADD COLUMN is_private BOOLEAN DEFAULT 0",
+	"ALTER TABLE gallery_groups ADD COLUMN package_id INTEGER REFERENCES packages(id) ON DELETE SET NULL",
+	"ALTER TABLE media ADD COLUMN pair_id INTEGER REFERENCES media(id) ON DELETE SET NULL",
+	"ALTER TABLE reviews ADD COLUMN status TEXT DEFAULT 'approved'",
+	"ALTER TABLE reviews ADD COLUMN booking_id INTEGER REFERENCES bookings(id) ON DELETE SET NULL",
+	"ALTER TABLE reviews ADD COLUMN gallery_group_id INTEGER REFERENCES gallery_groups(id) ON DELETE SET NULL",
+	"ALTER TABLE reviews ADD COLUMN follow_up_status TEXT",
+	"ALTER TABLE reviews ADD COLUMN follow_up_notes TEXT",
+	"ALTER TABLE reviews ADD COLUMN external_id TEXT",
+	"ALTER TABLE packages ADD COLUMN features TEXT",
+	"ALTER TABLE packages ADD COLUMN included TEXT",
+	"ALTER TABLE packages ADD COLUMN excluded TEXT",
+	"ALTER TABLE bookings ADD COLUMN promo_code TEXT",
+	"ALTER TABLE bookings ADD COLUMN discount_type TEXT",
+	"ALTER TABLE bookings ADD COLUMN discount_value INTEGER NOT NULL DEFAULT 0",
+	"ALTER TABLE quotes ADD COLUMN promo_code_id INTEGER REFERENCES promo_codes(id) ON DELETE SET NULL",
+	"ALTER TABLE quotes ADD COLUMN promo_code TEXT",
+	"ALTER TABLE quotes ADD COLUMN discount_type TEXT",
+	"ALTER TABLE quotes ADD COLUMN discount_value INTEGER NOT NULL DEFAULT 0",
+	"ALTER TABLE bookings ADD COLUMN membership_id INTEGER REFERENCES memberships(id) ON DELETE SET NULL",
+	"ALTER TABLE bookings ADD COLUMN series_id INTEGER REFERENCES booking_series(id) ON DELETE SET NULL",
+	"ALTER TABLE bookings ADD COLUMN service_address TEXT",
+	"ALTER TABLE bookings ADD COLUMN service_zip TEXT",
+	"ALTER TABLE bookings ADD COLUMN service_lat REAL",
+	"ALTER TABLE bookings ADD COLUMN service_lng REAL",
+	"ALTER TABLE bookings ADD COLUMN travel_minutes INTEGER NOT NULL DEFAULT 0",
+	"ALTER TABLE bookings ADD COLUMN travel_fee INTEGER NOT NULL DEFAULT 0",
+	"ALTER TABLE packages ADD COLUMN required_skills TEXT",
+	"ALTER TABLE bookings ADD COLUMN staff_id INTEGER REFERENCES staff(id) ON DELETE SET NULL",
+	"ALTER TABLE promo_codes ADD COLUMN min_spend INTEGER NOT NULL DEFAULT 0",
+}
+
+// ApplyColumnMigrations runs every entry in ColumnMigrations, ignoring
+// failures.
+func ApplyColumnMigrations(ctx context.Context, conn *sql.DB) {
+	for _, stmt := range ColumnMigrations {
+		_, _ = conn.ExecContext(ctx, stmt)
+	}
+}
@@ -9,7 +9,48 @@ import (
 	"time"
 )
 
+type Addon struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	Description sql.NullString `json:"description"`
+	Price       int64          `json:"price"`
+	IsActive    sql.NullBool   `json:"is_active"`
+	SortOrder   sql.NullInt64  `json:"sort_order"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+	UpdatedAt   sql.NullTime   `json:"updated_at"`
+}
+
 type Booking struct {
+	ID              int64           `json:"id"`
+	CustomerName    string          `json:"customer_name"`
+	Email           string          `json:"email"`
+	Phone           sql.NullString  `json:"phone"`
+	VehicleDetails  sql.NullString  `json:"vehicle_details"`
+	ServiceInterest sql.NullString  `json:"service_interest"`
+	Notes           sql.NullString  `json:"notes"`
+	RequestedStart  time.Time       `json:"requested_start"`
+	RequestedEnd    time.Time       `json:"requested_end"`
+	Status          sql.NullString  `json:"status"`
+	Source          sql.NullString  `json:"source"`
+	InternalNotes   sql.NullString  `json:"internal_notes"`
+	ClerkUserID     sql.NullString  `json:"clerk_user_id"`
+	CreatedAt       sql.NullTime    `json:"created_at"`
+	UpdatedAt       sql.NullTime    `json:"updated_at"`
+	PromoCode       sql.NullString  `json:"promo_code"`
+	DiscountType    sql.NullString  `json:"discount_type"`
+	DiscountValue   int64           `json:"discount_value"`
+	MembershipID    sql.NullInt64   `json:"membership_id"`
+	SeriesID        sql.NullInt64   `json:"series_id"`
+	ServiceAddress  sql.NullString  `json:"service_address"`
+	ServiceZip      sql.NullString  `json:"service_zip"`
+	ServiceLat      sql.NullFloat64 `json:"service_lat"`
+	ServiceLng      sql.NullFloat64 `json:"service_lng"`
+	TravelMinutes   int64           `json:"travel_minutes"`
+	TravelFee       int64           `json:"travel_fee"`
+	StaffID         sql.NullInt64   `json:"staff_id"`
+}
+
+type BookingSeries struct {
 	ID              int64          `json:"id"`
 	CustomerName    string         `json:"customer_name"`
 	Email           string         `json:"email"`
@@ -17,16 +58,49 @@ type Booking struct {
 	VehicleDetails  sql.NullString `json:"vehicle_details"`
 	ServiceInterest sql.NullString `json:"service_interest"`
 	Notes           sql.NullString `json:"notes"`
-	RequestedStart  time.Time      `json:"requested_start"`
-	RequestedEnd    time.Time      `json:"requested_end"`
-	Status          sql.NullString `json:"status"`
-	Source          sql.NullString `json:"source"`
-	InternalNotes   sql.NullString `json:"internal_notes"`
-	ClerkUserID     sql.NullString `json:"clerk_user_id"`
+	Rrule           string         `json:"rrule"`
+	StartsAt        time.Time      `json:"starts_at"`
+	DurationMinutes int64          `json:"duration_minutes"`
+	Status          string         `json:"status"`
+	BookedThrough   time.Time      `json:"booked_through"`
+	PreviousID      sql.NullInt64  `json:"previous_id"`
+	CancelledAt     sql.NullTime   `json:"cancelled_at"`
 	CreatedAt       sql.NullTime   `json:"created_at"`
 	UpdatedAt       sql.NullTime   `json:"updated_at"`
 }
 
+type BookingSeriesOccurrence struct {
+	ID          int64         `json:"id"`
+	SeriesID    int64         `json:"series_id"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	BookingID   sql.NullInt64 `json:"booking_id"`
+	Status      string        `json:"status"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+	UpdatedAt   sql.NullTime  `json:"updated_at"`
+}
+
+type ContactMessage struct {
+	ID              int64          `json:"id"`
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Message         string         `json:"message"`
+	IpAddress       sql.NullString `json:"ip_address"`
+	IsRead          sql.NullBool   `json:"is_read"`
+	RepliedAt       sql.NullTime   `json:"replied_at"`
+	BookingID       sql.NullInt64  `json:"booking_id"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+}
+
+type ContactReply struct {
+	ID        int64          `json:"id"`
+	MessageID int64          `json:"message_id"`
+	Body      string         `json:"body"`
+	SentBy    sql.NullString `json:"sent_by"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+}
+
 type GalleryGroup struct {
 	ID           int64          `json:"id"`
 	Title        string         `json:"title"`
@@ -39,6 +113,86 @@ type GalleryGroup struct {
 	SortOrder    sql.NullInt64  `json:"sort_order"`
 	CreatedAt    sql.NullTime   `json:"created_at"`
 	UpdatedAt    sql.NullTime   `json:"updated_at"`
+	PackageID    sql.NullInt64  `json:"package_id"`
+}
+
+type GiftCertificate struct {
+	ID             int64          `json:"id"`
+	Code           string         `json:"code"`
+	InitialValue   int64          `json:"initial_value"`
+	Balance        int64          `json:"balance"`
+	Status         string         `json:"status"`
+	Source         string         `json:"source"`
+	PurchaserName  sql.NullString `json:"purchaser_name"`
+	PurchaserEmail sql.NullString `json:"purchaser_email"`
+	RecipientName  sql.NullString `json:"recipient_name"`
+	RecipientEmail sql.NullString `json:"recipient_email"`
+	Message        sql.NullString `json:"message"`
+	PaymentID      sql.NullInt64  `json:"payment_id"`
+	ExpiresAt      sql.NullTime   `json:"expires_at"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+}
+
+type GiftCertificateTransaction struct {
+	ID                int64          `json:"id"`
+	GiftCertificateID int64          `json:"gift_certificate_id"`
+	Kind              string         `json:"kind"`
+	Amount            int64          `json:"amount"`
+	BalanceAfter      int64          `json:"balance_after"`
+	InvoiceID         sql.NullInt64  `json:"invoice_id"`
+	Note              sql.NullString `json:"note"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+}
+
+type Invoice struct {
+	ID             int64          `json:"id"`
+	Number         sql.NullString `json:"number"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	CustomerName   string         `json:"customer_name"`
+	Email          string         `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	VehicleDetails sql.NullString `json:"vehicle_details"`
+	Status         sql.NullString `json:"status"`
+	TaxName        sql.NullString `json:"tax_name"`
+	TaxRate        int64          `json:"tax_rate"`
+	DiscountType   sql.NullString `json:"discount_type"`
+	DiscountValue  int64          `json:"discount_value"`
+	Notes          sql.NullString `json:"notes"`
+	IssuedAt       sql.NullTime   `json:"issued_at"`
+	DueAt          sql.NullTime   `json:"due_at"`
+	SentAt         sql.NullTime   `json:"sent_at"`
+	PaidAt         sql.NullTime   `json:"paid_at"`
+	PaymentMethod  sql.NullString `json:"payment_method"`
+	VoidedAt       sql.NullTime   `json:"voided_at"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+}
+
+type InvoiceItem struct {
+	ID          int64         `json:"id"`
+	InvoiceID   int64         `json:"invoice_id"`
+	Kind        string        `json:"kind"`
+	PackageID   sql.NullInt64 `json:"package_id"`
+	AddonID     sql.NullInt64 `json:"addon_id"`
+	Description string        `json:"description"`
+	Quantity    int64         `json:"quantity"`
+	UnitPrice   int64         `json:"unit_price"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+}
+
+type InvoiceSequence struct {
+	Year       int64 `json:"year"`
+	LastNumber int64 `json:"last_number"`
+}
+
+type MediaVariant struct {
+	ID        int64        `json:"id"`
+	MediaID   int64        `json:"media_id"`
+	Width     int64        `json:"width"`
+	Height    int64        `json:"height"`
+	Url       string       `json:"url"`
+	CreatedAt sql.NullTime `json:"created_at"`
 }
 
 type Medium struct {
@@ -48,30 +202,281 @@ type Medium struct {
 	Kind           sql.NullString `json:"kind"`
 	SortOrder      sql.NullInt64  `json:"sort_order"`
 	AltText        sql.NullString `json:"alt_text"`
+	IsPrivate      sql.NullBool   `json:"is_private"`
+	PairID         sql.NullInt64  `json:"pair_id"`
 	CreatedAt      sql.NullTime   `json:"created_at"`
 }
 
+type Membership struct {
+	ID           int64          `json:"id"`
+	PlanID       int64          `json:"plan_id"`
+	CustomerName string         `json:"customer_name"`
+	Email        string         `json:"email"`
+	Phone        sql.NullString `json:"phone"`
+	ClerkUserID  sql.NullString `json:"clerk_user_id"`
+	Status       string         `json:"status"`
+	StartedAt    time.Time      `json:"started_at"`
+	PaidThrough  time.Time      `json:"paid_through"`
+	CancelledAt  sql.NullTime   `json:"cancelled_at"`
+	CancelReason sql.NullString `json:"cancel_reason"`
+	Notes        sql.NullString `json:"notes"`
+	CreatedAt    sql.NullTime   `json:"created_at"`
+	UpdatedAt    sql.NullTime   `json:"updated_at"`
+}
+
+type MembershipPlan struct {
+	ID                int64          `json:"id"`
+	Name              string         `json:"name"`
+	Description       sql.NullString `json:"description"`
+	PackageID         sql.NullInt64  `json:"package_id"`
+	ServicesPerPeriod int64          `json:"services_per_period"`
+	PeriodMonths      int64          `json:"period_months"`
+	Price             int64          `json:"price"`
+	IsActive          sql.NullBool   `json:"is_active"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+}
+
+type MembershipRenewal struct {
+	ID            int64          `json:"id"`
+	MembershipID  int64          `json:"membership_id"`
+	PeriodStart   time.Time      `json:"period_start"`
+	PeriodEnd     time.Time      `json:"period_end"`
+	Amount        int64          `json:"amount"`
+	PaymentMethod sql.NullString `json:"payment_method"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+}
+
 type Package struct {
-	ID          int64          `json:"id"`
-	Slug        string         `json:"slug"`
-	Name        string         `json:"name"`
-	ShortDesc   sql.NullString `json:"short_desc"`
-	LongDesc    sql.NullString `json:"long_desc"`
-	PriceMin    sql.NullInt64  `json:"price_min"`
-	PriceMax    sql.NullInt64  `json:"price_max"`
-	DurationEst sql.NullInt64  `json:"duration_est"`
-	IsActive    sql.NullBool   `json:"is_active"`
-	SortOrder   sql.NullInt64  `json:"sort_order"`
-	CreatedAt   sql.NullTime   `json:"created_at"`
-	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	ID             int64          `json:"id"`
+	Slug           string         `json:"slug"`
+	Name           string         `json:"name"`
+	ShortDesc      sql.NullString `json:"short_desc"`
+	LongDesc       sql.NullString `json:"long_desc"`
+	PriceMin       sql.NullInt64  `json:"price_min"`
+	PriceMax       sql.NullInt64  `json:"price_max"`
+	DurationEst    sql.NullInt64  `json:"duration_est"`
+	IsActive       sql.NullBool   `json:"is_active"`
+	SortOrder      sql.NullInt64  `json:"sort_order"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	Features       sql.NullString `json:"features"`
+	Included       sql.NullString `json:"included"`
+	Excluded       sql.NullString `json:"excluded"`
+	RequiredSkills sql.NullString `json:"required_skills"`
+}
+
+type PackageFaq struct {
+	ID        int64         `json:"id"`
+	PackageID int64         `json:"package_id"`
+	Question  string        `json:"question"`
+	Answer    string        `json:"answer"`
+	SortOrder sql.NullInt64 `json:"sort_order"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+}
+
+type Payment struct {
+	ID             int64          `json:"id"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	Kind           string         `json:"kind"`
+	Provider       string         `json:"provider"`
+	CheckoutID     sql.NullString `json:"checkout_id"`
+	PaymentRef     sql.NullString `json:"payment_ref"`
+	Amount         int64          `json:"amount"`
+	Currency       string         `json:"currency"`
+	Status         string         `json:"status"`
+	RefundRef      sql.NullString `json:"refund_ref"`
+	RefundedAmount int64          `json:"refunded_amount"`
+	PaidAt         sql.NullTime   `json:"paid_at"`
+	RefundedAt     sql.NullTime   `json:"refunded_at"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+}
+
+type PaymentEvent struct {
+	ID         string         `json:"id"`
+	Type       sql.NullString `json:"type"`
+	ReceivedAt sql.NullTime   `json:"received_at"`
+}
+
+type PromoCode struct {
+	ID                 int64          `json:"id"`
+	Code               string         `json:"code"`
+	Description        sql.NullString `json:"description"`
+	DiscountType       string         `json:"discount_type"`
+	DiscountValue      int64          `json:"discount_value"`
+	StartsAt           sql.NullTime   `json:"starts_at"`
+	EndsAt             sql.NullTime   `json:"ends_at"`
+	MaxUses            int64          `json:"max_uses"`
+	MaxUsesPerCustomer int64          `json:"max_uses_per_customer"`
+	MinSpend           int64          `json:"min_spend"`
+	IsActive           sql.NullBool   `json:"is_active"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	UpdatedAt          sql.NullTime   `json:"updated_at"`
+}
+
+type PromoCodePackage struct {
+	PromoCodeID int64 `json:"promo_code_id"`
+	PackageID   int64 `json:"package_id"`
+}
+
+type PromoRedemption struct {
+	ID          int64         `json:"id"`
+	PromoCodeID int64         `json:"promo_code_id"`
+	BookingID   sql.NullInt64 `json:"booking_id"`
+	QuoteID     sql.NullInt64 `json:"quote_id"`
+	Email       string        `json:"email"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+}
+
+type Quote struct {
+	ID               int64          `json:"id"`
+	CustomerName     string         `json:"customer_name"`
+	Email            string         `json:"email"`
+	Phone            sql.NullString `json:"phone"`
+	VehicleDetails   sql.NullString `json:"vehicle_details"`
+	Notes            sql.NullString `json:"notes"`
+	Status           sql.NullString `json:"status"`
+	Version          sql.NullInt64  `json:"version"`
+	ExpiresAt        sql.NullTime   `json:"expires_at"`
+	SentAt           sql.NullTime   `json:"sent_at"`
+	AcceptedAt       sql.NullTime   `json:"accepted_at"`
+	BookingID        sql.NullInt64  `json:"booking_id"`
+	ContactMessageID sql.NullInt64  `json:"contact_message_id"`
+	CreatedAt        sql.NullTime   `json:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	PromoCodeID      sql.NullInt64  `json:"promo_code_id"`
+	PromoCode        sql.NullString `json:"promo_code"`
+	DiscountType     sql.NullString `json:"discount_type"`
+	DiscountValue    int64          `json:"discount_value"`
+}
+
+type QuoteItem struct {
+	ID          int64         `json:"id"`
+	QuoteID     int64         `json:"quote_id"`
+	Kind        string        `json:"kind"`
+	PackageID   sql.NullInt64 `json:"package_id"`
+	AddonID     sql.NullInt64 `json:"addon_id"`
+	Description string        `json:"description"`
+	Quantity    int64         `json:"quantity"`
+	UnitPrice   int64         `json:"unit_price"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+}
+
+type QuoteVersion struct {
+	ID        int64        `json:"id"`
+	QuoteID   int64        `json:"quote_id"`
+	Version   int64        `json:"version"`
+	Items     string       `json:"items"`
+	Total     int64        `json:"total"`
+	ExpiresAt sql.NullTime `json:"expires_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
 }
 
 type Review struct {
-	ID         int64          `json:"id"`
-	Author     string         `json:"author"`
-	Rating     sql.NullInt64  `json:"rating"`
-	Body       sql.NullString `json:"body"`
-	Source     sql.NullString `json:"source"`
-	IsFeatured sql.NullBool   `json:"is_featured"`
-	CreatedAt  sql.NullTime   `json:"created_at"`
+	ID             int64          `json:"id"`
+	Author         string         `json:"author"`
+	Rating         sql.NullInt64  `json:"rating"`
+	Body           sql.NullString `json:"body"`
+	Source         sql.NullString `json:"source"`
+	IsFeatured     sql.NullBool   `json:"is_featured"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	Status         sql.NullString `json:"status"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	GalleryGroupID sql.NullInt64  `json:"gallery_group_id"`
+	FollowUpStatus sql.NullString `json:"follow_up_status"`
+	FollowUpNotes  sql.NullString `json:"follow_up_notes"`
+	ExternalID     sql.NullString `json:"external_id"`
+}
+
+type ReviewRequest struct {
+	ID             int64         `json:"id"`
+	BookingID      int64         `json:"booking_id"`
+	GalleryGroupID sql.NullInt64 `json:"gallery_group_id"`
+	Email          string        `json:"email"`
+	ExpiresAt      time.Time     `json:"expires_at"`
+	SentAt         sql.NullTime  `json:"sent_at"`
+	UsedAt         sql.NullTime  `json:"used_at"`
+	CreatedAt      sql.NullTime  `json:"created_at"`
+}
+
+type SlotHold struct {
+	ID        int64          `json:"id"`
+	Token     string         `json:"token"`
+	SlotStart time.Time      `json:"slot_start"`
+	SlotEnd   time.Time      `json:"slot_end"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	IpAddress sql.NullString `json:"ip_address"`
+	Status    string         `json:"status"`
+	BookingID sql.NullInt64  `json:"booking_id"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+	UpdatedAt sql.NullTime   `json:"updated_at"`
+}
+
+type Staff struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Email     sql.NullString `json:"email"`
+	Phone     sql.NullString `json:"phone"`
+	Role      string         `json:"role"`
+	Skills    sql.NullString `json:"skills"`
+	IsActive  sql.NullBool   `json:"is_active"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+	UpdatedAt sql.NullTime   `json:"updated_at"`
+}
+
+type StaffHour struct {
+	ID          int64 `json:"id"`
+	StaffID     int64 `json:"staff_id"`
+	Weekday     int64 `json:"weekday"`
+	StartMinute int64 `json:"start_minute"`
+	EndMinute   int64 `json:"end_minute"`
+}
+
+type StaffTimeOff struct {
+	ID        int64          `json:"id"`
+	StaffID   int64          `json:"staff_id"`
+	StartsAt  time.Time      `json:"starts_at"`
+	EndsAt    time.Time      `json:"ends_at"`
+	Reason    sql.NullString `json:"reason"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+}
+
+type TaxRate struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	Rate      int64        `json:"rate"`
+	IsDefault sql.NullBool `json:"is_default"`
+	IsActive  sql.NullBool `json:"is_active"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type WaitlistEntry struct {
+	ID              int64          `json:"id"`
+	CustomerName    string         `json:"customer_name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	VehicleDetails  sql.NullString `json:"vehicle_details"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Notes           sql.NullString `json:"notes"`
+	DateFrom        time.Time      `json:"date_from"`
+	DateTo          time.Time      `json:"date_to"`
+	SlotID          sql.NullString `json:"slot_id"`
+	ClerkUserID     sql.NullString `json:"clerk_user_id"`
+	Status          string         `json:"status"`
+	BookingID       sql.NullInt64  `json:"booking_id"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
+}
+
+type WaitlistOffer struct {
+	ID        int64         `json:"id"`
+	EntryID   int64         `json:"entry_id"`
+	SlotStart time.Time     `json:"slot_start"`
+	SlotEnd   time.Time     `json:"slot_end"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	Status    string        `json:"status"`
+	BookingID sql.NullInt64 `json:"booking_id"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+	UpdatedAt sql.NullTime  `json:"updated_at"`
 }
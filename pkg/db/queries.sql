@@ -18,13 +18,13 @@ SELECT * FROM packages
 ORDER BY sort_order, id;
 
 -- name: CreatePackage :one
-INSERT INTO packages (slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO packages (slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, features, included, excluded, required_skills)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: UpdatePackage :one
 UPDATE packages
-SET slug = ?, name = ?, short_desc = ?, long_desc = ?, price_min = ?, price_max = ?, duration_est = ?, is_active = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP
+SET slug = ?, name = ?, short_desc = ?, long_desc = ?, price_min = ?, price_max = ?, duration_est = ?, is_active = ?, sort_order = ?, features = ?, included = ?, excluded = ?, required_skills = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
 RETURNING *;
 
@@ -34,6 +34,30 @@ DELETE FROM packages WHERE id = ?;
 -- name: CountPackages :one
 SELECT COUNT(*) FROM packages;
 
+-- name: GetActivePackageBySlug :one
+SELECT * FROM packages
+WHERE slug = ? AND is_active = 1 LIMIT 1;
+
+-- Package FAQ queries
+
+-- name: ListPackageFAQs :many
+SELECT * FROM package_faqs
+WHERE package_id = ?
+ORDER BY sort_order, id;
+
+-- name: CreatePackageFAQ :one
+INSERT INTO package_faqs (package_id, question, answer, sort_order)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdatePackageFAQ :exec
+UPDATE package_faqs
+SET question = ?, answer = ?, sort_order = ?
+WHERE id = ? AND package_id = ?;
+
+-- name: DeletePackageFAQ :exec
+DELETE FROM package_faqs WHERE id = ? AND package_id = ?;
+
 -- Gallery queries
 
 -- name: ListGalleryGroups :many
@@ -55,14 +79,45 @@ WHERE slug = ? LIMIT 1;
 SELECT * FROM gallery_groups
 WHERE id = ? LIMIT 1;
 
+-- name: ListRelatedGalleryGroups :many
+SELECT * FROM gallery_groups
+WHERE id != sqlc.arg(id)
+ORDER BY
+    CASE WHEN package_id = sqlc.narg(package_id) THEN 0 ELSE 1 END,
+    CASE WHEN vehicle_make = sqlc.narg(vehicle_make) THEN 0 ELSE 1 END,
+    is_featured DESC,
+    sort_order
+LIMIT sqlc.arg(limit);
+
+-- Projects done with a package, in gallery order
+-- name: ListGalleryGroupsByPackage :many
+SELECT * FROM gallery_groups
+WHERE package_id = ?
+ORDER BY sort_order, id
+LIMIT ?;
+
+-- name: ListGalleryGroupTitles :many
+SELECT id, title, package_id FROM gallery_groups
+ORDER BY sort_order, id;
+
+-- name: ListGalleryGroupSlugs :many
+SELECT slug, updated_at FROM gallery_groups
+ORDER BY sort_order, id;
+
+-- name: ClearPackageGalleryGroups :exec
+UPDATE gallery_groups SET package_id = NULL WHERE package_id = ?;
+
+-- name: SetGalleryGroupPackage :exec
+UPDATE gallery_groups SET package_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
 -- name: CreateGalleryGroup :one
-INSERT INTO gallery_groups (title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO gallery_groups (title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, package_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: UpdateGalleryGroup :one
 UPDATE gallery_groups
-SET title = ?, slug = ?, vehicle_make = ?, vehicle_model = ?, vehicle_year = ?, description = ?, is_featured = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP
+SET title = ?, slug = ?, vehicle_make = ?, vehicle_model = ?, vehicle_year = ?, description = ?, is_featured = ?, sort_order = ?, package_id = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
 RETURNING *;
 
@@ -72,6 +127,95 @@ DELETE FROM gallery_groups WHERE id = ?;
 -- name: CountGalleryGroups :one
 SELECT COUNT(*) FROM gallery_groups;
 
+-- Gallery listing with filters. Every filter argument is optional (NULL,
+-- or false for featured_only, disables it). Facet queries repeat the filter
+-- minus their own dimension so counts reflect what selecting a value would
+-- return.
+
+-- name: ListGalleryGroupsPage :many
+SELECT * FROM gallery_groups
+WHERE (CAST(sqlc.narg(vehicle_make) AS TEXT) IS NULL OR vehicle_make = sqlc.narg(vehicle_make))
+  AND (CAST(sqlc.narg(vehicle_model) AS TEXT) IS NULL OR vehicle_model = sqlc.narg(vehicle_model))
+  AND (CAST(sqlc.narg(year_min) AS INTEGER) IS NULL OR vehicle_year >= sqlc.narg(year_min))
+  AND (CAST(sqlc.narg(year_max) AS INTEGER) IS NULL OR vehicle_year <= sqlc.narg(year_max))
+  AND (CAST(sqlc.arg(featured_only) AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(sqlc.narg(package_id) AS INTEGER) IS NULL OR package_id = sqlc.narg(package_id))
+  AND (CAST(sqlc.narg(after_sort_order) AS INTEGER) IS NULL
+    OR sort_order > sqlc.narg(after_sort_order)
+    OR (sort_order = sqlc.narg(after_sort_order) AND id > sqlc.arg(after_id)))
+ORDER BY sort_order, id
+LIMIT sqlc.arg(limit);
+
+-- name: CountGalleryGroupsFiltered :one
+SELECT COUNT(*) FROM gallery_groups
+WHERE (CAST(sqlc.narg(vehicle_make) AS TEXT) IS NULL OR vehicle_make = sqlc.narg(vehicle_make))
+  AND (CAST(sqlc.narg(vehicle_model) AS TEXT) IS NULL OR vehicle_model = sqlc.narg(vehicle_model))
+  AND (CAST(sqlc.narg(year_min) AS INTEGER) IS NULL OR vehicle_year >= sqlc.narg(year_min))
+  AND (CAST(sqlc.narg(year_max) AS INTEGER) IS NULL OR vehicle_year <= sqlc.narg(year_max))
+  AND (CAST(sqlc.arg(featured_only) AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(sqlc.narg(package_id) AS INTEGER) IS NULL OR package_id = sqlc.narg(package_id));
+
+-- name: ListMediaForGalleryGroups :many
+SELECT * FROM media
+WHERE gallery_group_id IN (sqlc.slice(group_ids))
+ORDER BY gallery_group_id, sort_order, id;
+
+-- name: ListMediaVariantsForMedia :many
+SELECT * FROM media_variants
+WHERE media_id IN (sqlc.slice(media_ids))
+ORDER BY media_id, width;
+
+-- name: CountGalleryGroupsByMake :many
+SELECT vehicle_make, COUNT(*) AS count FROM gallery_groups
+WHERE vehicle_make IS NOT NULL AND vehicle_make != ''
+  AND (CAST(sqlc.narg(year_min) AS INTEGER) IS NULL OR vehicle_year >= sqlc.narg(year_min))
+  AND (CAST(sqlc.narg(year_max) AS INTEGER) IS NULL OR vehicle_year <= sqlc.narg(year_max))
+  AND (CAST(sqlc.arg(featured_only) AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(sqlc.narg(package_id) AS INTEGER) IS NULL OR package_id = sqlc.narg(package_id))
+GROUP BY vehicle_make
+ORDER BY vehicle_make;
+
+-- name: CountGalleryGroupsByModel :many
+SELECT vehicle_model, COUNT(*) AS count FROM gallery_groups
+WHERE vehicle_model IS NOT NULL AND vehicle_model != ''
+  AND (CAST(sqlc.narg(vehicle_make) AS TEXT) IS NULL OR vehicle_make = sqlc.narg(vehicle_make))
+  AND (CAST(sqlc.narg(year_min) AS INTEGER) IS NULL OR vehicle_year >= sqlc.narg(year_min))
+  AND (CAST(sqlc.narg(year_max) AS INTEGER) IS NULL OR vehicle_year <= sqlc.narg(year_max))
+  AND (CAST(sqlc.arg(featured_only) AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(sqlc.narg(package_id) AS INTEGER) IS NULL OR package_id = sqlc.narg(package_id))
+GROUP BY vehicle_model
+ORDER BY vehicle_model;
+
+-- name: CountGalleryGroupsByYear :many
+SELECT vehicle_year, COUNT(*) AS count FROM gallery_groups
+WHERE vehicle_year IS NOT NULL
+  AND (CAST(sqlc.narg(vehicle_make) AS TEXT) IS NULL OR vehicle_make = sqlc.narg(vehicle_make))
+  AND (CAST(sqlc.narg(vehicle_model) AS TEXT) IS NULL OR vehicle_model = sqlc.narg(vehicle_model))
+  AND (CAST(sqlc.arg(featured_only) AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(sqlc.narg(package_id) AS INTEGER) IS NULL OR package_id = sqlc.narg(package_id))
+GROUP BY vehicle_year
+ORDER BY vehicle_year DESC;
+
+-- name: CountGalleryGroupsByPackage :many
+SELECT p.id, p.slug, p.name, COUNT(*) AS count FROM gallery_groups g
+JOIN packages p ON p.id = g.package_id
+WHERE (CAST(sqlc.narg(vehicle_make) AS TEXT) IS NULL OR g.vehicle_make = sqlc.narg(vehicle_make))
+  AND (CAST(sqlc.narg(vehicle_model) AS TEXT) IS NULL OR g.vehicle_model = sqlc.narg(vehicle_model))
+  AND (CAST(sqlc.narg(year_min) AS INTEGER) IS NULL OR g.vehicle_year >= sqlc.narg(year_min))
+  AND (CAST(sqlc.narg(year_max) AS INTEGER) IS NULL OR g.vehicle_year <= sqlc.narg(year_max))
+  AND (CAST(sqlc.arg(featured_only) AS BOOLEAN) = 0 OR g.is_featured = 1)
+GROUP BY p.id
+ORDER BY p.sort_order, p.id;
+
+-- name: CountFeaturedGalleryGroups :one
+SELECT COUNT(*) FROM gallery_groups
+WHERE is_featured = 1
+  AND (CAST(sqlc.narg(vehicle_make) AS TEXT) IS NULL OR vehicle_make = sqlc.narg(vehicle_make))
+  AND (CAST(sqlc.narg(vehicle_model) AS TEXT) IS NULL OR vehicle_model = sqlc.narg(vehicle_model))
+  AND (CAST(sqlc.narg(year_min) AS INTEGER) IS NULL OR vehicle_year >= sqlc.narg(year_min))
+  AND (CAST(sqlc.narg(year_max) AS INTEGER) IS NULL OR vehicle_year <= sqlc.narg(year_max))
+  AND (CAST(sqlc.narg(package_id) AS INTEGER) IS NULL OR package_id = sqlc.narg(package_id));
+
 -- Media queries
 
 -- name: GetMediaForGalleryGroup :many
@@ -79,6 +223,15 @@ SELECT * FROM media
 WHERE gallery_group_id = ?
 ORDER BY sort_order, id;
 
+-- name: GetMediaByID :one
+SELECT * FROM media
+WHERE id = ? LIMIT 1;
+
+-- name: GetNextMediaSortOrder :one
+SELECT CAST(COALESCE(MAX(sort_order), -1) + 1 AS INTEGER) AS next_sort_order
+FROM media
+WHERE gallery_group_id = ?;
+
 -- name: GetHeroImageForGalleryGroup :one
 SELECT * FROM media
 WHERE gallery_group_id = ? AND kind = 'hero'
@@ -86,8 +239,8 @@ ORDER BY sort_order
 LIMIT 1;
 
 -- name: CreateMedia :one
-INSERT INTO media (gallery_group_id, url, kind, sort_order, alt_text)
-VALUES (?, ?, ?, ?, ?)
+INSERT INTO media (gallery_group_id, url, kind, sort_order, alt_text, is_private)
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: UpdateMedia :one
@@ -96,36 +249,1201 @@ SET url = ?, kind = ?, sort_order = ?, alt_text = ?
 WHERE id = ?
 RETURNING *;
 
+-- name: SetMediaPair :exec
+UPDATE media SET pair_id = sqlc.arg(after_id) WHERE id = sqlc.arg(before_id);
+
+-- name: UnpairMedia :exec
+UPDATE media SET pair_id = NULL WHERE id = sqlc.arg(id) OR pair_id = sqlc.arg(id);
+
+-- name: SetMediaKind :exec
+UPDATE media SET kind = ? WHERE id = ?;
+
+-- Public before/after pairs for the homepage, featured projects first
+-- name: ListShowcaseMediaPairs :many
+SELECT
+    b.id AS before_id, b.url AS before_url, b.alt_text AS before_alt_text,
+    a.id AS after_id, a.url AS after_url, a.alt_text AS after_alt_text,
+    g.title, g.slug
+FROM media b
+JOIN media a ON a.id = b.pair_id
+JOIN gallery_groups g ON g.id = b.gallery_group_id
+WHERE b.is_private = 0 AND a.is_private = 0
+ORDER BY g.is_featured DESC, g.sort_order, g.id, b.sort_order
+LIMIT ?;
+
 -- name: DeleteMedia :exec
 DELETE FROM media WHERE id = ?;
 
 -- name: CountMedia :one
 SELECT COUNT(*) FROM media;
 
+-- name: ListMediaWithoutVariants :many
+SELECT * FROM media
+WHERE id NOT IN (SELECT media_id FROM media_variants)
+ORDER BY id;
+
+-- Media variant queries
+
+-- name: GetMediaVariantsForMedia :many
+SELECT * FROM media_variants
+WHERE media_id = ?
+ORDER BY width;
+
+-- name: GetMediaVariantsForGalleryGroup :many
+SELECT * FROM media_variants
+WHERE media_id IN (SELECT id FROM media WHERE gallery_group_id = ?)
+ORDER BY media_id, width;
+
+-- name: CreateMediaVariant :one
+INSERT INTO media_variants (media_id, width, height, url)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: DeleteMediaVariantsForMedia :exec
+DELETE FROM media_variants WHERE media_id = ?;
+
+-- name: DeleteMediaVariantsForGalleryGroup :exec
+DELETE FROM media_variants
+WHERE media_id IN (SELECT id FROM media WHERE gallery_group_id = ?);
+
+-- name: DeleteMediaForGalleryGroup :exec
+DELETE FROM media WHERE gallery_group_id = ?;
+
 -- Review queries
 
 -- name: ListReviews :many
 SELECT * FROM reviews
-ORDER BY created_at DESC
-LIMIT ?;
+WHERE CAST(sqlc.narg(status) AS TEXT) IS NULL OR status = sqlc.narg(status)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
 
 -- name: ListFeaturedReviews :many
 SELECT * FROM reviews
-WHERE is_featured = 1
+WHERE is_featured = 1 AND status = 'approved'
 ORDER BY created_at DESC
 LIMIT ?;
 
+-- name: GetReviewByID :one
+SELECT * FROM reviews
+WHERE id = ? LIMIT 1;
+
 -- name: CreateReview :one
-INSERT INTO reviews (author, rating, body, source, is_featured)
-VALUES (?, ?, ?, ?, ?)
+INSERT INTO reviews (author, rating, body, source, is_featured, status)
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING *;
 
+-- name: UpdateReview :one
+UPDATE reviews
+SET author = ?, rating = ?, body = ?, source = ?, is_featured = ?, status = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: SetReviewFeatured :exec
+UPDATE reviews SET is_featured = ? WHERE id = ?;
+
+-- Rejecting or un-approving a review also takes it off the home page
+-- name: SetReviewStatus :exec
+UPDATE reviews
+SET status = sqlc.arg(status),
+    is_featured = CASE WHEN CAST(sqlc.arg(status) AS TEXT) = 'approved' THEN is_featured ELSE 0 END
+WHERE id = sqlc.arg(id);
+
 -- name: DeleteReview :exec
 DELETE FROM reviews WHERE id = ?;
 
 -- name: CountReviews :one
 SELECT COUNT(*) FROM reviews;
 
+-- name: CountReviewsByStatus :many
+SELECT CAST(COALESCE(status, 'approved') AS TEXT) AS status, COUNT(*) AS count FROM reviews
+GROUP BY COALESCE(status, 'approved');
+
+-- Average over approved reviews, for the public aggregate rating
+-- name: GetReviewStats :one
+SELECT COUNT(*) AS count, CAST(COALESCE(AVG(rating), 0) AS REAL) AS average
+FROM reviews
+WHERE status = 'approved';
+
+-- Imported reviews keep the platform's date and are already public there
+-- name: CreateImportedReview :one
+INSERT INTO reviews (author, rating, body, source, is_featured, status, external_id, created_at)
+VALUES (?, ?, ?, ?, 0, 'approved', ?, ?)
+RETURNING *;
+
+-- Matches the same platform review, or the same words entered by hand
+-- before imports existed
+-- name: CountDuplicateReviews :one
+SELECT COUNT(*) FROM reviews
+WHERE (source = sqlc.arg(source) AND external_id = sqlc.arg(external_id))
+   OR (lower(author) = lower(CAST(sqlc.arg(author) AS TEXT))
+       AND COALESCE(body, '') = CAST(sqlc.arg(body) AS TEXT)
+       AND rating = sqlc.arg(rating));
+
+-- name: CreateCustomerReview :one
+INSERT INTO reviews (author, rating, body, source, is_featured, status, booking_id, gallery_group_id, follow_up_status)
+VALUES (?, ?, ?, 'customer', 0, 'pending', ?, ?, ?)
+RETURNING *;
+
+-- name: ListOpenFollowUpReviews :many
+SELECT r.*, b.customer_name, b.email, b.phone
+FROM reviews r
+LEFT JOIN bookings b ON b.id = r.booking_id
+WHERE r.follow_up_status = 'open'
+ORDER BY r.created_at ASC;
+
+-- name: CountOpenFollowUpReviews :one
+SELECT COUNT(*) FROM reviews WHERE follow_up_status = 'open';
+
+-- name: ResolveReviewFollowUp :exec
+UPDATE reviews
+SET follow_up_status = 'resolved', follow_up_notes = ?
+WHERE id = ? AND follow_up_status = 'open';
+
+-- Review request queries
+
+-- name: CreateReviewRequest :one
+INSERT INTO review_requests (booking_id, gallery_group_id, email, expires_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetReviewRequestByID :one
+SELECT * FROM review_requests
+WHERE id = ? LIMIT 1;
+
+-- name: MarkReviewRequestSent :exec
+UPDATE review_requests SET sent_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- Claims a request for a submission; zero rows means it was already used
+-- name: UseReviewRequest :execrows
+UPDATE review_requests SET used_at = CURRENT_TIMESTAMP
+WHERE id = ? AND used_at IS NULL;
+
+-- Retires earlier links when a new one is sent for the same booking
+-- name: ExpireReviewRequestsForBooking :exec
+UPDATE review_requests SET expires_at = CURRENT_TIMESTAMP
+WHERE booking_id = ? AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP;
+
+-- Review requests for the bookings on one page of ListBookings
+-- name: ListReviewRequestsForBookingPage :many
+SELECT rr.* FROM review_requests rr
+JOIN (SELECT id FROM bookings ORDER BY requested_start DESC LIMIT ? OFFSET ?) page ON page.id = rr.booking_id
+ORDER BY rr.booking_id, rr.id;
+
+-- Contact message queries
+
+-- name: CreateContactMessage :one
+INSERT INTO contact_messages (name, email, phone, service_interest, message, ip_address)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- Messages accepted from one address since a time, for rate limiting
+-- name: CountRecentContactMessages :one
+SELECT COUNT(*) FROM contact_messages
+WHERE ip_address = ? AND created_at >= ?;
+
+-- name: ListContactMessages :many
+SELECT * FROM contact_messages
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+
+-- name: ListUnreadContactMessages :many
+SELECT * FROM contact_messages
+WHERE is_read = 0
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+
+-- name: GetContactMessageByID :one
+SELECT * FROM contact_messages
+WHERE id = ? LIMIT 1;
+
+-- name: CountContactMessages :one
+SELECT COUNT(*) FROM contact_messages;
+
+-- name: CountUnreadContactMessages :one
+SELECT COUNT(*) FROM contact_messages WHERE is_read = 0;
+
+-- name: SetContactMessageRead :exec
+UPDATE contact_messages SET is_read = ? WHERE id = ?;
+
+-- name: SetContactMessageBooking :exec
+UPDATE contact_messages SET booking_id = ?, is_read = 1 WHERE id = ?;
+
+-- name: DeleteContactMessage :exec
+DELETE FROM contact_messages WHERE id = ?;
+
+-- name: CreateContactReply :one
+INSERT INTO contact_replies (message_id, body, sent_by)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: MarkContactMessageReplied :exec
+UPDATE contact_messages SET replied_at = CURRENT_TIMESTAMP, is_read = 1 WHERE id = ?;
+
+-- name: ListContactReplies :many
+SELECT * FROM contact_replies
+WHERE message_id = ?
+ORDER BY created_at, id;
+
+-- Add-on queries
+
+-- name: ListAddons :many
+SELECT * FROM addons
+ORDER BY sort_order, id;
+
+-- name: ListActiveAddons :many
+SELECT * FROM addons
+WHERE is_active = 1
+ORDER BY sort_order, id;
+
+-- name: GetAddonByID :one
+SELECT * FROM addons
+WHERE id = ? LIMIT 1;
+
+-- name: CreateAddon :one
+INSERT INTO addons (name, description, price, is_active, sort_order)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateAddon :exec
+UPDATE addons
+SET name = ?, description = ?, price = ?, is_active = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteAddon :exec
+DELETE FROM addons WHERE id = ?;
+
+-- Quote queries
+
+-- name: CreateQuote :one
+INSERT INTO quotes (customer_name, email, phone, vehicle_details, notes, expires_at, contact_message_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetQuoteByID :one
+SELECT * FROM quotes
+WHERE id = ? LIMIT 1;
+
+-- name: ListQuotes :many
+SELECT q.*, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM quote_items WHERE quote_id = q.id), 0) AS INTEGER) AS total
+FROM quotes q
+ORDER BY q.created_at DESC, q.id DESC
+LIMIT ?;
+
+-- name: ListQuotesByStatus :many
+SELECT q.*, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM quote_items WHERE quote_id = q.id), 0) AS INTEGER) AS total
+FROM quotes q
+WHERE q.status = ?
+ORDER BY q.created_at DESC, q.id DESC
+LIMIT ?;
+
+-- name: CountQuotesByStatus :many
+SELECT status, COUNT(*) AS count
+FROM quotes
+GROUP BY status;
+
+-- Editing a sent quote takes it back to draft until it is sent again
+-- name: UpdateQuoteDetails :exec
+UPDATE quotes
+SET customer_name = ?, email = ?, phone = ?, vehicle_details = ?, notes = ?, expires_at = ?,
+    status = CASE WHEN status = 'sent' THEN 'draft' ELSE status END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status IN ('draft', 'sent');
+
+-- name: ReviseQuote :exec
+UPDATE quotes
+SET status = 'draft', updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'sent';
+
+-- name: MarkQuoteSent :exec
+UPDATE quotes
+SET status = 'sent', version = ?, expires_at = ?, sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- Only a sent, unexpired quote can be accepted, and only once
+-- name: AcceptQuote :execrows
+UPDATE quotes
+SET status = 'accepted', booking_id = ?, accepted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'sent' AND expires_at > ?;
+
+-- name: ReopenQuoteForBooking :execrows
+UPDATE quotes
+SET status = 'sent', booking_id = NULL, accepted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE booking_id = ? AND status = 'accepted';
+
+-- name: DeclineQuote :execrows
+UPDATE quotes
+SET status = 'declined', updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'sent';
+
+-- Applying or removing a promo code changes the price, so a sent quote
+-- goes back to draft like any other edit
+-- name: SetQuotePromo :exec
+UPDATE quotes
+SET promo_code_id = ?, promo_code = ?, discount_type = ?, discount_value = ?,
+    status = CASE WHEN status = 'sent' THEN 'draft' ELSE status END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status IN ('draft', 'sent');
+
+-- name: DeleteQuote :exec
+DELETE FROM quotes WHERE id = ?;
+
+-- name: ListQuoteItems :many
+SELECT * FROM quote_items
+WHERE quote_id = ?
+ORDER BY id;
+
+-- name: CreateQuoteItem :one
+INSERT INTO quote_items (quote_id, kind, package_id, addon_id, description, quantity, unit_price)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: DeleteQuoteItem :exec
+DELETE FROM quote_items WHERE id = ? AND quote_id = ?;
+
+-- name: CreateQuoteVersion :one
+INSERT INTO quote_versions (quote_id, version, items, total, expires_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListQuoteVersions :many
+SELECT * FROM quote_versions
+WHERE quote_id = ?
+ORDER BY version DESC;
+
+-- name: ListQuotesForContactMessage :many
+SELECT id, status FROM quotes
+WHERE contact_message_id = ?
+ORDER BY id;
+
+-- Tax rate queries
+
+-- name: ListTaxRates :many
+SELECT * FROM tax_rates
+ORDER BY is_default DESC, name, id;
+
+-- name: ListActiveTaxRates :many
+SELECT * FROM tax_rates
+WHERE is_active = 1
+ORDER BY is_default DESC, name, id;
+
+-- name: GetTaxRateByID :one
+SELECT * FROM tax_rates
+WHERE id = ? LIMIT 1;
+
+-- name: GetDefaultTaxRate :one
+SELECT * FROM tax_rates
+WHERE is_default = 1 AND is_active = 1
+ORDER BY id LIMIT 1;
+
+-- name: CreateTaxRate :one
+INSERT INTO tax_rates (name, rate, is_default, is_active)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateTaxRate :exec
+UPDATE tax_rates
+SET name = ?, rate = ?, is_default = ?, is_active = ?
+WHERE id = ?;
+
+-- name: ClearDefaultTaxRate :exec
+UPDATE tax_rates
+SET is_default = 0
+WHERE id != ?;
+
+-- name: DeleteTaxRate :exec
+DELETE FROM tax_rates WHERE id = ?;
+
+-- Invoice queries
+
+-- name: CreateInvoice :one
+INSERT INTO invoices (booking_id, customer_name, email, phone, vehicle_details, tax_name, tax_rate, discount_type, discount_value, due_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetInvoiceByID :one
+SELECT * FROM invoices
+WHERE id = ? LIMIT 1;
+
+-- name: ListInvoices :many
+SELECT i.*, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM invoice_items WHERE invoice_id = i.id), 0) AS INTEGER) AS subtotal
+FROM invoices i
+ORDER BY i.created_at DESC, i.id DESC
+LIMIT ?;
+
+-- name: ListInvoicesByStatus :many
+SELECT i.*, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM invoice_items WHERE invoice_id = i.id), 0) AS INTEGER) AS subtotal
+FROM invoices i
+WHERE i.status = ?
+ORDER BY i.created_at DESC, i.id DESC
+LIMIT ?;
+
+-- name: CountInvoicesByStatus :many
+SELECT status, COUNT(*) AS count
+FROM invoices
+GROUP BY status;
+
+-- name: ListInvoicesForBookingPage :many
+SELECT i.id, i.booking_id, i.number, i.status FROM invoices i
+JOIN (SELECT id FROM bookings ORDER BY requested_start DESC LIMIT ? OFFSET ?) page ON page.id = i.booking_id
+ORDER BY i.booking_id, i.id;
+
+-- Invoices a signed-in customer can see: sent to their email, or for one
+-- of their bookings. Drafts stay private.
+-- name: ListCustomerInvoices :many
+SELECT i.*, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM invoice_items WHERE invoice_id = i.id), 0) AS INTEGER) AS subtotal
+FROM invoices i
+LEFT JOIN bookings b ON b.id = i.booking_id
+WHERE i.status IN ('sent', 'paid', 'void')
+  AND (i.email = sqlc.arg(email) OR b.clerk_user_id = sqlc.arg(clerk_user_id))
+ORDER BY i.issued_at DESC, i.id DESC;
+
+-- name: UpdateInvoiceDetails :exec
+UPDATE invoices
+SET customer_name = ?, email = ?, phone = ?, vehicle_details = ?, notes = ?, due_at = ?,
+    tax_name = ?, tax_rate = ?, discount_type = ?, discount_value = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'draft';
+
+-- name: NextInvoiceNumber :one
+INSERT INTO invoice_sequences (year, last_number)
+VALUES (?, 1)
+ON CONFLICT(year) DO UPDATE SET last_number = last_number + 1
+RETURNING last_number;
+
+-- name: IssueInvoice :execrows
+UPDATE invoices
+SET status = 'sent', number = ?, issued_at = ?, sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'draft';
+
+-- name: MarkInvoiceResent :exec
+UPDATE invoices
+SET sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: MarkInvoicePaid :execrows
+UPDATE invoices
+SET status = 'paid', paid_at = ?, payment_method = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'sent';
+
+-- name: VoidInvoice :execrows
+UPDATE invoices
+SET status = 'void', voided_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status IN ('sent', 'paid');
+
+-- Only drafts can be deleted; issued invoices are voided instead
+-- name: DeleteDraftInvoice :execrows
+DELETE FROM invoices WHERE id = ? AND status = 'draft';
+
+-- name: ListInvoiceItems :many
+SELECT * FROM invoice_items
+WHERE invoice_id = ?
+ORDER BY id;
+
+-- name: CreateInvoiceItem :one
+INSERT INTO invoice_items (invoice_id, kind, package_id, addon_id, description, quantity, unit_price)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: DeleteInvoiceItem :exec
+DELETE FROM invoice_items WHERE id = ? AND invoice_id = ?;
+
+-- name: GetAcceptedQuoteForBooking :one
+SELECT * FROM quotes
+WHERE booking_id = ? AND status = 'accepted'
+ORDER BY id DESC LIMIT 1;
+
+-- Payment queries
+
+-- name: CreatePayment :one
+INSERT INTO payments (booking_id, kind, provider, amount, currency)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetPaymentByID :one
+SELECT * FROM payments
+WHERE id = ? LIMIT 1;
+
+-- name: GetPaymentByCheckoutID :one
+SELECT * FROM payments
+WHERE checkout_id = ? LIMIT 1;
+
+-- name: GetBookingDeposit :one
+SELECT * FROM payments
+WHERE booking_id = ? AND kind = 'deposit'
+ORDER BY id DESC LIMIT 1;
+
+-- name: ListDepositsForBookingPage :many
+SELECT p.* FROM payments p
+JOIN (SELECT id FROM bookings ORDER BY requested_start DESC LIMIT ? OFFSET ?) page ON page.id = p.booking_id
+WHERE p.kind = 'deposit'
+ORDER BY p.booking_id, p.id;
+
+-- name: SetPaymentCheckout :exec
+UPDATE payments
+SET checkout_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- Money can arrive after a checkout was given up on, or be taken in
+-- person, so closed unpaid payments can still be marked paid
+-- name: MarkPaymentPaid :execrows
+UPDATE payments
+SET status = 'paid', provider = ?, payment_ref = ?, paid_at = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status IN ('pending', 'failed', 'expired', 'void');
+
+-- Ends a payment that was never completed (failed, expired or void)
+-- name: CloseUnpaidPayment :execrows
+UPDATE payments
+SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- Settles a paid deposit on cancellation: refunded, or forfeited with a
+-- zero refund when it falls inside the policy window
+-- name: SettlePayment :execrows
+UPDATE payments
+SET status = ?, refund_ref = ?, refunded_amount = ?, refunded_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'paid';
+
+-- name: RecordPaymentEvent :execrows
+INSERT INTO payment_events (id, type) VALUES (?, ?)
+ON CONFLICT(id) DO NOTHING;
+
+-- name: CancelPendingBooking :execrows
+UPDATE bookings
+SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- Promo code queries
+
+-- name: ListPromoCodes :many
+SELECT p.*,
+    CAST((SELECT COUNT(*) FROM promo_redemptions r LEFT JOIN bookings b ON b.id = r.booking_id
+          WHERE r.promo_code_id = p.id AND (b.id IS NULL OR b.status NOT IN ('cancelled', 'declined'))) AS INTEGER) AS redemptions,
+    CAST((SELECT COUNT(*) FROM promo_redemptions r JOIN bookings b ON b.id = r.booking_id
+          WHERE r.promo_code_id = p.id AND b.status IN ('cancelled', 'declined')) AS INTEGER) AS cancelled,
+    CAST((SELECT COUNT(DISTINCT r.email) FROM promo_redemptions r WHERE r.promo_code_id = p.id) AS INTEGER) AS customers
+FROM promo_codes p
+ORDER BY p.is_active DESC, p.created_at DESC, p.id DESC;
+
+-- name: GetPromoCodeStats :one
+SELECT p.*,
+    CAST((SELECT COUNT(*) FROM promo_redemptions r LEFT JOIN bookings b ON b.id = r.booking_id
+          WHERE r.promo_code_id = p.id AND (b.id IS NULL OR b.status NOT IN ('cancelled', 'declined'))) AS INTEGER) AS redemptions,
+    CAST((SELECT COUNT(*) FROM promo_redemptions r JOIN bookings b ON b.id = r.booking_id
+          WHERE r.promo_code_id = p.id AND b.status IN ('cancelled', 'declined')) AS INTEGER) AS cancelled,
+    CAST((SELECT COUNT(DISTINCT r.email) FROM promo_redemptions r WHERE r.promo_code_id = p.id) AS INTEGER) AS customers
+FROM promo_codes p
+WHERE p.id = ?;
+
+-- name: GetPromoCodeByCode :one
+SELECT * FROM promo_codes
+WHERE code = ? LIMIT 1;
+
+-- name: CreatePromoCode :one
+INSERT INTO promo_codes (code, description, discount_type, discount_value, starts_at, ends_at, max_uses, max_uses_per_customer, min_spend, is_active)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdatePromoCode :exec
+UPDATE promo_codes
+SET code = ?, description = ?, discount_type = ?, discount_value = ?, starts_at = ?, ends_at = ?,
+    max_uses = ?, max_uses_per_customer = ?, min_spend = ?, is_active = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- Codes that have been used are kept for their history; deactivate them
+-- instead
+-- name: DeleteUnusedPromoCode :execrows
+DELETE FROM promo_codes
+WHERE id = ? AND NOT EXISTS (SELECT 1 FROM promo_redemptions WHERE promo_code_id = promo_codes.id);
+
+-- name: ListPromoCodePackageIDs :many
+SELECT package_id FROM promo_code_packages
+WHERE promo_code_id = ?;
+
+-- name: ListPromoCodePackageNames :many
+SELECT pcp.promo_code_id, p.name
+FROM promo_code_packages pcp
+JOIN packages p ON p.id = pcp.package_id
+ORDER BY pcp.promo_code_id, p.sort_order, p.name;
+
+-- name: AddPromoCodePackage :exec
+INSERT INTO promo_code_packages (promo_code_id, package_id) VALUES (?, ?);
+
+-- name: ClearPromoCodePackages :exec
+DELETE FROM promo_code_packages WHERE promo_code_id = ?;
+
+-- name: CountPromoRedemptions :one
+SELECT COUNT(*) FROM promo_redemptions r
+LEFT JOIN bookings b ON b.id = r.booking_id
+WHERE r.promo_code_id = ? AND (b.id IS NULL OR b.status NOT IN ('cancelled', 'declined'));
+
+-- name: CountCustomerPromoRedemptions :one
+SELECT COUNT(*) FROM promo_redemptions r
+LEFT JOIN bookings b ON b.id = r.booking_id
+WHERE r.promo_code_id = ? AND r.email = ? AND (b.id IS NULL OR b.status NOT IN ('cancelled', 'declined'));
+
+-- name: CreatePromoRedemption :exec
+INSERT INTO promo_redemptions (promo_code_id, booking_id, quote_id, email)
+VALUES (?, ?, ?, ?);
+
+-- name: ListPromoRedemptions :many
+SELECT r.id, r.email, r.quote_id, r.created_at, r.booking_id,
+    CAST(COALESCE(b.customer_name, '') AS TEXT) AS customer_name,
+    CAST(COALESCE(b.status, '') AS TEXT) AS booking_status,
+    b.requested_start
+FROM promo_redemptions r
+LEFT JOIN bookings b ON b.id = r.booking_id
+WHERE r.promo_code_id = ?
+ORDER BY r.created_at DESC, r.id DESC;
+
+-- name: SetBookingPromo :exec
+UPDATE bookings
+SET promo_code = ?, discount_type = ?, discount_value = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- Gift certificate queries
+
+-- name: CreateGiftCertificate :one
+INSERT INTO gift_certificates (
+    code,
+    initial_value,
+    balance,
+    status,
+    source,
+    purchaser_name,
+    purchaser_email,
+    recipient_name,
+    recipient_email,
+    message,
+    expires_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetGiftCertificateByID :one
+SELECT * FROM gift_certificates
+WHERE id = ? LIMIT 1;
+
+-- name: GetGiftCertificateByCode :one
+SELECT * FROM gift_certificates
+WHERE code = ? LIMIT 1;
+
+-- name: GetGiftCertificateByPaymentID :one
+SELECT * FROM gift_certificates
+WHERE payment_id = ? LIMIT 1;
+
+-- Certificates still waiting for payment are left out
+-- name: ListGiftCertificates :many
+SELECT * FROM gift_certificates
+WHERE status != 'pending'
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+
+-- name: SetGiftCertificatePayment :exec
+UPDATE gift_certificates
+SET payment_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- Paid for online: the certificate gets its value
+-- name: ActivateGiftCertificate :execrows
+UPDATE gift_certificates
+SET status = 'active', balance = initial_value, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- Moves the balance by amount, which is negative when spending. Nothing
+-- changes if that would take it below zero.
+-- name: ChangeGiftCertificateBalance :one
+UPDATE gift_certificates
+SET balance = balance + CAST(sqlc.arg(amount) AS INTEGER), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND balance + CAST(sqlc.arg(amount) AS INTEGER) >= 0
+RETURNING balance;
+
+-- name: VoidGiftCertificate :execrows
+UPDATE gift_certificates
+SET status = 'void', balance = 0, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'active';
+
+-- name: CreateGiftCertificateTransaction :exec
+INSERT INTO gift_certificate_transactions (gift_certificate_id, kind, amount, balance_after, invoice_id, note)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListGiftCertificateTransactions :many
+SELECT t.*, i.number AS invoice_number
+FROM gift_certificate_transactions t
+LEFT JOIN invoices i ON i.id = t.invoice_id
+WHERE t.gift_certificate_id = ?
+ORDER BY t.id;
+
+-- What each certificate has paid towards an invoice, net of refunds
+-- name: ListInvoiceGiftCredits :many
+SELECT g.id, g.code, CAST(-SUM(t.amount) AS INTEGER) AS amount
+FROM gift_certificate_transactions t
+JOIN gift_certificates g ON g.id = t.gift_certificate_id
+WHERE t.invoice_id = ? AND t.kind IN ('redeem', 'refund')
+GROUP BY g.id, g.code
+HAVING SUM(t.amount) < 0
+ORDER BY MIN(t.id);
+
+-- Membership queries
+
+-- name: ListMembershipPlans :many
+SELECT p.*, pk.name AS package_name,
+    CAST((SELECT COUNT(*) FROM memberships m WHERE m.plan_id = p.id AND m.status = 'active') AS INTEGER) AS active_members
+FROM membership_plans p
+LEFT JOIN packages pk ON pk.id = p.package_id
+ORDER BY p.is_active DESC, p.price, p.name;
+
+-- name: ListActiveMembershipPlans :many
+SELECT p.*, pk.name AS package_name
+FROM membership_plans p
+LEFT JOIN packages pk ON pk.id = p.package_id
+WHERE p.is_active = 1
+ORDER BY p.price, p.name;
+
+-- name: GetMembershipPlanByID :one
+SELECT * FROM membership_plans WHERE id = ?;
+
+-- name: CreateMembershipPlan :one
+INSERT INTO membership_plans (name, description, package_id, services_per_period, period_months, price, is_active)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateMembershipPlan :exec
+UPDATE membership_plans
+SET name = ?, description = ?, package_id = ?, services_per_period = ?, period_months = ?, price = ?, is_active = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- Plans that have had members are kept for their history; deactivate
+-- them instead
+-- name: DeleteMembershipPlan :execrows
+DELETE FROM membership_plans
+WHERE id = ? AND NOT EXISTS (SELECT 1 FROM memberships WHERE plan_id = membership_plans.id);
+
+-- name: ListMemberships :many
+SELECT m.*, p.name AS plan_name, p.services_per_period, p.period_months, p.price
+FROM memberships m
+JOIN membership_plans p ON p.id = m.plan_id
+ORDER BY m.status, m.customer_name;
+
+-- name: GetMembershipByID :one
+SELECT m.*, p.name AS plan_name, p.package_id, p.services_per_period, p.period_months, p.price
+FROM memberships m
+JOIN membership_plans p ON p.id = m.plan_id
+WHERE m.id = ?;
+
+-- name: GetMembershipForUser :one
+SELECT m.*, p.name AS plan_name, p.package_id, p.services_per_period, p.period_months, p.price
+FROM memberships m
+JOIN membership_plans p ON p.id = m.plan_id
+WHERE m.clerk_user_id = ? AND m.status = 'active'
+ORDER BY m.paid_through DESC
+LIMIT 1;
+
+-- An active membership for an email that no account has claimed yet
+-- name: GetUnlinkedMembershipByEmail :one
+SELECT id FROM memberships
+WHERE email = ? AND status = 'active' AND clerk_user_id IS NULL
+ORDER BY paid_through DESC
+LIMIT 1;
+
+-- name: LinkMembershipUser :exec
+UPDATE memberships SET clerk_user_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: CreateMembership :one
+INSERT INTO memberships (plan_id, customer_name, email, phone, started_at, paid_through, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: RenewMembership :execrows
+UPDATE memberships
+SET paid_through = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'active';
+
+-- name: CancelMembership :execrows
+UPDATE memberships
+SET status = 'cancelled', cancelled_at = ?, cancel_reason = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'active';
+
+-- name: ResumeMembership :execrows
+UPDATE memberships
+SET status = 'active', cancelled_at = NULL, cancel_reason = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'cancelled';
+
+-- name: UpdateMembershipNotes :exec
+UPDATE memberships SET notes = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: CreateMembershipRenewal :exec
+INSERT INTO membership_renewals (membership_id, period_start, period_end, amount, payment_method)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ListMembershipRenewals :many
+SELECT * FROM membership_renewals WHERE membership_id = ? ORDER BY period_start DESC;
+
+-- Services used in a period. Cancelled and declined bookings give the
+-- service back.
+-- name: CountMembershipBookings :one
+SELECT COUNT(*) FROM bookings
+WHERE membership_id = ?
+  AND requested_start >= ?
+  AND requested_start < ?
+  AND status NOT IN ('cancelled', 'declined');
+
+-- name: ListMembershipBookingStarts :many
+SELECT membership_id, requested_start FROM bookings
+WHERE membership_id IS NOT NULL
+  AND requested_start >= ?
+  AND status NOT IN ('cancelled', 'declined');
+
+-- name: ListMembershipBookings :many
+SELECT * FROM bookings WHERE membership_id = ? ORDER BY requested_start DESC;
+
+-- name: SetBookingMembership :exec
+UPDATE bookings SET membership_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- Repeat booking queries
+
+-- name: CreateBookingSeries :one
+INSERT INTO booking_series (
+    customer_name,
+    email,
+    phone,
+    vehicle_details,
+    service_interest,
+    notes,
+    rrule,
+    starts_at,
+    duration_minutes,
+    booked_through,
+    previous_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetBookingSeriesByID :one
+SELECT * FROM booking_series WHERE id = ?;
+
+-- Upcoming counts bookings still to happen; conflicts counts dates from
+-- now on that couldn't be booked.
+-- name: ListBookingSeries :many
+SELECT s.*,
+    CAST((SELECT COUNT(*) FROM booking_series_occurrences o JOIN bookings b ON b.id = o.booking_id
+          WHERE o.series_id = s.id AND b.status IN ('pending', 'confirmed')) AS INTEGER) AS upcoming,
+    CAST((SELECT COUNT(*) FROM booking_series_occurrences o
+          WHERE o.series_id = s.id AND o.status = 'conflict' AND o.scheduled_at >= sqlc.arg(now)) AS INTEGER) AS conflicts
+FROM booking_series s
+ORDER BY s.status ASC, s.starts_at DESC;
+
+-- name: CountSeriesConflicts :one
+SELECT COUNT(*) FROM booking_series_occurrences o
+JOIN booking_series s ON s.id = o.series_id
+WHERE s.status = 'active' AND o.status = 'conflict' AND o.scheduled_at >= ?;
+
+-- Active series whose occurrences haven't been made up to the horizon
+-- name: ListSeriesToExtend :many
+SELECT * FROM booking_series WHERE status = 'active' AND booked_through < ?;
+
+-- name: SetSeriesBookedThrough :exec
+UPDATE booking_series SET booked_through = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: UpdateBookingSeries :exec
+UPDATE booking_series
+SET customer_name = ?,
+    email = ?,
+    phone = ?,
+    vehicle_details = ?,
+    service_interest = ?,
+    notes = ?,
+    rrule = ?,
+    starts_at = ?,
+    duration_minutes = ?,
+    booked_through = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: SetSeriesRule :exec
+UPDATE booking_series SET rrule = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: CancelBookingSeries :execrows
+UPDATE booking_series
+SET status = 'cancelled', cancelled_at = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'active';
+
+-- name: CreateSeriesOccurrence :exec
+INSERT INTO booking_series_occurrences (series_id, scheduled_at, booking_id, status)
+VALUES (?, ?, ?, ?);
+
+-- name: ListSeriesOccurrences :many
+SELECT o.*, b.requested_start, b.requested_end, b.status AS booking_status
+FROM booking_series_occurrences o
+LEFT JOIN bookings b ON b.id = o.booking_id
+WHERE o.series_id = ?
+ORDER BY o.scheduled_at ASC;
+
+-- name: GetSeriesOccurrence :one
+SELECT * FROM booking_series_occurrences WHERE id = ? AND series_id = ?;
+
+-- name: UpdateSeriesOccurrence :exec
+UPDATE booking_series_occurrences
+SET booking_id = ?, status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteSeriesOccurrencesFrom :exec
+DELETE FROM booking_series_occurrences WHERE series_id = ? AND scheduled_at >= ?;
+
+-- Bookings still to happen for a series, by when they were scheduled;
+-- a moved booking goes with the date it was moved from.
+-- name: ListSeriesBookingsFrom :many
+SELECT b.* FROM bookings b
+JOIN booking_series_occurrences o ON o.booking_id = b.id
+WHERE o.series_id = ?
+  AND o.scheduled_at >= ?
+  AND b.status IN ('pending', 'confirmed')
+ORDER BY b.requested_start ASC;
+
+-- name: SetBookingSeries :exec
+UPDATE bookings SET series_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: UpdateBookingTime :one
+UPDATE bookings
+SET requested_start = ?, requested_end = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING *;
+
+-- Waitlist queries
+
+-- name: CreateWaitlistEntry :one
+INSERT INTO waitlist_entries (
+    customer_name,
+    email,
+    phone,
+    vehicle_details,
+    service_interest,
+    notes,
+    date_from,
+    date_to,
+    slot_id,
+    clerk_user_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetWaitlistEntryByID :one
+SELECT * FROM waitlist_entries WHERE id = ?;
+
+-- name: ListWaitlistEntries :many
+SELECT e.*,
+    CAST((SELECT COUNT(*) FROM waitlist_offers o WHERE o.entry_id = e.id) AS INTEGER) AS offers
+FROM waitlist_entries e
+WHERE e.status = 'waiting' AND e.date_to > ?
+ORDER BY e.created_at ASC, e.id ASC;
+
+-- name: CountWaitlistEntries :one
+SELECT COUNT(*) FROM waitlist_entries WHERE status = 'waiting' AND date_to > ?;
+
+-- name: RemoveWaitlistEntry :execrows
+UPDATE waitlist_entries
+SET status = 'removed', updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'waiting';
+
+-- name: MarkWaitlistEntryBooked :exec
+UPDATE waitlist_entries
+SET status = 'booked', booking_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- Who to offer a freed slot to next: entries waiting for that day and
+-- slot that haven't been offered it before, first come first served.
+-- name: ListWaitlistEntriesForSlot :many
+SELECT e.* FROM waitlist_entries e
+WHERE e.status = 'waiting'
+  AND e.date_from <= sqlc.arg(slot_start)
+  AND e.date_to > sqlc.arg(slot_start)
+  AND (e.slot_id IS NULL OR e.slot_id = sqlc.arg(slot_id))
+  AND NOT EXISTS (
+    SELECT 1 FROM waitlist_offers o WHERE o.entry_id = e.id AND o.slot_start = sqlc.arg(slot_start)
+  )
+ORDER BY e.created_at ASC, e.id ASC;
+
+-- name: CreateWaitlistOffer :one
+INSERT INTO waitlist_offers (entry_id, slot_start, slot_end, expires_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetWaitlistOfferByID :one
+SELECT * FROM waitlist_offers WHERE id = ?;
+
+-- name: ListOpenWaitlistOffers :many
+SELECT o.*, e.customer_name, e.email
+FROM waitlist_offers o
+JOIN waitlist_entries e ON e.id = o.entry_id
+WHERE o.status = 'open' AND o.expires_at > ?
+ORDER BY o.expires_at ASC;
+
+-- Open offers whose time is up, to pass on to the next customer
+-- name: ListLapsedWaitlistOffers :many
+SELECT * FROM waitlist_offers WHERE status = 'open' AND expires_at <= ?;
+
+-- Slots held for a waitlisted customer
+-- name: ListWaitlistHolds :many
+SELECT slot_start FROM waitlist_offers
+WHERE status = 'open' AND expires_at > ? AND slot_start >= ? AND slot_start < ?;
+
+-- name: CountWaitlistHoldsAt :one
+SELECT COUNT(*) FROM waitlist_offers
+WHERE status = 'open' AND expires_at > ? AND slot_start = ?;
+
+-- name: CloseWaitlistOffer :execrows
+UPDATE waitlist_offers
+SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'open';
+
+-- name: ClaimWaitlistOffer :execrows
+UPDATE waitlist_offers
+SET status = 'claimed', booking_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'open' AND expires_at > ?;
+
+-- Slot hold queries
+
+-- name: CreateSlotHold :one
+INSERT INTO slot_holds (token, slot_start, slot_end, expires_at, ip_address)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetSlotHoldByToken :one
+SELECT * FROM slot_holds WHERE token = ?;
+
+-- name: ReleaseSlotHold :execrows
+UPDATE slot_holds
+SET status = 'released', updated_at = CURRENT_TIMESTAMP
+WHERE token = ? AND status = 'held';
+
+-- The hold only converts into a booking for the slot it was held on
+-- name: ConvertSlotHold :execrows
+UPDATE slot_holds
+SET status = 'converted', booking_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE token = ? AND slot_start = ? AND status = 'held';
+
+-- name: CountActiveSlotHoldsForIP :one
+SELECT COUNT(*) FROM slot_holds
+WHERE status = 'held' AND expires_at > ? AND ip_address = ?;
+
+-- Slots other visitors are holding; token is the asking visitor's own
+-- name: ListSlotHolds :many
+SELECT slot_start FROM slot_holds
+WHERE status = 'held' AND expires_at > ? AND slot_start >= ? AND slot_start < ? AND token != ?;
+
+-- name: CountSlotHoldsAt :one
+SELECT COUNT(*) FROM slot_holds
+WHERE status = 'held' AND expires_at > ? AND slot_start = ? AND token != ?;
+
+-- Mobile service queries
+
+-- name: SetBookingServiceLocation :exec
+UPDATE bookings
+SET service_address = ?, service_zip = ?, service_lat = ?, service_lng = ?,
+    travel_minutes = ?, travel_fee = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- Bookings that take their slot, with where they are and who's doing
+-- them, for working out the drive between jobs
+-- name: ListBookingStops :many
+SELECT requested_start, requested_end, service_address, service_lat, service_lng, travel_minutes, staff_id
+FROM bookings
+WHERE requested_start >= ?
+  AND requested_start < ?
+  AND status IN ('pending', 'confirmed', 'completed')
+ORDER BY requested_start;
+
+-- Staff queries
+
+-- name: ListStaff :many
+SELECT * FROM staff
+ORDER BY is_active DESC, name, id;
+
+-- name: GetStaffByID :one
+SELECT * FROM staff WHERE id = ? LIMIT 1;
+
+-- Staff who can be assigned jobs
+-- name: ListJobStaff :many
+SELECT * FROM staff
+WHERE is_active = 1 AND role IN ('technician', 'lead')
+ORDER BY name, id;
+
+-- The staff record for a signed-in account
+-- name: GetActiveStaffByEmail :one
+SELECT * FROM staff
+WHERE is_active = 1 AND email = ?
+ORDER BY id LIMIT 1;
+
+-- name: CreateStaff :one
+INSERT INTO staff (name, email, phone, role, skills, is_active)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateStaff :one
+UPDATE staff
+SET name = ?, email = ?, phone = ?, role = ?, skills = ?, is_active = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteStaff :exec
+DELETE FROM staff WHERE id = ?;
+
+-- name: ListStaffHours :many
+SELECT * FROM staff_hours
+WHERE staff_id = ?
+ORDER BY weekday;
+
+-- Shifts of everyone who can be assigned jobs
+-- name: ListJobStaffHours :many
+SELECT h.* FROM staff_hours h
+JOIN staff s ON s.id = h.staff_id
+WHERE s.is_active = 1 AND s.role IN ('technician', 'lead');
+
+-- name: DeleteStaffHours :exec
+DELETE FROM staff_hours WHERE staff_id = ?;
+
+-- name: CreateStaffHours :exec
+INSERT INTO staff_hours (staff_id, weekday, start_minute, end_minute)
+VALUES (?, ?, ?, ?);
+
+-- Time off that hasn't ended by the given time
+-- name: ListStaffTimeOff :many
+SELECT * FROM staff_time_off
+WHERE staff_id = ? AND ends_at > ?
+ORDER BY starts_at;
+
+-- Everyone's time off overlapping a range
+-- name: ListTimeOffBetween :many
+SELECT * FROM staff_time_off
+WHERE ends_at > ? AND starts_at < ?;
+
+-- name: CreateStaffTimeOff :one
+INSERT INTO staff_time_off (staff_id, starts_at, ends_at, reason)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: DeleteStaffTimeOff :exec
+DELETE FROM staff_time_off WHERE id = ? AND staff_id = ?;
+
+-- name: AssignBooking :exec
+UPDATE bookings
+SET staff_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- A technician's jobs that take their slot, for their schedule
+-- name: ListStaffBookings :many
+SELECT * FROM bookings
+WHERE staff_id = ?
+  AND requested_start >= ?
+  AND requested_start < ?
+  AND status IN ('pending', 'confirmed', 'completed')
+ORDER BY requested_start;
+
+-- Jobs assigned to a technician that overlap a stretch of time off
+-- name: CountStaffBookingsOverlapping :one
+SELECT COUNT(*) FROM bookings
+WHERE staff_id = ?
+  AND requested_start < ?
+  AND requested_end > ?
+  AND status IN ('pending', 'confirmed', 'completed');
+
 -- Booking queries
 
 -- name: ListBookings :many
@@ -184,14 +1502,14 @@ SELECT requested_start, requested_end, status
 FROM bookings
 WHERE requested_start >= ?
   AND requested_start < ?
-  AND status IN ('pending', 'confirmed')
+  AND status IN ('pending', 'confirmed', 'completed')
 ORDER BY requested_start;
 
 -- name: CountBlockedSlotsAt :one
 SELECT COUNT(*)
 FROM bookings
 WHERE requested_start = ?
-  AND status IN ('pending', 'confirmed');
+  AND status IN ('pending', 'confirmed', 'completed');
 
 -- name: ListBookingsForCalendar :many
 SELECT id, customer_name, email, phone, vehicle_details, service_interest, requested_start, requested_end, status
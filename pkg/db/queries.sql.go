@@ -8,14 +8,281 @@ package db
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 )
 
+const acceptQuote = `-- name: AcceptQuote :execrows
+UPDATE quotes
+SET status = 'accepted', booking_id = ?, accepted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'sent' AND expires_at > ?
+`
+
+type AcceptQuoteParams struct {
+	BookingID sql.NullInt64 `json:"booking_id"`
+	ID        int64         `json:"id"`
+	ExpiresAt sql.NullTime  `json:"expires_at"`
+}
+
+// Only a sent, unexpired quote can be accepted, and only once
+func (q *Queries) AcceptQuote(ctx context.Context, arg AcceptQuoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, acceptQuote, arg.BookingID, arg.ID, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const activateGiftCertificate = `-- name: ActivateGiftCertificate :execrows
+UPDATE gift_certificates
+SET status = 'active', balance = initial_value, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending'
+`
+
+// Paid for online: the certificate gets its value
+func (q *Queries) ActivateGiftCertificate(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, activateGiftCertificate, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const addPromoCodePackage = `-- name: AddPromoCodePackage :exec
+INSERT INTO promo_code_packages (promo_code_id, package_id) VALUES (?, ?)
+`
+
+type AddPromoCodePackageParams struct {
+	PromoCodeID int64 `json:"promo_code_id"`
+	PackageID   int64 `json:"package_id"`
+}
+
+func (q *Queries) AddPromoCodePackage(ctx context.Context, arg AddPromoCodePackageParams) error {
+	_, err := q.db.ExecContext(ctx, addPromoCodePackage, arg.PromoCodeID, arg.PackageID)
+	return err
+}
+
+const assignBooking = `-- name: AssignBooking :exec
+UPDATE bookings
+SET staff_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type AssignBookingParams struct {
+	StaffID sql.NullInt64 `json:"staff_id"`
+	ID      int64         `json:"id"`
+}
+
+func (q *Queries) AssignBooking(ctx context.Context, arg AssignBookingParams) error {
+	_, err := q.db.ExecContext(ctx, assignBooking, arg.StaffID, arg.ID)
+	return err
+}
+
+const cancelBookingSeries = `-- name: CancelBookingSeries :execrows
+UPDATE booking_series
+SET status = 'cancelled', cancelled_at = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'active'
+`
+
+type CancelBookingSeriesParams struct {
+	CancelledAt sql.NullTime `json:"cancelled_at"`
+	ID          int64        `json:"id"`
+}
+
+func (q *Queries) CancelBookingSeries(ctx context.Context, arg CancelBookingSeriesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelBookingSeries, arg.CancelledAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const cancelMembership = `-- name: CancelMembership :execrows
+UPDATE memberships
+SET status = 'cancelled', cancelled_at = ?, cancel_reason = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'active'
+`
+
+type CancelMembershipParams struct {
+	CancelledAt  sql.NullTime   `json:"cancelled_at"`
+	CancelReason sql.NullString `json:"cancel_reason"`
+	ID           int64          `json:"id"`
+}
+
+func (q *Queries) CancelMembership(ctx context.Context, arg CancelMembershipParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelMembership, arg.CancelledAt, arg.CancelReason, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const cancelPendingBooking = `-- name: CancelPendingBooking :execrows
+UPDATE bookings
+SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending'
+`
+
+func (q *Queries) CancelPendingBooking(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelPendingBooking, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const changeGiftCertificateBalance = `-- name: ChangeGiftCertificateBalance :one
+UPDATE gift_certificates
+SET balance = balance + CAST(? AS INTEGER), updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND balance + CAST(? AS INTEGER) >= 0
+RETURNING balance
+`
+
+type ChangeGiftCertificateBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+// Moves the balance by amount, which is negative when spending. Nothing
+// changes if that would take it below zero.
+func (q *Queries) ChangeGiftCertificateBalance(ctx context.Context, arg ChangeGiftCertificateBalanceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, changeGiftCertificateBalance, arg.Amount, arg.ID, arg.Amount)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const claimWaitlistOffer = `-- name: ClaimWaitlistOffer :execrows
+UPDATE waitlist_offers
+SET status = 'claimed', booking_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'open' AND expires_at > ?
+`
+
+type ClaimWaitlistOfferParams struct {
+	BookingID sql.NullInt64 `json:"booking_id"`
+	ID        int64         `json:"id"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+func (q *Queries) ClaimWaitlistOffer(ctx context.Context, arg ClaimWaitlistOfferParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimWaitlistOffer, arg.BookingID, arg.ID, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const clearDefaultTaxRate = `-- name: ClearDefaultTaxRate :exec
+UPDATE tax_rates
+SET is_default = 0
+WHERE id != ?
+`
+
+func (q *Queries) ClearDefaultTaxRate(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, clearDefaultTaxRate, id)
+	return err
+}
+
+const clearPackageGalleryGroups = `-- name: ClearPackageGalleryGroups :exec
+UPDATE gallery_groups SET package_id = NULL WHERE package_id = ?
+`
+
+func (q *Queries) ClearPackageGalleryGroups(ctx context.Context, packageID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, clearPackageGalleryGroups, packageID)
+	return err
+}
+
+const clearPromoCodePackages = `-- name: ClearPromoCodePackages :exec
+DELETE FROM promo_code_packages WHERE promo_code_id = ?
+`
+
+func (q *Queries) ClearPromoCodePackages(ctx context.Context, promoCodeID int64) error {
+	_, err := q.db.ExecContext(ctx, clearPromoCodePackages, promoCodeID)
+	return err
+}
+
+const closeUnpaidPayment = `-- name: CloseUnpaidPayment :execrows
+UPDATE payments
+SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending'
+`
+
+type CloseUnpaidPaymentParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+// Ends a payment that was never completed (failed, expired or void)
+func (q *Queries) CloseUnpaidPayment(ctx context.Context, arg CloseUnpaidPaymentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, closeUnpaidPayment, arg.Status, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const closeWaitlistOffer = `-- name: CloseWaitlistOffer :execrows
+UPDATE waitlist_offers
+SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'open'
+`
+
+type CloseWaitlistOfferParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) CloseWaitlistOffer(ctx context.Context, arg CloseWaitlistOfferParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, closeWaitlistOffer, arg.Status, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const convertSlotHold = `-- name: ConvertSlotHold :execrows
+UPDATE slot_holds
+SET status = 'converted', booking_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE token = ? AND slot_start = ? AND status = 'held'
+`
+
+type ConvertSlotHoldParams struct {
+	BookingID sql.NullInt64 `json:"booking_id"`
+	Token     string        `json:"token"`
+	SlotStart time.Time     `json:"slot_start"`
+}
+
+// The hold only converts into a booking for the slot it was held on
+func (q *Queries) ConvertSlotHold(ctx context.Context, arg ConvertSlotHoldParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, convertSlotHold, arg.BookingID, arg.Token, arg.SlotStart)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countActiveSlotHoldsForIP = `-- name: CountActiveSlotHoldsForIP :one
+SELECT COUNT(*) FROM slot_holds
+WHERE status = 'held' AND expires_at > ? AND ip_address = ?
+`
+
+type CountActiveSlotHoldsForIPParams struct {
+	ExpiresAt time.Time      `json:"expires_at"`
+	IpAddress sql.NullString `json:"ip_address"`
+}
+
+func (q *Queries) CountActiveSlotHoldsForIP(ctx context.Context, arg CountActiveSlotHoldsForIPParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveSlotHoldsForIP, arg.ExpiresAt, arg.IpAddress)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countBlockedSlotsAt = `-- name: CountBlockedSlotsAt :one
 SELECT COUNT(*)
 FROM bookings
 WHERE requested_start = ?
-  AND status IN ('pending', 'confirmed')
+  AND status IN ('pending', 'confirmed', 'completed')
 `
 
 func (q *Queries) CountBlockedSlotsAt(ctx context.Context, requestedStart time.Time) (int64, error) {
@@ -48,345 +315,276 @@ func (q *Queries) CountBookingsByStatus(ctx context.Context, status sql.NullStri
 	return count, err
 }
 
-const countGalleryGroups = `-- name: CountGalleryGroups :one
-SELECT COUNT(*) FROM gallery_groups
+const countContactMessages = `-- name: CountContactMessages :one
+SELECT COUNT(*) FROM contact_messages
 `
 
-func (q *Queries) CountGalleryGroups(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countGalleryGroups)
+func (q *Queries) CountContactMessages(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countContactMessages)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
-const countMedia = `-- name: CountMedia :one
-SELECT COUNT(*) FROM media
+const countCustomerPromoRedemptions = `-- name: CountCustomerPromoRedemptions :one
+SELECT COUNT(*) FROM promo_redemptions r
+LEFT JOIN bookings b ON b.id = r.booking_id
+WHERE r.promo_code_id = ? AND r.email = ? AND (b.id IS NULL OR b.status NOT IN ('cancelled', 'declined'))
 `
 
-func (q *Queries) CountMedia(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countMedia)
+type CountCustomerPromoRedemptionsParams struct {
+	PromoCodeID int64  `json:"promo_code_id"`
+	Email       string `json:"email"`
+}
+
+func (q *Queries) CountCustomerPromoRedemptions(ctx context.Context, arg CountCustomerPromoRedemptionsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countCustomerPromoRedemptions, arg.PromoCodeID, arg.Email)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
-const countPackages = `-- name: CountPackages :one
-SELECT COUNT(*) FROM packages
+const countDuplicateReviews = `-- name: CountDuplicateReviews :one
+SELECT COUNT(*) FROM reviews
+WHERE (source = ? AND external_id = ?)
+   OR (lower(author) = lower(CAST(? AS TEXT))
+       AND COALESCE(body, '') = CAST(? AS TEXT)
+       AND rating = ?)
 `
 
-func (q *Queries) CountPackages(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countPackages)
+type CountDuplicateReviewsParams struct {
+	Source     string `json:"source"`
+	ExternalID string `json:"external_id"`
+	Author     string `json:"author"`
+	Body       string `json:"body"`
+	Rating     int64  `json:"rating"`
+}
+
+// Matches the same platform review, or the same words entered by hand
+// before imports existed
+func (q *Queries) CountDuplicateReviews(ctx context.Context, arg CountDuplicateReviewsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDuplicateReviews,
+		arg.Source,
+		arg.ExternalID,
+		arg.Author,
+		arg.Body,
+		arg.Rating,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
-const countReviews = `-- name: CountReviews :one
-SELECT COUNT(*) FROM reviews
+const countFeaturedGalleryGroups = `-- name: CountFeaturedGalleryGroups :one
+SELECT COUNT(*) FROM gallery_groups
+WHERE is_featured = 1
+  AND (CAST(? AS TEXT) IS NULL OR vehicle_make = ?)
+  AND (CAST(? AS TEXT) IS NULL OR vehicle_model = ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year >= ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year <= ?)
+  AND (CAST(? AS INTEGER) IS NULL OR package_id = ?)
 `
 
-func (q *Queries) CountReviews(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countReviews)
+type CountFeaturedGalleryGroupsParams struct {
+	VehicleMake  sql.NullString `json:"vehicle_make"`
+	VehicleModel sql.NullString `json:"vehicle_model"`
+	YearMin      sql.NullInt64  `json:"year_min"`
+	YearMax      sql.NullInt64  `json:"year_max"`
+	PackageID    sql.NullInt64  `json:"package_id"`
+}
+
+func (q *Queries) CountFeaturedGalleryGroups(ctx context.Context, arg CountFeaturedGalleryGroupsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeaturedGalleryGroups,
+		arg.VehicleMake,
+		arg.VehicleMake,
+		arg.VehicleModel,
+		arg.VehicleModel,
+		arg.YearMin,
+		arg.YearMin,
+		arg.YearMax,
+		arg.YearMax,
+		arg.PackageID,
+		arg.PackageID,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
-const createBooking = `-- name: CreateBooking :one
-INSERT INTO bookings (
-    customer_name,
-    email,
-    phone,
-    vehicle_details,
-    service_interest,
-    notes,
-    requested_start,
-    requested_end,
-    status,
-    source,
-    clerk_user_id
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at
+const countGalleryGroups = `-- name: CountGalleryGroups :one
+SELECT COUNT(*) FROM gallery_groups
 `
 
-type CreateBookingParams struct {
-	CustomerName    string         `json:"customer_name"`
-	Email           string         `json:"email"`
-	Phone           sql.NullString `json:"phone"`
-	VehicleDetails  sql.NullString `json:"vehicle_details"`
-	ServiceInterest sql.NullString `json:"service_interest"`
-	Notes           sql.NullString `json:"notes"`
-	RequestedStart  time.Time      `json:"requested_start"`
-	RequestedEnd    time.Time      `json:"requested_end"`
-	Status          sql.NullString `json:"status"`
-	Source          sql.NullString `json:"source"`
-	ClerkUserID     sql.NullString `json:"clerk_user_id"`
+func (q *Queries) CountGalleryGroups(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGalleryGroups)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-func (q *Queries) CreateBooking(ctx context.Context, arg CreateBookingParams) (Booking, error) {
-	row := q.db.QueryRowContext(ctx, createBooking,
-		arg.CustomerName,
-		arg.Email,
-		arg.Phone,
-		arg.VehicleDetails,
-		arg.ServiceInterest,
-		arg.Notes,
-		arg.RequestedStart,
-		arg.RequestedEnd,
-		arg.Status,
-		arg.Source,
-		arg.ClerkUserID,
-	)
-	var i Booking
-	err := row.Scan(
-		&i.ID,
-		&i.CustomerName,
-		&i.Email,
-		&i.Phone,
-		&i.VehicleDetails,
-		&i.ServiceInterest,
-		&i.Notes,
-		&i.RequestedStart,
-		&i.RequestedEnd,
-		&i.Status,
-		&i.Source,
-		&i.InternalNotes,
-		&i.ClerkUserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+const countGalleryGroupsByMake = `-- name: CountGalleryGroupsByMake :many
+SELECT vehicle_make, COUNT(*) AS count FROM gallery_groups
+WHERE vehicle_make IS NOT NULL AND vehicle_make != ''
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year >= ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year <= ?)
+  AND (CAST(? AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(? AS INTEGER) IS NULL OR package_id = ?)
+GROUP BY vehicle_make
+ORDER BY vehicle_make
+`
+
+type CountGalleryGroupsByMakeParams struct {
+	YearMin      sql.NullInt64 `json:"year_min"`
+	YearMax      sql.NullInt64 `json:"year_max"`
+	FeaturedOnly bool          `json:"featured_only"`
+	PackageID    sql.NullInt64 `json:"package_id"`
+}
+
+type CountGalleryGroupsByMakeRow struct {
+	VehicleMake sql.NullString `json:"vehicle_make"`
+	Count       int64          `json:"count"`
+}
+
+func (q *Queries) CountGalleryGroupsByMake(ctx context.Context, arg CountGalleryGroupsByMakeParams) ([]CountGalleryGroupsByMakeRow, error) {
+	rows, err := q.db.QueryContext(ctx, countGalleryGroupsByMake,
+		arg.YearMin,
+		arg.YearMin,
+		arg.YearMax,
+		arg.YearMax,
+		arg.FeaturedOnly,
+		arg.PackageID,
+		arg.PackageID,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountGalleryGroupsByMakeRow
+	for rows.Next() {
+		var i CountGalleryGroupsByMakeRow
+		if err := rows.Scan(&i.VehicleMake, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const createGalleryGroup = `-- name: CreateGalleryGroup :one
-INSERT INTO gallery_groups (title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at
+const countGalleryGroupsByModel = `-- name: CountGalleryGroupsByModel :many
+SELECT vehicle_model, COUNT(*) AS count FROM gallery_groups
+WHERE vehicle_model IS NOT NULL AND vehicle_model != ''
+  AND (CAST(? AS TEXT) IS NULL OR vehicle_make = ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year >= ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year <= ?)
+  AND (CAST(? AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(? AS INTEGER) IS NULL OR package_id = ?)
+GROUP BY vehicle_model
+ORDER BY vehicle_model
 `
 
-type CreateGalleryGroupParams struct {
-	Title        string         `json:"title"`
-	Slug         string         `json:"slug"`
+type CountGalleryGroupsByModelParams struct {
 	VehicleMake  sql.NullString `json:"vehicle_make"`
+	YearMin      sql.NullInt64  `json:"year_min"`
+	YearMax      sql.NullInt64  `json:"year_max"`
+	FeaturedOnly bool           `json:"featured_only"`
+	PackageID    sql.NullInt64  `json:"package_id"`
+}
+
+type CountGalleryGroupsByModelRow struct {
 	VehicleModel sql.NullString `json:"vehicle_model"`
-	VehicleYear  sql.NullInt64  `json:"vehicle_year"`
-	Description  sql.NullString `json:"description"`
-	IsFeatured   sql.NullBool   `json:"is_featured"`
-	SortOrder    sql.NullInt64  `json:"sort_order"`
+	Count        int64          `json:"count"`
 }
 
-func (q *Queries) CreateGalleryGroup(ctx context.Context, arg CreateGalleryGroupParams) (GalleryGroup, error) {
-	row := q.db.QueryRowContext(ctx, createGalleryGroup,
-		arg.Title,
-		arg.Slug,
+func (q *Queries) CountGalleryGroupsByModel(ctx context.Context, arg CountGalleryGroupsByModelParams) ([]CountGalleryGroupsByModelRow, error) {
+	rows, err := q.db.QueryContext(ctx, countGalleryGroupsByModel,
 		arg.VehicleMake,
-		arg.VehicleModel,
-		arg.VehicleYear,
-		arg.Description,
-		arg.IsFeatured,
-		arg.SortOrder,
-	)
-	var i GalleryGroup
-	err := row.Scan(
-		&i.ID,
-		&i.Title,
-		&i.Slug,
-		&i.VehicleMake,
-		&i.VehicleModel,
-		&i.VehicleYear,
-		&i.Description,
-		&i.IsFeatured,
-		&i.SortOrder,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
-}
-
-const createMedia = `-- name: CreateMedia :one
-INSERT INTO media (gallery_group_id, url, kind, sort_order, alt_text)
-VALUES (?, ?, ?, ?, ?)
-RETURNING id, gallery_group_id, url, kind, sort_order, alt_text, created_at
-`
-
-type CreateMediaParams struct {
-	GalleryGroupID sql.NullInt64  `json:"gallery_group_id"`
-	Url            string         `json:"url"`
-	Kind           sql.NullString `json:"kind"`
-	SortOrder      sql.NullInt64  `json:"sort_order"`
-	AltText        sql.NullString `json:"alt_text"`
-}
-
-func (q *Queries) CreateMedia(ctx context.Context, arg CreateMediaParams) (Medium, error) {
-	row := q.db.QueryRowContext(ctx, createMedia,
-		arg.GalleryGroupID,
-		arg.Url,
-		arg.Kind,
-		arg.SortOrder,
-		arg.AltText,
-	)
-	var i Medium
-	err := row.Scan(
-		&i.ID,
-		&i.GalleryGroupID,
-		&i.Url,
-		&i.Kind,
-		&i.SortOrder,
-		&i.AltText,
-		&i.CreatedAt,
+		arg.VehicleMake,
+		arg.YearMin,
+		arg.YearMin,
+		arg.YearMax,
+		arg.YearMax,
+		arg.FeaturedOnly,
+		arg.PackageID,
+		arg.PackageID,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountGalleryGroupsByModelRow
+	for rows.Next() {
+		var i CountGalleryGroupsByModelRow
+		if err := rows.Scan(&i.VehicleModel, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const createPackage = `-- name: CreatePackage :one
-INSERT INTO packages (slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at
+const countGalleryGroupsByPackage = `-- name: CountGalleryGroupsByPackage :many
+SELECT p.id, p.slug, p.name, COUNT(*) AS count FROM gallery_groups g
+JOIN packages p ON p.id = g.package_id
+WHERE (CAST(? AS TEXT) IS NULL OR g.vehicle_make = ?)
+  AND (CAST(? AS TEXT) IS NULL OR g.vehicle_model = ?)
+  AND (CAST(? AS INTEGER) IS NULL OR g.vehicle_year >= ?)
+  AND (CAST(? AS INTEGER) IS NULL OR g.vehicle_year <= ?)
+  AND (CAST(? AS BOOLEAN) = 0 OR g.is_featured = 1)
+GROUP BY p.id
+ORDER BY p.sort_order, p.id
 `
 
-type CreatePackageParams struct {
-	Slug        string         `json:"slug"`
-	Name        string         `json:"name"`
-	ShortDesc   sql.NullString `json:"short_desc"`
-	LongDesc    sql.NullString `json:"long_desc"`
-	PriceMin    sql.NullInt64  `json:"price_min"`
-	PriceMax    sql.NullInt64  `json:"price_max"`
-	DurationEst sql.NullInt64  `json:"duration_est"`
-	IsActive    sql.NullBool   `json:"is_active"`
-	SortOrder   sql.NullInt64  `json:"sort_order"`
-}
-
-func (q *Queries) CreatePackage(ctx context.Context, arg CreatePackageParams) (Package, error) {
-	row := q.db.QueryRowContext(ctx, createPackage,
-		arg.Slug,
-		arg.Name,
-		arg.ShortDesc,
-		arg.LongDesc,
-		arg.PriceMin,
-		arg.PriceMax,
-		arg.DurationEst,
-		arg.IsActive,
-		arg.SortOrder,
-	)
-	var i Package
-	err := row.Scan(
-		&i.ID,
-		&i.Slug,
-		&i.Name,
-		&i.ShortDesc,
-		&i.LongDesc,
-		&i.PriceMin,
-		&i.PriceMax,
-		&i.DurationEst,
-		&i.IsActive,
-		&i.SortOrder,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type CountGalleryGroupsByPackageParams struct {
+	VehicleMake  sql.NullString `json:"vehicle_make"`
+	VehicleModel sql.NullString `json:"vehicle_model"`
+	YearMin      sql.NullInt64  `json:"year_min"`
+	YearMax      sql.NullInt64  `json:"year_max"`
+	FeaturedOnly bool           `json:"featured_only"`
 }
 
-const createReview = `-- name: CreateReview :one
-INSERT INTO reviews (author, rating, body, source, is_featured)
-VALUES (?, ?, ?, ?, ?)
-RETURNING id, author, rating, body, source, is_featured, created_at
-`
-
-type CreateReviewParams struct {
-	Author     string         `json:"author"`
-	Rating     sql.NullInt64  `json:"rating"`
-	Body       sql.NullString `json:"body"`
-	Source     sql.NullString `json:"source"`
-	IsFeatured sql.NullBool   `json:"is_featured"`
+type CountGalleryGroupsByPackageRow struct {
+	ID    int64  `json:"id"`
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
 }
 
-func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) (Review, error) {
-	row := q.db.QueryRowContext(ctx, createReview,
-		arg.Author,
-		arg.Rating,
-		arg.Body,
-		arg.Source,
-		arg.IsFeatured,
-	)
-	var i Review
-	err := row.Scan(
-		&i.ID,
-		&i.Author,
-		&i.Rating,
-		&i.Body,
-		&i.Source,
-		&i.IsFeatured,
-		&i.CreatedAt,
+func (q *Queries) CountGalleryGroupsByPackage(ctx context.Context, arg CountGalleryGroupsByPackageParams) ([]CountGalleryGroupsByPackageRow, error) {
+	rows, err := q.db.QueryContext(ctx, countGalleryGroupsByPackage,
+		arg.VehicleMake,
+		arg.VehicleMake,
+		arg.VehicleModel,
+		arg.VehicleModel,
+		arg.YearMin,
+		arg.YearMin,
+		arg.YearMax,
+		arg.YearMax,
+		arg.FeaturedOnly,
 	)
-	return i, err
-}
-
-const deleteGalleryGroup = `-- name: DeleteGalleryGroup :exec
-DELETE FROM gallery_groups WHERE id = ?
-`
-
-func (q *Queries) DeleteGalleryGroup(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteGalleryGroup, id)
-	return err
-}
-
-const deleteMedia = `-- name: DeleteMedia :exec
-DELETE FROM media WHERE id = ?
-`
-
-func (q *Queries) DeleteMedia(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteMedia, id)
-	return err
-}
-
-const deletePackage = `-- name: DeletePackage :exec
-DELETE FROM packages WHERE id = ?
-`
-
-func (q *Queries) DeletePackage(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deletePackage, id)
-	return err
-}
-
-const deleteReview = `-- name: DeleteReview :exec
-DELETE FROM reviews WHERE id = ?
-`
-
-func (q *Queries) DeleteReview(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteReview, id)
-	return err
-}
-
-const getAllPackages = `-- name: GetAllPackages :many
-
-SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at FROM packages
-WHERE is_active = 1
-ORDER BY sort_order, id
-`
-
-// Package queries
-func (q *Queries) GetAllPackages(ctx context.Context) ([]Package, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPackages)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Package
+	var items []CountGalleryGroupsByPackageRow
 	for rows.Next() {
-		var i Package
+		var i CountGalleryGroupsByPackageRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Slug,
 			&i.Name,
-			&i.ShortDesc,
-			&i.LongDesc,
-			&i.PriceMin,
-			&i.PriceMax,
-			&i.DurationEst,
-			&i.IsActive,
-			&i.SortOrder,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Count,
 		); err != nil {
 			return nil, err
 		}
@@ -401,34 +599,47 @@ func (q *Queries) GetAllPackages(ctx context.Context) ([]Package, error) {
 	return items, nil
 }
 
-const getAllPackagesAdmin = `-- name: GetAllPackagesAdmin :many
-SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at FROM packages
-ORDER BY sort_order, id
+const countGalleryGroupsByYear = `-- name: CountGalleryGroupsByYear :many
+SELECT vehicle_year, COUNT(*) AS count FROM gallery_groups
+WHERE vehicle_year IS NOT NULL
+  AND (CAST(? AS TEXT) IS NULL OR vehicle_make = ?)
+  AND (CAST(? AS TEXT) IS NULL OR vehicle_model = ?)
+  AND (CAST(? AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(? AS INTEGER) IS NULL OR package_id = ?)
+GROUP BY vehicle_year
+ORDER BY vehicle_year DESC
 `
 
-func (q *Queries) GetAllPackagesAdmin(ctx context.Context) ([]Package, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPackagesAdmin)
+type CountGalleryGroupsByYearParams struct {
+	VehicleMake  sql.NullString `json:"vehicle_make"`
+	VehicleModel sql.NullString `json:"vehicle_model"`
+	FeaturedOnly bool           `json:"featured_only"`
+	PackageID    sql.NullInt64  `json:"package_id"`
+}
+
+type CountGalleryGroupsByYearRow struct {
+	VehicleYear sql.NullInt64 `json:"vehicle_year"`
+	Count       int64         `json:"count"`
+}
+
+func (q *Queries) CountGalleryGroupsByYear(ctx context.Context, arg CountGalleryGroupsByYearParams) ([]CountGalleryGroupsByYearRow, error) {
+	rows, err := q.db.QueryContext(ctx, countGalleryGroupsByYear,
+		arg.VehicleMake,
+		arg.VehicleMake,
+		arg.VehicleModel,
+		arg.VehicleModel,
+		arg.FeaturedOnly,
+		arg.PackageID,
+		arg.PackageID,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Package
+	var items []CountGalleryGroupsByYearRow
 	for rows.Next() {
-		var i Package
-		if err := rows.Scan(
-			&i.ID,
-			&i.Slug,
-			&i.Name,
-			&i.ShortDesc,
-			&i.LongDesc,
-			&i.PriceMin,
-			&i.PriceMax,
-			&i.DurationEst,
-			&i.IsActive,
-			&i.SortOrder,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
+		var i CountGalleryGroupsByYearRow
+		if err := rows.Scan(&i.VehicleYear, &i.Count); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -442,130 +653,65 @@ func (q *Queries) GetAllPackagesAdmin(ctx context.Context) ([]Package, error) {
 	return items, nil
 }
 
-const getBookingByID = `-- name: GetBookingByID :one
-SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at FROM bookings
-WHERE id = ? LIMIT 1
+const countGalleryGroupsFiltered = `-- name: CountGalleryGroupsFiltered :one
+SELECT COUNT(*) FROM gallery_groups
+WHERE (CAST(? AS TEXT) IS NULL OR vehicle_make = ?)
+  AND (CAST(? AS TEXT) IS NULL OR vehicle_model = ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year >= ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year <= ?)
+  AND (CAST(? AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(? AS INTEGER) IS NULL OR package_id = ?)
 `
 
-func (q *Queries) GetBookingByID(ctx context.Context, id int64) (Booking, error) {
-	row := q.db.QueryRowContext(ctx, getBookingByID, id)
-	var i Booking
-	err := row.Scan(
-		&i.ID,
-		&i.CustomerName,
-		&i.Email,
-		&i.Phone,
-		&i.VehicleDetails,
-		&i.ServiceInterest,
-		&i.Notes,
-		&i.RequestedStart,
-		&i.RequestedEnd,
-		&i.Status,
-		&i.Source,
-		&i.InternalNotes,
-		&i.ClerkUserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type CountGalleryGroupsFilteredParams struct {
+	VehicleMake  sql.NullString `json:"vehicle_make"`
+	VehicleModel sql.NullString `json:"vehicle_model"`
+	YearMin      sql.NullInt64  `json:"year_min"`
+	YearMax      sql.NullInt64  `json:"year_max"`
+	FeaturedOnly bool           `json:"featured_only"`
+	PackageID    sql.NullInt64  `json:"package_id"`
 }
 
-const getGalleryGroupByID = `-- name: GetGalleryGroupByID :one
-SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at FROM gallery_groups
-WHERE id = ? LIMIT 1
-`
-
-func (q *Queries) GetGalleryGroupByID(ctx context.Context, id int64) (GalleryGroup, error) {
-	row := q.db.QueryRowContext(ctx, getGalleryGroupByID, id)
-	var i GalleryGroup
-	err := row.Scan(
-		&i.ID,
-		&i.Title,
-		&i.Slug,
-		&i.VehicleMake,
-		&i.VehicleModel,
-		&i.VehicleYear,
-		&i.Description,
-		&i.IsFeatured,
-		&i.SortOrder,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
-}
-
-const getGalleryGroupBySlug = `-- name: GetGalleryGroupBySlug :one
-SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at FROM gallery_groups
-WHERE slug = ? LIMIT 1
-`
-
-func (q *Queries) GetGalleryGroupBySlug(ctx context.Context, slug string) (GalleryGroup, error) {
-	row := q.db.QueryRowContext(ctx, getGalleryGroupBySlug, slug)
-	var i GalleryGroup
-	err := row.Scan(
-		&i.ID,
-		&i.Title,
-		&i.Slug,
-		&i.VehicleMake,
-		&i.VehicleModel,
-		&i.VehicleYear,
-		&i.Description,
-		&i.IsFeatured,
-		&i.SortOrder,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+func (q *Queries) CountGalleryGroupsFiltered(ctx context.Context, arg CountGalleryGroupsFilteredParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGalleryGroupsFiltered,
+		arg.VehicleMake,
+		arg.VehicleMake,
+		arg.VehicleModel,
+		arg.VehicleModel,
+		arg.YearMin,
+		arg.YearMin,
+		arg.YearMax,
+		arg.YearMax,
+		arg.FeaturedOnly,
+		arg.PackageID,
+		arg.PackageID,
 	)
-	return i, err
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-const getHeroImageForGalleryGroup = `-- name: GetHeroImageForGalleryGroup :one
-SELECT id, gallery_group_id, url, kind, sort_order, alt_text, created_at FROM media
-WHERE gallery_group_id = ? AND kind = 'hero'
-ORDER BY sort_order
-LIMIT 1
+const countInvoicesByStatus = `-- name: CountInvoicesByStatus :many
+SELECT status, COUNT(*) AS count
+FROM invoices
+GROUP BY status
 `
 
-func (q *Queries) GetHeroImageForGalleryGroup(ctx context.Context, galleryGroupID sql.NullInt64) (Medium, error) {
-	row := q.db.QueryRowContext(ctx, getHeroImageForGalleryGroup, galleryGroupID)
-	var i Medium
-	err := row.Scan(
-		&i.ID,
-		&i.GalleryGroupID,
-		&i.Url,
-		&i.Kind,
-		&i.SortOrder,
-		&i.AltText,
-		&i.CreatedAt,
-	)
-	return i, err
+type CountInvoicesByStatusRow struct {
+	Status sql.NullString `json:"status"`
+	Count  int64          `json:"count"`
 }
 
-const getMediaForGalleryGroup = `-- name: GetMediaForGalleryGroup :many
-
-SELECT id, gallery_group_id, url, kind, sort_order, alt_text, created_at FROM media
-WHERE gallery_group_id = ?
-ORDER BY sort_order, id
-`
-
-// Media queries
-func (q *Queries) GetMediaForGalleryGroup(ctx context.Context, galleryGroupID sql.NullInt64) ([]Medium, error) {
-	rows, err := q.db.QueryContext(ctx, getMediaForGalleryGroup, galleryGroupID)
+func (q *Queries) CountInvoicesByStatus(ctx context.Context) ([]CountInvoicesByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, countInvoicesByStatus)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Medium
+	var items []CountInvoicesByStatusRow
 	for rows.Next() {
-		var i Medium
-		if err := rows.Scan(
-			&i.ID,
-			&i.GalleryGroupID,
-			&i.Url,
-			&i.Kind,
-			&i.SortOrder,
-			&i.AltText,
-			&i.CreatedAt,
-		); err != nil {
+		var i CountInvoicesByStatusRow
+		if err := rows.Scan(&i.Status, &i.Count); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -579,86 +725,96 @@ func (q *Queries) GetMediaForGalleryGroup(ctx context.Context, galleryGroupID sq
 	return items, nil
 }
 
-const getPackageByID = `-- name: GetPackageByID :one
-SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at FROM packages
-WHERE id = ? LIMIT 1
+const countMedia = `-- name: CountMedia :one
+SELECT COUNT(*) FROM media
 `
 
-func (q *Queries) GetPackageByID(ctx context.Context, id int64) (Package, error) {
-	row := q.db.QueryRowContext(ctx, getPackageByID, id)
-	var i Package
-	err := row.Scan(
-		&i.ID,
-		&i.Slug,
-		&i.Name,
-		&i.ShortDesc,
-		&i.LongDesc,
-		&i.PriceMin,
-		&i.PriceMax,
-		&i.DurationEst,
-		&i.IsActive,
-		&i.SortOrder,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+func (q *Queries) CountMedia(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMedia)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-const getPackageBySlug = `-- name: GetPackageBySlug :one
-SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at FROM packages
-WHERE slug = ? LIMIT 1
+const countMembershipBookings = `-- name: CountMembershipBookings :one
+SELECT COUNT(*) FROM bookings
+WHERE membership_id = ?
+  AND requested_start >= ?
+  AND requested_start < ?
+  AND status NOT IN ('cancelled', 'declined')
 `
 
-func (q *Queries) GetPackageBySlug(ctx context.Context, slug string) (Package, error) {
-	row := q.db.QueryRowContext(ctx, getPackageBySlug, slug)
-	var i Package
-	err := row.Scan(
-		&i.ID,
-		&i.Slug,
-		&i.Name,
-		&i.ShortDesc,
-		&i.LongDesc,
-		&i.PriceMin,
-		&i.PriceMax,
-		&i.DurationEst,
-		&i.IsActive,
-		&i.SortOrder,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type CountMembershipBookingsParams struct {
+	MembershipID     sql.NullInt64 `json:"membership_id"`
+	RequestedStart   time.Time     `json:"requested_start"`
+	RequestedStart_2 time.Time     `json:"requested_start_2"`
 }
 
-const listBlockedSlots = `-- name: ListBlockedSlots :many
-SELECT requested_start, requested_end, status
-FROM bookings
-WHERE requested_start >= ?
-  AND requested_start < ?
-  AND status IN ('pending', 'confirmed')
-ORDER BY requested_start
+// Services used in a period. Cancelled and declined bookings give the
+// service back.
+func (q *Queries) CountMembershipBookings(ctx context.Context, arg CountMembershipBookingsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMembershipBookings, arg.MembershipID, arg.RequestedStart, arg.RequestedStart_2)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOpenFollowUpReviews = `-- name: CountOpenFollowUpReviews :one
+SELECT COUNT(*) FROM reviews WHERE follow_up_status = 'open'
 `
 
-type ListBlockedSlotsParams struct {
-	RequestedStart   time.Time `json:"requested_start"`
-	RequestedStart_2 time.Time `json:"requested_start_2"`
+func (q *Queries) CountOpenFollowUpReviews(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOpenFollowUpReviews)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-type ListBlockedSlotsRow struct {
-	RequestedStart time.Time      `json:"requested_start"`
-	RequestedEnd   time.Time      `json:"requested_end"`
-	Status         sql.NullString `json:"status"`
+const countPackages = `-- name: CountPackages :one
+SELECT COUNT(*) FROM packages
+`
+
+func (q *Queries) CountPackages(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPackages)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-func (q *Queries) ListBlockedSlots(ctx context.Context, arg ListBlockedSlotsParams) ([]ListBlockedSlotsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listBlockedSlots, arg.RequestedStart, arg.RequestedStart_2)
+const countPromoRedemptions = `-- name: CountPromoRedemptions :one
+SELECT COUNT(*) FROM promo_redemptions r
+LEFT JOIN bookings b ON b.id = r.booking_id
+WHERE r.promo_code_id = ? AND (b.id IS NULL OR b.status NOT IN ('cancelled', 'declined'))
+`
+
+func (q *Queries) CountPromoRedemptions(ctx context.Context, promoCodeID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPromoRedemptions, promoCodeID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countQuotesByStatus = `-- name: CountQuotesByStatus :many
+SELECT status, COUNT(*) AS count
+FROM quotes
+GROUP BY status
+`
+
+type CountQuotesByStatusRow struct {
+	Status sql.NullString `json:"status"`
+	Count  int64          `json:"count"`
+}
+
+func (q *Queries) CountQuotesByStatus(ctx context.Context) ([]CountQuotesByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, countQuotesByStatus)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListBlockedSlotsRow
+	var items []CountQuotesByStatusRow
 	for rows.Next() {
-		var i ListBlockedSlotsRow
-		if err := rows.Scan(&i.RequestedStart, &i.RequestedEnd, &i.Status); err != nil {
+		var i CountQuotesByStatusRow
+		if err := rows.Scan(&i.Status, &i.Count); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -672,45 +828,55 @@ func (q *Queries) ListBlockedSlots(ctx context.Context, arg ListBlockedSlotsPara
 	return items, nil
 }
 
-const listBookings = `-- name: ListBookings :many
+const countRecentContactMessages = `-- name: CountRecentContactMessages :one
+SELECT COUNT(*) FROM contact_messages
+WHERE ip_address = ? AND created_at >= ?
+`
 
-SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at FROM bookings
-ORDER BY requested_start DESC
-LIMIT ? OFFSET ?
+type CountRecentContactMessagesParams struct {
+	IpAddress sql.NullString `json:"ip_address"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+}
+
+// Messages accepted from one address since a time, for rate limiting
+func (q *Queries) CountRecentContactMessages(ctx context.Context, arg CountRecentContactMessagesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRecentContactMessages, arg.IpAddress, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countReviews = `-- name: CountReviews :one
+SELECT COUNT(*) FROM reviews
 `
 
-type ListBookingsParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+func (q *Queries) CountReviews(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countReviews)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-// Booking queries
-func (q *Queries) ListBookings(ctx context.Context, arg ListBookingsParams) ([]Booking, error) {
-	rows, err := q.db.QueryContext(ctx, listBookings, arg.Limit, arg.Offset)
+const countReviewsByStatus = `-- name: CountReviewsByStatus :many
+SELECT CAST(COALESCE(status, 'approved') AS TEXT) AS status, COUNT(*) AS count FROM reviews
+GROUP BY COALESCE(status, 'approved')
+`
+
+type CountReviewsByStatusRow struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+func (q *Queries) CountReviewsByStatus(ctx context.Context) ([]CountReviewsByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, countReviewsByStatus)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Booking
+	var items []CountReviewsByStatusRow
 	for rows.Next() {
-		var i Booking
-		if err := rows.Scan(
-			&i.ID,
-			&i.CustomerName,
-			&i.Email,
-			&i.Phone,
-			&i.VehicleDetails,
-			&i.ServiceInterest,
-			&i.Notes,
-			&i.RequestedStart,
-			&i.RequestedEnd,
-			&i.Status,
-			&i.Source,
-			&i.InternalNotes,
-			&i.ClerkUserID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
+		var i CountReviewsByStatusRow
+		if err := rows.Scan(&i.Status, &i.Count); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -724,42 +890,6601 @@ func (q *Queries) ListBookings(ctx context.Context, arg ListBookingsParams) ([]B
 	return items, nil
 }
 
-const listBookingsByStatus = `-- name: ListBookingsByStatus :many
-SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at FROM bookings
-WHERE status = ?
-ORDER BY requested_start ASC
-LIMIT ? OFFSET ?
+const countSeriesConflicts = `-- name: CountSeriesConflicts :one
+SELECT COUNT(*) FROM booking_series_occurrences o
+JOIN booking_series s ON s.id = o.series_id
+WHERE s.status = 'active' AND o.status = 'conflict' AND o.scheduled_at >= ?
 `
 
-type ListBookingsByStatusParams struct {
-	Status sql.NullString `json:"status"`
-	Limit  int64          `json:"limit"`
-	Offset int64          `json:"offset"`
+func (q *Queries) CountSeriesConflicts(ctx context.Context, scheduledAt time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSeriesConflicts, scheduledAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-func (q *Queries) ListBookingsByStatus(ctx context.Context, arg ListBookingsByStatusParams) ([]Booking, error) {
-	rows, err := q.db.QueryContext(ctx, listBookingsByStatus, arg.Status, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Booking
-	for rows.Next() {
-		var i Booking
-		if err := rows.Scan(
-			&i.ID,
-			&i.CustomerName,
-			&i.Email,
-			&i.Phone,
-			&i.VehicleDetails,
+const countSlotHoldsAt = `-- name: CountSlotHoldsAt :one
+SELECT COUNT(*) FROM slot_holds
+WHERE status = 'held' AND expires_at > ? AND slot_start = ? AND token != ?
+`
+
+type CountSlotHoldsAtParams struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	SlotStart time.Time `json:"slot_start"`
+	Token     string    `json:"token"`
+}
+
+func (q *Queries) CountSlotHoldsAt(ctx context.Context, arg CountSlotHoldsAtParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSlotHoldsAt, arg.ExpiresAt, arg.SlotStart, arg.Token)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countStaffBookingsOverlapping = `-- name: CountStaffBookingsOverlapping :one
+SELECT COUNT(*) FROM bookings
+WHERE staff_id = ?
+  AND requested_start < ?
+  AND requested_end > ?
+  AND status IN ('pending', 'confirmed', 'completed')
+`
+
+type CountStaffBookingsOverlappingParams struct {
+	StaffID        sql.NullInt64 `json:"staff_id"`
+	RequestedStart time.Time     `json:"requested_start"`
+	RequestedEnd   time.Time     `json:"requested_end"`
+}
+
+// Jobs assigned to a technician that overlap a stretch of time off
+func (q *Queries) CountStaffBookingsOverlapping(ctx context.Context, arg CountStaffBookingsOverlappingParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countStaffBookingsOverlapping, arg.StaffID, arg.RequestedStart, arg.RequestedEnd)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUnreadContactMessages = `-- name: CountUnreadContactMessages :one
+SELECT COUNT(*) FROM contact_messages WHERE is_read = 0
+`
+
+func (q *Queries) CountUnreadContactMessages(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUnreadContactMessages)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWaitlistEntries = `-- name: CountWaitlistEntries :one
+SELECT COUNT(*) FROM waitlist_entries WHERE status = 'waiting' AND date_to > ?
+`
+
+func (q *Queries) CountWaitlistEntries(ctx context.Context, dateTo time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWaitlistEntries, dateTo)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWaitlistHoldsAt = `-- name: CountWaitlistHoldsAt :one
+SELECT COUNT(*) FROM waitlist_offers
+WHERE status = 'open' AND expires_at > ? AND slot_start = ?
+`
+
+type CountWaitlistHoldsAtParams struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	SlotStart time.Time `json:"slot_start"`
+}
+
+func (q *Queries) CountWaitlistHoldsAt(ctx context.Context, arg CountWaitlistHoldsAtParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWaitlistHoldsAt, arg.ExpiresAt, arg.SlotStart)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAddon = `-- name: CreateAddon :one
+INSERT INTO addons (name, description, price, is_active, sort_order)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, name, description, price, is_active, sort_order, created_at, updated_at
+`
+
+type CreateAddonParams struct {
+	Name        string         `json:"name"`
+	Description sql.NullString `json:"description"`
+	Price       int64          `json:"price"`
+	IsActive    sql.NullBool   `json:"is_active"`
+	SortOrder   sql.NullInt64  `json:"sort_order"`
+}
+
+func (q *Queries) CreateAddon(ctx context.Context, arg CreateAddonParams) (Addon, error) {
+	row := q.db.QueryRowContext(ctx, createAddon,
+		arg.Name,
+		arg.Description,
+		arg.Price,
+		arg.IsActive,
+		arg.SortOrder,
+	)
+	var i Addon
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.IsActive,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createBooking = `-- name: CreateBooking :one
+INSERT INTO bookings (
+    customer_name,
+    email,
+    phone,
+    vehicle_details,
+    service_interest,
+    notes,
+    requested_start,
+    requested_end,
+    status,
+    source,
+    clerk_user_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id
+`
+
+type CreateBookingParams struct {
+	CustomerName    string         `json:"customer_name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	VehicleDetails  sql.NullString `json:"vehicle_details"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Notes           sql.NullString `json:"notes"`
+	RequestedStart  time.Time      `json:"requested_start"`
+	RequestedEnd    time.Time      `json:"requested_end"`
+	Status          sql.NullString `json:"status"`
+	Source          sql.NullString `json:"source"`
+	ClerkUserID     sql.NullString `json:"clerk_user_id"`
+}
+
+func (q *Queries) CreateBooking(ctx context.Context, arg CreateBookingParams) (Booking, error) {
+	row := q.db.QueryRowContext(ctx, createBooking,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.VehicleDetails,
+		arg.ServiceInterest,
+		arg.Notes,
+		arg.RequestedStart,
+		arg.RequestedEnd,
+		arg.Status,
+		arg.Source,
+		arg.ClerkUserID,
+	)
+	var i Booking
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.ServiceInterest,
+		&i.Notes,
+		&i.RequestedStart,
+		&i.RequestedEnd,
+		&i.Status,
+		&i.Source,
+		&i.InternalNotes,
+		&i.ClerkUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PromoCode,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.MembershipID,
+		&i.SeriesID,
+		&i.ServiceAddress,
+		&i.ServiceZip,
+		&i.ServiceLat,
+		&i.ServiceLng,
+		&i.TravelMinutes,
+		&i.TravelFee,
+		&i.StaffID,
+	)
+	return i, err
+}
+
+const createBookingSeries = `-- name: CreateBookingSeries :one
+
+INSERT INTO booking_series (
+    customer_name,
+    email,
+    phone,
+    vehicle_details,
+    service_interest,
+    notes,
+    rrule,
+    starts_at,
+    duration_minutes,
+    booked_through,
+    previous_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, customer_name, email, phone, vehicle_details, service_interest, notes, rrule, starts_at, duration_minutes, status, booked_through, previous_id, cancelled_at, created_at, updated_at
+`
+
+type CreateBookingSeriesParams struct {
+	CustomerName    string         `json:"customer_name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	VehicleDetails  sql.NullString `json:"vehicle_details"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Notes           sql.NullString `json:"notes"`
+	Rrule           string         `json:"rrule"`
+	StartsAt        time.Time      `json:"starts_at"`
+	DurationMinutes int64          `json:"duration_minutes"`
+	BookedThrough   time.Time      `json:"booked_through"`
+	PreviousID      sql.NullInt64  `json:"previous_id"`
+}
+
+// Repeat booking queries
+func (q *Queries) CreateBookingSeries(ctx context.Context, arg CreateBookingSeriesParams) (BookingSeries, error) {
+	row := q.db.QueryRowContext(ctx, createBookingSeries,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.VehicleDetails,
+		arg.ServiceInterest,
+		arg.Notes,
+		arg.Rrule,
+		arg.StartsAt,
+		arg.DurationMinutes,
+		arg.BookedThrough,
+		arg.PreviousID,
+	)
+	var i BookingSeries
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.ServiceInterest,
+		&i.Notes,
+		&i.Rrule,
+		&i.StartsAt,
+		&i.DurationMinutes,
+		&i.Status,
+		&i.BookedThrough,
+		&i.PreviousID,
+		&i.CancelledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createContactMessage = `-- name: CreateContactMessage :one
+
+INSERT INTO contact_messages (name, email, phone, service_interest, message, ip_address)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, name, email, phone, service_interest, message, ip_address, is_read, replied_at, booking_id, created_at
+`
+
+type CreateContactMessageParams struct {
+	Name            string         `json:"name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Message         string         `json:"message"`
+	IpAddress       sql.NullString `json:"ip_address"`
+}
+
+// Contact message queries
+func (q *Queries) CreateContactMessage(ctx context.Context, arg CreateContactMessageParams) (ContactMessage, error) {
+	row := q.db.QueryRowContext(ctx, createContactMessage,
+		arg.Name,
+		arg.Email,
+		arg.Phone,
+		arg.ServiceInterest,
+		arg.Message,
+		arg.IpAddress,
+	)
+	var i ContactMessage
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.ServiceInterest,
+		&i.Message,
+		&i.IpAddress,
+		&i.IsRead,
+		&i.RepliedAt,
+		&i.BookingID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createContactReply = `-- name: CreateContactReply :one
+INSERT INTO contact_replies (message_id, body, sent_by)
+VALUES (?, ?, ?)
+RETURNING id, message_id, body, sent_by, created_at
+`
+
+type CreateContactReplyParams struct {
+	MessageID int64          `json:"message_id"`
+	Body      string         `json:"body"`
+	SentBy    sql.NullString `json:"sent_by"`
+}
+
+func (q *Queries) CreateContactReply(ctx context.Context, arg CreateContactReplyParams) (ContactReply, error) {
+	row := q.db.QueryRowContext(ctx, createContactReply, arg.MessageID, arg.Body, arg.SentBy)
+	var i ContactReply
+	err := row.Scan(
+		&i.ID,
+		&i.MessageID,
+		&i.Body,
+		&i.SentBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createCustomerReview = `-- name: CreateCustomerReview :one
+INSERT INTO reviews (author, rating, body, source, is_featured, status, booking_id, gallery_group_id, follow_up_status)
+VALUES (?, ?, ?, 'customer', 0, 'pending', ?, ?, ?)
+RETURNING id, author, rating, body, source, is_featured, created_at, status, booking_id, gallery_group_id, follow_up_status, follow_up_notes, external_id
+`
+
+type CreateCustomerReviewParams struct {
+	Author         string         `json:"author"`
+	Rating         sql.NullInt64  `json:"rating"`
+	Body           sql.NullString `json:"body"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	GalleryGroupID sql.NullInt64  `json:"gallery_group_id"`
+	FollowUpStatus sql.NullString `json:"follow_up_status"`
+}
+
+func (q *Queries) CreateCustomerReview(ctx context.Context, arg CreateCustomerReviewParams) (Review, error) {
+	row := q.db.QueryRowContext(ctx, createCustomerReview,
+		arg.Author,
+		arg.Rating,
+		arg.Body,
+		arg.BookingID,
+		arg.GalleryGroupID,
+		arg.FollowUpStatus,
+	)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.Author,
+		&i.Rating,
+		&i.Body,
+		&i.Source,
+		&i.IsFeatured,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BookingID,
+		&i.GalleryGroupID,
+		&i.FollowUpStatus,
+		&i.FollowUpNotes,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const createGalleryGroup = `-- name: CreateGalleryGroup :one
+INSERT INTO gallery_groups (title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, package_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id
+`
+
+type CreateGalleryGroupParams struct {
+	Title        string         `json:"title"`
+	Slug         string         `json:"slug"`
+	VehicleMake  sql.NullString `json:"vehicle_make"`
+	VehicleModel sql.NullString `json:"vehicle_model"`
+	VehicleYear  sql.NullInt64  `json:"vehicle_year"`
+	Description  sql.NullString `json:"description"`
+	IsFeatured   sql.NullBool   `json:"is_featured"`
+	SortOrder    sql.NullInt64  `json:"sort_order"`
+	PackageID    sql.NullInt64  `json:"package_id"`
+}
+
+func (q *Queries) CreateGalleryGroup(ctx context.Context, arg CreateGalleryGroupParams) (GalleryGroup, error) {
+	row := q.db.QueryRowContext(ctx, createGalleryGroup,
+		arg.Title,
+		arg.Slug,
+		arg.VehicleMake,
+		arg.VehicleModel,
+		arg.VehicleYear,
+		arg.Description,
+		arg.IsFeatured,
+		arg.SortOrder,
+		arg.PackageID,
+	)
+	var i GalleryGroup
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.VehicleMake,
+		&i.VehicleModel,
+		&i.VehicleYear,
+		&i.Description,
+		&i.IsFeatured,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PackageID,
+	)
+	return i, err
+}
+
+const createGiftCertificate = `-- name: CreateGiftCertificate :one
+
+INSERT INTO gift_certificates (
+    code,
+    initial_value,
+    balance,
+    status,
+    source,
+    purchaser_name,
+    purchaser_email,
+    recipient_name,
+    recipient_email,
+    message,
+    expires_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, code, initial_value, balance, status, source, purchaser_name, purchaser_email, recipient_name, recipient_email, message, payment_id, expires_at, created_at, updated_at
+`
+
+type CreateGiftCertificateParams struct {
+	Code           string         `json:"code"`
+	InitialValue   int64          `json:"initial_value"`
+	Balance        int64          `json:"balance"`
+	Status         string         `json:"status"`
+	Source         string         `json:"source"`
+	PurchaserName  sql.NullString `json:"purchaser_name"`
+	PurchaserEmail sql.NullString `json:"purchaser_email"`
+	RecipientName  sql.NullString `json:"recipient_name"`
+	RecipientEmail sql.NullString `json:"recipient_email"`
+	Message        sql.NullString `json:"message"`
+	ExpiresAt      sql.NullTime   `json:"expires_at"`
+}
+
+// Gift certificate queries
+func (q *Queries) CreateGiftCertificate(ctx context.Context, arg CreateGiftCertificateParams) (GiftCertificate, error) {
+	row := q.db.QueryRowContext(ctx, createGiftCertificate,
+		arg.Code,
+		arg.InitialValue,
+		arg.Balance,
+		arg.Status,
+		arg.Source,
+		arg.PurchaserName,
+		arg.PurchaserEmail,
+		arg.RecipientName,
+		arg.RecipientEmail,
+		arg.Message,
+		arg.ExpiresAt,
+	)
+	var i GiftCertificate
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.InitialValue,
+		&i.Balance,
+		&i.Status,
+		&i.Source,
+		&i.PurchaserName,
+		&i.PurchaserEmail,
+		&i.RecipientName,
+		&i.RecipientEmail,
+		&i.Message,
+		&i.PaymentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createGiftCertificateTransaction = `-- name: CreateGiftCertificateTransaction :exec
+INSERT INTO gift_certificate_transactions (gift_certificate_id, kind, amount, balance_after, invoice_id, note)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateGiftCertificateTransactionParams struct {
+	GiftCertificateID int64          `json:"gift_certificate_id"`
+	Kind              string         `json:"kind"`
+	Amount            int64          `json:"amount"`
+	BalanceAfter      int64          `json:"balance_after"`
+	InvoiceID         sql.NullInt64  `json:"invoice_id"`
+	Note              sql.NullString `json:"note"`
+}
+
+func (q *Queries) CreateGiftCertificateTransaction(ctx context.Context, arg CreateGiftCertificateTransactionParams) error {
+	_, err := q.db.ExecContext(ctx, createGiftCertificateTransaction,
+		arg.GiftCertificateID,
+		arg.Kind,
+		arg.Amount,
+		arg.BalanceAfter,
+		arg.InvoiceID,
+		arg.Note,
+	)
+	return err
+}
+
+const createImportedReview = `-- name: CreateImportedReview :one
+INSERT INTO reviews (author, rating, body, source, is_featured, status, external_id, created_at)
+VALUES (?, ?, ?, ?, 0, 'approved', ?, ?)
+RETURNING id, author, rating, body, source, is_featured, created_at, status, booking_id, gallery_group_id, follow_up_status, follow_up_notes, external_id
+`
+
+type CreateImportedReviewParams struct {
+	Author     string         `json:"author"`
+	Rating     sql.NullInt64  `json:"rating"`
+	Body       sql.NullString `json:"body"`
+	Source     sql.NullString `json:"source"`
+	ExternalID sql.NullString `json:"external_id"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+}
+
+// Imported reviews keep the platform's date and are already public there
+func (q *Queries) CreateImportedReview(ctx context.Context, arg CreateImportedReviewParams) (Review, error) {
+	row := q.db.QueryRowContext(ctx, createImportedReview,
+		arg.Author,
+		arg.Rating,
+		arg.Body,
+		arg.Source,
+		arg.ExternalID,
+		arg.CreatedAt,
+	)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.Author,
+		&i.Rating,
+		&i.Body,
+		&i.Source,
+		&i.IsFeatured,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BookingID,
+		&i.GalleryGroupID,
+		&i.FollowUpStatus,
+		&i.FollowUpNotes,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const createInvoice = `-- name: CreateInvoice :one
+
+INSERT INTO invoices (booking_id, customer_name, email, phone, vehicle_details, tax_name, tax_rate, discount_type, discount_value, due_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, number, booking_id, customer_name, email, phone, vehicle_details, status, tax_name, tax_rate, discount_type, discount_value, notes, issued_at, due_at, sent_at, paid_at, payment_method, voided_at, created_at, updated_at
+`
+
+type CreateInvoiceParams struct {
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	CustomerName   string         `json:"customer_name"`
+	Email          string         `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	VehicleDetails sql.NullString `json:"vehicle_details"`
+	TaxName        sql.NullString `json:"tax_name"`
+	TaxRate        int64          `json:"tax_rate"`
+	DiscountType   sql.NullString `json:"discount_type"`
+	DiscountValue  int64          `json:"discount_value"`
+	DueAt          sql.NullTime   `json:"due_at"`
+}
+
+// Invoice queries
+func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, createInvoice,
+		arg.BookingID,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.VehicleDetails,
+		arg.TaxName,
+		arg.TaxRate,
+		arg.DiscountType,
+		arg.DiscountValue,
+		arg.DueAt,
+	)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.BookingID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.Status,
+		&i.TaxName,
+		&i.TaxRate,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.Notes,
+		&i.IssuedAt,
+		&i.DueAt,
+		&i.SentAt,
+		&i.PaidAt,
+		&i.PaymentMethod,
+		&i.VoidedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createInvoiceItem = `-- name: CreateInvoiceItem :one
+INSERT INTO invoice_items (invoice_id, kind, package_id, addon_id, description, quantity, unit_price)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, invoice_id, kind, package_id, addon_id, description, quantity, unit_price, created_at
+`
+
+type CreateInvoiceItemParams struct {
+	InvoiceID   int64         `json:"invoice_id"`
+	Kind        string        `json:"kind"`
+	PackageID   sql.NullInt64 `json:"package_id"`
+	AddonID     sql.NullInt64 `json:"addon_id"`
+	Description string        `json:"description"`
+	Quantity    int64         `json:"quantity"`
+	UnitPrice   int64         `json:"unit_price"`
+}
+
+func (q *Queries) CreateInvoiceItem(ctx context.Context, arg CreateInvoiceItemParams) (InvoiceItem, error) {
+	row := q.db.QueryRowContext(ctx, createInvoiceItem,
+		arg.InvoiceID,
+		arg.Kind,
+		arg.PackageID,
+		arg.AddonID,
+		arg.Description,
+		arg.Quantity,
+		arg.UnitPrice,
+	)
+	var i InvoiceItem
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.Kind,
+		&i.PackageID,
+		&i.AddonID,
+		&i.Description,
+		&i.Quantity,
+		&i.UnitPrice,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createMedia = `-- name: CreateMedia :one
+INSERT INTO media (gallery_group_id, url, kind, sort_order, alt_text, is_private)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, gallery_group_id, url, kind, sort_order, alt_text, is_private, pair_id, created_at
+`
+
+type CreateMediaParams struct {
+	GalleryGroupID sql.NullInt64  `json:"gallery_group_id"`
+	Url            string         `json:"url"`
+	Kind           sql.NullString `json:"kind"`
+	SortOrder      sql.NullInt64  `json:"sort_order"`
+	AltText        sql.NullString `json:"alt_text"`
+	IsPrivate      sql.NullBool   `json:"is_private"`
+}
+
+func (q *Queries) CreateMedia(ctx context.Context, arg CreateMediaParams) (Medium, error) {
+	row := q.db.QueryRowContext(ctx, createMedia,
+		arg.GalleryGroupID,
+		arg.Url,
+		arg.Kind,
+		arg.SortOrder,
+		arg.AltText,
+		arg.IsPrivate,
+	)
+	var i Medium
+	err := row.Scan(
+		&i.ID,
+		&i.GalleryGroupID,
+		&i.Url,
+		&i.Kind,
+		&i.SortOrder,
+		&i.AltText,
+		&i.IsPrivate,
+		&i.PairID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createMediaVariant = `-- name: CreateMediaVariant :one
+INSERT INTO media_variants (media_id, width, height, url)
+VALUES (?, ?, ?, ?)
+RETURNING id, media_id, width, height, url, created_at
+`
+
+type CreateMediaVariantParams struct {
+	MediaID int64  `json:"media_id"`
+	Width   int64  `json:"width"`
+	Height  int64  `json:"height"`
+	Url     string `json:"url"`
+}
+
+func (q *Queries) CreateMediaVariant(ctx context.Context, arg CreateMediaVariantParams) (MediaVariant, error) {
+	row := q.db.QueryRowContext(ctx, createMediaVariant,
+		arg.MediaID,
+		arg.Width,
+		arg.Height,
+		arg.Url,
+	)
+	var i MediaVariant
+	err := row.Scan(
+		&i.ID,
+		&i.MediaID,
+		&i.Width,
+		&i.Height,
+		&i.Url,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createMembership = `-- name: CreateMembership :one
+INSERT INTO memberships (plan_id, customer_name, email, phone, started_at, paid_through, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, plan_id, customer_name, email, phone, clerk_user_id, status, started_at, paid_through, cancelled_at, cancel_reason, notes, created_at, updated_at
+`
+
+type CreateMembershipParams struct {
+	PlanID       int64          `json:"plan_id"`
+	CustomerName string         `json:"customer_name"`
+	Email        string         `json:"email"`
+	Phone        sql.NullString `json:"phone"`
+	StartedAt    time.Time      `json:"started_at"`
+	PaidThrough  time.Time      `json:"paid_through"`
+	Notes        sql.NullString `json:"notes"`
+}
+
+func (q *Queries) CreateMembership(ctx context.Context, arg CreateMembershipParams) (Membership, error) {
+	row := q.db.QueryRowContext(ctx, createMembership,
+		arg.PlanID,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.StartedAt,
+		arg.PaidThrough,
+		arg.Notes,
+	)
+	var i Membership
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.ClerkUserID,
+		&i.Status,
+		&i.StartedAt,
+		&i.PaidThrough,
+		&i.CancelledAt,
+		&i.CancelReason,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createMembershipPlan = `-- name: CreateMembershipPlan :one
+INSERT INTO membership_plans (name, description, package_id, services_per_period, period_months, price, is_active)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, name, description, package_id, services_per_period, period_months, price, is_active, created_at, updated_at
+`
+
+type CreateMembershipPlanParams struct {
+	Name              string         `json:"name"`
+	Description       sql.NullString `json:"description"`
+	PackageID         sql.NullInt64  `json:"package_id"`
+	ServicesPerPeriod int64          `json:"services_per_period"`
+	PeriodMonths      int64          `json:"period_months"`
+	Price             int64          `json:"price"`
+	IsActive          sql.NullBool   `json:"is_active"`
+}
+
+func (q *Queries) CreateMembershipPlan(ctx context.Context, arg CreateMembershipPlanParams) (MembershipPlan, error) {
+	row := q.db.QueryRowContext(ctx, createMembershipPlan,
+		arg.Name,
+		arg.Description,
+		arg.PackageID,
+		arg.ServicesPerPeriod,
+		arg.PeriodMonths,
+		arg.Price,
+		arg.IsActive,
+	)
+	var i MembershipPlan
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.PackageID,
+		&i.ServicesPerPeriod,
+		&i.PeriodMonths,
+		&i.Price,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createMembershipRenewal = `-- name: CreateMembershipRenewal :exec
+INSERT INTO membership_renewals (membership_id, period_start, period_end, amount, payment_method)
+VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateMembershipRenewalParams struct {
+	MembershipID  int64          `json:"membership_id"`
+	PeriodStart   time.Time      `json:"period_start"`
+	PeriodEnd     time.Time      `json:"period_end"`
+	Amount        int64          `json:"amount"`
+	PaymentMethod sql.NullString `json:"payment_method"`
+}
+
+func (q *Queries) CreateMembershipRenewal(ctx context.Context, arg CreateMembershipRenewalParams) error {
+	_, err := q.db.ExecContext(ctx, createMembershipRenewal,
+		arg.MembershipID,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.Amount,
+		arg.PaymentMethod,
+	)
+	return err
+}
+
+const createPackage = `-- name: CreatePackage :one
+INSERT INTO packages (slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, features, included, excluded, required_skills)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at, features, included, excluded, required_skills
+`
+
+type CreatePackageParams struct {
+	Slug           string         `json:"slug"`
+	Name           string         `json:"name"`
+	ShortDesc      sql.NullString `json:"short_desc"`
+	LongDesc       sql.NullString `json:"long_desc"`
+	PriceMin       sql.NullInt64  `json:"price_min"`
+	PriceMax       sql.NullInt64  `json:"price_max"`
+	DurationEst    sql.NullInt64  `json:"duration_est"`
+	IsActive       sql.NullBool   `json:"is_active"`
+	SortOrder      sql.NullInt64  `json:"sort_order"`
+	Features       sql.NullString `json:"features"`
+	Included       sql.NullString `json:"included"`
+	Excluded       sql.NullString `json:"excluded"`
+	RequiredSkills sql.NullString `json:"required_skills"`
+}
+
+func (q *Queries) CreatePackage(ctx context.Context, arg CreatePackageParams) (Package, error) {
+	row := q.db.QueryRowContext(ctx, createPackage,
+		arg.Slug,
+		arg.Name,
+		arg.ShortDesc,
+		arg.LongDesc,
+		arg.PriceMin,
+		arg.PriceMax,
+		arg.DurationEst,
+		arg.IsActive,
+		arg.SortOrder,
+		arg.Features,
+		arg.Included,
+		arg.Excluded,
+		arg.RequiredSkills,
+	)
+	var i Package
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.ShortDesc,
+		&i.LongDesc,
+		&i.PriceMin,
+		&i.PriceMax,
+		&i.DurationEst,
+		&i.IsActive,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Features,
+		&i.Included,
+		&i.Excluded,
+		&i.RequiredSkills,
+	)
+	return i, err
+}
+
+const createPackageFAQ = `-- name: CreatePackageFAQ :one
+INSERT INTO package_faqs (package_id, question, answer, sort_order)
+VALUES (?, ?, ?, ?)
+RETURNING id, package_id, question, answer, sort_order, created_at
+`
+
+type CreatePackageFAQParams struct {
+	PackageID int64         `json:"package_id"`
+	Question  string        `json:"question"`
+	Answer    string        `json:"answer"`
+	SortOrder sql.NullInt64 `json:"sort_order"`
+}
+
+func (q *Queries) CreatePackageFAQ(ctx context.Context, arg CreatePackageFAQParams) (PackageFaq, error) {
+	row := q.db.QueryRowContext(ctx, createPackageFAQ,
+		arg.PackageID,
+		arg.Question,
+		arg.Answer,
+		arg.SortOrder,
+	)
+	var i PackageFaq
+	err := row.Scan(
+		&i.ID,
+		&i.PackageID,
+		&i.Question,
+		&i.Answer,
+		&i.SortOrder,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPayment = `-- name: CreatePayment :one
+
+INSERT INTO payments (booking_id, kind, provider, amount, currency)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, booking_id, kind, provider, checkout_id, payment_ref, amount, currency, status, refund_ref, refunded_amount, paid_at, refunded_at, created_at, updated_at
+`
+
+type CreatePaymentParams struct {
+	BookingID sql.NullInt64 `json:"booking_id"`
+	Kind      string        `json:"kind"`
+	Provider  string        `json:"provider"`
+	Amount    int64         `json:"amount"`
+	Currency  string        `json:"currency"`
+}
+
+// Payment queries
+func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, createPayment,
+		arg.BookingID,
+		arg.Kind,
+		arg.Provider,
+		arg.Amount,
+		arg.Currency,
+	)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.Kind,
+		&i.Provider,
+		&i.CheckoutID,
+		&i.PaymentRef,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.RefundRef,
+		&i.RefundedAmount,
+		&i.PaidAt,
+		&i.RefundedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createPromoCode = `-- name: CreatePromoCode :one
+INSERT INTO promo_codes (code, description, discount_type, discount_value, starts_at, ends_at, max_uses, max_uses_per_customer, min_spend, is_active)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, code, description, discount_type, discount_value, starts_at, ends_at, max_uses, max_uses_per_customer, min_spend, is_active, created_at, updated_at
+`
+
+type CreatePromoCodeParams struct {
+	Code               string         `json:"code"`
+	Description        sql.NullString `json:"description"`
+	DiscountType       string         `json:"discount_type"`
+	DiscountValue      int64          `json:"discount_value"`
+	StartsAt           sql.NullTime   `json:"starts_at"`
+	EndsAt             sql.NullTime   `json:"ends_at"`
+	MaxUses            int64          `json:"max_uses"`
+	MaxUsesPerCustomer int64          `json:"max_uses_per_customer"`
+	MinSpend           int64          `json:"min_spend"`
+	IsActive           sql.NullBool   `json:"is_active"`
+}
+
+func (q *Queries) CreatePromoCode(ctx context.Context, arg CreatePromoCodeParams) (PromoCode, error) {
+	row := q.db.QueryRowContext(ctx, createPromoCode,
+		arg.Code,
+		arg.Description,
+		arg.DiscountType,
+		arg.DiscountValue,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.MaxUses,
+		arg.MaxUsesPerCustomer,
+		arg.MinSpend,
+		arg.IsActive,
+	)
+	var i PromoCode
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.MaxUses,
+		&i.MaxUsesPerCustomer,
+		&i.MinSpend,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createPromoRedemption = `-- name: CreatePromoRedemption :exec
+INSERT INTO promo_redemptions (promo_code_id, booking_id, quote_id, email)
+VALUES (?, ?, ?, ?)
+`
+
+type CreatePromoRedemptionParams struct {
+	PromoCodeID int64         `json:"promo_code_id"`
+	BookingID   sql.NullInt64 `json:"booking_id"`
+	QuoteID     sql.NullInt64 `json:"quote_id"`
+	Email       string        `json:"email"`
+}
+
+func (q *Queries) CreatePromoRedemption(ctx context.Context, arg CreatePromoRedemptionParams) error {
+	_, err := q.db.ExecContext(ctx, createPromoRedemption,
+		arg.PromoCodeID,
+		arg.BookingID,
+		arg.QuoteID,
+		arg.Email,
+	)
+	return err
+}
+
+const createQuote = `-- name: CreateQuote :one
+
+INSERT INTO quotes (customer_name, email, phone, vehicle_details, notes, expires_at, contact_message_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, customer_name, email, phone, vehicle_details, notes, status, version, expires_at, sent_at, accepted_at, booking_id, contact_message_id, created_at, updated_at, promo_code_id, promo_code, discount_type, discount_value
+`
+
+type CreateQuoteParams struct {
+	CustomerName     string         `json:"customer_name"`
+	Email            string         `json:"email"`
+	Phone            sql.NullString `json:"phone"`
+	VehicleDetails   sql.NullString `json:"vehicle_details"`
+	Notes            sql.NullString `json:"notes"`
+	ExpiresAt        sql.NullTime   `json:"expires_at"`
+	ContactMessageID sql.NullInt64  `json:"contact_message_id"`
+}
+
+// Quote queries
+func (q *Queries) CreateQuote(ctx context.Context, arg CreateQuoteParams) (Quote, error) {
+	row := q.db.QueryRowContext(ctx, createQuote,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.VehicleDetails,
+		arg.Notes,
+		arg.ExpiresAt,
+		arg.ContactMessageID,
+	)
+	var i Quote
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.Notes,
+		&i.Status,
+		&i.Version,
+		&i.ExpiresAt,
+		&i.SentAt,
+		&i.AcceptedAt,
+		&i.BookingID,
+		&i.ContactMessageID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PromoCodeID,
+		&i.PromoCode,
+		&i.DiscountType,
+		&i.DiscountValue,
+	)
+	return i, err
+}
+
+const createQuoteItem = `-- name: CreateQuoteItem :one
+INSERT INTO quote_items (quote_id, kind, package_id, addon_id, description, quantity, unit_price)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, quote_id, kind, package_id, addon_id, description, quantity, unit_price, created_at
+`
+
+type CreateQuoteItemParams struct {
+	QuoteID     int64         `json:"quote_id"`
+	Kind        string        `json:"kind"`
+	PackageID   sql.NullInt64 `json:"package_id"`
+	AddonID     sql.NullInt64 `json:"addon_id"`
+	Description string        `json:"description"`
+	Quantity    int64         `json:"quantity"`
+	UnitPrice   int64         `json:"unit_price"`
+}
+
+func (q *Queries) CreateQuoteItem(ctx context.Context, arg CreateQuoteItemParams) (QuoteItem, error) {
+	row := q.db.QueryRowContext(ctx, createQuoteItem,
+		arg.QuoteID,
+		arg.Kind,
+		arg.PackageID,
+		arg.AddonID,
+		arg.Description,
+		arg.Quantity,
+		arg.UnitPrice,
+	)
+	var i QuoteItem
+	err := row.Scan(
+		&i.ID,
+		&i.QuoteID,
+		&i.Kind,
+		&i.PackageID,
+		&i.AddonID,
+		&i.Description,
+		&i.Quantity,
+		&i.UnitPrice,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createQuoteVersion = `-- name: CreateQuoteVersion :one
+INSERT INTO quote_versions (quote_id, version, items, total, expires_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, quote_id, version, items, total, expires_at, created_at
+`
+
+type CreateQuoteVersionParams struct {
+	QuoteID   int64        `json:"quote_id"`
+	Version   int64        `json:"version"`
+	Items     string       `json:"items"`
+	Total     int64        `json:"total"`
+	ExpiresAt sql.NullTime `json:"expires_at"`
+}
+
+func (q *Queries) CreateQuoteVersion(ctx context.Context, arg CreateQuoteVersionParams) (QuoteVersion, error) {
+	row := q.db.QueryRowContext(ctx, createQuoteVersion,
+		arg.QuoteID,
+		arg.Version,
+		arg.Items,
+		arg.Total,
+		arg.ExpiresAt,
+	)
+	var i QuoteVersion
+	err := row.Scan(
+		&i.ID,
+		&i.QuoteID,
+		&i.Version,
+		&i.Items,
+		&i.Total,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createReview = `-- name: CreateReview :one
+INSERT INTO reviews (author, rating, body, source, is_featured, status)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, author, rating, body, source, is_featured, created_at, status, booking_id, gallery_group_id, follow_up_status, follow_up_notes, external_id
+`
+
+type CreateReviewParams struct {
+	Author     string         `json:"author"`
+	Rating     sql.NullInt64  `json:"rating"`
+	Body       sql.NullString `json:"body"`
+	Source     sql.NullString `json:"source"`
+	IsFeatured sql.NullBool   `json:"is_featured"`
+	Status     sql.NullString `json:"status"`
+}
+
+func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) (Review, error) {
+	row := q.db.QueryRowContext(ctx, createReview,
+		arg.Author,
+		arg.Rating,
+		arg.Body,
+		arg.Source,
+		arg.IsFeatured,
+		arg.Status,
+	)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.Author,
+		&i.Rating,
+		&i.Body,
+		&i.Source,
+		&i.IsFeatured,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BookingID,
+		&i.GalleryGroupID,
+		&i.FollowUpStatus,
+		&i.FollowUpNotes,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const createReviewRequest = `-- name: CreateReviewRequest :one
+
+INSERT INTO review_requests (booking_id, gallery_group_id, email, expires_at)
+VALUES (?, ?, ?, ?)
+RETURNING id, booking_id, gallery_group_id, email, expires_at, sent_at, used_at, created_at
+`
+
+type CreateReviewRequestParams struct {
+	BookingID      int64         `json:"booking_id"`
+	GalleryGroupID sql.NullInt64 `json:"gallery_group_id"`
+	Email          string        `json:"email"`
+	ExpiresAt      time.Time     `json:"expires_at"`
+}
+
+// Review request queries
+func (q *Queries) CreateReviewRequest(ctx context.Context, arg CreateReviewRequestParams) (ReviewRequest, error) {
+	row := q.db.QueryRowContext(ctx, createReviewRequest,
+		arg.BookingID,
+		arg.GalleryGroupID,
+		arg.Email,
+		arg.ExpiresAt,
+	)
+	var i ReviewRequest
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.GalleryGroupID,
+		&i.Email,
+		&i.ExpiresAt,
+		&i.SentAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSeriesOccurrence = `-- name: CreateSeriesOccurrence :exec
+INSERT INTO booking_series_occurrences (series_id, scheduled_at, booking_id, status)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateSeriesOccurrenceParams struct {
+	SeriesID    int64         `json:"series_id"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	BookingID   sql.NullInt64 `json:"booking_id"`
+	Status      string        `json:"status"`
+}
+
+func (q *Queries) CreateSeriesOccurrence(ctx context.Context, arg CreateSeriesOccurrenceParams) error {
+	_, err := q.db.ExecContext(ctx, createSeriesOccurrence,
+		arg.SeriesID,
+		arg.ScheduledAt,
+		arg.BookingID,
+		arg.Status,
+	)
+	return err
+}
+
+const createSlotHold = `-- name: CreateSlotHold :one
+
+INSERT INTO slot_holds (token, slot_start, slot_end, expires_at, ip_address)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, token, slot_start, slot_end, expires_at, ip_address, status, booking_id, created_at, updated_at
+`
+
+type CreateSlotHoldParams struct {
+	Token     string         `json:"token"`
+	SlotStart time.Time      `json:"slot_start"`
+	SlotEnd   time.Time      `json:"slot_end"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	IpAddress sql.NullString `json:"ip_address"`
+}
+
+// Slot hold queries
+func (q *Queries) CreateSlotHold(ctx context.Context, arg CreateSlotHoldParams) (SlotHold, error) {
+	row := q.db.QueryRowContext(ctx, createSlotHold,
+		arg.Token,
+		arg.SlotStart,
+		arg.SlotEnd,
+		arg.ExpiresAt,
+		arg.IpAddress,
+	)
+	var i SlotHold
+	err := row.Scan(
+		&i.ID,
+		&i.Token,
+		&i.SlotStart,
+		&i.SlotEnd,
+		&i.ExpiresAt,
+		&i.IpAddress,
+		&i.Status,
+		&i.BookingID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createStaff = `-- name: CreateStaff :one
+INSERT INTO staff (name, email, phone, role, skills, is_active)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, name, email, phone, role, skills, is_active, created_at, updated_at
+`
+
+type CreateStaffParams struct {
+	Name     string         `json:"name"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+	Role     string         `json:"role"`
+	Skills   sql.NullString `json:"skills"`
+	IsActive sql.NullBool   `json:"is_active"`
+}
+
+func (q *Queries) CreateStaff(ctx context.Context, arg CreateStaffParams) (Staff, error) {
+	row := q.db.QueryRowContext(ctx, createStaff,
+		arg.Name,
+		arg.Email,
+		arg.Phone,
+		arg.Role,
+		arg.Skills,
+		arg.IsActive,
+	)
+	var i Staff
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Role,
+		&i.Skills,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createStaffHours = `-- name: CreateStaffHours :exec
+INSERT INTO staff_hours (staff_id, weekday, start_minute, end_minute)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateStaffHoursParams struct {
+	StaffID     int64 `json:"staff_id"`
+	Weekday     int64 `json:"weekday"`
+	StartMinute int64 `json:"start_minute"`
+	EndMinute   int64 `json:"end_minute"`
+}
+
+func (q *Queries) CreateStaffHours(ctx context.Context, arg CreateStaffHoursParams) error {
+	_, err := q.db.ExecContext(ctx, createStaffHours,
+		arg.StaffID,
+		arg.Weekday,
+		arg.StartMinute,
+		arg.EndMinute,
+	)
+	return err
+}
+
+const createStaffTimeOff = `-- name: CreateStaffTimeOff :one
+INSERT INTO staff_time_off (staff_id, starts_at, ends_at, reason)
+VALUES (?, ?, ?, ?)
+RETURNING id, staff_id, starts_at, ends_at, reason, created_at
+`
+
+type CreateStaffTimeOffParams struct {
+	StaffID  int64          `json:"staff_id"`
+	StartsAt time.Time      `json:"starts_at"`
+	EndsAt   time.Time      `json:"ends_at"`
+	Reason   sql.NullString `json:"reason"`
+}
+
+func (q *Queries) CreateStaffTimeOff(ctx context.Context, arg CreateStaffTimeOffParams) (StaffTimeOff, error) {
+	row := q.db.QueryRowContext(ctx, createStaffTimeOff,
+		arg.StaffID,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Reason,
+	)
+	var i StaffTimeOff
+	err := row.Scan(
+		&i.ID,
+		&i.StaffID,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createTaxRate = `-- name: CreateTaxRate :one
+INSERT INTO tax_rates (name, rate, is_default, is_active)
+VALUES (?, ?, ?, ?)
+RETURNING id, name, rate, is_default, is_active, created_at
+`
+
+type CreateTaxRateParams struct {
+	Name      string       `json:"name"`
+	Rate      int64        `json:"rate"`
+	IsDefault sql.NullBool `json:"is_default"`
+	IsActive  sql.NullBool `json:"is_active"`
+}
+
+func (q *Queries) CreateTaxRate(ctx context.Context, arg CreateTaxRateParams) (TaxRate, error) {
+	row := q.db.QueryRowContext(ctx, createTaxRate,
+		arg.Name,
+		arg.Rate,
+		arg.IsDefault,
+		arg.IsActive,
+	)
+	var i TaxRate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Rate,
+		&i.IsDefault,
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWaitlistEntry = `-- name: CreateWaitlistEntry :one
+
+INSERT INTO waitlist_entries (
+    customer_name,
+    email,
+    phone,
+    vehicle_details,
+    service_interest,
+    notes,
+    date_from,
+    date_to,
+    slot_id,
+    clerk_user_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, customer_name, email, phone, vehicle_details, service_interest, notes, date_from, date_to, slot_id, clerk_user_id, status, booking_id, created_at, updated_at
+`
+
+type CreateWaitlistEntryParams struct {
+	CustomerName    string         `json:"customer_name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	VehicleDetails  sql.NullString `json:"vehicle_details"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Notes           sql.NullString `json:"notes"`
+	DateFrom        time.Time      `json:"date_from"`
+	DateTo          time.Time      `json:"date_to"`
+	SlotID          sql.NullString `json:"slot_id"`
+	ClerkUserID     sql.NullString `json:"clerk_user_id"`
+}
+
+// Waitlist queries
+func (q *Queries) CreateWaitlistEntry(ctx context.Context, arg CreateWaitlistEntryParams) (WaitlistEntry, error) {
+	row := q.db.QueryRowContext(ctx, createWaitlistEntry,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.VehicleDetails,
+		arg.ServiceInterest,
+		arg.Notes,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.SlotID,
+		arg.ClerkUserID,
+	)
+	var i WaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.ServiceInterest,
+		&i.Notes,
+		&i.DateFrom,
+		&i.DateTo,
+		&i.SlotID,
+		&i.ClerkUserID,
+		&i.Status,
+		&i.BookingID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createWaitlistOffer = `-- name: CreateWaitlistOffer :one
+INSERT INTO waitlist_offers (entry_id, slot_start, slot_end, expires_at)
+VALUES (?, ?, ?, ?)
+RETURNING id, entry_id, slot_start, slot_end, expires_at, status, booking_id, created_at, updated_at
+`
+
+type CreateWaitlistOfferParams struct {
+	EntryID   int64     `json:"entry_id"`
+	SlotStart time.Time `json:"slot_start"`
+	SlotEnd   time.Time `json:"slot_end"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateWaitlistOffer(ctx context.Context, arg CreateWaitlistOfferParams) (WaitlistOffer, error) {
+	row := q.db.QueryRowContext(ctx, createWaitlistOffer,
+		arg.EntryID,
+		arg.SlotStart,
+		arg.SlotEnd,
+		arg.ExpiresAt,
+	)
+	var i WaitlistOffer
+	err := row.Scan(
+		&i.ID,
+		&i.EntryID,
+		&i.SlotStart,
+		&i.SlotEnd,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.BookingID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const declineQuote = `-- name: DeclineQuote :execrows
+UPDATE quotes
+SET status = 'declined', updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'sent'
+`
+
+func (q *Queries) DeclineQuote(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, declineQuote, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteAddon = `-- name: DeleteAddon :exec
+DELETE FROM addons WHERE id = ?
+`
+
+func (q *Queries) DeleteAddon(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteAddon, id)
+	return err
+}
+
+const deleteContactMessage = `-- name: DeleteContactMessage :exec
+DELETE FROM contact_messages WHERE id = ?
+`
+
+func (q *Queries) DeleteContactMessage(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteContactMessage, id)
+	return err
+}
+
+const deleteDraftInvoice = `-- name: DeleteDraftInvoice :execrows
+DELETE FROM invoices WHERE id = ? AND status = 'draft'
+`
+
+// Only drafts can be deleted; issued invoices are voided instead
+func (q *Queries) DeleteDraftInvoice(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteDraftInvoice, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteGalleryGroup = `-- name: DeleteGalleryGroup :exec
+DELETE FROM gallery_groups WHERE id = ?
+`
+
+func (q *Queries) DeleteGalleryGroup(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteGalleryGroup, id)
+	return err
+}
+
+const deleteInvoiceItem = `-- name: DeleteInvoiceItem :exec
+DELETE FROM invoice_items WHERE id = ? AND invoice_id = ?
+`
+
+type DeleteInvoiceItemParams struct {
+	ID        int64 `json:"id"`
+	InvoiceID int64 `json:"invoice_id"`
+}
+
+func (q *Queries) DeleteInvoiceItem(ctx context.Context, arg DeleteInvoiceItemParams) error {
+	_, err := q.db.ExecContext(ctx, deleteInvoiceItem, arg.ID, arg.InvoiceID)
+	return err
+}
+
+const deleteMedia = `-- name: DeleteMedia :exec
+DELETE FROM media WHERE id = ?
+`
+
+func (q *Queries) DeleteMedia(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMedia, id)
+	return err
+}
+
+const deleteMediaForGalleryGroup = `-- name: DeleteMediaForGalleryGroup :exec
+DELETE FROM media WHERE gallery_group_id = ?
+`
+
+func (q *Queries) DeleteMediaForGalleryGroup(ctx context.Context, galleryGroupID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, deleteMediaForGalleryGroup, galleryGroupID)
+	return err
+}
+
+const deleteMediaVariantsForGalleryGroup = `-- name: DeleteMediaVariantsForGalleryGroup :exec
+DELETE FROM media_variants
+WHERE media_id IN (SELECT id FROM media WHERE gallery_group_id = ?)
+`
+
+func (q *Queries) DeleteMediaVariantsForGalleryGroup(ctx context.Context, galleryGroupID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, deleteMediaVariantsForGalleryGroup, galleryGroupID)
+	return err
+}
+
+const deleteMediaVariantsForMedia = `-- name: DeleteMediaVariantsForMedia :exec
+DELETE FROM media_variants WHERE media_id = ?
+`
+
+func (q *Queries) DeleteMediaVariantsForMedia(ctx context.Context, mediaID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMediaVariantsForMedia, mediaID)
+	return err
+}
+
+const deleteMembershipPlan = `-- name: DeleteMembershipPlan :execrows
+DELETE FROM membership_plans
+WHERE id = ? AND NOT EXISTS (SELECT 1 FROM memberships WHERE plan_id = membership_plans.id)
+`
+
+// Plans that have had members are kept for their history; deactivate
+// them instead
+func (q *Queries) DeleteMembershipPlan(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMembershipPlan, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePackage = `-- name: DeletePackage :exec
+DELETE FROM packages WHERE id = ?
+`
+
+func (q *Queries) DeletePackage(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackage, id)
+	return err
+}
+
+const deletePackageFAQ = `-- name: DeletePackageFAQ :exec
+DELETE FROM package_faqs WHERE id = ? AND package_id = ?
+`
+
+type DeletePackageFAQParams struct {
+	ID        int64 `json:"id"`
+	PackageID int64 `json:"package_id"`
+}
+
+func (q *Queries) DeletePackageFAQ(ctx context.Context, arg DeletePackageFAQParams) error {
+	_, err := q.db.ExecContext(ctx, deletePackageFAQ, arg.ID, arg.PackageID)
+	return err
+}
+
+const deleteQuote = `-- name: DeleteQuote :exec
+DELETE FROM quotes WHERE id = ?
+`
+
+func (q *Queries) DeleteQuote(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteQuote, id)
+	return err
+}
+
+const deleteQuoteItem = `-- name: DeleteQuoteItem :exec
+DELETE FROM quote_items WHERE id = ? AND quote_id = ?
+`
+
+type DeleteQuoteItemParams struct {
+	ID      int64 `json:"id"`
+	QuoteID int64 `json:"quote_id"`
+}
+
+func (q *Queries) DeleteQuoteItem(ctx context.Context, arg DeleteQuoteItemParams) error {
+	_, err := q.db.ExecContext(ctx, deleteQuoteItem, arg.ID, arg.QuoteID)
+	return err
+}
+
+const deleteReview = `-- name: DeleteReview :exec
+DELETE FROM reviews WHERE id = ?
+`
+
+func (q *Queries) DeleteReview(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteReview, id)
+	return err
+}
+
+const deleteSeriesOccurrencesFrom = `-- name: DeleteSeriesOccurrencesFrom :exec
+DELETE FROM booking_series_occurrences WHERE series_id = ? AND scheduled_at >= ?
+`
+
+type DeleteSeriesOccurrencesFromParams struct {
+	SeriesID    int64     `json:"series_id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+func (q *Queries) DeleteSeriesOccurrencesFrom(ctx context.Context, arg DeleteSeriesOccurrencesFromParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSeriesOccurrencesFrom, arg.SeriesID, arg.ScheduledAt)
+	return err
+}
+
+const deleteStaff = `-- name: DeleteStaff :exec
+DELETE FROM staff WHERE id = ?
+`
+
+func (q *Queries) DeleteStaff(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStaff, id)
+	return err
+}
+
+const deleteStaffHours = `-- name: DeleteStaffHours :exec
+DELETE FROM staff_hours WHERE staff_id = ?
+`
+
+func (q *Queries) DeleteStaffHours(ctx context.Context, staffID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStaffHours, staffID)
+	return err
+}
+
+const deleteStaffTimeOff = `-- name: DeleteStaffTimeOff :exec
+DELETE FROM staff_time_off WHERE id = ? AND staff_id = ?
+`
+
+type DeleteStaffTimeOffParams struct {
+	ID      int64 `json:"id"`
+	StaffID int64 `json:"staff_id"`
+}
+
+func (q *Queries) DeleteStaffTimeOff(ctx context.Context, arg DeleteStaffTimeOffParams) error {
+	_, err := q.db.ExecContext(ctx, deleteStaffTimeOff, arg.ID, arg.StaffID)
+	return err
+}
+
+const deleteTaxRate = `-- name: DeleteTaxRate :exec
+DELETE FROM tax_rates WHERE id = ?
+`
+
+func (q *Queries) DeleteTaxRate(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTaxRate, id)
+	return err
+}
+
+const deleteUnusedPromoCode = `-- name: DeleteUnusedPromoCode :execrows
+DELETE FROM promo_codes
+WHERE id = ? AND NOT EXISTS (SELECT 1 FROM promo_redemptions WHERE promo_code_id = promo_codes.id)
+`
+
+// Codes that have been used are kept for their history; deactivate them
+// instead
+func (q *Queries) DeleteUnusedPromoCode(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUnusedPromoCode, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const expireReviewRequestsForBooking = `-- name: ExpireReviewRequestsForBooking :exec
+UPDATE review_requests SET expires_at = CURRENT_TIMESTAMP
+WHERE booking_id = ? AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+`
+
+// Retires earlier links when a new one is sent for the same booking
+func (q *Queries) ExpireReviewRequestsForBooking(ctx context.Context, bookingID int64) error {
+	_, err := q.db.ExecContext(ctx, expireReviewRequestsForBooking, bookingID)
+	return err
+}
+
+const getAcceptedQuoteForBooking = `-- name: GetAcceptedQuoteForBooking :one
+SELECT id, customer_name, email, phone, vehicle_details, notes, status, version, expires_at, sent_at, accepted_at, booking_id, contact_message_id, created_at, updated_at, promo_code_id, promo_code, discount_type, discount_value FROM quotes
+WHERE booking_id = ? AND status = 'accepted'
+ORDER BY id DESC LIMIT 1
+`
+
+func (q *Queries) GetAcceptedQuoteForBooking(ctx context.Context, bookingID sql.NullInt64) (Quote, error) {
+	row := q.db.QueryRowContext(ctx, getAcceptedQuoteForBooking, bookingID)
+	var i Quote
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.Notes,
+		&i.Status,
+		&i.Version,
+		&i.ExpiresAt,
+		&i.SentAt,
+		&i.AcceptedAt,
+		&i.BookingID,
+		&i.ContactMessageID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PromoCodeID,
+		&i.PromoCode,
+		&i.DiscountType,
+		&i.DiscountValue,
+	)
+	return i, err
+}
+
+const getActivePackageBySlug = `-- name: GetActivePackageBySlug :one
+SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at, features, included, excluded, required_skills FROM packages
+WHERE slug = ? AND is_active = 1 LIMIT 1
+`
+
+func (q *Queries) GetActivePackageBySlug(ctx context.Context, slug string) (Package, error) {
+	row := q.db.QueryRowContext(ctx, getActivePackageBySlug, slug)
+	var i Package
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.ShortDesc,
+		&i.LongDesc,
+		&i.PriceMin,
+		&i.PriceMax,
+		&i.DurationEst,
+		&i.IsActive,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Features,
+		&i.Included,
+		&i.Excluded,
+		&i.RequiredSkills,
+	)
+	return i, err
+}
+
+const getActiveStaffByEmail = `-- name: GetActiveStaffByEmail :one
+SELECT id, name, email, phone, role, skills, is_active, created_at, updated_at FROM staff
+WHERE is_active = 1 AND email = ?
+ORDER BY id LIMIT 1
+`
+
+// The staff record for a signed-in account
+func (q *Queries) GetActiveStaffByEmail(ctx context.Context, email sql.NullString) (Staff, error) {
+	row := q.db.QueryRowContext(ctx, getActiveStaffByEmail, email)
+	var i Staff
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Role,
+		&i.Skills,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getAddonByID = `-- name: GetAddonByID :one
+SELECT id, name, description, price, is_active, sort_order, created_at, updated_at FROM addons
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetAddonByID(ctx context.Context, id int64) (Addon, error) {
+	row := q.db.QueryRowContext(ctx, getAddonByID, id)
+	var i Addon
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.IsActive,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getAllPackages = `-- name: GetAllPackages :many
+
+SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at, features, included, excluded, required_skills FROM packages
+WHERE is_active = 1
+ORDER BY sort_order, id
+`
+
+// Package queries
+func (q *Queries) GetAllPackages(ctx context.Context) ([]Package, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPackages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Package
+	for rows.Next() {
+		var i Package
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Name,
+			&i.ShortDesc,
+			&i.LongDesc,
+			&i.PriceMin,
+			&i.PriceMax,
+			&i.DurationEst,
+			&i.IsActive,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Features,
+			&i.Included,
+			&i.Excluded,
+			&i.RequiredSkills,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllPackagesAdmin = `-- name: GetAllPackagesAdmin :many
+SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at, features, included, excluded, required_skills FROM packages
+ORDER BY sort_order, id
+`
+
+func (q *Queries) GetAllPackagesAdmin(ctx context.Context) ([]Package, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPackagesAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Package
+	for rows.Next() {
+		var i Package
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Name,
+			&i.ShortDesc,
+			&i.LongDesc,
+			&i.PriceMin,
+			&i.PriceMax,
+			&i.DurationEst,
+			&i.IsActive,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Features,
+			&i.Included,
+			&i.Excluded,
+			&i.RequiredSkills,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBookingByID = `-- name: GetBookingByID :one
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id FROM bookings
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetBookingByID(ctx context.Context, id int64) (Booking, error) {
+	row := q.db.QueryRowContext(ctx, getBookingByID, id)
+	var i Booking
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.ServiceInterest,
+		&i.Notes,
+		&i.RequestedStart,
+		&i.RequestedEnd,
+		&i.Status,
+		&i.Source,
+		&i.InternalNotes,
+		&i.ClerkUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PromoCode,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.MembershipID,
+		&i.SeriesID,
+		&i.ServiceAddress,
+		&i.ServiceZip,
+		&i.ServiceLat,
+		&i.ServiceLng,
+		&i.TravelMinutes,
+		&i.TravelFee,
+		&i.StaffID,
+	)
+	return i, err
+}
+
+const getBookingDeposit = `-- name: GetBookingDeposit :one
+SELECT id, booking_id, kind, provider, checkout_id, payment_ref, amount, currency, status, refund_ref, refunded_amount, paid_at, refunded_at, created_at, updated_at FROM payments
+WHERE booking_id = ? AND kind = 'deposit'
+ORDER BY id DESC LIMIT 1
+`
+
+func (q *Queries) GetBookingDeposit(ctx context.Context, bookingID sql.NullInt64) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getBookingDeposit, bookingID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.Kind,
+		&i.Provider,
+		&i.CheckoutID,
+		&i.PaymentRef,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.RefundRef,
+		&i.RefundedAmount,
+		&i.PaidAt,
+		&i.RefundedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getBookingSeriesByID = `-- name: GetBookingSeriesByID :one
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, rrule, starts_at, duration_minutes, status, booked_through, previous_id, cancelled_at, created_at, updated_at FROM booking_series WHERE id = ?
+`
+
+func (q *Queries) GetBookingSeriesByID(ctx context.Context, id int64) (BookingSeries, error) {
+	row := q.db.QueryRowContext(ctx, getBookingSeriesByID, id)
+	var i BookingSeries
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.ServiceInterest,
+		&i.Notes,
+		&i.Rrule,
+		&i.StartsAt,
+		&i.DurationMinutes,
+		&i.Status,
+		&i.BookedThrough,
+		&i.PreviousID,
+		&i.CancelledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getContactMessageByID = `-- name: GetContactMessageByID :one
+SELECT id, name, email, phone, service_interest, message, ip_address, is_read, replied_at, booking_id, created_at FROM contact_messages
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetContactMessageByID(ctx context.Context, id int64) (ContactMessage, error) {
+	row := q.db.QueryRowContext(ctx, getContactMessageByID, id)
+	var i ContactMessage
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.ServiceInterest,
+		&i.Message,
+		&i.IpAddress,
+		&i.IsRead,
+		&i.RepliedAt,
+		&i.BookingID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDefaultTaxRate = `-- name: GetDefaultTaxRate :one
+SELECT id, name, rate, is_default, is_active, created_at FROM tax_rates
+WHERE is_default = 1 AND is_active = 1
+ORDER BY id LIMIT 1
+`
+
+func (q *Queries) GetDefaultTaxRate(ctx context.Context) (TaxRate, error) {
+	row := q.db.QueryRowContext(ctx, getDefaultTaxRate)
+	var i TaxRate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Rate,
+		&i.IsDefault,
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getGalleryGroupByID = `-- name: GetGalleryGroupByID :one
+SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id FROM gallery_groups
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetGalleryGroupByID(ctx context.Context, id int64) (GalleryGroup, error) {
+	row := q.db.QueryRowContext(ctx, getGalleryGroupByID, id)
+	var i GalleryGroup
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.VehicleMake,
+		&i.VehicleModel,
+		&i.VehicleYear,
+		&i.Description,
+		&i.IsFeatured,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PackageID,
+	)
+	return i, err
+}
+
+const getGalleryGroupBySlug = `-- name: GetGalleryGroupBySlug :one
+SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id FROM gallery_groups
+WHERE slug = ? LIMIT 1
+`
+
+func (q *Queries) GetGalleryGroupBySlug(ctx context.Context, slug string) (GalleryGroup, error) {
+	row := q.db.QueryRowContext(ctx, getGalleryGroupBySlug, slug)
+	var i GalleryGroup
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.VehicleMake,
+		&i.VehicleModel,
+		&i.VehicleYear,
+		&i.Description,
+		&i.IsFeatured,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PackageID,
+	)
+	return i, err
+}
+
+const getGiftCertificateByCode = `-- name: GetGiftCertificateByCode :one
+SELECT id, code, initial_value, balance, status, source, purchaser_name, purchaser_email, recipient_name, recipient_email, message, payment_id, expires_at, created_at, updated_at FROM gift_certificates
+WHERE code = ? LIMIT 1
+`
+
+func (q *Queries) GetGiftCertificateByCode(ctx context.Context, code string) (GiftCertificate, error) {
+	row := q.db.QueryRowContext(ctx, getGiftCertificateByCode, code)
+	var i GiftCertificate
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.InitialValue,
+		&i.Balance,
+		&i.Status,
+		&i.Source,
+		&i.PurchaserName,
+		&i.PurchaserEmail,
+		&i.RecipientName,
+		&i.RecipientEmail,
+		&i.Message,
+		&i.PaymentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getGiftCertificateByID = `-- name: GetGiftCertificateByID :one
+SELECT id, code, initial_value, balance, status, source, purchaser_name, purchaser_email, recipient_name, recipient_email, message, payment_id, expires_at, created_at, updated_at FROM gift_certificates
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetGiftCertificateByID(ctx context.Context, id int64) (GiftCertificate, error) {
+	row := q.db.QueryRowContext(ctx, getGiftCertificateByID, id)
+	var i GiftCertificate
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.InitialValue,
+		&i.Balance,
+		&i.Status,
+		&i.Source,
+		&i.PurchaserName,
+		&i.PurchaserEmail,
+		&i.RecipientName,
+		&i.RecipientEmail,
+		&i.Message,
+		&i.PaymentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getGiftCertificateByPaymentID = `-- name: GetGiftCertificateByPaymentID :one
+SELECT id, code, initial_value, balance, status, source, purchaser_name, purchaser_email, recipient_name, recipient_email, message, payment_id, expires_at, created_at, updated_at FROM gift_certificates
+WHERE payment_id = ? LIMIT 1
+`
+
+func (q *Queries) GetGiftCertificateByPaymentID(ctx context.Context, paymentID sql.NullInt64) (GiftCertificate, error) {
+	row := q.db.QueryRowContext(ctx, getGiftCertificateByPaymentID, paymentID)
+	var i GiftCertificate
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.InitialValue,
+		&i.Balance,
+		&i.Status,
+		&i.Source,
+		&i.PurchaserName,
+		&i.PurchaserEmail,
+		&i.RecipientName,
+		&i.RecipientEmail,
+		&i.Message,
+		&i.PaymentID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHeroImageForGalleryGroup = `-- name: GetHeroImageForGalleryGroup :one
+SELECT id, gallery_group_id, url, kind, sort_order, alt_text, is_private, pair_id, created_at FROM media
+WHERE gallery_group_id = ? AND kind = 'hero'
+ORDER BY sort_order
+LIMIT 1
+`
+
+func (q *Queries) GetHeroImageForGalleryGroup(ctx context.Context, galleryGroupID sql.NullInt64) (Medium, error) {
+	row := q.db.QueryRowContext(ctx, getHeroImageForGalleryGroup, galleryGroupID)
+	var i Medium
+	err := row.Scan(
+		&i.ID,
+		&i.GalleryGroupID,
+		&i.Url,
+		&i.Kind,
+		&i.SortOrder,
+		&i.AltText,
+		&i.IsPrivate,
+		&i.PairID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getInvoiceByID = `-- name: GetInvoiceByID :one
+SELECT id, number, booking_id, customer_name, email, phone, vehicle_details, status, tax_name, tax_rate, discount_type, discount_value, notes, issued_at, due_at, sent_at, paid_at, payment_method, voided_at, created_at, updated_at FROM invoices
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetInvoiceByID(ctx context.Context, id int64) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceByID, id)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.BookingID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.Status,
+		&i.TaxName,
+		&i.TaxRate,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.Notes,
+		&i.IssuedAt,
+		&i.DueAt,
+		&i.SentAt,
+		&i.PaidAt,
+		&i.PaymentMethod,
+		&i.VoidedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getMediaByID = `-- name: GetMediaByID :one
+SELECT id, gallery_group_id, url, kind, sort_order, alt_text, is_private, pair_id, created_at FROM media
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetMediaByID(ctx context.Context, id int64) (Medium, error) {
+	row := q.db.QueryRowContext(ctx, getMediaByID, id)
+	var i Medium
+	err := row.Scan(
+		&i.ID,
+		&i.GalleryGroupID,
+		&i.Url,
+		&i.Kind,
+		&i.SortOrder,
+		&i.AltText,
+		&i.IsPrivate,
+		&i.PairID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMediaForGalleryGroup = `-- name: GetMediaForGalleryGroup :many
+
+SELECT id, gallery_group_id, url, kind, sort_order, alt_text, is_private, pair_id, created_at FROM media
+WHERE gallery_group_id = ?
+ORDER BY sort_order, id
+`
+
+// Media queries
+func (q *Queries) GetMediaForGalleryGroup(ctx context.Context, galleryGroupID sql.NullInt64) ([]Medium, error) {
+	rows, err := q.db.QueryContext(ctx, getMediaForGalleryGroup, galleryGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Medium
+	for rows.Next() {
+		var i Medium
+		if err := rows.Scan(
+			&i.ID,
+			&i.GalleryGroupID,
+			&i.Url,
+			&i.Kind,
+			&i.SortOrder,
+			&i.AltText,
+			&i.IsPrivate,
+			&i.PairID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMediaVariantsForGalleryGroup = `-- name: GetMediaVariantsForGalleryGroup :many
+SELECT id, media_id, width, height, url, created_at FROM media_variants
+WHERE media_id IN (SELECT id FROM media WHERE gallery_group_id = ?)
+ORDER BY media_id, width
+`
+
+func (q *Queries) GetMediaVariantsForGalleryGroup(ctx context.Context, galleryGroupID sql.NullInt64) ([]MediaVariant, error) {
+	rows, err := q.db.QueryContext(ctx, getMediaVariantsForGalleryGroup, galleryGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MediaVariant
+	for rows.Next() {
+		var i MediaVariant
+		if err := rows.Scan(
+			&i.ID,
+			&i.MediaID,
+			&i.Width,
+			&i.Height,
+			&i.Url,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMediaVariantsForMedia = `-- name: GetMediaVariantsForMedia :many
+
+SELECT id, media_id, width, height, url, created_at FROM media_variants
+WHERE media_id = ?
+ORDER BY width
+`
+
+// Media variant queries
+func (q *Queries) GetMediaVariantsForMedia(ctx context.Context, mediaID int64) ([]MediaVariant, error) {
+	rows, err := q.db.QueryContext(ctx, getMediaVariantsForMedia, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MediaVariant
+	for rows.Next() {
+		var i MediaVariant
+		if err := rows.Scan(
+			&i.ID,
+			&i.MediaID,
+			&i.Width,
+			&i.Height,
+			&i.Url,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMembershipByID = `-- name: GetMembershipByID :one
+SELECT m.id, m.plan_id, m.customer_name, m.email, m.phone, m.clerk_user_id, m.status, m.started_at, m.paid_through, m.cancelled_at, m.cancel_reason, m.notes, m.created_at, m.updated_at, p.name AS plan_name, p.package_id, p.services_per_period, p.period_months, p.price
+FROM memberships m
+JOIN membership_plans p ON p.id = m.plan_id
+WHERE m.id = ?
+`
+
+type GetMembershipByIDRow struct {
+	ID                int64          `json:"id"`
+	PlanID            int64          `json:"plan_id"`
+	CustomerName      string         `json:"customer_name"`
+	Email             string         `json:"email"`
+	Phone             sql.NullString `json:"phone"`
+	ClerkUserID       sql.NullString `json:"clerk_user_id"`
+	Status            string         `json:"status"`
+	StartedAt         time.Time      `json:"started_at"`
+	PaidThrough       time.Time      `json:"paid_through"`
+	CancelledAt       sql.NullTime   `json:"cancelled_at"`
+	CancelReason      sql.NullString `json:"cancel_reason"`
+	Notes             sql.NullString `json:"notes"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	PlanName          string         `json:"plan_name"`
+	PackageID         sql.NullInt64  `json:"package_id"`
+	ServicesPerPeriod int64          `json:"services_per_period"`
+	PeriodMonths      int64          `json:"period_months"`
+	Price             int64          `json:"price"`
+}
+
+func (q *Queries) GetMembershipByID(ctx context.Context, id int64) (GetMembershipByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getMembershipByID, id)
+	var i GetMembershipByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.ClerkUserID,
+		&i.Status,
+		&i.StartedAt,
+		&i.PaidThrough,
+		&i.CancelledAt,
+		&i.CancelReason,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PlanName,
+		&i.PackageID,
+		&i.ServicesPerPeriod,
+		&i.PeriodMonths,
+		&i.Price,
+	)
+	return i, err
+}
+
+const getMembershipForUser = `-- name: GetMembershipForUser :one
+SELECT m.id, m.plan_id, m.customer_name, m.email, m.phone, m.clerk_user_id, m.status, m.started_at, m.paid_through, m.cancelled_at, m.cancel_reason, m.notes, m.created_at, m.updated_at, p.name AS plan_name, p.package_id, p.services_per_period, p.period_months, p.price
+FROM memberships m
+JOIN membership_plans p ON p.id = m.plan_id
+WHERE m.clerk_user_id = ? AND m.status = 'active'
+ORDER BY m.paid_through DESC
+LIMIT 1
+`
+
+type GetMembershipForUserRow struct {
+	ID                int64          `json:"id"`
+	PlanID            int64          `json:"plan_id"`
+	CustomerName      string         `json:"customer_name"`
+	Email             string         `json:"email"`
+	Phone             sql.NullString `json:"phone"`
+	ClerkUserID       sql.NullString `json:"clerk_user_id"`
+	Status            string         `json:"status"`
+	StartedAt         time.Time      `json:"started_at"`
+	PaidThrough       time.Time      `json:"paid_through"`
+	CancelledAt       sql.NullTime   `json:"cancelled_at"`
+	CancelReason      sql.NullString `json:"cancel_reason"`
+	Notes             sql.NullString `json:"notes"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	PlanName          string         `json:"plan_name"`
+	PackageID         sql.NullInt64  `json:"package_id"`
+	ServicesPerPeriod int64          `json:"services_per_period"`
+	PeriodMonths      int64          `json:"period_months"`
+	Price             int64          `json:"price"`
+}
+
+func (q *Queries) GetMembershipForUser(ctx context.Context, clerkUserID sql.NullString) (GetMembershipForUserRow, error) {
+	row := q.db.QueryRowContext(ctx, getMembershipForUser, clerkUserID)
+	var i GetMembershipForUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.ClerkUserID,
+		&i.Status,
+		&i.StartedAt,
+		&i.PaidThrough,
+		&i.CancelledAt,
+		&i.CancelReason,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PlanName,
+		&i.PackageID,
+		&i.ServicesPerPeriod,
+		&i.PeriodMonths,
+		&i.Price,
+	)
+	return i, err
+}
+
+const getMembershipPlanByID = `-- name: GetMembershipPlanByID :one
+SELECT id, name, description, package_id, services_per_period, period_months, price, is_active, created_at, updated_at FROM membership_plans WHERE id = ?
+`
+
+func (q *Queries) GetMembershipPlanByID(ctx context.Context, id int64) (MembershipPlan, error) {
+	row := q.db.QueryRowContext(ctx, getMembershipPlanByID, id)
+	var i MembershipPlan
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.PackageID,
+		&i.ServicesPerPeriod,
+		&i.PeriodMonths,
+		&i.Price,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getNextMediaSortOrder = `-- name: GetNextMediaSortOrder :one
+SELECT CAST(COALESCE(MAX(sort_order), -1) + 1 AS INTEGER) AS next_sort_order
+FROM media
+WHERE gallery_group_id = ?
+`
+
+func (q *Queries) GetNextMediaSortOrder(ctx context.Context, galleryGroupID sql.NullInt64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getNextMediaSortOrder, galleryGroupID)
+	var next_sort_order int64
+	err := row.Scan(&next_sort_order)
+	return next_sort_order, err
+}
+
+const getPackageByID = `-- name: GetPackageByID :one
+SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at, features, included, excluded, required_skills FROM packages
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetPackageByID(ctx context.Context, id int64) (Package, error) {
+	row := q.db.QueryRowContext(ctx, getPackageByID, id)
+	var i Package
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.ShortDesc,
+		&i.LongDesc,
+		&i.PriceMin,
+		&i.PriceMax,
+		&i.DurationEst,
+		&i.IsActive,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Features,
+		&i.Included,
+		&i.Excluded,
+		&i.RequiredSkills,
+	)
+	return i, err
+}
+
+const getPackageBySlug = `-- name: GetPackageBySlug :one
+SELECT id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at, features, included, excluded, required_skills FROM packages
+WHERE slug = ? LIMIT 1
+`
+
+func (q *Queries) GetPackageBySlug(ctx context.Context, slug string) (Package, error) {
+	row := q.db.QueryRowContext(ctx, getPackageBySlug, slug)
+	var i Package
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.ShortDesc,
+		&i.LongDesc,
+		&i.PriceMin,
+		&i.PriceMax,
+		&i.DurationEst,
+		&i.IsActive,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Features,
+		&i.Included,
+		&i.Excluded,
+		&i.RequiredSkills,
+	)
+	return i, err
+}
+
+const getPaymentByCheckoutID = `-- name: GetPaymentByCheckoutID :one
+SELECT id, booking_id, kind, provider, checkout_id, payment_ref, amount, currency, status, refund_ref, refunded_amount, paid_at, refunded_at, created_at, updated_at FROM payments
+WHERE checkout_id = ? LIMIT 1
+`
+
+func (q *Queries) GetPaymentByCheckoutID(ctx context.Context, checkoutID sql.NullString) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentByCheckoutID, checkoutID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.Kind,
+		&i.Provider,
+		&i.CheckoutID,
+		&i.PaymentRef,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.RefundRef,
+		&i.RefundedAmount,
+		&i.PaidAt,
+		&i.RefundedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPaymentByID = `-- name: GetPaymentByID :one
+SELECT id, booking_id, kind, provider, checkout_id, payment_ref, amount, currency, status, refund_ref, refunded_amount, paid_at, refunded_at, created_at, updated_at FROM payments
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetPaymentByID(ctx context.Context, id int64) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentByID, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.Kind,
+		&i.Provider,
+		&i.CheckoutID,
+		&i.PaymentRef,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.RefundRef,
+		&i.RefundedAmount,
+		&i.PaidAt,
+		&i.RefundedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPromoCodeByCode = `-- name: GetPromoCodeByCode :one
+SELECT id, code, description, discount_type, discount_value, starts_at, ends_at, max_uses, max_uses_per_customer, min_spend, is_active, created_at, updated_at FROM promo_codes
+WHERE code = ? LIMIT 1
+`
+
+func (q *Queries) GetPromoCodeByCode(ctx context.Context, code string) (PromoCode, error) {
+	row := q.db.QueryRowContext(ctx, getPromoCodeByCode, code)
+	var i PromoCode
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.MaxUses,
+		&i.MaxUsesPerCustomer,
+		&i.MinSpend,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPromoCodeStats = `-- name: GetPromoCodeStats :one
+SELECT p.id, p.code, p.description, p.discount_type, p.discount_value, p.starts_at, p.ends_at, p.max_uses, p.max_uses_per_customer, p.min_spend, p.is_active, p.created_at, p.updated_at,
+    CAST((SELECT COUNT(*) FROM promo_redemptions r LEFT JOIN bookings b ON b.id = r.booking_id
+          WHERE r.promo_code_id = p.id AND (b.id IS NULL OR b.status NOT IN ('cancelled', 'declined'))) AS INTEGER) AS redemptions,
+    CAST((SELECT COUNT(*) FROM promo_redemptions r JOIN bookings b ON b.id = r.booking_id
+          WHERE r.promo_code_id = p.id AND b.status IN ('cancelled', 'declined')) AS INTEGER) AS cancelled,
+    CAST((SELECT COUNT(DISTINCT r.email) FROM promo_redemptions r WHERE r.promo_code_id = p.id) AS INTEGER) AS customers
+FROM promo_codes p
+WHERE p.id = ?
+`
+
+type GetPromoCodeStatsRow struct {
+	ID                 int64          `json:"id"`
+	Code               string         `json:"code"`
+	Description        sql.NullString `json:"description"`
+	DiscountType       string         `json:"discount_type"`
+	DiscountValue      int64          `json:"discount_value"`
+	StartsAt           sql.NullTime   `json:"starts_at"`
+	EndsAt             sql.NullTime   `json:"ends_at"`
+	MaxUses            int64          `json:"max_uses"`
+	MaxUsesPerCustomer int64          `json:"max_uses_per_customer"`
+	MinSpend           int64          `json:"min_spend"`
+	IsActive           sql.NullBool   `json:"is_active"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	UpdatedAt          sql.NullTime   `json:"updated_at"`
+	Redemptions        int64          `json:"redemptions"`
+	Cancelled          int64          `json:"cancelled"`
+	Customers          int64          `json:"customers"`
+}
+
+func (q *Queries) GetPromoCodeStats(ctx context.Context, id int64) (GetPromoCodeStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getPromoCodeStats, id)
+	var i GetPromoCodeStatsRow
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.MaxUses,
+		&i.MaxUsesPerCustomer,
+		&i.MinSpend,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Redemptions,
+		&i.Cancelled,
+		&i.Customers,
+	)
+	return i, err
+}
+
+const getQuoteByID = `-- name: GetQuoteByID :one
+SELECT id, customer_name, email, phone, vehicle_details, notes, status, version, expires_at, sent_at, accepted_at, booking_id, contact_message_id, created_at, updated_at, promo_code_id, promo_code, discount_type, discount_value FROM quotes
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetQuoteByID(ctx context.Context, id int64) (Quote, error) {
+	row := q.db.QueryRowContext(ctx, getQuoteByID, id)
+	var i Quote
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.Notes,
+		&i.Status,
+		&i.Version,
+		&i.ExpiresAt,
+		&i.SentAt,
+		&i.AcceptedAt,
+		&i.BookingID,
+		&i.ContactMessageID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PromoCodeID,
+		&i.PromoCode,
+		&i.DiscountType,
+		&i.DiscountValue,
+	)
+	return i, err
+}
+
+const getReviewByID = `-- name: GetReviewByID :one
+SELECT id, author, rating, body, source, is_featured, created_at, status, booking_id, gallery_group_id, follow_up_status, follow_up_notes, external_id FROM reviews
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetReviewByID(ctx context.Context, id int64) (Review, error) {
+	row := q.db.QueryRowContext(ctx, getReviewByID, id)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.Author,
+		&i.Rating,
+		&i.Body,
+		&i.Source,
+		&i.IsFeatured,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BookingID,
+		&i.GalleryGroupID,
+		&i.FollowUpStatus,
+		&i.FollowUpNotes,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getReviewRequestByID = `-- name: GetReviewRequestByID :one
+SELECT id, booking_id, gallery_group_id, email, expires_at, sent_at, used_at, created_at FROM review_requests
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetReviewRequestByID(ctx context.Context, id int64) (ReviewRequest, error) {
+	row := q.db.QueryRowContext(ctx, getReviewRequestByID, id)
+	var i ReviewRequest
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.GalleryGroupID,
+		&i.Email,
+		&i.ExpiresAt,
+		&i.SentAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getReviewStats = `-- name: GetReviewStats :one
+SELECT COUNT(*) AS count, CAST(COALESCE(AVG(rating), 0) AS REAL) AS average
+FROM reviews
+WHERE status = 'approved'
+`
+
+type GetReviewStatsRow struct {
+	Count   int64   `json:"count"`
+	Average float64 `json:"average"`
+}
+
+// Average over approved reviews, for the public aggregate rating
+func (q *Queries) GetReviewStats(ctx context.Context) (GetReviewStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getReviewStats)
+	var i GetReviewStatsRow
+	err := row.Scan(&i.Count, &i.Average)
+	return i, err
+}
+
+const getSeriesOccurrence = `-- name: GetSeriesOccurrence :one
+SELECT id, series_id, scheduled_at, booking_id, status, created_at, updated_at FROM booking_series_occurrences WHERE id = ? AND series_id = ?
+`
+
+type GetSeriesOccurrenceParams struct {
+	ID       int64 `json:"id"`
+	SeriesID int64 `json:"series_id"`
+}
+
+func (q *Queries) GetSeriesOccurrence(ctx context.Context, arg GetSeriesOccurrenceParams) (BookingSeriesOccurrence, error) {
+	row := q.db.QueryRowContext(ctx, getSeriesOccurrence, arg.ID, arg.SeriesID)
+	var i BookingSeriesOccurrence
+	err := row.Scan(
+		&i.ID,
+		&i.SeriesID,
+		&i.ScheduledAt,
+		&i.BookingID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSlotHoldByToken = `-- name: GetSlotHoldByToken :one
+SELECT id, token, slot_start, slot_end, expires_at, ip_address, status, booking_id, created_at, updated_at FROM slot_holds WHERE token = ?
+`
+
+func (q *Queries) GetSlotHoldByToken(ctx context.Context, token string) (SlotHold, error) {
+	row := q.db.QueryRowContext(ctx, getSlotHoldByToken, token)
+	var i SlotHold
+	err := row.Scan(
+		&i.ID,
+		&i.Token,
+		&i.SlotStart,
+		&i.SlotEnd,
+		&i.ExpiresAt,
+		&i.IpAddress,
+		&i.Status,
+		&i.BookingID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStaffByID = `-- name: GetStaffByID :one
+SELECT id, name, email, phone, role, skills, is_active, created_at, updated_at FROM staff WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetStaffByID(ctx context.Context, id int64) (Staff, error) {
+	row := q.db.QueryRowContext(ctx, getStaffByID, id)
+	var i Staff
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Role,
+		&i.Skills,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTaxRateByID = `-- name: GetTaxRateByID :one
+SELECT id, name, rate, is_default, is_active, created_at FROM tax_rates
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetTaxRateByID(ctx context.Context, id int64) (TaxRate, error) {
+	row := q.db.QueryRowContext(ctx, getTaxRateByID, id)
+	var i TaxRate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Rate,
+		&i.IsDefault,
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUnlinkedMembershipByEmail = `-- name: GetUnlinkedMembershipByEmail :one
+SELECT id FROM memberships
+WHERE email = ? AND status = 'active' AND clerk_user_id IS NULL
+ORDER BY paid_through DESC
+LIMIT 1
+`
+
+// An active membership for an email that no account has claimed yet
+func (q *Queries) GetUnlinkedMembershipByEmail(ctx context.Context, email string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUnlinkedMembershipByEmail, email)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getWaitlistEntryByID = `-- name: GetWaitlistEntryByID :one
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, date_from, date_to, slot_id, clerk_user_id, status, booking_id, created_at, updated_at FROM waitlist_entries WHERE id = ?
+`
+
+func (q *Queries) GetWaitlistEntryByID(ctx context.Context, id int64) (WaitlistEntry, error) {
+	row := q.db.QueryRowContext(ctx, getWaitlistEntryByID, id)
+	var i WaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.ServiceInterest,
+		&i.Notes,
+		&i.DateFrom,
+		&i.DateTo,
+		&i.SlotID,
+		&i.ClerkUserID,
+		&i.Status,
+		&i.BookingID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWaitlistOfferByID = `-- name: GetWaitlistOfferByID :one
+SELECT id, entry_id, slot_start, slot_end, expires_at, status, booking_id, created_at, updated_at FROM waitlist_offers WHERE id = ?
+`
+
+func (q *Queries) GetWaitlistOfferByID(ctx context.Context, id int64) (WaitlistOffer, error) {
+	row := q.db.QueryRowContext(ctx, getWaitlistOfferByID, id)
+	var i WaitlistOffer
+	err := row.Scan(
+		&i.ID,
+		&i.EntryID,
+		&i.SlotStart,
+		&i.SlotEnd,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.BookingID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const issueInvoice = `-- name: IssueInvoice :execrows
+UPDATE invoices
+SET status = 'sent', number = ?, issued_at = ?, sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'draft'
+`
+
+type IssueInvoiceParams struct {
+	Number   sql.NullString `json:"number"`
+	IssuedAt sql.NullTime   `json:"issued_at"`
+	ID       int64          `json:"id"`
+}
+
+func (q *Queries) IssueInvoice(ctx context.Context, arg IssueInvoiceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, issueInvoice, arg.Number, arg.IssuedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const linkMembershipUser = `-- name: LinkMembershipUser :exec
+UPDATE memberships SET clerk_user_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type LinkMembershipUserParams struct {
+	ClerkUserID sql.NullString `json:"clerk_user_id"`
+	ID          int64          `json:"id"`
+}
+
+func (q *Queries) LinkMembershipUser(ctx context.Context, arg LinkMembershipUserParams) error {
+	_, err := q.db.ExecContext(ctx, linkMembershipUser, arg.ClerkUserID, arg.ID)
+	return err
+}
+
+const listActiveAddons = `-- name: ListActiveAddons :many
+SELECT id, name, description, price, is_active, sort_order, created_at, updated_at FROM addons
+WHERE is_active = 1
+ORDER BY sort_order, id
+`
+
+func (q *Queries) ListActiveAddons(ctx context.Context) ([]Addon, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveAddons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Addon
+	for rows.Next() {
+		var i Addon
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.IsActive,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveMembershipPlans = `-- name: ListActiveMembershipPlans :many
+SELECT p.id, p.name, p.description, p.package_id, p.services_per_period, p.period_months, p.price, p.is_active, p.created_at, p.updated_at, pk.name AS package_name
+FROM membership_plans p
+LEFT JOIN packages pk ON pk.id = p.package_id
+WHERE p.is_active = 1
+ORDER BY p.price, p.name
+`
+
+type ListActiveMembershipPlansRow struct {
+	ID                int64          `json:"id"`
+	Name              string         `json:"name"`
+	Description       sql.NullString `json:"description"`
+	PackageID         sql.NullInt64  `json:"package_id"`
+	ServicesPerPeriod int64          `json:"services_per_period"`
+	PeriodMonths      int64          `json:"period_months"`
+	Price             int64          `json:"price"`
+	IsActive          sql.NullBool   `json:"is_active"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	PackageName       sql.NullString `json:"package_name"`
+}
+
+func (q *Queries) ListActiveMembershipPlans(ctx context.Context) ([]ListActiveMembershipPlansRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveMembershipPlans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveMembershipPlansRow
+	for rows.Next() {
+		var i ListActiveMembershipPlansRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.PackageID,
+			&i.ServicesPerPeriod,
+			&i.PeriodMonths,
+			&i.Price,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PackageName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveTaxRates = `-- name: ListActiveTaxRates :many
+SELECT id, name, rate, is_default, is_active, created_at FROM tax_rates
+WHERE is_active = 1
+ORDER BY is_default DESC, name, id
+`
+
+func (q *Queries) ListActiveTaxRates(ctx context.Context) ([]TaxRate, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveTaxRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaxRate
+	for rows.Next() {
+		var i TaxRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Rate,
+			&i.IsDefault,
+			&i.IsActive,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAddons = `-- name: ListAddons :many
+
+SELECT id, name, description, price, is_active, sort_order, created_at, updated_at FROM addons
+ORDER BY sort_order, id
+`
+
+// Add-on queries
+func (q *Queries) ListAddons(ctx context.Context) ([]Addon, error) {
+	rows, err := q.db.QueryContext(ctx, listAddons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Addon
+	for rows.Next() {
+		var i Addon
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.IsActive,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBlockedSlots = `-- name: ListBlockedSlots :many
+SELECT requested_start, requested_end, status
+FROM bookings
+WHERE requested_start >= ?
+  AND requested_start < ?
+  AND status IN ('pending', 'confirmed', 'completed')
+ORDER BY requested_start
+`
+
+type ListBlockedSlotsParams struct {
+	RequestedStart   time.Time `json:"requested_start"`
+	RequestedStart_2 time.Time `json:"requested_start_2"`
+}
+
+type ListBlockedSlotsRow struct {
+	RequestedStart time.Time      `json:"requested_start"`
+	RequestedEnd   time.Time      `json:"requested_end"`
+	Status         sql.NullString `json:"status"`
+}
+
+func (q *Queries) ListBlockedSlots(ctx context.Context, arg ListBlockedSlotsParams) ([]ListBlockedSlotsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBlockedSlots, arg.RequestedStart, arg.RequestedStart_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBlockedSlotsRow
+	for rows.Next() {
+		var i ListBlockedSlotsRow
+		if err := rows.Scan(&i.RequestedStart, &i.RequestedEnd, &i.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingSeries = `-- name: ListBookingSeries :many
+SELECT s.id, s.customer_name, s.email, s.phone, s.vehicle_details, s.service_interest, s.notes, s.rrule, s.starts_at, s.duration_minutes, s.status, s.booked_through, s.previous_id, s.cancelled_at, s.created_at, s.updated_at,
+    CAST((SELECT COUNT(*) FROM booking_series_occurrences o JOIN bookings b ON b.id = o.booking_id
+          WHERE o.series_id = s.id AND b.status IN ('pending', 'confirmed')) AS INTEGER) AS upcoming,
+    CAST((SELECT COUNT(*) FROM booking_series_occurrences o
+          WHERE o.series_id = s.id AND o.status = 'conflict' AND o.scheduled_at >= ?) AS INTEGER) AS conflicts
+FROM booking_series s
+ORDER BY s.status ASC, s.starts_at DESC
+`
+
+type ListBookingSeriesRow struct {
+	ID              int64          `json:"id"`
+	CustomerName    string         `json:"customer_name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	VehicleDetails  sql.NullString `json:"vehicle_details"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Notes           sql.NullString `json:"notes"`
+	Rrule           string         `json:"rrule"`
+	StartsAt        time.Time      `json:"starts_at"`
+	DurationMinutes int64          `json:"duration_minutes"`
+	Status          string         `json:"status"`
+	BookedThrough   time.Time      `json:"booked_through"`
+	PreviousID      sql.NullInt64  `json:"previous_id"`
+	CancelledAt     sql.NullTime   `json:"cancelled_at"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	Upcoming        int64          `json:"upcoming"`
+	Conflicts       int64          `json:"conflicts"`
+}
+
+// Upcoming counts bookings still to happen; conflicts counts dates from
+// now on that couldn't be booked.
+func (q *Queries) ListBookingSeries(ctx context.Context, now time.Time) ([]ListBookingSeriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBookingSeries, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookingSeriesRow
+	for rows.Next() {
+		var i ListBookingSeriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.Rrule,
+			&i.StartsAt,
+			&i.DurationMinutes,
+			&i.Status,
+			&i.BookedThrough,
+			&i.PreviousID,
+			&i.CancelledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Upcoming,
+			&i.Conflicts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingStops = `-- name: ListBookingStops :many
+SELECT requested_start, requested_end, service_address, service_lat, service_lng, travel_minutes, staff_id
+FROM bookings
+WHERE requested_start >= ?
+  AND requested_start < ?
+  AND status IN ('pending', 'confirmed', 'completed')
+ORDER BY requested_start
+`
+
+type ListBookingStopsParams struct {
+	RequestedStart   time.Time `json:"requested_start"`
+	RequestedStart_2 time.Time `json:"requested_start_2"`
+}
+
+type ListBookingStopsRow struct {
+	RequestedStart time.Time       `json:"requested_start"`
+	RequestedEnd   time.Time       `json:"requested_end"`
+	ServiceAddress sql.NullString  `json:"service_address"`
+	ServiceLat     sql.NullFloat64 `json:"service_lat"`
+	ServiceLng     sql.NullFloat64 `json:"service_lng"`
+	TravelMinutes  int64           `json:"travel_minutes"`
+	StaffID        sql.NullInt64   `json:"staff_id"`
+}
+
+// Bookings that take their slot, with where they are and who's doing
+// them, for working out the drive between jobs
+func (q *Queries) ListBookingStops(ctx context.Context, arg ListBookingStopsParams) ([]ListBookingStopsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBookingStops, arg.RequestedStart, arg.RequestedStart_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookingStopsRow
+	for rows.Next() {
+		var i ListBookingStopsRow
+		if err := rows.Scan(
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.ServiceAddress,
+			&i.ServiceLat,
+			&i.ServiceLng,
+			&i.TravelMinutes,
+			&i.StaffID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookings = `-- name: ListBookings :many
+
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id FROM bookings
+ORDER BY requested_start DESC
+LIMIT ? OFFSET ?
+`
+
+type ListBookingsParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+// Booking queries
+func (q *Queries) ListBookings(ctx context.Context, arg ListBookingsParams) ([]Booking, error) {
+	rows, err := q.db.QueryContext(ctx, listBookings, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Booking
+	for rows.Next() {
+		var i Booking
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.Status,
+			&i.Source,
+			&i.InternalNotes,
+			&i.ClerkUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromoCode,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.MembershipID,
+			&i.SeriesID,
+			&i.ServiceAddress,
+			&i.ServiceZip,
+			&i.ServiceLat,
+			&i.ServiceLng,
+			&i.TravelMinutes,
+			&i.TravelFee,
+			&i.StaffID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingsByStatus = `-- name: ListBookingsByStatus :many
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id FROM bookings
+WHERE status = ?
+ORDER BY requested_start ASC
+LIMIT ? OFFSET ?
+`
+
+type ListBookingsByStatusParams struct {
+	Status sql.NullString `json:"status"`
+	Limit  int64          `json:"limit"`
+	Offset int64          `json:"offset"`
+}
+
+func (q *Queries) ListBookingsByStatus(ctx context.Context, arg ListBookingsByStatusParams) ([]Booking, error) {
+	rows, err := q.db.QueryContext(ctx, listBookingsByStatus, arg.Status, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Booking
+	for rows.Next() {
+		var i Booking
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.Status,
+			&i.Source,
+			&i.InternalNotes,
+			&i.ClerkUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromoCode,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.MembershipID,
+			&i.SeriesID,
+			&i.ServiceAddress,
+			&i.ServiceZip,
+			&i.ServiceLat,
+			&i.ServiceLng,
+			&i.TravelMinutes,
+			&i.TravelFee,
+			&i.StaffID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingsForCalendar = `-- name: ListBookingsForCalendar :many
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, requested_start, requested_end, status
+FROM bookings
+WHERE requested_start >= ?
+  AND requested_start < ?
+ORDER BY requested_start ASC
+`
+
+type ListBookingsForCalendarParams struct {
+	RequestedStart   time.Time `json:"requested_start"`
+	RequestedStart_2 time.Time `json:"requested_start_2"`
+}
+
+type ListBookingsForCalendarRow struct {
+	ID              int64          `json:"id"`
+	CustomerName    string         `json:"customer_name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	VehicleDetails  sql.NullString `json:"vehicle_details"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	RequestedStart  time.Time      `json:"requested_start"`
+	RequestedEnd    time.Time      `json:"requested_end"`
+	Status          sql.NullString `json:"status"`
+}
+
+func (q *Queries) ListBookingsForCalendar(ctx context.Context, arg ListBookingsForCalendarParams) ([]ListBookingsForCalendarRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBookingsForCalendar, arg.RequestedStart, arg.RequestedStart_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookingsForCalendarRow
+	for rows.Next() {
+		var i ListBookingsForCalendarRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContactMessages = `-- name: ListContactMessages :many
+SELECT id, name, email, phone, service_interest, message, ip_address, is_read, replied_at, booking_id, created_at FROM contact_messages
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+func (q *Queries) ListContactMessages(ctx context.Context, limit int64) ([]ContactMessage, error) {
+	rows, err := q.db.QueryContext(ctx, listContactMessages, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ContactMessage
+	for rows.Next() {
+		var i ContactMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Phone,
+			&i.ServiceInterest,
+			&i.Message,
+			&i.IpAddress,
+			&i.IsRead,
+			&i.RepliedAt,
+			&i.BookingID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContactReplies = `-- name: ListContactReplies :many
+SELECT id, message_id, body, sent_by, created_at FROM contact_replies
+WHERE message_id = ?
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListContactReplies(ctx context.Context, messageID int64) ([]ContactReply, error) {
+	rows, err := q.db.QueryContext(ctx, listContactReplies, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ContactReply
+	for rows.Next() {
+		var i ContactReply
+		if err := rows.Scan(
+			&i.ID,
+			&i.MessageID,
+			&i.Body,
+			&i.SentBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCustomerInvoices = `-- name: ListCustomerInvoices :many
+SELECT i.id, i.number, i.booking_id, i.customer_name, i.email, i.phone, i.vehicle_details, i.status, i.tax_name, i.tax_rate, i.discount_type, i.discount_value, i.notes, i.issued_at, i.due_at, i.sent_at, i.paid_at, i.payment_method, i.voided_at, i.created_at, i.updated_at, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM invoice_items WHERE invoice_id = i.id), 0) AS INTEGER) AS subtotal
+FROM invoices i
+LEFT JOIN bookings b ON b.id = i.booking_id
+WHERE i.status IN ('sent', 'paid', 'void')
+  AND (i.email = ? OR b.clerk_user_id = ?)
+ORDER BY i.issued_at DESC, i.id DESC
+`
+
+type ListCustomerInvoicesParams struct {
+	Email       string `json:"email"`
+	ClerkUserID string `json:"clerk_user_id"`
+}
+
+type ListCustomerInvoicesRow struct {
+	ID             int64          `json:"id"`
+	Number         sql.NullString `json:"number"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	CustomerName   string         `json:"customer_name"`
+	Email          string         `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	VehicleDetails sql.NullString `json:"vehicle_details"`
+	Status         sql.NullString `json:"status"`
+	TaxName        sql.NullString `json:"tax_name"`
+	TaxRate        int64          `json:"tax_rate"`
+	DiscountType   sql.NullString `json:"discount_type"`
+	DiscountValue  int64          `json:"discount_value"`
+	Notes          sql.NullString `json:"notes"`
+	IssuedAt       sql.NullTime   `json:"issued_at"`
+	DueAt          sql.NullTime   `json:"due_at"`
+	SentAt         sql.NullTime   `json:"sent_at"`
+	PaidAt         sql.NullTime   `json:"paid_at"`
+	PaymentMethod  sql.NullString `json:"payment_method"`
+	VoidedAt       sql.NullTime   `json:"voided_at"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	Subtotal       int64          `json:"subtotal"`
+}
+
+// Invoices a signed-in customer can see: sent to their email, or for one
+// of their bookings. Drafts stay private.
+func (q *Queries) ListCustomerInvoices(ctx context.Context, arg ListCustomerInvoicesParams) ([]ListCustomerInvoicesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCustomerInvoices, arg.Email, arg.ClerkUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCustomerInvoicesRow
+	for rows.Next() {
+		var i ListCustomerInvoicesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Number,
+			&i.BookingID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.Status,
+			&i.TaxName,
+			&i.TaxRate,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.Notes,
+			&i.IssuedAt,
+			&i.DueAt,
+			&i.SentAt,
+			&i.PaidAt,
+			&i.PaymentMethod,
+			&i.VoidedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Subtotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDepositsForBookingPage = `-- name: ListDepositsForBookingPage :many
+SELECT p.id, p.booking_id, p.kind, p.provider, p.checkout_id, p.payment_ref, p.amount, p.currency, p.status, p.refund_ref, p.refunded_amount, p.paid_at, p.refunded_at, p.created_at, p.updated_at FROM payments p
+JOIN (SELECT id FROM bookings ORDER BY requested_start DESC LIMIT ? OFFSET ?) page ON page.id = p.booking_id
+WHERE p.kind = 'deposit'
+ORDER BY p.booking_id, p.id
+`
+
+type ListDepositsForBookingPageParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+func (q *Queries) ListDepositsForBookingPage(ctx context.Context, arg ListDepositsForBookingPageParams) ([]Payment, error) {
+	rows, err := q.db.QueryContext(ctx, listDepositsForBookingPage, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookingID,
+			&i.Kind,
+			&i.Provider,
+			&i.CheckoutID,
+			&i.PaymentRef,
+			&i.Amount,
+			&i.Currency,
+			&i.Status,
+			&i.RefundRef,
+			&i.RefundedAmount,
+			&i.PaidAt,
+			&i.RefundedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFeaturedGalleryGroups = `-- name: ListFeaturedGalleryGroups :many
+SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id FROM gallery_groups
+WHERE is_featured = 1
+ORDER BY sort_order, created_at DESC
+LIMIT ?
+`
+
+func (q *Queries) ListFeaturedGalleryGroups(ctx context.Context, limit int64) ([]GalleryGroup, error) {
+	rows, err := q.db.QueryContext(ctx, listFeaturedGalleryGroups, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GalleryGroup
+	for rows.Next() {
+		var i GalleryGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.VehicleMake,
+			&i.VehicleModel,
+			&i.VehicleYear,
+			&i.Description,
+			&i.IsFeatured,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PackageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFeaturedReviews = `-- name: ListFeaturedReviews :many
+SELECT id, author, rating, body, source, is_featured, created_at, status, booking_id, gallery_group_id, follow_up_status, follow_up_notes, external_id FROM reviews
+WHERE is_featured = 1 AND status = 'approved'
+ORDER BY created_at DESC
+LIMIT ?
+`
+
+func (q *Queries) ListFeaturedReviews(ctx context.Context, limit int64) ([]Review, error) {
+	rows, err := q.db.QueryContext(ctx, listFeaturedReviews, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Review
+	for rows.Next() {
+		var i Review
+		if err := rows.Scan(
+			&i.ID,
+			&i.Author,
+			&i.Rating,
+			&i.Body,
+			&i.Source,
+			&i.IsFeatured,
+			&i.CreatedAt,
+			&i.Status,
+			&i.BookingID,
+			&i.GalleryGroupID,
+			&i.FollowUpStatus,
+			&i.FollowUpNotes,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGalleryGroupSlugs = `-- name: ListGalleryGroupSlugs :many
+SELECT slug, updated_at FROM gallery_groups
+ORDER BY sort_order, id
+`
+
+type ListGalleryGroupSlugsRow struct {
+	Slug      string       `json:"slug"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+func (q *Queries) ListGalleryGroupSlugs(ctx context.Context) ([]ListGalleryGroupSlugsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listGalleryGroupSlugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGalleryGroupSlugsRow
+	for rows.Next() {
+		var i ListGalleryGroupSlugsRow
+		if err := rows.Scan(&i.Slug, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGalleryGroupTitles = `-- name: ListGalleryGroupTitles :many
+SELECT id, title, package_id FROM gallery_groups
+ORDER BY sort_order, id
+`
+
+type ListGalleryGroupTitlesRow struct {
+	ID        int64         `json:"id"`
+	Title     string        `json:"title"`
+	PackageID sql.NullInt64 `json:"package_id"`
+}
+
+func (q *Queries) ListGalleryGroupTitles(ctx context.Context) ([]ListGalleryGroupTitlesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listGalleryGroupTitles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGalleryGroupTitlesRow
+	for rows.Next() {
+		var i ListGalleryGroupTitlesRow
+		if err := rows.Scan(&i.ID, &i.Title, &i.PackageID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGalleryGroups = `-- name: ListGalleryGroups :many
+
+SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id FROM gallery_groups
+ORDER BY sort_order, created_at DESC
+LIMIT ? OFFSET ?
+`
+
+type ListGalleryGroupsParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+// Gallery queries
+func (q *Queries) ListGalleryGroups(ctx context.Context, arg ListGalleryGroupsParams) ([]GalleryGroup, error) {
+	rows, err := q.db.QueryContext(ctx, listGalleryGroups, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GalleryGroup
+	for rows.Next() {
+		var i GalleryGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.VehicleMake,
+			&i.VehicleModel,
+			&i.VehicleYear,
+			&i.Description,
+			&i.IsFeatured,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PackageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGalleryGroupsByPackage = `-- name: ListGalleryGroupsByPackage :many
+SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id FROM gallery_groups
+WHERE package_id = ?
+ORDER BY sort_order, id
+LIMIT ?
+`
+
+type ListGalleryGroupsByPackageParams struct {
+	PackageID sql.NullInt64 `json:"package_id"`
+	Limit     int64         `json:"limit"`
+}
+
+// Projects done with a package, in gallery order
+func (q *Queries) ListGalleryGroupsByPackage(ctx context.Context, arg ListGalleryGroupsByPackageParams) ([]GalleryGroup, error) {
+	rows, err := q.db.QueryContext(ctx, listGalleryGroupsByPackage, arg.PackageID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GalleryGroup
+	for rows.Next() {
+		var i GalleryGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.VehicleMake,
+			&i.VehicleModel,
+			&i.VehicleYear,
+			&i.Description,
+			&i.IsFeatured,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PackageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGalleryGroupsPage = `-- name: ListGalleryGroupsPage :many
+
+SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id FROM gallery_groups
+WHERE (CAST(? AS TEXT) IS NULL OR vehicle_make = ?)
+  AND (CAST(? AS TEXT) IS NULL OR vehicle_model = ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year >= ?)
+  AND (CAST(? AS INTEGER) IS NULL OR vehicle_year <= ?)
+  AND (CAST(? AS BOOLEAN) = 0 OR is_featured = 1)
+  AND (CAST(? AS INTEGER) IS NULL OR package_id = ?)
+  AND (CAST(? AS INTEGER) IS NULL
+    OR sort_order > ?
+    OR (sort_order = ? AND id > ?))
+ORDER BY sort_order, id
+LIMIT ?
+`
+
+type ListGalleryGroupsPageParams struct {
+	VehicleMake    sql.NullString `json:"vehicle_make"`
+	VehicleModel   sql.NullString `json:"vehicle_model"`
+	YearMin        sql.NullInt64  `json:"year_min"`
+	YearMax        sql.NullInt64  `json:"year_max"`
+	FeaturedOnly   bool           `json:"featured_only"`
+	PackageID      sql.NullInt64  `json:"package_id"`
+	AfterSortOrder sql.NullInt64  `json:"after_sort_order"`
+	AfterID        int64          `json:"after_id"`
+	Limit          int64          `json:"limit"`
+}
+
+// Gallery listing with filters. Every filter argument is optional (NULL,
+// or false for featured_only, disables it). Facet queries repeat the filter
+// minus their own dimension so counts reflect what selecting a value would
+// return.
+func (q *Queries) ListGalleryGroupsPage(ctx context.Context, arg ListGalleryGroupsPageParams) ([]GalleryGroup, error) {
+	rows, err := q.db.QueryContext(ctx, listGalleryGroupsPage,
+		arg.VehicleMake,
+		arg.VehicleMake,
+		arg.VehicleModel,
+		arg.VehicleModel,
+		arg.YearMin,
+		arg.YearMin,
+		arg.YearMax,
+		arg.YearMax,
+		arg.FeaturedOnly,
+		arg.PackageID,
+		arg.PackageID,
+		arg.AfterSortOrder,
+		arg.AfterSortOrder,
+		arg.AfterSortOrder,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GalleryGroup
+	for rows.Next() {
+		var i GalleryGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.VehicleMake,
+			&i.VehicleModel,
+			&i.VehicleYear,
+			&i.Description,
+			&i.IsFeatured,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PackageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGiftCertificateTransactions = `-- name: ListGiftCertificateTransactions :many
+SELECT t.id, t.gift_certificate_id, t.kind, t.amount, t.balance_after, t.invoice_id, t.note, t.created_at, i.number AS invoice_number
+FROM gift_certificate_transactions t
+LEFT JOIN invoices i ON i.id = t.invoice_id
+WHERE t.gift_certificate_id = ?
+ORDER BY t.id
+`
+
+type ListGiftCertificateTransactionsRow struct {
+	ID                int64          `json:"id"`
+	GiftCertificateID int64          `json:"gift_certificate_id"`
+	Kind              string         `json:"kind"`
+	Amount            int64          `json:"amount"`
+	BalanceAfter      int64          `json:"balance_after"`
+	InvoiceID         sql.NullInt64  `json:"invoice_id"`
+	Note              sql.NullString `json:"note"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	InvoiceNumber     sql.NullString `json:"invoice_number"`
+}
+
+func (q *Queries) ListGiftCertificateTransactions(ctx context.Context, giftCertificateID int64) ([]ListGiftCertificateTransactionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listGiftCertificateTransactions, giftCertificateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGiftCertificateTransactionsRow
+	for rows.Next() {
+		var i ListGiftCertificateTransactionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.GiftCertificateID,
+			&i.Kind,
+			&i.Amount,
+			&i.BalanceAfter,
+			&i.InvoiceID,
+			&i.Note,
+			&i.CreatedAt,
+			&i.InvoiceNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGiftCertificates = `-- name: ListGiftCertificates :many
+SELECT id, code, initial_value, balance, status, source, purchaser_name, purchaser_email, recipient_name, recipient_email, message, payment_id, expires_at, created_at, updated_at FROM gift_certificates
+WHERE status != 'pending'
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+// Certificates still waiting for payment are left out
+func (q *Queries) ListGiftCertificates(ctx context.Context, limit int64) ([]GiftCertificate, error) {
+	rows, err := q.db.QueryContext(ctx, listGiftCertificates, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GiftCertificate
+	for rows.Next() {
+		var i GiftCertificate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.InitialValue,
+			&i.Balance,
+			&i.Status,
+			&i.Source,
+			&i.PurchaserName,
+			&i.PurchaserEmail,
+			&i.RecipientName,
+			&i.RecipientEmail,
+			&i.Message,
+			&i.PaymentID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoiceGiftCredits = `-- name: ListInvoiceGiftCredits :many
+SELECT g.id, g.code, CAST(-SUM(t.amount) AS INTEGER) AS amount
+FROM gift_certificate_transactions t
+JOIN gift_certificates g ON g.id = t.gift_certificate_id
+WHERE t.invoice_id = ? AND t.kind IN ('redeem', 'refund')
+GROUP BY g.id, g.code
+HAVING SUM(t.amount) < 0
+ORDER BY MIN(t.id)
+`
+
+type ListInvoiceGiftCreditsRow struct {
+	ID     int64  `json:"id"`
+	Code   string `json:"code"`
+	Amount int64  `json:"amount"`
+}
+
+// What each certificate has paid towards an invoice, net of refunds
+func (q *Queries) ListInvoiceGiftCredits(ctx context.Context, invoiceID sql.NullInt64) ([]ListInvoiceGiftCreditsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInvoiceGiftCredits, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInvoiceGiftCreditsRow
+	for rows.Next() {
+		var i ListInvoiceGiftCreditsRow
+		if err := rows.Scan(&i.ID, &i.Code, &i.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoiceItems = `-- name: ListInvoiceItems :many
+SELECT id, invoice_id, kind, package_id, addon_id, description, quantity, unit_price, created_at FROM invoice_items
+WHERE invoice_id = ?
+ORDER BY id
+`
+
+func (q *Queries) ListInvoiceItems(ctx context.Context, invoiceID int64) ([]InvoiceItem, error) {
+	rows, err := q.db.QueryContext(ctx, listInvoiceItems, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoiceItem
+	for rows.Next() {
+		var i InvoiceItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.Kind,
+			&i.PackageID,
+			&i.AddonID,
+			&i.Description,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoices = `-- name: ListInvoices :many
+SELECT i.id, i.number, i.booking_id, i.customer_name, i.email, i.phone, i.vehicle_details, i.status, i.tax_name, i.tax_rate, i.discount_type, i.discount_value, i.notes, i.issued_at, i.due_at, i.sent_at, i.paid_at, i.payment_method, i.voided_at, i.created_at, i.updated_at, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM invoice_items WHERE invoice_id = i.id), 0) AS INTEGER) AS subtotal
+FROM invoices i
+ORDER BY i.created_at DESC, i.id DESC
+LIMIT ?
+`
+
+type ListInvoicesRow struct {
+	ID             int64          `json:"id"`
+	Number         sql.NullString `json:"number"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	CustomerName   string         `json:"customer_name"`
+	Email          string         `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	VehicleDetails sql.NullString `json:"vehicle_details"`
+	Status         sql.NullString `json:"status"`
+	TaxName        sql.NullString `json:"tax_name"`
+	TaxRate        int64          `json:"tax_rate"`
+	DiscountType   sql.NullString `json:"discount_type"`
+	DiscountValue  int64          `json:"discount_value"`
+	Notes          sql.NullString `json:"notes"`
+	IssuedAt       sql.NullTime   `json:"issued_at"`
+	DueAt          sql.NullTime   `json:"due_at"`
+	SentAt         sql.NullTime   `json:"sent_at"`
+	PaidAt         sql.NullTime   `json:"paid_at"`
+	PaymentMethod  sql.NullString `json:"payment_method"`
+	VoidedAt       sql.NullTime   `json:"voided_at"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	Subtotal       int64          `json:"subtotal"`
+}
+
+func (q *Queries) ListInvoices(ctx context.Context, limit int64) ([]ListInvoicesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInvoices, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInvoicesRow
+	for rows.Next() {
+		var i ListInvoicesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Number,
+			&i.BookingID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.Status,
+			&i.TaxName,
+			&i.TaxRate,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.Notes,
+			&i.IssuedAt,
+			&i.DueAt,
+			&i.SentAt,
+			&i.PaidAt,
+			&i.PaymentMethod,
+			&i.VoidedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Subtotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoicesByStatus = `-- name: ListInvoicesByStatus :many
+SELECT i.id, i.number, i.booking_id, i.customer_name, i.email, i.phone, i.vehicle_details, i.status, i.tax_name, i.tax_rate, i.discount_type, i.discount_value, i.notes, i.issued_at, i.due_at, i.sent_at, i.paid_at, i.payment_method, i.voided_at, i.created_at, i.updated_at, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM invoice_items WHERE invoice_id = i.id), 0) AS INTEGER) AS subtotal
+FROM invoices i
+WHERE i.status = ?
+ORDER BY i.created_at DESC, i.id DESC
+LIMIT ?
+`
+
+type ListInvoicesByStatusParams struct {
+	Status sql.NullString `json:"status"`
+	Limit  int64          `json:"limit"`
+}
+
+type ListInvoicesByStatusRow struct {
+	ID             int64          `json:"id"`
+	Number         sql.NullString `json:"number"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	CustomerName   string         `json:"customer_name"`
+	Email          string         `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	VehicleDetails sql.NullString `json:"vehicle_details"`
+	Status         sql.NullString `json:"status"`
+	TaxName        sql.NullString `json:"tax_name"`
+	TaxRate        int64          `json:"tax_rate"`
+	DiscountType   sql.NullString `json:"discount_type"`
+	DiscountValue  int64          `json:"discount_value"`
+	Notes          sql.NullString `json:"notes"`
+	IssuedAt       sql.NullTime   `json:"issued_at"`
+	DueAt          sql.NullTime   `json:"due_at"`
+	SentAt         sql.NullTime   `json:"sent_at"`
+	PaidAt         sql.NullTime   `json:"paid_at"`
+	PaymentMethod  sql.NullString `json:"payment_method"`
+	VoidedAt       sql.NullTime   `json:"voided_at"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	Subtotal       int64          `json:"subtotal"`
+}
+
+func (q *Queries) ListInvoicesByStatus(ctx context.Context, arg ListInvoicesByStatusParams) ([]ListInvoicesByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInvoicesByStatus, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInvoicesByStatusRow
+	for rows.Next() {
+		var i ListInvoicesByStatusRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Number,
+			&i.BookingID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.Status,
+			&i.TaxName,
+			&i.TaxRate,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.Notes,
+			&i.IssuedAt,
+			&i.DueAt,
+			&i.SentAt,
+			&i.PaidAt,
+			&i.PaymentMethod,
+			&i.VoidedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Subtotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoicesForBookingPage = `-- name: ListInvoicesForBookingPage :many
+SELECT i.id, i.booking_id, i.number, i.status FROM invoices i
+JOIN (SELECT id FROM bookings ORDER BY requested_start DESC LIMIT ? OFFSET ?) page ON page.id = i.booking_id
+ORDER BY i.booking_id, i.id
+`
+
+type ListInvoicesForBookingPageParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+type ListInvoicesForBookingPageRow struct {
+	ID        int64          `json:"id"`
+	BookingID sql.NullInt64  `json:"booking_id"`
+	Number    sql.NullString `json:"number"`
+	Status    sql.NullString `json:"status"`
+}
+
+func (q *Queries) ListInvoicesForBookingPage(ctx context.Context, arg ListInvoicesForBookingPageParams) ([]ListInvoicesForBookingPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInvoicesForBookingPage, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInvoicesForBookingPageRow
+	for rows.Next() {
+		var i ListInvoicesForBookingPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookingID,
+			&i.Number,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobStaff = `-- name: ListJobStaff :many
+SELECT id, name, email, phone, role, skills, is_active, created_at, updated_at FROM staff
+WHERE is_active = 1 AND role IN ('technician', 'lead')
+ORDER BY name, id
+`
+
+// Staff who can be assigned jobs
+func (q *Queries) ListJobStaff(ctx context.Context) ([]Staff, error) {
+	rows, err := q.db.QueryContext(ctx, listJobStaff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Staff
+	for rows.Next() {
+		var i Staff
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Phone,
+			&i.Role,
+			&i.Skills,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobStaffHours = `-- name: ListJobStaffHours :many
+SELECT h.id, h.staff_id, h.weekday, h.start_minute, h.end_minute FROM staff_hours h
+JOIN staff s ON s.id = h.staff_id
+WHERE s.is_active = 1 AND s.role IN ('technician', 'lead')
+`
+
+// Shifts of everyone who can be assigned jobs
+func (q *Queries) ListJobStaffHours(ctx context.Context) ([]StaffHour, error) {
+	rows, err := q.db.QueryContext(ctx, listJobStaffHours)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StaffHour
+	for rows.Next() {
+		var i StaffHour
+		if err := rows.Scan(
+			&i.ID,
+			&i.StaffID,
+			&i.Weekday,
+			&i.StartMinute,
+			&i.EndMinute,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLapsedWaitlistOffers = `-- name: ListLapsedWaitlistOffers :many
+SELECT id, entry_id, slot_start, slot_end, expires_at, status, booking_id, created_at, updated_at FROM waitlist_offers WHERE status = 'open' AND expires_at <= ?
+`
+
+// Open offers whose time is up, to pass on to the next customer
+func (q *Queries) ListLapsedWaitlistOffers(ctx context.Context, expiresAt time.Time) ([]WaitlistOffer, error) {
+	rows, err := q.db.QueryContext(ctx, listLapsedWaitlistOffers, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WaitlistOffer
+	for rows.Next() {
+		var i WaitlistOffer
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntryID,
+			&i.SlotStart,
+			&i.SlotEnd,
+			&i.ExpiresAt,
+			&i.Status,
+			&i.BookingID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMediaForGalleryGroups = `-- name: ListMediaForGalleryGroups :many
+SELECT id, gallery_group_id, url, kind, sort_order, alt_text, is_private, pair_id, created_at FROM media
+WHERE gallery_group_id IN (/*SLICE:group_ids*/?)
+ORDER BY gallery_group_id, sort_order, id
+`
+
+func (q *Queries) ListMediaForGalleryGroups(ctx context.Context, groupIds []sql.NullInt64) ([]Medium, error) {
+	query := listMediaForGalleryGroups
+	var queryParams []interface{}
+	if len(groupIds) > 0 {
+		for _, v := range groupIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:group_ids*/?", strings.Repeat(",?", len(groupIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:group_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Medium
+	for rows.Next() {
+		var i Medium
+		if err := rows.Scan(
+			&i.ID,
+			&i.GalleryGroupID,
+			&i.Url,
+			&i.Kind,
+			&i.SortOrder,
+			&i.AltText,
+			&i.IsPrivate,
+			&i.PairID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMediaVariantsForMedia = `-- name: ListMediaVariantsForMedia :many
+SELECT id, media_id, width, height, url, created_at FROM media_variants
+WHERE media_id IN (/*SLICE:media_ids*/?)
+ORDER BY media_id, width
+`
+
+func (q *Queries) ListMediaVariantsForMedia(ctx context.Context, mediaIds []int64) ([]MediaVariant, error) {
+	query := listMediaVariantsForMedia
+	var queryParams []interface{}
+	if len(mediaIds) > 0 {
+		for _, v := range mediaIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:media_ids*/?", strings.Repeat(",?", len(mediaIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:media_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MediaVariant
+	for rows.Next() {
+		var i MediaVariant
+		if err := rows.Scan(
+			&i.ID,
+			&i.MediaID,
+			&i.Width,
+			&i.Height,
+			&i.Url,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMediaWithoutVariants = `-- name: ListMediaWithoutVariants :many
+SELECT id, gallery_group_id, url, kind, sort_order, alt_text, is_private, pair_id, created_at FROM media
+WHERE id NOT IN (SELECT media_id FROM media_variants)
+ORDER BY id
+`
+
+func (q *Queries) ListMediaWithoutVariants(ctx context.Context) ([]Medium, error) {
+	rows, err := q.db.QueryContext(ctx, listMediaWithoutVariants)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Medium
+	for rows.Next() {
+		var i Medium
+		if err := rows.Scan(
+			&i.ID,
+			&i.GalleryGroupID,
+			&i.Url,
+			&i.Kind,
+			&i.SortOrder,
+			&i.AltText,
+			&i.IsPrivate,
+			&i.PairID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMembershipBookingStarts = `-- name: ListMembershipBookingStarts :many
+SELECT membership_id, requested_start FROM bookings
+WHERE membership_id IS NOT NULL
+  AND requested_start >= ?
+  AND status NOT IN ('cancelled', 'declined')
+`
+
+type ListMembershipBookingStartsRow struct {
+	MembershipID   sql.NullInt64 `json:"membership_id"`
+	RequestedStart time.Time     `json:"requested_start"`
+}
+
+func (q *Queries) ListMembershipBookingStarts(ctx context.Context, requestedStart time.Time) ([]ListMembershipBookingStartsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMembershipBookingStarts, requestedStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMembershipBookingStartsRow
+	for rows.Next() {
+		var i ListMembershipBookingStartsRow
+		if err := rows.Scan(&i.MembershipID, &i.RequestedStart); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMembershipBookings = `-- name: ListMembershipBookings :many
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id FROM bookings WHERE membership_id = ? ORDER BY requested_start DESC
+`
+
+func (q *Queries) ListMembershipBookings(ctx context.Context, membershipID sql.NullInt64) ([]Booking, error) {
+	rows, err := q.db.QueryContext(ctx, listMembershipBookings, membershipID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Booking
+	for rows.Next() {
+		var i Booking
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.Status,
+			&i.Source,
+			&i.InternalNotes,
+			&i.ClerkUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromoCode,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.MembershipID,
+			&i.SeriesID,
+			&i.ServiceAddress,
+			&i.ServiceZip,
+			&i.ServiceLat,
+			&i.ServiceLng,
+			&i.TravelMinutes,
+			&i.TravelFee,
+			&i.StaffID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMembershipPlans = `-- name: ListMembershipPlans :many
+
+SELECT p.id, p.name, p.description, p.package_id, p.services_per_period, p.period_months, p.price, p.is_active, p.created_at, p.updated_at, pk.name AS package_name,
+    CAST((SELECT COUNT(*) FROM memberships m WHERE m.plan_id = p.id AND m.status = 'active') AS INTEGER) AS active_members
+FROM membership_plans p
+LEFT JOIN packages pk ON pk.id = p.package_id
+ORDER BY p.is_active DESC, p.price, p.name
+`
+
+type ListMembershipPlansRow struct {
+	ID                int64          `json:"id"`
+	Name              string         `json:"name"`
+	Description       sql.NullString `json:"description"`
+	PackageID         sql.NullInt64  `json:"package_id"`
+	ServicesPerPeriod int64          `json:"services_per_period"`
+	PeriodMonths      int64          `json:"period_months"`
+	Price             int64          `json:"price"`
+	IsActive          sql.NullBool   `json:"is_active"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	PackageName       sql.NullString `json:"package_name"`
+	ActiveMembers     int64          `json:"active_members"`
+}
+
+// Membership queries
+func (q *Queries) ListMembershipPlans(ctx context.Context) ([]ListMembershipPlansRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMembershipPlans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMembershipPlansRow
+	for rows.Next() {
+		var i ListMembershipPlansRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.PackageID,
+			&i.ServicesPerPeriod,
+			&i.PeriodMonths,
+			&i.Price,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PackageName,
+			&i.ActiveMembers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMembershipRenewals = `-- name: ListMembershipRenewals :many
+SELECT id, membership_id, period_start, period_end, amount, payment_method, created_at FROM membership_renewals WHERE membership_id = ? ORDER BY period_start DESC
+`
+
+func (q *Queries) ListMembershipRenewals(ctx context.Context, membershipID int64) ([]MembershipRenewal, error) {
+	rows, err := q.db.QueryContext(ctx, listMembershipRenewals, membershipID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MembershipRenewal
+	for rows.Next() {
+		var i MembershipRenewal
+		if err := rows.Scan(
+			&i.ID,
+			&i.MembershipID,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.Amount,
+			&i.PaymentMethod,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMemberships = `-- name: ListMemberships :many
+SELECT m.id, m.plan_id, m.customer_name, m.email, m.phone, m.clerk_user_id, m.status, m.started_at, m.paid_through, m.cancelled_at, m.cancel_reason, m.notes, m.created_at, m.updated_at, p.name AS plan_name, p.services_per_period, p.period_months, p.price
+FROM memberships m
+JOIN membership_plans p ON p.id = m.plan_id
+ORDER BY m.status, m.customer_name
+`
+
+type ListMembershipsRow struct {
+	ID                int64          `json:"id"`
+	PlanID            int64          `json:"plan_id"`
+	CustomerName      string         `json:"customer_name"`
+	Email             string         `json:"email"`
+	Phone             sql.NullString `json:"phone"`
+	ClerkUserID       sql.NullString `json:"clerk_user_id"`
+	Status            string         `json:"status"`
+	StartedAt         time.Time      `json:"started_at"`
+	PaidThrough       time.Time      `json:"paid_through"`
+	CancelledAt       sql.NullTime   `json:"cancelled_at"`
+	CancelReason      sql.NullString `json:"cancel_reason"`
+	Notes             sql.NullString `json:"notes"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	PlanName          string         `json:"plan_name"`
+	ServicesPerPeriod int64          `json:"services_per_period"`
+	PeriodMonths      int64          `json:"period_months"`
+	Price             int64          `json:"price"`
+}
+
+func (q *Queries) ListMemberships(ctx context.Context) ([]ListMembershipsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMemberships)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMembershipsRow
+	for rows.Next() {
+		var i ListMembershipsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlanID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.ClerkUserID,
+			&i.Status,
+			&i.StartedAt,
+			&i.PaidThrough,
+			&i.CancelledAt,
+			&i.CancelReason,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PlanName,
+			&i.ServicesPerPeriod,
+			&i.PeriodMonths,
+			&i.Price,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenFollowUpReviews = `-- name: ListOpenFollowUpReviews :many
+SELECT r.id, r.author, r.rating, r.body, r.source, r.is_featured, r.created_at, r.status, r.booking_id, r.gallery_group_id, r.follow_up_status, r.follow_up_notes, r.external_id, b.customer_name, b.email, b.phone
+FROM reviews r
+LEFT JOIN bookings b ON b.id = r.booking_id
+WHERE r.follow_up_status = 'open'
+ORDER BY r.created_at ASC
+`
+
+type ListOpenFollowUpReviewsRow struct {
+	ID             int64          `json:"id"`
+	Author         string         `json:"author"`
+	Rating         sql.NullInt64  `json:"rating"`
+	Body           sql.NullString `json:"body"`
+	Source         sql.NullString `json:"source"`
+	IsFeatured     sql.NullBool   `json:"is_featured"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	Status         sql.NullString `json:"status"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	GalleryGroupID sql.NullInt64  `json:"gallery_group_id"`
+	FollowUpStatus sql.NullString `json:"follow_up_status"`
+	FollowUpNotes  sql.NullString `json:"follow_up_notes"`
+	ExternalID     sql.NullString `json:"external_id"`
+	CustomerName   sql.NullString `json:"customer_name"`
+	Email          sql.NullString `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+}
+
+func (q *Queries) ListOpenFollowUpReviews(ctx context.Context) ([]ListOpenFollowUpReviewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOpenFollowUpReviews)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOpenFollowUpReviewsRow
+	for rows.Next() {
+		var i ListOpenFollowUpReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Author,
+			&i.Rating,
+			&i.Body,
+			&i.Source,
+			&i.IsFeatured,
+			&i.CreatedAt,
+			&i.Status,
+			&i.BookingID,
+			&i.GalleryGroupID,
+			&i.FollowUpStatus,
+			&i.FollowUpNotes,
+			&i.ExternalID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenWaitlistOffers = `-- name: ListOpenWaitlistOffers :many
+SELECT o.id, o.entry_id, o.slot_start, o.slot_end, o.expires_at, o.status, o.booking_id, o.created_at, o.updated_at, e.customer_name, e.email
+FROM waitlist_offers o
+JOIN waitlist_entries e ON e.id = o.entry_id
+WHERE o.status = 'open' AND o.expires_at > ?
+ORDER BY o.expires_at ASC
+`
+
+type ListOpenWaitlistOffersRow struct {
+	ID           int64         `json:"id"`
+	EntryID      int64         `json:"entry_id"`
+	SlotStart    time.Time     `json:"slot_start"`
+	SlotEnd      time.Time     `json:"slot_end"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+	Status       string        `json:"status"`
+	BookingID    sql.NullInt64 `json:"booking_id"`
+	CreatedAt    sql.NullTime  `json:"created_at"`
+	UpdatedAt    sql.NullTime  `json:"updated_at"`
+	CustomerName string        `json:"customer_name"`
+	Email        string        `json:"email"`
+}
+
+func (q *Queries) ListOpenWaitlistOffers(ctx context.Context, expiresAt time.Time) ([]ListOpenWaitlistOffersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOpenWaitlistOffers, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOpenWaitlistOffersRow
+	for rows.Next() {
+		var i ListOpenWaitlistOffersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntryID,
+			&i.SlotStart,
+			&i.SlotEnd,
+			&i.ExpiresAt,
+			&i.Status,
+			&i.BookingID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CustomerName,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPackageFAQs = `-- name: ListPackageFAQs :many
+
+SELECT id, package_id, question, answer, sort_order, created_at FROM package_faqs
+WHERE package_id = ?
+ORDER BY sort_order, id
+`
+
+// Package FAQ queries
+func (q *Queries) ListPackageFAQs(ctx context.Context, packageID int64) ([]PackageFaq, error) {
+	rows, err := q.db.QueryContext(ctx, listPackageFAQs, packageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PackageFaq
+	for rows.Next() {
+		var i PackageFaq
+		if err := rows.Scan(
+			&i.ID,
+			&i.PackageID,
+			&i.Question,
+			&i.Answer,
+			&i.SortOrder,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPromoCodePackageIDs = `-- name: ListPromoCodePackageIDs :many
+SELECT package_id FROM promo_code_packages
+WHERE promo_code_id = ?
+`
+
+func (q *Queries) ListPromoCodePackageIDs(ctx context.Context, promoCodeID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listPromoCodePackageIDs, promoCodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var package_id int64
+		if err := rows.Scan(&package_id); err != nil {
+			return nil, err
+		}
+		items = append(items, package_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPromoCodePackageNames = `-- name: ListPromoCodePackageNames :many
+SELECT pcp.promo_code_id, p.name
+FROM promo_code_packages pcp
+JOIN packages p ON p.id = pcp.package_id
+ORDER BY pcp.promo_code_id, p.sort_order, p.name
+`
+
+type ListPromoCodePackageNamesRow struct {
+	PromoCodeID int64  `json:"promo_code_id"`
+	Name        string `json:"name"`
+}
+
+func (q *Queries) ListPromoCodePackageNames(ctx context.Context) ([]ListPromoCodePackageNamesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPromoCodePackageNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPromoCodePackageNamesRow
+	for rows.Next() {
+		var i ListPromoCodePackageNamesRow
+		if err := rows.Scan(&i.PromoCodeID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPromoCodes = `-- name: ListPromoCodes :many
+
+SELECT p.id, p.code, p.description, p.discount_type, p.discount_value, p.starts_at, p.ends_at, p.max_uses, p.max_uses_per_customer, p.min_spend, p.is_active, p.created_at, p.updated_at,
+    CAST((SELECT COUNT(*) FROM promo_redemptions r LEFT JOIN bookings b ON b.id = r.booking_id
+          WHERE r.promo_code_id = p.id AND (b.id IS NULL OR b.status NOT IN ('cancelled', 'declined'))) AS INTEGER) AS redemptions,
+    CAST((SELECT COUNT(*) FROM promo_redemptions r JOIN bookings b ON b.id = r.booking_id
+          WHERE r.promo_code_id = p.id AND b.status IN ('cancelled', 'declined')) AS INTEGER) AS cancelled,
+    CAST((SELECT COUNT(DISTINCT r.email) FROM promo_redemptions r WHERE r.promo_code_id = p.id) AS INTEGER) AS customers
+FROM promo_codes p
+ORDER BY p.is_active DESC, p.created_at DESC, p.id DESC
+`
+
+type ListPromoCodesRow struct {
+	ID                 int64          `json:"id"`
+	Code               string         `json:"code"`
+	Description        sql.NullString `json:"description"`
+	DiscountType       string         `json:"discount_type"`
+	DiscountValue      int64          `json:"discount_value"`
+	StartsAt           sql.NullTime   `json:"starts_at"`
+	EndsAt             sql.NullTime   `json:"ends_at"`
+	MaxUses            int64          `json:"max_uses"`
+	MaxUsesPerCustomer int64          `json:"max_uses_per_customer"`
+	MinSpend           int64          `json:"min_spend"`
+	IsActive           sql.NullBool   `json:"is_active"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	UpdatedAt          sql.NullTime   `json:"updated_at"`
+	Redemptions        int64          `json:"redemptions"`
+	Cancelled          int64          `json:"cancelled"`
+	Customers          int64          `json:"customers"`
+}
+
+// Promo code queries
+func (q *Queries) ListPromoCodes(ctx context.Context) ([]ListPromoCodesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPromoCodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPromoCodesRow
+	for rows.Next() {
+		var i ListPromoCodesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Description,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.MaxUses,
+			&i.MaxUsesPerCustomer,
+			&i.MinSpend,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Redemptions,
+			&i.Cancelled,
+			&i.Customers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPromoRedemptions = `-- name: ListPromoRedemptions :many
+SELECT r.id, r.email, r.quote_id, r.created_at, r.booking_id,
+    CAST(COALESCE(b.customer_name, '') AS TEXT) AS customer_name,
+    CAST(COALESCE(b.status, '') AS TEXT) AS booking_status,
+    b.requested_start
+FROM promo_redemptions r
+LEFT JOIN bookings b ON b.id = r.booking_id
+WHERE r.promo_code_id = ?
+ORDER BY r.created_at DESC, r.id DESC
+`
+
+type ListPromoRedemptionsRow struct {
+	ID             int64         `json:"id"`
+	Email          string        `json:"email"`
+	QuoteID        sql.NullInt64 `json:"quote_id"`
+	CreatedAt      sql.NullTime  `json:"created_at"`
+	BookingID      sql.NullInt64 `json:"booking_id"`
+	CustomerName   string        `json:"customer_name"`
+	BookingStatus  string        `json:"booking_status"`
+	RequestedStart sql.NullTime  `json:"requested_start"`
+}
+
+func (q *Queries) ListPromoRedemptions(ctx context.Context, promoCodeID int64) ([]ListPromoRedemptionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPromoRedemptions, promoCodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPromoRedemptionsRow
+	for rows.Next() {
+		var i ListPromoRedemptionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.QuoteID,
+			&i.CreatedAt,
+			&i.BookingID,
+			&i.CustomerName,
+			&i.BookingStatus,
+			&i.RequestedStart,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuoteItems = `-- name: ListQuoteItems :many
+SELECT id, quote_id, kind, package_id, addon_id, description, quantity, unit_price, created_at FROM quote_items
+WHERE quote_id = ?
+ORDER BY id
+`
+
+func (q *Queries) ListQuoteItems(ctx context.Context, quoteID int64) ([]QuoteItem, error) {
+	rows, err := q.db.QueryContext(ctx, listQuoteItems, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuoteItem
+	for rows.Next() {
+		var i QuoteItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.QuoteID,
+			&i.Kind,
+			&i.PackageID,
+			&i.AddonID,
+			&i.Description,
+			&i.Quantity,
+			&i.UnitPrice,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuoteVersions = `-- name: ListQuoteVersions :many
+SELECT id, quote_id, version, items, total, expires_at, created_at FROM quote_versions
+WHERE quote_id = ?
+ORDER BY version DESC
+`
+
+func (q *Queries) ListQuoteVersions(ctx context.Context, quoteID int64) ([]QuoteVersion, error) {
+	rows, err := q.db.QueryContext(ctx, listQuoteVersions, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuoteVersion
+	for rows.Next() {
+		var i QuoteVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.QuoteID,
+			&i.Version,
+			&i.Items,
+			&i.Total,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuotes = `-- name: ListQuotes :many
+SELECT q.id, q.customer_name, q.email, q.phone, q.vehicle_details, q.notes, q.status, q.version, q.expires_at, q.sent_at, q.accepted_at, q.booking_id, q.contact_message_id, q.created_at, q.updated_at, q.promo_code_id, q.promo_code, q.discount_type, q.discount_value, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM quote_items WHERE quote_id = q.id), 0) AS INTEGER) AS total
+FROM quotes q
+ORDER BY q.created_at DESC, q.id DESC
+LIMIT ?
+`
+
+type ListQuotesRow struct {
+	ID               int64          `json:"id"`
+	CustomerName     string         `json:"customer_name"`
+	Email            string         `json:"email"`
+	Phone            sql.NullString `json:"phone"`
+	VehicleDetails   sql.NullString `json:"vehicle_details"`
+	Notes            sql.NullString `json:"notes"`
+	Status           sql.NullString `json:"status"`
+	Version          sql.NullInt64  `json:"version"`
+	ExpiresAt        sql.NullTime   `json:"expires_at"`
+	SentAt           sql.NullTime   `json:"sent_at"`
+	AcceptedAt       sql.NullTime   `json:"accepted_at"`
+	BookingID        sql.NullInt64  `json:"booking_id"`
+	ContactMessageID sql.NullInt64  `json:"contact_message_id"`
+	CreatedAt        sql.NullTime   `json:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	PromoCodeID      sql.NullInt64  `json:"promo_code_id"`
+	PromoCode        sql.NullString `json:"promo_code"`
+	DiscountType     sql.NullString `json:"discount_type"`
+	DiscountValue    int64          `json:"discount_value"`
+	Total            int64          `json:"total"`
+}
+
+func (q *Queries) ListQuotes(ctx context.Context, limit int64) ([]ListQuotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listQuotes, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListQuotesRow
+	for rows.Next() {
+		var i ListQuotesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.Notes,
+			&i.Status,
+			&i.Version,
+			&i.ExpiresAt,
+			&i.SentAt,
+			&i.AcceptedAt,
+			&i.BookingID,
+			&i.ContactMessageID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromoCodeID,
+			&i.PromoCode,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuotesByStatus = `-- name: ListQuotesByStatus :many
+SELECT q.id, q.customer_name, q.email, q.phone, q.vehicle_details, q.notes, q.status, q.version, q.expires_at, q.sent_at, q.accepted_at, q.booking_id, q.contact_message_id, q.created_at, q.updated_at, q.promo_code_id, q.promo_code, q.discount_type, q.discount_value, CAST(COALESCE((SELECT SUM(quantity * unit_price) FROM quote_items WHERE quote_id = q.id), 0) AS INTEGER) AS total
+FROM quotes q
+WHERE q.status = ?
+ORDER BY q.created_at DESC, q.id DESC
+LIMIT ?
+`
+
+type ListQuotesByStatusParams struct {
+	Status sql.NullString `json:"status"`
+	Limit  int64          `json:"limit"`
+}
+
+type ListQuotesByStatusRow struct {
+	ID               int64          `json:"id"`
+	CustomerName     string         `json:"customer_name"`
+	Email            string         `json:"email"`
+	Phone            sql.NullString `json:"phone"`
+	VehicleDetails   sql.NullString `json:"vehicle_details"`
+	Notes            sql.NullString `json:"notes"`
+	Status           sql.NullString `json:"status"`
+	Version          sql.NullInt64  `json:"version"`
+	ExpiresAt        sql.NullTime   `json:"expires_at"`
+	SentAt           sql.NullTime   `json:"sent_at"`
+	AcceptedAt       sql.NullTime   `json:"accepted_at"`
+	BookingID        sql.NullInt64  `json:"booking_id"`
+	ContactMessageID sql.NullInt64  `json:"contact_message_id"`
+	CreatedAt        sql.NullTime   `json:"created_at"`
+	UpdatedAt        sql.NullTime   `json:"updated_at"`
+	PromoCodeID      sql.NullInt64  `json:"promo_code_id"`
+	PromoCode        sql.NullString `json:"promo_code"`
+	DiscountType     sql.NullString `json:"discount_type"`
+	DiscountValue    int64          `json:"discount_value"`
+	Total            int64          `json:"total"`
+}
+
+func (q *Queries) ListQuotesByStatus(ctx context.Context, arg ListQuotesByStatusParams) ([]ListQuotesByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, listQuotesByStatus, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListQuotesByStatusRow
+	for rows.Next() {
+		var i ListQuotesByStatusRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.Notes,
+			&i.Status,
+			&i.Version,
+			&i.ExpiresAt,
+			&i.SentAt,
+			&i.AcceptedAt,
+			&i.BookingID,
+			&i.ContactMessageID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromoCodeID,
+			&i.PromoCode,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuotesForContactMessage = `-- name: ListQuotesForContactMessage :many
+SELECT id, status FROM quotes
+WHERE contact_message_id = ?
+ORDER BY id
+`
+
+type ListQuotesForContactMessageRow struct {
+	ID     int64          `json:"id"`
+	Status sql.NullString `json:"status"`
+}
+
+func (q *Queries) ListQuotesForContactMessage(ctx context.Context, contactMessageID sql.NullInt64) ([]ListQuotesForContactMessageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listQuotesForContactMessage, contactMessageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListQuotesForContactMessageRow
+	for rows.Next() {
+		var i ListQuotesForContactMessageRow
+		if err := rows.Scan(&i.ID, &i.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRelatedGalleryGroups = `-- name: ListRelatedGalleryGroups :many
+SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id FROM gallery_groups
+WHERE id != ?
+ORDER BY
+    CASE WHEN package_id = ? THEN 0 ELSE 1 END,
+    CASE WHEN vehicle_make = ? THEN 0 ELSE 1 END,
+    is_featured DESC,
+    sort_order
+LIMIT ?
+`
+
+type ListRelatedGalleryGroupsParams struct {
+	ID          int64          `json:"id"`
+	PackageID   sql.NullInt64  `json:"package_id"`
+	VehicleMake sql.NullString `json:"vehicle_make"`
+	Limit       int64          `json:"limit"`
+}
+
+func (q *Queries) ListRelatedGalleryGroups(ctx context.Context, arg ListRelatedGalleryGroupsParams) ([]GalleryGroup, error) {
+	rows, err := q.db.QueryContext(ctx, listRelatedGalleryGroups,
+		arg.ID,
+		arg.PackageID,
+		arg.VehicleMake,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GalleryGroup
+	for rows.Next() {
+		var i GalleryGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.VehicleMake,
+			&i.VehicleModel,
+			&i.VehicleYear,
+			&i.Description,
+			&i.IsFeatured,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PackageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReviewRequestsForBookingPage = `-- name: ListReviewRequestsForBookingPage :many
+SELECT rr.id, rr.booking_id, rr.gallery_group_id, rr.email, rr.expires_at, rr.sent_at, rr.used_at, rr.created_at FROM review_requests rr
+JOIN (SELECT id FROM bookings ORDER BY requested_start DESC LIMIT ? OFFSET ?) page ON page.id = rr.booking_id
+ORDER BY rr.booking_id, rr.id
+`
+
+type ListReviewRequestsForBookingPageParams struct {
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+}
+
+// Review requests for the bookings on one page of ListBookings
+func (q *Queries) ListReviewRequestsForBookingPage(ctx context.Context, arg ListReviewRequestsForBookingPageParams) ([]ReviewRequest, error) {
+	rows, err := q.db.QueryContext(ctx, listReviewRequestsForBookingPage, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewRequest
+	for rows.Next() {
+		var i ReviewRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookingID,
+			&i.GalleryGroupID,
+			&i.Email,
+			&i.ExpiresAt,
+			&i.SentAt,
+			&i.UsedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReviews = `-- name: ListReviews :many
+
+SELECT id, author, rating, body, source, is_featured, created_at, status, booking_id, gallery_group_id, follow_up_status, follow_up_notes, external_id FROM reviews
+WHERE CAST(? AS TEXT) IS NULL OR status = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+type ListReviewsParams struct {
+	Status sql.NullString `json:"status"`
+	Limit  int64          `json:"limit"`
+}
+
+// Review queries
+func (q *Queries) ListReviews(ctx context.Context, arg ListReviewsParams) ([]Review, error) {
+	rows, err := q.db.QueryContext(ctx, listReviews, arg.Status, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Review
+	for rows.Next() {
+		var i Review
+		if err := rows.Scan(
+			&i.ID,
+			&i.Author,
+			&i.Rating,
+			&i.Body,
+			&i.Source,
+			&i.IsFeatured,
+			&i.CreatedAt,
+			&i.Status,
+			&i.BookingID,
+			&i.GalleryGroupID,
+			&i.FollowUpStatus,
+			&i.FollowUpNotes,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeriesBookingsFrom = `-- name: ListSeriesBookingsFrom :many
+SELECT b.id, b.customer_name, b.email, b.phone, b.vehicle_details, b.service_interest, b.notes, b.requested_start, b.requested_end, b.status, b.source, b.internal_notes, b.clerk_user_id, b.created_at, b.updated_at, b.promo_code, b.discount_type, b.discount_value, b.membership_id, b.series_id, b.service_address, b.service_zip, b.service_lat, b.service_lng, b.travel_minutes, b.travel_fee, b.staff_id FROM bookings b
+JOIN booking_series_occurrences o ON o.booking_id = b.id
+WHERE o.series_id = ?
+  AND o.scheduled_at >= ?
+  AND b.status IN ('pending', 'confirmed')
+ORDER BY b.requested_start ASC
+`
+
+type ListSeriesBookingsFromParams struct {
+	SeriesID    int64     `json:"series_id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// a moved booking goes with the date it was moved from.
+func (q *Queries) ListSeriesBookingsFrom(ctx context.Context, arg ListSeriesBookingsFromParams) ([]Booking, error) {
+	rows, err := q.db.QueryContext(ctx, listSeriesBookingsFrom, arg.SeriesID, arg.ScheduledAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Booking
+	for rows.Next() {
+		var i Booking
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.Status,
+			&i.Source,
+			&i.InternalNotes,
+			&i.ClerkUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromoCode,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.MembershipID,
+			&i.SeriesID,
+			&i.ServiceAddress,
+			&i.ServiceZip,
+			&i.ServiceLat,
+			&i.ServiceLng,
+			&i.TravelMinutes,
+			&i.TravelFee,
+			&i.StaffID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeriesOccurrences = `-- name: ListSeriesOccurrences :many
+SELECT o.id, o.series_id, o.scheduled_at, o.booking_id, o.status, o.created_at, o.updated_at, b.requested_start, b.requested_end, b.status AS booking_status
+FROM booking_series_occurrences o
+LEFT JOIN bookings b ON b.id = o.booking_id
+WHERE o.series_id = ?
+ORDER BY o.scheduled_at ASC
+`
+
+type ListSeriesOccurrencesRow struct {
+	ID             int64          `json:"id"`
+	SeriesID       int64          `json:"series_id"`
+	ScheduledAt    time.Time      `json:"scheduled_at"`
+	BookingID      sql.NullInt64  `json:"booking_id"`
+	Status         string         `json:"status"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	RequestedStart sql.NullTime   `json:"requested_start"`
+	RequestedEnd   sql.NullTime   `json:"requested_end"`
+	BookingStatus  sql.NullString `json:"booking_status"`
+}
+
+func (q *Queries) ListSeriesOccurrences(ctx context.Context, seriesID int64) ([]ListSeriesOccurrencesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSeriesOccurrences, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSeriesOccurrencesRow
+	for rows.Next() {
+		var i ListSeriesOccurrencesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeriesID,
+			&i.ScheduledAt,
+			&i.BookingID,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.BookingStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeriesToExtend = `-- name: ListSeriesToExtend :many
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, rrule, starts_at, duration_minutes, status, booked_through, previous_id, cancelled_at, created_at, updated_at FROM booking_series WHERE status = 'active' AND booked_through < ?
+`
+
+// Active series whose occurrences haven't been made up to the horizon
+func (q *Queries) ListSeriesToExtend(ctx context.Context, bookedThrough time.Time) ([]BookingSeries, error) {
+	rows, err := q.db.QueryContext(ctx, listSeriesToExtend, bookedThrough)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BookingSeries
+	for rows.Next() {
+		var i BookingSeries
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.Rrule,
+			&i.StartsAt,
+			&i.DurationMinutes,
+			&i.Status,
+			&i.BookedThrough,
+			&i.PreviousID,
+			&i.CancelledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShowcaseMediaPairs = `-- name: ListShowcaseMediaPairs :many
+SELECT
+    b.id AS before_id, b.url AS before_url, b.alt_text AS before_alt_text,
+    a.id AS after_id, a.url AS after_url, a.alt_text AS after_alt_text,
+    g.title, g.slug
+FROM media b
+JOIN media a ON a.id = b.pair_id
+JOIN gallery_groups g ON g.id = b.gallery_group_id
+WHERE b.is_private = 0 AND a.is_private = 0
+ORDER BY g.is_featured DESC, g.sort_order, g.id, b.sort_order
+LIMIT ?
+`
+
+type ListShowcaseMediaPairsRow struct {
+	BeforeID      int64          `json:"before_id"`
+	BeforeUrl     string         `json:"before_url"`
+	BeforeAltText sql.NullString `json:"before_alt_text"`
+	AfterID       int64          `json:"after_id"`
+	AfterUrl      string         `json:"after_url"`
+	AfterAltText  sql.NullString `json:"after_alt_text"`
+	Title         string         `json:"title"`
+	Slug          string         `json:"slug"`
+}
+
+// Public before/after pairs for the homepage, featured projects first
+func (q *Queries) ListShowcaseMediaPairs(ctx context.Context, limit int64) ([]ListShowcaseMediaPairsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listShowcaseMediaPairs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListShowcaseMediaPairsRow
+	for rows.Next() {
+		var i ListShowcaseMediaPairsRow
+		if err := rows.Scan(
+			&i.BeforeID,
+			&i.BeforeUrl,
+			&i.BeforeAltText,
+			&i.AfterID,
+			&i.AfterUrl,
+			&i.AfterAltText,
+			&i.Title,
+			&i.Slug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSlotHolds = `-- name: ListSlotHolds :many
+SELECT slot_start FROM slot_holds
+WHERE status = 'held' AND expires_at > ? AND slot_start >= ? AND slot_start < ? AND token != ?
+`
+
+type ListSlotHoldsParams struct {
+	ExpiresAt   time.Time `json:"expires_at"`
+	SlotStart   time.Time `json:"slot_start"`
+	SlotStart_2 time.Time `json:"slot_start_2"`
+	Token       string    `json:"token"`
+}
+
+// Slots other visitors are holding; token is the asking visitor's own
+func (q *Queries) ListSlotHolds(ctx context.Context, arg ListSlotHoldsParams) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, listSlotHolds,
+		arg.ExpiresAt,
+		arg.SlotStart,
+		arg.SlotStart_2,
+		arg.Token,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var slot_start time.Time
+		if err := rows.Scan(&slot_start); err != nil {
+			return nil, err
+		}
+		items = append(items, slot_start)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStaff = `-- name: ListStaff :many
+
+SELECT id, name, email, phone, role, skills, is_active, created_at, updated_at FROM staff
+ORDER BY is_active DESC, name, id
+`
+
+// Staff queries
+func (q *Queries) ListStaff(ctx context.Context) ([]Staff, error) {
+	rows, err := q.db.QueryContext(ctx, listStaff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Staff
+	for rows.Next() {
+		var i Staff
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Phone,
+			&i.Role,
+			&i.Skills,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStaffBookings = `-- name: ListStaffBookings :many
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id FROM bookings
+WHERE staff_id = ?
+  AND requested_start >= ?
+  AND requested_start < ?
+  AND status IN ('pending', 'confirmed', 'completed')
+ORDER BY requested_start
+`
+
+type ListStaffBookingsParams struct {
+	StaffID          sql.NullInt64 `json:"staff_id"`
+	RequestedStart   time.Time     `json:"requested_start"`
+	RequestedStart_2 time.Time     `json:"requested_start_2"`
+}
+
+// A technician's jobs that take their slot, for their schedule
+func (q *Queries) ListStaffBookings(ctx context.Context, arg ListStaffBookingsParams) ([]Booking, error) {
+	rows, err := q.db.QueryContext(ctx, listStaffBookings, arg.StaffID, arg.RequestedStart, arg.RequestedStart_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Booking
+	for rows.Next() {
+		var i Booking
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.Status,
+			&i.Source,
+			&i.InternalNotes,
+			&i.ClerkUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromoCode,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.MembershipID,
+			&i.SeriesID,
+			&i.ServiceAddress,
+			&i.ServiceZip,
+			&i.ServiceLat,
+			&i.ServiceLng,
+			&i.TravelMinutes,
+			&i.TravelFee,
+			&i.StaffID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStaffHours = `-- name: ListStaffHours :many
+SELECT id, staff_id, weekday, start_minute, end_minute FROM staff_hours
+WHERE staff_id = ?
+ORDER BY weekday
+`
+
+func (q *Queries) ListStaffHours(ctx context.Context, staffID int64) ([]StaffHour, error) {
+	rows, err := q.db.QueryContext(ctx, listStaffHours, staffID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StaffHour
+	for rows.Next() {
+		var i StaffHour
+		if err := rows.Scan(
+			&i.ID,
+			&i.StaffID,
+			&i.Weekday,
+			&i.StartMinute,
+			&i.EndMinute,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStaffTimeOff = `-- name: ListStaffTimeOff :many
+SELECT id, staff_id, starts_at, ends_at, reason, created_at FROM staff_time_off
+WHERE staff_id = ? AND ends_at > ?
+ORDER BY starts_at
+`
+
+type ListStaffTimeOffParams struct {
+	StaffID int64     `json:"staff_id"`
+	EndsAt  time.Time `json:"ends_at"`
+}
+
+// Time off that hasn't ended by the given time
+func (q *Queries) ListStaffTimeOff(ctx context.Context, arg ListStaffTimeOffParams) ([]StaffTimeOff, error) {
+	rows, err := q.db.QueryContext(ctx, listStaffTimeOff, arg.StaffID, arg.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StaffTimeOff
+	for rows.Next() {
+		var i StaffTimeOff
+		if err := rows.Scan(
+			&i.ID,
+			&i.StaffID,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaxRates = `-- name: ListTaxRates :many
+
+SELECT id, name, rate, is_default, is_active, created_at FROM tax_rates
+ORDER BY is_default DESC, name, id
+`
+
+// Tax rate queries
+func (q *Queries) ListTaxRates(ctx context.Context) ([]TaxRate, error) {
+	rows, err := q.db.QueryContext(ctx, listTaxRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaxRate
+	for rows.Next() {
+		var i TaxRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Rate,
+			&i.IsDefault,
+			&i.IsActive,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTimeOffBetween = `-- name: ListTimeOffBetween :many
+SELECT id, staff_id, starts_at, ends_at, reason, created_at FROM staff_time_off
+WHERE ends_at > ? AND starts_at < ?
+`
+
+type ListTimeOffBetweenParams struct {
+	EndsAt   time.Time `json:"ends_at"`
+	StartsAt time.Time `json:"starts_at"`
+}
+
+// Everyone's time off overlapping a range
+func (q *Queries) ListTimeOffBetween(ctx context.Context, arg ListTimeOffBetweenParams) ([]StaffTimeOff, error) {
+	rows, err := q.db.QueryContext(ctx, listTimeOffBetween, arg.EndsAt, arg.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StaffTimeOff
+	for rows.Next() {
+		var i StaffTimeOff
+		if err := rows.Scan(
+			&i.ID,
+			&i.StaffID,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnreadContactMessages = `-- name: ListUnreadContactMessages :many
+SELECT id, name, email, phone, service_interest, message, ip_address, is_read, replied_at, booking_id, created_at FROM contact_messages
+WHERE is_read = 0
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+func (q *Queries) ListUnreadContactMessages(ctx context.Context, limit int64) ([]ContactMessage, error) {
+	rows, err := q.db.QueryContext(ctx, listUnreadContactMessages, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ContactMessage
+	for rows.Next() {
+		var i ContactMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Phone,
+			&i.ServiceInterest,
+			&i.Message,
+			&i.IpAddress,
+			&i.IsRead,
+			&i.RepliedAt,
+			&i.BookingID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUpcomingBookings = `-- name: ListUpcomingBookings :many
+SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id FROM bookings
+WHERE requested_start >= datetime('now')
+  AND status IN ('pending', 'confirmed')
+ORDER BY requested_start ASC
+LIMIT ?
+`
+
+func (q *Queries) ListUpcomingBookings(ctx context.Context, limit int64) ([]Booking, error) {
+	rows, err := q.db.QueryContext(ctx, listUpcomingBookings, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Booking
+	for rows.Next() {
+		var i Booking
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.RequestedStart,
+			&i.RequestedEnd,
+			&i.Status,
+			&i.Source,
+			&i.InternalNotes,
+			&i.ClerkUserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromoCode,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.MembershipID,
+			&i.SeriesID,
+			&i.ServiceAddress,
+			&i.ServiceZip,
+			&i.ServiceLat,
+			&i.ServiceLng,
+			&i.TravelMinutes,
+			&i.TravelFee,
+			&i.StaffID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWaitlistEntries = `-- name: ListWaitlistEntries :many
+SELECT e.id, e.customer_name, e.email, e.phone, e.vehicle_details, e.service_interest, e.notes, e.date_from, e.date_to, e.slot_id, e.clerk_user_id, e.status, e.booking_id, e.created_at, e.updated_at,
+    CAST((SELECT COUNT(*) FROM waitlist_offers o WHERE o.entry_id = e.id) AS INTEGER) AS offers
+FROM waitlist_entries e
+WHERE e.status = 'waiting' AND e.date_to > ?
+ORDER BY e.created_at ASC, e.id ASC
+`
+
+type ListWaitlistEntriesRow struct {
+	ID              int64          `json:"id"`
+	CustomerName    string         `json:"customer_name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	VehicleDetails  sql.NullString `json:"vehicle_details"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Notes           sql.NullString `json:"notes"`
+	DateFrom        time.Time      `json:"date_from"`
+	DateTo          time.Time      `json:"date_to"`
+	SlotID          sql.NullString `json:"slot_id"`
+	ClerkUserID     sql.NullString `json:"clerk_user_id"`
+	Status          string         `json:"status"`
+	BookingID       sql.NullInt64  `json:"booking_id"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	Offers          int64          `json:"offers"`
+}
+
+func (q *Queries) ListWaitlistEntries(ctx context.Context, dateTo time.Time) ([]ListWaitlistEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWaitlistEntries, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWaitlistEntriesRow
+	for rows.Next() {
+		var i ListWaitlistEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
 			&i.ServiceInterest,
 			&i.Notes,
-			&i.RequestedStart,
-			&i.RequestedEnd,
+			&i.DateFrom,
+			&i.DateTo,
+			&i.SlotID,
+			&i.ClerkUserID,
 			&i.Status,
-			&i.Source,
-			&i.InternalNotes,
+			&i.BookingID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Offers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWaitlistEntriesForSlot = `-- name: ListWaitlistEntriesForSlot :many
+SELECT e.id, e.customer_name, e.email, e.phone, e.vehicle_details, e.service_interest, e.notes, e.date_from, e.date_to, e.slot_id, e.clerk_user_id, e.status, e.booking_id, e.created_at, e.updated_at FROM waitlist_entries e
+WHERE e.status = 'waiting'
+  AND e.date_from <= ?
+  AND e.date_to > ?
+  AND (e.slot_id IS NULL OR e.slot_id = ?)
+  AND NOT EXISTS (
+    SELECT 1 FROM waitlist_offers o WHERE o.entry_id = e.id AND o.slot_start = ?
+  )
+ORDER BY e.created_at ASC, e.id ASC
+`
+
+type ListWaitlistEntriesForSlotParams struct {
+	SlotStart time.Time `json:"slot_start"`
+	SlotID    string    `json:"slot_id"`
+}
+
+// Who to offer a freed slot to next: entries waiting for that day and
+// slot that haven't been offered it before, first come first served.
+func (q *Queries) ListWaitlistEntriesForSlot(ctx context.Context, arg ListWaitlistEntriesForSlotParams) ([]WaitlistEntry, error) {
+	rows, err := q.db.QueryContext(ctx, listWaitlistEntriesForSlot,
+		arg.SlotStart,
+		arg.SlotStart,
+		arg.SlotID,
+		arg.SlotStart,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WaitlistEntry
+	for rows.Next() {
+		var i WaitlistEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerName,
+			&i.Email,
+			&i.Phone,
+			&i.VehicleDetails,
+			&i.ServiceInterest,
+			&i.Notes,
+			&i.DateFrom,
+			&i.DateTo,
+			&i.SlotID,
 			&i.ClerkUserID,
+			&i.Status,
+			&i.BookingID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -776,283 +7501,675 @@ func (q *Queries) ListBookingsByStatus(ctx context.Context, arg ListBookingsBySt
 	return items, nil
 }
 
-const listBookingsForCalendar = `-- name: ListBookingsForCalendar :many
-SELECT id, customer_name, email, phone, vehicle_details, service_interest, requested_start, requested_end, status
-FROM bookings
-WHERE requested_start >= ?
-  AND requested_start < ?
-ORDER BY requested_start ASC
+const listWaitlistHolds = `-- name: ListWaitlistHolds :many
+SELECT slot_start FROM waitlist_offers
+WHERE status = 'open' AND expires_at > ? AND slot_start >= ? AND slot_start < ?
+`
+
+type ListWaitlistHoldsParams struct {
+	ExpiresAt   time.Time `json:"expires_at"`
+	SlotStart   time.Time `json:"slot_start"`
+	SlotStart_2 time.Time `json:"slot_start_2"`
+}
+
+// Slots held for a waitlisted customer
+func (q *Queries) ListWaitlistHolds(ctx context.Context, arg ListWaitlistHoldsParams) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, listWaitlistHolds, arg.ExpiresAt, arg.SlotStart, arg.SlotStart_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var slot_start time.Time
+		if err := rows.Scan(&slot_start); err != nil {
+			return nil, err
+		}
+		items = append(items, slot_start)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markContactMessageReplied = `-- name: MarkContactMessageReplied :exec
+UPDATE contact_messages SET replied_at = CURRENT_TIMESTAMP, is_read = 1 WHERE id = ?
+`
+
+func (q *Queries) MarkContactMessageReplied(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markContactMessageReplied, id)
+	return err
+}
+
+const markInvoicePaid = `-- name: MarkInvoicePaid :execrows
+UPDATE invoices
+SET status = 'paid', paid_at = ?, payment_method = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'sent'
+`
+
+type MarkInvoicePaidParams struct {
+	PaidAt        sql.NullTime   `json:"paid_at"`
+	PaymentMethod sql.NullString `json:"payment_method"`
+	ID            int64          `json:"id"`
+}
+
+func (q *Queries) MarkInvoicePaid(ctx context.Context, arg MarkInvoicePaidParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markInvoicePaid, arg.PaidAt, arg.PaymentMethod, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const markInvoiceResent = `-- name: MarkInvoiceResent :exec
+UPDATE invoices
+SET sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) MarkInvoiceResent(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markInvoiceResent, id)
+	return err
+}
+
+const markPaymentPaid = `-- name: MarkPaymentPaid :execrows
+UPDATE payments
+SET status = 'paid', provider = ?, payment_ref = ?, paid_at = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status IN ('pending', 'failed', 'expired', 'void')
+`
+
+type MarkPaymentPaidParams struct {
+	Provider   string         `json:"provider"`
+	PaymentRef sql.NullString `json:"payment_ref"`
+	PaidAt     sql.NullTime   `json:"paid_at"`
+	ID         int64          `json:"id"`
+}
+
+// Money can arrive after a checkout was given up on, or be taken in
+// person, so closed unpaid payments can still be marked paid
+func (q *Queries) MarkPaymentPaid(ctx context.Context, arg MarkPaymentPaidParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markPaymentPaid,
+		arg.Provider,
+		arg.PaymentRef,
+		arg.PaidAt,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const markQuoteSent = `-- name: MarkQuoteSent :exec
+UPDATE quotes
+SET status = 'sent', version = ?, expires_at = ?, sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type MarkQuoteSentParams struct {
+	Version   sql.NullInt64 `json:"version"`
+	ExpiresAt sql.NullTime  `json:"expires_at"`
+	ID        int64         `json:"id"`
+}
+
+func (q *Queries) MarkQuoteSent(ctx context.Context, arg MarkQuoteSentParams) error {
+	_, err := q.db.ExecContext(ctx, markQuoteSent, arg.Version, arg.ExpiresAt, arg.ID)
+	return err
+}
+
+const markReviewRequestSent = `-- name: MarkReviewRequestSent :exec
+UPDATE review_requests SET sent_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+func (q *Queries) MarkReviewRequestSent(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markReviewRequestSent, id)
+	return err
+}
+
+const markWaitlistEntryBooked = `-- name: MarkWaitlistEntryBooked :exec
+UPDATE waitlist_entries
+SET status = 'booked', booking_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type MarkWaitlistEntryBookedParams struct {
+	BookingID sql.NullInt64 `json:"booking_id"`
+	ID        int64         `json:"id"`
+}
+
+func (q *Queries) MarkWaitlistEntryBooked(ctx context.Context, arg MarkWaitlistEntryBookedParams) error {
+	_, err := q.db.ExecContext(ctx, markWaitlistEntryBooked, arg.BookingID, arg.ID)
+	return err
+}
+
+const nextInvoiceNumber = `-- name: NextInvoiceNumber :one
+INSERT INTO invoice_sequences (year, last_number)
+VALUES (?, 1)
+ON CONFLICT(year) DO UPDATE SET last_number = last_number + 1
+RETURNING last_number
+`
+
+func (q *Queries) NextInvoiceNumber(ctx context.Context, year int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextInvoiceNumber, year)
+	var last_number int64
+	err := row.Scan(&last_number)
+	return last_number, err
+}
+
+const recordPaymentEvent = `-- name: RecordPaymentEvent :execrows
+INSERT INTO payment_events (id, type) VALUES (?, ?)
+ON CONFLICT(id) DO NOTHING
+`
+
+type RecordPaymentEventParams struct {
+	ID   string         `json:"id"`
+	Type sql.NullString `json:"type"`
+}
+
+func (q *Queries) RecordPaymentEvent(ctx context.Context, arg RecordPaymentEventParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordPaymentEvent, arg.ID, arg.Type)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const releaseSlotHold = `-- name: ReleaseSlotHold :execrows
+UPDATE slot_holds
+SET status = 'released', updated_at = CURRENT_TIMESTAMP
+WHERE token = ? AND status = 'held'
+`
+
+func (q *Queries) ReleaseSlotHold(ctx context.Context, token string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, releaseSlotHold, token)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const removeWaitlistEntry = `-- name: RemoveWaitlistEntry :execrows
+UPDATE waitlist_entries
+SET status = 'removed', updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'waiting'
+`
+
+func (q *Queries) RemoveWaitlistEntry(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeWaitlistEntry, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renewMembership = `-- name: RenewMembership :execrows
+UPDATE memberships
+SET paid_through = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'active'
+`
+
+type RenewMembershipParams struct {
+	PaidThrough time.Time `json:"paid_through"`
+	ID          int64     `json:"id"`
+}
+
+func (q *Queries) RenewMembership(ctx context.Context, arg RenewMembershipParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renewMembership, arg.PaidThrough, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reopenQuoteForBooking = `-- name: ReopenQuoteForBooking :execrows
+UPDATE quotes
+SET status = 'sent', booking_id = NULL, accepted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE booking_id = ? AND status = 'accepted'
+`
+
+func (q *Queries) ReopenQuoteForBooking(ctx context.Context, bookingID sql.NullInt64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, reopenQuoteForBooking, bookingID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resolveReviewFollowUp = `-- name: ResolveReviewFollowUp :exec
+UPDATE reviews
+SET follow_up_status = 'resolved', follow_up_notes = ?
+WHERE id = ? AND follow_up_status = 'open'
+`
+
+type ResolveReviewFollowUpParams struct {
+	FollowUpNotes sql.NullString `json:"follow_up_notes"`
+	ID            int64          `json:"id"`
+}
+
+func (q *Queries) ResolveReviewFollowUp(ctx context.Context, arg ResolveReviewFollowUpParams) error {
+	_, err := q.db.ExecContext(ctx, resolveReviewFollowUp, arg.FollowUpNotes, arg.ID)
+	return err
+}
+
+const resumeMembership = `-- name: ResumeMembership :execrows
+UPDATE memberships
+SET status = 'active', cancelled_at = NULL, cancel_reason = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'cancelled'
+`
+
+func (q *Queries) ResumeMembership(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resumeMembership, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reviseQuote = `-- name: ReviseQuote :exec
+UPDATE quotes
+SET status = 'draft', updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'sent'
+`
+
+func (q *Queries) ReviseQuote(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, reviseQuote, id)
+	return err
+}
+
+const setBookingMembership = `-- name: SetBookingMembership :exec
+UPDATE bookings SET membership_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type SetBookingMembershipParams struct {
+	MembershipID sql.NullInt64 `json:"membership_id"`
+	ID           int64         `json:"id"`
+}
+
+func (q *Queries) SetBookingMembership(ctx context.Context, arg SetBookingMembershipParams) error {
+	_, err := q.db.ExecContext(ctx, setBookingMembership, arg.MembershipID, arg.ID)
+	return err
+}
+
+const setBookingPromo = `-- name: SetBookingPromo :exec
+UPDATE bookings
+SET promo_code = ?, discount_type = ?, discount_value = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type SetBookingPromoParams struct {
+	PromoCode     sql.NullString `json:"promo_code"`
+	DiscountType  sql.NullString `json:"discount_type"`
+	DiscountValue int64          `json:"discount_value"`
+	ID            int64          `json:"id"`
+}
+
+func (q *Queries) SetBookingPromo(ctx context.Context, arg SetBookingPromoParams) error {
+	_, err := q.db.ExecContext(ctx, setBookingPromo,
+		arg.PromoCode,
+		arg.DiscountType,
+		arg.DiscountValue,
+		arg.ID,
+	)
+	return err
+}
+
+const setBookingSeries = `-- name: SetBookingSeries :exec
+UPDATE bookings SET series_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type SetBookingSeriesParams struct {
+	SeriesID sql.NullInt64 `json:"series_id"`
+	ID       int64         `json:"id"`
+}
+
+func (q *Queries) SetBookingSeries(ctx context.Context, arg SetBookingSeriesParams) error {
+	_, err := q.db.ExecContext(ctx, setBookingSeries, arg.SeriesID, arg.ID)
+	return err
+}
+
+const setBookingServiceLocation = `-- name: SetBookingServiceLocation :exec
+
+UPDATE bookings
+SET service_address = ?, service_zip = ?, service_lat = ?, service_lng = ?,
+    travel_minutes = ?, travel_fee = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type SetBookingServiceLocationParams struct {
+	ServiceAddress sql.NullString  `json:"service_address"`
+	ServiceZip     sql.NullString  `json:"service_zip"`
+	ServiceLat     sql.NullFloat64 `json:"service_lat"`
+	ServiceLng     sql.NullFloat64 `json:"service_lng"`
+	TravelMinutes  int64           `json:"travel_minutes"`
+	TravelFee      int64           `json:"travel_fee"`
+	ID             int64           `json:"id"`
+}
+
+// Mobile service queries
+func (q *Queries) SetBookingServiceLocation(ctx context.Context, arg SetBookingServiceLocationParams) error {
+	_, err := q.db.ExecContext(ctx, setBookingServiceLocation,
+		arg.ServiceAddress,
+		arg.ServiceZip,
+		arg.ServiceLat,
+		arg.ServiceLng,
+		arg.TravelMinutes,
+		arg.TravelFee,
+		arg.ID,
+	)
+	return err
+}
+
+const setContactMessageBooking = `-- name: SetContactMessageBooking :exec
+UPDATE contact_messages SET booking_id = ?, is_read = 1 WHERE id = ?
+`
+
+type SetContactMessageBookingParams struct {
+	BookingID sql.NullInt64 `json:"booking_id"`
+	ID        int64         `json:"id"`
+}
+
+func (q *Queries) SetContactMessageBooking(ctx context.Context, arg SetContactMessageBookingParams) error {
+	_, err := q.db.ExecContext(ctx, setContactMessageBooking, arg.BookingID, arg.ID)
+	return err
+}
+
+const setContactMessageRead = `-- name: SetContactMessageRead :exec
+UPDATE contact_messages SET is_read = ? WHERE id = ?
+`
+
+type SetContactMessageReadParams struct {
+	IsRead sql.NullBool `json:"is_read"`
+	ID     int64        `json:"id"`
+}
+
+func (q *Queries) SetContactMessageRead(ctx context.Context, arg SetContactMessageReadParams) error {
+	_, err := q.db.ExecContext(ctx, setContactMessageRead, arg.IsRead, arg.ID)
+	return err
+}
+
+const setGalleryGroupPackage = `-- name: SetGalleryGroupPackage :exec
+UPDATE gallery_groups SET package_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type SetGalleryGroupPackageParams struct {
+	PackageID sql.NullInt64 `json:"package_id"`
+	ID        int64         `json:"id"`
+}
+
+func (q *Queries) SetGalleryGroupPackage(ctx context.Context, arg SetGalleryGroupPackageParams) error {
+	_, err := q.db.ExecContext(ctx, setGalleryGroupPackage, arg.PackageID, arg.ID)
+	return err
+}
+
+const setGiftCertificatePayment = `-- name: SetGiftCertificatePayment :exec
+UPDATE gift_certificates
+SET payment_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type SetGiftCertificatePaymentParams struct {
+	PaymentID sql.NullInt64 `json:"payment_id"`
+	ID        int64         `json:"id"`
+}
+
+func (q *Queries) SetGiftCertificatePayment(ctx context.Context, arg SetGiftCertificatePaymentParams) error {
+	_, err := q.db.ExecContext(ctx, setGiftCertificatePayment, arg.PaymentID, arg.ID)
+	return err
+}
+
+const setMediaKind = `-- name: SetMediaKind :exec
+UPDATE media SET kind = ? WHERE id = ?
 `
 
-type ListBookingsForCalendarParams struct {
-	RequestedStart   time.Time `json:"requested_start"`
-	RequestedStart_2 time.Time `json:"requested_start_2"`
+type SetMediaKindParams struct {
+	Kind sql.NullString `json:"kind"`
+	ID   int64          `json:"id"`
 }
 
-type ListBookingsForCalendarRow struct {
-	ID              int64          `json:"id"`
-	CustomerName    string         `json:"customer_name"`
-	Email           string         `json:"email"`
-	Phone           sql.NullString `json:"phone"`
-	VehicleDetails  sql.NullString `json:"vehicle_details"`
-	ServiceInterest sql.NullString `json:"service_interest"`
-	RequestedStart  time.Time      `json:"requested_start"`
-	RequestedEnd    time.Time      `json:"requested_end"`
-	Status          sql.NullString `json:"status"`
+func (q *Queries) SetMediaKind(ctx context.Context, arg SetMediaKindParams) error {
+	_, err := q.db.ExecContext(ctx, setMediaKind, arg.Kind, arg.ID)
+	return err
 }
 
-func (q *Queries) ListBookingsForCalendar(ctx context.Context, arg ListBookingsForCalendarParams) ([]ListBookingsForCalendarRow, error) {
-	rows, err := q.db.QueryContext(ctx, listBookingsForCalendar, arg.RequestedStart, arg.RequestedStart_2)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ListBookingsForCalendarRow
-	for rows.Next() {
-		var i ListBookingsForCalendarRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.CustomerName,
-			&i.Email,
-			&i.Phone,
-			&i.VehicleDetails,
-			&i.ServiceInterest,
-			&i.RequestedStart,
-			&i.RequestedEnd,
-			&i.Status,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const setMediaPair = `-- name: SetMediaPair :exec
+UPDATE media SET pair_id = ? WHERE id = ?
+`
+
+type SetMediaPairParams struct {
+	AfterID  int64 `json:"after_id"`
+	BeforeID int64 `json:"before_id"`
 }
 
-const listFeaturedGalleryGroups = `-- name: ListFeaturedGalleryGroups :many
-SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at FROM gallery_groups
-WHERE is_featured = 1
-ORDER BY sort_order, created_at DESC
-LIMIT ?
+func (q *Queries) SetMediaPair(ctx context.Context, arg SetMediaPairParams) error {
+	_, err := q.db.ExecContext(ctx, setMediaPair, arg.AfterID, arg.BeforeID)
+	return err
+}
+
+const setPaymentCheckout = `-- name: SetPaymentCheckout :exec
+UPDATE payments
+SET checkout_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending'
 `
 
-func (q *Queries) ListFeaturedGalleryGroups(ctx context.Context, limit int64) ([]GalleryGroup, error) {
-	rows, err := q.db.QueryContext(ctx, listFeaturedGalleryGroups, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GalleryGroup
-	for rows.Next() {
-		var i GalleryGroup
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Slug,
-			&i.VehicleMake,
-			&i.VehicleModel,
-			&i.VehicleYear,
-			&i.Description,
-			&i.IsFeatured,
-			&i.SortOrder,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type SetPaymentCheckoutParams struct {
+	CheckoutID sql.NullString `json:"checkout_id"`
+	ID         int64          `json:"id"`
 }
 
-const listFeaturedReviews = `-- name: ListFeaturedReviews :many
-SELECT id, author, rating, body, source, is_featured, created_at FROM reviews
-WHERE is_featured = 1
-ORDER BY created_at DESC
-LIMIT ?
+func (q *Queries) SetPaymentCheckout(ctx context.Context, arg SetPaymentCheckoutParams) error {
+	_, err := q.db.ExecContext(ctx, setPaymentCheckout, arg.CheckoutID, arg.ID)
+	return err
+}
+
+const setQuotePromo = `-- name: SetQuotePromo :exec
+UPDATE quotes
+SET promo_code_id = ?, promo_code = ?, discount_type = ?, discount_value = ?,
+    status = CASE WHEN status = 'sent' THEN 'draft' ELSE status END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status IN ('draft', 'sent')
 `
 
-func (q *Queries) ListFeaturedReviews(ctx context.Context, limit int64) ([]Review, error) {
-	rows, err := q.db.QueryContext(ctx, listFeaturedReviews, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Review
-	for rows.Next() {
-		var i Review
-		if err := rows.Scan(
-			&i.ID,
-			&i.Author,
-			&i.Rating,
-			&i.Body,
-			&i.Source,
-			&i.IsFeatured,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type SetQuotePromoParams struct {
+	PromoCodeID   sql.NullInt64  `json:"promo_code_id"`
+	PromoCode     sql.NullString `json:"promo_code"`
+	DiscountType  sql.NullString `json:"discount_type"`
+	DiscountValue int64          `json:"discount_value"`
+	ID            int64          `json:"id"`
 }
 
-const listGalleryGroups = `-- name: ListGalleryGroups :many
+// Applying or removing a promo code changes the price, so a sent quote
+// goes back to draft like any other edit
+func (q *Queries) SetQuotePromo(ctx context.Context, arg SetQuotePromoParams) error {
+	_, err := q.db.ExecContext(ctx, setQuotePromo,
+		arg.PromoCodeID,
+		arg.PromoCode,
+		arg.DiscountType,
+		arg.DiscountValue,
+		arg.ID,
+	)
+	return err
+}
 
-SELECT id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at FROM gallery_groups
-ORDER BY sort_order, created_at DESC
-LIMIT ? OFFSET ?
+const setReviewFeatured = `-- name: SetReviewFeatured :exec
+UPDATE reviews SET is_featured = ? WHERE id = ?
 `
 
-type ListGalleryGroupsParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+type SetReviewFeaturedParams struct {
+	IsFeatured sql.NullBool `json:"is_featured"`
+	ID         int64        `json:"id"`
 }
 
-// Gallery queries
-func (q *Queries) ListGalleryGroups(ctx context.Context, arg ListGalleryGroupsParams) ([]GalleryGroup, error) {
-	rows, err := q.db.QueryContext(ctx, listGalleryGroups, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GalleryGroup
-	for rows.Next() {
-		var i GalleryGroup
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Slug,
-			&i.VehicleMake,
-			&i.VehicleModel,
-			&i.VehicleYear,
-			&i.Description,
-			&i.IsFeatured,
-			&i.SortOrder,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) SetReviewFeatured(ctx context.Context, arg SetReviewFeaturedParams) error {
+	_, err := q.db.ExecContext(ctx, setReviewFeatured, arg.IsFeatured, arg.ID)
+	return err
 }
 
-const listReviews = `-- name: ListReviews :many
+const setReviewStatus = `-- name: SetReviewStatus :exec
+UPDATE reviews
+SET status = ?,
+    is_featured = CASE WHEN CAST(? AS TEXT) = 'approved' THEN is_featured ELSE 0 END
+WHERE id = ?
+`
 
-SELECT id, author, rating, body, source, is_featured, created_at FROM reviews
-ORDER BY created_at DESC
-LIMIT ?
+type SetReviewStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+// Rejecting or un-approving a review also takes it off the home page
+func (q *Queries) SetReviewStatus(ctx context.Context, arg SetReviewStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setReviewStatus, arg.Status, arg.Status, arg.ID)
+	return err
+}
+
+const setSeriesBookedThrough = `-- name: SetSeriesBookedThrough :exec
+UPDATE booking_series SET booked_through = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
 `
 
-// Review queries
-func (q *Queries) ListReviews(ctx context.Context, limit int64) ([]Review, error) {
-	rows, err := q.db.QueryContext(ctx, listReviews, limit)
+type SetSeriesBookedThroughParams struct {
+	BookedThrough time.Time `json:"booked_through"`
+	ID            int64     `json:"id"`
+}
+
+func (q *Queries) SetSeriesBookedThrough(ctx context.Context, arg SetSeriesBookedThroughParams) error {
+	_, err := q.db.ExecContext(ctx, setSeriesBookedThrough, arg.BookedThrough, arg.ID)
+	return err
+}
+
+const setSeriesRule = `-- name: SetSeriesRule :exec
+UPDATE booking_series SET rrule = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type SetSeriesRuleParams struct {
+	Rrule string `json:"rrule"`
+	ID    int64  `json:"id"`
+}
+
+func (q *Queries) SetSeriesRule(ctx context.Context, arg SetSeriesRuleParams) error {
+	_, err := q.db.ExecContext(ctx, setSeriesRule, arg.Rrule, arg.ID)
+	return err
+}
+
+const settlePayment = `-- name: SettlePayment :execrows
+UPDATE payments
+SET status = ?, refund_ref = ?, refunded_amount = ?, refunded_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'paid'
+`
+
+type SettlePaymentParams struct {
+	Status         string         `json:"status"`
+	RefundRef      sql.NullString `json:"refund_ref"`
+	RefundedAmount int64          `json:"refunded_amount"`
+	ID             int64          `json:"id"`
+}
+
+// Settles a paid deposit on cancellation: refunded, or forfeited with a
+// zero refund when it falls inside the policy window
+func (q *Queries) SettlePayment(ctx context.Context, arg SettlePaymentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, settlePayment,
+		arg.Status,
+		arg.RefundRef,
+		arg.RefundedAmount,
+		arg.ID,
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Review
-	for rows.Next() {
-		var i Review
-		if err := rows.Scan(
-			&i.ID,
-			&i.Author,
-			&i.Rating,
-			&i.Body,
-			&i.Source,
-			&i.IsFeatured,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return result.RowsAffected()
 }
 
-const listUpcomingBookings = `-- name: ListUpcomingBookings :many
-SELECT id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at FROM bookings
-WHERE requested_start >= datetime('now')
-  AND status IN ('pending', 'confirmed')
-ORDER BY requested_start ASC
-LIMIT ?
+const unpairMedia = `-- name: UnpairMedia :exec
+UPDATE media SET pair_id = NULL WHERE id = ? OR pair_id = ?
+`
+
+func (q *Queries) UnpairMedia(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, unpairMedia, id, id)
+	return err
+}
+
+const updateAddon = `-- name: UpdateAddon :exec
+UPDATE addons
+SET name = ?, description = ?, price = ?, is_active = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateAddonParams struct {
+	Name        string         `json:"name"`
+	Description sql.NullString `json:"description"`
+	Price       int64          `json:"price"`
+	IsActive    sql.NullBool   `json:"is_active"`
+	SortOrder   sql.NullInt64  `json:"sort_order"`
+	ID          int64          `json:"id"`
+}
+
+func (q *Queries) UpdateAddon(ctx context.Context, arg UpdateAddonParams) error {
+	_, err := q.db.ExecContext(ctx, updateAddon,
+		arg.Name,
+		arg.Description,
+		arg.Price,
+		arg.IsActive,
+		arg.SortOrder,
+		arg.ID,
+	)
+	return err
+}
+
+const updateBookingSeries = `-- name: UpdateBookingSeries :exec
+UPDATE booking_series
+SET customer_name = ?,
+    email = ?,
+    phone = ?,
+    vehicle_details = ?,
+    service_interest = ?,
+    notes = ?,
+    rrule = ?,
+    starts_at = ?,
+    duration_minutes = ?,
+    booked_through = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
 `
 
-func (q *Queries) ListUpcomingBookings(ctx context.Context, limit int64) ([]Booking, error) {
-	rows, err := q.db.QueryContext(ctx, listUpcomingBookings, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Booking
-	for rows.Next() {
-		var i Booking
-		if err := rows.Scan(
-			&i.ID,
-			&i.CustomerName,
-			&i.Email,
-			&i.Phone,
-			&i.VehicleDetails,
-			&i.ServiceInterest,
-			&i.Notes,
-			&i.RequestedStart,
-			&i.RequestedEnd,
-			&i.Status,
-			&i.Source,
-			&i.InternalNotes,
-			&i.ClerkUserID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type UpdateBookingSeriesParams struct {
+	CustomerName    string         `json:"customer_name"`
+	Email           string         `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	VehicleDetails  sql.NullString `json:"vehicle_details"`
+	ServiceInterest sql.NullString `json:"service_interest"`
+	Notes           sql.NullString `json:"notes"`
+	Rrule           string         `json:"rrule"`
+	StartsAt        time.Time      `json:"starts_at"`
+	DurationMinutes int64          `json:"duration_minutes"`
+	BookedThrough   time.Time      `json:"booked_through"`
+	ID              int64          `json:"id"`
+}
+
+func (q *Queries) UpdateBookingSeries(ctx context.Context, arg UpdateBookingSeriesParams) error {
+	_, err := q.db.ExecContext(ctx, updateBookingSeries,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.VehicleDetails,
+		arg.ServiceInterest,
+		arg.Notes,
+		arg.Rrule,
+		arg.StartsAt,
+		arg.DurationMinutes,
+		arg.BookedThrough,
+		arg.ID,
+	)
+	return err
 }
 
 const updateBookingStatus = `-- name: UpdateBookingStatus :one
 UPDATE bookings
 SET status = ?, internal_notes = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at
+RETURNING id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id
 `
 
 type UpdateBookingStatusParams struct {
@@ -1080,15 +8197,75 @@ func (q *Queries) UpdateBookingStatus(ctx context.Context, arg UpdateBookingStat
 		&i.ClerkUserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PromoCode,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.MembershipID,
+		&i.SeriesID,
+		&i.ServiceAddress,
+		&i.ServiceZip,
+		&i.ServiceLat,
+		&i.ServiceLng,
+		&i.TravelMinutes,
+		&i.TravelFee,
+		&i.StaffID,
+	)
+	return i, err
+}
+
+const updateBookingTime = `-- name: UpdateBookingTime :one
+UPDATE bookings
+SET requested_start = ?, requested_end = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, customer_name, email, phone, vehicle_details, service_interest, notes, requested_start, requested_end, status, source, internal_notes, clerk_user_id, created_at, updated_at, promo_code, discount_type, discount_value, membership_id, series_id, service_address, service_zip, service_lat, service_lng, travel_minutes, travel_fee, staff_id
+`
+
+type UpdateBookingTimeParams struct {
+	RequestedStart time.Time `json:"requested_start"`
+	RequestedEnd   time.Time `json:"requested_end"`
+	ID             int64     `json:"id"`
+}
+
+func (q *Queries) UpdateBookingTime(ctx context.Context, arg UpdateBookingTimeParams) (Booking, error) {
+	row := q.db.QueryRowContext(ctx, updateBookingTime, arg.RequestedStart, arg.RequestedEnd, arg.ID)
+	var i Booking
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerName,
+		&i.Email,
+		&i.Phone,
+		&i.VehicleDetails,
+		&i.ServiceInterest,
+		&i.Notes,
+		&i.RequestedStart,
+		&i.RequestedEnd,
+		&i.Status,
+		&i.Source,
+		&i.InternalNotes,
+		&i.ClerkUserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PromoCode,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.MembershipID,
+		&i.SeriesID,
+		&i.ServiceAddress,
+		&i.ServiceZip,
+		&i.ServiceLat,
+		&i.ServiceLng,
+		&i.TravelMinutes,
+		&i.TravelFee,
+		&i.StaffID,
 	)
 	return i, err
 }
 
 const updateGalleryGroup = `-- name: UpdateGalleryGroup :one
 UPDATE gallery_groups
-SET title = ?, slug = ?, vehicle_make = ?, vehicle_model = ?, vehicle_year = ?, description = ?, is_featured = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP
+SET title = ?, slug = ?, vehicle_make = ?, vehicle_model = ?, vehicle_year = ?, description = ?, is_featured = ?, sort_order = ?, package_id = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at
+RETURNING id, title, slug, vehicle_make, vehicle_model, vehicle_year, description, is_featured, sort_order, created_at, updated_at, package_id
 `
 
 type UpdateGalleryGroupParams struct {
@@ -1100,6 +8277,7 @@ type UpdateGalleryGroupParams struct {
 	Description  sql.NullString `json:"description"`
 	IsFeatured   sql.NullBool   `json:"is_featured"`
 	SortOrder    sql.NullInt64  `json:"sort_order"`
+	PackageID    sql.NullInt64  `json:"package_id"`
 	ID           int64          `json:"id"`
 }
 
@@ -1113,6 +8291,7 @@ func (q *Queries) UpdateGalleryGroup(ctx context.Context, arg UpdateGalleryGroup
 		arg.Description,
 		arg.IsFeatured,
 		arg.SortOrder,
+		arg.PackageID,
 		arg.ID,
 	)
 	var i GalleryGroup
@@ -1128,15 +8307,55 @@ func (q *Queries) UpdateGalleryGroup(ctx context.Context, arg UpdateGalleryGroup
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PackageID,
 	)
 	return i, err
 }
 
+const updateInvoiceDetails = `-- name: UpdateInvoiceDetails :exec
+UPDATE invoices
+SET customer_name = ?, email = ?, phone = ?, vehicle_details = ?, notes = ?, due_at = ?,
+    tax_name = ?, tax_rate = ?, discount_type = ?, discount_value = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'draft'
+`
+
+type UpdateInvoiceDetailsParams struct {
+	CustomerName   string         `json:"customer_name"`
+	Email          string         `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	VehicleDetails sql.NullString `json:"vehicle_details"`
+	Notes          sql.NullString `json:"notes"`
+	DueAt          sql.NullTime   `json:"due_at"`
+	TaxName        sql.NullString `json:"tax_name"`
+	TaxRate        int64          `json:"tax_rate"`
+	DiscountType   sql.NullString `json:"discount_type"`
+	DiscountValue  int64          `json:"discount_value"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) UpdateInvoiceDetails(ctx context.Context, arg UpdateInvoiceDetailsParams) error {
+	_, err := q.db.ExecContext(ctx, updateInvoiceDetails,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.VehicleDetails,
+		arg.Notes,
+		arg.DueAt,
+		arg.TaxName,
+		arg.TaxRate,
+		arg.DiscountType,
+		arg.DiscountValue,
+		arg.ID,
+	)
+	return err
+}
+
 const updateMedia = `-- name: UpdateMedia :one
 UPDATE media
 SET url = ?, kind = ?, sort_order = ?, alt_text = ?
 WHERE id = ?
-RETURNING id, gallery_group_id, url, kind, sort_order, alt_text, created_at
+RETURNING id, gallery_group_id, url, kind, sort_order, alt_text, is_private, pair_id, created_at
 `
 
 type UpdateMediaParams struct {
@@ -1163,29 +8382,81 @@ func (q *Queries) UpdateMedia(ctx context.Context, arg UpdateMediaParams) (Mediu
 		&i.Kind,
 		&i.SortOrder,
 		&i.AltText,
+		&i.IsPrivate,
+		&i.PairID,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const updateMembershipNotes = `-- name: UpdateMembershipNotes :exec
+UPDATE memberships SET notes = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateMembershipNotesParams struct {
+	Notes sql.NullString `json:"notes"`
+	ID    int64          `json:"id"`
+}
+
+func (q *Queries) UpdateMembershipNotes(ctx context.Context, arg UpdateMembershipNotesParams) error {
+	_, err := q.db.ExecContext(ctx, updateMembershipNotes, arg.Notes, arg.ID)
+	return err
+}
+
+const updateMembershipPlan = `-- name: UpdateMembershipPlan :exec
+UPDATE membership_plans
+SET name = ?, description = ?, package_id = ?, services_per_period = ?, period_months = ?, price = ?, is_active = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateMembershipPlanParams struct {
+	Name              string         `json:"name"`
+	Description       sql.NullString `json:"description"`
+	PackageID         sql.NullInt64  `json:"package_id"`
+	ServicesPerPeriod int64          `json:"services_per_period"`
+	PeriodMonths      int64          `json:"period_months"`
+	Price             int64          `json:"price"`
+	IsActive          sql.NullBool   `json:"is_active"`
+	ID                int64          `json:"id"`
+}
+
+func (q *Queries) UpdateMembershipPlan(ctx context.Context, arg UpdateMembershipPlanParams) error {
+	_, err := q.db.ExecContext(ctx, updateMembershipPlan,
+		arg.Name,
+		arg.Description,
+		arg.PackageID,
+		arg.ServicesPerPeriod,
+		arg.PeriodMonths,
+		arg.Price,
+		arg.IsActive,
+		arg.ID,
+	)
+	return err
+}
+
 const updatePackage = `-- name: UpdatePackage :one
 UPDATE packages
-SET slug = ?, name = ?, short_desc = ?, long_desc = ?, price_min = ?, price_max = ?, duration_est = ?, is_active = ?, sort_order = ?, updated_at = CURRENT_TIMESTAMP
+SET slug = ?, name = ?, short_desc = ?, long_desc = ?, price_min = ?, price_max = ?, duration_est = ?, is_active = ?, sort_order = ?, features = ?, included = ?, excluded = ?, required_skills = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at
+RETURNING id, slug, name, short_desc, long_desc, price_min, price_max, duration_est, is_active, sort_order, created_at, updated_at, features, included, excluded, required_skills
 `
 
 type UpdatePackageParams struct {
-	Slug        string         `json:"slug"`
-	Name        string         `json:"name"`
-	ShortDesc   sql.NullString `json:"short_desc"`
-	LongDesc    sql.NullString `json:"long_desc"`
-	PriceMin    sql.NullInt64  `json:"price_min"`
-	PriceMax    sql.NullInt64  `json:"price_max"`
-	DurationEst sql.NullInt64  `json:"duration_est"`
-	IsActive    sql.NullBool   `json:"is_active"`
-	SortOrder   sql.NullInt64  `json:"sort_order"`
-	ID          int64          `json:"id"`
+	Slug           string         `json:"slug"`
+	Name           string         `json:"name"`
+	ShortDesc      sql.NullString `json:"short_desc"`
+	LongDesc       sql.NullString `json:"long_desc"`
+	PriceMin       sql.NullInt64  `json:"price_min"`
+	PriceMax       sql.NullInt64  `json:"price_max"`
+	DurationEst    sql.NullInt64  `json:"duration_est"`
+	IsActive       sql.NullBool   `json:"is_active"`
+	SortOrder      sql.NullInt64  `json:"sort_order"`
+	Features       sql.NullString `json:"features"`
+	Included       sql.NullString `json:"included"`
+	Excluded       sql.NullString `json:"excluded"`
+	RequiredSkills sql.NullString `json:"required_skills"`
+	ID             int64          `json:"id"`
 }
 
 func (q *Queries) UpdatePackage(ctx context.Context, arg UpdatePackageParams) (Package, error) {
@@ -1199,6 +8470,10 @@ func (q *Queries) UpdatePackage(ctx context.Context, arg UpdatePackageParams) (P
 		arg.DurationEst,
 		arg.IsActive,
 		arg.SortOrder,
+		arg.Features,
+		arg.Included,
+		arg.Excluded,
+		arg.RequiredSkills,
 		arg.ID,
 	)
 	var i Package
@@ -1215,6 +8490,277 @@ func (q *Queries) UpdatePackage(ctx context.Context, arg UpdatePackageParams) (P
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Features,
+		&i.Included,
+		&i.Excluded,
+		&i.RequiredSkills,
+	)
+	return i, err
+}
+
+const updatePackageFAQ = `-- name: UpdatePackageFAQ :exec
+UPDATE package_faqs
+SET question = ?, answer = ?, sort_order = ?
+WHERE id = ? AND package_id = ?
+`
+
+type UpdatePackageFAQParams struct {
+	Question  string        `json:"question"`
+	Answer    string        `json:"answer"`
+	SortOrder sql.NullInt64 `json:"sort_order"`
+	ID        int64         `json:"id"`
+	PackageID int64         `json:"package_id"`
+}
+
+func (q *Queries) UpdatePackageFAQ(ctx context.Context, arg UpdatePackageFAQParams) error {
+	_, err := q.db.ExecContext(ctx, updatePackageFAQ,
+		arg.Question,
+		arg.Answer,
+		arg.SortOrder,
+		arg.ID,
+		arg.PackageID,
+	)
+	return err
+}
+
+const updatePromoCode = `-- name: UpdatePromoCode :exec
+UPDATE promo_codes
+SET code = ?, description = ?, discount_type = ?, discount_value = ?, starts_at = ?, ends_at = ?,
+    max_uses = ?, max_uses_per_customer = ?, min_spend = ?, is_active = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdatePromoCodeParams struct {
+	Code               string         `json:"code"`
+	Description        sql.NullString `json:"description"`
+	DiscountType       string         `json:"discount_type"`
+	DiscountValue      int64          `json:"discount_value"`
+	StartsAt           sql.NullTime   `json:"starts_at"`
+	EndsAt             sql.NullTime   `json:"ends_at"`
+	MaxUses            int64          `json:"max_uses"`
+	MaxUsesPerCustomer int64          `json:"max_uses_per_customer"`
+	MinSpend           int64          `json:"min_spend"`
+	IsActive           sql.NullBool   `json:"is_active"`
+	ID                 int64          `json:"id"`
+}
+
+func (q *Queries) UpdatePromoCode(ctx context.Context, arg UpdatePromoCodeParams) error {
+	_, err := q.db.ExecContext(ctx, updatePromoCode,
+		arg.Code,
+		arg.Description,
+		arg.DiscountType,
+		arg.DiscountValue,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.MaxUses,
+		arg.MaxUsesPerCustomer,
+		arg.MinSpend,
+		arg.IsActive,
+		arg.ID,
+	)
+	return err
+}
+
+const updateQuoteDetails = `-- name: UpdateQuoteDetails :exec
+UPDATE quotes
+SET customer_name = ?, email = ?, phone = ?, vehicle_details = ?, notes = ?, expires_at = ?,
+    status = CASE WHEN status = 'sent' THEN 'draft' ELSE status END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status IN ('draft', 'sent')
+`
+
+type UpdateQuoteDetailsParams struct {
+	CustomerName   string         `json:"customer_name"`
+	Email          string         `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	VehicleDetails sql.NullString `json:"vehicle_details"`
+	Notes          sql.NullString `json:"notes"`
+	ExpiresAt      sql.NullTime   `json:"expires_at"`
+	ID             int64          `json:"id"`
+}
+
+// Editing a sent quote takes it back to draft until it is sent again
+func (q *Queries) UpdateQuoteDetails(ctx context.Context, arg UpdateQuoteDetailsParams) error {
+	_, err := q.db.ExecContext(ctx, updateQuoteDetails,
+		arg.CustomerName,
+		arg.Email,
+		arg.Phone,
+		arg.VehicleDetails,
+		arg.Notes,
+		arg.ExpiresAt,
+		arg.ID,
+	)
+	return err
+}
+
+const updateReview = `-- name: UpdateReview :one
+UPDATE reviews
+SET author = ?, rating = ?, body = ?, source = ?, is_featured = ?, status = ?
+WHERE id = ?
+RETURNING id, author, rating, body, source, is_featured, created_at, status, booking_id, gallery_group_id, follow_up_status, follow_up_notes, external_id
+`
+
+type UpdateReviewParams struct {
+	Author     string         `json:"author"`
+	Rating     sql.NullInt64  `json:"rating"`
+	Body       sql.NullString `json:"body"`
+	Source     sql.NullString `json:"source"`
+	IsFeatured sql.NullBool   `json:"is_featured"`
+	Status     sql.NullString `json:"status"`
+	ID         int64          `json:"id"`
+}
+
+func (q *Queries) UpdateReview(ctx context.Context, arg UpdateReviewParams) (Review, error) {
+	row := q.db.QueryRowContext(ctx, updateReview,
+		arg.Author,
+		arg.Rating,
+		arg.Body,
+		arg.Source,
+		arg.IsFeatured,
+		arg.Status,
+		arg.ID,
+	)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.Author,
+		&i.Rating,
+		&i.Body,
+		&i.Source,
+		&i.IsFeatured,
+		&i.CreatedAt,
+		&i.Status,
+		&i.BookingID,
+		&i.GalleryGroupID,
+		&i.FollowUpStatus,
+		&i.FollowUpNotes,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const updateSeriesOccurrence = `-- name: UpdateSeriesOccurrence :exec
+UPDATE booking_series_occurrences
+SET booking_id = ?, status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateSeriesOccurrenceParams struct {
+	BookingID sql.NullInt64 `json:"booking_id"`
+	Status    string        `json:"status"`
+	ID        int64         `json:"id"`
+}
+
+func (q *Queries) UpdateSeriesOccurrence(ctx context.Context, arg UpdateSeriesOccurrenceParams) error {
+	_, err := q.db.ExecContext(ctx, updateSeriesOccurrence, arg.BookingID, arg.Status, arg.ID)
+	return err
+}
+
+const updateStaff = `-- name: UpdateStaff :one
+UPDATE staff
+SET name = ?, email = ?, phone = ?, role = ?, skills = ?, is_active = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING id, name, email, phone, role, skills, is_active, created_at, updated_at
+`
+
+type UpdateStaffParams struct {
+	Name     string         `json:"name"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+	Role     string         `json:"role"`
+	Skills   sql.NullString `json:"skills"`
+	IsActive sql.NullBool   `json:"is_active"`
+	ID       int64          `json:"id"`
+}
+
+func (q *Queries) UpdateStaff(ctx context.Context, arg UpdateStaffParams) (Staff, error) {
+	row := q.db.QueryRowContext(ctx, updateStaff,
+		arg.Name,
+		arg.Email,
+		arg.Phone,
+		arg.Role,
+		arg.Skills,
+		arg.IsActive,
+		arg.ID,
+	)
+	var i Staff
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Role,
+		&i.Skills,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
+
+const updateTaxRate = `-- name: UpdateTaxRate :exec
+UPDATE tax_rates
+SET name = ?, rate = ?, is_default = ?, is_active = ?
+WHERE id = ?
+`
+
+type UpdateTaxRateParams struct {
+	Name      string       `json:"name"`
+	Rate      int64        `json:"rate"`
+	IsDefault sql.NullBool `json:"is_default"`
+	IsActive  sql.NullBool `json:"is_active"`
+	ID        int64        `json:"id"`
+}
+
+func (q *Queries) UpdateTaxRate(ctx context.Context, arg UpdateTaxRateParams) error {
+	_, err := q.db.ExecContext(ctx, updateTaxRate,
+		arg.Name,
+		arg.Rate,
+		arg.IsDefault,
+		arg.IsActive,
+		arg.ID,
+	)
+	return err
+}
+
+const useReviewRequest = `-- name: UseReviewRequest :execrows
+UPDATE review_requests SET used_at = CURRENT_TIMESTAMP
+WHERE id = ? AND used_at IS NULL
+`
+
+// Claims a request for a submission; zero rows means it was already used
+func (q *Queries) UseReviewRequest(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useReviewRequest, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const voidGiftCertificate = `-- name: VoidGiftCertificate :execrows
+UPDATE gift_certificates
+SET status = 'void', balance = 0, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'active'
+`
+
+func (q *Queries) VoidGiftCertificate(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, voidGiftCertificate, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const voidInvoice = `-- name: VoidInvoice :execrows
+UPDATE invoices
+SET status = 'void', voided_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status IN ('sent', 'paid')
+`
+
+func (q *Queries) VoidInvoice(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, voidInvoice, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -14,7 +14,22 @@ CREATE TABLE IF NOT EXISTS packages (
     is_active BOOLEAN DEFAULT 1,
     sort_order INTEGER DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    features TEXT, -- bullet points for service cards, one per line
+    included TEXT, -- what the package covers, one per line
+    excluded TEXT, -- what it doesn't, one per line
+    required_skills TEXT -- comma-separated skills a technician needs to do it
+);
+
+-- Questions answered on a package's service page
+CREATE TABLE IF NOT EXISTS package_faqs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    package_id INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    sort_order INTEGER DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE CASCADE
 );
 
 -- Gallery groups (collections of images for a single vehicle/project)
@@ -29,7 +44,9 @@ CREATE TABLE IF NOT EXISTS gallery_groups (
     is_featured BOOLEAN DEFAULT 0,
     sort_order INTEGER DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    package_id INTEGER, -- package performed
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE SET NULL
 );
 
 -- Media (images for gallery groups)
@@ -40,8 +57,22 @@ CREATE TABLE IF NOT EXISTS media (
     kind TEXT DEFAULT 'gallery', -- before|after|hero|gallery
     sort_order INTEGER DEFAULT 0,
     alt_text TEXT,
+    is_private BOOLEAN DEFAULT 0, -- served only through signed URLs
+    pair_id INTEGER, -- set on a 'before' shot: the 'after' shot it compares with
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (gallery_group_id) REFERENCES gallery_groups(id) ON DELETE CASCADE
+    FOREIGN KEY (gallery_group_id) REFERENCES gallery_groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (pair_id) REFERENCES media(id) ON DELETE SET NULL
+);
+
+-- Resized copies of a media item, used to build srcset attributes
+CREATE TABLE IF NOT EXISTS media_variants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    media_id INTEGER NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (media_id) REFERENCES media(id) ON DELETE CASCADE
 );
 
 -- Customer reviews/testimonials
@@ -52,7 +83,30 @@ CREATE TABLE IF NOT EXISTS reviews (
     body TEXT,
     source TEXT, -- google|facebook|manual
     is_featured BOOLEAN DEFAULT 0,
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    status TEXT DEFAULT 'approved', -- pending|approved|rejected; only approved reviews are public
+    booking_id INTEGER, -- set for reviews collected through a review request
+    gallery_group_id INTEGER,
+    follow_up_status TEXT, -- open|resolved for low ratings needing a call back
+    follow_up_notes TEXT,
+    external_id TEXT, -- review ID on the source platform, for imports
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL,
+    FOREIGN KEY (gallery_group_id) REFERENCES gallery_groups(id) ON DELETE SET NULL
+);
+
+-- Post-service review invitations. The emailed link is signed with the
+-- row ID and works once (used_at).
+CREATE TABLE IF NOT EXISTS review_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    booking_id INTEGER NOT NULL,
+    gallery_group_id INTEGER,
+    email TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    used_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE,
+    FOREIGN KEY (gallery_group_id) REFERENCES gallery_groups(id) ON DELETE SET NULL
 );
 
 -- Booking requests & calendar slots
@@ -66,19 +120,490 @@ CREATE TABLE IF NOT EXISTS bookings (
     notes TEXT,
     requested_start DATETIME NOT NULL,
     requested_end DATETIME NOT NULL,
-    status TEXT DEFAULT 'pending', -- pending|confirmed|declined|cancelled
+    status TEXT DEFAULT 'pending', -- pending|confirmed|declined|cancelled|completed
     source TEXT DEFAULT 'web',
     internal_notes TEXT,
     clerk_user_id TEXT, -- Clerk user ID if logged in
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    promo_code TEXT, -- code redeemed with the booking
+    discount_type TEXT, -- amount|percent, taken off the invoice
+    discount_value INTEGER NOT NULL DEFAULT 0,
+    membership_id INTEGER REFERENCES memberships(id) ON DELETE SET NULL, -- drew on a membership instead of being charged
+    series_id INTEGER REFERENCES booking_series(id) ON DELETE SET NULL, -- made for a repeat booking
+    service_address TEXT, -- where a mobile job is done; NULL at the shop
+    service_zip TEXT,
+    service_lat REAL, -- the ZIP's centroid, when known
+    service_lng REAL,
+    travel_minutes INTEGER NOT NULL DEFAULT 0, -- one-way drive from the shop
+    travel_fee INTEGER NOT NULL DEFAULT 0, -- cents, added to the invoice
+    staff_id INTEGER REFERENCES staff(id) ON DELETE SET NULL -- the technician doing the job
+);
+
+-- Messages from the public contact form
+CREATE TABLE IF NOT EXISTS contact_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    service_interest TEXT,
+    message TEXT NOT NULL,
+    ip_address TEXT, -- for rate limiting
+    is_read BOOLEAN DEFAULT 0,
+    replied_at DATETIME, -- last reply sent from the inbox
+    booking_id INTEGER, -- set once converted to a booking
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+-- Replies emailed from the admin inbox
+CREATE TABLE IF NOT EXISTS contact_replies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    message_id INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    sent_by TEXT, -- admin email
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (message_id) REFERENCES contact_messages(id) ON DELETE CASCADE
+);
+
+-- Extras sold alongside a package (quoted and invoiced as line items)
+CREATE TABLE IF NOT EXISTS addons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT,
+    price INTEGER NOT NULL DEFAULT 0, -- cents
+    is_active BOOLEAN DEFAULT 1,
+    sort_order INTEGER DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Itemized prices sent to a customer before they book
+CREATE TABLE IF NOT EXISTS quotes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    vehicle_details TEXT,
+    notes TEXT, -- shown to the customer
+    status TEXT DEFAULT 'draft', -- draft|sent|accepted|declined
+    version INTEGER DEFAULT 0, -- last version sent; 0 until first sent
+    expires_at DATETIME,
+    sent_at DATETIME,
+    accepted_at DATETIME,
+    booking_id INTEGER, -- booking created on acceptance
+    contact_message_id INTEGER, -- message the quote answers
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    promo_code_id INTEGER REFERENCES promo_codes(id) ON DELETE SET NULL,
+    promo_code TEXT,
+    discount_type TEXT, -- amount|percent
+    discount_value INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL,
+    FOREIGN KEY (contact_message_id) REFERENCES contact_messages(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS quote_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quote_id INTEGER NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'custom', -- package|addon|custom
+    package_id INTEGER,
+    addon_id INTEGER,
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_price INTEGER NOT NULL DEFAULT 0, -- cents
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (quote_id) REFERENCES quotes(id) ON DELETE CASCADE,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE SET NULL,
+    FOREIGN KEY (addon_id) REFERENCES addons(id) ON DELETE SET NULL
+);
+
+-- What the customer was sent each time, kept when the quote is revised
+CREATE TABLE IF NOT EXISTS quote_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quote_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    items TEXT NOT NULL, -- JSON array of line items as sent
+    total INTEGER NOT NULL, -- cents
+    expires_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (quote_id) REFERENCES quotes(id) ON DELETE CASCADE
+);
+
+-- Tax rates offered on invoices; the rate is copied onto each invoice
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    rate INTEGER NOT NULL, -- basis points: 825 = 8.25%
+    is_default BOOLEAN DEFAULT 0,
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Invoices for completed work. Numbers are assigned when an invoice is
+-- first sent, so drafts never leave gaps in the sequence.
+CREATE TABLE IF NOT EXISTS invoices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    number TEXT UNIQUE, -- e.g. INV-2026-0001
+    booking_id INTEGER,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    vehicle_details TEXT,
+    status TEXT DEFAULT 'draft', -- draft|sent|paid|void
+    tax_name TEXT,
+    tax_rate INTEGER NOT NULL DEFAULT 0, -- basis points
+    discount_type TEXT, -- amount|percent, or NULL for none
+    discount_value INTEGER NOT NULL DEFAULT 0, -- cents, or basis points for percent
+    notes TEXT, -- printed on the invoice
+    issued_at DATETIME,
+    due_at DATETIME,
+    sent_at DATETIME,
+    paid_at DATETIME,
+    payment_method TEXT,
+    voided_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoice_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'custom', -- package|addon|custom
+    package_id INTEGER,
+    addon_id INTEGER,
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_price INTEGER NOT NULL DEFAULT 0, -- cents
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE SET NULL,
+    FOREIGN KEY (addon_id) REFERENCES addons(id) ON DELETE SET NULL
+);
+
+-- Last invoice number used in each year
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    year INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL DEFAULT 0
+);
+
+-- Money taken through the payment provider (see pkg/payments)
+CREATE TABLE IF NOT EXISTS payments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    booking_id INTEGER,
+    kind TEXT NOT NULL DEFAULT 'deposit', -- deposit|gift_certificate
+    provider TEXT NOT NULL, -- stripe|manual
+    checkout_id TEXT, -- provider checkout session
+    payment_ref TEXT, -- provider payment, refunds go against this
+    amount INTEGER NOT NULL, -- cents
+    currency TEXT NOT NULL DEFAULT 'usd',
+    status TEXT NOT NULL DEFAULT 'pending', -- pending|paid|failed|expired|void|refunded|forfeited
+    refund_ref TEXT,
+    refunded_amount INTEGER NOT NULL DEFAULT 0,
+    paid_at DATETIME,
+    refunded_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+-- Webhook deliveries already handled; providers retry until acknowledged
+CREATE TABLE IF NOT EXISTS payment_events (
+    id TEXT PRIMARY KEY,
+    type TEXT,
+    received_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Promo codes taken off bookings and quotes (see pkg/promo)
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT UNIQUE NOT NULL, -- stored upper case
+    description TEXT, -- for admins, e.g. "Spring ceramic special"
+    discount_type TEXT NOT NULL, -- amount|percent
+    discount_value INTEGER NOT NULL, -- cents, or basis points for percent
+    starts_at DATETIME,
+    ends_at DATETIME, -- exclusive
+    max_uses INTEGER NOT NULL DEFAULT 0, -- 0 for unlimited
+    max_uses_per_customer INTEGER NOT NULL DEFAULT 0, -- 0 for unlimited
+    min_spend INTEGER NOT NULL DEFAULT 0, -- cents before the discount, 0 for none
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Packages a code is limited to; none means any service
+CREATE TABLE IF NOT EXISTS promo_code_packages (
+    promo_code_id INTEGER NOT NULL,
+    package_id INTEGER NOT NULL,
+    PRIMARY KEY (promo_code_id, package_id),
+    FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id) ON DELETE CASCADE,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE CASCADE
+);
+
+-- Each use of a code. Uses whose booking was cancelled or declined don't
+-- count towards the limits.
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    promo_code_id INTEGER NOT NULL,
+    booking_id INTEGER,
+    quote_id INTEGER,
+    email TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id) ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL,
+    FOREIGN KEY (quote_id) REFERENCES quotes(id) ON DELETE SET NULL
+);
+
+-- Gift certificates. balance is what's left to spend; every change to it
+-- is recorded in gift_certificate_transactions.
+CREATE TABLE IF NOT EXISTS gift_certificates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE, -- e.g. GIFT-7KQ4-M2XD
+    initial_value INTEGER NOT NULL, -- cents
+    balance INTEGER NOT NULL DEFAULT 0, -- cents
+    status TEXT NOT NULL DEFAULT 'active', -- pending (awaiting payment)|active|void
+    source TEXT NOT NULL DEFAULT 'manual', -- manual|online
+    purchaser_name TEXT,
+    purchaser_email TEXT,
+    recipient_name TEXT,
+    recipient_email TEXT,
+    message TEXT, -- printed on the certificate
+    payment_id INTEGER,
+    expires_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS gift_certificate_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    gift_certificate_id INTEGER NOT NULL,
+    kind TEXT NOT NULL, -- issue|redeem|refund|adjust|void
+    amount INTEGER NOT NULL, -- cents, negative when taken off the balance
+    balance_after INTEGER NOT NULL,
+    invoice_id INTEGER,
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (gift_certificate_id) REFERENCES gift_certificates(id) ON DELETE CASCADE,
+    FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE SET NULL
+);
+
+-- Membership plans: a number of services each period for a set price
+-- (see pkg/membership)
+CREATE TABLE IF NOT EXISTS membership_plans (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL, -- e.g. "Monthly Maintenance Wash"
+    description TEXT,
+    package_id INTEGER, -- the service included; NULL for any
+    services_per_period INTEGER NOT NULL,
+    period_months INTEGER NOT NULL DEFAULT 1,
+    price INTEGER NOT NULL, -- cents per period
+    is_active BOOLEAN DEFAULT 1, -- inactive plans take no new members
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE SET NULL
+);
+
+-- A customer's membership. Periods run from started_at; what's been used
+-- in one is counted from the bookings made with the membership.
+CREATE TABLE IF NOT EXISTS memberships (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    plan_id INTEGER NOT NULL,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL, -- stored lower case
+    phone TEXT,
+    clerk_user_id TEXT, -- linked the first time they sign in with this email
+    status TEXT NOT NULL DEFAULT 'active', -- active|cancelled
+    started_at DATETIME NOT NULL,
+    paid_through DATETIME NOT NULL, -- the renewal date
+    cancelled_at DATETIME,
+    cancel_reason TEXT,
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (plan_id) REFERENCES membership_plans(id)
+);
+
+-- Each period paid for, the first included
+CREATE TABLE IF NOT EXISTS membership_renewals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    membership_id INTEGER NOT NULL,
+    period_start DATETIME NOT NULL,
+    period_end DATETIME NOT NULL,
+    amount INTEGER NOT NULL, -- cents
+    payment_method TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (membership_id) REFERENCES memberships(id) ON DELETE CASCADE
+);
+
+-- A repeat booking: the same customer and slot on a schedule. Bookings
+-- are made for each occurrence as it comes into the booking window.
+CREATE TABLE IF NOT EXISTS booking_series (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    vehicle_details TEXT,
+    service_interest TEXT,
+    notes TEXT,
+    rrule TEXT NOT NULL, -- RRULE subset, see pkg/recurrence
+    starts_at DATETIME NOT NULL, -- anchors the rule and sets the time of day
+    duration_minutes INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active', -- active|cancelled
+    booked_through DATETIME NOT NULL, -- occurrences before this have been made
+    previous_id INTEGER, -- the series this one was split from
+    cancelled_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (previous_id) REFERENCES booking_series(id) ON DELETE SET NULL
+);
+
+-- Each date a series falls on. A conflict is a date that couldn't be
+-- booked because the slot was taken or the shop was closed.
+CREATE TABLE IF NOT EXISTS booking_series_occurrences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    series_id INTEGER NOT NULL,
+    scheduled_at DATETIME NOT NULL, -- when the rule puts it, even if moved
+    booking_id INTEGER,
+    status TEXT NOT NULL, -- booked|conflict|skipped
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (series_id, scheduled_at),
+    FOREIGN KEY (series_id) REFERENCES booking_series(id) ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+-- Customers waiting for a slot to open up in a range of days. Freed slots
+-- are offered to entries in the order they joined.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    customer_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT,
+    vehicle_details TEXT,
+    service_interest TEXT,
+    notes TEXT,
+    date_from DATETIME NOT NULL, -- midnight starting the first day wanted
+    date_to DATETIME NOT NULL, -- midnight ending the last day wanted
+    slot_id TEXT, -- NULL for any slot
+    clerk_user_id TEXT,
+    status TEXT NOT NULL DEFAULT 'waiting', -- waiting|booked|removed
+    booking_id INTEGER, -- made by claiming an offer
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+-- A freed slot offered to a waitlisted customer. An open offer holds the
+-- slot until it expires; then it's offered to the next customer.
+CREATE TABLE IF NOT EXISTS waitlist_offers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entry_id INTEGER NOT NULL,
+    slot_start DATETIME NOT NULL,
+    slot_end DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open', -- open|claimed|passed|expired
+    booking_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (entry_id) REFERENCES waitlist_entries(id) ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+-- A slot picked on the booking page, kept from other visitors while the
+-- customer fills in the form. Holds lapse at expires_at.
+CREATE TABLE IF NOT EXISTS slot_holds (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT NOT NULL UNIQUE, -- the visitor's handle on the hold
+    slot_start DATETIME NOT NULL,
+    slot_end DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    ip_address TEXT,
+    status TEXT NOT NULL DEFAULT 'held', -- held|released|converted
+    booking_id INTEGER, -- the booking it became
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
+
+-- People who work at the shop. Technicians and leads are assigned jobs;
+-- their hours, skills and time off decide which.
+CREATE TABLE IF NOT EXISTS staff (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    email TEXT, -- lowercase; matched to the signed-in account for their schedule
+    phone TEXT,
+    role TEXT NOT NULL DEFAULT 'technician', -- technician|lead|manager|office
+    skills TEXT, -- comma-separated, e.g. exterior,ceramic
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The shift someone works on a weekday, every week
+CREATE TABLE IF NOT EXISTS staff_hours (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    staff_id INTEGER NOT NULL,
+    weekday INTEGER NOT NULL, -- 0 = Sunday
+    start_minute INTEGER NOT NULL, -- after midnight, in the shop's time zone
+    end_minute INTEGER NOT NULL,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE,
+    UNIQUE (staff_id, weekday)
+);
+
+-- Holidays, sick days and appointments, when someone can't take jobs
+CREATE TABLE IF NOT EXISTS staff_time_off (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    staff_id INTEGER NOT NULL,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    reason TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (staff_id) REFERENCES staff(id) ON DELETE CASCADE
+);
+
 -- Indexes for performance
+CREATE INDEX IF NOT EXISTS idx_package_faqs_package_id ON package_faqs(package_id);
 CREATE INDEX IF NOT EXISTS idx_gallery_groups_featured ON gallery_groups(is_featured);
 CREATE INDEX IF NOT EXISTS idx_gallery_groups_sort ON gallery_groups(sort_order);
 CREATE INDEX IF NOT EXISTS idx_media_gallery_group_id ON media(gallery_group_id);
+CREATE INDEX IF NOT EXISTS idx_media_variants_media_id ON media_variants(media_id);
 CREATE INDEX IF NOT EXISTS idx_media_sort ON media(sort_order);
 CREATE INDEX IF NOT EXISTS idx_bookings_requested_start ON bookings(requested_start);
 CREATE INDEX IF NOT EXISTS idx_bookings_status ON bookings(status);
 CREATE INDEX IF NOT EXISTS idx_reviews_featured ON reviews(is_featured);
+CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews(status);
+CREATE INDEX IF NOT EXISTS idx_reviews_follow_up ON reviews(follow_up_status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_source_external_id ON reviews(source, external_id);
+CREATE INDEX IF NOT EXISTS idx_review_requests_booking_id ON review_requests(booking_id);
+CREATE INDEX IF NOT EXISTS idx_contact_messages_created_at ON contact_messages(created_at);
+CREATE INDEX IF NOT EXISTS idx_contact_messages_ip ON contact_messages(ip_address, created_at);
+CREATE INDEX IF NOT EXISTS idx_contact_replies_message_id ON contact_replies(message_id);
+CREATE INDEX IF NOT EXISTS idx_quotes_status ON quotes(status);
+CREATE INDEX IF NOT EXISTS idx_quote_items_quote_id ON quote_items(quote_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_versions_quote_version ON quote_versions(quote_id, version);
+CREATE INDEX IF NOT EXISTS idx_invoices_status ON invoices(status);
+CREATE INDEX IF NOT EXISTS idx_invoices_booking_id ON invoices(booking_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_email ON invoices(email);
+CREATE INDEX IF NOT EXISTS idx_invoice_items_invoice_id ON invoice_items(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_payments_booking_id ON payments(booking_id);
+CREATE INDEX IF NOT EXISTS idx_payments_checkout_id ON payments(checkout_id);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_code ON promo_redemptions(promo_code_id, email);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_booking_id ON promo_redemptions(booking_id);
+CREATE INDEX IF NOT EXISTS idx_gift_certificates_payment_id ON gift_certificates(payment_id);
+CREATE INDEX IF NOT EXISTS idx_gift_certificate_transactions_certificate ON gift_certificate_transactions(gift_certificate_id);
+CREATE INDEX IF NOT EXISTS idx_gift_certificate_transactions_invoice_id ON gift_certificate_transactions(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_memberships_clerk_user_id ON memberships(clerk_user_id);
+CREATE INDEX IF NOT EXISTS idx_memberships_email ON memberships(email);
+CREATE INDEX IF NOT EXISTS idx_membership_renewals_membership_id ON membership_renewals(membership_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_membership_id ON bookings(membership_id);
+CREATE INDEX IF NOT EXISTS idx_booking_series_status ON booking_series(status, booked_through);
+CREATE INDEX IF NOT EXISTS idx_booking_series_occurrences_booking_id ON booking_series_occurrences(booking_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_series_id ON bookings(series_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_status ON waitlist_entries(status, date_from, date_to);
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers_entry_id ON waitlist_offers(entry_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers_open ON waitlist_offers(status, slot_start);
+CREATE INDEX IF NOT EXISTS idx_slot_holds_status ON slot_holds(status, slot_start);
+CREATE INDEX IF NOT EXISTS idx_staff_time_off_staff_id ON staff_time_off(staff_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_bookings_staff_id ON bookings(staff_id, requested_start);
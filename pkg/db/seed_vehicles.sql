@@ -2,10 +2,10 @@
 -- Run this to populate the gallery with example work
 
 -- First, insert packages if they don't exist
-INSERT OR IGNORE INTO packages (slug, name, short_desc, price_min, price_max, duration_est, is_active, sort_order) VALUES
-('interior-detail', 'Interior Detail', 'Deep vacuum, steam cleaning, leather conditioning, and odor elimination', 15000, 20000, 180, 1, 1),
-('exterior-detail', 'Exterior Detail', 'Hand wash, clay bar, polish, and premium wax protection', 20000, 30000, 240, 1, 2),
-('full-detail', 'Full Detail', 'Complete interior and exterior detailing for showroom finish', 30000, 50000, 360, 1, 3);
+INSERT OR IGNORE INTO packages (slug, name, short_desc, price_min, price_max, duration_est, is_active, sort_order, features) VALUES
+('interior-detail', 'Interior Detail', 'Deep vacuum, steam cleaning, leather conditioning, and odor elimination', 15000, 20000, 180, 1, 1, 'Deep vacuum & steam cleaning' || char(10) || 'Leather conditioning & fabric protection' || char(10) || 'Dashboard & console detail' || char(10) || 'Carpet shampoo & odor elimination'),
+('exterior-detail', 'Exterior Detail', 'Hand wash, clay bar, polish, and premium wax protection', 20000, 30000, 240, 1, 2, 'Hand wash & clay bar treatment' || char(10) || 'Paint correction & polish' || char(10) || 'Premium wax or sealant' || char(10) || 'Wheel & tire detail, trim restoration'),
+('full-detail', 'Full Detail', 'Complete interior and exterior detailing for showroom finish', 30000, 50000, 360, 1, 3, 'Complete interior & exterior service' || char(10) || 'Door jambs & engine bay detail' || char(10) || 'Headlight restoration' || char(10) || 'Final inspection for showroom finish');
 
 -- Insert vehicles from Courtesy Auto
 INSERT INTO vehicles (slug, year, make, model, trim, color, dealership_name, dealership_logo_url, dealership_listing_url, dealership_location, status) VALUES
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// busyTimeout is how long a write waits for another to finish before
+// SQLite gives up with SQLITE_BUSY.
+const busyTimeout = "5000" // milliseconds
+
+// Open opens the SQLite database at path. Transactions take the write lock
+// when they begin, and wait up to busyTimeout for it: two that read and
+// then write would otherwise both start, and SQLite would fail one of them
+// at once rather than let it wait.
+func Open(path string) (*sql.DB, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return sql.Open("sqlite", path+sep+"_txlock=immediate&_pragma=busy_timeout("+busyTimeout+")")
+}
+
+// IsBusy reports whether err is SQLite giving up waiting for another
+// connection's write lock.
+func IsBusy(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY
+}
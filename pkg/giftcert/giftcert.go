@@ -0,0 +1,90 @@
+// Package giftcert makes gift certificate codes and decides how much of a
+// certificate can be spent. Like promo, it knows nothing about storage.
+package giftcert
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Reasons a certificate can't be spent. Callers word them for the invoice
+// page.
+var (
+	ErrUnknown = errors.New("giftcert: unknown code")
+	ErrVoid    = errors.New("giftcert: certificate void")
+	ErrExpired = errors.New("giftcert: certificate expired")
+	ErrEmpty   = errors.New("giftcert: no balance left")
+	ErrAmount  = errors.New("giftcert: amount over the balance or amount due")
+)
+
+// codeAlphabet leaves out letters and digits that are easily confused,
+// such as O and 0, or I and 1.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// NewCode returns a random code like GIFT-7KQ4-M2XD. It has 40 random bits,
+// too many to guess.
+func NewCode() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	code := []byte("GIFT-")
+	for i, c := range b {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		code = append(code, codeAlphabet[int(c)%len(codeAlphabet)])
+	}
+	return string(code)
+}
+
+// Normalize is the stored form of a code, so it can be typed in any case,
+// with or without spaces and dashes, and with or without the GIFT prefix:
+// "gift 7kq4m2xd" and "7KQ4-M2XD" are both GIFT-7KQ4-M2XD. Anything that
+// isn't shaped like a code comes back upper case and without separators,
+// and won't match one.
+func Normalize(code string) string {
+	code = strings.ToUpper(strings.Join(strings.FieldsFunc(code, func(r rune) bool {
+		return r == '-' || unicode.IsSpace(r)
+	}), ""))
+	body := strings.TrimPrefix(code, "GIFT")
+	if len(body) != 8 {
+		return code
+	}
+	return "GIFT-" + body[:4] + "-" + body[4:]
+}
+
+// Certificate is what Spend needs to know about a certificate.
+type Certificate struct {
+	Status    string // pending|active|void
+	Balance   int64
+	ExpiresAt time.Time // zero for no expiry
+}
+
+// Spend works out how much to take off a certificate at time at towards
+// an amount due. A requested amount of zero means as much as possible.
+func (c Certificate) Spend(at time.Time, due int64, requested int64) (int64, error) {
+	switch {
+	case c.Status != "active":
+		if c.Status == "void" {
+			return 0, ErrVoid
+		}
+		return 0, ErrUnknown
+	case !c.ExpiresAt.IsZero() && !at.Before(c.ExpiresAt):
+		return 0, ErrExpired
+	case c.Balance <= 0:
+		return 0, ErrEmpty
+	}
+
+	available := min(c.Balance, due)
+	if requested == 0 {
+		requested = available
+	}
+	if requested <= 0 || requested > available {
+		return 0, ErrAmount
+	}
+	return requested, nil
+}
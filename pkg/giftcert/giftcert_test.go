@@ -0,0 +1,93 @@
+package giftcert
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"GIFT-7KQ4-M2XD", "GIFT-7KQ4-M2XD"},
+		{"gift-7kq4-m2xd", "GIFT-7KQ4-M2XD"},
+		{"GIFT7KQ4M2XD", "GIFT-7KQ4-M2XD"},
+		{"  gift 7kq4 m2xd\n", "GIFT-7KQ4-M2XD"},
+		{"GIFT--7KQ4--M2XD", "GIFT-7KQ4-M2XD"},
+		{"GIFT-7KQ-4M2XD", "GIFT-7KQ4-M2XD"},
+		// Without the prefix printed above the code
+		{"7KQ4-M2XD", "GIFT-7KQ4-M2XD"},
+		{"7kq4m2xd", "GIFT-7KQ4-M2XD"},
+		// Not shaped like a code
+		{"GIFT-7KQ4", "GIFT7KQ4"},
+		{"GIFT-7KQ4-M2XD-9", "GIFT7KQ4M2XD9"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.raw); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestNewCode(t *testing.T) {
+	shape := regexp.MustCompile(`^GIFT-[` + codeAlphabet + `]{4}-[` + codeAlphabet + `]{4}$`)
+	seen := make(map[string]bool)
+	for range 1000 {
+		code := NewCode()
+		if !shape.MatchString(code) {
+			t.Fatalf("NewCode() = %q", code)
+		}
+		if Normalize(code) != code {
+			t.Fatalf("Normalize(%q) = %q, want it unchanged", code, Normalize(code))
+		}
+		if seen[code] {
+			t.Fatalf("NewCode() repeated %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestSpend(t *testing.T) {
+	now := time.Date(2026, 6, 1, 15, 0, 0, 0, time.UTC)
+	active := Certificate{Status: "active", Balance: 10000}
+
+	tests := []struct {
+		name      string
+		cert      Certificate
+		due       int64
+		requested int64
+		want      int64
+		wantErr   error
+	}{
+		{name: "covers the invoice", cert: active, due: 6000, want: 6000},
+		{name: "part of the invoice", cert: active, due: 25000, want: 10000},
+		{name: "exactly the balance", cert: active, due: 10000, want: 10000},
+		{name: "a chosen amount", cert: active, due: 25000, requested: 2500, want: 2500},
+		{name: "chosen amount is the whole balance", cert: active, due: 25000, requested: 10000, want: 10000},
+		{name: "more than the balance", cert: active, due: 25000, requested: 10001, wantErr: ErrAmount},
+		{name: "more than is due", cert: active, due: 4000, requested: 5000, wantErr: ErrAmount},
+		{name: "negative amount", cert: active, due: 4000, requested: -100, wantErr: ErrAmount},
+		{name: "nothing due", cert: active, due: 0, wantErr: ErrAmount},
+		{name: "no balance", cert: Certificate{Status: "active"}, due: 4000, wantErr: ErrEmpty},
+		{name: "void", cert: Certificate{Status: "void", Balance: 10000}, due: 4000, wantErr: ErrVoid},
+		{name: "not paid for yet", cert: Certificate{Status: "pending", Balance: 10000}, due: 4000, wantErr: ErrUnknown},
+		{name: "before it expires", cert: Certificate{Status: "active", Balance: 10000, ExpiresAt: now.Add(time.Second)}, due: 4000, want: 4000},
+		{name: "the moment it expires", cert: Certificate{Status: "active", Balance: 10000, ExpiresAt: now}, due: 4000, wantErr: ErrExpired},
+		{name: "void beats expired", cert: Certificate{Status: "void", Balance: 10000, ExpiresAt: now.AddDate(-1, 0, 0)}, due: 4000, wantErr: ErrVoid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cert.Spend(now, tt.due, tt.requested)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Spend = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// Package imaging produces metadata-free originals and responsive width
+// variants for uploaded gallery photos using only the standard library.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	jpegQuality         = 82
+	originalJPEGQuality = 90
+)
+
+// MaxPixels caps the size of an image Process will decode. A few kilobytes
+// of compressed data can declare dimensions that take gigabytes to decode,
+// so the header is checked before any pixels are read.
+const MaxPixels = 40_000_000
+
+// DefaultWidths are the responsive widths generated for gallery images.
+var DefaultWidths = []int{480, 960, 1600}
+
+var (
+	ErrUnsupportedFormat = errors.New("imaging: unsupported image format")
+	ErrTooLarge          = errors.New("imaging: image dimensions are too large")
+)
+
+// Variant is a resized copy of an image, already encoded.
+type Variant struct {
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// Result holds the processed original and its variants. Ext is the file
+// extension (with dot) shared by the original and every variant.
+type Result struct {
+	Original    []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+	Variants    []Variant
+}
+
+// Process strips metadata from data and renders a variant for every width
+// narrower than the source. JPEGs are rotated according to their EXIF
+// orientation before the tag is discarded. GIFs and WebPs are passed through
+// without variants because the standard library cannot re-encode them; WebP
+// metadata chunks are still removed.
+func Process(data []byte, widths []int) (*Result, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg":
+		return processJPEG(data, widths)
+	case "image/png":
+		return processPNG(data, widths)
+	case "image/gif":
+		cfg, err := gif.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Original: data, ContentType: contentType, Ext: ".gif", Width: cfg.Width, Height: cfg.Height}, nil
+	case "image/webp":
+		stripped, width, height, err := stripWebPMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Original: stripped, ContentType: contentType, Ext: ".webp", Width: width, Height: height}, nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+func processJPEG(data []byte, widths []int) (*Result, error) {
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDimensions(cfg); err != nil {
+		return nil, err
+	}
+	src, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	img := applyOrientation(toRGBA(src), jpegOrientation(data))
+
+	encode := func(m image.Image, quality int) ([]byte, error) {
+		var buf bytes.Buffer
+		err := jpeg.Encode(&buf, m, &jpeg.Options{Quality: quality})
+		return buf.Bytes(), err
+	}
+
+	original, err := encode(img, originalJPEGQuality)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := buildVariants(img, widths, func(m image.Image) ([]byte, error) {
+		return encode(m, jpegQuality)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	return &Result{
+		Original:    original,
+		ContentType: "image/jpeg",
+		Ext:         ".jpg",
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Variants:    variants,
+	}, nil
+}
+
+func processPNG(data []byte, widths []int) (*Result, error) {
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDimensions(cfg); err != nil {
+		return nil, err
+	}
+	src, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	img := toRGBA(src)
+
+	encode := func(m image.Image) ([]byte, error) {
+		var buf bytes.Buffer
+		err := png.Encode(&buf, m)
+		return buf.Bytes(), err
+	}
+
+	// Re-encoding drops tEXt/iTXt/eXIf chunks along with everything else
+	// the decoder does not understand.
+	original, err := encode(img)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := buildVariants(img, widths, encode)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	return &Result{
+		Original:    original,
+		ContentType: "image/png",
+		Ext:         ".png",
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Variants:    variants,
+	}, nil
+}
+
+// checkDimensions rejects images that would take more than MaxPixels to
+// decode.
+func checkDimensions(cfg image.Config) error {
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return ErrUnsupportedFormat
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+func buildVariants(img *image.RGBA, widths []int, encode func(image.Image) ([]byte, error)) ([]Variant, error) {
+	bounds := img.Bounds()
+	var variants []Variant
+	for _, width := range widths {
+		if width <= 0 || width >= bounds.Dx() {
+			continue
+		}
+		height := bounds.Dy() * width / bounds.Dx()
+		if height < 1 {
+			height = 1
+		}
+		data, err := encode(Resize(img, width, height))
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, Variant{Width: width, Height: height, Data: data})
+	}
+	return variants, nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// VariantName returns the file name used for the width variant of name,
+// e.g. "truck.jpg" at 480 becomes "truck-480w.jpg". ext replaces the
+// original extension so re-encoded variants keep a matching suffix.
+func VariantName(name string, width int, ext string) string {
+	base := strings.TrimSuffix(name, path.Ext(name))
+	return base + "-" + strconv.Itoa(width) + "w" + ext
+}
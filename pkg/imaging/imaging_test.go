@@ -0,0 +1,116 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withJPEGSize rewrites the dimensions in a baseline JPEG's SOF0 header.
+func withJPEGSize(t *testing.T, data []byte, w, h uint16) []byte {
+	t.Helper()
+	out := append([]byte(nil), data...)
+	i := bytes.Index(out, []byte{0xFF, 0xC0})
+	if i < 0 {
+		t.Fatal("no SOF0 marker")
+	}
+	binary.BigEndian.PutUint16(out[i+5:], h)
+	binary.BigEndian.PutUint16(out[i+7:], w)
+	return out
+}
+
+// withPNGSize rewrites the dimensions in a PNG's IHDR chunk and fixes up
+// its checksum.
+func withPNGSize(data []byte, w, h uint32) []byte {
+	out := append([]byte(nil), data...)
+	// Signature (8), chunk length (4), "IHDR" (4), then width and height
+	const ihdr = 12
+	binary.BigEndian.PutUint32(out[ihdr+4:], w)
+	binary.BigEndian.PutUint32(out[ihdr+8:], h)
+	binary.BigEndian.PutUint32(out[ihdr+17:], crc32.ChecksumIEEE(out[ihdr:ihdr+17]))
+	return out
+}
+
+func TestProcess(t *testing.T) {
+	jpg := encodeJPEG(t, testImage(64, 32))
+	pngData := encodePNG(t, testImage(64, 32))
+
+	tests := []struct {
+		name     string
+		data     []byte
+		widths   []int
+		wantErr  error
+		wantW    int
+		variants []int
+	}{
+		{name: "jpeg", data: jpg, widths: []int{16, 32, 64, 128}, wantW: 64, variants: []int{16, 32}},
+		{name: "png", data: pngData, widths: []int{48}, wantW: 64, variants: []int{48}},
+		{name: "jpeg declaring huge dimensions", data: withJPEGSize(t, jpg, 60000, 60000), wantErr: ErrTooLarge},
+		{name: "png declaring huge dimensions", data: withPNGSize(pngData, 100000, 100000), wantErr: ErrTooLarge},
+		{name: "png just over the cap", data: withPNGSize(pngData, MaxPixels/1000+1, 1000), wantErr: ErrTooLarge},
+		{name: "not an image", data: []byte("hello, world"), wantErr: ErrUnsupportedFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Process(tt.data, tt.widths)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Width != tt.wantW {
+				t.Errorf("width = %d, want %d", got.Width, tt.wantW)
+			}
+			var widths []int
+			for _, v := range got.Variants {
+				widths = append(widths, v.Width)
+			}
+			if len(widths) != len(tt.variants) {
+				t.Fatalf("variant widths = %v, want %v", widths, tt.variants)
+			}
+			for i := range widths {
+				if widths[i] != tt.variants[i] {
+					t.Errorf("variant widths = %v, want %v", widths, tt.variants)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+)
+
+var errInvalidWebP = errors.New("imaging: invalid webp file")
+
+// jpegOrientation returns the EXIF orientation tag (1-8) of a JPEG, or 1
+// when the file carries no usable EXIF block.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		// Start of scan: no more metadata segments
+		if marker == 0xDA {
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if size < 2 || pos+2+size > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+size]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		pos += 2 + size
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation rotates/flips img so it displays upright once the EXIF
+// orientation tag is gone.
+func applyOrientation(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			so := y*img.Stride + x*4
+			do := dy*dst.Stride + dx*4
+			copy(dst.Pix[do:do+4], img.Pix[so:so+4])
+		}
+	}
+	return dst
+}
+
+// stripWebPMetadata removes EXIF and XMP chunks from a WebP container and
+// clears the matching VP8X flags. It also reports the canvas size.
+func stripWebPMetadata(data []byte) ([]byte, int, int, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, 0, 0, errInvalidWebP
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	width, height := 0, 0
+
+	pos := 12
+	for pos+8 <= len(data) {
+		fourCC := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + size + size%2
+		if pos+8+size > len(data) {
+			return nil, 0, 0, errInvalidWebP
+		}
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[pos:end]
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+			pos = end
+			continue
+		case "VP8X":
+			if size >= 10 {
+				chunk = append([]byte(nil), chunk...)
+				// Bit 3 = EXIF present, bit 2 = XMP present
+				chunk[8] &^= 0x08 | 0x04
+				width = 1 + int(uint32(chunk[12])|uint32(chunk[13])<<8|uint32(chunk[14])<<16)
+				height = 1 + int(uint32(chunk[15])|uint32(chunk[16])<<8|uint32(chunk[17])<<16)
+			}
+		case "VP8 ":
+			if width == 0 && size >= 10 {
+				width = int(binary.LittleEndian.Uint16(chunk[14:]) & 0x3FFF)
+				height = int(binary.LittleEndian.Uint16(chunk[16:]) & 0x3FFF)
+			}
+		case "VP8L":
+			if width == 0 && size >= 5 {
+				bits := binary.LittleEndian.Uint32(chunk[9:])
+				width = int(bits&0x3FFF) + 1
+				height = int((bits>>14)&0x3FFF) + 1
+			}
+		}
+		out = append(out, chunk...)
+		pos = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, width, height, nil
+}
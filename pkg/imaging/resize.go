@@ -0,0 +1,110 @@
+package imaging
+
+import (
+	"image"
+	"math"
+)
+
+// contribution describes which source pixels feed one destination pixel
+// and how much each one weighs.
+type contribution struct {
+	start   int
+	weights []float64
+}
+
+// Resize scales src to width x height with an area-averaging filter. It is
+// intended for downscaling, which is all the gallery needs.
+func Resize(src *image.RGBA, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	// Horizontal pass: srcW x srcH -> width x srcH
+	tmp := image.NewRGBA(image.Rect(0, 0, width, srcH))
+	cols := contributions(srcW, width)
+	for y := 0; y < srcH; y++ {
+		srcRow := src.Pix[(y)*src.Stride:]
+		dstRow := tmp.Pix[y*tmp.Stride:]
+		for x, c := range cols {
+			var r, g, b, a float64
+			for i, w := range c.weights {
+				off := (c.start + i) * 4
+				r += float64(srcRow[off]) * w
+				g += float64(srcRow[off+1]) * w
+				b += float64(srcRow[off+2]) * w
+				a += float64(srcRow[off+3]) * w
+			}
+			off := x * 4
+			dstRow[off] = clamp8(r)
+			dstRow[off+1] = clamp8(g)
+			dstRow[off+2] = clamp8(b)
+			dstRow[off+3] = clamp8(a)
+		}
+	}
+
+	// Vertical pass: width x srcH -> width x height
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	rows := contributions(srcH, height)
+	for y, c := range rows {
+		dstRow := dst.Pix[y*dst.Stride:]
+		for x := 0; x < width; x++ {
+			var r, g, b, a float64
+			for i, w := range c.weights {
+				off := (c.start+i)*tmp.Stride + x*4
+				r += float64(tmp.Pix[off]) * w
+				g += float64(tmp.Pix[off+1]) * w
+				b += float64(tmp.Pix[off+2]) * w
+				a += float64(tmp.Pix[off+3]) * w
+			}
+			off := x * 4
+			dstRow[off] = clamp8(r)
+			dstRow[off+1] = clamp8(g)
+			dstRow[off+2] = clamp8(b)
+			dstRow[off+3] = clamp8(a)
+		}
+	}
+
+	return dst
+}
+
+func contributions(srcLen, dstLen int) []contribution {
+	scale := float64(srcLen) / float64(dstLen)
+	out := make([]contribution, dstLen)
+	for i := range out {
+		lo := float64(i) * scale
+		hi := lo + scale
+		start := int(lo)
+		end := int(math.Ceil(hi))
+		if end > srcLen {
+			end = srcLen
+		}
+		if end <= start {
+			end = start + 1
+		}
+		weights := make([]float64, end-start)
+		var total float64
+		for j := start; j < end; j++ {
+			w := math.Min(hi, float64(j+1)) - math.Max(lo, float64(j))
+			if w < 0 {
+				w = 0
+			}
+			weights[j-start] = w
+			total += w
+		}
+		for j := range weights {
+			weights[j] /= total
+		}
+		out[i] = contribution{start: start, weights: weights}
+	}
+	return out
+}
+
+func clamp8(v float64) uint8 {
+	v = math.Round(v)
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
@@ -0,0 +1,85 @@
+package invoice
+
+import (
+	"io"
+	"time"
+)
+
+// Certificate is everything printed on a gift certificate.
+type Certificate struct {
+	From      Party
+	Code      string
+	Value     int64
+	To        string // recipient, may be empty
+	Giver     string // who it's from, may be empty
+	Message   string
+	ExpiresAt time.Time // zero for no expiry
+	Void      bool
+}
+
+// RenderCertificatePDF writes a gift certificate as a one-page PDF, boxed
+// in the top half of the page so it can be cut out.
+func RenderCertificatePDF(w io.Writer, cert Certificate) error {
+	p := &pdfWriter{}
+	p.newPage()
+
+	top := pageHeight - margin
+	bottom := pageHeight/2 - 12
+	p.line(margin, top, colAmtRight, top)
+	p.line(margin, bottom, colAmtRight, bottom)
+	p.line(margin, top, margin, bottom)
+	p.line(colAmtRight, top, colAmtRight, bottom)
+
+	center := pageWidth / 2
+	y := top - 44
+	p.textCenter(center, y, 12, true, cert.From.Name)
+	y -= 38
+	title := "GIFT CERTIFICATE"
+	if cert.Void {
+		title = "VOID"
+	}
+	p.textCenter(center, y, 26, true, title)
+	y -= 50
+	p.textCenter(center, y, 36, true, FormatMoney(cert.Value))
+
+	y -= 40
+	if cert.To != "" {
+		p.textCenter(center, y, 12, false, "For "+cert.To)
+		y -= 18
+	}
+	if cert.Giver != "" {
+		p.textCenter(center, y, 12, false, "From "+cert.Giver)
+		y -= 18
+	}
+	if cert.Message != "" {
+		y -= 4
+		for _, text := range wrapText(cert.Message, 10, false, colAmtRight-margin-96) {
+			if y < bottom+70 {
+				break
+			}
+			p.textCenter(center, y, 10, false, text)
+			y -= 13
+		}
+	}
+
+	p.line(margin+24, bottom+52, colAmtRight-24, bottom+52)
+	p.text(margin+24, bottom+34, 9, true, "CODE")
+	p.text(margin+24, bottom+20, 13, true, cert.Code)
+	if !cert.ExpiresAt.IsZero() {
+		p.textRight(colAmtRight-24, bottom+34, 9, true, "VALID UNTIL")
+		p.textRight(colAmtRight-24, bottom+20, 11, false, cert.ExpiresAt.Format("January 2, 2006"))
+	}
+
+	footY := bottom - 24
+	for _, line := range cert.From.Lines {
+		p.textCenter(center, footY, 9, false, line)
+		footY -= 12
+	}
+	p.textCenter(center, footY-6, 8, false, "Redeemable toward any service. Not exchangeable for cash.")
+
+	return p.writeTo(w)
+}
+
+func (p *pdfWriter) textCenter(center, y, size float64, bold bool, s string) {
+	p.text(center-textWidth(s, size, bold)/2, y, size, bold, s)
+}
@@ -0,0 +1,159 @@
+// Package invoice works out invoice totals and renders invoices and gift
+// certificates to PDF. Amounts are in cents and tax rates in basis points
+// (825 is 8.25%), the same units the database stores.
+package invoice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Discount kinds
+const (
+	DiscountNone    = ""
+	DiscountAmount  = "amount"  // Value is cents
+	DiscountPercent = "percent" // Value is basis points
+)
+
+// Line is an invoice line item.
+type Line struct {
+	Description string
+	Quantity    int64
+	UnitPrice   int64
+}
+
+func (l Line) Amount() int64 {
+	return l.Quantity * l.UnitPrice
+}
+
+// Discount comes off the subtotal before tax.
+type Discount struct {
+	Kind  string
+	Value int64
+}
+
+// Totals is the money summary of an invoice.
+type Totals struct {
+	Subtotal int64
+	Discount int64
+	Taxable  int64
+	Tax      int64
+	Total    int64
+}
+
+// Compute totals the lines, takes off the discount, then adds tax at
+// taxRate basis points. A discount never takes the total below zero.
+func Compute(lines []Line, discount Discount, taxRate int64) Totals {
+	var t Totals
+	for _, l := range lines {
+		t.Subtotal += l.Amount()
+	}
+
+	switch discount.Kind {
+	case DiscountAmount:
+		t.Discount = discount.Value
+	case DiscountPercent:
+		t.Discount = roundDiv(t.Subtotal*discount.Value, 10000)
+	}
+	if t.Discount > t.Subtotal {
+		t.Discount = t.Subtotal
+	}
+	if t.Discount < 0 {
+		t.Discount = 0
+	}
+
+	t.Taxable = t.Subtotal - t.Discount
+	t.Tax = roundDiv(t.Taxable*taxRate, 10000)
+	t.Total = t.Taxable + t.Tax
+	return t
+}
+
+// roundDiv divides rounding half away from zero, as for cents.
+func roundDiv(n, d int64) int64 {
+	if n < 0 {
+		return -roundDiv(-n, d)
+	}
+	return (n + d/2) / d
+}
+
+// FormatRate renders basis points as a percentage, e.g. 825 as "8.25%".
+func FormatRate(bps int64) string {
+	s := strconv.FormatFloat(float64(bps)/100, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s + "%"
+}
+
+// FormatMoney renders cents as dollars, e.g. "$1,250.00".
+func FormatMoney(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	dollars := strconv.FormatInt(cents/100, 10)
+	for i := len(dollars) - 3; i > 0; i -= 3 {
+		dollars = dollars[:i] + "," + dollars[i:]
+	}
+	return fmt.Sprintf("%s$%s.%02d", sign, dollars, cents%100)
+}
+
+// Party is who an invoice is from or to.
+type Party struct {
+	Name  string
+	Lines []string // address, phone, email
+}
+
+// Document is everything printed on an invoice.
+type Document struct {
+	Number   string
+	Status   string
+	IssuedAt time.Time
+	DueAt    time.Time
+	From     Party
+	To       Party
+	Lines    []Line
+	Discount Discount
+	TaxName  string
+	TaxRate  int64
+	Credits  []Credit
+	Notes    string
+}
+
+// Credit is money already put towards an invoice, such as a gift
+// certificate.
+type Credit struct {
+	Label  string
+	Amount int64
+}
+
+func (d Document) Totals() Totals {
+	return Compute(d.Lines, d.Discount, d.TaxRate)
+}
+
+// AmountDue is what's left to pay after credits.
+func (d Document) AmountDue() int64 {
+	due := d.Totals().Total
+	for _, c := range d.Credits {
+		due -= c.Amount
+	}
+	return max(due, 0)
+}
+
+// DiscountLabel describes the discount line, e.g. "Discount (10%)".
+func (d Document) DiscountLabel() string {
+	if d.Discount.Kind == DiscountPercent {
+		return "Discount (" + FormatRate(d.Discount.Value) + ")"
+	}
+	return "Discount"
+}
+
+// TaxLabel describes the tax line, e.g. "Sales tax (8.25%)".
+func (d Document) TaxLabel() string {
+	name := d.TaxName
+	if name == "" {
+		name = "Tax"
+	}
+	return name + " (" + FormatRate(d.TaxRate) + ")"
+}
@@ -0,0 +1,220 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []Line
+		discount Discount
+		taxRate  int64
+		want     Totals
+	}{
+		{
+			name:  "no tax or discount",
+			lines: []Line{{Quantity: 1, UnitPrice: 15000}, {Quantity: 2, UnitPrice: 2500}},
+			want:  Totals{Subtotal: 20000, Taxable: 20000, Total: 20000},
+		},
+		{
+			name:    "tax",
+			lines:   []Line{{Quantity: 1, UnitPrice: 20000}},
+			taxRate: 825,
+			want:    Totals{Subtotal: 20000, Taxable: 20000, Tax: 1650, Total: 21650},
+		},
+		{
+			// 8.25% of $19.99 is 164.9175 cents
+			name:    "tax rounds to the nearest cent",
+			lines:   []Line{{Quantity: 1, UnitPrice: 1999}},
+			taxRate: 825,
+			want:    Totals{Subtotal: 1999, Taxable: 1999, Tax: 165, Total: 2164},
+		},
+		{
+			// 5% of $0.10 is exactly half a cent
+			name:    "tax rounds half up",
+			lines:   []Line{{Quantity: 1, UnitPrice: 10}},
+			taxRate: 500,
+			want:    Totals{Subtotal: 10, Taxable: 10, Tax: 1, Total: 11},
+		},
+		{
+			// 5% of $0.09 is 0.45 cents
+			name:    "tax rounds down below half",
+			lines:   []Line{{Quantity: 1, UnitPrice: 9}},
+			taxRate: 500,
+			want:    Totals{Subtotal: 9, Taxable: 9, Total: 9},
+		},
+		{
+			name:     "amount discount before tax",
+			lines:    []Line{{Quantity: 1, UnitPrice: 20000}},
+			discount: Discount{Kind: DiscountAmount, Value: 5000},
+			taxRate:  1000,
+			want:     Totals{Subtotal: 20000, Discount: 5000, Taxable: 15000, Tax: 1500, Total: 16500},
+		},
+		{
+			// 15% of $33.33 is 499.95 cents
+			name:     "percent discount rounds",
+			lines:    []Line{{Quantity: 1, UnitPrice: 3333}},
+			discount: Discount{Kind: DiscountPercent, Value: 1500},
+			want:     Totals{Subtotal: 3333, Discount: 500, Taxable: 2833, Total: 2833},
+		},
+		{
+			name:     "discount capped at the subtotal",
+			lines:    []Line{{Quantity: 1, UnitPrice: 4000}},
+			discount: Discount{Kind: DiscountAmount, Value: 5000},
+			taxRate:  825,
+			want:     Totals{Subtotal: 4000, Discount: 4000},
+		},
+		{
+			name:     "over 100% off",
+			lines:    []Line{{Quantity: 1, UnitPrice: 4000}},
+			discount: Discount{Kind: DiscountPercent, Value: 15000},
+			want:     Totals{Subtotal: 4000, Discount: 4000},
+		},
+		{
+			name:     "negative discount ignored",
+			lines:    []Line{{Quantity: 1, UnitPrice: 4000}},
+			discount: Discount{Kind: DiscountAmount, Value: -500},
+			want:     Totals{Subtotal: 4000, Taxable: 4000, Total: 4000},
+		},
+		{
+			name:     "unknown discount kind ignored",
+			lines:    []Line{{Quantity: 1, UnitPrice: 4000}},
+			discount: Discount{Kind: "bogo", Value: 2000},
+			want:     Totals{Subtotal: 4000, Taxable: 4000, Total: 4000},
+		},
+		{
+			name:  "credit line",
+			lines: []Line{{Quantity: 1, UnitPrice: 10000}, {Quantity: 1, UnitPrice: -2500}},
+			want:  Totals{Subtotal: 7500, Taxable: 7500, Total: 7500},
+		},
+		{name: "no lines", taxRate: 825},
+	}
+	for _, tt := range tests {
+		if got := Compute(tt.lines, tt.discount, tt.taxRate); got != tt.want {
+			t.Errorf("%s: Compute = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRoundDiv(t *testing.T) {
+	tests := []struct{ n, d, want int64 }{
+		{149, 100, 1},
+		{150, 100, 2},
+		{151, 100, 2},
+		{-149, 100, -1},
+		{-150, 100, -2},
+		{0, 100, 0},
+	}
+	for _, tt := range tests {
+		if got := roundDiv(tt.n, tt.d); got != tt.want {
+			t.Errorf("roundDiv(%d, %d) = %d, want %d", tt.n, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		cents int64
+		want  string
+	}{
+		{0, "$0.00"},
+		{5, "$0.05"},
+		{99999, "$999.99"},
+		{125000, "$1,250.00"},
+		{123456789, "$1,234,567.89"},
+		{-2500, "-$25.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatMoney(tt.cents); got != tt.want {
+			t.Errorf("FormatMoney(%d) = %q, want %q", tt.cents, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		bps  int64
+		want string
+	}{
+		{825, "8.25%"},
+		{800, "8%"},
+		{750, "7.5%"},
+		{0, "0%"},
+		{10000, "100%"},
+	}
+	for _, tt := range tests {
+		if got := FormatRate(tt.bps); got != tt.want {
+			t.Errorf("FormatRate(%d) = %q, want %q", tt.bps, got, tt.want)
+		}
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc := Document{
+		Lines:    []Line{{Quantity: 1, UnitPrice: 20000}},
+		Discount: Discount{Kind: DiscountPercent, Value: 1000},
+		TaxName:  "Sales tax",
+		TaxRate:  825,
+	}
+	// $200 less 10% is $180, plus $14.85 tax
+	if got := doc.AmountDue(); got != 19485 {
+		t.Errorf("AmountDue = %d, want 19485", got)
+	}
+	doc.Credits = []Credit{{Label: "Gift certificate", Amount: 5000}}
+	if got := doc.AmountDue(); got != 14485 {
+		t.Errorf("AmountDue with a credit = %d, want 14485", got)
+	}
+	doc.Credits = append(doc.Credits, Credit{Label: "Gift certificate", Amount: 50000})
+	if got := doc.AmountDue(); got != 0 {
+		t.Errorf("AmountDue with credits over the total = %d, want 0", got)
+	}
+
+	if got, want := doc.DiscountLabel(), "Discount (10%)"; got != want {
+		t.Errorf("DiscountLabel = %q, want %q", got, want)
+	}
+	if got, want := doc.TaxLabel(), "Sales tax (8.25%)"; got != want {
+		t.Errorf("TaxLabel = %q, want %q", got, want)
+	}
+	doc.TaxName = ""
+	doc.Discount = Discount{Kind: DiscountAmount, Value: 500}
+	if got, want := doc.TaxLabel(), "Tax (8.25%)"; got != want {
+		t.Errorf("TaxLabel without a name = %q, want %q", got, want)
+	}
+	if got, want := doc.DiscountLabel(), "Discount"; got != want {
+		t.Errorf("DiscountLabel = %q, want %q", got, want)
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	doc := Document{
+		Number:   "INV-0042",
+		Status:   "sent",
+		IssuedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		DueAt:    time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+		From:     Party{Name: "C Auto Detailing Studio", Lines: []string{"123 Main St"}},
+		To:       Party{Name: "Dana Whitfield", Lines: []string{"dana@example.com"}},
+		TaxRate:  825,
+		Notes:    "Thanks for your business — see you next season.",
+	}
+	// Enough lines to run onto a second page
+	for i := 0; i < 60; i++ {
+		doc.Lines = append(doc.Lines, Line{Description: fmt.Sprintf("Line %d (café)", i), Quantity: 1, UnitPrice: 1000})
+	}
+
+	var buf bytes.Buffer
+	if err := RenderPDF(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-") || !strings.HasSuffix(strings.TrimSpace(out), "%%EOF") {
+		t.Fatalf("not a PDF: %.40q", out)
+	}
+	if pages := strings.Count(out, "/Type /Page "); pages != 2 || !strings.Contains(out, "/Count 2 >>") {
+		t.Errorf("rendered %d pages, want 60 lines over 2", pages)
+	}
+}
@@ -0,0 +1,363 @@
+package invoice
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Letter size in points, with the margin used on every side
+const (
+	pageWidth  = 612.0
+	pageHeight = 792.0
+	margin     = 54.0
+)
+
+// Table columns: right edges for the number columns
+const (
+	colDescWidth = 290.0
+	colQtyRight  = 400.0
+	colUnitRight = 480.0
+	colAmtRight  = pageWidth - margin
+	rowHeight    = 16.0
+	footerSpace  = 150.0 // room kept at the bottom of a page for totals
+)
+
+// RenderPDF writes the invoice as a PDF. It only uses the standard
+// Helvetica fonts, which every PDF reader has, so nothing is embedded.
+func RenderPDF(w io.Writer, doc Document) error {
+	p := &pdfWriter{}
+	p.newPage()
+
+	y := pageHeight - margin
+	p.text(margin, y-16, 20, true, doc.From.Name)
+	fromY := y - 34
+	for _, line := range doc.From.Lines {
+		p.text(margin, fromY, 9, false, line)
+		fromY -= 12
+	}
+
+	title := "INVOICE"
+	if doc.Status == "void" {
+		title = "VOID"
+	}
+	p.textRight(colAmtRight, y-18, 22, true, title)
+	metaY := y - 36
+	meta := [][2]string{}
+	if doc.Number != "" {
+		meta = append(meta, [2]string{"Invoice", doc.Number})
+	} else {
+		meta = append(meta, [2]string{"Invoice", "Draft"})
+	}
+	if !doc.IssuedAt.IsZero() {
+		meta = append(meta, [2]string{"Issued", doc.IssuedAt.Format("January 2, 2006")})
+	}
+	if !doc.DueAt.IsZero() {
+		meta = append(meta, [2]string{"Due", doc.DueAt.Format("January 2, 2006")})
+	}
+	if doc.Status == "paid" {
+		meta = append(meta, [2]string{"Status", "Paid"})
+	}
+	for _, m := range meta {
+		p.textRight(colUnitRight-6, metaY, 9, false, m[0])
+		p.textRight(colAmtRight, metaY, 9, true, m[1])
+		metaY -= 12
+	}
+
+	y = min(fromY, metaY) - 24
+	p.text(margin, y, 9, true, "BILL TO")
+	y -= 14
+	p.text(margin, y, 11, true, doc.To.Name)
+	for _, line := range doc.To.Lines {
+		y -= 13
+		p.text(margin, y, 10, false, line)
+	}
+
+	y -= 32
+	y = p.tableHeader(y)
+	for _, line := range doc.Lines {
+		wrapped := wrapText(line.Description, 10, false, colDescWidth)
+		needed := float64(len(wrapped)) * rowHeight
+		if y-needed < margin+40 {
+			p.newPage()
+			y = p.tableHeader(pageHeight - margin)
+		}
+		p.textRight(colQtyRight, y, 10, false, strconv.FormatInt(line.Quantity, 10))
+		p.textRight(colUnitRight, y, 10, false, FormatMoney(line.UnitPrice))
+		p.textRight(colAmtRight, y, 10, false, FormatMoney(line.Amount()))
+		for _, text := range wrapped {
+			p.text(margin, y, 10, false, text)
+			y -= rowHeight
+		}
+	}
+
+	if y < margin+footerSpace {
+		p.newPage()
+		y = pageHeight - margin
+	}
+	p.line(margin, y+rowHeight-6, colAmtRight, y+rowHeight-6)
+	y -= 6
+
+	totals := doc.Totals()
+	rows := [][2]string{{"Subtotal", FormatMoney(totals.Subtotal)}}
+	if totals.Discount > 0 {
+		rows = append(rows, [2]string{doc.DiscountLabel(), "-" + FormatMoney(totals.Discount)})
+	}
+	if doc.TaxRate > 0 {
+		rows = append(rows, [2]string{doc.TaxLabel(), FormatMoney(totals.Tax)})
+	}
+	for _, r := range rows {
+		p.textRight(colUnitRight, y, 10, false, r[0])
+		p.textRight(colAmtRight, y, 10, false, r[1])
+		y -= rowHeight
+	}
+	p.line(colQtyRight, y+rowHeight-4, colAmtRight, y+rowHeight-4)
+	y -= 4
+	p.textRight(colUnitRight, y, 12, true, "Total")
+	p.textRight(colAmtRight, y, 12, true, FormatMoney(totals.Total))
+
+	if len(doc.Credits) > 0 {
+		y -= 6
+		for _, credit := range doc.Credits {
+			y -= rowHeight
+			if y < margin {
+				p.newPage()
+				y = pageHeight - margin
+			}
+			p.textRight(colUnitRight, y, 10, false, credit.Label)
+			p.textRight(colAmtRight, y, 10, false, "-"+FormatMoney(credit.Amount))
+		}
+		y -= rowHeight + 4
+		p.textRight(colUnitRight, y, 12, true, "Balance due")
+		p.textRight(colAmtRight, y, 12, true, FormatMoney(doc.AmountDue()))
+	}
+
+	if doc.Notes != "" {
+		y -= 36
+		for _, para := range strings.Split(doc.Notes, "\n") {
+			for _, text := range wrapText(para, 9, false, pageWidth-2*margin) {
+				if y < margin {
+					p.newPage()
+					y = pageHeight - margin
+				}
+				p.text(margin, y, 9, false, text)
+				y -= 12
+			}
+		}
+	}
+
+	return p.writeTo(w)
+}
+
+func (p *pdfWriter) tableHeader(y float64) float64 {
+	p.text(margin, y, 9, true, "DESCRIPTION")
+	p.textRight(colQtyRight, y, 9, true, "QTY")
+	p.textRight(colUnitRight, y, 9, true, "UNIT PRICE")
+	p.textRight(colAmtRight, y, 9, true, "AMOUNT")
+	p.line(margin, y-6, colAmtRight, y-6)
+	return y - 22
+}
+
+// pdfWriter builds a minimal PDF: text in Helvetica and Helvetica-Bold,
+// and straight lines, over any number of pages.
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	cur   *bytes.Buffer
+}
+
+func (p *pdfWriter) newPage() {
+	p.cur = &bytes.Buffer{}
+	p.pages = append(p.pages, p.cur)
+}
+
+func (p *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	if s == "" {
+		return
+	}
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.cur, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, num(size), num(x), num(y), pdfString(s))
+}
+
+func (p *pdfWriter) textRight(right, y, size float64, bold bool, s string) {
+	p.text(right-textWidth(s, size, bold), y, size, bold, s)
+}
+
+func (p *pdfWriter) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(p.cur, "0.8 G 0.75 w %s %s m %s %s l S 0 G\n", num(x1), num(y1), num(x2), num(y2))
+}
+
+func (p *pdfWriter) writeTo(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1 catalog, 2 page tree, 3-4 fonts, then a page and its content
+	// stream for each page
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range p.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(pageWidth), num(pageHeight), 6+2*i))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		obj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+// winAnsiExtras maps the characters outside Latin-1 that WinAnsiEncoding
+// has room for.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// winAnsi encodes s for the standard fonts, replacing what they can't
+// show with "?".
+func winAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		case r == '\t':
+			out = append(out, ' ')
+		default:
+			if b, ok := winAnsiExtras[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, c := range winAnsi(s) {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Glyph widths in thousandths of the font size for ASCII 32-126, from the
+// standard Helvetica metrics. Anything else is measured as defaultWidth.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+const defaultWidth = 556
+
+func textWidth(s string, size float64, bold bool) float64 {
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, c := range winAnsi(s) {
+		if c >= 32 && c <= 126 {
+			total += widths[c-32]
+		} else {
+			total += defaultWidth
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// wrapText breaks s into lines no wider than maxWidth, splitting words
+// only when a single word is too wide on its own.
+func wrapText(s string, size float64, bold bool, maxWidth float64) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	current := ""
+	for _, word := range words {
+		for textWidth(word, size, bold) > maxWidth {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			runes := []rune(word)
+			n := len(runes) - 1
+			for n > 1 && textWidth(string(runes[:n]), size, bold) > maxWidth {
+				n--
+			}
+			lines = append(lines, string(runes[:n]))
+			word = string(runes[n:])
+		}
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if textWidth(candidate, size, bold) <= maxWidth {
+			current = candidate
+			continue
+		}
+		lines = append(lines, current)
+		current = word
+	}
+	return append(lines, current)
+}
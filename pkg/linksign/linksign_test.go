@@ -0,0 +1,105 @@
+package linksign
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := New([]byte("secret"))
+	token := s.Sign("review", 42, now.Add(time.Hour))
+
+	flip := func(tok string, i int) string {
+		b := []byte(tok)
+		if b[i] == 'A' {
+			b[i] = 'B'
+		} else {
+			b[i] = 'A'
+		}
+		return string(b)
+	}
+	forged := New([]byte("secret")).Sign("review", 43, now.Add(time.Hour))
+	idPart, rest, _ := strings.Cut(token, ".")
+	_, forgedRest, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name    string
+		signer  *Signer
+		purpose string
+		token   string
+		now     time.Time
+		wantID  int64
+		wantErr error
+	}{
+		{name: "valid", signer: s, purpose: "review", token: token, now: now, wantID: 42},
+		{name: "at expiry", signer: s, purpose: "review", token: token, now: now.Add(time.Hour), wantID: 42},
+		{name: "expired", signer: s, purpose: "review", token: token, now: now.Add(time.Hour + time.Second), wantErr: ErrExpired},
+		{name: "other purpose", signer: s, purpose: "quote", token: token, now: now, wantErr: ErrInvalid},
+		{name: "other key", signer: New([]byte("other")), purpose: "review", token: token, now: now, wantErr: ErrInvalid},
+		{name: "tampered signature", signer: s, purpose: "review", token: flip(token, len(token)-1), now: now, wantErr: ErrInvalid},
+		{name: "tampered id", signer: s, purpose: "review", token: "17." + rest, now: now, wantErr: ErrInvalid},
+		{name: "id swapped between tokens", signer: s, purpose: "review", token: idPart + "." + forgedRest, now: now, wantErr: ErrInvalid},
+		{name: "extended expiry", signer: s, purpose: "review", token: strings.Replace(token, rest[:strings.Index(rest, ".")], "zzzzzz", 1), now: now, wantErr: ErrInvalid},
+		{name: "empty", signer: s, purpose: "review", token: "", now: now, wantErr: ErrInvalid},
+		{name: "no separators", signer: s, purpose: "review", token: "abcdef", now: now, wantErr: ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := tt.signer.Verify(tt.purpose, tt.token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if id != tt.wantID {
+				t.Errorf("id = %d, want %d", id, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestTokenIsURLSafe(t *testing.T) {
+	s := New([]byte("secret"))
+	token := s.Sign("invoice", 1<<40, time.Now().Add(365*24*time.Hour))
+	if strings.ContainsAny(token, "+/=?&# ") {
+		t.Errorf("token %q isn't URL-safe", token)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{key: "", wantErr: true},
+		{key: "   ", wantErr: true},
+		{key: "change_me", wantErr: true},
+		{key: "a-real-secret"},
+	}
+	for _, tt := range tests {
+		t.Setenv("LINK_SIGNING_KEY", tt.key)
+		s, err := FromEnv()
+		if tt.wantErr && !errors.Is(err, ErrNoKey) {
+			t.Errorf("key %q: err = %v, want ErrNoKey", tt.key, err)
+		}
+		if (err != nil) != tt.wantErr {
+			t.Errorf("key %q: err = %v", tt.key, err)
+			continue
+		}
+		if err == nil {
+			token := s.Sign("review", 1, time.Now().Add(time.Hour))
+			if _, err := New([]byte(tt.key)).Verify("review", token, time.Now()); err != nil {
+				t.Errorf("key %q: token didn't verify with the same key: %v", tt.key, err)
+			}
+		}
+	}
+}
+
+func TestNilSignerVerifiesNothing(t *testing.T) {
+	token := New([]byte("secret")).Sign("review", 1, time.Now().Add(time.Hour))
+	var s *Signer
+	if _, err := s.Verify("review", token, time.Now()); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Verify() err = %v, want ErrNoKey", err)
+	}
+}
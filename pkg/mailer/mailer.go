@@ -0,0 +1,98 @@
+// Package mailer sends transactional email. SMTP is used when SMTP_HOST is
+// configured; otherwise messages are written to the log so development
+// flows (review links, replies) can be followed without a mail server.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Message is a plain-text email with an optional HTML alternative and
+// optional attachments.
+type Message struct {
+	To          []string
+	ReplyTo     string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Attachment is a file sent with a message, such as an invoice PDF.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer delivers messages.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var ErrNoRecipients = errors.New("mailer: message has no recipients")
+
+// FromEnv returns an SMTP mailer configured from SMTP_HOST, SMTP_PORT,
+// SMTP_USER, SMTP_PASS and SMTP_FROM, or a LogMailer when SMTP_HOST is
+// unset.
+func FromEnv() Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return LogMailer{}
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USER")
+	}
+	return &SMTP{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USER"),
+		Password: os.Getenv("SMTP_PASS"),
+		From:     from,
+		FromName: os.Getenv("SITE_NAME"),
+	}
+}
+
+// LogMailer prints messages instead of sending them.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	log.Printf("📧 Email to %s: %s\n%s", strings.Join(msg.To, ", "), msg.Subject, msg.Text)
+	for _, a := range msg.Attachments {
+		log.Printf("📎 Attachment %s (%s, %d bytes)", a.Filename, a.ContentType, len(a.Data))
+	}
+	return nil
+}
+
+func validateMessage(msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	for _, addr := range append([]string{msg.ReplyTo}, msg.To...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return fmt.Errorf("mailer: invalid address %q", addr)
+		}
+	}
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return errors.New("mailer: subject contains a line break")
+	}
+	for _, a := range msg.Attachments {
+		if a.Filename == "" || strings.ContainsAny(a.Filename, "\r\n\"") {
+			return fmt.Errorf("mailer: invalid attachment name %q", a.Filename)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Package membership works out membership periods, what a member can still
+// book in them, and churn. Like promo, it knows nothing about storage;
+// callers load the membership and count the bookings made with it.
+package membership
+
+import (
+	"errors"
+	"time"
+)
+
+// Reasons a booking can't use a membership. Callers word them for
+// customers.
+var (
+	ErrCancelled = errors.New("membership: cancelled")
+	ErrLapsed    = errors.New("membership: not paid through that date")
+	ErrService   = errors.New("membership: service not included")
+	ErrUsedUp    = errors.New("membership: no services left in the period")
+)
+
+// Membership is a member's plan and how long it's paid for.
+type Membership struct {
+	ServicesPerPeriod int64
+	PeriodMonths      int64
+	PackageID         int64 // the service included; 0 for any
+	StartedAt         time.Time
+	PaidThrough       time.Time
+	Active            bool
+}
+
+// Period returns the period containing at. Periods run PeriodMonths at a
+// time from StartedAt, so someone who joined on the 15th renews on the
+// 15th. Someone who joined on the 31st renews on the last day of shorter
+// months, and on the 31st again after them.
+func (m Membership) Period(at time.Time) (from, to time.Time) {
+	months := int(max(m.PeriodMonths, 1))
+	elapsed := (at.Year()-m.StartedAt.Year())*12 + int(at.Month()-m.StartedAt.Month())
+	n := elapsed / months
+	if elapsed < 0 {
+		n = (elapsed - months + 1) / months
+	}
+	// The estimate can be off by one either way around month ends
+	for m.renewal(n * months).After(at) {
+		n--
+	}
+	for !m.renewal((n + 1) * months).After(at) {
+		n++
+	}
+	return m.renewal(n * months), m.renewal((n + 1) * months)
+}
+
+// renewal is StartedAt plus months, on the same wall-clock time. Unlike
+// AddDate it doesn't roll a missing day over into the next month: a month
+// after Jan 31 is Feb 28, not Mar 3.
+func (m Membership) renewal(months int) time.Time {
+	year, month, day := m.StartedAt.Date()
+	hour, minute, sec := m.StartedAt.Clock()
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(year, month+time.Month(months), min(day, lastDay), hour, minute, sec, m.StartedAt.Nanosecond(), m.StartedAt.Location())
+}
+
+// Remaining is how many services are left in a period in which used have
+// already been booked.
+func (m Membership) Remaining(used int64) int64 {
+	return max(m.ServicesPerPeriod-used, 0)
+}
+
+// InGoodStanding reports whether the membership is active and paid up at
+// time at, which is what earns priority booking.
+func (m Membership) InGoodStanding(at time.Time) bool {
+	return m.Active && at.Before(m.PaidThrough)
+}
+
+// Check reports why an appointment at at for packageID can't use the
+// membership, or nil when it can. used is the number of services already
+// booked in the period the appointment falls in.
+func (m Membership) Check(at time.Time, packageID int64, used int64) error {
+	switch {
+	case !m.Active:
+		return ErrCancelled
+	case !at.Before(m.PaidThrough):
+		return ErrLapsed
+	case m.PackageID != 0 && packageID != m.PackageID:
+		return ErrService
+	case m.Remaining(used) == 0:
+		return ErrUsedUp
+	}
+	return nil
+}
+
+// Span is when a membership started and, once cancelled, when it ended.
+type Span struct {
+	Start time.Time
+	End   time.Time // zero while active
+}
+
+// Churn is how membership changed over a stretch of time.
+type Churn struct {
+	ActiveAtStart int
+	Joined        int
+	Cancelled     int
+	ActiveAtEnd   int
+}
+
+// Rate is the share of members at the start who cancelled, from 0 to 1.
+// Members who joined and cancelled within the stretch aren't counted.
+func (c Churn) Rate() float64 {
+	if c.ActiveAtStart == 0 {
+		return 0
+	}
+	return float64(c.Cancelled) / float64(c.ActiveAtStart)
+}
+
+// ChurnBetween tallies spans over [from, to).
+func ChurnBetween(spans []Span, from, to time.Time) Churn {
+	var c Churn
+	activeAt := func(s Span, t time.Time) bool {
+		return s.Start.Before(t) && (s.End.IsZero() || !s.End.Before(t))
+	}
+	for _, s := range spans {
+		wasActive := activeAt(s, from)
+		if wasActive {
+			c.ActiveAtStart++
+		}
+		if !s.Start.Before(from) && s.Start.Before(to) {
+			c.Joined++
+		}
+		if wasActive && !s.End.IsZero() && s.End.Before(to) {
+			c.Cancelled++
+		}
+		if activeAt(s, to) {
+			c.ActiveAtEnd++
+		}
+	}
+	return c
+}
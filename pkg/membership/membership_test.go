@@ -0,0 +1,138 @@
+package membership
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPeriod(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, ny)
+	}
+
+	tests := []struct {
+		name     string
+		started  time.Time
+		months   int64
+		at       time.Time
+		from, to time.Time
+	}{
+		{name: "first period", started: day(2026, 1, 15), months: 1, at: day(2026, 1, 20), from: day(2026, 1, 15), to: day(2026, 2, 15)},
+		{name: "the moment it starts", started: day(2026, 1, 15), months: 1, at: day(2026, 1, 15), from: day(2026, 1, 15), to: day(2026, 2, 15)},
+		{name: "last moment of a period", started: day(2026, 1, 15), months: 1, at: day(2026, 2, 15).Add(-time.Nanosecond), from: day(2026, 1, 15), to: day(2026, 2, 15)},
+		{name: "renewal day", started: day(2026, 1, 15), months: 1, at: day(2026, 2, 15), from: day(2026, 2, 15), to: day(2026, 3, 15)},
+		{name: "a year on", started: day(2026, 1, 15), months: 1, at: day(2027, 1, 16), from: day(2027, 1, 15), to: day(2027, 2, 15)},
+		{name: "before it started", started: day(2026, 1, 15), months: 1, at: day(2025, 12, 20), from: day(2025, 12, 15), to: day(2026, 1, 15)},
+		{name: "quarterly", started: day(2026, 1, 15), months: 3, at: day(2026, 6, 1), from: day(2026, 4, 15), to: day(2026, 7, 15)},
+		{name: "yearly", started: day(2026, 1, 15), months: 12, at: day(2027, 3, 1), from: day(2027, 1, 15), to: day(2028, 1, 15)},
+		{name: "no period length is monthly", started: day(2026, 1, 15), months: 0, at: day(2026, 2, 20), from: day(2026, 2, 15), to: day(2026, 3, 15)},
+
+		// Month ends
+		{name: "joined Jan 31, in January", started: day(2026, 1, 31), months: 1, at: day(2026, 2, 10), from: day(2026, 1, 31), to: day(2026, 2, 28)},
+		{name: "joined Jan 31, renews Feb 28", started: day(2026, 1, 31), months: 1, at: day(2026, 2, 28), from: day(2026, 2, 28), to: day(2026, 3, 31)},
+		{name: "joined Jan 31, leap year", started: day(2028, 1, 31), months: 1, at: day(2028, 2, 29), from: day(2028, 2, 29), to: day(2028, 3, 31)},
+		{name: "joined Jan 31, March", started: day(2026, 1, 31), months: 1, at: day(2026, 3, 30), from: day(2026, 2, 28), to: day(2026, 3, 31)},
+		{name: "joined Jan 31, back to the 31st", started: day(2026, 1, 31), months: 1, at: day(2026, 4, 5), from: day(2026, 3, 31), to: day(2026, 4, 30)},
+		{name: "joined Jan 30", started: day(2026, 1, 30), months: 1, at: day(2026, 3, 1), from: day(2026, 2, 28), to: day(2026, 3, 30)},
+		{name: "joined Feb 29, next year", started: day(2028, 2, 29), months: 12, at: day(2029, 3, 1), from: day(2029, 2, 28), to: day(2030, 2, 28)},
+		{name: "quarterly from Nov 30", started: day(2025, 11, 30), months: 3, at: day(2026, 3, 1), from: day(2026, 2, 28), to: day(2026, 5, 30)},
+		{name: "before joining on the 31st", started: day(2026, 3, 31), months: 1, at: day(2026, 3, 1), from: day(2026, 2, 28), to: day(2026, 3, 31)},
+
+		// Periods turn over at midnight in the shop's time zone, on either
+		// side of a clock change
+		{name: "into daylight time", started: day(2026, 2, 8), months: 1, at: day(2026, 3, 8).Add(-time.Minute), from: day(2026, 2, 8), to: day(2026, 3, 8)},
+		{name: "out of daylight time", started: day(2026, 10, 1), months: 1, at: day(2026, 11, 1).Add(30 * time.Minute), from: day(2026, 11, 1), to: day(2026, 12, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Membership{PeriodMonths: tt.months, StartedAt: tt.started}
+			from, to := m.Period(tt.at)
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("Period(%s) = %s – %s, want %s – %s", tt.at, from, to, tt.from, tt.to)
+			}
+			if from.Hour() != 0 || to.Hour() != 0 {
+				t.Errorf("Period(%s) = %s – %s, want midnight", tt.at, from, to)
+			}
+		})
+	}
+}
+
+func TestPeriodsDontOverlap(t *testing.T) {
+	m := Membership{PeriodMonths: 1, StartedAt: time.Date(2026, 1, 31, 9, 30, 0, 0, time.UTC)}
+	from, to := m.Period(m.StartedAt)
+	for range 36 {
+		nextFrom, nextTo := m.Period(to)
+		if !nextFrom.Equal(to) {
+			t.Fatalf("period after %s – %s starts %s", from, to, nextFrom)
+		}
+		from, to = nextFrom, nextTo
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 5, 10, 14, 0, 0, 0, time.UTC)
+	member := Membership{ServicesPerPeriod: 2, PeriodMonths: 1, PackageID: 4, PaidThrough: now.AddDate(0, 1, 0), Active: true}
+
+	tests := []struct {
+		name      string
+		m         Membership
+		at        time.Time
+		packageID int64
+		used      int64
+		want      error
+	}{
+		{name: "included", m: member, at: now, packageID: 4},
+		{name: "last service of the period", m: member, at: now, packageID: 4, used: 1},
+		{name: "used up", m: member, at: now, packageID: 4, used: 2, want: ErrUsedUp},
+		{name: "another service", m: member, at: now, packageID: 5, want: ErrService},
+		{name: "no service chosen", m: member, at: now, want: ErrService},
+		{name: "any service", m: Membership{ServicesPerPeriod: 1, PaidThrough: member.PaidThrough, Active: true}, at: now, packageID: 9},
+		{name: "the moment it lapses", m: member, at: member.PaidThrough, packageID: 4, want: ErrLapsed},
+		{name: "cancelled", m: Membership{ServicesPerPeriod: 2, PackageID: 4, PaidThrough: member.PaidThrough}, at: now, packageID: 4, want: ErrCancelled},
+	}
+	for _, tt := range tests {
+		if err := tt.m.Check(tt.at, tt.packageID, tt.used); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Check = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if got := member.Remaining(5); got != 0 {
+		t.Errorf("Remaining(5) = %d, want 0", got)
+	}
+	if member.InGoodStanding(member.PaidThrough) || !member.InGoodStanding(now) {
+		t.Error("InGoodStanding doesn't end when the membership is paid through")
+	}
+}
+
+func TestChurnBetween(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 4, d, 12, 0, 0, 0, time.UTC) }
+	from, to := day(1), day(30)
+
+	spans := []Span{
+		{Start: day(1).AddDate(0, -6, 0)},                              // stayed
+		{Start: day(1).AddDate(0, -6, 0), End: day(10)},                // cancelled
+		{Start: day(1).AddDate(0, -2, 0), End: day(30)},                // still a member as the stretch ends
+		{Start: day(1).AddDate(0, -2, 0), End: day(1)},                 // cancelled the moment it began
+		{Start: day(1).AddDate(-1, 0, 0), End: day(1).Add(-time.Hour)}, // gone before
+		{Start: day(5)},               // joined
+		{Start: day(6), End: day(20)}, // joined and left
+		{Start: day(30)},              // joins after
+		{Start: day(1)},               // joins as it begins
+	}
+	got := ChurnBetween(spans, from, to)
+	want := Churn{ActiveAtStart: 4, Joined: 3, Cancelled: 2, ActiveAtEnd: 4}
+	if got != want {
+		t.Errorf("ChurnBetween = %+v, want %+v", got, want)
+	}
+	if rate := got.Rate(); rate != 0.5 {
+		t.Errorf("Rate = %v, want 0.5", rate)
+	}
+	if rate := (Churn{Joined: 3}).Rate(); rate != 0 {
+		t.Errorf("Rate with no members at the start = %v, want 0", rate)
+	}
+}
@@ -0,0 +1,212 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultStripeBase = "https://api.stripe.com"
+	// Stripe signs the timestamp too; older deliveries are replays
+	webhookTolerance = 5 * time.Minute
+)
+
+// Stripe takes payments with Stripe Checkout. BaseURL can point at a local
+// mock server for testing.
+type Stripe struct {
+	SecretKey     string
+	WebhookSecret string
+	BaseURL       string
+	Client        *http.Client
+	now           func() time.Time
+}
+
+func NewStripe(secretKey, webhookSecret, baseURL string) *Stripe {
+	if baseURL == "" {
+		baseURL = defaultStripeBase
+	}
+	return &Stripe{
+		SecretKey:     secretKey,
+		WebhookSecret: webhookSecret,
+		BaseURL:       strings.TrimRight(baseURL, "/"),
+		Client:        &http.Client{Timeout: 15 * time.Second},
+		now:           time.Now,
+	}
+}
+
+func (s *Stripe) Name() string { return "stripe" }
+
+func (s *Stripe) CreateCheckout(ctx context.Context, req CheckoutRequest) (Checkout, error) {
+	form := url.Values{
+		"mode":                                   {"payment"},
+		"success_url":                            {req.SuccessURL},
+		"cancel_url":                             {req.CancelURL},
+		"client_reference_id":                    {req.Reference},
+		"line_items[0][quantity]":                {"1"},
+		"line_items[0][price_data][currency]":    {req.Currency},
+		"line_items[0][price_data][unit_amount]": {strconv.FormatInt(req.Amount, 10)},
+		"line_items[0][price_data][product_data][name]": {req.Description},
+	}
+	if req.Email != "" {
+		form.Set("customer_email", req.Email)
+	}
+	if !req.ExpiresAt.IsZero() {
+		form.Set("expires_at", strconv.FormatInt(req.ExpiresAt.Unix(), 10))
+	}
+	for k, v := range req.Metadata {
+		form.Set("metadata["+k+"]", v)
+		form.Set("payment_intent_data[metadata]["+k+"]", v)
+	}
+
+	var session struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := s.post(ctx, "/v1/checkout/sessions", req.Reference, form, &session); err != nil {
+		return Checkout{}, err
+	}
+	return Checkout{ID: session.ID, URL: session.URL}, nil
+}
+
+func (s *Stripe) Refund(ctx context.Context, req RefundRequest) (Refund, error) {
+	form := url.Values{
+		"payment_intent": {req.PaymentRef},
+		"amount":         {strconv.FormatInt(req.Amount, 10)},
+	}
+	var refund struct {
+		ID     string `json:"id"`
+		Amount int64  `json:"amount"`
+	}
+	if err := s.post(ctx, "/v1/refunds", req.Reference, form, &refund); err != nil {
+		return Refund{}, err
+	}
+	return Refund{ID: refund.ID, Amount: refund.Amount}, nil
+}
+
+// post sends a form-encoded API request. The idempotency key makes a
+// retried request return the original result instead of charging twice.
+func (s *Stripe) post(ctx context.Context, path, idempotencyKey string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.SecretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("stripe: %s", apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe: %s returned %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("stripe: decoding %s response: %w", path, err)
+	}
+	return nil
+}
+
+// ParseWebhook checks the Stripe-Signature header, an HMAC-SHA256 of
+// "timestamp.payload" with the endpoint's signing secret, then decodes the
+// checkout session events we act on.
+func (s *Stripe) ParseWebhook(payload []byte, header http.Header) (Event, error) {
+	if s.WebhookSecret == "" {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header.Get("Stripe-Signature"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return Event{}, ErrInvalidSignature
+	}
+	if age := s.now().Sub(time.Unix(unix, 0)); age > webhookTolerance || age < -webhookTolerance {
+		return Event{}, ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.WebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	valid := false
+	for _, sig := range signatures {
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, expected) {
+			valid = true
+		}
+	}
+	if !valid {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID            string            `json:"id"`
+				PaymentIntent string            `json:"payment_intent"`
+				PaymentStatus string            `json:"payment_status"`
+				AmountTotal   int64             `json:"amount_total"`
+				Metadata      map[string]string `json:"metadata"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, fmt.Errorf("stripe: decoding webhook: %w", err)
+	}
+
+	session := event.Data.Object
+	out := Event{
+		ID:         event.ID,
+		CheckoutID: session.ID,
+		PaymentRef: session.PaymentIntent,
+		Amount:     session.AmountTotal,
+		Metadata:   session.Metadata,
+	}
+	switch event.Type {
+	case "checkout.session.completed":
+		// Bank debits complete later, with async_payment_succeeded
+		if session.PaymentStatus == "paid" {
+			out.Kind = EventPaid
+		}
+	case "checkout.session.async_payment_succeeded":
+		out.Kind = EventPaid
+	case "checkout.session.async_payment_failed":
+		out.Kind = EventFailed
+	case "checkout.session.expired":
+		out.Kind = EventExpired
+	}
+	return out, nil
+}
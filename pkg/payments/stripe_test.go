@@ -0,0 +1,297 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStripe is enough of the Stripe API for checkouts and refunds. Like
+// Stripe, it replays the first response to a repeated idempotency key.
+type fakeStripe struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	requests []*http.Request
+	forms    []map[string]string
+	replies  map[string][]byte // by idempotency key
+	refunded map[string]int64  // by payment intent
+}
+
+func newFakeStripe(t *testing.T) (*fakeStripe, *Stripe) {
+	f := &fakeStripe{t: t, replies: make(map[string][]byte), refunded: make(map[string]int64)}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, NewStripe("sk_test_123", "whsec_test", server.URL+"/")
+}
+
+func (f *fakeStripe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer sk_test_123" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error": {"message": "Invalid API Key provided"}}`)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		f.t.Errorf("parsing form: %v", err)
+	}
+	form := make(map[string]string)
+	for k, v := range r.PostForm {
+		form[k] = v[0]
+	}
+	f.requests = append(f.requests, r)
+	f.forms = append(f.forms, form)
+
+	key := r.Header.Get("Idempotency-Key")
+	if reply, ok := f.replies[key]; ok && key != "" {
+		w.Write(reply)
+		return
+	}
+
+	var reply any
+	switch r.URL.Path {
+	case "/v1/checkout/sessions":
+		id := fmt.Sprintf("cs_test_%d", len(f.requests))
+		reply = map[string]any{"id": id, "url": "https://checkout.stripe.com/c/pay/" + id}
+	case "/v1/refunds":
+		amount, _ := strconv.ParseInt(form["amount"], 10, 64)
+		intent := form["payment_intent"]
+		if f.refunded[intent]+amount > 10000 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": {"message": "Refund amount is greater than unrefunded amount on charge"}}`)
+			return
+		}
+		f.refunded[intent] += amount
+		reply = map[string]any{"id": fmt.Sprintf("re_test_%d", len(f.requests)), "amount": amount}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	data, _ := json.Marshal(reply)
+	f.replies[key] = data
+	w.Write(data)
+}
+
+func TestCreateCheckout(t *testing.T) {
+	f, stripe := newFakeStripe(t)
+	expires := time.Date(2026, 5, 1, 13, 0, 0, 0, time.UTC)
+
+	checkout, err := stripe.CreateCheckout(context.Background(), CheckoutRequest{
+		Reference:   "deposit-12-1777640400",
+		Description: "Booking deposit – Fri, May 1 9:00 AM",
+		Amount:      5000,
+		Currency:    "usd",
+		Email:       "dana@example.com",
+		SuccessURL:  "https://example.com/booking/deposit/tok?paid=1",
+		CancelURL:   "https://example.com/booking/deposit/tok",
+		ExpiresAt:   expires,
+		Metadata:    map[string]string{"booking_id": "7", "payment_id": "12"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkout.ID != "cs_test_1" || checkout.URL != "https://checkout.stripe.com/c/pay/cs_test_1" {
+		t.Errorf("checkout = %+v", checkout)
+	}
+
+	if got := f.requests[0].Header.Get("Idempotency-Key"); got != "deposit-12-1777640400" {
+		t.Errorf("Idempotency-Key = %q", got)
+	}
+	want := map[string]string{
+		"mode":                                   "payment",
+		"client_reference_id":                    "deposit-12-1777640400",
+		"customer_email":                         "dana@example.com",
+		"expires_at":                             strconv.FormatInt(expires.Unix(), 10),
+		"success_url":                            "https://example.com/booking/deposit/tok?paid=1",
+		"cancel_url":                             "https://example.com/booking/deposit/tok",
+		"line_items[0][quantity]":                "1",
+		"line_items[0][price_data][currency]":    "usd",
+		"line_items[0][price_data][unit_amount]": "5000",
+		"line_items[0][price_data][product_data][name]": "Booking deposit – Fri, May 1 9:00 AM",
+		"metadata[booking_id]":                          "7",
+		"payment_intent_data[metadata][payment_id]":     "12",
+	}
+	for k, v := range want {
+		if got := f.forms[0][k]; got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+
+	// A retry of the same checkout gets the same session back
+	again, err := stripe.CreateCheckout(context.Background(), CheckoutRequest{Reference: "deposit-12-1777640400", Amount: 5000, Currency: "usd"})
+	if err != nil || again.ID != checkout.ID {
+		t.Errorf("retried checkout = %+v, %v; want %s", again, err, checkout.ID)
+	}
+}
+
+func TestStripeAPIErrors(t *testing.T) {
+	_, stripe := newFakeStripe(t)
+	stripe.SecretKey = "sk_test_wrong"
+	_, err := stripe.CreateCheckout(context.Background(), CheckoutRequest{Reference: "deposit-1", Amount: 100, Currency: "usd"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid API Key provided") {
+		t.Errorf("err = %v, want Stripe's message", err)
+	}
+
+	stripe.BaseURL = "http://127.0.0.1:1"
+	if _, err := stripe.Refund(context.Background(), RefundRequest{Reference: "refund-1", PaymentRef: "pi_1", Amount: 100}); err == nil {
+		t.Error("Refund with Stripe unreachable: no error")
+	}
+}
+
+func TestRefund(t *testing.T) {
+	f, stripe := newFakeStripe(t)
+	ctx := context.Background()
+
+	first, err := stripe.Refund(ctx, RefundRequest{Reference: "refund-payment-12", PaymentRef: "pi_123", Amount: 2500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == "" || first.Amount != 2500 {
+		t.Errorf("refund = %+v", first)
+	}
+	if got := f.forms[0]["payment_intent"]; got != "pi_123" {
+		t.Errorf("payment_intent = %q", got)
+	}
+
+	// A retried refund isn't paid out twice
+	retry, err := stripe.Refund(ctx, RefundRequest{Reference: "refund-payment-12", PaymentRef: "pi_123", Amount: 2500})
+	if err != nil || retry.ID != first.ID {
+		t.Errorf("retried refund = %+v, %v; want %s", retry, err, first.ID)
+	}
+
+	// A second refund of the same amount is a new refund
+	second, err := stripe.Refund(ctx, RefundRequest{Reference: "refund-payment-13", PaymentRef: "pi_123", Amount: 2500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID == first.ID {
+		t.Errorf("second refund of the same amount was taken for a retry of %s", first.ID)
+	}
+	if f.refunded["pi_123"] != 5000 {
+		t.Errorf("refunded %d, want 5000", f.refunded["pi_123"])
+	}
+
+	if _, err := stripe.Refund(ctx, RefundRequest{Reference: "refund-payment-14", PaymentRef: "pi_123", Amount: 9000}); err == nil {
+		t.Error("refunding more than was paid: no error")
+	}
+}
+
+// signWebhook builds a Stripe-Signature header for payload.
+func signWebhook(secret string, at time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseWebhook(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := func(kind, paymentStatus string) []byte {
+		return []byte(fmt.Sprintf(`{
+			"id": "evt_1",
+			"type": %q,
+			"data": {"object": {
+				"id": "cs_test_1",
+				"payment_intent": "pi_123",
+				"payment_status": %q,
+				"amount_total": 5000,
+				"metadata": {"booking_id": "7", "payment_id": "12"}
+			}}
+		}`, kind, paymentStatus))
+	}
+	completed := event("checkout.session.completed", "paid")
+
+	tests := []struct {
+		name     string
+		secret   string
+		payload  []byte
+		header   string
+		wantKind string
+		wantErr  error
+	}{
+		{name: "paid", payload: completed, header: signWebhook("whsec_test", now, completed), wantKind: EventPaid},
+		{name: "paid later by bank debit", payload: event("checkout.session.completed", "unpaid"), wantKind: ""},
+		{name: "bank debit cleared", payload: event("checkout.session.async_payment_succeeded", "paid"), wantKind: EventPaid},
+		{name: "bank debit failed", payload: event("checkout.session.async_payment_failed", "unpaid"), wantKind: EventFailed},
+		{name: "abandoned", payload: event("checkout.session.expired", "unpaid"), wantKind: EventExpired},
+		{name: "other events ignored", payload: event("charge.refunded", ""), wantKind: ""},
+		{name: "signed just inside the tolerance", payload: completed, header: signWebhook("whsec_test", now.Add(-webhookTolerance), completed), wantKind: EventPaid},
+		{
+			name:     "one of several signatures valid",
+			payload:  completed,
+			header:   signWebhook("whsec_old", now, completed) + "," + strings.Split(signWebhook("whsec_test", now, completed), ",")[1],
+			wantKind: EventPaid,
+		},
+		{name: "wrong secret", payload: completed, header: signWebhook("whsec_other", now, completed), wantErr: ErrInvalidSignature},
+		{name: "tampered payload", payload: []byte(strings.Replace(string(completed), "5000", "50", 1)), header: signWebhook("whsec_test", now, completed), wantErr: ErrInvalidSignature},
+		{name: "stale", payload: completed, header: signWebhook("whsec_test", now.Add(-webhookTolerance-time.Second), completed), wantErr: ErrInvalidSignature},
+		{name: "from the future", payload: completed, header: signWebhook("whsec_test", now.Add(webhookTolerance+time.Second), completed), wantErr: ErrInvalidSignature},
+		{name: "no header", payload: completed, header: "-", wantErr: ErrInvalidSignature},
+		{name: "no timestamp", payload: completed, header: "v1=" + strings.Split(signWebhook("whsec_test", now, completed), "v1=")[1], wantErr: ErrInvalidSignature},
+		{name: "no signature", payload: completed, header: "t=" + strconv.FormatInt(now.Unix(), 10), wantErr: ErrInvalidSignature},
+		{name: "no webhook secret set", secret: "-", payload: completed, wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := "whsec_test"
+			if tt.secret == "-" {
+				secret = ""
+			}
+			stripe := NewStripe("sk_test_123", secret, "")
+			stripe.now = func() time.Time { return now }
+
+			header := http.Header{}
+			switch tt.header {
+			case "":
+				header.Set("Stripe-Signature", signWebhook("whsec_test", now, tt.payload))
+			case "-":
+			default:
+				header.Set("Stripe-Signature", tt.header)
+			}
+
+			got, err := stripe.ParseWebhook(tt.payload, header)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", got.Kind, tt.wantKind)
+			}
+			if got.ID != "evt_1" || got.CheckoutID != "cs_test_1" || got.PaymentRef != "pi_123" || got.Amount != 5000 || got.Metadata["payment_id"] != "12" {
+				t.Errorf("event = %+v", got)
+			}
+		})
+	}
+}
+
+func TestManual(t *testing.T) {
+	var m Manual
+	if checkout, err := m.CreateCheckout(context.Background(), CheckoutRequest{Amount: 5000}); err != nil || checkout.URL != "" {
+		t.Errorf("CreateCheckout = %+v, %v; want no hosted checkout", checkout, err)
+	}
+	if _, err := m.ParseWebhook([]byte("{}"), http.Header{}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ParseWebhook: err = %v, want ErrUnsupported", err)
+	}
+	if refund, err := m.Refund(context.Background(), RefundRequest{Amount: 2500}); err != nil || refund.Amount != 2500 || refund.ID != "" {
+		t.Errorf("Refund = %+v, %v", refund, err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package promo decides whether a promo code can be used and what it takes
+// off. It knows nothing about storage; callers load the code and how often
+// it has been used, and Check applies the rules.
+package promo
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/invoice"
+)
+
+// Reasons a code can't be used. Callers word them for customers.
+var (
+	ErrUnknown       = errors.New("promo: unknown code")
+	ErrNotStarted    = errors.New("promo: code not active yet")
+	ErrExpired       = errors.New("promo: code expired")
+	ErrPackage       = errors.New("promo: code doesn't apply to these services")
+	ErrMinSpend      = errors.New("promo: order below the minimum spend")
+	ErrUsedUp        = errors.New("promo: code used up")
+	ErrCustomerLimit = errors.New("promo: customer already used code")
+)
+
+// Code is a promo code and its rules. Zero limits and times mean no limit.
+type Code struct {
+	Code           string
+	Kind           string // invoice.DiscountAmount or invoice.DiscountPercent
+	Value          int64  // cents, or basis points for percent
+	PackageIDs     []int64
+	MinSpend       int64 // cents, before the discount
+	StartsAt       time.Time
+	EndsAt         time.Time
+	MaxUses        int64
+	MaxPerCustomer int64
+	Active         bool
+}
+
+// Usage is how many times a code has been redeemed, in total and by the
+// customer trying to use it. Cancelled bookings don't count.
+type Usage struct {
+	Total    int64
+	Customer int64
+}
+
+// Order is what a code is being used on.
+type Order struct {
+	PackageIDs []int64
+	Subtotal   int64 // cents, before the discount
+}
+
+// Normalize is the stored form of a code: trimmed and upper case, so
+// customers can type it however they like.
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Check reports why the code can't be used at time at on order, or nil
+// when it can. A code limited to packages needs at least one of them in the
+// order; it then discounts the whole order.
+func (c Code) Check(at time.Time, order Order, usage Usage) error {
+	switch {
+	case !c.Active:
+		return ErrUnknown
+	case !c.StartsAt.IsZero() && at.Before(c.StartsAt):
+		return ErrNotStarted
+	case !c.EndsAt.IsZero() && !at.Before(c.EndsAt):
+		return ErrExpired
+	case !c.appliesTo(order.PackageIDs):
+		return ErrPackage
+	case order.Subtotal < c.MinSpend:
+		return ErrMinSpend
+	case c.MaxUses > 0 && usage.Total >= c.MaxUses:
+		return ErrUsedUp
+	case c.MaxPerCustomer > 0 && usage.Customer >= c.MaxPerCustomer:
+		return ErrCustomerLimit
+	}
+	return nil
+}
+
+func (c Code) appliesTo(packageIDs []int64) bool {
+	if len(c.PackageIDs) == 0 {
+		return true
+	}
+	for _, want := range c.PackageIDs {
+		for _, got := range packageIDs {
+			if want == got {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c Code) Discount() invoice.Discount {
+	return invoice.Discount{Kind: c.Kind, Value: c.Value}
+}
+
+// Label describes the discount, e.g. "15% off" or "$20.00 off".
+func (c Code) Label() string {
+	return Label(c.Kind, c.Value)
+}
+
+// Label describes a discount stored away from its code, such as on a
+// booking.
+func Label(kind string, value int64) string {
+	if kind == invoice.DiscountPercent {
+		return invoice.FormatRate(value) + " off"
+	}
+	return invoice.FormatMoney(value) + " off"
+}
@@ -0,0 +1,132 @@
+package promo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"detailingpass/pkg/invoice"
+)
+
+func TestCheck(t *testing.T) {
+	starts := time.Date(2026, 4, 1, 4, 0, 0, 0, time.UTC)
+	ends := time.Date(2026, 5, 1, 4, 0, 0, 0, time.UTC)
+	spring := Code{
+		Code:     "SPRING15",
+		Kind:     invoice.DiscountPercent,
+		Value:    1500,
+		StartsAt: starts,
+		EndsAt:   ends,
+		Active:   true,
+	}
+	during := starts.Add(48 * time.Hour)
+	order := Order{PackageIDs: []int64{3}, Subtotal: 25000}
+
+	tests := []struct {
+		name  string
+		code  func(Code) Code
+		at    time.Time
+		order Order
+		usage Usage
+		want  error
+	}{
+		{name: "within the window", at: during},
+		{name: "inactive", code: func(c Code) Code { c.Active = false; return c }, at: during, want: ErrUnknown},
+		{name: "before it starts", at: starts.Add(-time.Second), want: ErrNotStarted},
+		{name: "the moment it starts", at: starts},
+		{name: "last moment", at: ends.Add(-time.Second)},
+		{name: "the moment it ends", at: ends, want: ErrExpired},
+		{name: "long expired", at: ends.AddDate(1, 0, 0), want: ErrExpired},
+		{name: "no window", code: func(c Code) Code { c.StartsAt, c.EndsAt = time.Time{}, time.Time{}; return c }, at: ends.AddDate(5, 0, 0)},
+		{name: "expiry beats the usage limit", code: func(c Code) Code { c.MaxUses = 1; return c }, at: ends, usage: Usage{Total: 1}, want: ErrExpired},
+
+		{name: "for the booked package", code: func(c Code) Code { c.PackageIDs = []int64{3}; return c }, at: during},
+		{name: "one of several packages", code: func(c Code) Code { c.PackageIDs = []int64{7, 3}; return c }, at: during, order: Order{PackageIDs: []int64{1, 3}, Subtotal: 25000}},
+		{name: "for another package", code: func(c Code) Code { c.PackageIDs = []int64{7}; return c }, at: during, want: ErrPackage},
+		{name: "package code on a custom-only order", code: func(c Code) Code { c.PackageIDs = []int64{3}; return c }, at: during, order: Order{Subtotal: 25000}, want: ErrPackage},
+
+		{name: "over the minimum spend", code: func(c Code) Code { c.MinSpend = 20000; return c }, at: during},
+		{name: "exactly the minimum spend", code: func(c Code) Code { c.MinSpend = 25000; return c }, at: during},
+		{name: "a cent under the minimum spend", code: func(c Code) Code { c.MinSpend = 25001; return c }, at: during, want: ErrMinSpend},
+		{name: "minimum spend on an empty order", code: func(c Code) Code { c.MinSpend = 1; return c }, at: during, order: Order{PackageIDs: []int64{3}}, want: ErrMinSpend},
+
+		{name: "unlimited uses", at: during, usage: Usage{Total: 500, Customer: 20}},
+		{name: "under the usage limit", code: func(c Code) Code { c.MaxUses = 10; return c }, at: during, usage: Usage{Total: 9}},
+		{name: "at the usage limit", code: func(c Code) Code { c.MaxUses = 10; return c }, at: during, usage: Usage{Total: 10}, want: ErrUsedUp},
+		{name: "first use by this customer", code: func(c Code) Code { c.MaxPerCustomer = 1; return c }, at: during, usage: Usage{Total: 40}},
+		{name: "customer already used it", code: func(c Code) Code { c.MaxPerCustomer = 1; return c }, at: during, usage: Usage{Total: 40, Customer: 1}, want: ErrCustomerLimit},
+		{name: "used up beats the customer limit", code: func(c Code) Code { c.MaxUses, c.MaxPerCustomer = 2, 1; return c }, at: during, usage: Usage{Total: 2, Customer: 1}, want: ErrUsedUp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := spring
+			if tt.code != nil {
+				code = tt.code(code)
+			}
+			o := order
+			if tt.order.PackageIDs != nil || tt.order.Subtotal != 0 {
+				o = tt.order
+			}
+			if err := code.Check(tt.at, o, tt.usage); !errors.Is(err, tt.want) {
+				t.Errorf("Check = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     Code
+		subtotal int64
+		want     int64
+	}{
+		{name: "percent", code: Code{Kind: invoice.DiscountPercent, Value: 1500}, subtotal: 20000, want: 3000},
+		{name: "percent rounds half up", code: Code{Kind: invoice.DiscountPercent, Value: 1500}, subtotal: 9999, want: 1500},
+		{name: "percent rounds down", code: Code{Kind: invoice.DiscountPercent, Value: 1500}, subtotal: 3, want: 0},
+		{name: "fractional percent", code: Code{Kind: invoice.DiscountPercent, Value: 1250}, subtotal: 4, want: 1},
+		{name: "all of it", code: Code{Kind: invoice.DiscountPercent, Value: 10000}, subtotal: 18999, want: 18999},
+		{name: "fixed", code: Code{Kind: invoice.DiscountAmount, Value: 2000}, subtotal: 15000, want: 2000},
+		{name: "fixed doesn't depend on the order", code: Code{Kind: invoice.DiscountAmount, Value: 2000}, subtotal: 99999, want: 2000},
+		{name: "fixed over the order", code: Code{Kind: invoice.DiscountAmount, Value: 2000}, subtotal: 1500, want: 1500},
+	}
+	for _, tt := range tests {
+		lines := []invoice.Line{{Quantity: 1, UnitPrice: tt.subtotal}}
+		got := invoice.Compute(lines, tt.code.Discount(), 0)
+		if got.Discount != tt.want || got.Total != tt.subtotal-tt.want {
+			t.Errorf("%s: discount %d, total %d; want %d off %d", tt.name, got.Discount, got.Total, tt.want, tt.subtotal)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for raw, want := range map[string]string{
+		"SPRING15":     "SPRING15",
+		"spring15":     "SPRING15",
+		"  Spring15\n": "SPRING15",
+		"fall-20_off":  "FALL-20_OFF",
+		"":             "",
+	} {
+		if got := Normalize(raw); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		kind  string
+		value int64
+		want  string
+	}{
+		{invoice.DiscountPercent, 1500, "15% off"},
+		{invoice.DiscountPercent, 1250, "12.5% off"},
+		{invoice.DiscountAmount, 2000, "$20.00 off"},
+		{invoice.DiscountAmount, 123456, "$1,234.56 off"},
+	}
+	for _, tt := range tests {
+		if got := Label(tt.kind, tt.value); got != tt.want {
+			t.Errorf("Label(%q, %d) = %q, want %q", tt.kind, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+// Package recurrence expands the subset of iCalendar RRULEs used for
+// repeat bookings: weekly on one or more days, or monthly on the start's
+// day of the month, every N weeks or months, ending after a number of
+// times or on a date.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Freq string
+
+const (
+	Weekly  Freq = "WEEKLY"
+	Monthly Freq = "MONTHLY"
+)
+
+// Limits that keep a typo from booking out the next decade
+const (
+	MaxInterval = 12
+	MaxCount    = 104
+)
+
+// Reasons a rule is rejected. Callers word them for admins.
+var (
+	ErrFreq     = errors.New("recurrence: frequency must be weekly or monthly")
+	ErrInterval = fmt.Errorf("recurrence: interval must be 1 to %d", MaxInterval)
+	ErrCount    = fmt.Errorf("recurrence: count must be 1 to %d", MaxCount)
+	ErrByDay    = errors.New("recurrence: BYDAY is for weekly rules only")
+	ErrSyntax   = errors.New("recurrence: invalid rule")
+)
+
+// Rule is a parsed RRULE. Occurrences keep the wall-clock time of the
+// series start, so a 9am series stays at 9am across daylight saving.
+type Rule struct {
+	Freq     Freq
+	Interval int            // 1 when unset
+	ByDay    []time.Weekday // weekly only; empty for the start's weekday
+	Count    int            // 0 for no limit
+	Until    time.Time      // inclusive; zero for no end date
+}
+
+var dayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// ParseDay reads a BYDAY code such as "TU".
+func ParseDay(code string) (time.Weekday, bool) {
+	day, ok := dayCodes[strings.ToUpper(code)]
+	return day, ok
+}
+
+// DayCode is the BYDAY code for day.
+func DayCode(day time.Weekday) string {
+	return strings.ToUpper(day.String()[:2])
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU". A leading
+// "RRULE:" is allowed. UNTIL is read as UTC.
+func Parse(s string) (Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(strings.ToUpper(s)), "RRULE:")
+	r := Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, ErrSyntax
+		}
+		switch key {
+		case "FREQ":
+			r.Freq = Freq(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return Rule{}, ErrSyntax
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return Rule{}, ErrSyntax
+			}
+			r.Count = n
+		case "UNTIL":
+			t, err := time.Parse("20060102T150405Z", value)
+			if err != nil {
+				return Rule{}, ErrSyntax
+			}
+			r.Until = t
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := ParseDay(code)
+				if !ok {
+					return Rule{}, ErrSyntax
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "WKST":
+			if value != "MO" {
+				return Rule{}, ErrSyntax
+			}
+		default:
+			return Rule{}, ErrSyntax
+		}
+	}
+	return r, r.Validate()
+}
+
+// Validate reports why the rule can't be used, or nil.
+func (r Rule) Validate() error {
+	switch {
+	case r.Freq != Weekly && r.Freq != Monthly:
+		return ErrFreq
+	case r.Interval < 1 || r.Interval > MaxInterval:
+		return ErrInterval
+	case r.Count < 0 || r.Count > MaxCount:
+		return ErrCount
+	case r.Freq != Weekly && len(r.ByDay) > 0:
+		return ErrByDay
+	}
+	return nil
+}
+
+// String formats the rule as an RRULE, without the "RRULE:" prefix.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		var codes []string
+		for _, day := range sortedDays(r.ByDay) {
+			codes = append(codes, DayCode(day))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of a series starting at start that fall
+// in [from, to). The start is the first occurrence only if it matches the
+// rule. COUNT is counted from the start, so occurrences before from still
+// use it up.
+func (r Rule) Between(start, from, to time.Time) []time.Time {
+	var out []time.Time
+	r.each(start, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			out = append(out, t)
+		}
+		return true
+	})
+	return out
+}
+
+// CountBefore is how many occurrences fall before t.
+func (r Rule) CountBefore(start, t time.Time) int {
+	n := 0
+	r.each(start, func(at time.Time) bool {
+		if !at.Before(t) {
+			return false
+		}
+		n++
+		return true
+	})
+	return n
+}
+
+// First is the first occurrence, if the rule has any.
+func (r Rule) First(start time.Time) (time.Time, bool) {
+	var first time.Time
+	r.each(start, func(t time.Time) bool {
+		first = t
+		return false
+	})
+	return first, !first.IsZero()
+}
+
+// each calls fn with each occurrence in order until it returns false or
+// the rule ends.
+func (r Rule) each(start time.Time, fn func(time.Time) bool) {
+	if r.Validate() != nil {
+		return
+	}
+	n := 0
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		n++
+		if !fn(t) {
+			return false
+		}
+		return r.Count == 0 || n < r.Count
+	}
+
+	y, m, d := start.Date()
+	hh, mm, ss := start.Clock()
+	loc := start.Location()
+	// Enough periods for the longest series, with room for skipped months
+	const maxPeriods = MaxCount * 4
+
+	switch r.Freq {
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		days = sortedDays(days)
+		// Weeks start on Monday
+		monday := d - (int(start.Weekday())+6)%7
+		for week := 0; week < maxPeriods; week++ {
+			for _, day := range days {
+				offset := (int(day) + 6) % 7
+				t := time.Date(y, m, monday+week*7*r.Interval+offset, hh, mm, ss, 0, loc)
+				if !emit(t) {
+					return
+				}
+			}
+		}
+	case Monthly:
+		for k := 0; k < maxPeriods; k++ {
+			t := time.Date(y, m+time.Month(k*r.Interval), d, hh, mm, ss, 0, loc)
+			// Months without the day (the 31st in April) are skipped
+			if t.Day() != d {
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// Describe says how the series repeats, such as "Every other Tuesday" or
+// "Monthly on the 15th, 6 times".
+func (r Rule) Describe(start time.Time) string {
+	var label string
+	switch r.Freq {
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		var names []string
+		for _, day := range sortedDays(days) {
+			names = append(names, day.String())
+		}
+		dayList := joinList(names)
+		switch {
+		case r.Interval == 1 && len(days) == 1:
+			label = "Every " + dayList
+		case r.Interval == 1:
+			label = "Every week on " + dayList
+		case r.Interval == 2 && len(days) == 1:
+			label = "Every other " + dayList
+		case r.Interval == 2:
+			label = "Every other week on " + dayList
+		default:
+			label = fmt.Sprintf("Every %d weeks on %s", r.Interval, dayList)
+		}
+	case Monthly:
+		day := ordinal(start.Day())
+		switch r.Interval {
+		case 1:
+			label = "Monthly on the " + day
+		case 2:
+			label = "Every other month on the " + day
+		default:
+			label = fmt.Sprintf("Every %d months on the %s", r.Interval, day)
+		}
+	default:
+		return "Doesn't repeat"
+	}
+	if r.Count > 0 {
+		label += fmt.Sprintf(", %d times", r.Count)
+	}
+	if !r.Until.IsZero() {
+		label += ", until " + r.Until.In(start.Location()).Format("Jan 2, 2006")
+	}
+	return label
+}
+
+func sortedDays(days []time.Weekday) []time.Weekday {
+	seen := make(map[time.Weekday]bool)
+	var out []time.Weekday
+	for _, day := range days {
+		if !seen[day] {
+			seen[day] = true
+			out = append(out, day)
+		}
+	}
+	// Monday first
+	sort.Slice(out, func(i, j int) bool { return (out[i]+6)%7 < (out[j]+6)%7 })
+	return out
+}
+
+func joinList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,322 @@
+package recurrence
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var shop = mustLoad("America/New_York")
+
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// at is a wall-clock time at the shop.
+func at(month time.Month, day, hour int) time.Time {
+	return time.Date(2026, month, day, hour, 0, 0, 0, shop)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    Rule
+		wantErr error
+	}{
+		{rule: "FREQ=WEEKLY", want: Rule{Freq: Weekly, Interval: 1}},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", want: Rule{Freq: Weekly, Interval: 2, ByDay: []time.Weekday{time.Tuesday}}},
+		{rule: "RRULE:FREQ=WEEKLY;BYDAY=TH,MO;WKST=MO", want: Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Thursday, time.Monday}}},
+		{rule: " rrule:freq=monthly;count=6 ", want: Rule{Freq: Monthly, Interval: 1, Count: 6}},
+		{rule: "FREQ=MONTHLY;INTERVAL=12;UNTIL=20270101T045959Z", want: Rule{Freq: Monthly, Interval: 12, Until: time.Date(2027, 1, 1, 4, 59, 59, 0, time.UTC)}},
+		{rule: "FREQ=WEEKLY;INTERVAL=12;COUNT=104", want: Rule{Freq: Weekly, Interval: 12, Count: 104}},
+
+		{rule: "", wantErr: ErrSyntax},
+		{rule: "FREQ=WEEKLY;", wantErr: ErrSyntax},
+		{rule: "FREQ=WEEKLY;BYMONTH=1", wantErr: ErrSyntax},
+		{rule: "FREQ=WEEKLY;INTERVAL=two", wantErr: ErrSyntax},
+		{rule: "FREQ=WEEKLY;COUNT=many", wantErr: ErrSyntax},
+		{rule: "FREQ=WEEKLY;BYDAY=TU,XX", wantErr: ErrSyntax},
+		{rule: "FREQ=WEEKLY;BYDAY=2TU", wantErr: ErrSyntax},
+		{rule: "FREQ=WEEKLY;WKST=SU", wantErr: ErrSyntax},
+		{rule: "FREQ=WEEKLY;UNTIL=2026-12-31", wantErr: ErrSyntax},
+		{rule: "FREQ=DAILY", wantErr: ErrFreq},
+		{rule: "INTERVAL=2", wantErr: ErrFreq},
+		{rule: "FREQ=WEEKLY;INTERVAL=0", wantErr: ErrInterval},
+		{rule: "FREQ=WEEKLY;INTERVAL=13", wantErr: ErrInterval},
+		{rule: "FREQ=WEEKLY;COUNT=105", wantErr: ErrCount},
+		{rule: "FREQ=WEEKLY;COUNT=-1", wantErr: ErrCount},
+		{rule: "FREQ=MONTHLY;BYDAY=TU", wantErr: ErrByDay},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.rule)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Parse(%q): err = %v, want %v", tt.rule, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for rule, want := range map[string]string{
+		"FREQ=WEEKLY;INTERVAL=1":                       "FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=FR,MO,WE,MO;WKST=MO":        "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		"FREQ=WEEKLY;BYDAY=SU,SA":                      "FREQ=WEEKLY;BYDAY=SA,SU",
+		"RRULE:FREQ=MONTHLY;COUNT=6;INTERVAL=3":        "FREQ=MONTHLY;INTERVAL=3;COUNT=6",
+		"FREQ=MONTHLY;UNTIL=20270101T045959Z;COUNT=10": "FREQ=MONTHLY;COUNT=10;UNTIL=20270101T045959Z",
+	} {
+		r, err := Parse(rule)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", rule, err)
+		}
+		if got := r.String(); got != want {
+			t.Errorf("Parse(%q).String() = %q, want %q", rule, got, want)
+		}
+		again, err := Parse(r.String())
+		if err != nil || again.String() != want {
+			t.Errorf("%q doesn't survive a round trip: %q, %v", want, again.String(), err)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	rule := func(s string) Rule {
+		r, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", s, err)
+		}
+		return r
+	}
+	far := at(12, 31, 0).AddDate(1, 0, 0)
+
+	tests := []struct {
+		name     string
+		rule     Rule
+		start    time.Time
+		from, to time.Time
+		want     []time.Time
+	}{
+		{
+			name:  "every other Tuesday",
+			rule:  rule("FREQ=WEEKLY;INTERVAL=2;COUNT=4"),
+			start: at(3, 3, 9), from: at(3, 3, 9), to: far,
+			want: []time.Time{at(3, 3, 9), at(3, 17, 9), at(3, 31, 9), at(4, 14, 9)},
+		},
+		{
+			name:  "several days a week, from a start on neither",
+			rule:  rule("FREQ=WEEKLY;BYDAY=TH,MO;COUNT=4"),
+			start: at(3, 4, 13), from: at(3, 1, 0), to: far,
+			want: []time.Time{at(3, 5, 13), at(3, 9, 13), at(3, 12, 13), at(3, 16, 13)},
+		},
+		{
+			name:  "every other week over a weekend",
+			rule:  rule("FREQ=WEEKLY;INTERVAL=2;BYDAY=SA,SU;COUNT=4"),
+			start: at(3, 7, 10), from: at(3, 1, 0), to: far,
+			want: []time.Time{at(3, 7, 10), at(3, 8, 10), at(3, 21, 10), at(3, 22, 10)},
+		},
+		{
+			name:  "monthly skips months without the day",
+			rule:  rule("FREQ=MONTHLY;COUNT=5"),
+			start: at(1, 31, 9), from: at(1, 1, 0), to: far,
+			want: []time.Time{at(1, 31, 9), at(3, 31, 9), at(5, 31, 9), at(7, 31, 9), at(8, 31, 9)},
+		},
+		{
+			name:  "every other month on the 31st",
+			rule:  rule("FREQ=MONTHLY;INTERVAL=2;COUNT=5"),
+			start: at(1, 31, 9), from: at(1, 1, 0), to: far,
+			want: []time.Time{at(1, 31, 9), at(3, 31, 9), at(5, 31, 9), at(7, 31, 9), time.Date(2027, 1, 31, 9, 0, 0, 0, shop)},
+		},
+		{
+			name:  "window in the middle",
+			rule:  rule("FREQ=WEEKLY"),
+			start: at(3, 3, 9), from: at(3, 10, 9), to: at(3, 24, 9),
+			want: []time.Time{at(3, 10, 9), at(3, 17, 9)},
+		},
+		{
+			name:  "count is used up before the window",
+			rule:  rule("FREQ=WEEKLY;COUNT=3"),
+			start: at(3, 3, 9), from: at(3, 10, 0), to: far,
+			want: []time.Time{at(3, 10, 9), at(3, 17, 9)},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  Rule{Freq: Weekly, Interval: 1, Until: at(3, 17, 9).UTC()},
+			start: at(3, 3, 9), from: at(3, 1, 0), to: far,
+			want: []time.Time{at(3, 3, 9), at(3, 10, 9), at(3, 17, 9)},
+		},
+		{
+			name:  "until just before an occurrence",
+			rule:  Rule{Freq: Weekly, Interval: 1, Until: at(3, 17, 9).Add(-time.Second)},
+			start: at(3, 3, 9), from: at(3, 1, 0), to: far,
+			want: []time.Time{at(3, 3, 9), at(3, 10, 9)},
+		},
+		{
+			name:  "count and until, until first",
+			rule:  Rule{Freq: Weekly, Interval: 1, Count: 10, Until: at(3, 10, 23)},
+			start: at(3, 3, 9), from: at(3, 1, 0), to: far,
+			want: []time.Time{at(3, 3, 9), at(3, 10, 9)},
+		},
+		{
+			name:  "ends before it starts",
+			rule:  Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Monday}, Until: at(3, 8, 23)},
+			start: at(3, 4, 9), from: at(3, 1, 0), to: far,
+		},
+		{
+			name:  "invalid rule",
+			rule:  Rule{Freq: "DAILY", Interval: 1},
+			start: at(3, 3, 9), from: at(3, 1, 0), to: far,
+		},
+
+		// The wall-clock time holds across clock changes
+		{
+			name:  "into daylight time",
+			rule:  rule("FREQ=WEEKLY;COUNT=3"),
+			start: at(3, 1, 9), from: at(3, 1, 0), to: far,
+			want: []time.Time{at(3, 1, 9), at(3, 8, 9), at(3, 15, 9)},
+		},
+		{
+			name:  "out of daylight time",
+			rule:  rule("FREQ=WEEKLY;COUNT=2"),
+			start: at(10, 25, 9), from: at(10, 1, 0), to: far,
+			want: []time.Time{at(10, 25, 9), at(11, 1, 9)},
+		},
+		{
+			name:  "monthly across both",
+			rule:  rule("FREQ=MONTHLY;INTERVAL=4;COUNT=3"),
+			start: at(2, 15, 8), from: at(1, 1, 0), to: far,
+			want: []time.Time{at(2, 15, 8), at(6, 15, 8), at(10, 15, 8)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.Between(tt.start, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Between = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %s, want %s", i, got[i], tt.want[i])
+				}
+				if got[i].Location() != shop {
+					t.Errorf("occurrence %d is in %s, want the start's time zone", i, got[i].Location())
+				}
+			}
+		})
+	}
+}
+
+func TestDaylightSaving(t *testing.T) {
+	r := Rule{Freq: Weekly, Interval: 1}
+	got := r.Between(at(3, 1, 9), at(3, 1, 0), at(3, 9, 0))
+	if len(got) != 2 {
+		t.Fatalf("Between = %v", got)
+	}
+	// A week apart on the wall clock is an hour short of a week in March
+	if elapsed := got[1].Sub(got[0]); elapsed != 7*24*time.Hour-time.Hour {
+		t.Errorf("occurrences %s apart, want 167h", elapsed)
+	}
+	if got[0].UTC().Hour() != 14 || got[1].UTC().Hour() != 13 {
+		t.Errorf("occurrences at %s and %s UTC, want 14:00 and 13:00", got[0].UTC(), got[1].UTC())
+	}
+}
+
+func TestEachBounds(t *testing.T) {
+	start := at(3, 3, 9)
+	never := start.AddDate(1000, 0, 0)
+	const maxPeriods = MaxCount * 4
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		start time.Time
+		want  int
+	}{
+		// A series with no end stops after maxPeriods weeks or months
+		{name: "weekly with no end", rule: Rule{Freq: Weekly, Interval: 1}, start: start, want: maxPeriods},
+		{name: "every 12 weeks with no end", rule: Rule{Freq: Weekly, Interval: MaxInterval}, start: start, want: maxPeriods},
+		// Less the Monday before the Tuesday start
+		{name: "three days a week with no end", rule: Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Monday, time.Tuesday, time.Friday}}, start: start, want: 3*maxPeriods - 1},
+		{name: "monthly with no end", rule: Rule{Freq: Monthly, Interval: 1}, start: start, want: maxPeriods},
+		// The longest series allowed fits well inside the bound
+		{name: "longest weekly series", rule: Rule{Freq: Weekly, Interval: MaxInterval, Count: MaxCount}, start: start, want: MaxCount},
+		{name: "longest series on the 31st", rule: Rule{Freq: Monthly, Interval: 1, Count: MaxCount}, start: at(1, 31, 9), want: MaxCount},
+	}
+	for _, tt := range tests {
+		if got := len(tt.rule.Between(tt.start, tt.start, never)); got != tt.want {
+			t.Errorf("%s: %d occurrences, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// Feb 29 only comes round in leap years, so a yearly series from it
+	// runs out of periods before its count: 416 years hold 101 of them
+	leap := time.Date(2028, 2, 29, 9, 0, 0, 0, shop)
+	got := Rule{Freq: Monthly, Interval: 12, Count: MaxCount}.Between(leap, leap, leap.AddDate(1000, 0, 0))
+	if len(got) != 101 {
+		t.Errorf("yearly from Feb 29: %d occurrences, want 101", len(got))
+	}
+	for _, occurrence := range got {
+		if occurrence.Month() != time.February || occurrence.Day() != 29 {
+			t.Fatalf("yearly from Feb 29 landed on %s", occurrence)
+		}
+	}
+}
+
+func TestCountBeforeAndFirst(t *testing.T) {
+	weekly := Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Tuesday}}
+	start := at(3, 2, 9) // a Monday
+
+	if first, ok := weekly.First(start); !ok || !first.Equal(at(3, 3, 9)) {
+		t.Errorf("First = %s, %v; want the Tuesday after the start", first, ok)
+	}
+	for _, tt := range []struct {
+		before time.Time
+		want   int
+	}{
+		{at(3, 3, 9), 0},
+		{at(3, 3, 10), 1},
+		{at(3, 17, 9), 2},
+		{at(3, 17, 9).Add(time.Second), 3},
+	} {
+		if got := weekly.CountBefore(start, tt.before); got != tt.want {
+			t.Errorf("CountBefore(%s) = %d, want %d", tt.before, got, tt.want)
+		}
+	}
+
+	ended := weekly
+	ended.Until = at(3, 2, 23)
+	if first, ok := ended.First(start); ok {
+		t.Errorf("First = %s for a rule that ends before its first occurrence", first)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		rule  Rule
+		start time.Time
+		want  string
+	}{
+		{Rule{Freq: Weekly, Interval: 1}, at(3, 3, 9), "Every Tuesday"},
+		{Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Thursday, time.Monday}}, at(3, 3, 9), "Every week on Monday and Thursday"},
+		{Rule{Freq: Weekly, Interval: 2}, at(3, 3, 9), "Every other Tuesday"},
+		{Rule{Freq: Weekly, Interval: 2, ByDay: []time.Weekday{time.Friday, time.Monday, time.Wednesday}}, at(3, 3, 9), "Every other week on Monday, Wednesday and Friday"},
+		{Rule{Freq: Weekly, Interval: 3, ByDay: []time.Weekday{time.Sunday}}, at(3, 3, 9), "Every 3 weeks on Sunday"},
+		{Rule{Freq: Monthly, Interval: 1}, at(1, 31, 9), "Monthly on the 31st"},
+		{Rule{Freq: Monthly, Interval: 2}, at(1, 2, 9), "Every other month on the 2nd"},
+		{Rule{Freq: Monthly, Interval: 3}, at(1, 13, 9), "Every 3 months on the 13th"},
+		{Rule{Freq: Monthly, Interval: 1, Count: 6}, at(1, 23, 9), "Monthly on the 23rd, 6 times"},
+		{Rule{Freq: Weekly, Interval: 1, Until: at(12, 31, 23).UTC()}, at(3, 3, 9), "Every Tuesday, until Dec 31, 2026"},
+		{Rule{Freq: "DAILY", Interval: 1}, at(3, 3, 9), "Doesn't repeat"},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Describe(tt.start); got != tt.want {
+			t.Errorf("Describe(%s) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package reviewimport
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// facebookReview is a page rating as the Graph API's /{page}/ratings edge
+// returns it.
+type facebookReview struct {
+	CreatedTime string `json:"created_time"`
+	Reviewer    struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"reviewer"`
+	Rating             int64  `json:"rating"`
+	RecommendationType string `json:"recommendation_type"` // positive|negative
+	ReviewText         string `json:"review_text"`
+	OpenGraphStory     struct {
+		ID string `json:"id"`
+	} `json:"open_graph_story"`
+}
+
+func parseFacebook(data []byte) (Result, error) {
+	list, err := unwrapList(data, "data")
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, raw := range list {
+		var f facebookReview
+		if err := json.Unmarshal(raw, &f); err != nil {
+			result.Skipped++
+			continue
+		}
+
+		created, ok := parseTime(f.CreatedTime)
+		author := strings.TrimSpace(f.Reviewer.Name)
+		rating := facebookRating(f)
+		id := f.OpenGraphStory.ID
+		if id == "" && f.Reviewer.ID != "" && ok {
+			// Older exports lack the story ID; a reviewer can only leave
+			// one review at a time, so this is stable enough.
+			id = f.Reviewer.ID + "_" + f.CreatedTime
+		}
+		if id == "" || rating == 0 || !ok || author == "" {
+			result.Skipped++
+			continue
+		}
+
+		result.Reviews = append(result.Reviews, Review{
+			ExternalID: id,
+			Author:     author,
+			Rating:     rating,
+			Body:       strings.TrimSpace(f.ReviewText),
+			CreatedAt:  created,
+		})
+	}
+	return result, nil
+}
+
+// facebookRating maps recommendations, which replaced star ratings in
+// 2018, onto the five-star scale: recommended is 5, not recommended is 1.
+func facebookRating(f facebookReview) int64 {
+	if f.Rating >= 1 && f.Rating <= 5 {
+		return f.Rating
+	}
+	switch f.RecommendationType {
+	case "positive":
+		return 5
+	case "negative":
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package reviewimport
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// googleReview is a review as Google Business Profile exports it, the same
+// shape as the My Business API's accounts.locations.reviews resource.
+type googleReview struct {
+	Name     string `json:"name"` // accounts/{a}/locations/{l}/reviews/{id}
+	ReviewID string `json:"reviewId"`
+	Reviewer struct {
+		DisplayName string `json:"displayName"`
+		IsAnonymous bool   `json:"isAnonymous"`
+	} `json:"reviewer"`
+	StarRating string `json:"starRating"`
+	Comment    string `json:"comment"`
+	CreateTime string `json:"createTime"`
+}
+
+var googleStarRatings = map[string]int64{
+	"ONE":   1,
+	"TWO":   2,
+	"THREE": 3,
+	"FOUR":  4,
+	"FIVE":  5,
+}
+
+func parseGoogle(data []byte) (Result, error) {
+	list, err := unwrapList(data, "reviews")
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, raw := range list {
+		var g googleReview
+		if err := json.Unmarshal(raw, &g); err != nil {
+			result.Skipped++
+			continue
+		}
+
+		id := g.ReviewID
+		if id == "" {
+			id = g.Name[strings.LastIndex(g.Name, "/")+1:]
+		}
+		rating := googleStarRatings[g.StarRating]
+		created, ok := parseTime(g.CreateTime)
+		author := strings.TrimSpace(g.Reviewer.DisplayName)
+		if author == "" && g.Reviewer.IsAnonymous {
+			author = "A Google user"
+		}
+		if id == "" || rating == 0 || !ok || author == "" {
+			result.Skipped++
+			continue
+		}
+
+		result.Reviews = append(result.Reviews, Review{
+			ExternalID: id,
+			Author:     author,
+			Rating:     rating,
+			Body:       googleOriginalText(g.Comment),
+			CreatedAt:  created,
+		})
+	}
+	return result, nil
+}
+
+// googleOriginalText drops Google's machine translation from comments
+// written in another language, keeping what the customer wrote.
+func googleOriginalText(comment string) string {
+	if _, original, ok := strings.Cut(comment, "(Original)"); ok {
+		return strings.TrimSpace(original)
+	}
+	return strings.TrimSpace(comment)
+}
@@ -0,0 +1,91 @@
+// Package reviewimport reads review exports from Google Business Profile
+// (Takeout) and Facebook pages into a common shape for storing as reviews.
+package reviewimport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	SourceGoogle   = "google"
+	SourceFacebook = "facebook"
+)
+
+// maxExportSize bounds how much of an upload is read.
+const maxExportSize = 10 << 20
+
+var ErrUnknownSource = errors.New("reviewimport: unknown source")
+
+// Review is one review from an export.
+type Review struct {
+	ExternalID string // the platform's ID, stable across exports
+	Author     string
+	Rating     int64 // 1-5
+	Body       string
+	CreatedAt  time.Time
+}
+
+// Result is everything usable in an export plus a count of entries that
+// were skipped for missing an author, rating or date.
+type Result struct {
+	Reviews []Review
+	Skipped int
+}
+
+// Parse reads an export from source ("google" or "facebook").
+func Parse(source string, r io.Reader) (Result, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxExportSize+1))
+	if err != nil {
+		return Result{}, err
+	}
+	if len(data) > maxExportSize {
+		return Result{}, fmt.Errorf("reviewimport: export is larger than %d MB", maxExportSize>>20)
+	}
+
+	switch source {
+	case SourceGoogle:
+		return parseGoogle(data)
+	case SourceFacebook:
+		return parseFacebook(data)
+	default:
+		return Result{}, ErrUnknownSource
+	}
+}
+
+// unwrapList accepts either a bare JSON array or an object holding the
+// array under key, which is how the same export looks depending on whether
+// it came from the download tool or the API.
+func unwrapList(data []byte, key string) ([]json.RawMessage, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var list []json.RawMessage
+		err := json.Unmarshal(data, &list)
+		return list, err
+	}
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+	raw, ok := wrapper[key]
+	if !ok {
+		return nil, fmt.Errorf("reviewimport: no %q list in export", key)
+	}
+	var list []json.RawMessage
+	err := json.Unmarshal(raw, &list)
+	return list, err
+}
+
+// parseTime accepts RFC 3339 and Facebook's numeric-offset variant.
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05-0700"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,160 @@
+package reviewimport
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFixtures(t *testing.T) {
+	at := func(value string) time.Time {
+		t.Helper()
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed.UTC()
+	}
+
+	tests := []struct {
+		file    string
+		source  string
+		want    Result
+		wantErr bool
+	}{
+		{
+			file:   "google_takeout.json",
+			source: SourceGoogle,
+			want: Result{
+				Reviews: []Review{
+					{ExternalID: "AbFvOqk1", Author: "Dana Whitfield", Rating: 5, Body: "Truck looks brand new. Booking was easy.", CreatedAt: at("2025-06-14T18:22:05.123456Z")},
+					// The ID comes from the resource name, and only the
+					// customer's own words are kept
+					{ExternalID: "AbFvOqk2", Author: "Luis Ortega", Rating: 4, Body: "Muy buen trabajo en el interior", CreatedAt: at("2025-07-01T12:00:00Z")},
+					{ExternalID: "AbFvOqk3", Author: "A Google user", Rating: 2, CreatedAt: at("2025-07-03T13:30:00Z")},
+				},
+				// No stars, a bad date, no author, and an entry that isn't
+				// a review at all
+				Skipped: 4,
+			},
+		},
+		{
+			file:   "google_bare.json",
+			source: SourceGoogle,
+			want: Result{Reviews: []Review{
+				{ExternalID: "ZzTop9", Author: "Pat Kim", Rating: 1, Body: "Missed my appointment window.", CreatedAt: at("2024-12-31T23:59:59Z")},
+			}},
+		},
+		{
+			file:   "facebook_ratings.json",
+			source: SourceFacebook,
+			want: Result{
+				Reviews: []Review{
+					{ExternalID: "1234567890_555", Author: "Morgan Lee", Rating: 5, Body: "Ceramic coating still beading water after a year.", CreatedAt: at("2025-05-02T14:11:09Z")},
+					// Recommendations map onto stars, and reviews without
+					// a story ID fall back to reviewer and time
+					{ExternalID: "10159_2025-05-09T10:00:00-0400", Author: "Sam Patel", Rating: 5, Body: "Recommended!", CreatedAt: at("2025-05-09T14:00:00Z")},
+					{ExternalID: "1234567890_777", Author: "Riley Chen", Rating: 1, CreatedAt: at("2025-05-10T10:00:00Z")},
+				},
+				// No rating, no IDs at all, and a rating off the scale
+				Skipped: 3,
+			},
+		},
+		{
+			file:   "facebook_bare.json",
+			source: SourceFacebook,
+			want: Result{Reviews: []Review{
+				{ExternalID: "20001_2023-11-20T09:15:00+0000", Author: "Jordan Blake", Rating: 3, Body: "Fine, a little pricey.", CreatedAt: at("2023-11-20T09:15:00Z")},
+			}},
+		},
+		{file: "truncated.json", source: SourceGoogle, wantErr: true},
+		{file: "wrong_key.json", source: SourceGoogle, wantErr: true},
+		{file: "wrong_key.json", source: SourceFacebook, wantErr: true},
+		// A Google export read as Facebook has no "data" list
+		{file: "google_takeout.json", source: SourceFacebook, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source+"/"+tt.file, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			got, err := Parse(tt.source, f)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Skipped != tt.want.Skipped {
+				t.Errorf("Skipped = %d, want %d", got.Skipped, tt.want.Skipped)
+			}
+			if len(got.Reviews) != len(tt.want.Reviews) {
+				t.Fatalf("got %d reviews, want %d: %+v", len(got.Reviews), len(tt.want.Reviews), got.Reviews)
+			}
+			for i := range got.Reviews {
+				if !reflect.DeepEqual(got.Reviews[i], tt.want.Reviews[i]) {
+					t.Errorf("review %d =\n  %+v\nwant\n  %+v", i, got.Reviews[i], tt.want.Reviews[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseIsStableAcrossExports(t *testing.T) {
+	// Re-importing the same export must produce the same IDs so the
+	// importer can skip reviews it already has.
+	for _, tt := range []struct{ file, source string }{
+		{"google_takeout.json", SourceGoogle},
+		{"facebook_ratings.json", SourceFacebook},
+	} {
+		data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := Parse(tt.source, bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := Parse(tt.source, bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[string]bool)
+		for i, r := range first.Reviews {
+			if seen[r.ExternalID] {
+				t.Errorf("%s: duplicate ID %q", tt.file, r.ExternalID)
+			}
+			seen[r.ExternalID] = true
+			if second.Reviews[i].ExternalID != r.ExternalID {
+				t.Errorf("%s: ID changed between parses: %q, %q", tt.file, r.ExternalID, second.Reviews[i].ExternalID)
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse("yelp", bytes.NewReader([]byte("[]"))); !errors.Is(err, ErrUnknownSource) {
+		t.Errorf("unknown source: err = %v, want ErrUnknownSource", err)
+	}
+
+	huge := append([]byte("["), bytes.Repeat([]byte(" "), maxExportSize)...)
+	huge = append(huge, ']')
+	if _, err := Parse(SourceGoogle, bytes.NewReader(huge)); err == nil {
+		t.Error("oversized export: no error")
+	}
+
+	got, err := Parse(SourceGoogle, bytes.NewReader([]byte("[]")))
+	if err != nil || len(got.Reviews) != 0 || got.Skipped != 0 {
+		t.Errorf("empty export: %+v, %v", got, err)
+	}
+}
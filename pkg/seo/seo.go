@@ -0,0 +1,142 @@
+// Package seo builds the schema.org JSON-LD embedded in public pages. Every
+// builder takes the site's base URL because structured data must use
+// absolute URLs.
+package seo
+
+import (
+	"fmt"
+	"strings"
+
+	"detailingpass/pkg/db"
+)
+
+// Business details shown in search results; these match the site footer.
+const (
+	BusinessName  = "C Auto Detailing Studio"
+	BusinessPhone = "+1-555-123-4567"
+	BusinessEmail = "info@cautodetailingstudio.com"
+)
+
+// Object is a JSON-LD node.
+type Object map[string]any
+
+// Image is a picture in an ImageGallery.
+type Image struct {
+	URL     string
+	Caption string
+}
+
+// AbsoluteURL resolves a site path against baseURL, leaving full URLs (CDN
+// or signed storage links) as they are.
+func AbsoluteURL(baseURL, ref string) string {
+	if ref == "" || strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return ref
+	}
+	if !strings.HasPrefix(ref, "/") {
+		ref = "/" + ref
+	}
+	return strings.TrimRight(baseURL, "/") + ref
+}
+
+// Business describes the shop as an AutoRepair, the LocalBusiness subtype
+// search engines use for detailers. rating may be nil.
+func Business(baseURL string, rating Object) Object {
+	business := Object{
+		"@context":  "https://schema.org",
+		"@type":     "AutoRepair",
+		"@id":       AbsoluteURL(baseURL, "/#business"),
+		"name":      BusinessName,
+		"url":       AbsoluteURL(baseURL, "/"),
+		"telephone": BusinessPhone,
+		"email":     BusinessEmail,
+		"image":     AbsoluteURL(baseURL, "/favicon.png"),
+	}
+	if rating != nil {
+		business["aggregateRating"] = rating
+	}
+	return business
+}
+
+// AggregateRating summarises approved reviews, or returns nil when there
+// are none; an empty rating is invalid structured data.
+func AggregateRating(average float64, count int64) Object {
+	if count == 0 {
+		return nil
+	}
+	return Object{
+		"@type":       "AggregateRating",
+		"ratingValue": fmt.Sprintf("%.1f", average),
+		"reviewCount": count,
+		"bestRating":  5,
+		"worstRating": 1,
+	}
+}
+
+// Service describes a package, with its price range as the offer.
+func Service(baseURL string, pkg db.Package) Object {
+	service := Object{
+		"@context":    "https://schema.org",
+		"@type":       "Service",
+		"name":        pkg.Name,
+		"serviceType": "Auto detailing",
+		"url":         AbsoluteURL(baseURL, "/services/"+pkg.Slug),
+		"provider": Object{
+			"@type": "AutoRepair",
+			"@id":   AbsoluteURL(baseURL, "/#business"),
+			"name":  BusinessName,
+		},
+	}
+	if pkg.ShortDesc.String != "" {
+		service["description"] = pkg.ShortDesc.String
+	}
+	if pkg.PriceMin.Valid && pkg.PriceMin.Int64 > 0 {
+		if pkg.PriceMax.Valid && pkg.PriceMax.Int64 > pkg.PriceMin.Int64 {
+			service["offers"] = Object{
+				"@type":         "AggregateOffer",
+				"priceCurrency": "USD",
+				"lowPrice":      price(pkg.PriceMin.Int64),
+				"highPrice":     price(pkg.PriceMax.Int64),
+			}
+		} else {
+			service["offers"] = Object{
+				"@type":         "Offer",
+				"priceCurrency": "USD",
+				"price":         price(pkg.PriceMin.Int64),
+			}
+		}
+	}
+	return service
+}
+
+// ImageGallery describes a gallery project page and its photos. Image URLs
+// are resolved against baseURL.
+func ImageGallery(baseURL, path, name, description string, images []Image) Object {
+	gallery := Object{
+		"@context": "https://schema.org",
+		"@type":    "ImageGallery",
+		"name":     name,
+		"url":      AbsoluteURL(baseURL, path),
+	}
+	if description != "" {
+		gallery["description"] = description
+	}
+	var objects []Object
+	for _, img := range images {
+		obj := Object{
+			"@type":      "ImageObject",
+			"contentUrl": AbsoluteURL(baseURL, img.URL),
+		}
+		if img.Caption != "" {
+			obj["caption"] = img.Caption
+		}
+		objects = append(objects, obj)
+	}
+	if len(objects) > 0 {
+		gallery["image"] = objects
+	}
+	return gallery
+}
+
+func price(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
@@ -0,0 +1,102 @@
+package seo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"detailingpass/pkg/db"
+)
+
+func TestAbsoluteURL(t *testing.T) {
+	tests := []struct {
+		base, ref, want string
+	}{
+		{"https://example.com", "/services", "https://example.com/services"},
+		{"https://example.com/", "/services", "https://example.com/services"},
+		{"https://example.com", "services", "https://example.com/services"},
+		{"https://example.com", "", ""},
+		{"https://example.com", "https://cdn.example.net/a.jpg", "https://cdn.example.net/a.jpg"},
+		{"https://example.com", "http://old.example.com/a.jpg", "http://old.example.com/a.jpg"},
+	}
+	for _, tt := range tests {
+		if got := AbsoluteURL(tt.base, tt.ref); got != tt.want {
+			t.Errorf("AbsoluteURL(%q, %q) = %q, want %q", tt.base, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestAggregateRating(t *testing.T) {
+	if got := AggregateRating(0, 0); got != nil {
+		t.Errorf("AggregateRating with no reviews = %v, want nil", got)
+	}
+	got := AggregateRating(4.666, 12)
+	if got["ratingValue"] != "4.7" || got["reviewCount"] != int64(12) {
+		t.Errorf("AggregateRating = %v", got)
+	}
+	if Business("https://example.com", nil)["aggregateRating"] != nil {
+		t.Error("Business without a rating has an aggregateRating")
+	}
+	if Business("https://example.com", got)["aggregateRating"] == nil {
+		t.Error("Business dropped its rating")
+	}
+}
+
+func TestServiceOffers(t *testing.T) {
+	cents := func(v int64) sql.NullInt64 { return sql.NullInt64{Int64: v, Valid: true} }
+
+	tests := []struct {
+		name     string
+		min, max sql.NullInt64
+		want     Object
+	}{
+		{
+			name: "range",
+			min:  cents(15000), max: cents(25050),
+			want: Object{"@type": "AggregateOffer", "priceCurrency": "USD", "lowPrice": "150.00", "highPrice": "250.50"},
+		},
+		{
+			name: "fixed",
+			min:  cents(8905), max: cents(8905),
+			want: Object{"@type": "Offer", "priceCurrency": "USD", "price": "89.05"},
+		},
+		{name: "no price"},
+		{name: "zero price", min: cents(0)},
+	}
+	for _, tt := range tests {
+		got := Service("https://example.com", db.Package{Slug: "interior", Name: "Interior", PriceMin: tt.min, PriceMax: tt.max})
+		if got["url"] != "https://example.com/services/interior" {
+			t.Errorf("%s: url = %v", tt.name, got["url"])
+		}
+		offers, _ := got["offers"].(Object)
+		if tt.want == nil {
+			if offers != nil {
+				t.Errorf("%s: offers = %v, want none", tt.name, offers)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(offers, tt.want) {
+			t.Errorf("%s: offers = %v, want %v", tt.name, offers, tt.want)
+		}
+	}
+}
+
+func TestImageGallery(t *testing.T) {
+	got := ImageGallery("https://example.com", "/gallery/model-3", "Model 3", "", []Image{
+		{URL: "/uploads/a.jpg", Caption: "Before"},
+		{URL: "https://cdn.example.net/b.jpg"},
+	})
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"@context":"https://schema.org","@type":"ImageGallery","image":[{"@type":"ImageObject","caption":"Before","contentUrl":"https://example.com/uploads/a.jpg"},{"@type":"ImageObject","contentUrl":"https://cdn.example.net/b.jpg"}],"name":"Model 3","url":"https://example.com/gallery/model-3"}`
+	if string(data) != want {
+		t.Errorf("ImageGallery =\n  %s\nwant\n  %s", data, want)
+	}
+
+	if _, ok := ImageGallery("https://example.com", "/gallery/x", "X", "", nil)["image"]; ok {
+		t.Error("ImageGallery without photos has an image list")
+	}
+}
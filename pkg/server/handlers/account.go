@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/auth"
+	"detailingpass/pkg/db"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Account is the signed-in customer's portal. It lists the invoices sent
+// to their email address or for bookings they made while signed in.
+func (h *Handler) Account(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	info := auth.GetUserInfo(ctx)
+	rows, err := queries.ListCustomerInvoices(ctx, customerInvoicesParams(ctx, info))
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load your invoices")
+	}
+
+	data := pages.AccountPageData{}
+	if info != nil {
+		data.FirstName = info.FirstName
+		data.Email = info.Email
+	}
+	_, data.Staff = currentStaff(ctx, queries)
+	now := time.Now()
+	if row, ok := claimMembership(ctx, queries); ok {
+		rules := membershipRules(row)
+		from, to := rules.Period(now)
+		used, err := membershipUsage(ctx, queries, row, now)
+		if err != nil {
+			c.Logger().Warnf("Failed to count membership bookings: %v", err)
+		}
+		var packageName string
+		if row.PackageID.Valid {
+			if pkg, err := queries.GetPackageByID(ctx, row.PackageID.Int64); err == nil {
+				packageName = pkg.Name
+			}
+		}
+		data.Membership = &pages.AccountMembership{
+			Plan:      row.PlanName,
+			Includes:  membershipIncludesLabel(row.ServicesPerPeriod, packageName),
+			Period:    membershipPeriodRange(from, to),
+			Remaining: rules.Remaining(used),
+			RenewsOn:  rules.PaidThrough.Format("January 2, 2006"),
+			Lapsed:    !rules.InGoodStanding(now),
+		}
+	}
+	for _, row := range rows {
+		inv := invoiceFromRow(db.ListInvoicesRow(row))
+		data.Invoices = append(data.Invoices, pages.AccountInvoice{
+			ID:          row.ID,
+			Number:      row.Number.String,
+			Status:      invoiceDisplayStatus(inv, now),
+			IssuedLabel: invoiceDateLabel(row.IssuedAt),
+			DueLabel:    invoiceDateLabel(row.DueAt),
+			Total:       invoiceTotals(inv, row.Subtotal).Total,
+		})
+	}
+
+	return pages.AccountPage(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) AccountInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	inv, ok := customerInvoice(c, queries)
+	if !ok {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	data := pages.InvoicePageData{
+		State:   pages.InvoiceLinkOpen,
+		PDFURL:  "/account/invoices/" + c.Param("id") + "/pdf",
+		BackURL: "/account",
+	}
+	if err := fillInvoicePage(ctx, queries, &data, inv); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load invoice")
+	}
+	return pages.InvoicePage(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) AccountInvoicePDF(c echo.Context) error {
+	queries := db.New(h.db)
+
+	inv, ok := customerInvoice(c, queries)
+	if !ok {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	return writeInvoicePDF(c, queries, inv)
+}
+
+// customerInvoice loads the invoice in the URL if the signed-in customer
+// can see it.
+func customerInvoice(c echo.Context, queries *db.Queries) (db.Invoice, bool) {
+	ctx := c.Request().Context()
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return db.Invoice{}, false
+	}
+	rows, err := queries.ListCustomerInvoices(ctx, customerInvoicesParams(ctx, auth.GetUserInfo(ctx)))
+	if err != nil {
+		return db.Invoice{}, false
+	}
+	for _, row := range rows {
+		if row.ID == id {
+			inv, err := queries.GetInvoiceByID(ctx, id)
+			return inv, err == nil
+		}
+	}
+	return db.Invoice{}, false
+}
+
+// customerInvoicesParams matches on the account's email when Clerk can
+// tell us it, and always on the user ID saved with their bookings.
+func customerInvoicesParams(ctx context.Context, info *auth.UserInfo) db.ListCustomerInvoicesParams {
+	params := db.ListCustomerInvoicesParams{ClerkUserID: auth.GetUserID(ctx)}
+	if info != nil {
+		params.Email = strings.ToLower(strings.TrimSpace(info.Email))
+	}
+	return params
+}
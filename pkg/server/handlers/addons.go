@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"detailingpass/pkg/db"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) AdminAddons(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	addons, err := queries.ListAddons(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch add-ons")
+	}
+
+	data := pages.AdminAddonsData{
+		Addons:       addons,
+		ErrorMessage: c.QueryParam("error"),
+	}
+	if id, err := strconv.ParseInt(c.QueryParam("edit"), 10, 64); err == nil {
+		for _, a := range addons {
+			if a.ID == id {
+				addon := a
+				data.Editing = &addon
+			}
+		}
+	}
+
+	return pages.AdminAddons(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) CreateAddon(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	params, errMsg := parseAddonForm(c)
+	if errMsg != "" {
+		return addonsRedirect(c, errMsg)
+	}
+	if _, err := queries.CreateAddon(ctx, params); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create add-on: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/addons")
+}
+
+func (h *Handler) UpdateAddon(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid add-on ID")
+	}
+	params, errMsg := parseAddonForm(c)
+	if errMsg != "" {
+		return addonsRedirect(c, errMsg)
+	}
+
+	err = queries.UpdateAddon(ctx, db.UpdateAddonParams{
+		Name:        params.Name,
+		Description: params.Description,
+		Price:       params.Price,
+		IsActive:    params.IsActive,
+		SortOrder:   params.SortOrder,
+		ID:          id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update add-on: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/addons")
+}
+
+func (h *Handler) DeleteAddon(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid add-on ID")
+	}
+	if err := queries.DeleteAddon(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete add-on: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/addons")
+}
+
+func parseAddonForm(c echo.Context) (db.CreateAddonParams, string) {
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return db.CreateAddonParams{}, "Name is required"
+	}
+	price, err := parseDollars(c.FormValue("price"))
+	if err != nil {
+		return db.CreateAddonParams{}, "Enter the price in dollars, e.g. 49.99"
+	}
+	description := strings.TrimSpace(c.FormValue("description"))
+	sortOrder, _ := strconv.ParseInt(c.FormValue("sort_order"), 10, 64)
+
+	return db.CreateAddonParams{
+		Name:        name,
+		Description: sql.NullString{String: description, Valid: description != ""},
+		Price:       price,
+		IsActive:    sql.NullBool{Bool: c.FormValue("is_active") == "true", Valid: true},
+		SortOrder:   sql.NullInt64{Int64: sortOrder, Valid: true},
+	}, ""
+}
+
+func addonsRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/addons?error="+url.QueryEscape(errMsg))
+}
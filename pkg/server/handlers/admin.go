@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"detailingpass/pkg/db"
+	"detailingpass/pkg/staffing"
 	"detailingpass/web/templates/pages"
 
 	"github.com/labstack/echo/v4"
@@ -58,18 +60,23 @@ func (h *Handler) AdminPackages(c echo.Context) error {
 			pkg, err := queries.GetPackageByID(ctx, id)
 			if err == nil {
 				formData = &pages.PackageFormData{
-					ID:          pkg.ID,
-					Slug:        pkg.Slug,
-					Name:        pkg.Name,
-					ShortDesc:   pkg.ShortDesc.String,
-					LongDesc:    pkg.LongDesc.String,
-					PriceMin:    pkg.PriceMin.Int64,
-					PriceMax:    pkg.PriceMax.Int64,
-					DurationEst: pkg.DurationEst.Int64,
-					IsActive:    pkg.IsActive.Bool,
-					SortOrder:   pkg.SortOrder.Int64,
-					IsEdit:      true,
+					ID:             pkg.ID,
+					Slug:           pkg.Slug,
+					Name:           pkg.Name,
+					ShortDesc:      pkg.ShortDesc.String,
+					LongDesc:       pkg.LongDesc.String,
+					PriceMin:       pkg.PriceMin.Int64,
+					PriceMax:       pkg.PriceMax.Int64,
+					DurationEst:    pkg.DurationEst.Int64,
+					IsActive:       pkg.IsActive.Bool,
+					SortOrder:      pkg.SortOrder.Int64,
+					Features:       pkg.Features.String,
+					Included:       pkg.Included.String,
+					Excluded:       pkg.Excluded.String,
+					IsEdit:         true,
+					RequiredSkills: staffing.ParseSkills(pkg.RequiredSkills.String),
 				}
+				loadPackageContent(c, queries, formData)
 			}
 		}
 	}
@@ -86,6 +93,10 @@ func (h *Handler) CreatePackage(c echo.Context) error {
 	slug := c.FormValue("slug")
 	shortDesc := c.FormValue("short_desc")
 	longDesc := c.FormValue("long_desc")
+	features := cleanPackageLines(c.FormValue("features"))
+	included := cleanPackageLines(c.FormValue("included"))
+	excluded := cleanPackageLines(c.FormValue("excluded"))
+	requiredSkills := packageSkillsValue(c)
 
 	// Parse prices (convert from dollars to cents)
 	priceMinStr := c.FormValue("price_min")
@@ -109,15 +120,19 @@ func (h *Handler) CreatePackage(c echo.Context) error {
 
 	// Create package
 	_, err := queries.CreatePackage(ctx, db.CreatePackageParams{
-		Slug:        slug,
-		Name:        name,
-		ShortDesc:   sql.NullString{String: shortDesc, Valid: shortDesc != ""},
-		LongDesc:    sql.NullString{String: longDesc, Valid: longDesc != ""},
-		PriceMin:    sql.NullInt64{Int64: priceMinCents, Valid: true},
-		PriceMax:    sql.NullInt64{Int64: priceMaxCents, Valid: true},
-		DurationEst: sql.NullInt64{Int64: durationMinutes, Valid: true},
-		IsActive:    sql.NullBool{Bool: isActive, Valid: true},
-		SortOrder:   sql.NullInt64{Int64: sortOrder, Valid: true},
+		Slug:           slug,
+		Name:           name,
+		ShortDesc:      sql.NullString{String: shortDesc, Valid: shortDesc != ""},
+		LongDesc:       sql.NullString{String: longDesc, Valid: longDesc != ""},
+		PriceMin:       sql.NullInt64{Int64: priceMinCents, Valid: true},
+		PriceMax:       sql.NullInt64{Int64: priceMaxCents, Valid: true},
+		DurationEst:    sql.NullInt64{Int64: durationMinutes, Valid: true},
+		IsActive:       sql.NullBool{Bool: isActive, Valid: true},
+		SortOrder:      sql.NullInt64{Int64: sortOrder, Valid: true},
+		Features:       sql.NullString{String: features, Valid: features != ""},
+		Included:       sql.NullString{String: included, Valid: included != ""},
+		Excluded:       sql.NullString{String: excluded, Valid: excluded != ""},
+		RequiredSkills: sql.NullString{String: requiredSkills, Valid: requiredSkills != ""},
 	})
 
 	if err != nil {
@@ -143,6 +158,10 @@ func (h *Handler) UpdatePackage(c echo.Context) error {
 	slug := c.FormValue("slug")
 	shortDesc := c.FormValue("short_desc")
 	longDesc := c.FormValue("long_desc")
+	features := cleanPackageLines(c.FormValue("features"))
+	included := cleanPackageLines(c.FormValue("included"))
+	excluded := cleanPackageLines(c.FormValue("excluded"))
+	requiredSkills := packageSkillsValue(c)
 
 	// Parse prices (convert from dollars to cents)
 	priceMinStr := c.FormValue("price_min")
@@ -166,16 +185,20 @@ func (h *Handler) UpdatePackage(c echo.Context) error {
 
 	// Update package
 	_, err = queries.UpdatePackage(ctx, db.UpdatePackageParams{
-		ID:          id,
-		Slug:        slug,
-		Name:        name,
-		ShortDesc:   sql.NullString{String: shortDesc, Valid: shortDesc != ""},
-		LongDesc:    sql.NullString{String: longDesc, Valid: longDesc != ""},
-		PriceMin:    sql.NullInt64{Int64: priceMinCents, Valid: true},
-		PriceMax:    sql.NullInt64{Int64: priceMaxCents, Valid: true},
-		DurationEst: sql.NullInt64{Int64: durationMinutes, Valid: true},
-		IsActive:    sql.NullBool{Bool: isActive, Valid: true},
-		SortOrder:   sql.NullInt64{Int64: sortOrder, Valid: true},
+		ID:             id,
+		Slug:           slug,
+		Name:           name,
+		ShortDesc:      sql.NullString{String: shortDesc, Valid: shortDesc != ""},
+		LongDesc:       sql.NullString{String: longDesc, Valid: longDesc != ""},
+		PriceMin:       sql.NullInt64{Int64: priceMinCents, Valid: true},
+		PriceMax:       sql.NullInt64{Int64: priceMaxCents, Valid: true},
+		DurationEst:    sql.NullInt64{Int64: durationMinutes, Valid: true},
+		IsActive:       sql.NullBool{Bool: isActive, Valid: true},
+		SortOrder:      sql.NullInt64{Int64: sortOrder, Valid: true},
+		Features:       sql.NullString{String: features, Valid: features != ""},
+		Included:       sql.NullString{String: included, Valid: included != ""},
+		Excluded:       sql.NullString{String: excluded, Valid: excluded != ""},
+		RequiredSkills: sql.NullString{String: requiredSkills, Valid: requiredSkills != ""},
 	})
 
 	if err != nil {
@@ -204,3 +227,26 @@ func (h *Handler) DeletePackage(c echo.Context) error {
 
 	return c.Redirect(http.StatusSeeOther, "/admin/packages")
 }
+
+// packageSkillsValue reads the skills ticked on the package form, as
+// stored.
+func packageSkillsValue(c echo.Context) string {
+	form, err := c.FormParams()
+	if err != nil {
+		return ""
+	}
+	return staffing.JoinSkills(form["required_skills"])
+}
+
+// cleanPackageLines normalizes a one-item-per-line textarea, dropping blank
+// lines and stray bullet characters pasted in with the text.
+func cleanPackageLines(raw string) string {
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•▪"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -8,16 +8,19 @@ import (
 	"time"
 
 	"detailingpass/pkg/db"
+	"detailingpass/pkg/promo"
+	"detailingpass/pkg/staffing"
 	"detailingpass/web/templates/pages"
 
 	"github.com/labstack/echo/v4"
 )
 
 var (
-	bookingStatusOptions = []string{"pending", "confirmed", "declined", "cancelled"}
+	bookingStatusOptions = []string{"pending", "confirmed", "completed", "declined", "cancelled"}
 	bookingStatusSet     = map[string]bool{
 		"pending":   true,
 		"confirmed": true,
+		"completed": true,
 		"declined":  true,
 		"cancelled": true,
 	}
@@ -43,12 +46,98 @@ func (h *Handler) AdminBookings(c echo.Context) error {
 	total, _ := queries.CountBookings(ctx)
 	pending, _ := queries.CountBookingsByStatus(ctx, sql.NullString{String: "pending", Valid: true})
 	confirmed, _ := queries.CountBookingsByStatus(ctx, sql.NullString{String: "confirmed", Valid: true})
+	completed, _ := queries.CountBookingsByStatus(ctx, sql.NullString{String: "completed", Valid: true})
 	declined, _ := queries.CountBookingsByStatus(ctx, sql.NullString{String: "declined", Valid: true})
 	cancelled, _ := queries.CountBookingsByStatus(ctx, sql.NullString{String: "cancelled", Valid: true})
+	seriesConflicts, _ := queries.CountSeriesConflicts(ctx, time.Now().UTC())
+	waitlisted, _ := queries.CountWaitlistEntries(ctx, time.Now().UTC())
+
+	// Keyed by booking; rows are ordered by ID so the latest request wins.
+	reviewRequests := make(map[int64]db.ReviewRequest)
+	requestRows, err := queries.ListReviewRequestsForBookingPage(ctx, db.ListReviewRequestsForBookingPageParams{
+		Limit:  adminBookingsPageSize,
+		Offset: offset,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to load review requests: %v", err)
+	}
+	for _, request := range requestRows {
+		reviewRequests[request.BookingID] = request
+	}
+
+	invoices := make(map[int64][]pages.BookingInvoice)
+	invoiceRows, err := queries.ListInvoicesForBookingPage(ctx, db.ListInvoicesForBookingPageParams{
+		Limit:  adminBookingsPageSize,
+		Offset: offset,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to load invoices: %v", err)
+	}
+	for _, inv := range invoiceRows {
+		invoices[inv.BookingID.Int64] = append(invoices[inv.BookingID.Int64], pages.BookingInvoice{
+			ID:     inv.ID,
+			Number: inv.Number.String,
+			Status: inv.Status.String,
+		})
+	}
+
+	// Keyed by booking; the latest deposit wins
+	deposits := make(map[int64]*pages.BookingDeposit)
+	depositRows, err := queries.ListDepositsForBookingPage(ctx, db.ListDepositsForBookingPageParams{
+		Limit:  adminBookingsPageSize,
+		Offset: offset,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to load deposits: %v", err)
+	}
+	for _, deposit := range depositRows {
+		when := deposit.PaidAt
+		if deposit.RefundedAt.Valid {
+			when = deposit.RefundedAt
+		}
+		deposits[deposit.BookingID.Int64] = &pages.BookingDeposit{
+			Amount:   deposit.Amount,
+			Status:   deposit.Status,
+			Provider: deposit.Provider,
+			Refunded: deposit.RefundedAmount,
+			When:     formatAdminTime(when),
+		}
+	}
+
+	groups, err := queries.ListGalleryGroups(ctx, db.ListGalleryGroupsParams{Limit: 200, Offset: 0})
+	if err != nil {
+		c.Logger().Warnf("Failed to load gallery groups: %v", err)
+	}
+	galleryOptions := make([]pages.BookingGalleryOption, 0, len(groups))
+	for _, group := range groups {
+		galleryOptions = append(galleryOptions, pages.BookingGalleryOption{ID: group.ID, Title: group.Title})
+	}
+
+	// Everyone is listed by name, so jobs still show who had them after
+	// they leave; only active technicians can be picked
+	staffNames := make(map[int64]string)
+	var staffOptions []pages.BookingStaffOption
+	staffRows, err := queries.ListStaff(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to load staff: %v", err)
+	}
+	for _, member := range staffRows {
+		staffNames[member.ID] = member.Name
+		if member.IsActive.Bool && staffing.DoesJobs(member.Role) {
+			staffOptions = append(staffOptions, pages.BookingStaffOption{ID: member.ID, Name: member.Name})
+		}
+	}
 
 	items := make([]pages.AdminBookingItem, 0, len(rows))
 	for _, row := range rows {
-		items = append(items, buildAdminBookingItem(row))
+		item := buildAdminBookingItem(row)
+		if request, ok := reviewRequests[row.ID]; ok {
+			item.ReviewRequest = buildBookingReviewRequest(request)
+		}
+		item.Invoices = invoices[row.ID]
+		item.Deposit = deposits[row.ID]
+		item.StaffName = staffNames[row.StaffID.Int64]
+		items = append(items, item)
 	}
 
 	hasNext := offset+int64(len(rows)) < total
@@ -57,11 +146,17 @@ func (h *Handler) AdminBookings(c echo.Context) error {
 			Total:     total,
 			Pending:   pending,
 			Confirmed: confirmed,
+			Completed: completed,
 			Declined:  declined,
 			Cancelled: cancelled,
 		},
-		Bookings:      items,
-		StatusOptions: bookingStatusOptions,
+		Bookings:        items,
+		StatusOptions:   bookingStatusOptions,
+		Galleries:       galleryOptions,
+		SeriesConflicts: seriesConflicts,
+		Waitlisted:      waitlisted,
+		Staff:           staffOptions,
+		ErrorMessage:    c.QueryParam("error"),
 		Pagination: pages.AdminPagination{
 			Page:     page,
 			PageSize: int(adminBookingsPageSize),
@@ -92,7 +187,23 @@ func (h *Handler) UpdateBookingStatus(c echo.Context) error {
 
 	internalNotes := strings.TrimSpace(c.FormValue("internal_notes"))
 
-	_, err = queries.UpdateBookingStatus(ctx, db.UpdateBookingStatusParams{
+	previous, err := queries.GetBookingByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Booking not found")
+	}
+
+	previousStatus := normalizeBookingStatus(previous.Status.String)
+	if status == "confirmed" && previousStatus != "confirmed" {
+		reason, err := depositBlocksConfirmation(ctx, queries, id)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to check deposit")
+		}
+		if reason != "" {
+			return c.String(http.StatusConflict, reason)
+		}
+	}
+
+	booking, err := queries.UpdateBookingStatus(ctx, db.UpdateBookingStatusParams{
 		Status: sql.NullString{
 			String: status,
 			Valid:  true,
@@ -107,11 +218,43 @@ func (h *Handler) UpdateBookingStatus(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to update booking")
 	}
 
+	// Calling a booking off refunds or forfeits its deposit. A failed refund
+	// can be retried from the card.
+	if (status == "cancelled" || status == "declined") && previousStatus != status {
+		if err := h.settleDeposit(ctx, queries, booking, status, time.Now()); err != nil {
+			c.Logger().Warnf("Failed to settle deposit for booking %d: %v", id, err)
+		}
+	}
+
+	// A slot given up is offered to the waitlist
+	if (status == "cancelled" || status == "declined") && (previousStatus == "pending" || previousStatus == "confirmed") {
+		h.offerFreedSlots([]time.Time{booking.RequestedStart})
+	}
+	// Other changes that take or free the slot still update booking pages
+	if bookingStatusBlocks(status) && !bookingStatusBlocks(previousStatus) {
+		publishSlotChange("booked", booking.RequestedStart)
+	} else if previousStatus == "completed" && !bookingStatusBlocks(status) {
+		publishSlotChange("freed", booking.RequestedStart)
+	}
+
+	// Finishing a job asks the customer for a review. The status change
+	// stands even if the email can't be sent; it can be resent from the card.
+	if status == "completed" && previousStatus != "completed" {
+		if err := h.sendReviewRequest(ctx, queries, booking, sql.NullInt64{}); err != nil {
+			c.Logger().Warnf("Failed to send review request for booking %d: %v", id, err)
+		}
+	}
+
+	return c.Redirect(http.StatusSeeOther, bookingsRedirect(c))
+}
+
+// bookingsRedirect returns to the bookings page the form was posted from.
+func bookingsRedirect(c echo.Context) string {
 	redirect := "/admin/bookings"
-	if pageParam := strings.TrimSpace(c.FormValue("page")); pageParam != "" {
-		redirect = redirect + "?page=" + pageParam
+	if page, err := strconv.Atoi(strings.TrimSpace(c.FormValue("page"))); err == nil && page > 1 {
+		redirect += "?page=" + strconv.Itoa(page)
 	}
-	return c.Redirect(http.StatusSeeOther, redirect)
+	return redirect
 }
 
 func parsePageParam(raw string) int {
@@ -139,6 +282,10 @@ func buildAdminBookingItem(row db.Booking) pages.AdminBookingItem {
 	if row.CreatedAt.Valid {
 		submittedAt = row.CreatedAt.Time.In(bookingLocation).Format("Jan 2, 2006 3:04 PM")
 	}
+	var promoLabel string
+	if row.PromoCode.Valid {
+		promoLabel = row.PromoCode.String + " · " + promo.Label(row.DiscountType.String, row.DiscountValue)
+	}
 
 	return pages.AdminBookingItem{
 		ID:            row.ID,
@@ -157,7 +304,30 @@ func buildAdminBookingItem(row db.Booking) pages.AdminBookingItem {
 		Source:        nullableString(row.Source),
 		StartISO:      startLocal.Format(time.RFC3339),
 		EndISO:        endLocal.Format(time.RFC3339),
+		Promo:         promoLabel,
+		MembershipID:  row.MembershipID.Int64,
+		SeriesID:      row.SeriesID.Int64,
+		Location:      mobileJobLabel(row),
+		StaffID:       row.StaffID.Int64,
+	}
+}
+
+func buildBookingReviewRequest(request db.ReviewRequest) *pages.BookingReviewRequest {
+	info := &pages.BookingReviewRequest{}
+	switch {
+	case request.UsedAt.Valid:
+		info.State = "reviewed"
+		info.When = request.UsedAt.Time.In(bookingLocation).Format("Jan 2, 2006")
+	case !request.ExpiresAt.After(time.Now()):
+		info.State = "expired"
+		info.When = request.ExpiresAt.In(bookingLocation).Format("Jan 2, 2006")
+	case request.SentAt.Valid:
+		info.State = "sent"
+		info.When = request.SentAt.Time.In(bookingLocation).Format("Jan 2, 2006")
+	default:
+		info.State = "unsent"
 	}
+	return info
 }
 
 func nullableString(value sql.NullString) string {
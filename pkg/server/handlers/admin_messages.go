@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/auth"
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/mailer"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const adminMessagesLimit = 100
+
+// AdminMessages is the contact form inbox. Opening a message with ?view=
+// marks it read.
+func (h *Handler) AdminMessages(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	filter := ""
+	if c.QueryParam("filter") == "unread" {
+		filter = "unread"
+	}
+
+	var selected *pages.ContactMessageView
+	if id, err := strconv.ParseInt(c.QueryParam("view"), 10, 64); err == nil {
+		if msg, err := queries.GetContactMessageByID(ctx, id); err == nil {
+			if !msg.IsRead.Bool {
+				err := queries.SetContactMessageRead(ctx, db.SetContactMessageReadParams{
+					IsRead: sql.NullBool{Bool: true, Valid: true},
+					ID:     msg.ID,
+				})
+				if err != nil {
+					c.Logger().Warnf("Failed to mark message %d read: %v", msg.ID, err)
+				}
+			}
+			selected = loadContactMessageView(c, queries, msg)
+		}
+	}
+
+	var messages []db.ContactMessage
+	var err error
+	if filter == "unread" {
+		messages, err = queries.ListUnreadContactMessages(ctx, adminMessagesLimit)
+	} else {
+		messages, err = queries.ListContactMessages(ctx, adminMessagesLimit)
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch messages")
+	}
+
+	total, _ := queries.CountContactMessages(ctx)
+	unread, _ := queries.CountUnreadContactMessages(ctx)
+
+	data := pages.AdminMessagesData{
+		Filter:       filter,
+		Total:        total,
+		Unread:       unread,
+		Selected:     selected,
+		ErrorMessage: c.QueryParam("error"),
+	}
+	for _, msg := range messages {
+		data.Messages = append(data.Messages, newContactMessageItem(msg))
+	}
+	for _, slot := range bookingSlotDefinitions {
+		data.Slots = append(data.Slots, pages.BookingSlot{
+			ID:          slot.ID,
+			Label:       slot.Label,
+			Description: slotWindowLabel(time.Date(2000, 1, 1, slot.StartHour, slot.StartMinute, 0, 0, bookingLocation), slot.Duration),
+		})
+	}
+
+	return pages.AdminMessages(data).Render(ctx, c.Response().Writer)
+}
+
+func loadContactMessageView(c echo.Context, queries *db.Queries, msg db.ContactMessage) *pages.ContactMessageView {
+	ctx := c.Request().Context()
+	msg.IsRead = sql.NullBool{Bool: true, Valid: true}
+	view := &pages.ContactMessageView{Message: newContactMessageItem(msg)}
+
+	replies, err := queries.ListContactReplies(ctx, msg.ID)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch replies for message %d: %v", msg.ID, err)
+	}
+	for _, r := range replies {
+		view.Replies = append(view.Replies, pages.ContactReplyItem{
+			ContactReply: r,
+			SentAt:       formatAdminTime(r.CreatedAt),
+		})
+	}
+
+	quotes, err := queries.ListQuotesForContactMessage(ctx, sql.NullInt64{Int64: msg.ID, Valid: true})
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch quotes for message %d: %v", msg.ID, err)
+	}
+	view.Quotes = quotes
+
+	if msg.BookingID.Valid {
+		if booking, err := queries.GetBookingByID(ctx, msg.BookingID.Int64); err == nil {
+			view.BookingLabel = fmt.Sprintf("%s (%s)",
+				booking.RequestedStart.In(bookingLocation).Format("Monday, January 2 at 3:04 PM"),
+				normalizeBookingStatus(booking.Status.String))
+		}
+	}
+	return view
+}
+
+func newContactMessageItem(msg db.ContactMessage) pages.ContactMessageItem {
+	return pages.ContactMessageItem{ContactMessage: msg, ReceivedAt: formatAdminTime(msg.CreatedAt)}
+}
+
+func formatAdminTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.In(bookingLocation).Format("Jan 2, 3:04 PM")
+}
+
+// ReplyToContactMessage emails a reply to the sender and keeps a copy on
+// the message.
+func (h *Handler) ReplyToContactMessage(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid message ID")
+	}
+	msg, err := queries.GetContactMessageByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Message not found")
+	}
+
+	body := strings.TrimSpace(c.FormValue("body"))
+	if body == "" {
+		return messagesRedirect(c, id, "Write a reply before sending")
+	}
+
+	quoted := "> " + strings.ReplaceAll(msg.Message, "\n", "\n> ")
+	err = h.mailer.Send(ctx, mailer.Message{
+		To:      []string{msg.Email},
+		ReplyTo: h.contactEmail,
+		Subject: "Re: your message to C Auto Detailing Studio",
+		Text:    fmt.Sprintf("%s\n\n%s wrote:\n%s\n", body, msg.Name, quoted),
+	})
+	if err != nil {
+		return messagesRedirect(c, id, fmt.Sprintf("Failed to send reply: %v", err))
+	}
+
+	var sentBy sql.NullString
+	if info := auth.GetUserInfo(ctx); info != nil && info.Email != "" {
+		sentBy = sql.NullString{String: info.Email, Valid: true}
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Reply sent but could not be saved")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	_, err = qtx.CreateContactReply(ctx, db.CreateContactReplyParams{
+		MessageID: id,
+		Body:      body,
+		SentBy:    sentBy,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Reply sent but could not be saved")
+	}
+	if err := qtx.MarkContactMessageReplied(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, "Reply sent but could not be saved")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Reply sent but could not be saved")
+	}
+
+	return messagesRedirect(c, id, "")
+}
+
+// UpdateContactMessageRead marks a message read or unread.
+func (h *Handler) UpdateContactMessageRead(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid message ID")
+	}
+	isRead := c.FormValue("is_read") == "true"
+
+	err = queries.SetContactMessageRead(ctx, db.SetContactMessageReadParams{
+		IsRead: sql.NullBool{Bool: isRead, Valid: true},
+		ID:     id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update message: %v", err))
+	}
+
+	if !isRead {
+		// Viewing the message would mark it read again
+		return c.Redirect(http.StatusSeeOther, messagesListURL(c))
+	}
+	return messagesRedirect(c, id, "")
+}
+
+// ConvertContactMessageToBooking books the sender into a slot and links the
+// booking to the message. The admin has already agreed the time with the
+// customer, so the booking starts out confirmed.
+func (h *Handler) ConvertContactMessageToBooking(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid message ID")
+	}
+	msg, err := queries.GetContactMessageByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Message not found")
+	}
+	if msg.BookingID.Valid {
+		return messagesRedirect(c, id, "This message has already been booked")
+	}
+
+	slotDef, ok := slotLookup[c.FormValue("slot_id")]
+	if !ok {
+		return messagesRedirect(c, id, "Choose a slot")
+	}
+	day, err := time.ParseInLocation("2006-01-02", c.FormValue("date"), bookingLocation)
+	if err != nil {
+		return messagesRedirect(c, id, "Choose a date")
+	}
+	start := time.Date(day.Year(), day.Month(), day.Day(), slotDef.StartHour, slotDef.StartMinute, 0, 0, bookingLocation)
+	if start.Before(time.Now()) {
+		return messagesRedirect(c, id, "That slot is in the past")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create booking")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	taken, err := slotTaken(ctx, qtx, start, time.Now(), "")
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create booking")
+	}
+	if taken {
+		return messagesRedirect(c, id, "That slot is already taken")
+	}
+
+	vehicle := strings.TrimSpace(c.FormValue("vehicle"))
+	booking, err := qtx.CreateBooking(ctx, db.CreateBookingParams{
+		CustomerName:    msg.Name,
+		Email:           msg.Email,
+		Phone:           msg.Phone,
+		VehicleDetails:  sql.NullString{String: vehicle, Valid: vehicle != ""},
+		ServiceInterest: msg.ServiceInterest,
+		Notes:           sql.NullString{String: msg.Message, Valid: true},
+		RequestedStart:  start.UTC(),
+		RequestedEnd:    start.UTC().Add(slotDef.Duration),
+		Status:          sql.NullString{String: "confirmed", Valid: true},
+		Source:          sql.NullString{String: "contact", Valid: true},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create booking: %v", err))
+	}
+	err = qtx.SetContactMessageBooking(ctx, db.SetContactMessageBookingParams{
+		BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+		ID:        id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create booking: %v", err))
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create booking")
+	}
+	publishSlotChange("booked", start)
+
+	return messagesRedirect(c, id, "")
+}
+
+func (h *Handler) DeleteContactMessage(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid message ID")
+	}
+	if err := queries.DeleteContactMessage(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete message: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, messagesListURL(c))
+}
+
+// messagesRedirect returns to a message in the inbox, keeping the list
+// filter, with an optional error.
+func messagesRedirect(c echo.Context, id int64, errMsg string) error {
+	q := url.Values{}
+	q.Set("view", strconv.FormatInt(id, 10))
+	if c.FormValue("filter") == "unread" {
+		q.Set("filter", "unread")
+	}
+	if errMsg != "" {
+		q.Set("error", errMsg)
+	}
+	return c.Redirect(http.StatusSeeOther, "/admin/messages?"+q.Encode())
+}
+
+func messagesListURL(c echo.Context) string {
+	if c.FormValue("filter") == "unread" {
+		return "/admin/messages?filter=unread"
+	}
+	return "/admin/messages"
+}
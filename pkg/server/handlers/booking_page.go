@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"detailingpass/pkg/db"
 	"detailingpass/web/templates/pages"
 
 	"github.com/labstack/echo/v4"
@@ -20,11 +21,40 @@ func (h *Handler) BookingPage(c echo.Context) error {
 		})
 	}
 
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	packages, err := queries.GetAllPackages(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch packages: %v", err)
+	}
+
 	data := pages.BookingPageData{
-		Slots: slots,
+		Slots:           slots,
+		Packages:        packages,
+		SelectedPackage: c.QueryParam("package"),
+		DepositPolicy:   h.depositPolicy(),
+		MobileService:   h.serviceArea.Enabled(),
+	}
+
+	now := time.Now()
+	if row, ok := claimMembership(ctx, queries); ok && membershipRules(row).InGoodStanding(now) {
+		used, err := membershipUsage(ctx, queries, row, now)
+		if err != nil {
+			c.Logger().Warnf("Failed to count membership bookings: %v", err)
+		}
+		data.Membership = &pages.BookingMembership{
+			Plan:      row.PlanName,
+			Remaining: membershipRules(row).Remaining(used),
+		}
+		if row.PackageID.Valid {
+			if pkg, err := queries.GetPackageByID(ctx, row.PackageID.Int64); err == nil {
+				data.Membership.Service = pkg.Name
+			}
+		}
 	}
 
-	return pages.Booking(data).Render(c.Request().Context(), c.Response().Writer)
+	return pages.Booking(data).Render(ctx, c.Response().Writer)
 }
 
 func formatSlotDuration(d time.Duration) string {
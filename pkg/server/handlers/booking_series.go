@@ -0,0 +1,807 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/recurrence"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+// seriesRule is the series' stored rule. Rules are validated before
+// they're saved, so one that doesn't parse has no occurrences.
+func seriesRule(series db.BookingSeries) recurrence.Rule {
+	rule, _ := recurrence.Parse(series.Rrule)
+	return rule
+}
+
+// seriesStart is when the series starts, in the shop's time zone so its
+// occurrences keep their time of day across daylight saving.
+func seriesStart(series db.BookingSeries) time.Time {
+	return series.StartsAt.In(bookingLocation)
+}
+
+// seriesHorizon is how far ahead series occurrences are booked: a day
+// past the furthest anyone else can book, so a repeat customer always
+// has their slot before it's offered to someone else.
+func seriesHorizon(now time.Time) time.Time {
+	local := now.In(bookingLocation)
+	return time.Date(local.Year(), local.Month(), local.Day()+maxBookingHorizon+2, 0, 0, 0, 0, bookingLocation)
+}
+
+// bookSeriesOccurrences makes bookings for the occurrences between the
+// series' booked_through and until. Dates whose slot is already taken, or
+// that fall on a day we're closed, are recorded as conflicts for the shop
+// to move or skip. It returns the slots booked.
+func bookSeriesOccurrences(ctx context.Context, queries *db.Queries, series db.BookingSeries, until time.Time, now time.Time) ([]time.Time, error) {
+	if !until.After(series.BookedThrough) {
+		return nil, nil
+	}
+	var booked []time.Time
+	duration := time.Duration(series.DurationMinutes) * time.Minute
+	for _, at := range seriesRule(series).Between(seriesStart(series), series.BookedThrough, until) {
+		// Dates already past when the series was made aren't booked
+		if !at.After(now) {
+			continue
+		}
+		taken, err := slotTaken(ctx, queries, at, now, "")
+		if err != nil {
+			return nil, err
+		}
+		occurrence := db.CreateSeriesOccurrenceParams{
+			SeriesID:    series.ID,
+			ScheduledAt: at.UTC(),
+			Status:      "conflict",
+		}
+		if !taken && !closedDays[at.Weekday()] {
+			booking, err := createSeriesBooking(ctx, queries, series, at, duration)
+			if err != nil {
+				return nil, err
+			}
+			occurrence.BookingID = sql.NullInt64{Int64: booking.ID, Valid: true}
+			occurrence.Status = "booked"
+			booked = append(booked, at)
+		}
+		if err := queries.CreateSeriesOccurrence(ctx, occurrence); err != nil {
+			return nil, err
+		}
+	}
+	err := queries.SetSeriesBookedThrough(ctx, db.SetSeriesBookedThroughParams{
+		BookedThrough: until.UTC(),
+		ID:            series.ID,
+	})
+	return booked, err
+}
+
+// createSeriesBooking books one occurrence of a series. The shop set the
+// series up with the customer, so its bookings start out confirmed.
+func createSeriesBooking(ctx context.Context, queries *db.Queries, series db.BookingSeries, at time.Time, duration time.Duration) (db.Booking, error) {
+	booking, err := queries.CreateBooking(ctx, db.CreateBookingParams{
+		CustomerName:    series.CustomerName,
+		Email:           series.Email,
+		Phone:           series.Phone,
+		VehicleDetails:  series.VehicleDetails,
+		ServiceInterest: series.ServiceInterest,
+		Notes:           series.Notes,
+		RequestedStart:  at.UTC(),
+		RequestedEnd:    at.Add(duration).UTC(),
+		Status:          sql.NullString{String: "confirmed", Valid: true},
+		Source:          sql.NullString{String: "series", Valid: true},
+	})
+	if err != nil {
+		return db.Booking{}, err
+	}
+	err = queries.SetBookingSeries(ctx, db.SetBookingSeriesParams{
+		SeriesID: sql.NullInt64{Int64: series.ID, Valid: true},
+		ID:       booking.ID,
+	})
+	return booking, err
+}
+
+// extendBookingSeries books the series occurrences that have come into
+// the booking window. RunJobs calls it well inside the day of margin
+// seriesHorizon leaves, so the slots are taken before anyone else is
+// offered them. Each series is extended in its own transaction; one that
+// fails is retried on the next run.
+func (h *Handler) extendBookingSeries(ctx context.Context) error {
+	queries := db.New(h.db)
+	now := time.Now()
+	until := seriesHorizon(now)
+	due, err := queries.ListSeriesToExtend(ctx, until.UTC())
+	if err != nil {
+		return err
+	}
+	for _, series := range due {
+		tx, err := h.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		booked, err := bookSeriesOccurrences(ctx, queries.WithTx(tx), series, until, now)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("series %d: %w", series.ID, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		publishSlotChange("booked", booked...)
+	}
+	return nil
+}
+
+// cancelSeriesBookings cancels the series' bookings that are still to
+// happen, from the occurrence scheduled at from onwards, and returns the
+// slots freed.
+func (h *Handler) cancelSeriesBookings(ctx context.Context, queries *db.Queries, seriesID int64, from time.Time, now time.Time) ([]time.Time, error) {
+	bookings, err := queries.ListSeriesBookingsFrom(ctx, db.ListSeriesBookingsFromParams{
+		SeriesID:    seriesID,
+		ScheduledAt: from.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var freed []time.Time
+	for _, booking := range bookings {
+		if !booking.RequestedStart.After(now) {
+			continue
+		}
+		if err := h.cancelBooking(ctx, queries, booking, now); err != nil {
+			return nil, err
+		}
+		freed = append(freed, booking.RequestedStart)
+	}
+	return freed, nil
+}
+
+// cancelBooking cancels a booking on the shop's side, keeping its notes,
+// and settles any deposit.
+func (h *Handler) cancelBooking(ctx context.Context, queries *db.Queries, booking db.Booking, now time.Time) error {
+	cancelled, err := queries.UpdateBookingStatus(ctx, db.UpdateBookingStatusParams{
+		Status:        sql.NullString{String: "cancelled", Valid: true},
+		InternalNotes: booking.InternalNotes,
+		ID:            booking.ID,
+	})
+	if err != nil {
+		return err
+	}
+	return h.settleDeposit(ctx, queries, cancelled, "cancelled", now)
+}
+
+func seriesListItem(series db.BookingSeries) pages.SeriesListItem {
+	start := seriesStart(series)
+	duration := time.Duration(series.DurationMinutes) * time.Minute
+	label, window := resolveSlotDetails(start, start.Add(duration))
+	item := pages.SeriesListItem{
+		ID:       series.ID,
+		Customer: series.CustomerName,
+		Email:    series.Email,
+		Schedule: seriesRule(series).Describe(start),
+		Slot:     label + " · " + window,
+		Service:  nullableString(series.ServiceInterest),
+		Active:   series.Status == "active",
+	}
+	if first, ok := seriesRule(series).First(start); ok {
+		item.Starts = first.Format("Jan 2, 2006")
+	}
+	return item
+}
+
+func seriesSlotOptions() []pages.SeriesSlotOption {
+	options := make([]pages.SeriesSlotOption, 0, len(bookingSlotDefinitions))
+	for _, slot := range bookingSlotDefinitions {
+		start := time.Date(2000, 1, 1, slot.StartHour, slot.StartMinute, 0, 0, bookingLocation)
+		options = append(options, pages.SeriesSlotOption{
+			ID:    slot.ID,
+			Label: slot.Label + " · " + slotWindowLabel(start, slot.Duration),
+		})
+	}
+	return options
+}
+
+func (h *Handler) AdminBookingSeriesList(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	rows, err := queries.ListBookingSeries(ctx, time.Now().UTC())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch repeat bookings")
+	}
+	packages, err := queries.GetAllPackages(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to load packages: %v", err)
+	}
+
+	data := pages.AdminBookingSeriesListData{
+		Slots:        seriesSlotOptions(),
+		Packages:     packages,
+		ErrorMessage: c.QueryParam("error"),
+		Form: pages.SeriesForm{
+			StartsOn: time.Now().In(bookingLocation).AddDate(0, 0, 1).Format("2006-01-02"),
+			Freq:     string(recurrence.Weekly),
+			Interval: "1",
+			Ends:     "never",
+		},
+	}
+	for _, row := range rows {
+		item := seriesListItem(db.BookingSeries{
+			ID:              row.ID,
+			CustomerName:    row.CustomerName,
+			Email:           row.Email,
+			ServiceInterest: row.ServiceInterest,
+			Rrule:           row.Rrule,
+			StartsAt:        row.StartsAt,
+			DurationMinutes: row.DurationMinutes,
+			Status:          row.Status,
+		})
+		item.Upcoming = row.Upcoming
+		item.Conflicts = row.Conflicts
+		data.Series = append(data.Series, item)
+	}
+
+	return pages.AdminBookingSeriesList(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) CreateBookingSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(c.FormValue("starts_on")), bookingLocation)
+	if err != nil {
+		return bookingSeriesListRedirect(c, "Choose the date of the first booking")
+	}
+	params, errMsg := parseBookingSeriesForm(c, day)
+	if errMsg != "" {
+		return bookingSeriesListRedirect(c, errMsg)
+	}
+	if !params.StartsAt.After(time.Now()) {
+		return bookingSeriesListRedirect(c, "The first booking must be in the future")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save repeat booking")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	series, err := qtx.CreateBookingSeries(ctx, params)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save repeat booking")
+	}
+	now := time.Now()
+	booked, err := bookSeriesOccurrences(ctx, qtx, series, seriesHorizon(now), now)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to book repeat booking")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save repeat booking")
+	}
+	publishSlotChange("booked", booked...)
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/bookings/series/%d", series.ID))
+}
+
+func (h *Handler) AdminBookingSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid series ID")
+	}
+	series, err := queries.GetBookingSeriesByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Repeat booking not found")
+	}
+	occurrences, err := queries.ListSeriesOccurrences(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch occurrences")
+	}
+	packages, err := queries.GetAllPackages(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to load packages: %v", err)
+	}
+
+	now := time.Now()
+	rule := seriesRule(series)
+	start := seriesStart(series)
+	data := pages.AdminBookingSeriesData{
+		Series:       seriesListItem(series),
+		Phone:        nullableString(series.Phone),
+		Vehicle:      nullableString(series.VehicleDetails),
+		Notes:        nullableString(series.Notes),
+		Rule:         series.Rrule,
+		PreviousID:   series.PreviousID.Int64,
+		Slots:        seriesSlotOptions(),
+		Packages:     packages,
+		ErrorMessage: c.QueryParam("error"),
+	}
+	if series.CancelledAt.Valid {
+		data.CancelledOn = invoiceDateLabel(series.CancelledAt)
+	}
+
+	var next time.Time
+	for _, o := range occurrences {
+		view := seriesOccurrenceView(o, now)
+		view.Editable = view.Editable && data.Series.Active
+		if view.Status == "conflict" && !view.Past {
+			data.Series.Conflicts++
+		}
+		if o.BookingID.Valid && o.Status == "booked" && !view.Past && (view.Status == "pending" || view.Status == "confirmed") {
+			data.Series.Upcoming++
+		}
+		if next.IsZero() && o.ScheduledAt.After(now) {
+			next = o.ScheduledAt.In(bookingLocation)
+		}
+		data.Occurrences = append(data.Occurrences, view)
+	}
+
+	// The edit form starts out as the series is now, with the times left
+	// counted from the next occurrence
+	form := pages.SeriesForm{
+		Name:     series.CustomerName,
+		Email:    series.Email,
+		Phone:    nullableString(series.Phone),
+		Vehicle:  nullableString(series.VehicleDetails),
+		Service:  nullableString(series.ServiceInterest),
+		Notes:    nullableString(series.Notes),
+		Freq:     string(rule.Freq),
+		Interval: strconv.Itoa(rule.Interval),
+		Ends:     "never",
+	}
+	if slot, ok := matchSlotDefinition(start); ok {
+		form.SlotID = slot.ID
+	}
+	for _, day := range rule.ByDay {
+		form.ByDay = append(form.ByDay, recurrence.DayCode(day))
+	}
+	switch {
+	case rule.Count > 0:
+		form.Ends = "count"
+		left := rule.Count
+		if !next.IsZero() {
+			left -= rule.CountBefore(start, next)
+		}
+		form.Count = strconv.Itoa(left)
+	case !rule.Until.IsZero():
+		form.Ends = "until"
+		form.Until = rule.Until.In(bookingLocation).Format("2006-01-02")
+	}
+	data.Form = form
+
+	return pages.AdminBookingSeries(data).Render(ctx, c.Response().Writer)
+}
+
+func seriesOccurrenceView(o db.ListSeriesOccurrencesRow, now time.Time) pages.SeriesOccurrenceView {
+	scheduled := o.ScheduledAt.In(bookingLocation)
+	view := pages.SeriesOccurrenceView{
+		ID:        o.ID,
+		Date:      scheduled.Format("Mon, Jan 2, 2006 · 3:04 PM"),
+		Status:    o.Status,
+		BookingID: o.BookingID.Int64,
+		Past:      !o.ScheduledAt.After(now),
+	}
+	if o.Status == "booked" {
+		view.Status = normalizeBookingStatus(o.BookingStatus.String)
+		if o.RequestedStart.Valid && !o.RequestedStart.Time.Equal(o.ScheduledAt) {
+			view.Moved = "Moved to " + o.RequestedStart.Time.In(bookingLocation).Format("Mon, Jan 2 · 3:04 PM")
+			view.Past = !o.RequestedStart.Time.After(now)
+		}
+	}
+	view.Editable = !view.Past && view.Status != "skipped" && view.Status != "completed"
+	return view
+}
+
+// MoveSeriesOccurrence changes the date or slot of one occurrence, leaving
+// the rest of the series alone. Moving a conflict books it.
+func (h *Handler) MoveSeriesOccurrence(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	series, occurrence, ok := loadSeriesOccurrence(c, queries)
+	if !ok {
+		return c.String(http.StatusNotFound, "Occurrence not found")
+	}
+	if series.Status != "active" {
+		return bookingSeriesRedirect(c, series.ID, "This repeat booking is cancelled")
+	}
+	if occurrence.Status == "skipped" {
+		return bookingSeriesRedirect(c, series.ID, "That date was skipped")
+	}
+
+	slot, ok := slotLookup[c.FormValue("slot_id")]
+	if !ok {
+		return bookingSeriesRedirect(c, series.ID, "Choose a slot")
+	}
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(c.FormValue("date")), bookingLocation)
+	if err != nil {
+		return bookingSeriesRedirect(c, series.ID, "Choose a date")
+	}
+	start := time.Date(day.Year(), day.Month(), day.Day(), slot.StartHour, slot.StartMinute, 0, 0, bookingLocation)
+	now := time.Now()
+	if !start.After(now) {
+		return bookingSeriesRedirect(c, series.ID, "Choose a time in the future")
+	}
+	if closedDays[start.Weekday()] {
+		return bookingSeriesRedirect(c, series.ID, "We're closed that day")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to move booking")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	var current db.Booking
+	if occurrence.BookingID.Valid {
+		current, err = qtx.GetBookingByID(ctx, occurrence.BookingID.Int64)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to move booking")
+		}
+	}
+	active := current.ID != 0 && (normalizeBookingStatus(current.Status.String) == "pending" || normalizeBookingStatus(current.Status.String) == "confirmed")
+	if active && current.RequestedStart.Equal(start.UTC()) {
+		return bookingSeriesRedirect(c, series.ID, "")
+	}
+	taken, err := slotTaken(ctx, qtx, start, now, "")
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to move booking")
+	}
+	if taken {
+		return bookingSeriesRedirect(c, series.ID, "That slot is already taken")
+	}
+
+	if active {
+		_, err = qtx.UpdateBookingTime(ctx, db.UpdateBookingTimeParams{
+			RequestedStart: start.UTC(),
+			RequestedEnd:   start.Add(slot.Duration).UTC(),
+			ID:             current.ID,
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to move booking")
+		}
+	} else {
+		booking, err := createSeriesBooking(ctx, qtx, series, start, slot.Duration)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to move booking")
+		}
+		err = qtx.UpdateSeriesOccurrence(ctx, db.UpdateSeriesOccurrenceParams{
+			BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+			Status:    "booked",
+			ID:        occurrence.ID,
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to move booking")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to move booking")
+	}
+	publishSlotChange("booked", start)
+	if active {
+		h.offerFreedSlots([]time.Time{current.RequestedStart})
+	}
+
+	return bookingSeriesRedirect(c, series.ID, "")
+}
+
+// SkipSeriesOccurrence leaves one date out of the series, cancelling its
+// booking.
+func (h *Handler) SkipSeriesOccurrence(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	series, occurrence, ok := loadSeriesOccurrence(c, queries)
+	if !ok {
+		return c.String(http.StatusNotFound, "Occurrence not found")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to skip date")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	now := time.Now()
+	var freed []time.Time
+	if occurrence.BookingID.Valid {
+		booking, err := qtx.GetBookingByID(ctx, occurrence.BookingID.Int64)
+		if err == nil && booking.RequestedStart.After(now) {
+			status := normalizeBookingStatus(booking.Status.String)
+			if status == "pending" || status == "confirmed" {
+				if err := h.cancelBooking(ctx, qtx, booking, now); err != nil {
+					return c.String(http.StatusInternalServerError, "Failed to skip date")
+				}
+				freed = append(freed, booking.RequestedStart)
+			}
+		}
+	}
+	err = qtx.UpdateSeriesOccurrence(ctx, db.UpdateSeriesOccurrenceParams{
+		BookingID: occurrence.BookingID,
+		Status:    "skipped",
+		ID:        occurrence.ID,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to skip date")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to skip date")
+	}
+	h.offerFreedSlots(freed)
+
+	return bookingSeriesRedirect(c, series.ID, "")
+}
+
+// UpdateBookingSeries changes the series from one occurrence onwards.
+// Bookings from that date are cancelled and remade on the new schedule.
+// Changing it from the first occurrence edits the series in place;
+// otherwise the series ends the day before and the rest continues as a
+// new series, so the dates already past keep the schedule they had.
+func (h *Handler) UpdateBookingSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid series ID")
+	}
+	series, err := queries.GetBookingSeriesByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Repeat booking not found")
+	}
+	if series.Status != "active" {
+		return bookingSeriesRedirect(c, id, "This repeat booking is cancelled")
+	}
+	occurrenceID, err := strconv.ParseInt(c.FormValue("from_occurrence"), 10, 64)
+	if err != nil {
+		return bookingSeriesRedirect(c, id, "Choose the date the change starts from")
+	}
+	occurrence, err := queries.GetSeriesOccurrence(ctx, db.GetSeriesOccurrenceParams{ID: occurrenceID, SeriesID: id})
+	if err != nil {
+		return bookingSeriesRedirect(c, id, "Choose the date the change starts from")
+	}
+	now := time.Now()
+	from := occurrence.ScheduledAt.In(bookingLocation)
+	if !from.After(now) {
+		return bookingSeriesRedirect(c, id, "That date has already passed")
+	}
+	params, errMsg := parseBookingSeriesForm(c, from)
+	if errMsg != "" {
+		return bookingSeriesRedirect(c, id, errMsg)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update repeat booking")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	freed, err := h.cancelSeriesBookings(ctx, qtx, id, from, now)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update repeat booking")
+	}
+	err = qtx.DeleteSeriesOccurrencesFrom(ctx, db.DeleteSeriesOccurrencesFromParams{SeriesID: id, ScheduledAt: from.UTC()})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update repeat booking")
+	}
+
+	rule := seriesRule(series)
+	target := series
+	if rule.CountBefore(seriesStart(series), from) == 0 {
+		err = qtx.UpdateBookingSeries(ctx, db.UpdateBookingSeriesParams{
+			CustomerName:    params.CustomerName,
+			Email:           params.Email,
+			Phone:           params.Phone,
+			VehicleDetails:  params.VehicleDetails,
+			ServiceInterest: params.ServiceInterest,
+			Notes:           params.Notes,
+			Rrule:           params.Rrule,
+			StartsAt:        params.StartsAt,
+			DurationMinutes: params.DurationMinutes,
+			BookedThrough:   params.BookedThrough,
+			ID:              id,
+		})
+		if err == nil {
+			target, err = qtx.GetBookingSeriesByID(ctx, id)
+		}
+	} else {
+		rule.Count = 0
+		rule.Until = from.Add(-time.Second)
+		err = qtx.SetSeriesRule(ctx, db.SetSeriesRuleParams{Rrule: rule.String(), ID: id})
+		if err == nil {
+			params.PreviousID = sql.NullInt64{Int64: id, Valid: true}
+			target, err = qtx.CreateBookingSeries(ctx, params)
+		}
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update repeat booking")
+	}
+	booked, err := bookSeriesOccurrences(ctx, qtx, target, seriesHorizon(now), now)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update repeat booking")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update repeat booking")
+	}
+	publishSlotChange("booked", booked...)
+	// Dates the new schedule didn't take back
+	h.offerFreedSlots(freed)
+
+	return bookingSeriesRedirect(c, target.ID, "")
+}
+
+// CancelBookingSeries ends the series and cancels the bookings still to
+// come. Past bookings are left as they are.
+func (h *Handler) CancelBookingSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid series ID")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to cancel repeat booking")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	now := time.Now()
+	cancelled, err := qtx.CancelBookingSeries(ctx, db.CancelBookingSeriesParams{
+		CancelledAt: sql.NullTime{Time: now.UTC(), Valid: true},
+		ID:          id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to cancel repeat booking")
+	}
+	if cancelled == 0 {
+		return bookingSeriesRedirect(c, id, "This repeat booking is already cancelled")
+	}
+	freed, err := h.cancelSeriesBookings(ctx, qtx, id, time.Time{}, now)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to cancel repeat booking")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to cancel repeat booking")
+	}
+	h.offerFreedSlots(freed)
+
+	return bookingSeriesRedirect(c, id, "")
+}
+
+// loadSeriesOccurrence loads the series and occurrence named in the path.
+func loadSeriesOccurrence(c echo.Context, queries *db.Queries) (db.BookingSeries, db.BookingSeriesOccurrence, bool) {
+	ctx := c.Request().Context()
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return db.BookingSeries{}, db.BookingSeriesOccurrence{}, false
+	}
+	occurrenceID, err := strconv.ParseInt(c.Param("occurrence"), 10, 64)
+	if err != nil {
+		return db.BookingSeries{}, db.BookingSeriesOccurrence{}, false
+	}
+	series, err := queries.GetBookingSeriesByID(ctx, id)
+	if err != nil {
+		return db.BookingSeries{}, db.BookingSeriesOccurrence{}, false
+	}
+	occurrence, err := queries.GetSeriesOccurrence(ctx, db.GetSeriesOccurrenceParams{ID: occurrenceID, SeriesID: id})
+	if err != nil {
+		return db.BookingSeries{}, db.BookingSeriesOccurrence{}, false
+	}
+	return series, occurrence, true
+}
+
+// parseBookingSeriesForm reads the customer, slot and schedule from the
+// form. The series starts on day, in the chosen slot. The message is
+// non-empty when the form can't be used.
+func parseBookingSeriesForm(c echo.Context, day time.Time) (db.CreateBookingSeriesParams, string) {
+	name := strings.TrimSpace(c.FormValue("customer_name"))
+	email := strings.ToLower(strings.TrimSpace(c.FormValue("email")))
+	if name == "" || !strings.Contains(email, "@") {
+		return db.CreateBookingSeriesParams{}, "Enter the customer's name and email"
+	}
+	slot, ok := slotLookup[c.FormValue("slot_id")]
+	if !ok {
+		return db.CreateBookingSeriesParams{}, "Choose a slot"
+	}
+	start := time.Date(day.Year(), day.Month(), day.Day(), slot.StartHour, slot.StartMinute, 0, 0, bookingLocation)
+
+	rule := recurrence.Rule{Freq: recurrence.Freq(c.FormValue("freq")), Interval: 1}
+	if raw := strings.TrimSpace(c.FormValue("interval")); raw != "" {
+		interval, err := strconv.Atoi(raw)
+		if err != nil {
+			return db.CreateBookingSeriesParams{}, seriesRuleErrorMessage(recurrence.ErrInterval)
+		}
+		rule.Interval = interval
+	}
+	if rule.Freq == recurrence.Weekly {
+		form, _ := c.FormParams()
+		for _, code := range form["byday"] {
+			if day, ok := recurrence.ParseDay(code); ok {
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		}
+	}
+	switch c.FormValue("ends") {
+	case "count":
+		count, err := strconv.Atoi(strings.TrimSpace(c.FormValue("count")))
+		if err != nil || count < 1 {
+			return db.CreateBookingSeriesParams{}, seriesRuleErrorMessage(recurrence.ErrCount)
+		}
+		rule.Count = count
+	case "until":
+		until, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(c.FormValue("until")), bookingLocation)
+		if err != nil {
+			return db.CreateBookingSeriesParams{}, "Choose the date of the last booking"
+		}
+		rule.Until = time.Date(until.Year(), until.Month(), until.Day(), 23, 59, 59, 0, bookingLocation)
+	}
+	if err := rule.Validate(); err != nil {
+		return db.CreateBookingSeriesParams{}, seriesRuleErrorMessage(err)
+	}
+	if _, ok := rule.First(start); !ok {
+		return db.CreateBookingSeriesParams{}, "That schedule ends before its first booking"
+	}
+
+	phone := strings.TrimSpace(c.FormValue("phone"))
+	vehicle := strings.TrimSpace(c.FormValue("vehicle"))
+	service := strings.TrimSpace(c.FormValue("service"))
+	notes := strings.TrimSpace(c.FormValue("notes"))
+	return db.CreateBookingSeriesParams{
+		CustomerName:    name,
+		Email:           email,
+		Phone:           sql.NullString{String: phone, Valid: phone != ""},
+		VehicleDetails:  sql.NullString{String: vehicle, Valid: vehicle != ""},
+		ServiceInterest: sql.NullString{String: service, Valid: service != ""},
+		Notes:           sql.NullString{String: notes, Valid: notes != ""},
+		Rrule:           rule.String(),
+		StartsAt:        start.UTC(),
+		DurationMinutes: int64(slot.Duration / time.Minute),
+		// Nothing is booked yet; the first occurrence is on or after start
+		BookedThrough: start.UTC(),
+	}, ""
+}
+
+// seriesRuleErrorMessage words the reasons a repeat rule is rejected for
+// the series form.
+func seriesRuleErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, recurrence.ErrFreq):
+		return "Repeat weekly or monthly"
+	case errors.Is(err, recurrence.ErrInterval):
+		return fmt.Sprintf("Repeat every 1 to %d weeks or months", recurrence.MaxInterval)
+	case errors.Is(err, recurrence.ErrCount):
+		return fmt.Sprintf("A series can run 1 to %d times", recurrence.MaxCount)
+	case errors.Is(err, recurrence.ErrByDay):
+		return "Only weekly series can pick days of the week"
+	default:
+		return "Invalid repeat rule"
+	}
+}
+
+func bookingSeriesListRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/bookings/series?error="+url.QueryEscape(errMsg))
+}
+
+func bookingSeriesRedirect(c echo.Context, id int64, errMsg string) error {
+	target := fmt.Sprintf("/admin/bookings/series/%d", id)
+	if errMsg != "" {
+		target += "?error=" + url.QueryEscape(errMsg)
+	}
+	return c.Redirect(http.StatusSeeOther, target)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,8 @@ import (
 
 	"detailingpass/pkg/auth"
 	"detailingpass/pkg/db"
+	"detailingpass/pkg/promo"
+	"detailingpass/web/templates/pages"
 
 	"github.com/labstack/echo/v4"
 )
@@ -19,7 +22,7 @@ import (
 const (
 	defaultBookingTimezone = "America/New_York"
 	defaultBookingHorizon  = 45
-	maxBookingHorizon      = 90
+	maxBookingHorizon      = 90 // for members in good standing
 	defaultBookingDays     = 30
 )
 
@@ -102,6 +105,8 @@ type slotAvailability struct {
 	StartISO  string `json:"start_iso"`
 	EndISO    string `json:"end_iso"`
 	Available bool   `json:"available"`
+	// MembersOnly marks a slot that's open, but only to members
+	MembersOnly bool `json:"members_only,omitempty"`
 }
 
 type bookingRequest struct {
@@ -113,11 +118,26 @@ type bookingRequest struct {
 	Notes   string `json:"notes"`
 	Date    string `json:"date"`
 	SlotID  string `json:"slot_id"`
+	// PromoCode is optional; an invalid code rejects the request so the
+	// customer can fix it
+	PromoCode string `json:"promo_code"`
+	// UseMembership books one of the signed-in member's included
+	// services instead of taking a deposit
+	UseMembership bool `json:"use_membership"`
+	// HoldToken is the hold placed when the slot was picked, if any
+	HoldToken string `json:"hold_token"`
+	// Mobile asks us to come to Address instead of the customer coming
+	// to the shop
+	Mobile  bool   `json:"mobile"`
+	Address string `json:"address"`
+	ZIP     string `json:"zip"`
 }
 
 type bookingResponse struct {
 	Message string                 `json:"message"`
 	Booking map[string]interface{} `json:"booking"`
+	// PaymentURL is where to pay the deposit, when it's taken online
+	PaymentURL string `json:"payment_url,omitempty"`
 }
 
 func loadBookingLocation() *time.Location {
@@ -179,22 +199,25 @@ func (h *Handler) BookingAvailability(c echo.Context) error {
 	}
 
 	endExclusive := start.AddDate(0, 0, daysRequested)
-	blocked, err := queries.ListBlockedSlots(ctx, db.ListBlockedSlotsParams{
-		RequestedStart:   start.UTC(),
-		RequestedStart_2: endExclusive.UTC(),
-	})
+	// The visitor's own hold stays available to them
+	holdToken := strings.TrimSpace(c.QueryParam("hold"))
+	// Slots are shown for the service the visitor has picked, where they
+	// want it done
+	skills, err := serviceSkills(ctx, queries, c.QueryParam("service"))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Unable to load availability",
 		})
 	}
-
-	blockedMap := make(map[string]struct{}, len(blocked))
-	for _, slot := range blocked {
-		blockedMap[slotKey(slot.RequestedStart)] = struct{}{}
+	needs := jobNeeds{skills: skills, stop: h.visitorStop(strings.TrimSpace(c.QueryParam("zip")))}
+	blockedMap, err := h.blockedSlotKeys(ctx, queries, start, endExclusive, time.Now(), holdToken, needs)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Unable to load availability",
+		})
 	}
 
-	days := buildAvailabilityDays(start, endExclusive, blockedMap)
+	days := buildAvailabilityDays(start, endExclusive, blockedMap, bookingHorizon(ctx, queries))
 	resp := availabilityResponse{
 		GeneratedAt: time.Now().In(bookingLocation),
 		Range: availabilityRange{
@@ -218,52 +241,131 @@ func (h *Handler) CreateBookingRequest(c echo.Context) error {
 	req.Name = strings.TrimSpace(req.Name)
 	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
 	req.Phone = strings.TrimSpace(req.Phone)
+	req.Address = strings.TrimSpace(req.Address)
 
 	if req.Name == "" || req.Email == "" || req.Date == "" || req.SlotID == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name, email, date, and slot are required"})
 	}
 
-	slotDef, ok := slotLookup[req.SlotID]
-	if !ok {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid slot selection"})
+	slotDef, slotStartLocal, errMsg := parseBookingSlot(req.Date, req.SlotID)
+	if errMsg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
 	}
 
-	day, err := time.ParseInLocation("2006-01-02", req.Date, bookingLocation)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid date format"})
-	}
+	slotStartUTC := slotStartLocal.UTC()
+	slotEndUTC := slotStartUTC.Add(slotDef.Duration)
 
-	slotStartLocal := time.Date(day.Year(), day.Month(), day.Day(), slotDef.StartHour, slotDef.StartMinute, 0, 0, bookingLocation)
-	if slotStartLocal.Before(time.Now().In(bookingLocation)) {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Selected slot is no longer in the future"})
-	}
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
 
-	if closedDays[slotStartLocal.Weekday()] {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "We are closed on the selected date"})
+	member, isMember := currentMembership(ctx, queries)
+	horizon := defaultBookingHorizon
+	if isMember && membershipRules(member).InGoodStanding(time.Now()) {
+		horizon = maxBookingHorizon
 	}
-
-	if slotStartLocal.After(time.Now().In(bookingLocation).AddDate(0, 0, maxBookingHorizon)) {
+	if slotStartLocal.After(time.Now().In(bookingLocation).AddDate(0, 0, horizon)) {
+		if horizon < maxBookingHorizon && !slotStartLocal.After(time.Now().In(bookingLocation).AddDate(0, 0, maxBookingHorizon)) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Only members can book this far ahead."})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Selected slot is outside our booking window"})
 	}
+	if req.UseMembership {
+		if !isMember {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Sign in with the email you joined with to book with your membership."})
+		}
+		if strings.TrimSpace(req.PromoCode) != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Promo codes can't be used on a membership booking."})
+		}
+	}
 
-	slotStartUTC := slotStartLocal.UTC()
-	slotEndUTC := slotStartUTC.Add(slotDef.Duration)
+	trip, err := h.mobileTrip(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
 
-	ctx := c.Request().Context()
-	queries := db.New(h.db)
+	// Get Clerk user ID from session if logged in
+	clerkUserID := auth.GetUserID(ctx)
+
+	// The booking and its deposit are saved together so a request never
+	// exists without the deposit it owes, and in the same transaction as
+	// the check that the slot is free, so two requests can't both take its
+	// last place. One kept waiting too long for the others has most likely
+	// lost it to them.
+	tx, err := h.db.BeginTx(ctx, nil)
+	if db.IsBusy(err) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error":    "That time has just been taken. Choose a different slot, or join the waitlist and we'll email you if it opens up.",
+			"waitlist": "true",
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+	}
+	defer tx.Rollback()
+	txQueries := queries.WithTx(tx)
 
-	conflictCount, err := queries.CountBlockedSlotsAt(ctx, slotStartUTC)
+	taken, err := slotTaken(ctx, txQueries, slotStartUTC, time.Now(), req.HoldToken)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+	}
+	if taken {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error":    "That time has just been taken. Choose a different slot, or join the waitlist and we'll email you if it opens up.",
+			"waitlist": "true",
+		})
+	}
+	skills, err := serviceSkills(ctx, txQueries, req.Service)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+	}
+	unfit, err := h.slotTakenFor(ctx, txQueries, slotStartUTC, slotEndUTC, time.Now(), req.HoldToken, jobNeeds{skills: skills, stop: trip.Stop})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
 	}
-	if conflictCount > 0 {
-		return c.JSON(http.StatusConflict, map[string]string{"error": "That time has just been taken. Choose a different slot."})
+	if unfit {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "We can't fit that service in then: the technicians who do it are busy, or can't get to you in time between other jobs. Choose a different slot."})
 	}
 
-	// Get Clerk user ID from session if logged in
-	clerkUserID := auth.GetUserID(ctx)
+	// Entitlements are checked inside the transaction so two bookings
+	// can't both take the last service of a period
+	var servicesLeft int64
+	if req.UseMembership {
+		var packageID int64
+		if pkg, err := txQueries.GetPackageBySlug(ctx, strings.TrimSpace(req.Service)); err == nil {
+			packageID = pkg.ID
+		}
+		used, err := membershipUsage(ctx, txQueries, member, slotStartLocal)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+		}
+		if err := membershipRules(member).Check(slotStartLocal, packageID, used); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": membershipErrorMessage(err)})
+		}
+		servicesLeft = membershipRules(member).Remaining(used + 1)
+	}
+
+	// Checked inside the transaction so two bookings can't both take the
+	// last use of a code
+	var promoRow db.PromoCode
+	var promoCode promo.Code
+	if strings.TrimSpace(req.PromoCode) != "" {
+		// Booked work is priced on the day, so the starting price is what
+		// counts toward a minimum spend
+		var order promo.Order
+		if pkg, err := txQueries.GetPackageBySlug(ctx, strings.TrimSpace(req.Service)); err == nil {
+			order.PackageIDs = append(order.PackageIDs, pkg.ID)
+			order.Subtotal = pkg.PriceMin.Int64
+		}
+		promoRow, promoCode, err = redeemablePromo(ctx, txQueries, req.PromoCode, req.Email, order, time.Now())
+		if msg := promoErrorMessage(err); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+		}
+	}
 
-	booking, err := queries.CreateBooking(ctx, db.CreateBookingParams{
+	booking, err := txQueries.CreateBooking(ctx, db.CreateBookingParams{
 		CustomerName: req.Name,
 		Email:        req.Email,
 		Phone: sql.NullString{
@@ -301,6 +403,68 @@ func (h *Handler) CreateBookingRequest(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
 	}
 
+	if req.HoldToken != "" {
+		converted, err := txQueries.ConvertSlotHold(ctx, db.ConvertSlotHoldParams{
+			BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+			Token:     req.HoldToken,
+			SlotStart: slotStartUTC,
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+		}
+		// The hold was released, used or swept away after lapsing
+		if converted == 0 {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "We're no longer holding that time for you. Choose the slot again."})
+		}
+	}
+
+	if req.Mobile {
+		if err := txQueries.SetBookingServiceLocation(ctx, serviceLocationParams(booking.ID, req.Address, trip)); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+		}
+	}
+
+	if promoRow.ID != 0 {
+		if err := redeemPromoForBooking(ctx, txQueries, promoRow, booking, sql.NullInt64{}); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+		}
+	}
+
+	if req.UseMembership {
+		err := txQueries.SetBookingMembership(ctx, db.SetBookingMembershipParams{
+			MembershipID: sql.NullInt64{Int64: member.ID, Valid: true},
+			ID:           booking.ID,
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+		}
+	}
+
+	// Included services are already paid for, so members don't owe a
+	// deposit on them
+	var deposit db.Payment
+	if h.bookingDeposit > 0 && !req.UseMembership {
+		deposit, err = txQueries.CreatePayment(ctx, db.CreatePaymentParams{
+			BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+			Kind:      "deposit",
+			Provider:  h.payments.Name(),
+			Amount:    h.bookingDeposit,
+			Currency:  "usd",
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+		}
+	}
+	if err := tx.Commit(); db.IsBusy(err) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error":    "That time has just been taken. Choose a different slot, or join the waitlist and we'll email you if it opens up.",
+			"waitlist": "true",
+		})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to save booking"})
+	}
+	publishSlotChange("booked", slotStartUTC)
+
 	resp := bookingResponse{
 		Message: "Booking request received. We'll confirm shortly.",
 		Booking: map[string]interface{}{
@@ -314,12 +478,89 @@ func (h *Handler) CreateBookingRequest(c echo.Context) error {
 		},
 	}
 
+	if deposit.ID != 0 {
+		paymentURL, err := h.startDepositCheckout(ctx, queries, booking, deposit)
+		if err != nil {
+			c.Logger().Warnf("Failed to start deposit checkout for booking %d: %v", booking.ID, err)
+			if err := abandonDeposit(ctx, queries, deposit, "void"); err != nil {
+				c.Logger().Warnf("Failed to cancel booking %d: %v", booking.ID, err)
+			} else {
+				publishSlotChange("freed", slotStartUTC)
+			}
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": "We couldn't reach our payment provider. Please try again in a moment."})
+		}
+		resp.Booking["deposit"] = deposit.Amount
+		resp.PaymentURL = paymentURL
+		if paymentURL != "" {
+			resp.Message = fmt.Sprintf("Booking request received. Taking you to pay the %s deposit…", pages.FormatMoney(deposit.Amount))
+		} else {
+			resp.Message = fmt.Sprintf("Booking request received. We'll be in touch to take the %s deposit, then confirm your appointment.", pages.FormatMoney(deposit.Amount))
+		}
+	}
+
+	if req.UseMembership {
+		resp.Booking["membership"] = member.PlanName
+		resp.Booking["services_left"] = servicesLeft
+		resp.Message += fmt.Sprintf(" It's included in your %s membership; you have %d left that period.", member.PlanName, servicesLeft)
+	}
+
+	if req.Mobile {
+		resp.Booking["address"] = req.Address + " " + trip.ZIP
+		resp.Booking["travel_fee"] = trip.Fee
+		if trip.Fee > 0 {
+			resp.Message += fmt.Sprintf(" We'll come to you; a %s travel fee will be added to your bill.", pages.FormatMoney(trip.Fee))
+		}
+	}
+
+	if promoRow.ID != 0 {
+		resp.Booking["promo_code"] = promoRow.Code
+		resp.Message += fmt.Sprintf(" Promo code %s applied: %s.", promoRow.Code, promoCode.Label())
+	}
+
 	return c.JSON(http.StatusCreated, resp)
 }
 
-func buildAvailabilityDays(start, endExclusive time.Time, blocked map[string]struct{}) []availabilityDay {
+// parseBookingSlot reads the date and slot picked on the booking page,
+// explaining why they can't be booked when they can't.
+func parseBookingSlot(date, slotID string) (slotDefinition, time.Time, string) {
+	slotDef, ok := slotLookup[slotID]
+	if !ok {
+		return slotDefinition{}, time.Time{}, "Invalid slot selection"
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", date, bookingLocation)
+	if err != nil {
+		return slotDefinition{}, time.Time{}, "Invalid date format"
+	}
+
+	slotStartLocal := time.Date(day.Year(), day.Month(), day.Day(), slotDef.StartHour, slotDef.StartMinute, 0, 0, bookingLocation)
+	if slotStartLocal.Before(time.Now().In(bookingLocation)) {
+		return slotDefinition{}, time.Time{}, "Selected slot is no longer in the future"
+	}
+
+	if closedDays[slotStartLocal.Weekday()] {
+		return slotDefinition{}, time.Time{}, "We are closed on the selected date"
+	}
+	return slotDef, slotStartLocal, ""
+}
+
+// bookingHorizon is how many days ahead the visitor can book: further for
+// members in good standing.
+func bookingHorizon(ctx context.Context, queries *db.Queries) int {
+	if row, ok := currentMembership(ctx, queries); ok && membershipRules(row).InGoodStanding(time.Now()) {
+		return maxBookingHorizon
+	}
+	return defaultBookingHorizon
+}
+
+// buildAvailabilityDays lays out the slots from start. Slots more than
+// horizon days out can't be booked; those a member could book are marked
+// members only.
+func buildAvailabilityDays(start, endExclusive time.Time, blocked map[string]struct{}, horizon int) []availabilityDay {
 	var days []availabilityDay
 	now := time.Now().In(bookingLocation)
+	bookableUntil := now.AddDate(0, 0, horizon)
+	membersUntil := now.AddDate(0, 0, maxBookingHorizon)
 	currentDay := start
 
 	for currentDay.Before(endExclusive) {
@@ -338,15 +579,21 @@ func buildAvailabilityDays(start, endExclusive time.Time, blocked map[string]str
 				if _, exists := blocked[slotKey(slotStartUTC)]; exists {
 					available = false
 				}
+				membersOnly := false
+				if slotStartLocal.After(bookableUntil) {
+					membersOnly = available && !slotStartLocal.After(membersUntil)
+					available = false
+				}
 
 				windowLabel := slotWindowLabel(slotStartLocal, slot.Duration)
 				slots = append(slots, slotAvailability{
-					ID:        slot.ID,
-					Label:     slot.Label,
-					Window:    windowLabel,
-					StartISO:  slotStartLocal.Format(time.RFC3339),
-					EndISO:    slotEndLocal.Format(time.RFC3339),
-					Available: available,
+					ID:          slot.ID,
+					Label:       slot.Label,
+					Window:      windowLabel,
+					StartISO:    slotStartLocal.Format(time.RFC3339),
+					EndISO:      slotEndLocal.Format(time.RFC3339),
+					Available:   available,
+					MembersOnly: membersOnly,
 				})
 
 				if available {
@@ -388,6 +635,52 @@ func slotKey(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
 }
 
+// slotTaken reports whether the slot starting at start can't take
+// another job: it's booked, held for a waitlisted customer or held by a
+// visitor other than the one holding holdToken, up to what the staff
+// working then can do. holdToken is empty for the shop.
+func slotTaken(ctx context.Context, queries *db.Queries, start time.Time, now time.Time, holdToken string) (bool, error) {
+	from, to := slotDay(start)
+	book, err := loadSlotBook(ctx, queries, from, to, now, holdToken)
+	if err != nil {
+		return false, err
+	}
+	return book.full(start, start.Add(slotLength(start))), nil
+}
+
+// slotTakenFor is slotTaken for a job from start to end with needs: it
+// also counts only the technicians with the skills, and the drive to and
+// from the job's neighbours.
+func (h *Handler) slotTakenFor(ctx context.Context, queries *db.Queries, start, end time.Time, now time.Time, holdToken string, needs jobNeeds) (bool, error) {
+	from, to := slotDay(start)
+	book, err := loadSlotBook(ctx, queries, from, to, now, holdToken)
+	if err != nil {
+		return false, err
+	}
+	book.area = h.serviceArea
+	return !book.open(start, end, needs), nil
+}
+
+// blockedSlotKeys returns the slots starting in [from, to) that a job
+// with needs can't be booked in, keyed by slotKey.
+func (h *Handler) blockedSlotKeys(ctx context.Context, queries *db.Queries, from, to time.Time, now time.Time, holdToken string, needs jobNeeds) (map[string]struct{}, error) {
+	book, err := loadSlotBook(ctx, queries, from, to, now, holdToken)
+	if err != nil {
+		return nil, err
+	}
+	book.area = h.serviceArea
+	keys := make(map[string]struct{})
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, slot := range bookingSlotDefinitions {
+			start := time.Date(day.Year(), day.Month(), day.Day(), slot.StartHour, slot.StartMinute, 0, 0, bookingLocation)
+			if !book.open(start, start.Add(slot.Duration), needs) {
+				keys[slotKey(start)] = struct{}{}
+			}
+		}
+	}
+	return keys, nil
+}
+
 func matchSlotDefinition(start time.Time) (slotDefinition, bool) {
 	startLocal := start.In(bookingLocation)
 	for _, slot := range bookingSlotDefinitions {
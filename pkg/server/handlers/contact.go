@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	contactFormPurpose = "contact-form"
+	// Bots submit the form as soon as it loads; people take longer than this
+	contactMinFillTime = 3 * time.Second
+	contactFormTTL     = 24 * time.Hour
+	// At most contactRateLimit messages per address per contactRateWindow
+	contactRateLimit        = 5
+	contactRateWindow       = time.Hour
+	contactMessageMaxLength = 5000
+)
+
+type contactRequest struct {
+	Name    string `json:"name" form:"name"`
+	Email   string `json:"email" form:"email"`
+	Phone   string `json:"phone" form:"phone"`
+	Service string `json:"service" form:"service"`
+	Message string `json:"message" form:"message"`
+	Website string `json:"website" form:"website"` // honeypot, hidden from people
+	Token   string `json:"form_token" form:"form_token"`
+}
+
+func (h *Handler) Contact(c echo.Context) error {
+	ctx := c.Request().Context()
+	data := pages.ContactData{
+		Packages: contactPackages(c, db.New(h.db)),
+		Token:    h.contactFormToken(),
+		Sent:     c.QueryParam("sent") != "",
+	}
+	return pages.Contact(data).Render(ctx, c.Response().Writer)
+}
+
+// SubmitContact stores a contact form message for the admin inbox. The
+// form posts JSON from main.js, or a plain form without JavaScript.
+// Submissions caught by the honeypot or timing trap get the normal success
+// response so bots have nothing to learn from, but are not stored.
+func (h *Handler) SubmitContact(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	var req contactRequest
+	if err := c.Bind(&req); err != nil {
+		return h.contactError(c, queries, req, http.StatusBadRequest, "Invalid request")
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
+	req.Phone = strings.TrimSpace(req.Phone)
+	req.Service = strings.TrimSpace(req.Service)
+	req.Message = strings.TrimSpace(req.Message)
+
+	if req.Website != "" {
+		c.Logger().Infof("Discarded contact message from %s: honeypot filled", c.RealIP())
+		return contactSent(c)
+	}
+	// Without LINK_SIGNING_KEY the form can't be stamped, so only the
+	// honeypot catches bots
+	if h.links != nil {
+		rendered, err := h.links.Verify(contactFormPurpose, req.Token, time.Now())
+		if errors.Is(err, linksign.ErrExpired) {
+			return h.contactError(c, queries, req, http.StatusBadRequest, "This form has been open a while. Please send your message again.")
+		}
+		if err != nil || time.Since(time.Unix(rendered, 0)) < contactMinFillTime {
+			c.Logger().Infof("Discarded contact message from %s: failed timing check", c.RealIP())
+			return contactSent(c)
+		}
+	}
+
+	if req.Name == "" || req.Email == "" || req.Message == "" {
+		return h.contactError(c, queries, req, http.StatusBadRequest, "Name, email, and message are required")
+	}
+	if !strings.Contains(req.Email, "@") || strings.ContainsAny(req.Email, " \r\n") {
+		return h.contactError(c, queries, req, http.StatusBadRequest, "Enter a valid email address")
+	}
+	if utf8.RuneCountInString(req.Message) > contactMessageMaxLength {
+		return h.contactError(c, queries, req, http.StatusBadRequest, fmt.Sprintf("Keep your message under %d characters", contactMessageMaxLength))
+	}
+
+	ip := c.RealIP()
+	recent, err := queries.CountRecentContactMessages(ctx, db.CountRecentContactMessagesParams{
+		IpAddress: sql.NullString{String: ip, Valid: true},
+		CreatedAt: sql.NullTime{Time: time.Now().UTC().Add(-contactRateWindow), Valid: true},
+	})
+	if err != nil {
+		return h.contactError(c, queries, req, http.StatusInternalServerError, "Unable to send your message")
+	}
+	if recent >= contactRateLimit {
+		return h.contactError(c, queries, req, http.StatusTooManyRequests, "You've sent several messages recently. Please call us or try again later.")
+	}
+
+	msg, err := queries.CreateContactMessage(ctx, db.CreateContactMessageParams{
+		Name:            req.Name,
+		Email:           req.Email,
+		Phone:           sql.NullString{String: req.Phone, Valid: req.Phone != ""},
+		ServiceInterest: sql.NullString{String: req.Service, Valid: req.Service != ""},
+		Message:         req.Message,
+		IpAddress:       sql.NullString{String: ip, Valid: ip != ""},
+	})
+	if err != nil {
+		return h.contactError(c, queries, req, http.StatusInternalServerError, "Unable to send your message")
+	}
+
+	if h.contactEmail != "" {
+		err := h.mailer.Send(ctx, mailer.Message{
+			To:      []string{h.contactEmail},
+			ReplyTo: msg.Email,
+			Subject: "New message from " + msg.Name,
+			Text: fmt.Sprintf("%s\n\nFrom: %s <%s>\n\nReply from the inbox: %s/admin/messages/%d\n",
+				msg.Message, msg.Name, msg.Email, h.siteURL, msg.ID),
+		})
+		if err != nil {
+			c.Logger().Warnf("Failed to send contact notification for message %d: %v", msg.ID, err)
+		}
+	}
+
+	return contactSent(c)
+}
+
+func contactSent(c echo.Context) error {
+	if isJSONRequest(c) {
+		return c.JSON(http.StatusCreated, map[string]string{
+			"message": "Thanks for reaching out. We'll get back to you shortly.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, "/contact?sent=1")
+}
+
+// contactError reports a problem with a submission, re-rendering the form
+// with what was entered when it wasn't posted by main.js.
+func (h *Handler) contactError(c echo.Context, queries *db.Queries, req contactRequest, status int, msg string) error {
+	if isJSONRequest(c) {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+	data := pages.ContactData{
+		Packages: contactPackages(c, queries),
+		Token:    h.contactFormToken(),
+		Form: pages.ContactFormValues{
+			Name:    req.Name,
+			Email:   req.Email,
+			Phone:   req.Phone,
+			Service: req.Service,
+			Message: req.Message,
+		},
+		ErrorMessage: msg,
+	}
+	c.Response().WriteHeader(status)
+	return pages.Contact(data).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// contactFormToken stamps the form with the time it was rendered, signed so
+// the timing trap can't be skipped by sending an old timestamp.
+func (h *Handler) contactFormToken() string {
+	if h.links == nil {
+		return ""
+	}
+	now := time.Now()
+	return h.links.Sign(contactFormPurpose, now.Unix(), now.Add(contactFormTTL))
+}
+
+func contactPackages(c echo.Context, queries *db.Queries) []db.Package {
+	packages, err := queries.GetAllPackages(c.Request().Context())
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch packages: %v", err)
+	}
+	return packages
+}
+
+func isJSONRequest(c echo.Context) bool {
+	return strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/linksign"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestSubmitContact(t *testing.T) {
+	links := linksign.New([]byte("test"))
+	// signedAt is a form token for a form rendered at the given time
+	signedAt := func(rendered time.Time) string {
+		return links.Sign(contactFormPurpose, rendered.Unix(), rendered.Add(contactFormTTL))
+	}
+	filled := func(overrides map[string]string) url.Values {
+		form := url.Values{
+			"name":       {"Dana Whitfield"},
+			"email":      {"Dana@Example.com "},
+			"message":    {"Can you do a ceramic coating on a Tacoma?"},
+			"form_token": {signedAt(time.Now().Add(-time.Minute))},
+		}
+		for k, v := range overrides {
+			form.Set(k, v)
+		}
+		return form
+	}
+
+	tests := []struct {
+		name       string
+		form       url.Values
+		prior      int  // messages already sent from this address
+		noKey      bool // LINK_SIGNING_KEY isn't set
+		wantStatus int
+		wantStored bool
+	}{
+		{name: "valid", form: filled(nil), wantStatus: http.StatusSeeOther, wantStored: true},
+		// Caught bots are told the message was sent
+		{name: "honeypot", form: filled(map[string]string{"website": "http://spam.example"}), wantStatus: http.StatusSeeOther},
+		{name: "submitted too fast", form: filled(map[string]string{"form_token": signedAt(time.Now())}), wantStatus: http.StatusSeeOther},
+		{name: "forged token", form: filled(map[string]string{"form_token": "1.2.3"}), wantStatus: http.StatusSeeOther},
+		{name: "no token", form: filled(map[string]string{"form_token": ""}), wantStatus: http.StatusSeeOther},
+		{name: "expired form", form: filled(map[string]string{"form_token": signedAt(time.Now().Add(-contactFormTTL - time.Minute))}), wantStatus: http.StatusBadRequest},
+		{name: "missing message", form: filled(map[string]string{"message": "  "}), wantStatus: http.StatusBadRequest},
+		{name: "bad email", form: filled(map[string]string{"email": "dana"}), wantStatus: http.StatusBadRequest},
+		{name: "message too long", form: filled(map[string]string{"message": strings.Repeat("é", contactMessageMaxLength+1)}), wantStatus: http.StatusBadRequest},
+		{name: "under the rate limit", form: filled(nil), prior: contactRateLimit - 1, wantStatus: http.StatusSeeOther, wantStored: true},
+		{name: "rate limited", form: filled(nil), prior: contactRateLimit, wantStatus: http.StatusTooManyRequests},
+		// Without the key there's no timing check, only the honeypot
+		{name: "no key", form: filled(map[string]string{"form_token": ""}), noKey: true, wantStatus: http.StatusSeeOther, wantStored: true},
+		{name: "no key, honeypot", form: filled(map[string]string{"form_token": "", "website": "http://spam.example"}), noKey: true, wantStatus: http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := newTestDB(t)
+			queries := db.New(conn)
+			mail := &recordingMailer{}
+			h := &Handler{db: conn, links: links, mailer: mail, contactEmail: "shop@example.com", siteURL: "https://example.com"}
+			if tt.noKey {
+				h.links = nil
+			}
+
+			for i := 0; i < tt.prior; i++ {
+				_, err := queries.CreateContactMessage(context.Background(), db.CreateContactMessageParams{
+					Name:      "Earlier",
+					Email:     "earlier@example.com",
+					Message:   "Earlier message",
+					IpAddress: sql.NullString{String: "192.0.2.10", Valid: true},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/contact", strings.NewReader(tt.form.Encode()))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			req.Header.Set(echo.HeaderXRealIP, "192.0.2.10")
+			rec := httptest.NewRecorder()
+			if err := h.SubmitContact(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			var stored int
+			if err := conn.QueryRow(`SELECT COUNT(*) FROM contact_messages WHERE email = 'dana@example.com'`).Scan(&stored); err != nil {
+				t.Fatal(err)
+			}
+			if (stored == 1) != tt.wantStored || stored > 1 {
+				t.Errorf("stored %d messages, want stored = %v", stored, tt.wantStored)
+			}
+			sent := mail.messages()
+			if tt.wantStored {
+				if len(sent) != 1 || sent[0].To[0] != "shop@example.com" || sent[0].ReplyTo != "dana@example.com" {
+					t.Errorf("notification = %+v", sent)
+				}
+			} else if len(sent) != 0 {
+				t.Errorf("sent %d notifications for a message that wasn't stored", len(sent))
+			}
+		})
+	}
+}
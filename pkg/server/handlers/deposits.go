@@ -0,0 +1,453 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+	"detailingpass/pkg/payments"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	depositLinkPurpose = "deposit"
+	// Stripe requires checkouts to stay open at least 30 minutes. An
+	// abandoned checkout cancels its booking, so this is also how long an
+	// unpaid request holds its slot.
+	depositCheckoutTTL    = time.Hour
+	defaultRefundHours    = 48
+	maxWebhookPayloadSize = 64 << 10
+)
+
+func loadBookingDeposit() int64 {
+	raw := strings.TrimSpace(os.Getenv("BOOKING_DEPOSIT"))
+	cents, err := parseDollars(raw)
+	if err != nil {
+		log.Printf("⚠️  BOOKING_DEPOSIT %q is not a dollar amount; deposits are off", raw)
+		return 0
+	}
+	return cents
+}
+
+func loadDepositRefundWindow() time.Duration {
+	hours := defaultRefundHours
+	if raw := strings.TrimSpace(os.Getenv("DEPOSIT_REFUND_HOURS")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			hours = parsed
+		}
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// depositPolicy is shown wherever a customer is asked for a deposit.
+func (h *Handler) depositPolicy() string {
+	if h.bookingDeposit == 0 {
+		return ""
+	}
+	return fmt.Sprintf("A %s deposit secures your appointment and comes off your final bill. It's refunded in full if you cancel at least %d hours ahead; later cancellations and no-shows forfeit it.",
+		pages.FormatMoney(h.bookingDeposit), int(h.depositRefundWindow.Hours()))
+}
+
+// depositLink lets the customer check on or pay their deposit until the
+// appointment is over.
+func (h *Handler) depositLink(booking db.Booking) string {
+	return h.customerLink("/booking/deposit/", depositLinkPurpose, booking.ID, booking.RequestedEnd.Add(24*time.Hour))
+}
+
+// startDepositCheckout opens a checkout for a pending deposit and returns
+// where to send the customer to pay. The URL is empty when the provider
+// takes payment in person, or when customer links are off and there's no
+// deposit page to come back to.
+func (h *Handler) startDepositCheckout(ctx context.Context, queries *db.Queries, booking db.Booking, deposit db.Payment) (string, error) {
+	link := h.depositLink(booking)
+	if link == "" {
+		return "", nil
+	}
+	expires := time.Now().Add(depositCheckoutTTL)
+	checkout, err := h.payments.CreateCheckout(ctx, payments.CheckoutRequest{
+		Reference:   fmt.Sprintf("deposit-%d-%d", deposit.ID, expires.Unix()),
+		Description: "Booking deposit – " + booking.RequestedStart.In(bookingLocation).Format("Mon, Jan 2 3:04 PM"),
+		Amount:      deposit.Amount,
+		Currency:    deposit.Currency,
+		Email:       booking.Email,
+		SuccessURL:  link + "?paid=1",
+		CancelURL:   link,
+		ExpiresAt:   expires,
+		Metadata: map[string]string{
+			"booking_id": strconv.FormatInt(booking.ID, 10),
+			"payment_id": strconv.FormatInt(deposit.ID, 10),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if checkout.ID != "" {
+		err = queries.SetPaymentCheckout(ctx, db.SetPaymentCheckoutParams{
+			CheckoutID: sql.NullString{String: checkout.ID, Valid: true},
+			ID:         deposit.ID,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return checkout.URL, nil
+}
+
+// abandonDeposit gives up on a deposit that couldn't be started or wasn't
+// paid, freeing the slot it was holding. A quote accepted with the booking
+// goes back to the customer to accept again.
+func abandonDeposit(ctx context.Context, queries *db.Queries, deposit db.Payment, status string) error {
+	if _, err := queries.CloseUnpaidPayment(ctx, db.CloseUnpaidPaymentParams{Status: status, ID: deposit.ID}); err != nil {
+		return err
+	}
+	if !deposit.BookingID.Valid {
+		return nil
+	}
+	cancelled, err := queries.CancelPendingBooking(ctx, deposit.BookingID.Int64)
+	if err != nil || cancelled == 0 {
+		return err
+	}
+	_, err = queries.ReopenQuoteForBooking(ctx, deposit.BookingID)
+	return err
+}
+
+// depositBlocksConfirmation explains why a booking can't be confirmed yet,
+// or returns "" when it can. Bookings without a deposit are never held up.
+func depositBlocksConfirmation(ctx context.Context, queries *db.Queries, bookingID int64) (string, error) {
+	deposit, err := queries.GetBookingDeposit(ctx, sql.NullInt64{Int64: bookingID, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if deposit.Status == "paid" {
+		return "", nil
+	}
+	return fmt.Sprintf("The %s deposit for this booking is %s. Record it as paid before confirming.",
+		pages.FormatMoney(deposit.Amount), depositStatusLabel(deposit.Status)), nil
+}
+
+// settleDeposit deals with the deposit when a booking is called off. A
+// declined booking is always refunded; a cancelled one only when it's
+// cancelled inside the refund window. Unpaid deposits are voided.
+func (h *Handler) settleDeposit(ctx context.Context, queries *db.Queries, booking db.Booking, status string, now time.Time) error {
+	deposit, err := queries.GetBookingDeposit(ctx, sql.NullInt64{Int64: booking.ID, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch deposit.Status {
+	case "pending":
+		_, err := queries.CloseUnpaidPayment(ctx, db.CloseUnpaidPaymentParams{Status: "void", ID: deposit.ID})
+		return err
+	case "paid":
+		if status == "cancelled" && booking.RequestedStart.Sub(now) < h.depositRefundWindow {
+			_, err := queries.SettlePayment(ctx, db.SettlePaymentParams{Status: "forfeited", ID: deposit.ID})
+			return err
+		}
+		return h.refundDeposit(ctx, queries, deposit)
+	}
+	return nil
+}
+
+// refundDeposit returns a paid deposit through the provider that took it.
+func (h *Handler) refundDeposit(ctx context.Context, queries *db.Queries, deposit db.Payment) error {
+	provider := h.providerFor(deposit.Provider)
+	refund, err := provider.Refund(ctx, payments.RefundRequest{
+		// A deposit is refunded once, in full
+		Reference:  fmt.Sprintf("refund-payment-%d", deposit.ID),
+		PaymentRef: deposit.PaymentRef.String,
+		Amount:     deposit.Amount,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = queries.SettlePayment(ctx, db.SettlePaymentParams{
+		Status:         "refunded",
+		RefundRef:      sql.NullString{String: refund.ID, Valid: refund.ID != ""},
+		RefundedAmount: refund.Amount,
+		ID:             deposit.ID,
+	})
+	return err
+}
+
+// providerFor returns the provider a payment was taken with. Payments
+// recorded by hand are refunded by hand whichever provider is configured.
+func (h *Handler) providerFor(name string) payments.PaymentProvider {
+	if name == h.payments.Name() {
+		return h.payments
+	}
+	return payments.Manual{}
+}
+
+func depositStatusLabel(status string) string {
+	switch status {
+	case "pending":
+		return "not paid yet"
+	case "void":
+		return "cancelled"
+	default:
+		return status
+	}
+}
+
+func (h *Handler) DepositPage(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	data := pages.DepositPageData{Token: c.Param("token"), State: pages.DepositLinkInvalid}
+	booking, deposit, state := h.resolveDepositToken(ctx, queries, c.Param("token"))
+	if state == pages.DepositLinkOpen {
+		h.fillDepositPage(&data, booking, deposit)
+		data.JustPaid = c.QueryParam("paid") != "" && deposit.Status == "pending"
+		data.Error = c.QueryParam("error")
+	}
+	data.State = state
+	return pages.DepositPage(data).Render(ctx, c.Response().Writer)
+}
+
+// PayDeposit sends the customer back to checkout, for when they closed the
+// payment page before finishing.
+func (h *Handler) PayDeposit(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	token := c.Param("token")
+	booking, deposit, state := h.resolveDepositToken(ctx, queries, token)
+	if state != pages.DepositLinkOpen {
+		return c.Redirect(http.StatusSeeOther, "/booking/deposit/"+token)
+	}
+	if deposit.Status != "pending" || normalizeBookingStatus(booking.Status.String) != "pending" {
+		return c.Redirect(http.StatusSeeOther, "/booking/deposit/"+token)
+	}
+
+	checkoutURL, err := h.startDepositCheckout(ctx, queries, booking, deposit)
+	if err != nil {
+		c.Logger().Warnf("Failed to start deposit checkout for booking %d: %v", booking.ID, err)
+		return c.Redirect(http.StatusSeeOther, "/booking/deposit/"+token+"?error="+
+			url.QueryEscape("We couldn't reach our payment provider. Please try again in a moment."))
+	}
+	if checkoutURL == "" {
+		return c.Redirect(http.StatusSeeOther, "/booking/deposit/"+token)
+	}
+	return c.Redirect(http.StatusSeeOther, checkoutURL)
+}
+
+func (h *Handler) resolveDepositToken(ctx context.Context, queries *db.Queries, token string) (db.Booking, db.Payment, string) {
+	id, err := h.links.Verify(depositLinkPurpose, token, time.Now())
+	if errors.Is(err, linksign.ErrExpired) {
+		return db.Booking{}, db.Payment{}, pages.DepositLinkExpired
+	}
+	if err != nil {
+		return db.Booking{}, db.Payment{}, pages.DepositLinkInvalid
+	}
+	booking, err := queries.GetBookingByID(ctx, id)
+	if err != nil {
+		return db.Booking{}, db.Payment{}, pages.DepositLinkInvalid
+	}
+	deposit, err := queries.GetBookingDeposit(ctx, sql.NullInt64{Int64: id, Valid: true})
+	if err != nil {
+		return db.Booking{}, db.Payment{}, pages.DepositLinkInvalid
+	}
+	return booking, deposit, pages.DepositLinkOpen
+}
+
+func (h *Handler) fillDepositPage(data *pages.DepositPageData, booking db.Booking, deposit db.Payment) {
+	slotLabel, slotWindow := resolveSlotDetails(booking.RequestedStart, booking.RequestedEnd)
+	data.FirstName = customerFirstName(booking.CustomerName)
+	data.Appointment = fmt.Sprintf("%s, %s (%s)", slotLabel, booking.RequestedStart.In(bookingLocation).Format("Monday, January 2"), slotWindow)
+	data.BookingStatus = normalizeBookingStatus(booking.Status.String)
+	data.Amount = deposit.Amount
+	data.Status = deposit.Status
+	data.RefundedAmount = deposit.RefundedAmount
+	data.PayOnline = h.payments.Name() != "manual"
+	data.Policy = h.depositPolicy()
+}
+
+// PaymentWebhook receives payment results from the provider. Each event is
+// handled once; the provider retries until it gets a 2xx.
+func (h *Handler) PaymentWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookPayloadSize))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unable to read payload"})
+	}
+	event, err := h.payments.ParseWebhook(payload, c.Request().Header)
+	if errors.Is(err, payments.ErrUnsupported) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Webhooks are not enabled"})
+	}
+	if err != nil {
+		c.Logger().Warnf("Rejected payment webhook: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid webhook"})
+	}
+	if event.Kind == "" || event.CheckoutID == "" {
+		return c.NoContent(http.StatusOK)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to process webhook"})
+	}
+	defer tx.Rollback()
+	queries := db.New(h.db).WithTx(tx)
+
+	recorded, err := queries.RecordPaymentEvent(ctx, db.RecordPaymentEventParams{
+		ID:   event.ID,
+		Type: sql.NullString{String: event.Kind, Valid: true},
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to process webhook"})
+	}
+	if recorded == 0 {
+		return c.NoContent(http.StatusOK)
+	}
+
+	payment, err := queries.GetPaymentByCheckoutID(ctx, sql.NullString{String: event.CheckoutID, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		// A checkout replaced by a newer one, or not ours
+		return c.NoContent(http.StatusOK)
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to process webhook"})
+	}
+
+	paid := false
+	var cert db.GiftCertificate
+	switch event.Kind {
+	case payments.EventPaid:
+		updated, err := queries.MarkPaymentPaid(ctx, db.MarkPaymentPaidParams{
+			Provider:   h.payments.Name(),
+			PaymentRef: sql.NullString{String: event.PaymentRef, Valid: event.PaymentRef != ""},
+			PaidAt:     sql.NullTime{Time: time.Now().UTC(), Valid: true},
+			ID:         payment.ID,
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to process webhook"})
+		}
+		paid = updated > 0
+		if paid && payment.Kind == giftCertificatePaymentKind {
+			if cert, paid, err = activateGiftCertificate(ctx, queries, payment); err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to process webhook"})
+			}
+		}
+	case payments.EventExpired, payments.EventFailed:
+		// An unpaid gift certificate stays pending, so a late payment
+		// can still activate it
+		if err := abandonDeposit(ctx, queries, payment, event.Kind); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to process webhook"})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to process webhook"})
+	}
+	switch {
+	case !paid && payment.BookingID.Valid && payment.Kind == "deposit":
+		// An unpaid request has given up its slot
+		if booking, err := db.New(h.db).GetBookingByID(ctx, payment.BookingID.Int64); err == nil {
+			h.offerFreedSlots([]time.Time{booking.RequestedStart})
+		}
+	case !paid:
+	case payment.Kind == giftCertificatePaymentKind:
+		h.deliverGiftCertificate(c, cert)
+	case payment.BookingID.Valid:
+		h.notifyDepositPaid(c, db.New(h.db), payment)
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// notifyDepositPaid tells the shop a booking is ready to confirm.
+func (h *Handler) notifyDepositPaid(c echo.Context, queries *db.Queries, deposit db.Payment) {
+	if h.contactEmail == "" {
+		return
+	}
+	ctx := c.Request().Context()
+	booking, err := queries.GetBookingByID(ctx, deposit.BookingID.Int64)
+	if err != nil {
+		return
+	}
+
+	note := ""
+	if status := normalizeBookingStatus(booking.Status.String); status != "pending" {
+		// Paid after the request was called off, e.g. a late bank debit
+		note = fmt.Sprintf("The booking is %s, so this deposit may need refunding.\n\n", status)
+	}
+	err = h.mailer.Send(ctx, mailer.Message{
+		To:      []string{h.contactEmail},
+		ReplyTo: booking.Email,
+		Subject: fmt.Sprintf("Deposit paid by %s", booking.CustomerName),
+		Text: fmt.Sprintf("%s paid the %s deposit for %s.\n\n%sReview the booking: %s/admin/bookings\n",
+			booking.CustomerName, pages.FormatMoney(deposit.Amount),
+			booking.RequestedStart.In(bookingLocation).Format("Monday, January 2 at 3:04 PM"), note, h.siteURL),
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to send deposit notification for booking %d: %v", booking.ID, err)
+	}
+}
+
+// MarkDepositPaid records a deposit taken in person.
+func (h *Handler) MarkDepositPaid(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid booking ID")
+	}
+	deposit, err := queries.GetBookingDeposit(ctx, sql.NullInt64{Int64: id, Valid: true})
+	if err != nil {
+		return c.String(http.StatusNotFound, "Deposit not found")
+	}
+	_, err = queries.MarkPaymentPaid(ctx, db.MarkPaymentPaidParams{
+		Provider: "manual",
+		PaidAt:   sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:       deposit.ID,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to record deposit")
+	}
+	return c.Redirect(http.StatusSeeOther, bookingsRedirect(c))
+}
+
+// RefundDeposit refunds a paid deposit in full whatever the policy says,
+// for goodwill or when an automatic refund failed.
+func (h *Handler) RefundDeposit(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid booking ID")
+	}
+	deposit, err := queries.GetBookingDeposit(ctx, sql.NullInt64{Int64: id, Valid: true})
+	if err != nil {
+		return c.String(http.StatusNotFound, "Deposit not found")
+	}
+	if deposit.Status != "paid" {
+		return c.String(http.StatusConflict, "Only paid deposits can be refunded")
+	}
+	if err := h.refundDeposit(ctx, queries, deposit); err != nil {
+		c.Logger().Warnf("Failed to refund deposit for booking %d: %v", id, err)
+		return c.String(http.StatusBadGateway, "The refund failed: "+err.Error())
+	}
+	return c.Redirect(http.StatusSeeOther, bookingsRedirect(c))
+}
@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"detailingpass/pkg/db"
+	"detailingpass/pkg/seo"
+	"detailingpass/web/templates"
 	"detailingpass/web/templates/pages"
 
 	"github.com/labstack/echo/v4"
@@ -38,60 +41,306 @@ func (h *Handler) Gallery(c echo.Context) error {
 	ctx := c.Request().Context()
 	queries := db.New(h.db)
 
-	// Get all gallery groups
-	groups, err := queries.ListGalleryGroups(ctx, db.ListGalleryGroupsParams{
-		Limit:  100,
-		Offset: 0,
-	})
+	filters := parseGalleryFilters(c)
+	params := galleryListParams(ctx, queries, filters)
+
+	if after, ok := decodeGalleryCursor(c.QueryParam("cursor")); ok {
+		params.AfterSortOrder = sql.NullInt64{Int64: after.SortOrder, Valid: true}
+		params.AfterID = after.ID
+	}
+
+	// Fetch one extra row to learn whether another page exists
+	params.Limit = galleryPageSize + 1
+	groups, err := queries.ListGalleryGroupsPage(ctx, params)
 	if err != nil {
 		c.Logger().Errorf("Failed to fetch gallery groups: %v", err)
 		groups = []db.GalleryGroup{}
 	}
 
-	// Build gallery items with images
-	var items []pages.GalleryItem
+	nextCursor := ""
+	if len(groups) > galleryPageSize {
+		groups = groups[:galleryPageSize]
+		last := groups[len(groups)-1]
+		nextCursor = encodeGalleryCursor(galleryCursor{SortOrder: last.SortOrder.Int64, ID: last.ID})
+	}
+
+	data := pages.GalleryPageData{
+		Items:   h.buildGalleryItems(c, queries, groups),
+		Filters: filters,
+		Facets:  loadGalleryFacets(c, queries, params),
+	}
+	if nextCursor != "" {
+		data.NextURL = galleryURL(filters, nextCursor)
+	}
+	if c.QueryParam("cursor") != "" {
+		data.FirstURL = galleryURL(filters, "")
+	}
+
+	return pages.Gallery(data).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// buildGalleryItems turns a page of groups into gallery items using two
+// queries for the whole page instead of one per group.
+func (h *Handler) buildGalleryItems(c echo.Context, queries *db.Queries, groups []db.GalleryGroup) []pages.GalleryItem {
+	if len(groups) == 0 {
+		return nil
+	}
+	ctx := c.Request().Context()
+
+	groupIDs := make([]sql.NullInt64, len(groups))
+	for i, g := range groups {
+		groupIDs[i] = sql.NullInt64{Int64: g.ID, Valid: true}
+	}
+	media, err := queries.ListMediaForGalleryGroups(ctx, groupIDs)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch gallery media: %v", err)
+	}
+
+	mediaByGroup := make(map[int64][]db.Medium)
+	var mediaIDs []int64
+	for _, m := range media {
+		mediaByGroup[m.GalleryGroupID.Int64] = append(mediaByGroup[m.GalleryGroupID.Int64], m)
+		// Private images are never shown, so their variants aren't needed
+		if !m.IsPrivate.Bool {
+			mediaIDs = append(mediaIDs, m.ID)
+		}
+	}
+	var variants []db.MediaVariant
+	if len(mediaIDs) > 0 {
+		variants, err = queries.ListMediaVariantsForMedia(ctx, mediaIDs)
+		if err != nil {
+			c.Logger().Warnf("Failed to fetch gallery media variants: %v", err)
+		}
+	}
+	srcSets := h.buildSrcSets(variants)
+
+	items := make([]pages.GalleryItem, 0, len(groups))
 	for _, g := range groups {
-		media, mediaErr := queries.GetMediaForGalleryGroup(ctx, sql.NullInt64{Int64: g.ID, Valid: true})
-		if mediaErr != nil {
-			c.Logger().Warnf("Failed to fetch media for gallery group %d: %v", g.ID, mediaErr)
+		items = append(items, h.newGalleryItem(g, mediaByGroup[g.ID], srcSets))
+	}
+	return items
+}
+
+// GalleryDetail renders a single project page at /gallery/:slug.
+func (h *Handler) GalleryDetail(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	group, err := queries.GetGalleryGroupBySlug(ctx, c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.String(http.StatusNotFound, "Project not found")
 		}
+		return c.String(http.StatusInternalServerError, "Failed to load project")
+	}
 
-		var images []pages.GalleryImage
-		heroImage := "/static/images/placeholder.jpg"
+	item := h.buildGalleryItem(c, queries, group)
+	pairs, rest := pairBeforeAfter(item.Images)
 
-		for _, m := range media {
-			img := pages.GalleryImage{
-				ID:      m.ID,
-				URL:     m.Url,
-				Kind:    m.Kind.String,
-				AltText: m.AltText.String,
-			}
-			images = append(images, img)
-			if m.Kind.String == "hero" && heroImage == "/static/images/placeholder.jpg" {
-				heroImage = m.Url
+	var pkg *db.Package
+	if group.PackageID.Valid {
+		if p, err := queries.GetPackageByID(ctx, group.PackageID.Int64); err == nil {
+			pkg = &p
+		}
+	}
+
+	relatedGroups, err := queries.ListRelatedGalleryGroups(ctx, db.ListRelatedGalleryGroupsParams{
+		ID:          group.ID,
+		PackageID:   group.PackageID,
+		VehicleMake: group.VehicleMake,
+		Limit:       3,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch related gallery groups for %d: %v", group.ID, err)
+	}
+	var related []pages.GalleryItem
+	for _, g := range relatedGroups {
+		related = append(related, h.buildGalleryItem(c, queries, g))
+	}
+
+	data := pages.GalleryDetailData{
+		Item:            item,
+		Pairs:           pairs,
+		Images:          rest,
+		Package:         pkg,
+		Related:         related,
+		MetaDescription: galleryMetaDescription(group, pkg),
+	}
+
+	path := "/gallery/" + group.Slug
+	var images []seo.Image
+	for _, img := range item.Images {
+		images = append(images, seo.Image{URL: img.URL, Caption: img.AltText})
+	}
+	data.SEO = templates.SEO{
+		URL:            seo.AbsoluteURL(h.siteURL, path),
+		Type:           "article",
+		StructuredData: []any{seo.ImageGallery(h.siteURL, path, group.Title, data.MetaDescription, images)},
+	}
+	if len(item.Images) > 0 {
+		data.SEO.Image = seo.AbsoluteURL(h.siteURL, item.HeroImage)
+	}
+
+	return pages.GalleryDetail(data).Render(ctx, c.Response().Writer)
+}
+
+// buildGalleryItem loads a single group's images.
+func (h *Handler) buildGalleryItem(c echo.Context, queries *db.Queries, g db.GalleryGroup) pages.GalleryItem {
+	ctx := c.Request().Context()
+
+	media, mediaErr := queries.GetMediaForGalleryGroup(ctx, sql.NullInt64{Int64: g.ID, Valid: true})
+	if mediaErr != nil {
+		c.Logger().Warnf("Failed to fetch media for gallery group %d: %v", g.ID, mediaErr)
+	}
+	variants, variantErr := queries.GetMediaVariantsForGalleryGroup(ctx, sql.NullInt64{Int64: g.ID, Valid: true})
+	if variantErr != nil {
+		c.Logger().Warnf("Failed to fetch media variants for gallery group %d: %v", g.ID, variantErr)
+	}
+	return h.newGalleryItem(g, media, h.buildSrcSets(variants))
+}
+
+// newGalleryItem keeps a group's public images and picks its hero.
+func (h *Handler) newGalleryItem(g db.GalleryGroup, media []db.Medium, srcSets map[int64]string) pages.GalleryItem {
+	var images []pages.GalleryImage
+	heroImage := "/static/images/placeholder.jpg"
+	heroSrcSet := ""
+
+	for _, m := range media {
+		// Private job photos are for the customer and admins only
+		if m.IsPrivate.Bool {
+			continue
+		}
+		img := pages.GalleryImage{
+			ID:      m.ID,
+			URL:     h.mediaURL(m.Url),
+			SrcSet:  srcSets[m.ID],
+			Kind:    m.Kind.String,
+			AltText: m.AltText.String,
+			PairID:  m.PairID.Int64,
+		}
+		images = append(images, img)
+		if m.Kind.String == "hero" && heroImage == "/static/images/placeholder.jpg" {
+			heroImage = img.URL
+			heroSrcSet = img.SrcSet
+		}
+	}
+
+	// Use first image as hero if no hero designated
+	if heroImage == "/static/images/placeholder.jpg" && len(images) > 0 {
+		heroImage = images[0].URL
+		heroSrcSet = images[0].SrcSet
+	}
+
+	return pages.GalleryItem{
+		ID:           g.ID,
+		Slug:         g.Slug,
+		Title:        g.Title,
+		VehicleMake:  g.VehicleMake.String,
+		VehicleModel: g.VehicleModel.String,
+		VehicleYear:  g.VehicleYear.Int64,
+		Description:  g.Description.String,
+		IsFeatured:   g.IsFeatured.Bool,
+		HeroImage:    heroImage,
+		HeroSrcSet:   heroSrcSet,
+		Images:       images,
+	}
+}
+
+// pairBeforeAfter returns the before/after pairs set up in the admin, in
+// the order of their "before" image. Groups with no explicit pairs (photos
+// uploaded before pairing existed) fall back to matching the nth "before"
+// with the nth "after". Everything left unpaired is returned in rest, in its
+// original order.
+func pairBeforeAfter(images []pages.GalleryImage) ([]pages.BeforeAfterPair, []pages.GalleryImage) {
+	byID := make(map[int64]int, len(images))
+	for i, img := range images {
+		byID[img.ID] = i
+	}
+
+	paired := make(map[int]bool)
+	var pairs []pages.BeforeAfterPair
+	for i, img := range images {
+		if img.PairID == 0 {
+			continue
+		}
+		// The "after" may be private or deleted, leaving nothing to compare
+		j, ok := byID[img.PairID]
+		if !ok || paired[j] {
+			continue
+		}
+		pairs = append(pairs, pages.BeforeAfterPair{Before: img, After: images[j]})
+		paired[i] = true
+		paired[j] = true
+	}
+
+	if len(pairs) == 0 {
+		var befores, afters []int
+		for i, img := range images {
+			switch img.Kind {
+			case "before":
+				befores = append(befores, i)
+			case "after":
+				afters = append(afters, i)
 			}
 		}
+		for i := 0; i < len(befores) && i < len(afters); i++ {
+			pairs = append(pairs, pages.BeforeAfterPair{Before: images[befores[i]], After: images[afters[i]]})
+			paired[befores[i]] = true
+			paired[afters[i]] = true
+		}
+	}
 
-		// Use first image as hero if no hero designated
-		if heroImage == "/static/images/placeholder.jpg" && len(images) > 0 {
-			heroImage = images[0].URL
+	var rest []pages.GalleryImage
+	for i, img := range images {
+		if !paired[i] {
+			rest = append(rest, img)
 		}
+	}
+	return pairs, rest
+}
+
+const maxMetaDescriptionLen = 155
 
-		items = append(items, pages.GalleryItem{
-			ID:           g.ID,
-			Slug:         g.Slug,
-			Title:        g.Title,
-			VehicleMake:  g.VehicleMake.String,
-			VehicleModel: g.VehicleModel.String,
-			VehicleYear:  g.VehicleYear.Int64,
-			Description:  g.Description.String,
-			IsFeatured:   g.IsFeatured.Bool,
-			HeroImage:    heroImage,
-			Images:       images,
-		})
+// galleryMetaDescription uses the project description when there is one and
+// otherwise describes the vehicle and package.
+func galleryMetaDescription(g db.GalleryGroup, pkg *db.Package) string {
+	desc := strings.Join(strings.Fields(g.Description.String), " ")
+	if desc == "" {
+		vehicle := strings.TrimSpace(fmt.Sprintf("%s %s", g.VehicleMake.String, g.VehicleModel.String))
+		if g.VehicleYear.Valid && vehicle != "" {
+			vehicle = fmt.Sprintf("%d %s", g.VehicleYear.Int64, vehicle)
+		}
+		if vehicle == "" {
+			vehicle = g.Title
+		}
+		desc = fmt.Sprintf("See the results of our detailing work on this %s", vehicle)
+		if pkg != nil {
+			desc += fmt.Sprintf(" with the %s package", pkg.Name)
+		}
+		desc += "."
 	}
+	if len(desc) > maxMetaDescriptionLen {
+		cut := strings.LastIndex(desc[:maxMetaDescriptionLen-1], " ")
+		if cut <= 0 {
+			cut = maxMetaDescriptionLen - 1
+		}
+		desc = strings.TrimRight(desc[:cut], " ,.;:") + "…"
+	}
+	return desc
+}
 
-	return pages.Gallery(items).Render(c.Request().Context(), c.Response().Writer)
+// buildSrcSets groups variants by media ID into srcset attribute values.
+// Variants must be ordered by width within each media item.
+func (h *Handler) buildSrcSets(variants []db.MediaVariant) map[int64]string {
+	srcSets := make(map[int64]string)
+	for _, v := range variants {
+		entry := fmt.Sprintf("%s %dw", h.mediaURL(v.Url), v.Width)
+		if existing := srcSets[v.MediaID]; existing != "" {
+			entry = existing + ", " + entry
+		}
+		srcSets[v.MediaID] = entry
+	}
+	return srcSets
 }
 
 // Admin Gallery handlers
@@ -108,6 +357,11 @@ func (h *Handler) AdminGallery(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to fetch gallery groups")
 	}
 
+	packages, err := queries.GetAllPackagesAdmin(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch packages")
+	}
+
 	// Check if we're editing
 	var formData *pages.GalleryFormData
 	editID := c.QueryParam("edit")
@@ -126,13 +380,14 @@ func (h *Handler) AdminGallery(c echo.Context) error {
 					Description:  group.Description.String,
 					IsFeatured:   group.IsFeatured.Bool,
 					SortOrder:    group.SortOrder.Int64,
+					PackageID:    group.PackageID.Int64,
 					IsEdit:       true,
 				}
 			}
 		}
 	}
 
-	return pages.AdminGallery(groups, formData).Render(c.Request().Context(), c.Response().Writer)
+	return pages.AdminGallery(groups, packages, formData).Render(c.Request().Context(), c.Response().Writer)
 }
 
 func (h *Handler) CreateGalleryGroup(c echo.Context) error {
@@ -152,6 +407,7 @@ func (h *Handler) CreateGalleryGroup(c echo.Context) error {
 	isFeatured := c.FormValue("is_featured") == "true"
 	sortOrderStr := c.FormValue("sort_order")
 	sortOrder, _ := strconv.ParseInt(sortOrderStr, 10, 64)
+	packageID, _ := strconv.ParseInt(c.FormValue("package_id"), 10, 64)
 
 	_, err := queries.CreateGalleryGroup(ctx, db.CreateGalleryGroupParams{
 		Title:        title,
@@ -162,6 +418,7 @@ func (h *Handler) CreateGalleryGroup(c echo.Context) error {
 		Description:  sql.NullString{String: description, Valid: description != ""},
 		IsFeatured:   sql.NullBool{Bool: isFeatured, Valid: true},
 		SortOrder:    sql.NullInt64{Int64: sortOrder, Valid: true},
+		PackageID:    sql.NullInt64{Int64: packageID, Valid: packageID > 0},
 	})
 
 	if err != nil {
@@ -191,6 +448,7 @@ func (h *Handler) UpdateGalleryGroup(c echo.Context) error {
 	isFeatured := c.FormValue("is_featured") == "true"
 	sortOrderStr := c.FormValue("sort_order")
 	sortOrder, _ := strconv.ParseInt(sortOrderStr, 10, 64)
+	packageID, _ := strconv.ParseInt(c.FormValue("package_id"), 10, 64)
 
 	_, err = queries.UpdateGalleryGroup(ctx, db.UpdateGalleryGroupParams{
 		ID:           id,
@@ -202,6 +460,7 @@ func (h *Handler) UpdateGalleryGroup(c echo.Context) error {
 		Description:  sql.NullString{String: description, Valid: description != ""},
 		IsFeatured:   sql.NullBool{Bool: isFeatured, Valid: true},
 		SortOrder:    sql.NullInt64{Int64: sortOrder, Valid: true},
+		PackageID:    sql.NullInt64{Int64: packageID, Valid: packageID > 0},
 	})
 
 	if err != nil {
@@ -221,10 +480,49 @@ func (h *Handler) DeleteGalleryGroup(c echo.Context) error {
 		return c.String(http.StatusBadRequest, "Invalid gallery group ID")
 	}
 
-	err = queries.DeleteGalleryGroup(ctx, id)
+	// Collect media first so uploaded files can be removed once the rows are gone
+	groupID := sql.NullInt64{Int64: id, Valid: true}
+	media, err := queries.GetMediaForGalleryGroup(ctx, groupID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch gallery media")
+	}
+	variants, err := queries.GetMediaVariantsForGalleryGroup(ctx, groupID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch media variants")
+	}
+
+	// Deleted row by row rather than left to ON DELETE CASCADE, which only
+	// runs on connections with foreign keys turned on
+	tx, err := h.db.BeginTx(ctx, nil)
 	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to delete gallery group")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	if err := qtx.DeleteMediaVariantsForGalleryGroup(ctx, groupID); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete media variants: %v", err))
+	}
+	if err := qtx.DeleteMediaForGalleryGroup(ctx, groupID); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete media: %v", err))
+	}
+	if err := qtx.DeleteGalleryGroup(ctx, id); err != nil {
 		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete gallery group: %v", err))
 	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to delete gallery group")
+	}
+
+	for _, m := range media {
+		if err := h.removeMediaFile(ctx, m.Url); err != nil {
+			c.Logger().Warnf("Failed to remove media file %s: %v", m.Url, err)
+		}
+	}
+	for _, v := range variants {
+		if err := h.removeMediaFile(ctx, v.Url); err != nil {
+			c.Logger().Warnf("Failed to remove media file %s: %v", v.Url, err)
+		}
+	}
 
 	return c.Redirect(http.StatusSeeOther, "/admin/gallery")
 }
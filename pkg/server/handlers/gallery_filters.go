@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"detailingpass/pkg/db"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const galleryPageSize = 12
+
+// galleryCursor marks the last group shown on a page. Groups are ordered
+// by (sort_order, id), so the next page starts strictly after it.
+type galleryCursor struct {
+	SortOrder int64
+	ID        int64
+}
+
+// encodeGalleryCursor produces an opaque query-string token for a cursor.
+func encodeGalleryCursor(cur galleryCursor) string {
+	raw := strconv.FormatInt(cur.SortOrder, 10) + "." + strconv.FormatInt(cur.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeGalleryCursor parses a token from encodeGalleryCursor. Malformed
+// tokens are treated as no cursor so a mangled link shows the first page.
+func decodeGalleryCursor(token string) (galleryCursor, bool) {
+	if token == "" {
+		return galleryCursor{}, false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return galleryCursor{}, false
+	}
+	sortPart, idPart, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return galleryCursor{}, false
+	}
+	sortOrder, err := strconv.ParseInt(sortPart, 10, 64)
+	if err != nil {
+		return galleryCursor{}, false
+	}
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		return galleryCursor{}, false
+	}
+	return galleryCursor{SortOrder: sortOrder, ID: id}, true
+}
+
+// parseGalleryFilters reads the gallery filters from the query string.
+// Unparseable years are ignored rather than rejected.
+func parseGalleryFilters(c echo.Context) pages.GalleryFilters {
+	f := pages.GalleryFilters{
+		Make:     strings.TrimSpace(c.QueryParam("make")),
+		Model:    strings.TrimSpace(c.QueryParam("model")),
+		Service:  strings.TrimSpace(c.QueryParam("service")),
+		Featured: c.QueryParam("featured") == "1" || c.QueryParam("featured") == "true",
+	}
+	if y, err := strconv.ParseInt(c.QueryParam("year_min"), 10, 64); err == nil && y > 0 {
+		f.YearMin = y
+	}
+	if y, err := strconv.ParseInt(c.QueryParam("year_max"), 10, 64); err == nil && y > 0 {
+		f.YearMax = y
+	}
+	if f.YearMin > 0 && f.YearMax > 0 && f.YearMin > f.YearMax {
+		f.YearMin, f.YearMax = f.YearMax, f.YearMin
+	}
+	return f
+}
+
+// galleryListParams converts filters into query arguments. The service
+// filter is a package slug; an unknown slug matches nothing.
+func galleryListParams(ctx context.Context, queries *db.Queries, f pages.GalleryFilters) db.ListGalleryGroupsPageParams {
+	params := db.ListGalleryGroupsPageParams{
+		VehicleMake:  sql.NullString{String: f.Make, Valid: f.Make != ""},
+		VehicleModel: sql.NullString{String: f.Model, Valid: f.Model != ""},
+		YearMin:      sql.NullInt64{Int64: f.YearMin, Valid: f.YearMin > 0},
+		YearMax:      sql.NullInt64{Int64: f.YearMax, Valid: f.YearMax > 0},
+		FeaturedOnly: f.Featured,
+	}
+	if f.Service != "" {
+		params.PackageID = sql.NullInt64{Int64: -1, Valid: true}
+		if pkg, err := queries.GetPackageBySlug(ctx, f.Service); err == nil {
+			params.PackageID.Int64 = pkg.ID
+		}
+	}
+	return params
+}
+
+// galleryURL builds a /gallery link that keeps the current filters.
+func galleryURL(f pages.GalleryFilters, cursor string) string {
+	q := url.Values{}
+	if f.Make != "" {
+		q.Set("make", f.Make)
+	}
+	if f.Model != "" {
+		q.Set("model", f.Model)
+	}
+	if f.YearMin > 0 {
+		q.Set("year_min", strconv.FormatInt(f.YearMin, 10))
+	}
+	if f.YearMax > 0 {
+		q.Set("year_max", strconv.FormatInt(f.YearMax, 10))
+	}
+	if f.Featured {
+		q.Set("featured", "1")
+	}
+	if f.Service != "" {
+		q.Set("service", f.Service)
+	}
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	if len(q) == 0 {
+		return "/gallery"
+	}
+	return "/gallery?" + q.Encode()
+}
+
+// loadGalleryFacets counts groups per filter value. Each dimension is
+// counted with every other active filter applied but not its own, so the
+// numbers show what choosing that value would return.
+func loadGalleryFacets(c echo.Context, queries *db.Queries, p db.ListGalleryGroupsPageParams) pages.GalleryFacets {
+	ctx := c.Request().Context()
+	var facets pages.GalleryFacets
+
+	total, err := queries.CountGalleryGroupsFiltered(ctx, db.CountGalleryGroupsFilteredParams{
+		VehicleMake:  p.VehicleMake,
+		VehicleModel: p.VehicleModel,
+		YearMin:      p.YearMin,
+		YearMax:      p.YearMax,
+		FeaturedOnly: p.FeaturedOnly,
+		PackageID:    p.PackageID,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to count gallery groups: %v", err)
+	}
+	facets.Total = total
+
+	makes, err := queries.CountGalleryGroupsByMake(ctx, db.CountGalleryGroupsByMakeParams{
+		YearMin:      p.YearMin,
+		YearMax:      p.YearMax,
+		FeaturedOnly: p.FeaturedOnly,
+		PackageID:    p.PackageID,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to count gallery makes: %v", err)
+	}
+	for _, m := range makes {
+		facets.Makes = append(facets.Makes, pages.FacetOption{Value: m.VehicleMake.String, Label: m.VehicleMake.String, Count: m.Count})
+	}
+
+	models, err := queries.CountGalleryGroupsByModel(ctx, db.CountGalleryGroupsByModelParams{
+		VehicleMake:  p.VehicleMake,
+		YearMin:      p.YearMin,
+		YearMax:      p.YearMax,
+		FeaturedOnly: p.FeaturedOnly,
+		PackageID:    p.PackageID,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to count gallery models: %v", err)
+	}
+	for _, m := range models {
+		facets.Models = append(facets.Models, pages.FacetOption{Value: m.VehicleModel.String, Label: m.VehicleModel.String, Count: m.Count})
+	}
+
+	years, err := queries.CountGalleryGroupsByYear(ctx, db.CountGalleryGroupsByYearParams{
+		VehicleMake:  p.VehicleMake,
+		VehicleModel: p.VehicleModel,
+		FeaturedOnly: p.FeaturedOnly,
+		PackageID:    p.PackageID,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to count gallery years: %v", err)
+	}
+	for _, y := range years {
+		year := strconv.FormatInt(y.VehicleYear.Int64, 10)
+		facets.Years = append(facets.Years, pages.FacetOption{Value: year, Label: year, Count: y.Count})
+	}
+
+	services, err := queries.CountGalleryGroupsByPackage(ctx, db.CountGalleryGroupsByPackageParams{
+		VehicleMake:  p.VehicleMake,
+		VehicleModel: p.VehicleModel,
+		YearMin:      p.YearMin,
+		YearMax:      p.YearMax,
+		FeaturedOnly: p.FeaturedOnly,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to count gallery services: %v", err)
+	}
+	for _, s := range services {
+		facets.Services = append(facets.Services, pages.FacetOption{Value: s.Slug, Label: s.Name, Count: s.Count})
+	}
+
+	featured, err := queries.CountFeaturedGalleryGroups(ctx, db.CountFeaturedGalleryGroupsParams{
+		VehicleMake:  p.VehicleMake,
+		VehicleModel: p.VehicleModel,
+		YearMin:      p.YearMin,
+		YearMax:      p.YearMax,
+		PackageID:    p.PackageID,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to count featured gallery groups: %v", err)
+	}
+	facets.Featured = featured
+
+	return facets
+}
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/imaging"
+	"detailingpass/pkg/storage"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	maxMediaFileBytes    = 15 << 20
+	maxMediaRequestBytes = 100 << 20
+)
+
+var (
+	mediaKinds   = []string{"gallery", "hero", "before", "after"}
+	mediaKindSet = map[string]bool{
+		"gallery": true,
+		"hero":    true,
+		"before":  true,
+		"after":   true,
+	}
+	// Sniffed content types we accept, mapped to the extension we store them under
+	allowedMediaTypes = map[string]string{
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/webp": ".webp",
+		"image/gif":  ".gif",
+	}
+	errMediaTooLarge   = errors.New("file exceeds the upload size limit")
+	errMediaNotAllowed = errors.New("file type is not a supported image")
+)
+
+func (h *Handler) AdminGalleryMedia(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid gallery group ID")
+	}
+
+	group, err := queries.GetGalleryGroupByID(ctx, groupID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Gallery group not found")
+	}
+
+	media, err := queries.GetMediaForGalleryGroup(ctx, sql.NullInt64{Int64: groupID, Valid: true})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch media")
+	}
+
+	pairedWith := make(map[int64]int64)
+	for _, m := range media {
+		if m.PairID.Valid {
+			pairedWith[m.PairID.Int64] = m.ID
+		}
+	}
+
+	items := make([]pages.AdminMediaItem, 0, len(media))
+	for _, m := range media {
+		items = append(items, pages.AdminMediaItem{Medium: m, DisplayURL: h.mediaURL(m.Url), PairedWith: pairedWith[m.ID]})
+	}
+
+	data := pages.AdminGalleryMediaData{
+		Group:        group,
+		Media:        items,
+		Kinds:        mediaKinds,
+		MaxUploadMB:  maxMediaFileBytes >> 20,
+		ErrorMessage: c.QueryParam("error"),
+	}
+
+	return pages.AdminGalleryMedia(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) UploadGalleryMedia(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid gallery group ID")
+	}
+
+	if _, err := queries.GetGalleryGroupByID(ctx, groupID); err != nil {
+		return c.String(http.StatusNotFound, "Gallery group not found")
+	}
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxMediaRequestBytes)
+	form, err := c.MultipartForm()
+	if err != nil {
+		return mediaRedirect(c, groupID, "Upload too large or malformed")
+	}
+
+	files := form.File["files"]
+	if len(files) == 0 {
+		return mediaRedirect(c, groupID, "Choose at least one image to upload")
+	}
+
+	kind := normalizeMediaKind(c.FormValue("kind"))
+	altText := strings.TrimSpace(c.FormValue("alt_text"))
+	private := c.FormValue("is_private") == "on"
+
+	nextSort, err := queries.GetNextMediaSortOrder(ctx, sql.NullInt64{Int64: groupID, Valid: true})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to prepare upload")
+	}
+
+	for _, fh := range files {
+		saved, err := h.saveMediaUpload(ctx, groupID, private, fh)
+		if err != nil {
+			return mediaRedirect(c, groupID, fmt.Sprintf("%s: %v", fh.Filename, err))
+		}
+
+		media, err := queries.CreateMedia(ctx, db.CreateMediaParams{
+			GalleryGroupID: sql.NullInt64{Int64: groupID, Valid: true},
+			Url:            saved.Key,
+			Kind:           sql.NullString{String: kind, Valid: true},
+			SortOrder:      sql.NullInt64{Int64: nextSort, Valid: true},
+			AltText:        sql.NullString{String: altText, Valid: altText != ""},
+			IsPrivate:      sql.NullBool{Bool: private, Valid: true},
+		})
+		if err != nil {
+			h.removeSavedMedia(ctx, saved)
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to save media: %v", err))
+		}
+
+		for _, v := range saved.Variants {
+			_, err := queries.CreateMediaVariant(ctx, db.CreateMediaVariantParams{
+				MediaID: media.ID,
+				Width:   v.Width,
+				Height:  v.Height,
+				Url:     v.Url,
+			})
+			if err != nil {
+				return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to save media variant: %v", err))
+			}
+		}
+		nextSort++
+	}
+
+	return mediaRedirect(c, groupID, "")
+}
+
+func (h *Handler) UpdateGalleryMedia(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	groupID, media, err := h.loadGroupMedia(c)
+	if err != nil {
+		return err
+	}
+
+	altText := strings.TrimSpace(c.FormValue("alt_text"))
+	sortOrder := media.SortOrder.Int64
+	if raw := strings.TrimSpace(c.FormValue("sort_order")); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return mediaRedirect(c, groupID, "Sort order must be a number")
+		}
+		sortOrder = parsed
+	}
+
+	kind := normalizeMediaKind(c.FormValue("kind"))
+	// A pair only makes sense between a "before" and an "after"
+	if kind != media.Kind.String {
+		if err := queries.UnpairMedia(ctx, media.ID); err != nil {
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to unpair media: %v", err))
+		}
+	}
+
+	_, err = queries.UpdateMedia(ctx, db.UpdateMediaParams{
+		Url:       media.Url,
+		Kind:      sql.NullString{String: kind, Valid: true},
+		SortOrder: sql.NullInt64{Int64: sortOrder, Valid: true},
+		AltText:   sql.NullString{String: altText, Valid: altText != ""},
+		ID:        media.ID,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update media: %v", err))
+	}
+
+	return mediaRedirect(c, groupID, "")
+}
+
+// PairGalleryMedia links a "before" image to the "after" image it compares
+// with. Each image belongs to at most one pair, so existing pairs involving
+// either image are cleared first. An empty after_id just unpairs.
+func (h *Handler) PairGalleryMedia(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	groupID, before, err := h.loadGroupMedia(c)
+	if err != nil {
+		return err
+	}
+
+	raw := strings.TrimSpace(c.FormValue("after_id"))
+	if raw == "" {
+		if err := queries.UnpairMedia(ctx, before.ID); err != nil {
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to unpair media: %v", err))
+		}
+		return mediaRedirect(c, groupID, "")
+	}
+	afterID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || afterID == before.ID {
+		return mediaRedirect(c, groupID, "Choose a different image to pair with")
+	}
+	after, err := queries.GetMediaByID(ctx, afterID)
+	if err != nil || after.GalleryGroupID.Int64 != groupID {
+		return mediaRedirect(c, groupID, "Paired image must be in the same gallery group")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to pair media")
+	}
+	defer tx.Rollback()
+	if err := pairMedia(ctx, queries.WithTx(tx), before.ID, after.ID); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to pair media: %v", err))
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to pair media: %v", err))
+	}
+
+	return mediaRedirect(c, groupID, "")
+}
+
+// pairMedia replaces any pairs involving either image with before→after
+// and sets both kinds to match.
+func pairMedia(ctx context.Context, queries *db.Queries, beforeID, afterID int64) error {
+	for _, id := range []int64{beforeID, afterID} {
+		if err := queries.UnpairMedia(ctx, id); err != nil {
+			return err
+		}
+	}
+	for id, kind := range map[int64]string{beforeID: "before", afterID: "after"} {
+		if err := queries.SetMediaKind(ctx, db.SetMediaKindParams{Kind: sql.NullString{String: kind, Valid: true}, ID: id}); err != nil {
+			return err
+		}
+	}
+	return queries.SetMediaPair(ctx, db.SetMediaPairParams{AfterID: afterID, BeforeID: beforeID})
+}
+
+func (h *Handler) DeleteGalleryMedia(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	groupID, media, err := h.loadGroupMedia(c)
+	if err != nil {
+		return err
+	}
+
+	variants, err := queries.GetMediaVariantsForMedia(ctx, media.ID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch media variants")
+	}
+
+	if err := queries.UnpairMedia(ctx, media.ID); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to unpair media: %v", err))
+	}
+	if err := queries.DeleteMediaVariantsForMedia(ctx, media.ID); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete media variants: %v", err))
+	}
+	if err := queries.DeleteMedia(ctx, media.ID); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete media: %v", err))
+	}
+
+	if err := h.removeMediaFile(ctx, media.Url); err != nil {
+		c.Logger().Warnf("Failed to remove media file %s: %v", media.Url, err)
+	}
+	for _, v := range variants {
+		if err := h.removeMediaFile(ctx, v.Url); err != nil {
+			c.Logger().Warnf("Failed to remove media file %s: %v", v.Url, err)
+		}
+	}
+
+	return mediaRedirect(c, groupID, "")
+}
+
+// loadGroupMedia resolves the :id and :mediaID params and checks that the
+// media row belongs to the gallery group in the URL.
+func (h *Handler) loadGroupMedia(c echo.Context) (int64, db.Medium, error) {
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, db.Medium{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid gallery group ID")
+	}
+	mediaID, err := strconv.ParseInt(c.Param("mediaID"), 10, 64)
+	if err != nil {
+		return 0, db.Medium{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid media ID")
+	}
+
+	media, err := db.New(h.db).GetMediaByID(c.Request().Context(), mediaID)
+	if err != nil || media.GalleryGroupID.Int64 != groupID {
+		return 0, db.Medium{}, echo.NewHTTPError(http.StatusNotFound, "Media not found")
+	}
+	return groupID, media, nil
+}
+
+// savedMedia is an upload written to storage: the stripped original plus any
+// responsive variants, each identified by its storage key.
+type savedMedia struct {
+	Key      string
+	Variants []db.MediaVariant
+}
+
+func (h *Handler) removeSavedMedia(ctx context.Context, m savedMedia) {
+	h.storage.Delete(ctx, m.Key)
+	for _, v := range m.Variants {
+		h.storage.Delete(ctx, v.Url)
+	}
+}
+
+// saveMediaUpload validates an uploaded file by sniffing its content, strips
+// its metadata, renders width variants and writes everything to media
+// storage under a random name. Private uploads go under
+// storage.PrivatePrefix so they are only reachable through signed URLs.
+func (h *Handler) saveMediaUpload(ctx context.Context, groupID int64, private bool, fh *multipart.FileHeader) (savedMedia, error) {
+	if fh.Size > maxMediaFileBytes {
+		return savedMedia{}, errMediaTooLarge
+	}
+
+	src, err := fh.Open()
+	if err != nil {
+		return savedMedia{}, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxMediaFileBytes+1))
+	if err != nil {
+		return savedMedia{}, err
+	}
+	if len(data) > maxMediaFileBytes {
+		return savedMedia{}, errMediaTooLarge
+	}
+
+	ext, ok := allowedMediaTypes[http.DetectContentType(data)]
+	if !ok {
+		return savedMedia{}, errMediaNotAllowed
+	}
+
+	processed, err := imaging.Process(data, imaging.DefaultWidths)
+	if err != nil {
+		return savedMedia{}, fmt.Errorf("could not process image: %w", err)
+	}
+
+	name, err := randomMediaName(ext)
+	if err != nil {
+		return savedMedia{}, err
+	}
+
+	dir := "gallery/" + strconv.FormatInt(groupID, 10) + "/"
+	if private {
+		dir = storage.PrivatePrefix + dir
+	}
+
+	saved := savedMedia{Key: dir + name}
+	if err := h.storage.Put(ctx, saved.Key, processed.Original, processed.ContentType); err != nil {
+		return savedMedia{}, err
+	}
+	for _, v := range processed.Variants {
+		key := dir + imaging.VariantName(name, v.Width, processed.Ext)
+		if err := h.storage.Put(ctx, key, v.Data, processed.ContentType); err != nil {
+			h.removeSavedMedia(ctx, saved)
+			return savedMedia{}, err
+		}
+		saved.Variants = append(saved.Variants, db.MediaVariant{
+			Width:  int64(v.Width),
+			Height: int64(v.Height),
+			Url:    key,
+		})
+	}
+
+	return saved, nil
+}
+
+func randomMediaName(ext string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf) + ext, nil
+}
+
+func normalizeMediaKind(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if !mediaKindSet[value] {
+		return "gallery"
+	}
+	return value
+}
+
+func mediaRedirect(c echo.Context, groupID int64, errMsg string) error {
+	redirect := fmt.Sprintf("/admin/gallery/%d/media", groupID)
+	if errMsg != "" {
+		redirect += "?error=" + url.QueryEscape(errMsg)
+	}
+	return c.Redirect(http.StatusSeeOther, redirect)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/storage"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestPairBeforeAfter(t *testing.T) {
+	img := func(id int64, kind string, pairID int64) pages.GalleryImage {
+		return pages.GalleryImage{ID: id, Kind: kind, PairID: pairID}
+	}
+	type pair struct{ before, after int64 }
+
+	tests := []struct {
+		name   string
+		images []pages.GalleryImage
+		pairs  []pair
+		rest   []int64
+	}{
+		{
+			name:   "explicit pair",
+			images: []pages.GalleryImage{img(1, "hero", 0), img(2, "before", 3), img(3, "after", 0)},
+			pairs:  []pair{{2, 3}},
+			rest:   []int64{1},
+		},
+		{
+			name:   "pairs in order of their before",
+			images: []pages.GalleryImage{img(4, "after", 0), img(1, "before", 4), img(2, "after", 0), img(3, "before", 2)},
+			pairs:  []pair{{1, 4}, {3, 2}},
+		},
+		{
+			name:   "after is private or deleted",
+			images: []pages.GalleryImage{img(1, "before", 9), img(2, "gallery", 0)},
+			rest:   []int64{1, 2},
+		},
+		{
+			name:   "explicit pairs turn off matching by kind",
+			images: []pages.GalleryImage{img(1, "before", 2), img(2, "after", 0), img(3, "before", 0), img(4, "after", 0)},
+			pairs:  []pair{{1, 2}},
+			rest:   []int64{3, 4},
+		},
+		{
+			name:   "legacy groups match the nth before with the nth after",
+			images: []pages.GalleryImage{img(1, "after", 0), img(2, "before", 0), img(3, "before", 0), img(4, "gallery", 0), img(5, "after", 0)},
+			pairs:  []pair{{2, 1}, {3, 5}},
+			rest:   []int64{4},
+		},
+		{
+			name:   "extra befores are left over",
+			images: []pages.GalleryImage{img(1, "before", 0), img(2, "before", 0), img(3, "after", 0)},
+			pairs:  []pair{{1, 3}},
+			rest:   []int64{2},
+		},
+		{
+			name: "no images",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, rest := pairBeforeAfter(tt.images)
+			var gotPairs []pair
+			for _, p := range pairs {
+				gotPairs = append(gotPairs, pair{p.Before.ID, p.After.ID})
+			}
+			var gotRest []int64
+			for _, img := range rest {
+				gotRest = append(gotRest, img.ID)
+			}
+			if !reflect.DeepEqual(gotPairs, tt.pairs) {
+				t.Errorf("pairs = %v, want %v", gotPairs, tt.pairs)
+			}
+			if !reflect.DeepEqual(gotRest, tt.rest) {
+				t.Errorf("rest = %v, want %v", gotRest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestPairMedia(t *testing.T) {
+	ctx := context.Background()
+	queries := newTestQueries(t)
+
+	group, err := queries.CreateGalleryGroup(ctx, db.CreateGalleryGroupParams{Slug: "truck", Title: "Truck"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, kind := range []string{"before", "after", "gallery"} {
+		m, err := queries.CreateMedia(ctx, db.CreateMediaParams{
+			GalleryGroupID: sql.NullInt64{Int64: group.ID, Valid: true},
+			Url:            "gallery/1/" + kind + ".jpg",
+			Kind:           sql.NullString{String: kind, Valid: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, m.ID)
+	}
+	a, b, c := ids[0], ids[1], ids[2]
+
+	state := func() map[int64][2]any {
+		got := make(map[int64][2]any)
+		for _, id := range ids {
+			m, err := queries.GetMediaByID(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pair any
+			if m.PairID.Valid {
+				pair = m.PairID.Int64
+			}
+			got[id] = [2]any{m.Kind.String, pair}
+		}
+		return got
+	}
+
+	if err := pairMedia(ctx, queries, a, b); err != nil {
+		t.Fatal(err)
+	}
+	want := map[int64][2]any{a: {"before", b}, b: {"after", nil}, c: {"gallery", nil}}
+	if got := state(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after pairing a→b: %v, want %v", got, want)
+	}
+
+	// Re-pairing b as a before breaks its old pair and sets both kinds
+	if err := pairMedia(ctx, queries, b, c); err != nil {
+		t.Fatal(err)
+	}
+	want = map[int64][2]any{a: {"before", nil}, b: {"before", c}, c: {"after", nil}}
+	if got := state(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after pairing b→c: %v, want %v", got, want)
+	}
+}
+
+func TestDeleteGalleryGroup(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestDB(t)
+	// As on a server connection that never turned foreign keys on, so
+	// nothing cascades
+	if _, err := conn.Exec("PRAGMA foreign_keys = OFF"); err != nil {
+		t.Fatal(err)
+	}
+	queries := db.New(conn)
+	store := storage.NewLocal(t.TempDir(), "/uploads", []byte("test"))
+	h := &Handler{db: conn, storage: store}
+
+	group, err := queries.CreateGalleryGroup(ctx, db.CreateGalleryGroupParams{Slug: "truck", Title: "Truck"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := queries.CreateGalleryGroup(ctx, db.CreateGalleryGroupParams{Slug: "coupe", Title: "Coupe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, g := range []db.GalleryGroup{group, other} {
+		key := fmt.Sprintf("gallery/%d/hero.jpg", g.ID)
+		m, err := queries.CreateMedia(ctx, db.CreateMediaParams{GalleryGroupID: sql.NullInt64{Int64: g.ID, Valid: true}, Url: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		variant := fmt.Sprintf("gallery/%d/hero-640.jpg", g.ID)
+		if _, err := queries.CreateMediaVariant(ctx, db.CreateMediaVariantParams{MediaID: m.ID, Width: 640, Height: 480, Url: variant}); err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []string{key, variant} {
+			if err := store.Put(ctx, k, []byte("jpeg"), "image/jpeg"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		keys = append(keys, key, variant)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.FormatInt(group.ID, 10))
+	if err := h.DeleteGalleryGroup(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	count := func(query string) int {
+		var n int
+		if err := conn.QueryRow(query).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if n := count("SELECT COUNT(*) FROM media"); n != 1 {
+		t.Errorf("%d media rows left, want the other group's 1", n)
+	}
+	if n := count("SELECT COUNT(*) FROM media_variants"); n != 1 {
+		t.Errorf("%d variant rows left, want the other group's 1", n)
+	}
+	for i, key := range keys {
+		_, err := os.Stat(filepath.Join(store.Dir, key))
+		if kept := err == nil; kept != (i >= 2) {
+			t.Errorf("%s kept = %v, want %v", key, kept, i >= 2)
+		}
+	}
+}
@@ -0,0 +1,832 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/giftcert"
+	"detailingpass/pkg/invoice"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+	"detailingpass/pkg/payments"
+	"detailingpass/pkg/seo"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	giftLinkPurpose             = "gift"
+	giftValidityYears           = 5
+	giftLinkGrace               = 60 * 24 * time.Hour
+	giftCheckoutTTL             = time.Hour
+	minGiftValue                = 2500
+	maxGiftValue                = 100000
+	adminGiftCertificatesLimit  = 200
+	giftCertificatePaymentKind  = "gift_certificate"
+	giftCertificatePaymentLabel = "gift certificate"
+)
+
+// giftCertificateAmounts are offered as buttons on the purchase form; any
+// amount between minGiftValue and maxGiftValue can be typed instead.
+var giftCertificateAmounts = []int64{5000, 10000, 15000, 25000}
+
+// giftCertificateExpiry is when a certificate issued now runs out: the end
+// of the day giftValidityYears from now.
+func giftCertificateExpiry(now time.Time) time.Time {
+	return endOfDay(now.In(bookingLocation).AddDate(giftValidityYears, 0, 0))
+}
+
+// giftCertificateLink is the printable certificate. It works for a while
+// after the certificate expires so the balance can still be looked up.
+func (h *Handler) giftCertificateLink(cert db.GiftCertificate) string {
+	until := cert.ExpiresAt.Time
+	if !cert.ExpiresAt.Valid {
+		until = giftCertificateExpiry(time.Now())
+	}
+	return h.customerLink("/gift-certificates/", giftLinkPurpose, cert.ID, until.Add(giftLinkGrace))
+}
+
+// giftCertificateStatus is the stored status, except that an active
+// certificate shows as expired or redeemed once it can't be spent.
+func giftCertificateStatus(cert db.GiftCertificate, now time.Time) string {
+	switch {
+	case cert.Status != "active":
+		return cert.Status
+	case cert.ExpiresAt.Valid && !cert.ExpiresAt.Time.After(now):
+		return "expired"
+	case cert.Balance == 0:
+		return "redeemed"
+	}
+	return "active"
+}
+
+func giftCertificateRules(cert db.GiftCertificate) giftcert.Certificate {
+	return giftcert.Certificate{
+		Status:    cert.Status,
+		Balance:   cert.Balance,
+		ExpiresAt: cert.ExpiresAt.Time,
+	}
+}
+
+// giftCertificateDocument is what gets printed on a certificate.
+func giftCertificateDocument(cert db.GiftCertificate) invoice.Certificate {
+	doc := invoice.Certificate{
+		From: invoice.Party{
+			Name:  seo.BusinessName,
+			Lines: []string{seo.BusinessPhone, seo.BusinessEmail},
+		},
+		Code:    cert.Code,
+		Value:   cert.InitialValue,
+		To:      cert.RecipientName.String,
+		Giver:   cert.PurchaserName.String,
+		Message: cert.Message.String,
+		Void:    cert.Status == "void",
+	}
+	if cert.ExpiresAt.Valid {
+		doc.ExpiresAt = cert.ExpiresAt.Time.In(bookingLocation)
+	}
+	return doc
+}
+
+func giftCertificateFilename(cert db.GiftCertificate) string {
+	return "gift-certificate-" + strings.ToLower(cert.Code) + ".pdf"
+}
+
+// giftCertErrorMessage words the giftcert errors for the invoice page.
+func giftCertErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, giftcert.ErrVoid):
+		return "That gift certificate has been cancelled."
+	case errors.Is(err, giftcert.ErrExpired):
+		return "That gift certificate has expired."
+	case errors.Is(err, giftcert.ErrEmpty):
+		return "That gift certificate has no balance left."
+	case errors.Is(err, giftcert.ErrAmount):
+		return "Enter an amount no more than the balance and the amount due."
+	default:
+		return "That gift certificate code isn't valid."
+	}
+}
+
+// changeGiftBalance moves a certificate's balance and records why in its
+// ledger. It fails without changing anything if the balance would go
+// below zero.
+func changeGiftBalance(ctx context.Context, queries *db.Queries, certID int64, kind string, amount int64, invoiceID sql.NullInt64, note string) error {
+	balance, err := queries.ChangeGiftCertificateBalance(ctx, db.ChangeGiftCertificateBalanceParams{
+		Amount: amount,
+		ID:     certID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return giftcert.ErrAmount
+	}
+	if err != nil {
+		return err
+	}
+	return queries.CreateGiftCertificateTransaction(ctx, db.CreateGiftCertificateTransactionParams{
+		GiftCertificateID: certID,
+		Kind:              kind,
+		Amount:            amount,
+		BalanceAfter:      balance,
+		InvoiceID:         invoiceID,
+		Note:              sql.NullString{String: note, Valid: note != ""},
+	})
+}
+
+// recordGiftIssue starts a new certificate's ledger with its full value.
+func recordGiftIssue(ctx context.Context, queries *db.Queries, cert db.GiftCertificate, note string) error {
+	return queries.CreateGiftCertificateTransaction(ctx, db.CreateGiftCertificateTransactionParams{
+		GiftCertificateID: cert.ID,
+		Kind:              "issue",
+		Amount:            cert.InitialValue,
+		BalanceAfter:      cert.InitialValue,
+		Note:              sql.NullString{String: note, Valid: note != ""},
+	})
+}
+
+// sendGiftCertificate emails the certificate, with its PDF, to whoever
+// bought it and to the recipient when we have their address.
+func (h *Handler) sendGiftCertificate(ctx context.Context, cert db.GiftCertificate) error {
+	if h.links == nil {
+		return errLinksOff
+	}
+	var pdf bytes.Buffer
+	if err := invoice.RenderCertificatePDF(&pdf, giftCertificateDocument(cert)); err != nil {
+		return err
+	}
+
+	var to []string
+	for _, email := range []sql.NullString{cert.RecipientEmail, cert.PurchaserEmail} {
+		if email.Valid && (len(to) == 0 || to[0] != email.String) {
+			to = append(to, email.String)
+		}
+	}
+	if len(to) == 0 {
+		return errors.New("the certificate has no email address")
+	}
+
+	greeting := "Hi"
+	if cert.RecipientName.Valid {
+		greeting = "Hi " + customerFirstName(cert.RecipientName.String)
+	}
+	intro := "Here is your gift certificate"
+	if cert.PurchaserName.Valid {
+		intro = "Here is a gift certificate from " + cert.PurchaserName.String
+	}
+	message := ""
+	if cert.Message.Valid {
+		message = "\n\"" + cert.Message.String + "\"\n"
+	}
+	expiry := ""
+	if cert.ExpiresAt.Valid {
+		expiry = " It's valid until " + invoiceDateLabel(cert.ExpiresAt) + "."
+	}
+	text := fmt.Sprintf(
+		"%s,\n\n%s for %s at C Auto Detailing Studio.\n%s\nYour code is %s.%s "+
+			"Mention it when you book and we'll take it off your invoice.\n\n"+
+			"The certificate is attached to print, and you can view it or check the balance here:\n%s\n",
+		greeting, intro, invoice.FormatMoney(cert.InitialValue), message, cert.Code, expiry, h.giftCertificateLink(cert),
+	)
+	return h.mailer.Send(ctx, mailer.Message{
+		To:      to,
+		ReplyTo: h.contactEmail,
+		Subject: "Your C Auto Detailing Studio gift certificate",
+		Text:    text,
+		Attachments: []mailer.Attachment{{
+			Filename:    giftCertificateFilename(cert),
+			ContentType: "application/pdf",
+			Data:        pdf.Bytes(),
+		}},
+	})
+}
+
+// GiftCertificates is the public page for buying a gift certificate.
+func (h *Handler) GiftCertificates(c echo.Context) error {
+	ctx := c.Request().Context()
+	data := pages.GiftCertificatesData{
+		Amounts:   giftCertificateAmounts,
+		PayOnline: h.payments.Name() != "manual" && h.links != nil,
+		Form:      pages.GiftCertificateFormValues{Amount: "100"},
+	}
+	return pages.GiftCertificates(data).Render(ctx, c.Response().Writer)
+}
+
+// BuyGiftCertificate creates a certificate waiting for payment and sends
+// the buyer to checkout. It becomes spendable when the payment webhook
+// arrives.
+func (h *Handler) BuyGiftCertificate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	form := pages.GiftCertificateFormValues{
+		Amount:         strings.TrimSpace(c.FormValue("amount")),
+		PurchaserName:  strings.TrimSpace(c.FormValue("purchaser_name")),
+		PurchaserEmail: strings.TrimSpace(strings.ToLower(c.FormValue("purchaser_email"))),
+		RecipientName:  strings.TrimSpace(c.FormValue("recipient_name")),
+		RecipientEmail: strings.TrimSpace(strings.ToLower(c.FormValue("recipient_email"))),
+		Message:        strings.TrimSpace(c.FormValue("message")),
+	}
+	data := pages.GiftCertificatesData{
+		Amounts:   giftCertificateAmounts,
+		PayOnline: h.payments.Name() != "manual" && h.links != nil,
+		Form:      form,
+	}
+	fail := func(msg string) error {
+		data.ErrorMessage = msg
+		return pages.GiftCertificates(data).Render(ctx, c.Response().Writer)
+	}
+	if !data.PayOnline {
+		return fail("Gift certificates can't be bought online at the moment. Please get in touch and we'll arrange one.")
+	}
+
+	value, errMsg := parseGiftValue(form.Amount)
+	if errMsg != "" {
+		return fail(errMsg)
+	}
+	if form.PurchaserName == "" || !validEmail(form.PurchaserEmail) {
+		return fail("Enter your name and email so we can send you the certificate.")
+	}
+	if form.RecipientEmail != "" && !validEmail(form.RecipientEmail) {
+		return fail("Enter a valid email for the recipient, or leave it blank.")
+	}
+	if len(form.Message) > 300 {
+		return fail("Keep the message under 300 characters so it fits on the certificate.")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Unable to start checkout")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	cert, err := qtx.CreateGiftCertificate(ctx, db.CreateGiftCertificateParams{
+		Code:           giftcert.NewCode(),
+		InitialValue:   value,
+		Status:         "pending",
+		Source:         "online",
+		PurchaserName:  sql.NullString{String: form.PurchaserName, Valid: true},
+		PurchaserEmail: sql.NullString{String: form.PurchaserEmail, Valid: true},
+		RecipientName:  sql.NullString{String: form.RecipientName, Valid: form.RecipientName != ""},
+		RecipientEmail: sql.NullString{String: form.RecipientEmail, Valid: form.RecipientEmail != ""},
+		Message:        sql.NullString{String: form.Message, Valid: form.Message != ""},
+		ExpiresAt:      sql.NullTime{Time: giftCertificateExpiry(time.Now()), Valid: true},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Unable to start checkout")
+	}
+	payment, err := qtx.CreatePayment(ctx, db.CreatePaymentParams{
+		Kind:     giftCertificatePaymentKind,
+		Provider: h.payments.Name(),
+		Amount:   value,
+		Currency: "usd",
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Unable to start checkout")
+	}
+	err = qtx.SetGiftCertificatePayment(ctx, db.SetGiftCertificatePaymentParams{
+		PaymentID: sql.NullInt64{Int64: payment.ID, Valid: true},
+		ID:        cert.ID,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Unable to start checkout")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Unable to start checkout")
+	}
+
+	link := h.giftCertificateLink(cert)
+	expires := time.Now().Add(giftCheckoutTTL)
+	checkout, err := h.payments.CreateCheckout(ctx, payments.CheckoutRequest{
+		Reference:   fmt.Sprintf("gift-%d-%d", payment.ID, expires.Unix()),
+		Description: "Gift certificate – " + invoice.FormatMoney(value),
+		Amount:      value,
+		Currency:    payment.Currency,
+		Email:       form.PurchaserEmail,
+		SuccessURL:  link + "?paid=1",
+		CancelURL:   h.siteURL + "/gift-certificates",
+		ExpiresAt:   expires,
+		Metadata: map[string]string{
+			"gift_certificate_id": strconv.FormatInt(cert.ID, 10),
+			"payment_id":          strconv.FormatInt(payment.ID, 10),
+		},
+	})
+	if err == nil && checkout.ID != "" {
+		err = queries.SetPaymentCheckout(ctx, db.SetPaymentCheckoutParams{
+			CheckoutID: sql.NullString{String: checkout.ID, Valid: true},
+			ID:         payment.ID,
+		})
+	}
+	if err != nil || checkout.URL == "" {
+		c.Logger().Warnf("Failed to start gift certificate checkout %d: %v", payment.ID, err)
+		if err := abandonDeposit(ctx, queries, payment, "void"); err != nil {
+			c.Logger().Warnf("Failed to close payment %d: %v", payment.ID, err)
+		}
+		return fail("We couldn't reach our payment provider. Please try again in a moment.")
+	}
+
+	return c.Redirect(http.StatusSeeOther, checkout.URL)
+}
+
+func parseGiftValue(raw string) (int64, string) {
+	value, err := parseDollars(raw)
+	if err != nil || value < minGiftValue || value > maxGiftValue {
+		return 0, fmt.Sprintf("Choose an amount between %s and %s.", invoice.FormatMoney(minGiftValue), invoice.FormatMoney(maxGiftValue))
+	}
+	return value, ""
+}
+
+func validEmail(email string) bool {
+	return strings.Contains(email, "@") && !strings.ContainsAny(email, " \r\n")
+}
+
+// activateGiftCertificate gives a certificate bought online its value once
+// the payment is in. It returns false if it was already active.
+func activateGiftCertificate(ctx context.Context, queries *db.Queries, payment db.Payment) (db.GiftCertificate, bool, error) {
+	cert, err := queries.GetGiftCertificateByPaymentID(ctx, sql.NullInt64{Int64: payment.ID, Valid: true})
+	if err != nil {
+		return db.GiftCertificate{}, false, err
+	}
+	activated, err := queries.ActivateGiftCertificate(ctx, cert.ID)
+	if err != nil || activated == 0 {
+		return cert, false, err
+	}
+	if err := recordGiftIssue(ctx, queries, cert, "Bought online"); err != nil {
+		return cert, false, err
+	}
+	cert.Status = "active"
+	cert.Balance = cert.InitialValue
+	return cert, true, nil
+}
+
+// deliverGiftCertificate sends a certificate that has just been paid for,
+// and lets the shop know it was sold.
+func (h *Handler) deliverGiftCertificate(c echo.Context, cert db.GiftCertificate) {
+	ctx := c.Request().Context()
+	if err := h.sendGiftCertificate(ctx, cert); err != nil {
+		c.Logger().Warnf("Failed to email gift certificate %d: %v", cert.ID, err)
+	}
+	if h.contactEmail == "" {
+		return
+	}
+	err := h.mailer.Send(ctx, mailer.Message{
+		To:      []string{h.contactEmail},
+		ReplyTo: cert.PurchaserEmail.String,
+		Subject: fmt.Sprintf("Gift certificate sold to %s", cert.PurchaserName.String),
+		Text: fmt.Sprintf("%s bought a %s gift certificate (%s).\n\nView it: %s/admin/gift-certificates/%d\n",
+			cert.PurchaserName.String, invoice.FormatMoney(cert.InitialValue), cert.Code, h.siteURL, cert.ID),
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to send gift certificate notification for %d: %v", cert.ID, err)
+	}
+}
+
+// GiftCertificatePage is the printable certificate from the emailed link,
+// with its current balance.
+func (h *Handler) GiftCertificatePage(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	token := c.Param("token")
+	cert, state := h.resolveGiftCertificateToken(ctx, queries, token)
+	data := pages.GiftCertificatePageData{State: state}
+	if state == pages.GiftLinkOpen {
+		fillGiftCertificatePage(&data, cert)
+		data.JustPaid = c.QueryParam("paid") != ""
+		data.PDFURL = "/gift-certificates/" + token + "/pdf"
+	}
+	return pages.GiftCertificatePage(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) GiftCertificatePDF(c echo.Context) error {
+	cert, state := h.resolveGiftCertificateToken(c.Request().Context(), db.New(h.db), c.Param("token"))
+	if state != pages.GiftLinkOpen || cert.Status == "pending" {
+		return c.String(http.StatusNotFound, "Gift certificate not found")
+	}
+	return writeGiftCertificatePDF(c, cert)
+}
+
+func (h *Handler) resolveGiftCertificateToken(ctx context.Context, queries *db.Queries, token string) (db.GiftCertificate, string) {
+	id, err := h.links.Verify(giftLinkPurpose, token, time.Now())
+	if errors.Is(err, linksign.ErrExpired) {
+		return db.GiftCertificate{}, pages.GiftLinkExpired
+	}
+	if err != nil {
+		return db.GiftCertificate{}, pages.GiftLinkInvalid
+	}
+	cert, err := queries.GetGiftCertificateByID(ctx, id)
+	if err != nil {
+		return db.GiftCertificate{}, pages.GiftLinkInvalid
+	}
+	return cert, pages.GiftLinkOpen
+}
+
+func fillGiftCertificatePage(data *pages.GiftCertificatePageData, cert db.GiftCertificate) {
+	data.Certificate = giftCertificateDocument(cert)
+	data.Status = giftCertificateStatus(cert, time.Now())
+	data.Balance = cert.Balance
+}
+
+func writeGiftCertificatePDF(c echo.Context, cert db.GiftCertificate) error {
+	var pdf bytes.Buffer
+	if err := invoice.RenderCertificatePDF(&pdf, giftCertificateDocument(cert)); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to render gift certificate")
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", giftCertificateFilename(cert)))
+	return c.Blob(http.StatusOK, "application/pdf", pdf.Bytes())
+}
+
+// AdminGiftCertificates lists certificates with the form to issue one by
+// hand, e.g. when it's paid for in the shop.
+func (h *Handler) AdminGiftCertificates(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	rows, err := queries.ListGiftCertificates(ctx, adminGiftCertificatesLimit)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch gift certificates")
+	}
+
+	data := pages.AdminGiftCertificatesData{
+		Form:         pages.GiftCertificateFormValues{ExpiresOn: giftCertificateExpiry(time.Now()).In(bookingLocation).Format("2006-01-02")},
+		ErrorMessage: c.QueryParam("error"),
+	}
+	now := time.Now()
+	for _, cert := range rows {
+		item := giftCertificateListItem(cert, now)
+		data.Certificates = append(data.Certificates, item)
+		if item.Status == "active" {
+			data.Outstanding += cert.Balance
+		}
+	}
+
+	return pages.AdminGiftCertificates(data).Render(ctx, c.Response().Writer)
+}
+
+func giftCertificateListItem(cert db.GiftCertificate, now time.Time) pages.GiftCertificateListItem {
+	item := pages.GiftCertificateListItem{
+		ID:         cert.ID,
+		Code:       cert.Code,
+		Value:      cert.InitialValue,
+		Balance:    cert.Balance,
+		Status:     giftCertificateStatus(cert, now),
+		Source:     cert.Source,
+		Recipient:  nullableString(cert.RecipientName),
+		Purchaser:  nullableString(cert.PurchaserName),
+		CreatedAt:  formatAdminTime(cert.CreatedAt),
+		ValidUntil: invoiceDateLabel(cert.ExpiresAt),
+	}
+	if item.Recipient == "" {
+		item.Recipient = nullableString(cert.RecipientEmail)
+	}
+	return item
+}
+
+// IssueGiftCertificate creates a certificate paid for outside the website,
+// or given away.
+func (h *Handler) IssueGiftCertificate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	value, errMsg := parseGiftValue(c.FormValue("amount"))
+	if errMsg != "" {
+		return giftCertificatesRedirect(c, errMsg)
+	}
+	expires := sql.NullTime{}
+	if raw := strings.TrimSpace(c.FormValue("expires_on")); raw != "" {
+		day, err := time.ParseInLocation("2006-01-02", raw, bookingLocation)
+		if err != nil || !endOfDay(day).After(time.Now()) {
+			return giftCertificatesRedirect(c, "Enter an expiry date in the future, or leave it blank for none")
+		}
+		expires = sql.NullTime{Time: endOfDay(day), Valid: true}
+	}
+
+	field := func(name string) sql.NullString {
+		v := strings.TrimSpace(c.FormValue(name))
+		return sql.NullString{String: v, Valid: v != ""}
+	}
+	params := db.CreateGiftCertificateParams{
+		Code:           giftcert.NewCode(),
+		InitialValue:   value,
+		Balance:        value,
+		Status:         "active",
+		Source:         "manual",
+		PurchaserName:  field("purchaser_name"),
+		PurchaserEmail: field("purchaser_email"),
+		RecipientName:  field("recipient_name"),
+		RecipientEmail: field("recipient_email"),
+		Message:        field("message"),
+		ExpiresAt:      expires,
+	}
+	for _, email := range []*sql.NullString{&params.PurchaserEmail, &params.RecipientEmail} {
+		email.String = strings.ToLower(email.String)
+		if email.Valid && !validEmail(email.String) {
+			return giftCertificatesRedirect(c, "Enter valid email addresses, or leave them blank")
+		}
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to issue gift certificate")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	cert, err := qtx.CreateGiftCertificate(ctx, params)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to issue gift certificate: %v", err))
+	}
+	if err := recordGiftIssue(ctx, qtx, cert, strings.TrimSpace(c.FormValue("note"))); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to issue gift certificate")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to issue gift certificate")
+	}
+
+	return giftCertificateRedirect(c, cert.ID, "")
+}
+
+// AdminGiftCertificate shows a certificate and its ledger.
+func (h *Handler) AdminGiftCertificate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid gift certificate ID")
+	}
+	cert, err := queries.GetGiftCertificateByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Gift certificate not found")
+	}
+	ledger, err := queries.ListGiftCertificateTransactions(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch transactions")
+	}
+
+	data := pages.AdminGiftCertificateData{
+		Certificate:    giftCertificateListItem(cert, time.Now()),
+		PurchaserEmail: nullableString(cert.PurchaserEmail),
+		RecipientEmail: nullableString(cert.RecipientEmail),
+		Message:        nullableString(cert.Message),
+		ErrorMessage:   c.QueryParam("error"),
+	}
+	if cert.Status != "pending" {
+		data.CustomerLink = h.giftCertificateLink(cert)
+	}
+	for _, t := range ledger {
+		data.Transactions = append(data.Transactions, pages.GiftTransactionView{
+			Kind:          t.Kind,
+			Amount:        t.Amount,
+			BalanceAfter:  t.BalanceAfter,
+			InvoiceID:     t.InvoiceID.Int64,
+			InvoiceNumber: nullableString(t.InvoiceNumber),
+			Note:          nullableString(t.Note),
+			When:          formatAdminTime(t.CreatedAt),
+		})
+	}
+
+	return pages.AdminGiftCertificate(data).Render(ctx, c.Response().Writer)
+}
+
+// AdjustGiftCertificate corrects a balance by hand. A note saying why is
+// required since it goes in the ledger.
+func (h *Handler) AdjustGiftCertificate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid gift certificate ID")
+	}
+	cert, err := queries.GetGiftCertificateByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Gift certificate not found")
+	}
+	if cert.Status != "active" {
+		return giftCertificateRedirect(c, id, "Only active certificates can be adjusted")
+	}
+
+	raw := strings.TrimSpace(c.FormValue("amount"))
+	negative := strings.HasPrefix(raw, "-")
+	amount, err := parseDollars(strings.TrimPrefix(raw, "-"))
+	if err != nil || amount == 0 {
+		return giftCertificateRedirect(c, id, "Enter the amount to add, or a negative amount to take off")
+	}
+	if negative {
+		amount = -amount
+	}
+	note := strings.TrimSpace(c.FormValue("note"))
+	if note == "" {
+		return giftCertificateRedirect(c, id, "Add a note saying why the balance changed")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to adjust balance")
+	}
+	defer tx.Rollback()
+
+	err = changeGiftBalance(ctx, queries.WithTx(tx), id, "adjust", amount, sql.NullInt64{}, note)
+	if errors.Is(err, giftcert.ErrAmount) {
+		return giftCertificateRedirect(c, id, "That would take the balance below zero")
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to adjust balance")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to adjust balance")
+	}
+
+	return giftCertificateRedirect(c, id, "")
+}
+
+// VoidGiftCertificate cancels a certificate, e.g. when its purchase is
+// refunded. The remaining balance is written off in the ledger.
+func (h *Handler) VoidGiftCertificate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid gift certificate ID")
+	}
+	cert, err := queries.GetGiftCertificateByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Gift certificate not found")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to void gift certificate")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	voided, err := qtx.VoidGiftCertificate(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to void gift certificate")
+	}
+	if voided == 0 {
+		return giftCertificateRedirect(c, id, "Only active certificates can be voided")
+	}
+	err = qtx.CreateGiftCertificateTransaction(ctx, db.CreateGiftCertificateTransactionParams{
+		GiftCertificateID: id,
+		Kind:              "void",
+		Amount:            -cert.Balance,
+		Note:              sql.NullString{String: strings.TrimSpace(c.FormValue("note")), Valid: strings.TrimSpace(c.FormValue("note")) != ""},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to void gift certificate")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to void gift certificate")
+	}
+
+	return giftCertificateRedirect(c, id, "")
+}
+
+// SendGiftCertificateEmail emails a certificate again, or for the first
+// time when it was issued by hand.
+func (h *Handler) SendGiftCertificateEmail(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid gift certificate ID")
+	}
+	cert, err := queries.GetGiftCertificateByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Gift certificate not found")
+	}
+	if cert.Status != "active" {
+		return giftCertificateRedirect(c, id, "Only active certificates can be sent")
+	}
+	if err := h.sendGiftCertificate(ctx, cert); err != nil {
+		return giftCertificateRedirect(c, id, fmt.Sprintf("Failed to send: %v", err))
+	}
+
+	return giftCertificateRedirect(c, id, "")
+}
+
+func (h *Handler) AdminGiftCertificatePDF(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid gift certificate ID")
+	}
+	cert, err := db.New(h.db).GetGiftCertificateByID(c.Request().Context(), id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Gift certificate not found")
+	}
+	return writeGiftCertificatePDF(c, cert)
+}
+
+// RedeemGiftCertificate puts a certificate towards a sent invoice. The
+// amount defaults to as much as the certificate covers; an invoice paid
+// off entirely this way is marked paid.
+func (h *Handler) RedeemGiftCertificate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+	inv, err := queries.GetInvoiceByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	if inv.Status.String != "sent" {
+		return invoiceRedirect(c, id, "Gift certificates can only be used on sent invoices")
+	}
+
+	var requested int64
+	if raw := strings.TrimSpace(c.FormValue("amount")); raw != "" {
+		if requested, err = parseDollars(raw); err != nil || requested == 0 {
+			return invoiceRedirect(c, id, "Enter a valid amount, or leave it blank to use as much as possible")
+		}
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to redeem gift certificate")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	cert, err := qtx.GetGiftCertificateByCode(ctx, giftcert.Normalize(c.FormValue("code")))
+	if errors.Is(err, sql.ErrNoRows) {
+		return invoiceRedirect(c, id, giftCertErrorMessage(giftcert.ErrUnknown))
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to redeem gift certificate")
+	}
+	doc, err := loadInvoiceDocument(ctx, qtx, inv)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to redeem gift certificate")
+	}
+	due := doc.AmountDue()
+	amount, err := giftCertificateRules(cert).Spend(time.Now(), due, requested)
+	if err != nil {
+		return invoiceRedirect(c, id, giftCertErrorMessage(err))
+	}
+
+	invoiceID := sql.NullInt64{Int64: id, Valid: true}
+	if err := changeGiftBalance(ctx, qtx, cert.ID, "redeem", -amount, invoiceID, ""); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to redeem gift certificate")
+	}
+	if amount == due {
+		_, err := qtx.MarkInvoicePaid(ctx, db.MarkInvoicePaidParams{
+			PaidAt:        sql.NullTime{Time: time.Now().UTC(), Valid: true},
+			PaymentMethod: sql.NullString{String: giftCertificatePaymentLabel, Valid: true},
+			ID:            id,
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to redeem gift certificate")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to redeem gift certificate")
+	}
+
+	return invoiceRedirect(c, id, "")
+}
+
+// refundInvoiceGiftCredits puts back what gift certificates paid towards
+// an invoice that has been voided.
+func refundInvoiceGiftCredits(ctx context.Context, queries *db.Queries, inv db.Invoice) error {
+	invoiceID := sql.NullInt64{Int64: inv.ID, Valid: true}
+	credits, err := queries.ListInvoiceGiftCredits(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+	for _, credit := range credits {
+		note := "Invoice " + inv.Number.String + " voided"
+		if err := changeGiftBalance(ctx, queries, credit.ID, "refund", credit.Amount, invoiceID, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func giftCertificatesRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/gift-certificates?error="+url.QueryEscape(errMsg))
+}
+
+func giftCertificateRedirect(c echo.Context, id int64, errMsg string) error {
+	target := fmt.Sprintf("/admin/gift-certificates/%d", id)
+	if errMsg != "" {
+		target += "?error=" + url.QueryEscape(errMsg)
+	}
+	return c.Redirect(http.StatusSeeOther, target)
+}
@@ -2,12 +2,91 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+	"detailingpass/pkg/payments"
+	"detailingpass/pkg/servicearea"
+	"detailingpass/pkg/storage"
 )
 
 type Handler struct {
-	db *sql.DB
+	db      *sql.DB
+	storage storage.Storage
+	// Shared by every handler that emails customers or links back to the
+	// site
+	mailer mailer.Mailer
+	// links is nil when LINK_SIGNING_KEY isn't set, which turns off every
+	// feature that sends customers a link
+	links   *linksign.Signer
+	siteURL string
+	// Where contact form messages and staff notifications go, and where
+	// customer replies are directed
+	contactEmail string
+	// Where happy customers are sent to leave a public review
+	googleReviewURL string
+	payments        payments.PaymentProvider
+	// bookingDeposit is taken with each online booking request, in cents.
+	// Zero turns deposits off.
+	bookingDeposit int64
+	// Cancelling at least this long before the appointment refunds the
+	// deposit; later cancellations forfeit it.
+	depositRefundWindow time.Duration
+	// Where we'll go for mobile jobs. Mobile bookings are off when it
+	// isn't set up.
+	serviceArea servicearea.Area
+	// Work handed off from requests by goBackground
+	background sync.WaitGroup
+}
+
+// New builds the handlers from the environment, so it must run after any
+// .env file is loaded.
+func New(db *sql.DB) (*Handler, error) {
+	store, err := storage.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	links, err := linksign.FromEnv()
+	if err != nil {
+		log.Printf("⚠️  %v; review requests, the waitlist and other customer links are off", err)
+	}
+	return &Handler{
+		db:                  db,
+		storage:             store,
+		mailer:              mailer.FromEnv(),
+		links:               links,
+		siteURL:             loadSiteURL(),
+		contactEmail:        strings.TrimSpace(os.Getenv("CONTACT_EMAIL")),
+		googleReviewURL:     strings.TrimSpace(os.Getenv("GOOGLE_REVIEW_URL")),
+		payments:            payments.FromEnv(),
+		bookingDeposit:      loadBookingDeposit(),
+		depositRefundWindow: loadDepositRefundWindow(),
+		serviceArea:         servicearea.FromEnv(),
+	}, nil
 }
 
-func New(db *sql.DB) *Handler {
-	return &Handler{db: db}
+func loadSiteURL() string {
+	if u := strings.TrimRight(strings.TrimSpace(os.Getenv("SITE_URL")), "/"); u != "" {
+		return u
+	}
+	return "http://localhost:8080"
+}
+
+// errLinksOff is returned by features that need a customer link when
+// LINK_SIGNING_KEY isn't set.
+var errLinksOff = errors.New("customer links are off until LINK_SIGNING_KEY is set")
+
+// customerLink is the signed link to path plus a token for the row with
+// id, or "" when customer links are off.
+func (h *Handler) customerLink(path, purpose string, id int64, expires time.Time) string {
+	if h.links == nil {
+		return ""
+	}
+	return h.siteURL + path + h.links.Sign(purpose, id, expires)
 }
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/mailer"
+
+	_ "modernc.org/sqlite"
+)
+
+var testDBCount atomic.Int64
+
+// newTestDB opens a fresh in-memory database with the app's schema.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	schema, err := os.ReadFile("../../db/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := fmt.Sprintf("file:handlers%d?mode=memory&cache=shared&_pragma=foreign_keys(1)", testDBCount.Add(1))
+	conn, err := sql.Open("sqlite", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetMaxOpenConns(1)
+	t.Cleanup(func() { conn.Close() })
+	if _, err := conn.Exec(string(schema)); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+// newTestFileDB is newTestDB on disk, opened the way the server opens it,
+// for tests that need several connections contending for the write lock.
+func newTestFileDB(t *testing.T) *sql.DB {
+	t.Helper()
+	schema, err := os.ReadFile("../../db/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if _, err := conn.Exec(string(schema)); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func newTestQueries(t *testing.T) *db.Queries {
+	t.Helper()
+	return db.New(newTestDB(t))
+}
+
+// recordingMailer keeps sent messages for tests to inspect.
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *recordingMailer) messages() []mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mailer.Message(nil), m.sent...)
+}
@@ -1,13 +1,68 @@
 package handlers
 
 import (
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/seo"
+	"detailingpass/web/templates"
 	"detailingpass/web/templates/pages"
 
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	homePackageLimit    = 3
+	homeComparisonLimit = 2
+	homeReviewLimit     = 6
+)
+
 func (h *Handler) Home(c echo.Context) error {
-	return pages.Home().Render(c.Request().Context(), c.Response().Writer)
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	var data pages.HomeData
+
+	packages, err := queries.GetAllPackages(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch packages: %v", err)
+	}
+	if len(packages) > homePackageLimit {
+		packages = packages[:homePackageLimit]
+	}
+	data.Packages = packages
+
+	pairs, err := queries.ListShowcaseMediaPairs(ctx, homeComparisonLimit)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch before/after pairs: %v", err)
+	}
+	for _, p := range pairs {
+		data.Comparisons = append(data.Comparisons, pages.HomeComparison{
+			Pair: pages.BeforeAfterPair{
+				Before: pages.GalleryImage{ID: p.BeforeID, URL: h.mediaURL(p.BeforeUrl), Kind: "before", AltText: p.BeforeAltText.String},
+				After:  pages.GalleryImage{ID: p.AfterID, URL: h.mediaURL(p.AfterUrl), Kind: "after", AltText: p.AfterAltText.String},
+			},
+			Title: p.Title,
+			Slug:  p.Slug,
+		})
+	}
+
+	reviews, err := queries.ListFeaturedReviews(ctx, homeReviewLimit)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch featured reviews: %v", err)
+	}
+	data.Reviews = reviews
+
+	if stats, err := queries.GetReviewStats(ctx); err == nil {
+		data.Rating = pages.RatingSummary{Average: stats.Average, Count: stats.Count}
+	} else {
+		c.Logger().Warnf("Failed to fetch review stats: %v", err)
+	}
+
+	data.SEO = templates.SEO{
+		URL:            seo.AbsoluteURL(h.siteURL, "/"),
+		StructuredData: []any{seo.Business(h.siteURL, seo.AggregateRating(data.Rating.Average, data.Rating.Count))},
+	}
+
+	return pages.Home(data).Render(ctx, c.Response().Writer)
 }
 
 func (h *Handler) About(c echo.Context) error {
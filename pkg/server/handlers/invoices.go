@@ -0,0 +1,954 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/invoice"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+	"detailingpass/pkg/seo"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	invoiceLinkPurpose = "invoice"
+	invoiceLinkTTL     = 2 * 365 * 24 * time.Hour
+	invoiceTerms       = 14 * 24 * time.Hour // due date for new invoices
+	adminInvoicesLimit = 200
+)
+
+var (
+	invoiceStatuses       = []string{"draft", "sent", "paid", "void"}
+	invoicePaymentMethods = []string{"card", "cash", "check", "transfer"}
+)
+
+// AdminInvoices lists invoices, optionally by status, with the new invoice
+// form.
+func (h *Handler) AdminInvoices(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	filter := c.QueryParam("status")
+	data := pages.AdminInvoicesData{
+		Statuses:     invoiceStatuses,
+		Counts:       map[string]int64{},
+		ErrorMessage: c.QueryParam("error"),
+	}
+
+	counts, err := queries.CountInvoicesByStatus(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch invoices")
+	}
+	for _, row := range counts {
+		data.Counts[row.Status.String] = row.Count
+		data.Total += row.Count
+	}
+
+	for _, status := range invoiceStatuses {
+		if filter == status {
+			data.Filter = filter
+		}
+	}
+
+	var rows []db.ListInvoicesRow
+	if data.Filter != "" {
+		filtered, err := queries.ListInvoicesByStatus(ctx, db.ListInvoicesByStatusParams{
+			Status: sql.NullString{String: filter, Valid: true},
+			Limit:  adminInvoicesLimit,
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to fetch invoices")
+		}
+		for _, row := range filtered {
+			rows = append(rows, db.ListInvoicesRow(row))
+		}
+	} else {
+		rows, err = queries.ListInvoices(ctx, adminInvoicesLimit)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to fetch invoices")
+		}
+	}
+
+	now := time.Now()
+	for _, row := range rows {
+		inv := invoiceFromRow(row)
+		data.Invoices = append(data.Invoices, pages.InvoiceListItem{
+			ID:           row.ID,
+			Number:       row.Number.String,
+			CustomerName: row.CustomerName,
+			Email:        row.Email,
+			Status:       invoiceDisplayStatus(inv, now),
+			Total:        invoiceTotals(inv, row.Subtotal).Total,
+			CreatedAt:    formatAdminTime(row.CreatedAt),
+			DueDate:      invoiceDateLabel(row.DueAt),
+		})
+	}
+
+	return pages.AdminInvoices(data).Render(ctx, c.Response().Writer)
+}
+
+func invoiceFromRow(row db.ListInvoicesRow) db.Invoice {
+	return db.Invoice{
+		ID:            row.ID,
+		Number:        row.Number,
+		Status:        row.Status,
+		TaxRate:       row.TaxRate,
+		DiscountType:  row.DiscountType,
+		DiscountValue: row.DiscountValue,
+		DueAt:         row.DueAt,
+	}
+}
+
+// invoiceTotals works out an invoice's totals from its line subtotal.
+func invoiceTotals(inv db.Invoice, subtotal int64) invoice.Totals {
+	return invoice.Compute([]invoice.Line{{Quantity: 1, UnitPrice: subtotal}}, invoiceDiscount(inv), inv.TaxRate)
+}
+
+func invoiceDiscount(inv db.Invoice) invoice.Discount {
+	return invoice.Discount{Kind: inv.DiscountType.String, Value: inv.DiscountValue}
+}
+
+// invoiceDisplayStatus is the stored status, except that a sent invoice
+// past its due date shows as overdue.
+func invoiceDisplayStatus(inv db.Invoice, now time.Time) string {
+	if inv.Status.String == "sent" && inv.DueAt.Valid && !inv.DueAt.Time.After(now) {
+		return "overdue"
+	}
+	return inv.Status.String
+}
+
+func invoiceDateLabel(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.In(bookingLocation).Format("January 2, 2006")
+}
+
+// CreateInvoice starts a draft invoice that isn't tied to a booking.
+func (h *Handler) CreateInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	details, errMsg := parseInvoiceCustomer(c)
+	if errMsg != "" {
+		return c.Redirect(http.StatusSeeOther, "/admin/invoices?error="+url.QueryEscape(errMsg))
+	}
+	params := db.CreateInvoiceParams{
+		CustomerName:   details.CustomerName,
+		Email:          details.Email,
+		Phone:          details.Phone,
+		VehicleDetails: details.VehicleDetails,
+		DueAt:          sql.NullTime{Time: defaultInvoiceDue(time.Now()), Valid: true},
+	}
+	if rate, err := queries.GetDefaultTaxRate(ctx); err == nil {
+		params.TaxName = sql.NullString{String: rate.Name, Valid: true}
+		params.TaxRate = rate.Rate
+	}
+
+	inv, err := queries.CreateInvoice(ctx, params)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create invoice: %v", err))
+	}
+
+	return invoiceRedirect(c, inv.ID, "")
+}
+
+// CreateInvoiceFromBooking starts a draft invoice for a booking. The lines
+// come from the quote the customer accepted for it, or else the package
+// they booked, plus any travel fee for a mobile job. A promo code used on
+// the booking carries over as the discount.
+func (h *Handler) CreateInvoiceFromBooking(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid booking ID")
+	}
+	booking, err := queries.GetBookingByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Booking not found")
+	}
+
+	params := db.CreateInvoiceParams{
+		BookingID:      sql.NullInt64{Int64: booking.ID, Valid: true},
+		CustomerName:   booking.CustomerName,
+		Email:          strings.ToLower(strings.TrimSpace(booking.Email)),
+		Phone:          booking.Phone,
+		VehicleDetails: booking.VehicleDetails,
+		DiscountType:   booking.DiscountType,
+		DiscountValue:  booking.DiscountValue,
+		DueAt:          sql.NullTime{Time: defaultInvoiceDue(time.Now()), Valid: true},
+	}
+	if rate, err := queries.GetDefaultTaxRate(ctx); err == nil {
+		params.TaxName = sql.NullString{String: rate.Name, Valid: true}
+		params.TaxRate = rate.Rate
+	}
+
+	var lines []db.CreateInvoiceItemParams
+	if quote, err := queries.GetAcceptedQuoteForBooking(ctx, sql.NullInt64{Int64: booking.ID, Valid: true}); err == nil {
+		items, err := queries.ListQuoteItems(ctx, quote.ID)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to fetch quote items")
+		}
+		for _, item := range items {
+			lines = append(lines, db.CreateInvoiceItemParams{
+				Kind:        item.Kind,
+				PackageID:   item.PackageID,
+				AddonID:     item.AddonID,
+				Description: item.Description,
+				Quantity:    item.Quantity,
+				UnitPrice:   item.UnitPrice,
+			})
+		}
+	} else if booking.ServiceInterest.Valid {
+		if pkg, err := queries.GetPackageBySlug(ctx, booking.ServiceInterest.String); err == nil {
+			line := db.CreateInvoiceItemParams{
+				Kind:        "package",
+				PackageID:   sql.NullInt64{Int64: pkg.ID, Valid: true},
+				Description: pkg.Name,
+				Quantity:    1,
+				UnitPrice:   pkg.PriceMin.Int64,
+			}
+			// The membership has already paid for the service; add-ons
+			// are still charged
+			if booking.MembershipID.Valid {
+				if member, err := queries.GetMembershipByID(ctx, booking.MembershipID.Int64); err == nil {
+					line.Description = fmt.Sprintf("%s (included in %s membership)", pkg.Name, member.PlanName)
+					line.UnitPrice = 0
+				}
+			}
+			lines = append(lines, line)
+		}
+	}
+	if booking.TravelFee > 0 {
+		lines = append(lines, db.CreateInvoiceItemParams{
+			Kind:        "custom",
+			Description: "Travel to " + booking.ServiceAddress.String + " " + booking.ServiceZip.String,
+			Quantity:    1,
+			UnitPrice:   booking.TravelFee,
+		})
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create invoice")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	inv, err := qtx.CreateInvoice(ctx, params)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create invoice: %v", err))
+	}
+	for _, line := range lines {
+		line.InvoiceID = inv.ID
+		if _, err := qtx.CreateInvoiceItem(ctx, line); err != nil {
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create invoice: %v", err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create invoice")
+	}
+
+	return invoiceRedirect(c, inv.ID, "")
+}
+
+// defaultInvoiceDue is the end of the day invoiceTerms from now, in the
+// shop's timezone.
+func defaultInvoiceDue(now time.Time) time.Time {
+	return endOfDay(now.In(bookingLocation).Add(invoiceTerms))
+}
+
+// AdminInvoice is the invoice editor. Drafts can be changed freely; once
+// sent, an invoice can only be resent, marked paid or voided.
+func (h *Handler) AdminInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+	inv, err := queries.GetInvoiceByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	items, err := queries.ListInvoiceItems(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch invoice items")
+	}
+	doc, err := loadInvoiceDocument(ctx, queries, inv)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch invoice")
+	}
+
+	data := pages.AdminInvoiceData{
+		Invoice:        inv,
+		Status:         invoiceDisplayStatus(inv, time.Now()),
+		Editable:       inv.Status.String == "draft",
+		Lines:          invoiceLines(items),
+		Totals:         doc.Totals(),
+		Credits:        doc.Credits,
+		AmountDue:      doc.AmountDue(),
+		DiscountLabel:  doc.DiscountLabel(),
+		TaxLabel:       doc.TaxLabel(),
+		DueLabel:       invoiceDateLabel(inv.DueAt),
+		IssuedLabel:    invoiceDateLabel(inv.IssuedAt),
+		PaidLabel:      invoiceDateLabel(inv.PaidAt),
+		SentAt:         formatAdminTime(inv.SentAt),
+		PaymentMethods: invoicePaymentMethods,
+		ErrorMessage:   c.QueryParam("error"),
+	}
+	if inv.DueAt.Valid {
+		data.DueDate = inv.DueAt.Time.In(bookingLocation).Format("2006-01-02")
+	}
+	switch inv.DiscountType.String {
+	case invoice.DiscountAmount:
+		data.DiscountValue = strconv.FormatFloat(float64(inv.DiscountValue)/100, 'f', 2, 64)
+	case invoice.DiscountPercent:
+		data.DiscountValue = strings.TrimSuffix(invoice.FormatRate(inv.DiscountValue), "%")
+	}
+	if inv.Status.String != "draft" {
+		data.CustomerLink = h.invoiceLink(inv)
+	}
+	if inv.BookingID.Valid {
+		if booking, err := queries.GetBookingByID(ctx, inv.BookingID.Int64); err == nil {
+			data.BookingLabel = booking.RequestedStart.In(bookingLocation).Format("Monday, January 2 at 3:04 PM")
+		}
+	}
+
+	if data.Editable {
+		if data.Packages, err = queries.GetAllPackages(ctx); err != nil {
+			c.Logger().Warnf("Failed to fetch packages: %v", err)
+		}
+		if data.Addons, err = queries.ListActiveAddons(ctx); err != nil {
+			c.Logger().Warnf("Failed to fetch add-ons: %v", err)
+		}
+		rates, err := queries.ListActiveTaxRates(ctx)
+		if err != nil {
+			c.Logger().Warnf("Failed to fetch tax rates: %v", err)
+		}
+		data.TaxOptions = invoiceTaxOptions(inv, rates)
+	}
+
+	return pages.AdminInvoice(data).Render(ctx, c.Response().Writer)
+}
+
+// invoiceTaxOptions lists the active rates for the tax select. An invoice
+// keeps the rate it was given, so one that has since been changed or
+// retired is offered as "keep".
+func invoiceTaxOptions(inv db.Invoice, rates []db.TaxRate) []pages.InvoiceTaxOption {
+	options := []pages.InvoiceTaxOption{{Value: "", Label: "No tax", Selected: inv.TaxRate == 0}}
+	matched := inv.TaxRate == 0
+	for _, rate := range rates {
+		selected := !matched && rate.Name == inv.TaxName.String && rate.Rate == inv.TaxRate
+		matched = matched || selected
+		options = append(options, pages.InvoiceTaxOption{
+			Value:    strconv.FormatInt(rate.ID, 10),
+			Label:    rate.Name + " (" + invoice.FormatRate(rate.Rate) + ")",
+			Selected: selected,
+		})
+	}
+	if !matched {
+		options = append(options, pages.InvoiceTaxOption{
+			Value:    "keep",
+			Label:    inv.TaxName.String + " (" + invoice.FormatRate(inv.TaxRate) + ")",
+			Selected: true,
+		})
+	}
+	return options
+}
+
+func invoiceLines(items []db.InvoiceItem) []pages.LineItem {
+	lines := make([]pages.LineItem, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, pages.LineItem{
+			ID:          item.ID,
+			Kind:        item.Kind,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.Quantity * item.UnitPrice,
+		})
+	}
+	return lines
+}
+
+// invoiceDocument is what gets printed: the invoice as stored, from the
+// business to the customer.
+func invoiceDocument(inv db.Invoice, items []db.InvoiceItem) invoice.Document {
+	doc := invoice.Document{
+		Number: inv.Number.String,
+		Status: inv.Status.String,
+		From: invoice.Party{
+			Name:  seo.BusinessName,
+			Lines: []string{seo.BusinessPhone, seo.BusinessEmail},
+		},
+		To: invoice.Party{
+			Name:  inv.CustomerName,
+			Lines: []string{inv.Email},
+		},
+		Discount: invoiceDiscount(inv),
+		TaxName:  inv.TaxName.String,
+		TaxRate:  inv.TaxRate,
+		Notes:    inv.Notes.String,
+	}
+	if inv.Phone.Valid {
+		doc.To.Lines = append(doc.To.Lines, inv.Phone.String)
+	}
+	if inv.VehicleDetails.Valid {
+		doc.To.Lines = append(doc.To.Lines, inv.VehicleDetails.String)
+	}
+	if inv.IssuedAt.Valid {
+		doc.IssuedAt = inv.IssuedAt.Time.In(bookingLocation)
+	}
+	if inv.DueAt.Valid {
+		doc.DueAt = inv.DueAt.Time.In(bookingLocation)
+	}
+	for _, item := range items {
+		doc.Lines = append(doc.Lines, invoice.Line{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+		})
+	}
+	return doc
+}
+
+// loadInvoiceDocument is invoiceDocument with the invoice's lines and any
+// gift certificates put towards it.
+func loadInvoiceDocument(ctx context.Context, queries *db.Queries, inv db.Invoice) (invoice.Document, error) {
+	items, err := queries.ListInvoiceItems(ctx, inv.ID)
+	if err != nil {
+		return invoice.Document{}, err
+	}
+	doc := invoiceDocument(inv, items)
+	credits, err := queries.ListInvoiceGiftCredits(ctx, sql.NullInt64{Int64: inv.ID, Valid: true})
+	if err != nil {
+		return invoice.Document{}, err
+	}
+	for _, credit := range credits {
+		doc.Credits = append(doc.Credits, invoice.Credit{
+			Label:  "Gift certificate " + credit.Code,
+			Amount: credit.Amount,
+		})
+	}
+	return doc, nil
+}
+
+// UpdateInvoice saves a draft's customer details, due date, tax and
+// discount.
+func (h *Handler) UpdateInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+	inv, err := queries.GetInvoiceByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	if inv.Status.String != "draft" {
+		return invoiceRedirect(c, id, "Only draft invoices can be changed")
+	}
+
+	details, errMsg := parseInvoiceCustomer(c)
+	if errMsg != "" {
+		return invoiceRedirect(c, id, errMsg)
+	}
+	params := db.UpdateInvoiceDetailsParams{
+		CustomerName:   details.CustomerName,
+		Email:          details.Email,
+		Phone:          details.Phone,
+		VehicleDetails: details.VehicleDetails,
+		TaxName:        inv.TaxName,
+		TaxRate:        inv.TaxRate,
+		ID:             id,
+	}
+	notes := strings.TrimSpace(c.FormValue("notes"))
+	params.Notes = sql.NullString{String: notes, Valid: notes != ""}
+
+	if raw := c.FormValue("due_date"); raw != "" {
+		day, err := time.ParseInLocation("2006-01-02", raw, bookingLocation)
+		if err != nil {
+			return invoiceRedirect(c, id, "Enter a valid due date")
+		}
+		params.DueAt = sql.NullTime{Time: endOfDay(day), Valid: true}
+	}
+
+	switch taxRate := c.FormValue("tax_rate"); taxRate {
+	case "keep":
+	case "":
+		params.TaxName = sql.NullString{}
+		params.TaxRate = 0
+	default:
+		rateID, _ := strconv.ParseInt(taxRate, 10, 64)
+		rate, err := queries.GetTaxRateByID(ctx, rateID)
+		if err != nil {
+			return invoiceRedirect(c, id, "Choose a tax rate")
+		}
+		params.TaxName = sql.NullString{String: rate.Name, Valid: true}
+		params.TaxRate = rate.Rate
+	}
+
+	rawDiscount := strings.TrimSpace(c.FormValue("discount_value"))
+	switch kind := c.FormValue("discount_type"); kind {
+	case invoice.DiscountAmount:
+		params.DiscountValue, err = parseDollars(rawDiscount)
+		if err != nil {
+			return invoiceRedirect(c, id, "Enter the discount in dollars, e.g. 25")
+		}
+		params.DiscountType = sql.NullString{String: kind, Valid: true}
+	case invoice.DiscountPercent:
+		params.DiscountValue, err = parsePercent(rawDiscount)
+		if err != nil {
+			return invoiceRedirect(c, id, "Enter the discount as a percentage between 0 and 100")
+		}
+		params.DiscountType = sql.NullString{String: kind, Valid: true}
+	case invoice.DiscountNone:
+	default:
+		return invoiceRedirect(c, id, "Unknown discount type")
+	}
+	if params.DiscountValue == 0 {
+		params.DiscountType = sql.NullString{}
+	}
+
+	if err := queries.UpdateInvoiceDetails(ctx, params); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update invoice: %v", err))
+	}
+
+	return invoiceRedirect(c, id, "")
+}
+
+// parseInvoiceCustomer reads the customer fields shared by the new invoice
+// form and the editor.
+func parseInvoiceCustomer(c echo.Context) (db.CreateInvoiceParams, string) {
+	name := strings.TrimSpace(c.FormValue("customer_name"))
+	email := strings.TrimSpace(strings.ToLower(c.FormValue("email")))
+	if name == "" || email == "" {
+		return db.CreateInvoiceParams{}, "Customer name and email are required"
+	}
+	if !strings.Contains(email, "@") || strings.ContainsAny(email, " \r\n") {
+		return db.CreateInvoiceParams{}, "Enter a valid email address"
+	}
+
+	phone := strings.TrimSpace(c.FormValue("phone"))
+	vehicle := strings.TrimSpace(c.FormValue("vehicle_details"))
+	return db.CreateInvoiceParams{
+		CustomerName:   name,
+		Email:          email,
+		Phone:          sql.NullString{String: phone, Valid: phone != ""},
+		VehicleDetails: sql.NullString{String: vehicle, Valid: vehicle != ""},
+	}, ""
+}
+
+// AddInvoiceItem adds a package, add-on or custom line to a draft.
+func (h *Handler) AddInvoiceItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+	inv, err := queries.GetInvoiceByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	if inv.Status.String != "draft" {
+		return invoiceRedirect(c, id, "Only draft invoices can be changed")
+	}
+
+	item, errMsg := parseLineItem(c, queries)
+	if errMsg != "" {
+		return invoiceRedirect(c, id, errMsg)
+	}
+	_, err = queries.CreateInvoiceItem(ctx, db.CreateInvoiceItemParams{
+		InvoiceID:   id,
+		Kind:        item.Kind,
+		PackageID:   item.PackageID,
+		AddonID:     item.AddonID,
+		Description: item.Description,
+		Quantity:    item.Quantity,
+		UnitPrice:   item.UnitPrice,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to add line: %v", err))
+	}
+
+	return invoiceRedirect(c, id, "")
+}
+
+func (h *Handler) DeleteInvoiceItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+	itemID, err := strconv.ParseInt(c.Param("itemID"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid line ID")
+	}
+	inv, err := queries.GetInvoiceByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	if inv.Status.String != "draft" {
+		return invoiceRedirect(c, id, "Only draft invoices can be changed")
+	}
+
+	if err := queries.DeleteInvoiceItem(ctx, db.DeleteInvoiceItemParams{ID: itemID, InvoiceID: id}); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to remove line: %v", err))
+	}
+
+	return invoiceRedirect(c, id, "")
+}
+
+// SendInvoice emails the invoice with its PDF attached. Sending a draft
+// issues it: it gets the next number in the year's sequence and can no
+// longer be edited. Sending an issued invoice again just resends it.
+func (h *Handler) SendInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+	inv, err := queries.GetInvoiceByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	items, err := queries.ListInvoiceItems(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch invoice items")
+	}
+
+	switch inv.Status.String {
+	case "draft":
+		if len(items) == 0 {
+			return invoiceRedirect(c, id, "Add at least one line before sending")
+		}
+		now := time.Now()
+		if inv.DueAt.Valid && inv.DueAt.Time.Before(endOfDay(now)) {
+			return invoiceRedirect(c, id, "Set a due date after today before sending")
+		}
+		if err := h.issueInvoice(ctx, queries, id, now); err != nil {
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to issue invoice: %v", err))
+		}
+		if inv, err = queries.GetInvoiceByID(ctx, id); err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to issue invoice")
+		}
+		if err := h.sendInvoiceEmail(ctx, queries, inv); err != nil {
+			return invoiceRedirect(c, id, fmt.Sprintf("Invoice %s is issued but the email failed: %v. Try sending it again.", inv.Number.String, err))
+		}
+	case "sent", "paid":
+		if err := h.sendInvoiceEmail(ctx, queries, inv); err != nil {
+			return invoiceRedirect(c, id, fmt.Sprintf("Failed to send invoice: %v", err))
+		}
+		if err := queries.MarkInvoiceResent(ctx, id); err != nil {
+			c.Logger().Warnf("Failed to record resend of invoice %d: %v", id, err)
+		}
+	default:
+		return invoiceRedirect(c, id, "Void invoices can't be sent")
+	}
+
+	return invoiceRedirect(c, id, "")
+}
+
+// issueInvoice numbers a draft and marks it sent. Numbers run per calendar
+// year in the shop's timezone, e.g. INV-2026-0001.
+func (h *Handler) issueInvoice(ctx context.Context, queries *db.Queries, id int64, now time.Time) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	year := int64(now.In(bookingLocation).Year())
+	n, err := qtx.NextInvoiceNumber(ctx, year)
+	if err != nil {
+		return err
+	}
+	issued, err := qtx.IssueInvoice(ctx, db.IssueInvoiceParams{
+		Number:   sql.NullString{String: fmt.Sprintf("INV-%d-%04d", year, n), Valid: true},
+		IssuedAt: sql.NullTime{Time: now.UTC(), Valid: true},
+		ID:       id,
+	})
+	if err != nil {
+		return err
+	}
+	if issued == 0 {
+		return errors.New("invoice is no longer a draft")
+	}
+	return tx.Commit()
+}
+
+func (h *Handler) sendInvoiceEmail(ctx context.Context, queries *db.Queries, inv db.Invoice) error {
+	if h.links == nil {
+		return errLinksOff
+	}
+	doc, err := loadInvoiceDocument(ctx, queries, inv)
+	if err != nil {
+		return err
+	}
+	var pdf bytes.Buffer
+	if err := invoice.RenderPDF(&pdf, doc); err != nil {
+		return err
+	}
+
+	var status string
+	switch {
+	case inv.Status.String == "paid":
+		status = "It has been paid in full. Thank you!"
+	case len(doc.Credits) > 0:
+		status = fmt.Sprintf("The balance of %s is due by %s.", invoice.FormatMoney(doc.AmountDue()), invoiceDateLabel(inv.DueAt))
+	default:
+		status = fmt.Sprintf("The total of %s is due by %s.", invoice.FormatMoney(doc.Totals().Total), invoiceDateLabel(inv.DueAt))
+	}
+	text := fmt.Sprintf(
+		"Hi %s,\n\nThank you for choosing C Auto Detailing Studio. Your invoice %s is attached.\n\n%s\n\n"+
+			"You can also view it online:\n%s\n",
+		customerFirstName(inv.CustomerName),
+		inv.Number.String,
+		status,
+		h.invoiceLink(inv),
+	)
+	return h.mailer.Send(ctx, mailer.Message{
+		To:      []string{inv.Email},
+		ReplyTo: h.contactEmail,
+		Subject: fmt.Sprintf("Invoice %s from C Auto Detailing Studio", inv.Number.String),
+		Text:    text,
+		Attachments: []mailer.Attachment{{
+			Filename:    invoiceFilename(inv),
+			ContentType: "application/pdf",
+			Data:        pdf.Bytes(),
+		}},
+	})
+}
+
+// invoiceLink is the customer's link to an issued invoice.
+func (h *Handler) invoiceLink(inv db.Invoice) string {
+	issued := inv.IssuedAt.Time
+	if !inv.IssuedAt.Valid {
+		issued = time.Now()
+	}
+	return h.customerLink("/invoice/", invoiceLinkPurpose, inv.ID, issued.Add(invoiceLinkTTL))
+}
+
+func invoiceFilename(inv db.Invoice) string {
+	if inv.Number.Valid {
+		return inv.Number.String + ".pdf"
+	}
+	return fmt.Sprintf("invoice-draft-%d.pdf", inv.ID)
+}
+
+// MarkInvoicePaid records payment of a sent invoice.
+func (h *Handler) MarkInvoicePaid(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+
+	method := c.FormValue("payment_method")
+	valid := false
+	for _, m := range invoicePaymentMethods {
+		valid = valid || m == method
+	}
+	if !valid {
+		return invoiceRedirect(c, id, "Choose how the invoice was paid")
+	}
+	paidAt := time.Now().UTC()
+	if raw := c.FormValue("paid_on"); raw != "" {
+		day, err := time.ParseInLocation("2006-01-02", raw, bookingLocation)
+		if err != nil {
+			return invoiceRedirect(c, id, "Enter a valid payment date")
+		}
+		paidAt = day.Add(12 * time.Hour).UTC()
+	}
+
+	paid, err := queries.MarkInvoicePaid(ctx, db.MarkInvoicePaidParams{
+		PaidAt:        sql.NullTime{Time: paidAt, Valid: true},
+		PaymentMethod: sql.NullString{String: method, Valid: true},
+		ID:            id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update invoice: %v", err))
+	}
+	if paid == 0 {
+		return invoiceRedirect(c, id, "Only sent invoices can be marked paid")
+	}
+
+	return invoiceRedirect(c, id, "")
+}
+
+// VoidInvoice cancels an issued invoice. It keeps its number so the
+// sequence has no gaps. Gift certificates used on it get their balance
+// back.
+func (h *Handler) VoidInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to void invoice")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	voided, err := qtx.VoidInvoice(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to void invoice: %v", err))
+	}
+	if voided == 0 {
+		return invoiceRedirect(c, id, "Only sent or paid invoices can be voided")
+	}
+	inv, err := qtx.GetInvoiceByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to void invoice")
+	}
+	if err := refundInvoiceGiftCredits(ctx, qtx, inv); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to refund gift certificates: %v", err))
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to void invoice")
+	}
+
+	return invoiceRedirect(c, id, "")
+}
+
+func (h *Handler) DeleteInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+	deleted, err := queries.DeleteDraftInvoice(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete invoice: %v", err))
+	}
+	if deleted == 0 {
+		return invoiceRedirect(c, id, "Issued invoices can't be deleted. Void them instead.")
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/invoices")
+}
+
+// AdminInvoicePDF downloads an invoice as PDF, drafts included.
+func (h *Handler) AdminInvoicePDF(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid invoice ID")
+	}
+	inv, err := queries.GetInvoiceByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	return writeInvoicePDF(c, queries, inv)
+}
+
+func writeInvoicePDF(c echo.Context, queries *db.Queries, inv db.Invoice) error {
+	doc, err := loadInvoiceDocument(c.Request().Context(), queries, inv)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch invoice items")
+	}
+	var pdf bytes.Buffer
+	if err := invoice.RenderPDF(&pdf, doc); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to render invoice")
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", invoiceFilename(inv)))
+	return c.Blob(http.StatusOK, "application/pdf", pdf.Bytes())
+}
+
+func invoiceRedirect(c echo.Context, id int64, errMsg string) error {
+	target := fmt.Sprintf("/admin/invoices/%d", id)
+	if errMsg != "" {
+		target += "?error=" + url.QueryEscape(errMsg)
+	}
+	return c.Redirect(http.StatusSeeOther, target)
+}
+
+// InvoicePage is the customer's view of an invoice from the emailed link.
+func (h *Handler) InvoicePage(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	inv, state := h.resolveInvoiceToken(ctx, queries, c.Param("token"))
+	data := pages.InvoicePageData{State: state}
+	if state == pages.InvoiceLinkOpen {
+		if err := fillInvoicePage(ctx, queries, &data, inv); err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to load invoice")
+		}
+		data.PDFURL = "/invoice/" + c.Param("token") + "/pdf"
+	}
+	return pages.InvoicePage(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) InvoicePDF(c echo.Context) error {
+	queries := db.New(h.db)
+
+	inv, state := h.resolveInvoiceToken(c.Request().Context(), queries, c.Param("token"))
+	if state != pages.InvoiceLinkOpen {
+		return c.String(http.StatusNotFound, "Invoice not found")
+	}
+	return writeInvoicePDF(c, queries, inv)
+}
+
+// resolveInvoiceToken finds the invoice a link is for. Drafts are never
+// shown to customers, even if a link somehow exists.
+func (h *Handler) resolveInvoiceToken(ctx context.Context, queries *db.Queries, token string) (db.Invoice, string) {
+	id, err := h.links.Verify(invoiceLinkPurpose, token, time.Now())
+	if errors.Is(err, linksign.ErrExpired) {
+		return db.Invoice{}, pages.InvoiceLinkExpired
+	}
+	if err != nil {
+		return db.Invoice{}, pages.InvoiceLinkInvalid
+	}
+	inv, err := queries.GetInvoiceByID(ctx, id)
+	if err != nil || inv.Status.String == "draft" {
+		return db.Invoice{}, pages.InvoiceLinkInvalid
+	}
+	return inv, pages.InvoiceLinkOpen
+}
+
+func fillInvoicePage(ctx context.Context, queries *db.Queries, data *pages.InvoicePageData, inv db.Invoice) error {
+	doc, err := loadInvoiceDocument(ctx, queries, inv)
+	if err != nil {
+		return err
+	}
+	data.Doc = doc
+	data.Status = invoiceDisplayStatus(inv, time.Now())
+	data.PaidLabel = invoiceDateLabel(inv.PaidAt)
+	return nil
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// jobInterval is how often RunJobs does its work. Series are booked a
+	// day past the booking window, so anything well under a day keeps
+	// repeat customers ahead of everyone else; the waitlist wants it
+	// short, as the next customer hears of a lapsed offer only then.
+	jobInterval = 5 * time.Minute
+	// backgroundTimeout bounds work handed off by goBackground.
+	backgroundTimeout = 2 * time.Minute
+)
+
+// RunJobs does the work that no request should wait on: booking repeat
+// series as their occurrences come into the booking window, and offering
+// the slots of lapsed waitlist offers to the next customer. It runs once
+// straight away, then every jobInterval until ctx is done.
+func (h *Handler) RunJobs(ctx context.Context) {
+	ticker := time.NewTicker(jobInterval)
+	defer ticker.Stop()
+	for {
+		h.runJobs(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Handler) runJobs(ctx context.Context) {
+	if err := h.extendBookingSeries(ctx); err != nil {
+		log.Printf("Failed to extend repeat bookings: %v", err)
+	}
+	if err := h.advanceWaitlist(ctx); err != nil {
+		log.Printf("Failed to advance the waitlist: %v", err)
+	}
+}
+
+// goBackground runs fn off the request path, such as sending email a
+// customer shouldn't wait on. Tests wait for it with h.background.Wait.
+func (h *Handler) goBackground(fn func(ctx context.Context)) {
+	h.background.Add(1)
+	go func() {
+		defer h.background.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundTimeout)
+		defer cancel()
+		fn(ctx)
+	}()
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"detailingpass/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRunJobsExtendsSeries(t *testing.T) {
+	conn := newTestDB(t)
+	queries := db.New(conn)
+	ctx := context.Background()
+	h := &Handler{db: conn}
+
+	tomorrow := time.Now().In(bookingLocation).AddDate(0, 0, 1)
+	start := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 8, 0, 0, 0, bookingLocation)
+	series, err := queries.CreateBookingSeries(ctx, db.CreateBookingSeriesParams{
+		CustomerName:    "Dana Whitfield",
+		Email:           "dana@example.com",
+		Rrule:           "FREQ=WEEKLY",
+		StartsAt:        start.UTC(),
+		DurationMinutes: 180,
+		BookedThrough:   start.UTC(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	occurrences := func() int {
+		t.Helper()
+		rows, err := queries.ListSeriesOccurrences(ctx, series.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(rows)
+	}
+
+	// Reading availability leaves the series alone
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	if err := h.BookingAvailability(e.NewContext(httptest.NewRequest(http.MethodGet, "/api/bookings/availability", nil), rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("availability = %d", rec.Code)
+	}
+	if n := occurrences(); n != 0 {
+		t.Fatalf("reading availability booked %d occurrences", n)
+	}
+
+	h.runJobs(ctx)
+	// Weekly out to a day past the longest booking window
+	if n, want := occurrences(), maxBookingHorizon/7; n < want {
+		t.Errorf("runJobs booked %d occurrences, want at least %d", n, want)
+	}
+	extended, err := queries.GetBookingSeriesByID(ctx, series.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !extended.BookedThrough.Equal(seriesHorizon(time.Now())) {
+		t.Errorf("booked through %s, want %s", extended.BookedThrough, seriesHorizon(time.Now()))
+	}
+
+	// Running again books nothing twice
+	before := occurrences()
+	h.runJobs(ctx)
+	if n := occurrences(); n != before {
+		t.Errorf("second run: %d occurrences, want %d", n, before)
+	}
+}
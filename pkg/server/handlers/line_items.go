@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"detailingpass/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+// lineItem is a package, add-on or custom line posted from the quote or
+// invoice editor.
+type lineItem struct {
+	Kind        string
+	PackageID   sql.NullInt64
+	AddonID     sql.NullInt64
+	Description string
+	Quantity    int64
+	UnitPrice   int64
+}
+
+// parseLineItem reads a line from the item forms. Package and add-on lines
+// take their catalog name and price unless others are entered.
+func parseLineItem(c echo.Context, queries *db.Queries) (lineItem, string) {
+	ctx := c.Request().Context()
+
+	quantity := int64(1)
+	if raw := strings.TrimSpace(c.FormValue("quantity")); raw != "" {
+		var err error
+		quantity, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || quantity < 1 {
+			return lineItem{}, "Quantity must be at least 1"
+		}
+	}
+	rawPrice := strings.TrimSpace(c.FormValue("unit_price"))
+	price, err := parseDollars(rawPrice)
+	if err != nil {
+		return lineItem{}, "Enter the price in dollars, e.g. 49.99"
+	}
+
+	item := lineItem{
+		Kind:        c.FormValue("kind"),
+		Description: strings.TrimSpace(c.FormValue("description")),
+		Quantity:    quantity,
+		UnitPrice:   price,
+	}
+	switch item.Kind {
+	case "package":
+		packageID, _ := strconv.ParseInt(c.FormValue("package_id"), 10, 64)
+		pkg, err := queries.GetPackageByID(ctx, packageID)
+		if err != nil {
+			return lineItem{}, "Choose a package"
+		}
+		item.PackageID = sql.NullInt64{Int64: pkg.ID, Valid: true}
+		if item.Description == "" {
+			item.Description = pkg.Name
+		}
+		if rawPrice == "" {
+			item.UnitPrice = pkg.PriceMin.Int64
+		}
+	case "addon":
+		addonID, _ := strconv.ParseInt(c.FormValue("addon_id"), 10, 64)
+		addon, err := queries.GetAddonByID(ctx, addonID)
+		if err != nil {
+			return lineItem{}, "Choose an add-on"
+		}
+		item.AddonID = sql.NullInt64{Int64: addon.ID, Valid: true}
+		if item.Description == "" {
+			item.Description = addon.Name
+		}
+		if rawPrice == "" {
+			item.UnitPrice = addon.Price
+		}
+	case "custom":
+		if item.Description == "" {
+			return lineItem{}, "Describe the custom line"
+		}
+	default:
+		return lineItem{}, "Unknown line type"
+	}
+	return item, ""
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// Prefix of upload URLs written before media rows stored storage keys
+	legacyUploadURLPrefix = "/uploads/"
+	signedMediaTTL        = time.Hour
+)
+
+// mediaURL turns a stored media reference into a URL the browser can load.
+// Seeded rows hold site paths ("/static/...") or absolute URLs and pass
+// through unchanged; uploads hold storage keys, and private keys get a
+// short-lived signed URL.
+func (h *Handler) mediaURL(ref string) string {
+	if isSitePathOrURL(ref) {
+		return ref
+	}
+	if storage.IsPrivate(ref) {
+		signed, err := h.storage.SignedURL(ref, signedMediaTTL)
+		if err != nil {
+			return ""
+		}
+		return signed
+	}
+	return h.storage.URL(ref)
+}
+
+// mediaStorageKey returns the storage key behind a media reference. Static
+// site images and external URLs are not in storage.
+func mediaStorageKey(ref string) (string, bool) {
+	if strings.HasPrefix(ref, legacyUploadURLPrefix) {
+		ref = strings.TrimPrefix(ref, legacyUploadURLPrefix)
+	} else if isSitePathOrURL(ref) {
+		return "", false
+	}
+	key, err := storage.CleanKey(ref)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+func isSitePathOrURL(ref string) bool {
+	return strings.HasPrefix(ref, "/") || strings.Contains(ref, "://")
+}
+
+// removeMediaFile deletes the stored object behind a media reference.
+// References outside storage (seeded static images) are left alone.
+func (h *Handler) removeMediaFile(ctx context.Context, ref string) error {
+	key, ok := mediaStorageKey(ref)
+	if !ok {
+		return nil
+	}
+	return h.storage.Delete(ctx, key)
+}
+
+// ServeUpload serves objects from local media storage. Private objects
+// require the signature added by storage.Local.SignedURL; with a remote
+// backend everything is served by the bucket instead.
+func (h *Handler) ServeUpload(c echo.Context) error {
+	local, ok := h.storage.(*storage.Local)
+	if !ok {
+		return echo.ErrNotFound
+	}
+
+	raw, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return echo.ErrNotFound
+	}
+	key, err := storage.CleanKey(raw)
+	if err != nil {
+		return echo.ErrNotFound
+	}
+
+	if storage.IsPrivate(key) {
+		if !local.Verify(key, c.QueryParams(), time.Now()) {
+			return c.String(http.StatusForbidden, "Link expired or invalid")
+		}
+		c.Response().Header().Set("Cache-Control", "private, no-store")
+	}
+
+	file, err := local.Path(key)
+	if err != nil {
+		return echo.ErrNotFound
+	}
+	return c.File(file)
+}
@@ -0,0 +1,707 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/auth"
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/membership"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+// currentMembership is the signed-in customer's active membership.
+func currentMembership(ctx context.Context, queries *db.Queries) (db.GetMembershipByIDRow, bool) {
+	userID := auth.GetUserID(ctx)
+	if userID == "" {
+		return db.GetMembershipByIDRow{}, false
+	}
+	row, err := queries.GetMembershipForUser(ctx, sql.NullString{String: userID, Valid: true})
+	return db.GetMembershipByIDRow(row), err == nil
+}
+
+// claimMembership is currentMembership for pages. Members are added by
+// email, so the first time one signs in with that email their account is
+// linked to the membership; the booking API then only needs the cheaper
+// lookup.
+func claimMembership(ctx context.Context, queries *db.Queries) (db.GetMembershipByIDRow, bool) {
+	if row, ok := currentMembership(ctx, queries); ok || auth.GetUserID(ctx) == "" {
+		return row, ok
+	}
+	info := auth.GetUserInfo(ctx)
+	if info == nil || info.Email == "" {
+		return db.GetMembershipByIDRow{}, false
+	}
+	id, err := queries.GetUnlinkedMembershipByEmail(ctx, strings.ToLower(strings.TrimSpace(info.Email)))
+	if err != nil {
+		return db.GetMembershipByIDRow{}, false
+	}
+	err = queries.LinkMembershipUser(ctx, db.LinkMembershipUserParams{
+		ClerkUserID: sql.NullString{String: auth.GetUserID(ctx), Valid: true},
+		ID:          id,
+	})
+	if err != nil {
+		return db.GetMembershipByIDRow{}, false
+	}
+	return currentMembership(ctx, queries)
+}
+
+// membershipRules converts a stored membership for the membership package.
+// Periods are worked out in the shop's time zone so they turn over at
+// midnight there.
+func membershipRules(row db.GetMembershipByIDRow) membership.Membership {
+	return membership.Membership{
+		ServicesPerPeriod: row.ServicesPerPeriod,
+		PeriodMonths:      row.PeriodMonths,
+		PackageID:         row.PackageID.Int64,
+		StartedAt:         row.StartedAt.In(bookingLocation),
+		PaidThrough:       row.PaidThrough.In(bookingLocation),
+		Active:            row.Status == "active",
+	}
+}
+
+// membershipErrorMessage words the reasons a booking can't use a
+// membership for the customer.
+func membershipErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, membership.ErrCancelled):
+		return "Your membership has been cancelled."
+	case errors.Is(err, membership.ErrLapsed):
+		return "Your membership isn't renewed for that date yet. Choose an earlier date, or get in touch to renew."
+	case errors.Is(err, membership.ErrService):
+		return "Your membership doesn't include that service."
+	default:
+		return "You've already booked all the services your membership includes for that period."
+	}
+}
+
+// membershipUsage counts the services booked in the period containing at.
+func membershipUsage(ctx context.Context, queries *db.Queries, row db.GetMembershipByIDRow, at time.Time) (int64, error) {
+	from, to := membershipRules(row).Period(at)
+	return queries.CountMembershipBookings(ctx, db.CountMembershipBookingsParams{
+		MembershipID:     sql.NullInt64{Int64: row.ID, Valid: true},
+		RequestedStart:   from.UTC(),
+		RequestedStart_2: to.UTC(),
+	})
+}
+
+func membershipStatus(status string, paidThrough time.Time, now time.Time) string {
+	if status == "active" && !now.Before(paidThrough) {
+		return "lapsed"
+	}
+	return status
+}
+
+func membershipIncludesLabel(services int64, packageName string) string {
+	if packageName == "" {
+		if services == 1 {
+			return "1 service"
+		}
+		return fmt.Sprintf("%d services", services)
+	}
+	return fmt.Sprintf("%d × %s", services, packageName)
+}
+
+func membershipPeriodRange(from, to time.Time) string {
+	return from.In(bookingLocation).Format("Jan 2") + " – " + to.In(bookingLocation).AddDate(0, 0, -1).Format("Jan 2, 2006")
+}
+
+func membershipPlanListItem(row db.ListMembershipPlansRow) pages.MembershipPlanListItem {
+	return pages.MembershipPlanListItem{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: nullableString(row.Description),
+		Includes:    membershipIncludesLabel(row.ServicesPerPeriod, nullableString(row.PackageName)),
+		Price:       row.Price,
+		Period:      pages.MembershipPeriodLabel(row.PeriodMonths),
+		Active:      row.IsActive.Valid && row.IsActive.Bool,
+		Members:     row.ActiveMembers,
+	}
+}
+
+// Memberships lists the plans open to new members.
+func (h *Handler) Memberships(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	rows, err := db.New(h.db).ListActiveMembershipPlans(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load memberships")
+	}
+	var data pages.MembershipsPageData
+	for _, row := range rows {
+		data.Plans = append(data.Plans, pages.MembershipPlanListItem{
+			ID:          row.ID,
+			Name:        row.Name,
+			Description: nullableString(row.Description),
+			Includes:    membershipIncludesLabel(row.ServicesPerPeriod, nullableString(row.PackageName)),
+			Price:       row.Price,
+			Period:      pages.MembershipPeriodLabel(row.PeriodMonths),
+			Active:      true,
+		})
+	}
+
+	return pages.Memberships(data).Render(ctx, c.Response().Writer)
+}
+
+// AdminMemberships lists members with how much of their current period
+// they've used, and how membership has grown and churned.
+func (h *Handler) AdminMemberships(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	rows, err := queries.ListMemberships(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch memberships")
+	}
+	plans, err := queries.ListMembershipPlans(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch membership plans")
+	}
+
+	// Periods are at most a year, so this covers every current period
+	now := time.Now()
+	starts, err := queries.ListMembershipBookingStarts(ctx, now.AddDate(-1, 0, 0).UTC())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch membership bookings")
+	}
+	bookedAt := make(map[int64][]time.Time)
+	for _, s := range starts {
+		bookedAt[s.MembershipID.Int64] = append(bookedAt[s.MembershipID.Int64], s.RequestedStart)
+	}
+
+	data := pages.AdminMembershipsData{
+		PaymentMethods: invoicePaymentMethods,
+		Form:           pages.MemberForm{StartedOn: now.In(bookingLocation).Format("2006-01-02")},
+		ErrorMessage:   c.QueryParam("error"),
+	}
+	for _, plan := range plans {
+		if plan.IsActive.Valid && plan.IsActive.Bool {
+			data.Plans = append(data.Plans, membershipPlanListItem(plan))
+		}
+	}
+
+	var spans []membership.Span
+	for _, row := range rows {
+		rules := membershipRules(db.GetMembershipByIDRow{
+			ServicesPerPeriod: row.ServicesPerPeriod,
+			PeriodMonths:      row.PeriodMonths,
+			StartedAt:         row.StartedAt,
+			PaidThrough:       row.PaidThrough,
+			Status:            row.Status,
+		})
+		from, to := rules.Period(now)
+		item := pages.MemberListItem{
+			ID:       row.ID,
+			Name:     row.CustomerName,
+			Email:    row.Email,
+			Plan:     row.PlanName,
+			Status:   membershipStatus(row.Status, row.PaidThrough, now),
+			Since:    row.StartedAt.In(bookingLocation).Format("Jan 2, 2006"),
+			PaidTo:   row.PaidThrough.In(bookingLocation).Format("Jan 2, 2006"),
+			Included: row.ServicesPerPeriod,
+		}
+		for _, at := range bookedAt[row.ID] {
+			if !at.Before(from) && at.Before(to) {
+				item.Used++
+			}
+		}
+		data.Members = append(data.Members, item)
+
+		switch item.Status {
+		case "active":
+			data.Stats.Active++
+			data.Stats.MonthlyRevenue += row.Price / int64(max(int(row.PeriodMonths), 1))
+		case "lapsed":
+			data.Stats.Active++
+			data.Stats.Lapsed++
+		}
+		span := membership.Span{Start: row.StartedAt}
+		if row.Status == "cancelled" && row.CancelledAt.Valid {
+			span.End = row.CancelledAt.Time
+		}
+		spans = append(spans, span)
+	}
+
+	recent := membership.ChurnBetween(spans, now.AddDate(0, 0, -30), now)
+	data.Stats.Joined = recent.Joined
+	data.Stats.Cancelled = recent.Cancelled
+	data.Stats.ChurnRate = churnRateLabel(recent)
+
+	local := now.In(bookingLocation)
+	thisMonth := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, bookingLocation)
+	for i := 5; i >= 0; i-- {
+		from := thisMonth.AddDate(0, -i, 0)
+		to := from.AddDate(0, 1, 0)
+		label := from.Format("January 2006")
+		if i == 0 {
+			to = now
+			label += " (so far)"
+		}
+		churn := membership.ChurnBetween(spans, from, to)
+		data.Churn = append(data.Churn, pages.ChurnMonth{
+			Label:         label,
+			ActiveAtStart: churn.ActiveAtStart,
+			Joined:        churn.Joined,
+			Cancelled:     churn.Cancelled,
+			ActiveAtEnd:   churn.ActiveAtEnd,
+			Rate:          churnRateLabel(churn),
+		})
+	}
+
+	return pages.AdminMemberships(data).Render(ctx, c.Response().Writer)
+}
+
+func churnRateLabel(churn membership.Churn) string {
+	if churn.ActiveAtStart == 0 {
+		return "–"
+	}
+	return fmt.Sprintf("%.1f%%", churn.Rate()*100)
+}
+
+// CreateMembership signs a customer up and records the payment for their
+// first period.
+func (h *Handler) CreateMembership(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	planID, err := strconv.ParseInt(c.FormValue("plan_id"), 10, 64)
+	if err != nil {
+		return membershipsRedirect(c, "Choose a plan")
+	}
+	plan, err := queries.GetMembershipPlanByID(ctx, planID)
+	if err != nil || !plan.IsActive.Bool {
+		return membershipsRedirect(c, "That plan isn't open to new members")
+	}
+	name := strings.TrimSpace(c.FormValue("customer_name"))
+	email := strings.ToLower(strings.TrimSpace(c.FormValue("email")))
+	if name == "" || !strings.Contains(email, "@") {
+		return membershipsRedirect(c, "Enter the member's name and email")
+	}
+	method, ok := membershipPaymentMethod(c)
+	if !ok {
+		return membershipsRedirect(c, "Choose how the first period was paid")
+	}
+
+	startedAt := time.Now().In(bookingLocation)
+	startedAt = time.Date(startedAt.Year(), startedAt.Month(), startedAt.Day(), 0, 0, 0, 0, bookingLocation)
+	if raw := strings.TrimSpace(c.FormValue("started_on")); raw != "" {
+		startedAt, err = time.ParseInLocation("2006-01-02", raw, bookingLocation)
+		if err != nil {
+			return membershipsRedirect(c, "Invalid start date")
+		}
+	}
+	rules := membership.Membership{PeriodMonths: plan.PeriodMonths, StartedAt: startedAt}
+	_, paidThrough := rules.Period(startedAt)
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to add member")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	phone := strings.TrimSpace(c.FormValue("phone"))
+	notes := strings.TrimSpace(c.FormValue("notes"))
+	member, err := qtx.CreateMembership(ctx, db.CreateMembershipParams{
+		PlanID:       plan.ID,
+		CustomerName: name,
+		Email:        email,
+		Phone:        sql.NullString{String: phone, Valid: phone != ""},
+		StartedAt:    startedAt.UTC(),
+		PaidThrough:  paidThrough.UTC(),
+		Notes:        sql.NullString{String: notes, Valid: notes != ""},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to add member: %v", err))
+	}
+	err = qtx.CreateMembershipRenewal(ctx, db.CreateMembershipRenewalParams{
+		MembershipID:  member.ID,
+		PeriodStart:   startedAt.UTC(),
+		PeriodEnd:     paidThrough.UTC(),
+		Amount:        plan.Price,
+		PaymentMethod: sql.NullString{String: method, Valid: true},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to add member")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to add member")
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/memberships/%d", member.ID))
+}
+
+func (h *Handler) AdminMembership(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid membership ID")
+	}
+	row, err := queries.GetMembershipByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Membership not found")
+	}
+	renewals, err := queries.ListMembershipRenewals(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch renewals")
+	}
+	bookings, err := queries.ListMembershipBookings(ctx, sql.NullInt64{Int64: id, Valid: true})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch bookings")
+	}
+
+	now := time.Now()
+	var packageName string
+	if row.PackageID.Valid {
+		if pkg, err := queries.GetPackageByID(ctx, row.PackageID.Int64); err == nil {
+			packageName = pkg.Name
+		}
+	}
+	from, to := membershipRules(row).Period(now)
+	data := pages.AdminMembershipData{
+		Member: pages.MemberListItem{
+			ID:       row.ID,
+			Name:     row.CustomerName,
+			Email:    row.Email,
+			Plan:     row.PlanName,
+			Status:   membershipStatus(row.Status, row.PaidThrough, now),
+			Since:    row.StartedAt.In(bookingLocation).Format("Jan 2, 2006"),
+			PaidTo:   row.PaidThrough.In(bookingLocation).Format("Jan 2, 2006"),
+			Included: row.ServicesPerPeriod,
+		},
+		Phone:          nullableString(row.Phone),
+		Linked:         row.ClerkUserID.Valid,
+		Includes:       membershipIncludesLabel(row.ServicesPerPeriod, packageName),
+		Price:          row.Price,
+		Period:         pages.MembershipPeriodLabel(row.PeriodMonths),
+		CurrentPeriod:  membershipPeriodRange(from, to),
+		Notes:          nullableString(row.Notes),
+		CancelReason:   nullableString(row.CancelReason),
+		PaymentMethods: invoicePaymentMethods,
+		ErrorMessage:   c.QueryParam("error"),
+	}
+	if row.CancelledAt.Valid {
+		data.CancelledOn = invoiceDateLabel(row.CancelledAt)
+	}
+	for _, r := range renewals {
+		data.Renewals = append(data.Renewals, pages.MembershipRenewalView{
+			Period: membershipPeriodRange(r.PeriodStart, r.PeriodEnd),
+			Amount: r.Amount,
+			Method: nullableString(r.PaymentMethod),
+			When:   formatAdminTime(r.CreatedAt),
+		})
+	}
+	for _, b := range bookings {
+		status := normalizeBookingStatus(b.Status.String)
+		if !b.RequestedStart.Before(from) && b.RequestedStart.Before(to) && status != "cancelled" && status != "declined" {
+			data.Member.Used++
+		}
+		data.Bookings = append(data.Bookings, pages.MembershipBookingView{
+			ID:          b.ID,
+			Appointment: b.RequestedStart.In(bookingLocation).Format("Mon, Jan 2 3:04 PM"),
+			Service:     nullableString(b.ServiceInterest),
+			Status:      status,
+		})
+	}
+
+	return pages.AdminMembership(data).Render(ctx, c.Response().Writer)
+}
+
+// RenewMembership records a payment for the next period. A membership
+// that lapsed more than a period ago picks up from the current period
+// rather than back-filling the ones missed.
+func (h *Handler) RenewMembership(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid membership ID")
+	}
+	row, err := queries.GetMembershipByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Membership not found")
+	}
+	method, ok := membershipPaymentMethod(c)
+	if !ok {
+		return membershipRedirect(c, id, "Choose how the renewal was paid")
+	}
+
+	rules := membershipRules(row)
+	from := rules.PaidThrough
+	if current, _ := rules.Period(time.Now()); from.Before(current) {
+		from = current
+	}
+	_, to := rules.Period(from)
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to renew membership")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	renewed, err := qtx.RenewMembership(ctx, db.RenewMembershipParams{PaidThrough: to.UTC(), ID: id})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to renew membership")
+	}
+	if renewed == 0 {
+		return membershipRedirect(c, id, "Resume the membership before renewing it")
+	}
+	err = qtx.CreateMembershipRenewal(ctx, db.CreateMembershipRenewalParams{
+		MembershipID:  id,
+		PeriodStart:   from.UTC(),
+		PeriodEnd:     to.UTC(),
+		Amount:        row.Price,
+		PaymentMethod: sql.NullString{String: method, Valid: true},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to renew membership")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to renew membership")
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/memberships/%d", id))
+}
+
+// CancelMembership stops the membership straight away. Bookings already
+// made with it are kept.
+func (h *Handler) CancelMembership(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid membership ID")
+	}
+	reason := strings.TrimSpace(c.FormValue("reason"))
+	cancelled, err := queries.CancelMembership(ctx, db.CancelMembershipParams{
+		CancelledAt:  sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		CancelReason: sql.NullString{String: reason, Valid: reason != ""},
+		ID:           id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to cancel membership")
+	}
+	if cancelled == 0 {
+		return membershipRedirect(c, id, "This membership is already cancelled")
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/memberships/%d", id))
+}
+
+func (h *Handler) ResumeMembership(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid membership ID")
+	}
+	if _, err := queries.ResumeMembership(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to resume membership")
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/memberships/%d", id))
+}
+
+func (h *Handler) UpdateMembershipNotes(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid membership ID")
+	}
+	notes := strings.TrimSpace(c.FormValue("notes"))
+	err = queries.UpdateMembershipNotes(ctx, db.UpdateMembershipNotesParams{
+		Notes: sql.NullString{String: notes, Valid: notes != ""},
+		ID:    id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save notes")
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/memberships/%d", id))
+}
+
+func (h *Handler) AdminMembershipPlans(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	rows, err := queries.ListMembershipPlans(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch membership plans")
+	}
+	packages, err := queries.GetAllPackages(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch packages: %v", err)
+	}
+
+	data := pages.AdminMembershipPlansData{
+		Packages:     packages,
+		Form:         pages.MembershipPlanForm{ServicesPerPeriod: "1", PeriodMonths: 1, Active: true},
+		ErrorMessage: c.QueryParam("error"),
+	}
+	for _, row := range rows {
+		data.Plans = append(data.Plans, membershipPlanListItem(row))
+		if strconv.FormatInt(row.ID, 10) == c.QueryParam("edit") {
+			data.Form = pages.MembershipPlanForm{
+				ID:                row.ID,
+				Name:              row.Name,
+				Description:       nullableString(row.Description),
+				PackageID:         row.PackageID.Int64,
+				ServicesPerPeriod: strconv.FormatInt(row.ServicesPerPeriod, 10),
+				PeriodMonths:      row.PeriodMonths,
+				Price:             fmt.Sprintf("%.2f", float64(row.Price)/100),
+				Active:            row.IsActive.Valid && row.IsActive.Bool,
+			}
+		}
+	}
+
+	return pages.AdminMembershipPlans(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) CreateMembershipPlan(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	params, errMsg := parseMembershipPlanForm(c)
+	if errMsg != "" {
+		return membershipPlansRedirect(c, errMsg)
+	}
+	if _, err := db.New(h.db).CreateMembershipPlan(ctx, params); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create plan: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/memberships/plans")
+}
+
+// UpdateMembershipPlan changes a plan. Changes apply to existing members
+// from their next booking; a new period length only moves renewal dates
+// after the one they've paid up to.
+func (h *Handler) UpdateMembershipPlan(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid plan ID")
+	}
+	params, errMsg := parseMembershipPlanForm(c)
+	if errMsg != "" {
+		return membershipPlansRedirect(c, errMsg)
+	}
+	err = db.New(h.db).UpdateMembershipPlan(ctx, db.UpdateMembershipPlanParams{
+		Name:              params.Name,
+		Description:       params.Description,
+		PackageID:         params.PackageID,
+		ServicesPerPeriod: params.ServicesPerPeriod,
+		PeriodMonths:      params.PeriodMonths,
+		Price:             params.Price,
+		IsActive:          params.IsActive,
+		ID:                id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update plan: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/memberships/plans")
+}
+
+func (h *Handler) DeleteMembershipPlan(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid plan ID")
+	}
+	deleted, err := db.New(h.db).DeleteMembershipPlan(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete plan: %v", err))
+	}
+	if deleted == 0 {
+		return membershipPlansRedirect(c, "This plan has members, so it's kept for their history. Close it to new members instead.")
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/memberships/plans")
+}
+
+func parseMembershipPlanForm(c echo.Context) (db.CreateMembershipPlanParams, string) {
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return db.CreateMembershipPlanParams{}, "Give the plan a name"
+	}
+	description := strings.TrimSpace(c.FormValue("description"))
+	params := db.CreateMembershipPlanParams{
+		Name:        name,
+		Description: sql.NullString{String: description, Valid: description != ""},
+		IsActive:    sql.NullBool{Bool: c.FormValue("is_active") == "true", Valid: true},
+	}
+	if raw := strings.TrimSpace(c.FormValue("package_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return db.CreateMembershipPlanParams{}, "Invalid service"
+		}
+		params.PackageID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	services, err := strconv.ParseInt(strings.TrimSpace(c.FormValue("services_per_period")), 10, 64)
+	if err != nil || services < 1 {
+		return db.CreateMembershipPlanParams{}, "Enter how many services each period includes"
+	}
+	params.ServicesPerPeriod = services
+
+	months, _ := strconv.ParseInt(c.FormValue("period_months"), 10, 64)
+	for _, option := range pages.MembershipPeriodOptions {
+		if months == option {
+			params.PeriodMonths = months
+		}
+	}
+	if params.PeriodMonths == 0 {
+		return db.CreateMembershipPlanParams{}, "Choose how often the plan renews"
+	}
+
+	params.Price, err = parseDollars(c.FormValue("price"))
+	if err != nil || params.Price == 0 {
+		return db.CreateMembershipPlanParams{}, "Enter the price per period in dollars"
+	}
+
+	return params, ""
+}
+
+func membershipPaymentMethod(c echo.Context) (string, bool) {
+	method := c.FormValue("payment_method")
+	for _, m := range invoicePaymentMethods {
+		if m == method {
+			return method, true
+		}
+	}
+	return "", false
+}
+
+func membershipsRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/memberships?error="+url.QueryEscape(errMsg))
+}
+
+func membershipPlansRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/memberships/plans?error="+url.QueryEscape(errMsg))
+}
+
+func membershipRedirect(c echo.Context, id int64, errMsg string) error {
+	target := fmt.Sprintf("/admin/memberships/%d", id)
+	if errMsg != "" {
+		target += "?error=" + url.QueryEscape(errMsg)
+	}
+	return c.Redirect(http.StatusSeeOther, target)
+}
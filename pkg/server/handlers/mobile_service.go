@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/servicearea"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+type serviceAreaResponse struct {
+	ZIP           string  `json:"zip"`
+	Miles         float64 `json:"miles,omitempty"`
+	TravelMinutes int     `json:"travel_minutes"`
+	Fee           int64   `json:"fee"`
+	Message       string  `json:"message"`
+}
+
+// CheckServiceArea tells the booking page whether we travel to a ZIP and
+// what it costs, before the customer picks a time.
+func (h *Handler) CheckServiceArea(c echo.Context) error {
+	trip, err := h.serviceArea.Quote(c.QueryParam("zip"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, serviceAreaResponse{
+		ZIP:           trip.ZIP,
+		Miles:         float64(int(trip.Miles*10)) / 10,
+		TravelMinutes: int(trip.Travel.Minutes()),
+		Fee:           trip.Fee,
+		Message:       tripLabel(trip),
+	})
+}
+
+func tripLabel(trip servicearea.Trip) string {
+	if trip.Fee == 0 {
+		return fmt.Sprintf("We come to %s at no extra charge.", trip.ZIP)
+	}
+	return fmt.Sprintf("We come to %s for a %s travel fee.", trip.ZIP, pages.FormatMoney(trip.Fee))
+}
+
+// visitorStop is where the visitor wants the job done: at the ZIP they've
+// given when we serve it, and the shop otherwise.
+func (h *Handler) visitorStop(zip string) servicearea.Stop {
+	if zip == "" {
+		return servicearea.Stop{}
+	}
+	trip, err := h.serviceArea.Quote(zip)
+	if err != nil {
+		return servicearea.Stop{}
+	}
+	return trip.Stop
+}
+
+type bookedStop struct {
+	start, end time.Time
+	stop       servicearea.Stop
+	staffID    int64 // 0 until the job is assigned
+}
+
+func listBookedStops(ctx context.Context, queries *db.Queries, from, to time.Time) ([]bookedStop, error) {
+	rows, err := queries.ListBookingStops(ctx, db.ListBookingStopsParams{
+		RequestedStart:   from.UTC(),
+		RequestedStart_2: to.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	stops := make([]bookedStop, 0, len(rows))
+	for _, row := range rows {
+		stops = append(stops, bookedStop{
+			start:   row.RequestedStart,
+			end:     row.RequestedEnd,
+			stop:    bookingStop(row.ServiceAddress, row.ServiceLat, row.ServiceLng, row.TravelMinutes),
+			staffID: row.StaffID.Int64,
+		})
+	}
+	return stops, nil
+}
+
+func bookingStop(address sql.NullString, lat, lng sql.NullFloat64, travelMinutes int64) servicearea.Stop {
+	if !address.Valid {
+		return servicearea.Stop{}
+	}
+	return servicearea.Stop{
+		Mobile:   true,
+		Location: servicearea.Point{Lat: lat.Float64, Lng: lng.Float64},
+		Located:  lat.Valid && lng.Valid,
+		Travel:   time.Duration(travelMinutes) * time.Minute,
+	}
+}
+
+// tooFarFrom reports whether a job at stop from start to end leaves too
+// little time to drive from the job before it that day, or to the one
+// after, going by area. The first and last drives of the day aren't
+// counted; the slots leave room for them.
+func tooFarFrom(area servicearea.Area, stops []bookedStop, start, end time.Time, stop servicearea.Stop) bool {
+	day := start.In(bookingLocation).Format("2006-01-02")
+	var prev, next *bookedStop
+	for i := range stops {
+		s := &stops[i]
+		if s.start.In(bookingLocation).Format("2006-01-02") != day {
+			continue
+		}
+		if !s.end.After(start) && (prev == nil || s.end.After(prev.end)) {
+			prev = s
+		}
+		if !s.start.Before(end) && (next == nil || s.start.Before(next.start)) {
+			next = s
+		}
+	}
+	if prev != nil && start.Sub(prev.end) < area.Between(prev.stop, stop) {
+		return true
+	}
+	return next != nil && next.start.Sub(end) < area.Between(stop, next.stop)
+}
+
+// mobileTrip reads where a booking request wants us to go. It returns the
+// zero trip for a job at the shop.
+func (h *Handler) mobileTrip(req bookingRequest) (servicearea.Trip, error) {
+	if !req.Mobile {
+		return servicearea.Trip{}, nil
+	}
+	if req.Address == "" {
+		return servicearea.Trip{}, errors.New("Enter the address you'd like us to come to.")
+	}
+	return h.serviceArea.Quote(req.ZIP)
+}
+
+func serviceLocationParams(bookingID int64, address string, trip servicearea.Trip) db.SetBookingServiceLocationParams {
+	return db.SetBookingServiceLocationParams{
+		ServiceAddress: sql.NullString{String: address, Valid: true},
+		ServiceZip:     sql.NullString{String: trip.ZIP, Valid: true},
+		ServiceLat:     sql.NullFloat64{Float64: trip.Location.Lat, Valid: trip.Located},
+		ServiceLng:     sql.NullFloat64{Float64: trip.Location.Lng, Valid: trip.Located},
+		TravelMinutes:  int64(trip.Travel.Minutes()),
+		TravelFee:      trip.Fee,
+		ID:             bookingID,
+	}
+}
+
+// mobileJobLabel describes where a mobile job is for the admin, or
+// is empty for a job at the shop.
+func mobileJobLabel(row db.Booking) string {
+	if !row.ServiceAddress.Valid {
+		return ""
+	}
+	label := fmt.Sprintf("%s %s · %d min drive", row.ServiceAddress.String, row.ServiceZip.String, row.TravelMinutes)
+	if row.TravelFee > 0 {
+		label += " · " + pages.FormatMoney(row.TravelFee) + " travel"
+	}
+	return label
+}
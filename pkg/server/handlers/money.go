@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var errInvalidAmount = errors.New("invalid amount")
+
+// parseDollars reads a non-negative dollar amount typed by an admin, such
+// as "49.99" or "$1,250", into cents. Blank is zero.
+func parseDollars(raw string) (int64, error) {
+	raw = strings.NewReplacer("$", "", ",", "", " ", "").Replace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	dollars, err := strconv.ParseFloat(raw, 64)
+	if err != nil || dollars < 0 || math.IsInf(dollars, 0) || math.IsNaN(dollars) {
+		return 0, errInvalidAmount
+	}
+	return int64(math.Round(dollars * 100)), nil
+}
+
+// parsePercent reads a percentage such as "8.25" or "15%" into basis
+// points. Blank is zero and anything over 100% is rejected.
+func parsePercent(raw string) (int64, error) {
+	raw = strings.NewReplacer("%", "", " ", "").Replace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	percent, err := strconv.ParseFloat(raw, 64)
+	if err != nil || percent < 0 || percent > 100 || math.IsNaN(percent) {
+		return 0, errInvalidAmount
+	}
+	return int64(math.Round(percent * 100)), nil
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"detailingpass/pkg/db"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+// loadPackageContent fills in the FAQs and gallery choices shown when
+// editing a package.
+func loadPackageContent(c echo.Context, queries *db.Queries, form *pages.PackageFormData) {
+	ctx := c.Request().Context()
+
+	faqs, err := queries.ListPackageFAQs(ctx, form.ID)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch FAQs for package %d: %v", form.ID, err)
+	}
+	form.FAQs = faqs
+
+	groups, err := queries.ListGalleryGroupTitles(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch gallery groups: %v", err)
+	}
+	for _, g := range groups {
+		form.Projects = append(form.Projects, pages.PackageProjectOption{
+			ID:       g.ID,
+			Title:    g.Title,
+			Selected: g.PackageID.Valid && g.PackageID.Int64 == form.ID,
+			Other:    g.PackageID.Valid && g.PackageID.Int64 != form.ID,
+		})
+	}
+}
+
+func (h *Handler) CreatePackageFAQ(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	packageID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid package ID")
+	}
+
+	question, answer, sortOrder, ok := parsePackageFAQForm(c)
+	if !ok {
+		return c.String(http.StatusBadRequest, "Question and answer are required")
+	}
+
+	_, err = queries.CreatePackageFAQ(ctx, db.CreatePackageFAQParams{
+		PackageID: packageID,
+		Question:  question,
+		Answer:    answer,
+		SortOrder: sortOrder,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to add FAQ: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, packageEditURL(packageID))
+}
+
+func (h *Handler) UpdatePackageFAQ(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	packageID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid package ID")
+	}
+	faqID, err := strconv.ParseInt(c.Param("faqID"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid FAQ ID")
+	}
+
+	question, answer, sortOrder, ok := parsePackageFAQForm(c)
+	if !ok {
+		return c.String(http.StatusBadRequest, "Question and answer are required")
+	}
+
+	err = queries.UpdatePackageFAQ(ctx, db.UpdatePackageFAQParams{
+		Question:  question,
+		Answer:    answer,
+		SortOrder: sortOrder,
+		ID:        faqID,
+		PackageID: packageID,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update FAQ: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, packageEditURL(packageID))
+}
+
+func (h *Handler) DeletePackageFAQ(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	packageID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid package ID")
+	}
+	faqID, err := strconv.ParseInt(c.Param("faqID"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid FAQ ID")
+	}
+
+	err = queries.DeletePackageFAQ(ctx, db.DeletePackageFAQParams{ID: faqID, PackageID: packageID})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete FAQ: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, packageEditURL(packageID))
+}
+
+// UpdatePackageProjects sets which gallery projects show on a package's
+// service page. A project belongs to at most one package, so ticking one
+// here moves it off any other.
+func (h *Handler) UpdatePackageProjects(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	packageID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid package ID")
+	}
+
+	params, err := c.FormParams()
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid form")
+	}
+	var groupIDs []int64
+	for _, raw := range params["gallery_group_id"] {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid gallery group")
+		}
+		groupIDs = append(groupIDs, id)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update projects")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	if err := qtx.ClearPackageGalleryGroups(ctx, sql.NullInt64{Int64: packageID, Valid: true}); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update projects: %v", err))
+	}
+	for _, id := range groupIDs {
+		err := qtx.SetGalleryGroupPackage(ctx, db.SetGalleryGroupPackageParams{
+			PackageID: sql.NullInt64{Int64: packageID, Valid: true},
+			ID:        id,
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update projects: %v", err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update projects")
+	}
+
+	return c.Redirect(http.StatusSeeOther, packageEditURL(packageID))
+}
+
+func parsePackageFAQForm(c echo.Context) (question, answer string, sortOrder sql.NullInt64, ok bool) {
+	question = strings.TrimSpace(c.FormValue("question"))
+	answer = strings.TrimSpace(c.FormValue("answer"))
+	order, _ := strconv.ParseInt(c.FormValue("sort_order"), 10, 64)
+	return question, answer, sql.NullInt64{Int64: order, Valid: true}, question != "" && answer != ""
+}
+
+func packageEditURL(id int64) string {
+	return fmt.Sprintf("/admin/packages?edit=%d", id)
+}
@@ -0,0 +1,495 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/invoice"
+	"detailingpass/pkg/promo"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// redeemablePromo finds a code and checks it can be used now by email on
+// order. The error is one of the promo errors, which promoErrorMessage
+// words for the customer, or a database failure.
+func redeemablePromo(ctx context.Context, queries *db.Queries, raw string, email string, order promo.Order, now time.Time) (db.PromoCode, promo.Code, error) {
+	row, err := queries.GetPromoCodeByCode(ctx, promo.Normalize(raw))
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.PromoCode{}, promo.Code{}, promo.ErrUnknown
+	}
+	if err != nil {
+		return db.PromoCode{}, promo.Code{}, err
+	}
+	code, err := promoCodeRules(ctx, queries, row)
+	if err != nil {
+		return db.PromoCode{}, promo.Code{}, err
+	}
+
+	var usage promo.Usage
+	if usage.Total, err = queries.CountPromoRedemptions(ctx, row.ID); err != nil {
+		return db.PromoCode{}, promo.Code{}, err
+	}
+	usage.Customer, err = queries.CountCustomerPromoRedemptions(ctx, db.CountCustomerPromoRedemptionsParams{
+		PromoCodeID: row.ID,
+		Email:       strings.ToLower(strings.TrimSpace(email)),
+	})
+	if err != nil {
+		return db.PromoCode{}, promo.Code{}, err
+	}
+
+	if err := code.Check(now, order, usage); err != nil {
+		return db.PromoCode{}, promo.Code{}, err
+	}
+	return row, code, nil
+}
+
+// promoErrorMessages words the promo errors for customers.
+var promoErrorMessages = []struct {
+	err     error
+	message string
+}{
+	{promo.ErrUnknown, "That promo code isn't valid."},
+	{promo.ErrNotStarted, "That promo code isn't active yet."},
+	{promo.ErrExpired, "That promo code has expired."},
+	{promo.ErrPackage, "That promo code doesn't apply to the selected service."},
+	{promo.ErrMinSpend, "Your order doesn't reach that promo code's minimum spend."},
+	{promo.ErrUsedUp, "That promo code has reached its limit."},
+	{promo.ErrCustomerLimit, "You've already used that promo code."},
+}
+
+// promoErrorMessage is what to tell the customer when err is a promo
+// error, or "" for database failures.
+func promoErrorMessage(err error) string {
+	for _, m := range promoErrorMessages {
+		if errors.Is(err, m.err) {
+			return m.message
+		}
+	}
+	return ""
+}
+
+// redeemPromoForBooking records the discount on the booking, where the
+// invoice picks it up, and counts the use against the code. quoteID is set
+// when the code came from an accepted quote.
+func redeemPromoForBooking(ctx context.Context, queries *db.Queries, row db.PromoCode, booking db.Booking, quoteID sql.NullInt64) error {
+	err := queries.SetBookingPromo(ctx, db.SetBookingPromoParams{
+		PromoCode:     sql.NullString{String: row.Code, Valid: true},
+		DiscountType:  sql.NullString{String: row.DiscountType, Valid: true},
+		DiscountValue: row.DiscountValue,
+		ID:            booking.ID,
+	})
+	if err != nil {
+		return err
+	}
+	return queries.CreatePromoRedemption(ctx, db.CreatePromoRedemptionParams{
+		PromoCodeID: row.ID,
+		BookingID:   sql.NullInt64{Int64: booking.ID, Valid: true},
+		QuoteID:     quoteID,
+		Email:       strings.ToLower(strings.TrimSpace(booking.Email)),
+	})
+}
+
+func promoCodeRules(ctx context.Context, queries *db.Queries, row db.PromoCode) (promo.Code, error) {
+	packageIDs, err := queries.ListPromoCodePackageIDs(ctx, row.ID)
+	if err != nil {
+		return promo.Code{}, err
+	}
+	return promo.Code{
+		Code:           row.Code,
+		Kind:           row.DiscountType,
+		Value:          row.DiscountValue,
+		PackageIDs:     packageIDs,
+		StartsAt:       row.StartsAt.Time,
+		EndsAt:         row.EndsAt.Time,
+		MaxUses:        row.MaxUses,
+		MinSpend:       row.MinSpend,
+		MaxPerCustomer: row.MaxUsesPerCustomer,
+		Active:         row.IsActive.Bool,
+	}, nil
+}
+
+// AdminPromoCodes lists promo codes with how often they've been used, and
+// the form to add or edit one.
+func (h *Handler) AdminPromoCodes(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	rows, err := queries.ListPromoCodes(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch promo codes")
+	}
+	packages, err := queries.GetAllPackages(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch packages: %v", err)
+	}
+	packageNames := loadPromoPackageNames(c, queries)
+
+	data := pages.AdminPromoCodesData{
+		Packages:     packages,
+		Form:         pages.PromoCodeForm{Kind: invoice.DiscountPercent, Active: true},
+		ErrorMessage: c.QueryParam("error"),
+	}
+	now := time.Now()
+	for _, row := range rows {
+		data.Codes = append(data.Codes, promoCodeListItem(row, packageNames[row.ID], now))
+		if strconv.FormatInt(row.ID, 10) == c.QueryParam("edit") {
+			data.Form = promoCodeForm(ctx, queries, row)
+		}
+	}
+
+	return pages.AdminPromoCodes(data).Render(ctx, c.Response().Writer)
+}
+
+// AdminPromoCode shows every use of a code.
+func (h *Handler) AdminPromoCode(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid promo code ID")
+	}
+	stats, err := queries.GetPromoCodeStats(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Promo code not found")
+	}
+	row := db.ListPromoCodesRow(stats)
+	redemptions, err := queries.ListPromoRedemptions(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch redemptions")
+	}
+
+	data := pages.AdminPromoCodeData{
+		Code: promoCodeListItem(row, loadPromoPackageNames(c, queries)[id], time.Now()),
+	}
+	for _, r := range redemptions {
+		view := pages.PromoRedemptionView{
+			Customer:  r.CustomerName,
+			Email:     r.Email,
+			Status:    r.BookingStatus,
+			QuoteID:   r.QuoteID.Int64,
+			BookingID: r.BookingID.Int64,
+			When:      formatAdminTime(r.CreatedAt),
+		}
+		if r.RequestedStart.Valid {
+			view.Appointment = r.RequestedStart.Time.In(bookingLocation).Format("Mon, Jan 2 3:04 PM")
+		}
+		if view.Status == "" && !r.BookingID.Valid {
+			view.Status = "deleted"
+		}
+		data.Redemptions = append(data.Redemptions, view)
+	}
+
+	return pages.AdminPromoCode(data).Render(ctx, c.Response().Writer)
+}
+
+func loadPromoPackageNames(c echo.Context, queries *db.Queries) map[int64][]string {
+	names := make(map[int64][]string)
+	rows, err := queries.ListPromoCodePackageNames(c.Request().Context())
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch promo code packages: %v", err)
+	}
+	for _, row := range rows {
+		names[row.PromoCodeID] = append(names[row.PromoCodeID], row.Name)
+	}
+	return names
+}
+
+func promoCodeListItem(row db.ListPromoCodesRow, packages []string, now time.Time) pages.PromoCodeListItem {
+	return pages.PromoCodeListItem{
+		ID:             row.ID,
+		Code:           row.Code,
+		Description:    nullableString(row.Description),
+		Label:          promo.Label(row.DiscountType, row.DiscountValue),
+		Packages:       packages,
+		Window:         promoWindowLabel(row.StartsAt, row.EndsAt),
+		Status:         promoCodeStatus(row, now),
+		Redemptions:    row.Redemptions,
+		Cancelled:      row.Cancelled,
+		Customers:      row.Customers,
+		MaxUses:        row.MaxUses,
+		MaxPerCustomer: row.MaxUsesPerCustomer,
+		MinSpend:       row.MinSpend,
+	}
+}
+
+func promoCodeStatus(row db.ListPromoCodesRow, now time.Time) string {
+	switch {
+	case !row.IsActive.Bool:
+		return "inactive"
+	case row.StartsAt.Valid && now.Before(row.StartsAt.Time):
+		return "scheduled"
+	case row.EndsAt.Valid && !now.Before(row.EndsAt.Time):
+		return "expired"
+	case row.MaxUses > 0 && row.Redemptions >= row.MaxUses:
+		return "used up"
+	default:
+		return "active"
+	}
+}
+
+// promoWindowLabel shows the dates a code runs, both inclusive.
+func promoWindowLabel(starts, ends sql.NullTime) string {
+	const layout = "Jan 2, 2006"
+	switch {
+	case starts.Valid && ends.Valid:
+		return starts.Time.In(bookingLocation).Format(layout) + " – " + promoLastDay(ends).Format(layout)
+	case starts.Valid:
+		return "From " + starts.Time.In(bookingLocation).Format(layout)
+	case ends.Valid:
+		return "Until " + promoLastDay(ends).Format(layout)
+	default:
+		return "No end date"
+	}
+}
+
+// promoLastDay is the last day a code works. ends_at is the start of the
+// day after, in the shop's timezone.
+func promoLastDay(ends sql.NullTime) time.Time {
+	return ends.Time.In(bookingLocation).AddDate(0, 0, -1)
+}
+
+func promoCodeForm(ctx context.Context, queries *db.Queries, row db.ListPromoCodesRow) pages.PromoCodeForm {
+	form := pages.PromoCodeForm{
+		ID:          row.ID,
+		Code:        row.Code,
+		Description: nullableString(row.Description),
+		Kind:        row.DiscountType,
+		Active:      row.IsActive.Bool,
+		PackageIDs:  make(map[int64]bool),
+	}
+	if row.DiscountType == invoice.DiscountPercent {
+		form.Value = strings.TrimSuffix(invoice.FormatRate(row.DiscountValue), "%")
+	} else {
+		form.Value = fmt.Sprintf("%.2f", float64(row.DiscountValue)/100)
+	}
+	if row.StartsAt.Valid {
+		form.StartsOn = row.StartsAt.Time.In(bookingLocation).Format("2006-01-02")
+	}
+	if row.EndsAt.Valid {
+		form.EndsOn = promoLastDay(row.EndsAt).Format("2006-01-02")
+	}
+	if row.MaxUses > 0 {
+		form.MaxUses = strconv.FormatInt(row.MaxUses, 10)
+	}
+	if row.MaxUsesPerCustomer > 0 {
+		form.MaxPerCustomer = strconv.FormatInt(row.MaxUsesPerCustomer, 10)
+	}
+	if row.MinSpend > 0 {
+		form.MinSpend = fmt.Sprintf("%.2f", float64(row.MinSpend)/100)
+	}
+	ids, _ := queries.ListPromoCodePackageIDs(ctx, row.ID)
+	for _, id := range ids {
+		form.PackageIDs[id] = true
+	}
+	return form
+}
+
+func (h *Handler) CreatePromoCode(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	params, packageIDs, errMsg := parsePromoCodeForm(c)
+	if errMsg != "" {
+		return promoCodesRedirect(c, errMsg)
+	}
+	if _, err := queries.GetPromoCodeByCode(ctx, params.Code); err == nil {
+		return promoCodesRedirect(c, "There's already a promo code "+params.Code)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create promo code")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	code, err := qtx.CreatePromoCode(ctx, params)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create promo code: %v", err))
+	}
+	if err := setPromoCodePackages(ctx, qtx, code.ID, packageIDs); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create promo code")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create promo code")
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/promo-codes")
+}
+
+// UpdatePromoCode changes a code's rules. Bookings already made keep the
+// discount they were given.
+func (h *Handler) UpdatePromoCode(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid promo code ID")
+	}
+	params, packageIDs, errMsg := parsePromoCodeForm(c)
+	if errMsg != "" {
+		return promoCodesRedirect(c, errMsg)
+	}
+	if existing, err := queries.GetPromoCodeByCode(ctx, params.Code); err == nil && existing.ID != id {
+		return promoCodesRedirect(c, "There's already a promo code "+params.Code)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update promo code")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	err = qtx.UpdatePromoCode(ctx, db.UpdatePromoCodeParams{
+		Code:               params.Code,
+		Description:        params.Description,
+		DiscountType:       params.DiscountType,
+		DiscountValue:      params.DiscountValue,
+		StartsAt:           params.StartsAt,
+		EndsAt:             params.EndsAt,
+		MaxUses:            params.MaxUses,
+		MaxUsesPerCustomer: params.MaxUsesPerCustomer,
+		MinSpend:           params.MinSpend,
+		IsActive:           params.IsActive,
+		ID:                 id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update promo code: %v", err))
+	}
+	if err := setPromoCodePackages(ctx, qtx, id, packageIDs); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update promo code")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update promo code")
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/promo-codes")
+}
+
+func (h *Handler) DeletePromoCode(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid promo code ID")
+	}
+	deleted, err := queries.DeleteUnusedPromoCode(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete promo code: %v", err))
+	}
+	if deleted == 0 {
+		return promoCodesRedirect(c, "This code has been used, so it's kept for its history. Deactivate it instead.")
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/promo-codes")
+}
+
+func setPromoCodePackages(ctx context.Context, queries *db.Queries, id int64, packageIDs []int64) error {
+	if err := queries.ClearPromoCodePackages(ctx, id); err != nil {
+		return err
+	}
+	for _, packageID := range packageIDs {
+		err := queries.AddPromoCodePackage(ctx, db.AddPromoCodePackageParams{PromoCodeID: id, PackageID: packageID})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parsePromoCodeForm(c echo.Context) (db.CreatePromoCodeParams, []int64, string) {
+	code := promo.Normalize(c.FormValue("code"))
+	if !promoCodePattern.MatchString(code) {
+		return db.CreatePromoCodeParams{}, nil, "Codes are 3 to 32 letters, numbers, dashes or underscores"
+	}
+
+	description := strings.TrimSpace(c.FormValue("description"))
+	params := db.CreatePromoCodeParams{
+		Code:         code,
+		Description:  sql.NullString{String: description, Valid: description != ""},
+		DiscountType: c.FormValue("discount_type"),
+		IsActive:     sql.NullBool{Bool: c.FormValue("is_active") == "true", Valid: true},
+	}
+	var err error
+	switch params.DiscountType {
+	case invoice.DiscountPercent:
+		params.DiscountValue, err = parsePercent(c.FormValue("discount_value"))
+	case invoice.DiscountAmount:
+		params.DiscountValue, err = parseDollars(c.FormValue("discount_value"))
+	default:
+		return db.CreatePromoCodeParams{}, nil, "Choose a percentage or fixed discount"
+	}
+	if err != nil || params.DiscountValue == 0 {
+		return db.CreatePromoCodeParams{}, nil, "Enter the discount as a percentage or dollar amount"
+	}
+
+	if raw := strings.TrimSpace(c.FormValue("starts_on")); raw != "" {
+		day, err := time.ParseInLocation("2006-01-02", raw, bookingLocation)
+		if err != nil {
+			return db.CreatePromoCodeParams{}, nil, "Invalid start date"
+		}
+		params.StartsAt = sql.NullTime{Time: day.UTC(), Valid: true}
+	}
+	if raw := strings.TrimSpace(c.FormValue("ends_on")); raw != "" {
+		day, err := time.ParseInLocation("2006-01-02", raw, bookingLocation)
+		if err != nil {
+			return db.CreatePromoCodeParams{}, nil, "Invalid end date"
+		}
+		params.EndsAt = sql.NullTime{Time: day.AddDate(0, 0, 1).UTC(), Valid: true}
+	}
+	if params.StartsAt.Valid && params.EndsAt.Valid && !params.EndsAt.Time.After(params.StartsAt.Time) {
+		return db.CreatePromoCodeParams{}, nil, "The end date can't be before the start date"
+	}
+
+	for _, field := range []struct {
+		name string
+		dest *int64
+	}{
+		{"max_uses", &params.MaxUses},
+		{"max_uses_per_customer", &params.MaxUsesPerCustomer},
+	} {
+		raw := strings.TrimSpace(c.FormValue(field.name))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			return db.CreatePromoCodeParams{}, nil, "Limits must be whole numbers; leave blank for no limit"
+		}
+		*field.dest = n
+	}
+	if params.MinSpend, err = parseDollars(c.FormValue("min_spend")); err != nil {
+		return db.CreatePromoCodeParams{}, nil, "Enter the minimum spend as a dollar amount; leave blank for none"
+	}
+
+	form, _ := c.FormParams()
+	var packageIDs []int64
+	for _, raw := range form["package_id"] {
+		if id, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			packageIDs = append(packageIDs, id)
+		}
+	}
+
+	return params, packageIDs, ""
+}
+
+func promoCodesRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/promo-codes?error="+url.QueryEscape(errMsg))
+}
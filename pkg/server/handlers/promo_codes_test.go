@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/invoice"
+	"detailingpass/pkg/promo"
+)
+
+func TestRedeemablePromo(t *testing.T) {
+	queries := newTestQueries(t)
+	ctx := context.Background()
+	now := time.Date(2026, 4, 10, 15, 0, 0, 0, time.UTC)
+
+	for _, params := range []db.CreatePromoCodeParams{
+		{Code: "SPRING15", DiscountType: invoice.DiscountPercent, DiscountValue: 1500, MinSpend: 20000, MaxUsesPerCustomer: 1},
+		{Code: "OLD", DiscountType: invoice.DiscountAmount, DiscountValue: 2000, EndsAt: sql.NullTime{Time: now.AddDate(0, 0, -1), Valid: true}},
+	} {
+		params.IsActive = sql.NullBool{Bool: true, Valid: true}
+		if _, err := queries.CreatePromoCode(ctx, params); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spring, err := queries.GetPromoCodeByCode(ctx, "SPRING15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = queries.CreatePromoRedemption(ctx, db.CreatePromoRedemptionParams{PromoCodeID: spring.ID, Email: "used@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		code    string
+		email   string
+		order   promo.Order
+		wantErr error
+		wantMsg string
+	}{
+		{name: "redeemable", code: " spring15 ", email: "new@example.com", order: promo.Order{Subtotal: 25000}},
+		{name: "unknown", code: "NOPE", email: "new@example.com", order: promo.Order{Subtotal: 25000}, wantErr: promo.ErrUnknown, wantMsg: "That promo code isn't valid."},
+		{name: "expired", code: "OLD", email: "new@example.com", order: promo.Order{Subtotal: 25000}, wantErr: promo.ErrExpired, wantMsg: "That promo code has expired."},
+		{name: "under the minimum", code: "SPRING15", email: "new@example.com", order: promo.Order{Subtotal: 19999}, wantErr: promo.ErrMinSpend, wantMsg: "Your order doesn't reach that promo code's minimum spend."},
+		{name: "already used", code: "SPRING15", email: " Used@Example.com", order: promo.Order{Subtotal: 25000}, wantErr: promo.ErrCustomerLimit, wantMsg: "You've already used that promo code."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row, _, err := redeemablePromo(ctx, queries, tt.code, tt.email, tt.order, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got := promoErrorMessage(err); got != tt.wantMsg {
+				t.Errorf("promoErrorMessage = %q, want %q", got, tt.wantMsg)
+			}
+			if err == nil && row.ID != spring.ID {
+				t.Errorf("redeemed %+v, want SPRING15", row)
+			}
+		})
+	}
+
+	if msg := promoErrorMessage(sql.ErrConnDone); msg != "" {
+		t.Errorf("database failure shown to the customer as %q", msg)
+	}
+}
@@ -0,0 +1,1091 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/invoice"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+	"detailingpass/pkg/promo"
+	"detailingpass/pkg/staffing"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	quoteLinkPurpose    = "quote"
+	quoteValidity       = 14 * 24 * time.Hour
+	quoteLinkGrace      = 60 * 24 * time.Hour
+	quoteSlotPickerDays = 21
+	adminQuotesLimit    = 200
+)
+
+var quoteStatuses = []string{"draft", "sent", "accepted", "declined"}
+
+// quoteVersionItem is a line as it was sent, kept in quote_versions.items
+type quoteVersionItem struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+}
+
+// AdminQuotes lists quotes, optionally by status, with the new quote form.
+func (h *Handler) AdminQuotes(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	filter := c.QueryParam("status")
+	data := pages.AdminQuotesData{
+		Statuses:     quoteStatuses,
+		Counts:       map[string]int64{},
+		ErrorMessage: c.QueryParam("error"),
+	}
+
+	counts, err := queries.CountQuotesByStatus(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch quotes")
+	}
+	for _, row := range counts {
+		data.Counts[row.Status.String] = row.Count
+		data.Total += row.Count
+	}
+
+	for _, status := range quoteStatuses {
+		if filter == status {
+			data.Filter = filter
+		}
+	}
+
+	var rows []db.ListQuotesRow
+	if data.Filter != "" {
+		filtered, err := queries.ListQuotesByStatus(ctx, db.ListQuotesByStatusParams{
+			Status: sql.NullString{String: filter, Valid: true},
+			Limit:  adminQuotesLimit,
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to fetch quotes")
+		}
+		for _, row := range filtered {
+			rows = append(rows, db.ListQuotesRow(row))
+		}
+	} else {
+		rows, err = queries.ListQuotes(ctx, adminQuotesLimit)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to fetch quotes")
+		}
+	}
+
+	now := time.Now()
+	for _, row := range rows {
+		totals := invoice.Compute([]invoice.Line{{Quantity: 1, UnitPrice: row.Total}},
+			invoice.Discount{Kind: row.DiscountType.String, Value: row.DiscountValue}, 0)
+		data.Quotes = append(data.Quotes, pages.QuoteListItem{
+			ID:           row.ID,
+			CustomerName: row.CustomerName,
+			Email:        row.Email,
+			Status:       quoteDisplayStatus(db.Quote{Status: row.Status, ExpiresAt: row.ExpiresAt}, now),
+			Version:      row.Version.Int64,
+			Total:        totals.Total,
+			PromoCode:    nullableString(row.PromoCode),
+			CreatedAt:    formatAdminTime(row.CreatedAt),
+			ValidUntil:   quoteValidUntilLabel(row.ExpiresAt),
+		})
+	}
+
+	return pages.AdminQuotes(data).Render(ctx, c.Response().Writer)
+}
+
+// quoteDisplayStatus is the stored status, except that a sent quote past
+// its expiry shows as expired.
+func quoteDisplayStatus(q db.Quote, now time.Time) string {
+	if q.Status.String == "sent" && q.ExpiresAt.Valid && !q.ExpiresAt.Time.After(now) {
+		return "expired"
+	}
+	return q.Status.String
+}
+
+func quoteValidUntilLabel(expires sql.NullTime) string {
+	if !expires.Valid {
+		return ""
+	}
+	return expires.Time.In(bookingLocation).Format("January 2, 2006")
+}
+
+// CreateQuote starts a draft quote for a customer.
+func (h *Handler) CreateQuote(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	details, errMsg := parseQuoteDetails(c)
+	if errMsg != "" {
+		return c.Redirect(http.StatusSeeOther, "/admin/quotes?error="+url.QueryEscape(errMsg))
+	}
+
+	quote, err := queries.CreateQuote(ctx, db.CreateQuoteParams{
+		CustomerName:   details.CustomerName,
+		Email:          details.Email,
+		Phone:          details.Phone,
+		VehicleDetails: details.VehicleDetails,
+		Notes:          details.Notes,
+		ExpiresAt:      details.ExpiresAt,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create quote: %v", err))
+	}
+
+	return quoteRedirect(c, quote.ID, "")
+}
+
+// CreateQuoteFromMessage starts a draft quote for the sender of a contact
+// message. When they picked a package on the form it becomes the first line.
+func (h *Handler) CreateQuoteFromMessage(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid message ID")
+	}
+	msg, err := queries.GetContactMessageByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Message not found")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create quote")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	quote, err := qtx.CreateQuote(ctx, db.CreateQuoteParams{
+		CustomerName:     msg.Name,
+		Email:            msg.Email,
+		Phone:            msg.Phone,
+		ExpiresAt:        sql.NullTime{Time: defaultQuoteExpiry(time.Now()), Valid: true},
+		ContactMessageID: sql.NullInt64{Int64: msg.ID, Valid: true},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create quote: %v", err))
+	}
+	if msg.ServiceInterest.Valid {
+		if pkg, err := qtx.GetPackageBySlug(ctx, msg.ServiceInterest.String); err == nil {
+			_, err := qtx.CreateQuoteItem(ctx, db.CreateQuoteItemParams{
+				QuoteID:     quote.ID,
+				Kind:        "package",
+				PackageID:   sql.NullInt64{Int64: pkg.ID, Valid: true},
+				Description: pkg.Name,
+				Quantity:    1,
+				UnitPrice:   pkg.PriceMin.Int64,
+			})
+			if err != nil {
+				return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create quote: %v", err))
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create quote")
+	}
+
+	return quoteRedirect(c, quote.ID, "")
+}
+
+// AdminQuote is the quote editor: details, line items, sending and the
+// history of versions sent.
+func (h *Handler) AdminQuote(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid quote ID")
+	}
+	quote, err := queries.GetQuoteByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Quote not found")
+	}
+	items, err := queries.ListQuoteItems(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch quote items")
+	}
+
+	data := pages.AdminQuoteData{
+		Quote:           quote,
+		Status:          quoteDisplayStatus(quote, time.Now()),
+		Editable:        quoteEditable(quote),
+		ValidUntilLabel: quoteValidUntilLabel(quote.ExpiresAt),
+		SentAt:          formatAdminTime(quote.SentAt),
+		ErrorMessage:    c.QueryParam("error"),
+	}
+	data.Lines, _ = quoteLines(items)
+	data.Totals = quoteTotals(quote, items)
+	data.PromoCode = nullableString(quote.PromoCode)
+	data.DiscountLabel = quoteDiscountLabel(quote)
+	if quote.ExpiresAt.Valid {
+		data.ValidUntil = quote.ExpiresAt.Time.In(bookingLocation).Format("2006-01-02")
+	}
+	if quote.Status.String == "sent" && quote.ExpiresAt.Valid {
+		data.CustomerLink = h.quoteLink(quote)
+	}
+	if quote.BookingID.Valid {
+		if booking, err := queries.GetBookingByID(ctx, quote.BookingID.Int64); err == nil {
+			data.BookingLabel = fmt.Sprintf("%s (%s)",
+				booking.RequestedStart.In(bookingLocation).Format("Monday, January 2 at 3:04 PM"),
+				normalizeBookingStatus(booking.Status.String))
+		}
+	}
+
+	if data.Editable {
+		if data.Packages, err = queries.GetAllPackages(ctx); err != nil {
+			c.Logger().Warnf("Failed to fetch packages: %v", err)
+		}
+		if data.Addons, err = queries.ListActiveAddons(ctx); err != nil {
+			c.Logger().Warnf("Failed to fetch add-ons: %v", err)
+		}
+	}
+
+	versions, err := queries.ListQuoteVersions(ctx, id)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch versions for quote %d: %v", id, err)
+	}
+	for _, v := range versions {
+		view := pages.QuoteVersionView{
+			Version:    v.Version,
+			Total:      v.Total,
+			SentAt:     formatAdminTime(v.CreatedAt),
+			ValidUntil: quoteValidUntilLabel(v.ExpiresAt),
+		}
+		var sent []quoteVersionItem
+		if err := json.Unmarshal([]byte(v.Items), &sent); err != nil {
+			c.Logger().Warnf("Failed to read version %d of quote %d: %v", v.Version, id, err)
+		}
+		for _, item := range sent {
+			view.Lines = append(view.Lines, pages.LineItem{
+				Kind:        item.Kind,
+				Description: item.Description,
+				Quantity:    item.Quantity,
+				UnitPrice:   item.UnitPrice,
+				Amount:      item.Quantity * item.UnitPrice,
+			})
+			view.Subtotal += item.Quantity * item.UnitPrice
+		}
+		data.Versions = append(data.Versions, view)
+	}
+
+	return pages.AdminQuote(data).Render(ctx, c.Response().Writer)
+}
+
+func quoteEditable(q db.Quote) bool {
+	return q.Status.String == "draft" || q.Status.String == "sent"
+}
+
+// quoteTotals is the quote's subtotal less any promo code. Quotes carry no
+// tax; that is added on the invoice.
+func quoteTotals(q db.Quote, items []db.QuoteItem) invoice.Totals {
+	lines := make([]invoice.Line, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, invoice.Line{Quantity: item.Quantity, UnitPrice: item.UnitPrice})
+	}
+	return invoice.Compute(lines, invoice.Discount{Kind: q.DiscountType.String, Value: q.DiscountValue}, 0)
+}
+
+// quoteDiscountLabel names the promo line, e.g. "Promo SPRING (15% off)".
+func quoteDiscountLabel(q db.Quote) string {
+	if !q.PromoCode.Valid {
+		return ""
+	}
+	return fmt.Sprintf("Promo %s (%s)", q.PromoCode.String, promo.Label(q.DiscountType.String, q.DiscountValue))
+}
+
+// quotePromoOrder is what a promo code on the quote applies to: its
+// packages, for codes limited to some packages, and its subtotal.
+func quotePromoOrder(items []db.QuoteItem) promo.Order {
+	var order promo.Order
+	for _, item := range items {
+		if item.PackageID.Valid {
+			order.PackageIDs = append(order.PackageIDs, item.PackageID.Int64)
+		}
+		order.Subtotal += item.Quantity * item.UnitPrice
+	}
+	return order
+}
+
+func quoteLines(items []db.QuoteItem) ([]pages.LineItem, int64) {
+	lines := make([]pages.LineItem, 0, len(items))
+	var total int64
+	for _, item := range items {
+		amount := item.Quantity * item.UnitPrice
+		lines = append(lines, pages.LineItem{
+			ID:          item.ID,
+			Kind:        item.Kind,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      amount,
+		})
+		total += amount
+	}
+	return lines, total
+}
+
+// UpdateQuote saves the customer details and expiry. Changing a sent quote
+// takes it back to draft until it is sent again.
+func (h *Handler) UpdateQuote(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid quote ID")
+	}
+	details, errMsg := parseQuoteDetails(c)
+	if errMsg != "" {
+		return quoteRedirect(c, id, errMsg)
+	}
+
+	err = queries.UpdateQuoteDetails(ctx, db.UpdateQuoteDetailsParams{
+		CustomerName:   details.CustomerName,
+		Email:          details.Email,
+		Phone:          details.Phone,
+		VehicleDetails: details.VehicleDetails,
+		Notes:          details.Notes,
+		ExpiresAt:      details.ExpiresAt,
+		ID:             id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update quote: %v", err))
+	}
+
+	return quoteRedirect(c, id, "")
+}
+
+func parseQuoteDetails(c echo.Context) (db.CreateQuoteParams, string) {
+	name := strings.TrimSpace(c.FormValue("customer_name"))
+	email := strings.TrimSpace(strings.ToLower(c.FormValue("email")))
+	if name == "" || email == "" {
+		return db.CreateQuoteParams{}, "Customer name and email are required"
+	}
+	if !strings.Contains(email, "@") || strings.ContainsAny(email, " \r\n") {
+		return db.CreateQuoteParams{}, "Enter a valid email address"
+	}
+
+	expires := defaultQuoteExpiry(time.Now())
+	if raw := c.FormValue("valid_until"); raw != "" {
+		day, err := time.ParseInLocation("2006-01-02", raw, bookingLocation)
+		if err != nil {
+			return db.CreateQuoteParams{}, "Enter a valid expiry date"
+		}
+		expires = endOfDay(day)
+	}
+
+	phone := strings.TrimSpace(c.FormValue("phone"))
+	vehicle := strings.TrimSpace(c.FormValue("vehicle_details"))
+	notes := strings.TrimSpace(c.FormValue("notes"))
+	return db.CreateQuoteParams{
+		CustomerName:   name,
+		Email:          email,
+		Phone:          sql.NullString{String: phone, Valid: phone != ""},
+		VehicleDetails: sql.NullString{String: vehicle, Valid: vehicle != ""},
+		Notes:          sql.NullString{String: notes, Valid: notes != ""},
+		ExpiresAt:      sql.NullTime{Time: expires, Valid: true},
+	}, ""
+}
+
+// defaultQuoteExpiry is the end of the day quoteValidity from now, in the
+// shop's timezone.
+func defaultQuoteExpiry(now time.Time) time.Time {
+	return endOfDay(now.In(bookingLocation).Add(quoteValidity))
+}
+
+func endOfDay(day time.Time) time.Time {
+	day = day.In(bookingLocation)
+	return time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, bookingLocation).UTC()
+}
+
+// AddQuoteItem adds a package, add-on or custom line.
+func (h *Handler) AddQuoteItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid quote ID")
+	}
+	quote, err := queries.GetQuoteByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Quote not found")
+	}
+	if !quoteEditable(quote) {
+		return quoteRedirect(c, id, "This quote can no longer be changed")
+	}
+
+	item, errMsg := parseLineItem(c, queries)
+	if errMsg != "" {
+		return quoteRedirect(c, id, errMsg)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to add line")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	_, err = qtx.CreateQuoteItem(ctx, db.CreateQuoteItemParams{
+		QuoteID:     id,
+		Kind:        item.Kind,
+		PackageID:   item.PackageID,
+		AddonID:     item.AddonID,
+		Description: item.Description,
+		Quantity:    item.Quantity,
+		UnitPrice:   item.UnitPrice,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to add line: %v", err))
+	}
+	if err := qtx.ReviseQuote(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to add line")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to add line")
+	}
+
+	return quoteRedirect(c, id, "")
+}
+
+func (h *Handler) DeleteQuoteItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid quote ID")
+	}
+	itemID, err := strconv.ParseInt(c.Param("itemID"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid line ID")
+	}
+	quote, err := queries.GetQuoteByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Quote not found")
+	}
+	if !quoteEditable(quote) {
+		return quoteRedirect(c, id, "This quote can no longer be changed")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to remove line")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	if err := qtx.DeleteQuoteItem(ctx, db.DeleteQuoteItemParams{ID: itemID, QuoteID: id}); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to remove line: %v", err))
+	}
+	if err := qtx.ReviseQuote(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to remove line")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to remove line")
+	}
+
+	return quoteRedirect(c, id, "")
+}
+
+// ApplyQuotePromo puts a promo code on a quote, or takes it off when the
+// code is blank. The code is checked against the quote's packages and
+// customer now, and again when the quote is sent.
+func (h *Handler) ApplyQuotePromo(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid quote ID")
+	}
+	quote, err := queries.GetQuoteByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Quote not found")
+	}
+	if !quoteEditable(quote) {
+		return quoteRedirect(c, id, "This quote can no longer be changed")
+	}
+
+	var params db.SetQuotePromoParams
+	if raw := c.FormValue("promo_code"); strings.TrimSpace(raw) != "" {
+		items, err := queries.ListQuoteItems(ctx, id)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to fetch quote items")
+		}
+		row, _, err := redeemablePromo(ctx, queries, raw, quote.Email, quotePromoOrder(items), time.Now())
+		if msg := promoErrorMessage(err); msg != "" {
+			return quoteRedirect(c, id, msg)
+		}
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to apply promo code")
+		}
+		params = db.SetQuotePromoParams{
+			PromoCodeID:   sql.NullInt64{Int64: row.ID, Valid: true},
+			PromoCode:     sql.NullString{String: row.Code, Valid: true},
+			DiscountType:  sql.NullString{String: row.DiscountType, Valid: true},
+			DiscountValue: row.DiscountValue,
+		}
+	}
+	params.ID = id
+	if err := queries.SetQuotePromo(ctx, params); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to apply promo code: %v", err))
+	}
+
+	return quoteRedirect(c, id, "")
+}
+
+// SendQuote emails the customer a link to the quote and records what was
+// sent as a new version. Sending again after changes sends a revision.
+func (h *Handler) SendQuote(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid quote ID")
+	}
+	quote, err := queries.GetQuoteByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Quote not found")
+	}
+	if !quoteEditable(quote) {
+		return quoteRedirect(c, id, "This quote has already been answered")
+	}
+	if !quote.ExpiresAt.Valid || !quote.ExpiresAt.Time.After(time.Now()) {
+		return quoteRedirect(c, id, "Set an expiry date in the future before sending")
+	}
+	items, err := queries.ListQuoteItems(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch quote items")
+	}
+	if len(items) == 0 {
+		return quoteRedirect(c, id, "Add at least one line before sending")
+	}
+	if quote.PromoCode.Valid {
+		_, _, err := redeemablePromo(ctx, queries, quote.PromoCode.String, quote.Email, quotePromoOrder(items), time.Now())
+		if msg := promoErrorMessage(err); msg != "" {
+			return quoteRedirect(c, id, fmt.Sprintf("Promo code %s can't be used: %s Remove it before sending.", quote.PromoCode.String, msg))
+		}
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to send quote")
+		}
+	}
+
+	version := quote.Version.Int64 + 1
+	snapshot := make([]quoteVersionItem, 0, len(items))
+	for _, item := range items {
+		snapshot = append(snapshot, quoteVersionItem{
+			Kind:        item.Kind,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+		})
+	}
+	totals := quoteTotals(quote, items)
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to send quote")
+	}
+
+	if err := h.sendQuoteEmail(ctx, quote, items, totals, version); err != nil {
+		return quoteRedirect(c, id, fmt.Sprintf("Failed to send quote: %v", err))
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Quote sent but could not be saved")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	_, err = qtx.CreateQuoteVersion(ctx, db.CreateQuoteVersionParams{
+		QuoteID:   id,
+		Version:   version,
+		Items:     string(encoded),
+		Total:     totals.Total,
+		ExpiresAt: quote.ExpiresAt,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Quote sent but could not be saved")
+	}
+	err = qtx.MarkQuoteSent(ctx, db.MarkQuoteSentParams{
+		Version:   sql.NullInt64{Int64: version, Valid: true},
+		ExpiresAt: quote.ExpiresAt,
+		ID:        id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Quote sent but could not be saved")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Quote sent but could not be saved")
+	}
+
+	return quoteRedirect(c, id, "")
+}
+
+func (h *Handler) sendQuoteEmail(ctx context.Context, quote db.Quote, items []db.QuoteItem, totals invoice.Totals, version int64) error {
+	if h.links == nil {
+		return errLinksOff
+	}
+	var lines strings.Builder
+	for _, item := range items {
+		if item.Quantity > 1 {
+			fmt.Fprintf(&lines, "  %s x%d  %s\n", item.Description, item.Quantity, pages.FormatMoney(item.Quantity*item.UnitPrice))
+		} else {
+			fmt.Fprintf(&lines, "  %s  %s\n", item.Description, pages.FormatMoney(item.UnitPrice))
+		}
+	}
+	if totals.Discount > 0 {
+		fmt.Fprintf(&lines, "\n  Subtotal: %s\n  %s: -%s", pages.FormatMoney(totals.Subtotal), quoteDiscountLabel(quote), pages.FormatMoney(totals.Discount))
+	}
+
+	intro := "Here is your quote"
+	subject := fmt.Sprintf("Your quote from C Auto Detailing Studio (#%d)", quote.ID)
+	if version > 1 {
+		intro = "Here is your updated quote"
+		subject = fmt.Sprintf("Your updated quote from C Auto Detailing Studio (#%d)", quote.ID)
+	}
+	text := fmt.Sprintf(
+		"Hi %s,\n\n%s:\n\n%s\n  Total: %s\n\n"+
+			"To accept it and choose a time for your appointment, open:\n%s\n\n"+
+			"This quote is valid until %s.\n",
+		customerFirstName(quote.CustomerName),
+		intro,
+		lines.String(),
+		pages.FormatMoney(totals.Total),
+		h.quoteLink(quote),
+		quoteValidUntilLabel(quote.ExpiresAt),
+	)
+	return h.mailer.Send(ctx, mailer.Message{
+		To:      []string{quote.Email},
+		ReplyTo: h.contactEmail,
+		Subject: subject,
+		Text:    text,
+	})
+}
+
+// quoteLink is the customer's link to a quote. It outlives the quote by
+// quoteLinkGrace so an accepted quote can still be looked up afterwards.
+func (h *Handler) quoteLink(quote db.Quote) string {
+	return h.customerLink("/quote/", quoteLinkPurpose, quote.ID, quote.ExpiresAt.Time.Add(quoteLinkGrace))
+}
+
+func (h *Handler) DeleteQuote(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid quote ID")
+	}
+	if err := queries.DeleteQuote(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete quote: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/quotes")
+}
+
+func quoteRedirect(c echo.Context, id int64, errMsg string) error {
+	target := fmt.Sprintf("/admin/quotes/%d", id)
+	if errMsg != "" {
+		target += "?error=" + url.QueryEscape(errMsg)
+	}
+	return c.Redirect(http.StatusSeeOther, target)
+}
+
+// QuotePage is the customer's view of a quote, where they can accept it
+// and pick a slot, or decline it.
+func (h *Handler) QuotePage(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	data, _ := h.loadQuotePage(c, queries, c.Param("token"))
+	return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+}
+
+// AcceptQuote books the chosen slot and marks the quote accepted. The
+// booking waits for confirmation like any other request.
+func (h *Handler) AcceptQuote(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	data, quote := h.loadQuotePage(c, queries, c.Param("token"))
+	if data.State != pages.QuoteLinkOpen {
+		return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+	}
+
+	data.Selected = c.FormValue("start")
+	data.Mobile = c.FormValue("service_location") == "mobile"
+	data.Address = strings.TrimSpace(c.FormValue("address"))
+	data.ZIP = strings.TrimSpace(c.FormValue("zip"))
+	start, err := time.Parse(time.RFC3339, data.Selected)
+	if err != nil {
+		data.Error = "Please choose a time for your appointment."
+		return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+	}
+	slotDef, ok := matchSlotDefinition(start)
+	startLocal := start.In(bookingLocation)
+	now := time.Now()
+	switch {
+	case !ok || startLocal.Second() != 0:
+		data.Error = "Please choose one of the listed times."
+	case !startLocal.After(now):
+		data.Error = "That time has passed. Please choose another."
+	case startLocal.After(now.AddDate(0, 0, bookingHorizon(ctx, queries))) || closedDays[startLocal.Weekday()]:
+		data.Error = "That time isn't available. Please choose another."
+	}
+	if data.Error != "" {
+		return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+	}
+
+	trip, err := h.mobileTrip(bookingRequest{Mobile: data.Mobile, Address: data.Address, ZIP: data.ZIP})
+	if err != nil {
+		data.Error = err.Error()
+		return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+	}
+
+	items, err := queries.ListQuoteItems(ctx, quote.ID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to accept quote")
+	}
+	service, err := quoteService(ctx, queries, items)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to accept quote")
+	}
+	notes := fmt.Sprintf("Accepted quote #%d (version %d).", quote.ID, quote.Version.Int64)
+	if quote.Notes.Valid {
+		notes += "\n\n" + quote.Notes.String
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to accept quote")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	taken, err := h.slotTakenFor(ctx, qtx, start, start.Add(slotDef.Duration), time.Now(), "", jobNeeds{skills: service.skills, stop: trip.Stop})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to accept quote")
+	}
+	if taken {
+		// Done with the transaction before listing the other slots
+		tx.Rollback()
+		data.Error = "That time has just been taken. Please choose another."
+		if data.Mobile {
+			data.Error = "We can't fit your job in then, or can't get to you in time between other jobs. Please choose another time."
+		}
+		data.Days = h.quoteSlotDays(c, queries, service.skills)
+		return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+	}
+
+	booking, err := qtx.CreateBooking(ctx, db.CreateBookingParams{
+		CustomerName:    quote.CustomerName,
+		Email:           quote.Email,
+		Phone:           quote.Phone,
+		VehicleDetails:  quote.VehicleDetails,
+		ServiceInterest: service.slug,
+		Notes:           sql.NullString{String: notes, Valid: true},
+		RequestedStart:  start.UTC(),
+		RequestedEnd:    start.UTC().Add(slotDef.Duration),
+		Status:          sql.NullString{String: "pending", Valid: true},
+		Source:          sql.NullString{String: "quote", Valid: true},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to accept quote")
+	}
+	accepted, err := qtx.AcceptQuote(ctx, db.AcceptQuoteParams{
+		BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+		ID:        quote.ID,
+		ExpiresAt: sql.NullTime{Time: now.UTC(), Valid: true},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to accept quote")
+	}
+	if accepted == 0 {
+		// Answered, revised or expired since the page loaded
+		data, _ = h.loadQuotePage(c, queries, c.Param("token"))
+		return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+	}
+	if err := redeemQuotePromo(ctx, qtx, quote, booking); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to accept quote")
+	}
+	if data.Mobile {
+		if err := qtx.SetBookingServiceLocation(ctx, serviceLocationParams(booking.ID, data.Address, trip)); err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to accept quote")
+		}
+	}
+	// The deposit is taken as for any other booking request
+	var deposit db.Payment
+	if h.bookingDeposit > 0 {
+		deposit, err = qtx.CreatePayment(ctx, db.CreatePaymentParams{
+			BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+			Kind:      "deposit",
+			Provider:  h.payments.Name(),
+			Amount:    h.bookingDeposit,
+			Currency:  "usd",
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to accept quote")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to accept quote")
+	}
+	publishSlotChange("booked", start)
+
+	var paymentURL string
+	if deposit.ID != 0 {
+		paymentURL, err = h.startDepositCheckout(ctx, queries, booking, deposit)
+		if err != nil {
+			c.Logger().Warnf("Failed to start deposit checkout for booking %d: %v", booking.ID, err)
+			// Cancelling the booking reopens the quote so the customer
+			// can try again
+			if err := abandonDeposit(ctx, queries, deposit, "void"); err != nil {
+				c.Logger().Warnf("Failed to cancel booking %d: %v", booking.ID, err)
+			} else {
+				publishSlotChange("freed", start)
+			}
+			data, _ = h.loadQuotePage(c, queries, c.Param("token"))
+			data.Error = "We couldn't reach our payment provider. Please try again in a moment."
+			return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+		}
+	}
+
+	h.notifyQuoteAnswered(c, quote, "accepted",
+		fmt.Sprintf("Requested appointment: %s", startLocal.Format("Monday, January 2 at 3:04 PM")))
+
+	if paymentURL != "" {
+		return c.Redirect(http.StatusSeeOther, paymentURL)
+	}
+	data.State = pages.QuoteLinkAccepted
+	data.BookingLabel = fmt.Sprintf("%s, %s", startLocal.Format("Monday, January 2"), slotWindowLabel(startLocal, slotDef.Duration))
+	data.DepositDue = deposit.Amount
+	if deposit.ID != 0 {
+		data.DepositURL = h.depositLink(booking)
+	}
+	data.Days = nil
+	return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+}
+
+// quotedService is what a quote's booking is for: the first package on it,
+// and the skills every package on it needs.
+type quotedService struct {
+	slug   sql.NullString
+	skills []string
+}
+
+func quoteService(ctx context.Context, queries *db.Queries, items []db.QuoteItem) (quotedService, error) {
+	var service quotedService
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if !item.PackageID.Valid {
+			continue
+		}
+		pkg, err := queries.GetPackageByID(ctx, item.PackageID.Int64)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return quotedService{}, err
+		}
+		if !service.slug.Valid {
+			service.slug = sql.NullString{String: pkg.Slug, Valid: true}
+		}
+		for _, skill := range staffing.ParseSkills(pkg.RequiredSkills.String) {
+			if !seen[skill] {
+				seen[skill] = true
+				service.skills = append(service.skills, skill)
+			}
+		}
+	}
+	return service, nil
+}
+
+// redeemQuotePromo carries a quote's promo code over to the booking made
+// from it. The quote keeps the discount it was sent with, so the code isn't
+// checked again; the customer was promised that price.
+func redeemQuotePromo(ctx context.Context, queries *db.Queries, quote db.Quote, booking db.Booking) error {
+	if !quote.PromoCode.Valid {
+		return nil
+	}
+	if !quote.PromoCodeID.Valid {
+		// The code was deleted after it was applied
+		return queries.SetBookingPromo(ctx, db.SetBookingPromoParams{
+			PromoCode:     quote.PromoCode,
+			DiscountType:  quote.DiscountType,
+			DiscountValue: quote.DiscountValue,
+			ID:            booking.ID,
+		})
+	}
+	row := db.PromoCode{
+		ID:            quote.PromoCodeID.Int64,
+		Code:          quote.PromoCode.String,
+		DiscountType:  quote.DiscountType.String,
+		DiscountValue: quote.DiscountValue,
+	}
+	return redeemPromoForBooking(ctx, queries, row, booking, sql.NullInt64{Int64: quote.ID, Valid: true})
+}
+
+func (h *Handler) DeclineQuote(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	data, quote := h.loadQuotePage(c, queries, c.Param("token"))
+	if data.State != pages.QuoteLinkOpen {
+		return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+	}
+
+	declined, err := queries.DeclineQuote(ctx, quote.ID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to decline quote")
+	}
+	if declined > 0 {
+		h.notifyQuoteAnswered(c, quote, "declined", "")
+	}
+
+	data, _ = h.loadQuotePage(c, queries, c.Param("token"))
+	return pages.QuotePage(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) notifyQuoteAnswered(c echo.Context, quote db.Quote, outcome string, detail string) {
+	if h.contactEmail == "" {
+		return
+	}
+	text := fmt.Sprintf("%s has %s quote #%d.\n\n", quote.CustomerName, outcome, quote.ID)
+	if detail != "" {
+		text += detail + "\n\n"
+	}
+	text += fmt.Sprintf("View the quote: %s/admin/quotes/%d\n", h.siteURL, quote.ID)
+
+	err := h.mailer.Send(c.Request().Context(), mailer.Message{
+		To:      []string{h.contactEmail},
+		ReplyTo: quote.Email,
+		Subject: fmt.Sprintf("Quote #%d %s by %s", quote.ID, outcome, quote.CustomerName),
+		Text:    text,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to send quote notification for quote %d: %v", quote.ID, err)
+	}
+}
+
+// loadQuotePage resolves a quote token, reporting why the quote can't be
+// answered when it can't.
+func (h *Handler) loadQuotePage(c echo.Context, queries *db.Queries, token string) (pages.QuotePageData, db.Quote) {
+	ctx := c.Request().Context()
+	data := pages.QuotePageData{Token: token, State: pages.QuoteLinkInvalid}
+
+	id, err := h.links.Verify(quoteLinkPurpose, token, time.Now())
+	if errors.Is(err, linksign.ErrExpired) {
+		data.State = pages.QuoteLinkExpired
+		return data, db.Quote{}
+	}
+	if err != nil {
+		return data, db.Quote{}
+	}
+	quote, err := queries.GetQuoteByID(ctx, id)
+	if err != nil {
+		return data, db.Quote{}
+	}
+	items, err := queries.ListQuoteItems(ctx, id)
+	if err != nil {
+		return data, db.Quote{}
+	}
+
+	data.Number = quote.ID
+	data.FirstName = customerFirstName(quote.CustomerName)
+	data.Vehicle = nullableString(quote.VehicleDetails)
+	data.Notes = nullableString(quote.Notes)
+	data.ValidUntil = quoteValidUntilLabel(quote.ExpiresAt)
+	data.Lines, _ = quoteLines(items)
+	totals := quoteTotals(quote, items)
+	data.Subtotal = totals.Subtotal
+	data.Discount = totals.Discount
+	data.DiscountLabel = quoteDiscountLabel(quote)
+	data.Total = totals.Total
+
+	switch quoteDisplayStatus(quote, time.Now()) {
+	case "sent":
+		data.State = pages.QuoteLinkOpen
+		service, err := quoteService(ctx, queries, items)
+		if err != nil {
+			c.Logger().Warnf("Failed to load the services on quote %d: %v", quote.ID, err)
+		}
+		data.Days = h.quoteSlotDays(c, queries, service.skills)
+		data.MobileService = h.serviceArea.Enabled()
+		data.DepositPolicy = h.depositPolicy()
+	case "accepted":
+		data.State = pages.QuoteLinkAccepted
+		if quote.BookingID.Valid {
+			if booking, err := queries.GetBookingByID(ctx, quote.BookingID.Int64); err == nil {
+				_, window := resolveSlotDetails(booking.RequestedStart, booking.RequestedEnd)
+				data.BookingLabel = booking.RequestedStart.In(bookingLocation).Format("Monday, January 2") + ", " + window
+				deposit, err := queries.GetBookingDeposit(ctx, sql.NullInt64{Int64: booking.ID, Valid: true})
+				if err == nil && deposit.Status == "pending" {
+					data.DepositDue = deposit.Amount
+					data.DepositURL = h.depositLink(booking)
+				}
+			}
+		}
+	case "declined":
+		data.State = pages.QuoteLinkDeclined
+	case "expired":
+		data.State = pages.QuoteLinkExpired
+	default:
+		data.State = pages.QuoteLinkRevising
+	}
+	return data, quote
+}
+
+// quoteSlotDays lists the open slots over the next few weeks for the
+// accept form, for a job at the shop needing skills. Days with nothing
+// open are left out.
+func (h *Handler) quoteSlotDays(c echo.Context, queries *db.Queries, skills []string) []pages.QuoteSlotDay {
+	ctx := c.Request().Context()
+	start := time.Now().In(bookingLocation)
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, bookingLocation)
+	endExclusive := start.AddDate(0, 0, quoteSlotPickerDays)
+
+	blockedMap, err := h.blockedSlotKeys(ctx, queries, start, endExclusive, time.Now(), "", jobNeeds{skills: skills})
+	if err != nil {
+		c.Logger().Warnf("Failed to load availability for quote: %v", err)
+		return nil
+	}
+
+	var days []pages.QuoteSlotDay
+	for _, day := range buildAvailabilityDays(start, endExclusive, blockedMap, bookingHorizon(ctx, queries)) {
+		if !day.HasAvailability {
+			continue
+		}
+		option := pages.QuoteSlotDay{Label: day.Label}
+		for _, slot := range day.Slots {
+			if slot.Available {
+				option.Slots = append(option.Slots, pages.QuoteSlotOption{
+					Value:  slot.StartISO,
+					Label:  slot.Label,
+					Window: slot.Window,
+				})
+			}
+		}
+		days = append(days, option)
+	}
+	return days
+}
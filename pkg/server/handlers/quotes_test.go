@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/payments"
+
+	"github.com/labstack/echo/v4"
+)
+
+// checkoutProvider is a hosted checkout that hands out url, or fails with
+// err.
+type checkoutProvider struct {
+	payments.Manual
+	url string
+	err error
+}
+
+func (p checkoutProvider) Name() string { return "test" }
+
+func (p checkoutProvider) CreateCheckout(ctx context.Context, req payments.CheckoutRequest) (payments.Checkout, error) {
+	if p.err != nil {
+		return payments.Checkout{}, p.err
+	}
+	return payments.Checkout{ID: "cs_" + req.Reference, URL: p.url}, nil
+}
+
+func TestAcceptQuote(t *testing.T) {
+	ctx := context.Background()
+	tomorrow := time.Now().In(bookingLocation).AddDate(0, 0, 1)
+	slot := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 8, 0, 0, 0, bookingLocation)
+
+	tests := []struct {
+		name       string
+		skills     string // the only technician's skills
+		deposit    int64
+		provider   payments.PaymentProvider
+		wantStatus int
+		wantBooked bool
+		wantBody   string
+	}{
+		{name: "nobody can do the work", skills: "interior", wantStatus: http.StatusOK, wantBody: "just been taken"},
+		{name: "no deposit", skills: "ceramic", wantStatus: http.StatusOK, wantBooked: true, wantBody: "We&#39;ll confirm your appointment"},
+		{name: "deposit taken in person", skills: "ceramic", deposit: 5000, provider: payments.Manual{}, wantStatus: http.StatusOK, wantBooked: true, wantBody: "Pay $50.00 deposit"},
+		{name: "deposit taken online", skills: "ceramic", deposit: 5000, provider: checkoutProvider{url: "https://pay.example/cs"}, wantStatus: http.StatusSeeOther, wantBooked: true},
+		{name: "checkout fails", skills: "ceramic", deposit: 5000, provider: checkoutProvider{err: errors.New("down")}, wantStatus: http.StatusOK, wantBody: "couldn&#39;t reach our payment provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := newTestDB(t)
+			queries := db.New(conn)
+
+			pkg, err := queries.CreatePackage(ctx, db.CreatePackageParams{
+				Slug:           "ceramic",
+				Name:           "Ceramic Coating",
+				IsActive:       sql.NullBool{Bool: true, Valid: true},
+				RequiredSkills: sql.NullString{String: "ceramic", Valid: true},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tech, err := queries.CreateStaff(ctx, db.CreateStaffParams{
+				Name:     "Alex",
+				Role:     "technician",
+				Skills:   sql.NullString{String: tt.skills, Valid: true},
+				IsActive: sql.NullBool{Bool: true, Valid: true},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for day := 0; day < 7; day++ {
+				err := queries.CreateStaffHours(ctx, db.CreateStaffHoursParams{StaffID: tech.ID, Weekday: int64(day), StartMinute: 7 * 60, EndMinute: 20 * 60})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			quote, err := queries.CreateQuote(ctx, db.CreateQuoteParams{
+				CustomerName: "Dana Whitfield",
+				Email:        "dana@example.com",
+				ExpiresAt:    sql.NullTime{Time: time.Now().Add(7 * 24 * time.Hour).UTC(), Valid: true},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = queries.CreateQuoteItem(ctx, db.CreateQuoteItemParams{
+				QuoteID:     quote.ID,
+				Kind:        "package",
+				PackageID:   sql.NullInt64{Int64: pkg.ID, Valid: true},
+				Description: pkg.Name,
+				Quantity:    1,
+				UnitPrice:   90000,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := conn.Exec(`UPDATE quotes SET status = 'sent' WHERE id = ?`, quote.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			provider := tt.provider
+			if provider == nil {
+				provider = payments.Manual{}
+			}
+			h := &Handler{
+				db:             conn,
+				links:          linksign.New([]byte("test")),
+				mailer:         &recordingMailer{},
+				siteURL:        "https://example.com",
+				payments:       provider,
+				bookingDeposit: tt.deposit,
+			}
+			token := h.links.Sign(quoteLinkPurpose, quote.ID, time.Now().Add(time.Hour))
+			form := url.Values{"start": {slot.Format(time.RFC3339)}}
+			req := httptest.NewRequest(http.MethodPost, "/quote/"+token+"/accept", strings.NewReader(form.Encode()))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("token")
+			c.SetParamValues(token)
+			if err := h.AcceptQuote(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("page doesn't say %q", tt.wantBody)
+			}
+
+			quote, err = queries.GetQuoteByID(ctx, quote.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := quote.Status.String == "accepted"; got != tt.wantBooked {
+				t.Fatalf("quote status = %q", quote.Status.String)
+			}
+			if !tt.wantBooked {
+				var live int
+				conn.QueryRow(`SELECT COUNT(*) FROM bookings WHERE status NOT IN ('cancelled', 'declined')`).Scan(&live)
+				if live != 0 {
+					t.Errorf("%d bookings left holding the slot", live)
+				}
+				return
+			}
+			booking, err := queries.GetBookingByID(ctx, quote.BookingID.Int64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !booking.RequestedStart.Equal(slot) || booking.ServiceInterest.String != "ceramic" {
+				t.Errorf("booking = %v for %q", booking.RequestedStart, booking.ServiceInterest.String)
+			}
+			deposit, err := queries.GetBookingDeposit(ctx, sql.NullInt64{Int64: booking.ID, Valid: true})
+			if tt.deposit == 0 {
+				if !errors.Is(err, sql.ErrNoRows) {
+					t.Errorf("deposit = %+v, %v; want none", deposit, err)
+				}
+				return
+			}
+			if err != nil || deposit.Amount != tt.deposit || deposit.Status != "pending" {
+				t.Errorf("deposit = %+v, %v", deposit, err)
+			}
+			if tt.wantStatus == http.StatusSeeOther && rec.Header().Get("Location") != "https://pay.example/cs" {
+				t.Errorf("redirected to %q", rec.Header().Get("Location"))
+			}
+		})
+	}
+}
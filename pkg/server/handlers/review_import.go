@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/reviewimport"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+var reviewImportSources = []string{reviewimport.SourceGoogle, reviewimport.SourceFacebook}
+
+func (h *Handler) AdminReviewImport(c echo.Context) error {
+	data := pages.AdminReviewImportData{
+		Sources:      reviewImportSources,
+		ErrorMessage: c.QueryParam("error"),
+	}
+	return pages.AdminReviewImport(data).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// ImportReviews stores the reviews in an uploaded export, skipping any
+// already on file, then lists what was added so the admin can pick which
+// to feature.
+func (h *Handler) ImportReviews(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	source := c.FormValue("source")
+	fh, err := c.FormFile("export")
+	if err != nil {
+		return reviewImportRedirect(c, "Choose an export file to import")
+	}
+	file, err := fh.Open()
+	if err != nil {
+		return reviewImportRedirect(c, "Could not read the uploaded file")
+	}
+	defer file.Close()
+
+	parsed, err := reviewimport.Parse(source, file)
+	if err != nil {
+		return reviewImportRedirect(c, fmt.Sprintf("Could not read the %s export: %v", source, err))
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to import reviews")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	result := pages.ReviewImportResult{Source: source, Skipped: parsed.Skipped}
+	for _, r := range parsed.Reviews {
+		dupes, err := qtx.CountDuplicateReviews(ctx, db.CountDuplicateReviewsParams{
+			Source:     source,
+			ExternalID: r.ExternalID,
+			Author:     r.Author,
+			Body:       r.Body,
+			Rating:     r.Rating,
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to import reviews: %v", err))
+		}
+		if dupes > 0 {
+			result.Duplicates++
+			continue
+		}
+
+		review, err := qtx.CreateImportedReview(ctx, db.CreateImportedReviewParams{
+			Author:     r.Author,
+			Rating:     sql.NullInt64{Int64: r.Rating, Valid: true},
+			Body:       sql.NullString{String: r.Body, Valid: r.Body != ""},
+			Source:     sql.NullString{String: source, Valid: true},
+			ExternalID: sql.NullString{String: r.ExternalID, Valid: true},
+			CreatedAt:  sql.NullTime{Time: r.CreatedAt, Valid: true},
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to import reviews: %v", err))
+		}
+		result.Imported = append(result.Imported, review)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to import reviews")
+	}
+
+	data := pages.AdminReviewImportData{
+		Sources: reviewImportSources,
+		Result:  &result,
+	}
+	return pages.AdminReviewImport(data).Render(ctx, c.Response().Writer)
+}
+
+// FeatureImportedReviews features the reviews ticked after an import.
+func (h *Handler) FeatureImportedReviews(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	params, err := c.FormParams()
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid form")
+	}
+	for _, raw := range params["review_id"] {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid review ID")
+		}
+		review, err := queries.GetReviewByID(ctx, id)
+		if err != nil || reviewStatus(review) != "approved" {
+			continue
+		}
+		err = queries.SetReviewFeatured(ctx, db.SetReviewFeaturedParams{
+			IsFeatured: sql.NullBool{Bool: true, Valid: true},
+			ID:         id,
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update review: %v", err))
+		}
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/reviews")
+}
+
+func reviewImportRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/reviews/import?error="+url.QueryEscape(errMsg))
+}
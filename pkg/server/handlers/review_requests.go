@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	reviewLinkPurpose = "review"
+	reviewRequestTTL  = 30 * 24 * time.Hour
+	// Ratings at or below this go to the follow-up queue instead of being
+	// pointed at Google.
+	reviewFollowUpThreshold = 3
+	reviewBodyMaxLength     = 2000
+)
+
+// sendReviewRequest emails a fresh single-use review link for a booking.
+// Any unused links sent earlier for the same booking stop working.
+func (h *Handler) sendReviewRequest(ctx context.Context, queries *db.Queries, booking db.Booking, galleryGroupID sql.NullInt64) error {
+	if h.links == nil {
+		return errLinksOff
+	}
+	if err := queries.ExpireReviewRequestsForBooking(ctx, booking.ID); err != nil {
+		return err
+	}
+
+	expires := time.Now().UTC().Add(reviewRequestTTL).Truncate(time.Second)
+	request, err := queries.CreateReviewRequest(ctx, db.CreateReviewRequestParams{
+		BookingID:      booking.ID,
+		GalleryGroupID: galleryGroupID,
+		Email:          booking.Email,
+		ExpiresAt:      expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := h.customerLink("/review/", reviewLinkPurpose, request.ID, expires)
+	text := fmt.Sprintf(
+		"Hi %s,\n\nThanks for trusting us with your vehicle. How did we do?\n\n"+
+			"It takes less than a minute to leave a rating:\n%s\n\n"+
+			"The link works once and expires on %s.\n",
+		customerFirstName(booking.CustomerName),
+		link,
+		expires.In(bookingLocation).Format("January 2, 2006"),
+	)
+	err = h.mailer.Send(ctx, mailer.Message{
+		To:      []string{booking.Email},
+		Subject: "How did we do?",
+		Text:    text,
+	})
+	if err != nil {
+		return err
+	}
+
+	return queries.MarkReviewRequestSent(ctx, request.ID)
+}
+
+// SendReviewRequest sends, or resends, the review link for a booking.
+func (h *Handler) SendReviewRequest(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid booking ID")
+	}
+
+	booking, err := queries.GetBookingByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Booking not found")
+	}
+	if normalizeBookingStatus(booking.Status.String) != "completed" {
+		return c.String(http.StatusBadRequest, "Only completed bookings can be sent a review request")
+	}
+
+	var galleryGroupID sql.NullInt64
+	if raw := c.FormValue("gallery_group_id"); raw != "" {
+		groupID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid gallery group")
+		}
+		galleryGroupID = sql.NullInt64{Int64: groupID, Valid: true}
+	}
+
+	if err := h.sendReviewRequest(ctx, queries, booking, galleryGroupID); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to send review request: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, bookingsRedirect(c))
+}
+
+// ReviewForm is the page a customer lands on from a review request email.
+func (h *Handler) ReviewForm(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	data, _ := h.loadReviewRequestPage(ctx, queries, c.Param("token"))
+	return pages.ReviewRequestPage(data).Render(ctx, c.Response().Writer)
+}
+
+// SubmitReview stores a review from a review link. Low ratings are held
+// for a personal follow-up; everything waits for moderation before it is
+// shown on the site.
+func (h *Handler) SubmitReview(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	data, request := h.loadReviewRequestPage(ctx, queries, c.Param("token"))
+	if data.State != pages.ReviewLinkOpen {
+		return pages.ReviewRequestPage(data).Render(ctx, c.Response().Writer)
+	}
+
+	data.Body = strings.TrimSpace(c.FormValue("body"))
+	rating, err := strconv.ParseInt(c.FormValue("rating"), 10, 64)
+	if err != nil || rating < 1 || rating > 5 {
+		data.Error = "Please choose a star rating."
+		return pages.ReviewRequestPage(data).Render(ctx, c.Response().Writer)
+	}
+	data.Rating = rating
+	if utf8.RuneCountInString(data.Body) > reviewBodyMaxLength {
+		data.Error = fmt.Sprintf("Please keep your review under %d characters.", reviewBodyMaxLength)
+		return pages.ReviewRequestPage(data).Render(ctx, c.Response().Writer)
+	}
+
+	booking, err := queries.GetBookingByID(ctx, request.BookingID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load booking")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save review")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	claimed, err := qtx.UseReviewRequest(ctx, request.ID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save review")
+	}
+	if claimed == 0 {
+		data.State = pages.ReviewLinkUsed
+		return pages.ReviewRequestPage(data).Render(ctx, c.Response().Writer)
+	}
+
+	var followUp sql.NullString
+	if rating <= reviewFollowUpThreshold {
+		followUp = sql.NullString{String: "open", Valid: true}
+	}
+	_, err = qtx.CreateCustomerReview(ctx, db.CreateCustomerReviewParams{
+		Author:         reviewAuthorName(booking.CustomerName),
+		Rating:         sql.NullInt64{Int64: rating, Valid: true},
+		Body:           sql.NullString{String: data.Body, Valid: data.Body != ""},
+		BookingID:      sql.NullInt64{Int64: booking.ID, Valid: true},
+		GalleryGroupID: request.GalleryGroupID,
+		FollowUpStatus: followUp,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save review")
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save review")
+	}
+
+	thanks := pages.ReviewThanksData{
+		FirstName: data.FirstName,
+		Rating:    rating,
+		Body:      data.Body,
+		FollowUp:  followUp.Valid,
+	}
+	if !followUp.Valid {
+		thanks.GoogleReviewURL = h.googleReviewURL
+	}
+	return pages.ReviewThanks(thanks).Render(ctx, c.Response().Writer)
+}
+
+// loadReviewRequestPage resolves a review token to its request, reporting
+// why the link can't be used when it can't.
+func (h *Handler) loadReviewRequestPage(ctx context.Context, queries *db.Queries, token string) (pages.ReviewRequestData, db.ReviewRequest) {
+	data := pages.ReviewRequestData{Token: token, State: pages.ReviewLinkInvalid}
+
+	id, err := h.links.Verify(reviewLinkPurpose, token, time.Now())
+	if errors.Is(err, linksign.ErrExpired) {
+		data.State = pages.ReviewLinkExpired
+		return data, db.ReviewRequest{}
+	}
+	if err != nil {
+		return data, db.ReviewRequest{}
+	}
+
+	request, err := queries.GetReviewRequestByID(ctx, id)
+	if err != nil {
+		return data, db.ReviewRequest{}
+	}
+	booking, err := queries.GetBookingByID(ctx, request.BookingID)
+	if err != nil {
+		return data, db.ReviewRequest{}
+	}
+
+	data.FirstName = customerFirstName(booking.CustomerName)
+	data.Service = nullableString(booking.ServiceInterest)
+	data.Vehicle = nullableString(booking.VehicleDetails)
+	data.DateLabel = booking.RequestedStart.In(bookingLocation).Format("Monday, January 2")
+	if request.GalleryGroupID.Valid {
+		if group, err := queries.GetGalleryGroupByID(ctx, request.GalleryGroupID.Int64); err == nil {
+			data.GalleryTitle = group.Title
+			data.GallerySlug = group.Slug
+		}
+	}
+
+	switch {
+	case request.UsedAt.Valid:
+		data.State = pages.ReviewLinkUsed
+	case !request.ExpiresAt.After(time.Now()):
+		data.State = pages.ReviewLinkExpired
+	default:
+		data.State = pages.ReviewLinkOpen
+	}
+	return data, request
+}
+
+func customerFirstName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return "there"
+	}
+	return fields[0]
+}
+
+// reviewAuthorName publishes a customer as first name and last initial.
+func reviewAuthorName(name string) string {
+	fields := strings.Fields(name)
+	switch len(fields) {
+	case 0:
+		return "Verified customer"
+	case 1:
+		return fields[0]
+	default:
+		last := []rune(fields[len(fields)-1])
+		return fields[0] + " " + strings.ToUpper(string(last[0])) + "."
+	}
+}
+
+// AdminReviewFollowUps lists low-rated reviews that still need a call back.
+func (h *Handler) AdminReviewFollowUps(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	rows, err := queries.ListOpenFollowUpReviews(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch follow-ups")
+	}
+
+	items := make([]pages.ReviewFollowUpItem, 0, len(rows))
+	for _, row := range rows {
+		item := pages.ReviewFollowUpItem{
+			ID:           row.ID,
+			Author:       row.Author,
+			Rating:       row.Rating.Int64,
+			Body:         row.Body.String,
+			CustomerName: row.CustomerName.String,
+			Email:        row.Email.String,
+			Phone:        row.Phone.String,
+		}
+		if row.CreatedAt.Valid {
+			item.SubmittedAt = row.CreatedAt.Time.In(bookingLocation).Format("Jan 2, 2006 3:04 PM")
+		}
+		items = append(items, item)
+	}
+
+	return pages.AdminReviewFollowUps(items).Render(ctx, c.Response().Writer)
+}
+
+// ResolveReviewFollowUp closes a follow-up with a note on how it went.
+func (h *Handler) ResolveReviewFollowUp(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	notes := strings.TrimSpace(c.FormValue("follow_up_notes"))
+	err = queries.ResolveReviewFollowUp(ctx, db.ResolveReviewFollowUpParams{
+		FollowUpNotes: sql.NullString{String: notes, Valid: notes != ""},
+		ID:            id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to resolve follow-up: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/reviews/follow-ups")
+}
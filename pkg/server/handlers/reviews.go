@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"detailingpass/pkg/db"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const adminReviewsLimit = 200
+
+var (
+	reviewStatuses  = []string{"pending", "approved", "rejected"}
+	reviewStatusSet = map[string]bool{"pending": true, "approved": true, "rejected": true}
+	reviewSources   = []string{"manual", "google", "facebook", "customer"}
+	reviewSourceSet = map[string]bool{"manual": true, "google": true, "facebook": true, "customer": true}
+)
+
+func (h *Handler) AdminReviews(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	filter := c.QueryParam("status")
+	if !reviewStatusSet[filter] {
+		filter = ""
+	}
+
+	reviews, err := queries.ListReviews(ctx, db.ListReviewsParams{
+		Status: sql.NullString{String: filter, Valid: filter != ""},
+		Limit:  adminReviewsLimit,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch reviews")
+	}
+
+	counts, err := queries.CountReviewsByStatus(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to count reviews")
+	}
+	statusCounts := make(map[string]int64, len(counts))
+	var total int64
+	for _, row := range counts {
+		statusCounts[row.Status] = row.Count
+		total += row.Count
+	}
+
+	stats, err := queries.GetReviewStats(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch review stats: %v", err)
+	}
+
+	followUps, err := queries.CountOpenFollowUpReviews(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to count review follow-ups: %v", err)
+	}
+
+	// Check if we're editing
+	var formData *pages.ReviewFormData
+	if editID := c.QueryParam("edit"); editID != "" {
+		if id, err := strconv.ParseInt(editID, 10, 64); err == nil {
+			if review, err := queries.GetReviewByID(ctx, id); err == nil {
+				formData = &pages.ReviewFormData{
+					ID:         review.ID,
+					Author:     review.Author,
+					Rating:     review.Rating.Int64,
+					Body:       review.Body.String,
+					Source:     review.Source.String,
+					IsFeatured: review.IsFeatured.Bool,
+					Status:     reviewStatus(review),
+					IsEdit:     true,
+				}
+			}
+		}
+	}
+
+	data := pages.AdminReviewsData{
+		Reviews:      reviews,
+		Filter:       filter,
+		Statuses:     reviewStatuses,
+		Sources:      reviewSources,
+		StatusCounts: statusCounts,
+		Total:        total,
+		Rating:       pages.RatingSummary{Average: stats.Average, Count: stats.Count},
+		FollowUps:    followUps,
+		Form:         formData,
+		ErrorMessage: c.QueryParam("error"),
+	}
+
+	return pages.AdminReviews(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) CreateReview(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	form, errMsg := parseReviewForm(c)
+	if errMsg != "" {
+		return reviewsRedirect(c, errMsg)
+	}
+
+	_, err := queries.CreateReview(ctx, db.CreateReviewParams{
+		Author:     form.Author,
+		Rating:     sql.NullInt64{Int64: form.Rating, Valid: true},
+		Body:       sql.NullString{String: form.Body, Valid: form.Body != ""},
+		Source:     sql.NullString{String: form.Source, Valid: true},
+		IsFeatured: sql.NullBool{Bool: form.IsFeatured, Valid: true},
+		Status:     sql.NullString{String: form.Status, Valid: true},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create review: %v", err))
+	}
+
+	return reviewsRedirect(c, "")
+}
+
+func (h *Handler) UpdateReview(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	form, errMsg := parseReviewForm(c)
+	if errMsg != "" {
+		return reviewsRedirect(c, errMsg)
+	}
+
+	_, err = queries.UpdateReview(ctx, db.UpdateReviewParams{
+		Author:     form.Author,
+		Rating:     sql.NullInt64{Int64: form.Rating, Valid: true},
+		Body:       sql.NullString{String: form.Body, Valid: form.Body != ""},
+		Source:     sql.NullString{String: form.Source, Valid: true},
+		IsFeatured: sql.NullBool{Bool: form.IsFeatured, Valid: true},
+		Status:     sql.NullString{String: form.Status, Valid: true},
+		ID:         id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update review: %v", err))
+	}
+
+	return reviewsRedirect(c, "")
+}
+
+// ToggleReviewFeatured flips whether a review appears on the home page.
+// Only approved reviews can be featured.
+func (h *Handler) ToggleReviewFeatured(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	review, err := queries.GetReviewByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Review not found")
+	}
+
+	featured := !review.IsFeatured.Bool
+	if featured && reviewStatus(review) != "approved" {
+		return reviewsRedirect(c, "Approve a review before featuring it")
+	}
+
+	err = queries.SetReviewFeatured(ctx, db.SetReviewFeaturedParams{
+		IsFeatured: sql.NullBool{Bool: featured, Valid: true},
+		ID:         id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update review: %v", err))
+	}
+
+	return reviewsRedirect(c, "")
+}
+
+// UpdateReviewStatus moderates a review. Anything other than approved is
+// hidden from the public site and loses its featured flag.
+func (h *Handler) UpdateReviewStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	status := c.FormValue("status")
+	if !reviewStatusSet[status] {
+		return c.String(http.StatusBadRequest, "Invalid status")
+	}
+
+	if err := queries.SetReviewStatus(ctx, db.SetReviewStatusParams{Status: status, ID: id}); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update review: %v", err))
+	}
+
+	return reviewsRedirect(c, "")
+}
+
+func (h *Handler) DeleteReview(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	if err := queries.DeleteReview(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete review: %v", err))
+	}
+
+	return reviewsRedirect(c, "")
+}
+
+// parseReviewForm validates the create/edit form, returning a message for
+// the admin when it is unusable.
+func parseReviewForm(c echo.Context) (pages.ReviewFormData, string) {
+	form := pages.ReviewFormData{
+		Author:     strings.TrimSpace(c.FormValue("author")),
+		Body:       strings.TrimSpace(c.FormValue("body")),
+		Source:     c.FormValue("source"),
+		Status:     c.FormValue("status"),
+		IsFeatured: c.FormValue("is_featured") == "true",
+	}
+	if form.Author == "" {
+		return form, "Author is required"
+	}
+
+	rating, err := strconv.ParseInt(c.FormValue("rating"), 10, 64)
+	if err != nil || rating < 1 || rating > 5 {
+		return form, "Rating must be between 1 and 5"
+	}
+	form.Rating = rating
+
+	if !reviewSourceSet[form.Source] {
+		form.Source = "manual"
+	}
+	if !reviewStatusSet[form.Status] {
+		form.Status = "approved"
+	}
+	if form.Status != "approved" {
+		form.IsFeatured = false
+	}
+	return form, ""
+}
+
+// reviewStatus treats rows from before moderation existed as approved.
+func reviewStatus(r db.Review) string {
+	if !r.Status.Valid {
+		return "approved"
+	}
+	return r.Status.String
+}
+
+// reviewsRedirect returns to the review list, keeping the status filter
+// the form was submitted from.
+func reviewsRedirect(c echo.Context, errMsg string) error {
+	q := url.Values{}
+	if filter := c.FormValue("filter"); reviewStatusSet[filter] {
+		q.Set("status", filter)
+	}
+	if errMsg != "" {
+		q.Set("error", errMsg)
+	}
+	redirect := "/admin/reviews"
+	if len(q) > 0 {
+		redirect += "?" + q.Encode()
+	}
+	return c.Redirect(http.StatusSeeOther, redirect)
+}
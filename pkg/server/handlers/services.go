@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/seo"
+	"detailingpass/web/templates"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Services lists every active package in its admin sort order.
+func (h *Handler) Services(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	packages, err := queries.GetAllPackages(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load services")
+	}
+
+	structured := []any{seo.Business(h.siteURL, businessRating(c, queries))}
+	for _, pkg := range packages {
+		structured = append(structured, seo.Service(h.siteURL, pkg))
+	}
+	meta := templates.SEO{
+		URL:            seo.AbsoluteURL(h.siteURL, "/services"),
+		StructuredData: structured,
+	}
+
+	return pages.Services(packages, meta).Render(ctx, c.Response().Writer)
+}
+
+const servicePageProjectLimit = 6
+
+// ServiceDetail renders a package's service page at /services/:slug.
+func (h *Handler) ServiceDetail(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	pkg, err := queries.GetActivePackageBySlug(ctx, c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.String(http.StatusNotFound, "Service not found")
+		}
+		return c.String(http.StatusInternalServerError, "Failed to load service")
+	}
+
+	faqs, err := queries.ListPackageFAQs(ctx, pkg.ID)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch FAQs for package %d: %v", pkg.ID, err)
+	}
+
+	groups, err := queries.ListGalleryGroupsByPackage(ctx, db.ListGalleryGroupsByPackageParams{
+		PackageID: sql.NullInt64{Int64: pkg.ID, Valid: true},
+		Limit:     servicePageProjectLimit,
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch projects for package %d: %v", pkg.ID, err)
+	}
+
+	var others []db.Package
+	if all, err := queries.GetAllPackages(ctx); err == nil {
+		for _, p := range all {
+			if p.ID != pkg.ID {
+				others = append(others, p)
+			}
+		}
+	} else {
+		c.Logger().Warnf("Failed to fetch packages: %v", err)
+	}
+
+	data := pages.ServiceDetailData{
+		Package:  pkg,
+		FAQs:     faqs,
+		Projects: h.buildGalleryItems(c, queries, groups),
+		Others:   others,
+		SEO: templates.SEO{
+			URL:            seo.AbsoluteURL(h.siteURL, "/services/"+pkg.Slug),
+			StructuredData: []any{seo.Service(h.siteURL, pkg)},
+		},
+	}
+	// Preview the package with its most recent project, if it has photos
+	if len(data.Projects) > 0 && len(data.Projects[0].Images) > 0 {
+		data.SEO.Image = seo.AbsoluteURL(h.siteURL, data.Projects[0].HeroImage)
+	}
+	return pages.ServiceDetail(data).Render(ctx, c.Response().Writer)
+}
+
+// businessRating is the aggregate of approved reviews for structured data,
+// or nil when there are none.
+func businessRating(c echo.Context, queries *db.Queries) seo.Object {
+	stats, err := queries.GetReviewStats(c.Request().Context())
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch review stats: %v", err)
+		return nil
+	}
+	return seo.AggregateRating(stats.Average, stats.Count)
+}
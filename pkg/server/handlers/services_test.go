@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"detailingpass/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestServicesFollowsCatalog(t *testing.T) {
+	conn := newTestDB(t)
+	queries := db.New(conn)
+	ctx := context.Background()
+
+	for _, p := range []db.CreatePackageParams{
+		{Slug: "ceramic", Name: "Ceramic Coating", SortOrder: sql.NullInt64{Int64: 3, Valid: true}},
+		{Slug: "interior", Name: "Interior Detail", SortOrder: sql.NullInt64{Int64: 1, Valid: true}, PriceMin: sql.NullInt64{Int64: 15000, Valid: true}, PriceMax: sql.NullInt64{Int64: 25000, Valid: true}},
+		{Slug: "retired", Name: "Retired Package", SortOrder: sql.NullInt64{Int64: 2, Valid: true}, IsActive: sql.NullBool{Bool: false, Valid: true}},
+		{Slug: "exterior", Name: "Exterior Detail", SortOrder: sql.NullInt64{Int64: 2, Valid: true}},
+	} {
+		if !p.IsActive.Valid {
+			p.IsActive = sql.NullBool{Bool: true, Valid: true}
+		}
+		if _, err := queries.CreatePackage(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := &Handler{db: conn, siteURL: "https://example.com"}
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	if err := h.Services(e.NewContext(httptest.NewRequest(http.MethodGet, "/services", nil), rec)); err != nil {
+		t.Fatal(err)
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "Retired Package") {
+		t.Error("/services lists an inactive package")
+	}
+	interior := strings.Index(body, "Interior Detail")
+	exterior := strings.Index(body, "Exterior Detail")
+	ceramic := strings.Index(body, "Ceramic Coating")
+	if interior < 0 || exterior < 0 || ceramic < 0 {
+		t.Fatalf("/services is missing active packages")
+	}
+	if !(interior < exterior && exterior < ceramic) {
+		t.Error("/services doesn't follow sort_order")
+	}
+	if !strings.Contains(body, "Starting at $150") {
+		t.Error("/services doesn't show the price from cents")
+	}
+}
+
+func TestServiceDetail(t *testing.T) {
+	conn := newTestDB(t)
+	queries := db.New(conn)
+	ctx := context.Background()
+
+	pkg, err := queries.CreatePackage(ctx, db.CreatePackageParams{
+		Slug:     "interior",
+		Name:     "Interior Detail",
+		IsActive: sql.NullBool{Bool: true, Valid: true},
+		Included: sql.NullString{String: "Steam clean seats\nVacuum", Valid: true},
+		Excluded: sql.NullString{String: "Headliner stains", Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queries.CreatePackage(ctx, db.CreatePackageParams{Slug: "retired", Name: "Retired", IsActive: sql.NullBool{Bool: false, Valid: true}}); err != nil {
+		t.Fatal(err)
+	}
+	for i, q := range []string{"Do you need water hookup?", "How long does it take?"} {
+		_, err := queries.CreatePackageFAQ(ctx, db.CreatePackageFAQParams{
+			PackageID: pkg.ID,
+			Question:  q,
+			Answer:    "Answer",
+			SortOrder: sql.NullInt64{Int64: int64(2 - i), Valid: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := &Handler{db: conn, siteURL: "https://example.com"}
+	e := echo.New()
+	serve := func(slug string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/services/"+slug, nil), rec)
+		c.SetParamNames("slug")
+		c.SetParamValues(slug)
+		if err := h.ServiceDetail(c); err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	rec := serve("interior")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/services/interior = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Steam clean seats", "Vacuum", "Headliner stains", "/booking?package=interior"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/services/interior is missing %q", want)
+		}
+	}
+	water := strings.Index(body, "Do you need water hookup?")
+	long := strings.Index(body, "How long does it take?")
+	if water < 0 || long < 0 || long > water {
+		t.Error("/services/interior doesn't list FAQs in sort_order")
+	}
+
+	for _, slug := range []string{"retired", "missing"} {
+		if rec := serve(slug); rec.Code != http.StatusNotFound {
+			t.Errorf("/services/%s = %d, want 404", slug, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/seo"
+
+	"github.com/labstack/echo/v4"
+)
+
+// sitemapExcludedPrefixes are GET routes that are not public pages.
+var sitemapExcludedPrefixes = []string{
+	"/admin", "/api", "/health", "/uploads", "/static", "/review",
+	"/sign-in", "/sign-up", "/account", "/favicon", "/robots.txt", "/sitemap.xml",
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Sitemap lists every public page: the static routes registered on the
+// server, plus a page per active package and gallery project.
+func (h *Handler) Sitemap(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	packages, err := queries.GetAllPackages(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to build sitemap")
+	}
+	groups, err := queries.ListGalleryGroupSlugs(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to build sitemap")
+	}
+
+	// Listing pages change whenever one of their entries does
+	var servicesMod, galleryMod time.Time
+	for _, p := range packages {
+		servicesMod = later(servicesMod, p.UpdatedAt.Time)
+	}
+	for _, g := range groups {
+		galleryMod = later(galleryMod, g.UpdatedAt.Time)
+	}
+	lastMod := map[string]time.Time{
+		"/":         later(servicesMod, galleryMod),
+		"/services": servicesMod,
+		"/gallery":  galleryMod,
+	}
+
+	set := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, path := range sitemapStaticPaths(c.Echo()) {
+		set.URLs = append(set.URLs, h.newSitemapURL(path, lastMod[path]))
+	}
+	for _, p := range packages {
+		set.URLs = append(set.URLs, h.newSitemapURL("/services/"+p.Slug, p.UpdatedAt.Time))
+	}
+	for _, g := range groups {
+		set.URLs = append(set.URLs, h.newSitemapURL("/gallery/"+g.Slug, g.UpdatedAt.Time))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationXMLCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+	if _, err := c.Response().Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	return xml.NewEncoder(c.Response()).Encode(set)
+}
+
+// sitemapStaticPaths returns the public GET routes without path
+// parameters, home first.
+func sitemapStaticPaths(e *echo.Echo) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, r := range e.Routes() {
+		if r.Method != http.MethodGet || strings.ContainsAny(r.Path, ":*") || seen[r.Path] {
+			continue
+		}
+		excluded := false
+		for _, prefix := range sitemapExcludedPrefixes {
+			if strings.HasPrefix(r.Path, prefix) {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+		seen[r.Path] = true
+		paths = append(paths, r.Path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if paths[i] == "/" || paths[j] == "/" {
+			return paths[i] == "/"
+		}
+		return paths[i] < paths[j]
+	})
+	return paths
+}
+
+func (h *Handler) newSitemapURL(path string, modified time.Time) sitemapURL {
+	u := sitemapURL{Loc: seo.AbsoluteURL(h.siteURL, path)}
+	if !modified.IsZero() {
+		u.LastMod = modified.UTC().Format("2006-01-02")
+	}
+	return u
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
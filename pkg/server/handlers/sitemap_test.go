@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"detailingpass/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestSitemapStaticPaths(t *testing.T) {
+	e := echo.New()
+	noop := func(echo.Context) error { return nil }
+	for _, path := range []string{"/services", "/", "/gallery", "/admin", "/admin/packages", "/api/availability", "/services/:slug", "/uploads/*", "/contact", "/review/:token"} {
+		e.GET(path, noop)
+	}
+	e.POST("/booking", noop)
+	e.HEAD("/contact", noop)
+
+	want := []string{"/", "/contact", "/gallery", "/services"}
+	if got := sitemapStaticPaths(e); !reflect.DeepEqual(got, want) {
+		t.Errorf("sitemapStaticPaths = %q, want %q", got, want)
+	}
+}
+
+func TestSitemap(t *testing.T) {
+	conn := newTestDB(t)
+	queries := db.New(conn)
+	ctx := context.Background()
+
+	if _, err := queries.CreatePackage(ctx, db.CreatePackageParams{Slug: "interior", Name: "Interior", IsActive: sql.NullBool{Bool: true, Valid: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queries.CreatePackage(ctx, db.CreatePackageParams{Slug: "retired", Name: "Retired", IsActive: sql.NullBool{Bool: false, Valid: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queries.CreateGalleryGroup(ctx, db.CreateGalleryGroupParams{Title: "Model 3", Slug: "model-3"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`UPDATE packages SET updated_at = '2025-03-04 10:00:00'; UPDATE gallery_groups SET updated_at = '2025-06-01 23:30:00'`); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &Handler{db: conn, siteURL: "https://example.com/"}
+	e := echo.New()
+	e.GET("/", h.Sitemap)
+	e.GET("/services", h.Sitemap)
+	e.GET("/gallery", h.Sitemap)
+
+	rec := httptest.NewRecorder()
+	if err := h.Sitemap(e.NewContext(httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil), rec)); err != nil {
+		t.Fatal(err)
+	}
+	var set sitemapURLSet
+	if err := xml.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatal(err)
+	}
+	want := []sitemapURL{
+		{Loc: "https://example.com/", LastMod: "2025-06-01"},
+		{Loc: "https://example.com/gallery", LastMod: "2025-06-01"},
+		{Loc: "https://example.com/services", LastMod: "2025-03-04"},
+		{Loc: "https://example.com/services/interior", LastMod: "2025-03-04"},
+		{Loc: "https://example.com/gallery/model-3", LastMod: "2025-06-01"},
+	}
+	if !reflect.DeepEqual(set.URLs, want) {
+		t.Errorf("sitemap URLs =\n  %+v\nwant\n  %+v", set.URLs, want)
+	}
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/servicearea"
+	"detailingpass/pkg/staffing"
+)
+
+// jobNeeds is what a job asks of whoever does it: the skills its service
+// needs, and where it is. The zero value is a job at the shop anyone can
+// do.
+type jobNeeds struct {
+	skills []string
+	stop   servicearea.Stop
+}
+
+// serviceSkills is the skills the package with slug needs. Services that
+// aren't packages need none.
+func serviceSkills(ctx context.Context, queries *db.Queries, slug string) ([]string, error) {
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		return nil, nil
+	}
+	pkg, err := queries.GetPackageBySlug(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return staffing.ParseSkills(pkg.RequiredSkills.String), nil
+}
+
+// slotBook is what decides whether a slot can take another job: the jobs
+// already booked, the slots held for waitlisted customers and visitors,
+// and who's working. Until anyone's hours are set up the shop takes one
+// job at a time, as it always has.
+type slotBook struct {
+	jobs  []bookedStop
+	holds map[string]int // by slotKey
+	techs []staffing.Tech
+	// area times the drives between jobs, which only open counts
+	area servicearea.Area
+}
+
+// loadSlotBook loads what decides the slots starting in [from, to). Holds
+// placed with holdToken are the asking visitor's own and don't count.
+func loadSlotBook(ctx context.Context, queries *db.Queries, from, to time.Time, now time.Time, holdToken string) (*slotBook, error) {
+	jobs, err := listBookedStops(ctx, queries, from, to)
+	if err != nil {
+		return nil, err
+	}
+	offered, err := queries.ListWaitlistHolds(ctx, db.ListWaitlistHoldsParams{
+		ExpiresAt:   now.UTC(),
+		SlotStart:   from.UTC(),
+		SlotStart_2: to.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	held, err := queries.ListSlotHolds(ctx, db.ListSlotHoldsParams{
+		ExpiresAt:   now.UTC(),
+		SlotStart:   from.UTC(),
+		SlotStart_2: to.UTC(),
+		Token:       holdToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	book := &slotBook{jobs: jobs, holds: make(map[string]int, len(offered)+len(held))}
+	for _, start := range append(offered, held...) {
+		book.holds[slotKey(start)]++
+	}
+	book.techs, err = loadTechs(ctx, queries, from, to, jobs)
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// loadTechs loads everyone who can be assigned jobs in [from, to), with
+// the jobs they have. It returns nil when nobody's hours are set.
+func loadTechs(ctx context.Context, queries *db.Queries, from, to time.Time, jobs []bookedStop) ([]staffing.Tech, error) {
+	hours, err := queries.ListJobStaffHours(ctx)
+	if err != nil || len(hours) == 0 {
+		return nil, err
+	}
+	staff, err := queries.ListJobStaff(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeOff, err := queries.ListTimeOffBetween(ctx, db.ListTimeOffBetweenParams{
+		EndsAt:   from.UTC(),
+		StartsAt: to.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	techs := make([]staffing.Tech, 0, len(staff))
+	for _, member := range staff {
+		tech := staffTech(member, hours)
+		for _, off := range timeOff {
+			if off.StaffID == member.ID {
+				tech.Off = append(tech.Off, staffing.Span{Start: off.StartsAt, End: off.EndsAt})
+			}
+		}
+		for _, job := range jobs {
+			if job.staffID == member.ID {
+				tech.Jobs = append(tech.Jobs, staffing.Span{Start: job.start, End: job.end})
+			}
+		}
+		techs = append(techs, tech)
+	}
+	return techs, nil
+}
+
+// staffTech is member as scheduling sees them, with their shifts picked
+// out of hours. Time off and jobs are left for the caller.
+func staffTech(member db.Staff, hours []db.StaffHour) staffing.Tech {
+	tech := staffing.Tech{
+		ID:     member.ID,
+		Role:   member.Role,
+		Skills: staffing.ParseSkills(member.Skills.String),
+		Hours:  make(map[time.Weekday]staffing.Shift),
+	}
+	for _, h := range hours {
+		if h.StaffID == member.ID {
+			tech.Hours[time.Weekday(h.Weekday)] = staffing.Shift{Start: int(h.StartMinute), End: int(h.EndMinute)}
+		}
+	}
+	return tech
+}
+
+// release gives up one hold on the slot starting at start, for a
+// waitlisted customer claiming the slot held for them.
+func (b *slotBook) release(start time.Time) {
+	if b.holds[slotKey(start)] > 0 {
+		b.holds[slotKey(start)]--
+	}
+}
+
+// open reports whether a job with needs can be booked from start to end.
+// With staff set up, that's when more technicians could take it than
+// there are unassigned jobs and holds already waiting for one.
+func (b *slotBook) open(start, end time.Time, needs jobNeeds) bool {
+	return b.fits(start, end, needs.skills, &needs.stop)
+}
+
+// full reports whether nobody could take another job from start to end,
+// whatever it needs and wherever it is.
+func (b *slotBook) full(start, end time.Time) bool {
+	return !b.fits(start, end, nil, nil)
+}
+
+// fits is open, leaving out the drive to and from neighbouring jobs when
+// stop is nil.
+func (b *slotBook) fits(start, end time.Time, skills []string, stop *servicearea.Stop) bool {
+	job := staffing.Span{Start: start, End: end}
+	waiting := b.holds[slotKey(start)]
+	if b.techs == nil {
+		for _, other := range b.jobs {
+			if (staffing.Span{Start: other.start, End: other.end}).Overlaps(job) {
+				return false
+			}
+		}
+		return waiting == 0 && (stop == nil || !tooFarFrom(b.area, b.jobs, start, end, *stop))
+	}
+
+	assignable := make(map[int64]bool, len(b.techs))
+	for _, tech := range b.techs {
+		assignable[tech.ID] = true
+	}
+	for _, other := range b.jobs {
+		if !assignable[other.staffID] && (staffing.Span{Start: other.start, End: other.end}).Overlaps(job) {
+			waiting++
+		}
+	}
+	free := 0
+	for _, tech := range b.techs {
+		if !tech.Free(job, skills, bookingLocation) {
+			continue
+		}
+		if stop == nil || !tooFarFrom(b.area, b.jobsFor(tech.ID), start, end, *stop) {
+			free++
+		}
+	}
+	return free > waiting
+}
+
+// jobsFor is the jobs assigned to the technician with id.
+func (b *slotBook) jobsFor(id int64) []bookedStop {
+	var jobs []bookedStop
+	for _, job := range b.jobs {
+		if job.staffID == id {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// slotDay is the day around start in the shop's time zone, which holds
+// every job a job at start can clash with or drive between.
+func slotDay(start time.Time) (from, to time.Time) {
+	local := start.In(bookingLocation)
+	from = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, bookingLocation)
+	return from, from.AddDate(0, 0, 1)
+}
+
+// slotLength is how long a job starting at start takes: its slot's
+// length, or a standard slot's for a custom time.
+func slotLength(start time.Time) time.Duration {
+	if slot, ok := matchSlotDefinition(start); ok {
+		return slot.Duration
+	}
+	return bookingSlotDefinitions[0].Duration
+}
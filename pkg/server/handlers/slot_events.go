@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"detailingpass/pkg/broker"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// Comments sent on quiet streams so proxies don't close them
+	slotStreamHeartbeat = 25 * time.Second
+	maxSlotStreams      = 500
+)
+
+// slotEvents tells open booking pages when a slot changes hands
+var slotEvents = broker.New[slotEvent](32)
+
+// slotEvent is a slot that changed. Booking pages refetch availability for
+// the day rather than trusting the event, so kinds are informational.
+type slotEvent struct {
+	Kind  string `json:"kind"`  // booked|freed|held|released|assigned
+	Date  string `json:"date"`  // the slot's day in the shop's time zone
+	Start string `json:"start"` // RFC3339, UTC
+	// Until is when a hold lapses, so pages can refresh then
+	Until string `json:"until,omitempty"`
+}
+
+func publishSlotChange(kind string, starts ...time.Time) {
+	for _, start := range starts {
+		slotEvents.Publish(slotEvent{
+			Kind:  kind,
+			Date:  start.In(bookingLocation).Format("2006-01-02"),
+			Start: slotKey(start),
+		})
+	}
+}
+
+func publishSlotHeld(start, until time.Time) {
+	slotEvents.Publish(slotEvent{
+		Kind:  "held",
+		Date:  start.In(bookingLocation).Format("2006-01-02"),
+		Start: slotKey(start),
+		Until: slotKey(until),
+	})
+}
+
+// bookingStatusBlocks reports whether a booking in status takes its slot,
+// matching ListBlockedSlots.
+func bookingStatusBlocks(status string) bool {
+	return status == "pending" || status == "confirmed" || status == "completed"
+}
+
+// SlotStream sends slot changes to the booking page as server-sent events
+// until the visitor leaves.
+func (h *Handler) SlotStream(c echo.Context) error {
+	if slotEvents.Subscribers() >= maxSlotStreams {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Live updates are busy"})
+	}
+	events, unsubscribe := slotEvents.Subscribe()
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// Stops nginx buffering the stream
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	fmt.Fprint(res, "retry: 5000\n\n")
+	res.Flush()
+
+	heartbeat := time.NewTicker(slotStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(res, "event: slot\ndata: %s\n\n", data); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// How long a slot picked on the booking page is kept from other
+	// visitors while the form is filled in
+	slotHoldTTL = 10 * time.Minute
+	// Holds one visitor can have at once, so nobody can empty the calendar
+	maxSlotHoldsPerIP = 3
+)
+
+type slotHoldRequest struct {
+	Date   string `json:"date"`
+	SlotID string `json:"slot_id"`
+	// Release is the visitor's previous hold, given up for this one
+	Release string `json:"release"`
+}
+
+type slotHoldResponse struct {
+	HoldToken string    `json:"hold_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PlaceSlotHold holds the slot a visitor has picked so nobody else can
+// take it before they submit. The hold lapses on its own; submitting the
+// booking converts it.
+func (h *Handler) PlaceSlotHold(c echo.Context) error {
+	var req slotHoldRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	slotDef, slotStartLocal, errMsg := parseBookingSlot(req.Date, req.SlotID)
+	if errMsg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+	}
+
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+	now := time.Now()
+	if slotStartLocal.After(now.In(bookingLocation).AddDate(0, 0, bookingHorizon(ctx, queries))) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Selected slot is outside our booking window"})
+	}
+
+	if release := strings.TrimSpace(req.Release); release != "" {
+		if err := releaseSlotHold(ctx, queries, release); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to hold slot"})
+		}
+	}
+
+	// Checked and held in one transaction, so two visitors can't both take
+	// the last place in a slot. One kept waiting too long for the others
+	// has most likely lost it to them.
+	tx, err := h.db.BeginTx(ctx, nil)
+	if db.IsBusy(err) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Someone has just picked that time. Choose a different slot."})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to hold slot"})
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	taken, err := slotTaken(ctx, qtx, slotStartLocal, now, "")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to hold slot"})
+	}
+	if taken {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Someone has just picked that time. Choose a different slot."})
+	}
+
+	ip := c.RealIP()
+	active, err := qtx.CountActiveSlotHoldsForIP(ctx, db.CountActiveSlotHoldsForIPParams{
+		ExpiresAt: now.UTC(),
+		IpAddress: sql.NullString{String: ip, Valid: true},
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to hold slot"})
+	}
+	if active >= maxSlotHoldsPerIP {
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "You're holding several times already. Submit a request or wait a few minutes."})
+	}
+
+	token, err := randomHoldToken()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to hold slot"})
+	}
+	hold, err := qtx.CreateSlotHold(ctx, db.CreateSlotHoldParams{
+		Token:     token,
+		SlotStart: slotStartLocal.UTC(),
+		SlotEnd:   slotStartLocal.Add(slotDef.Duration).UTC(),
+		ExpiresAt: now.Add(slotHoldTTL).UTC().Truncate(time.Second),
+		IpAddress: sql.NullString{String: ip, Valid: ip != ""},
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to hold slot"})
+	}
+	if err := tx.Commit(); db.IsBusy(err) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Someone has just picked that time. Choose a different slot."})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to hold slot"})
+	}
+
+	publishSlotHeld(hold.SlotStart, hold.ExpiresAt)
+
+	return c.JSON(http.StatusCreated, slotHoldResponse{HoldToken: hold.Token, ExpiresAt: hold.ExpiresAt})
+}
+
+// ReleaseSlotHold gives a hold up, for when the visitor picks another day
+// or leaves the page. Releasing a hold that has lapsed or been used is
+// not an error.
+func (h *Handler) ReleaseSlotHold(c echo.Context) error {
+	if err := releaseSlotHold(c.Request().Context(), db.New(h.db), c.Param("token")); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to release hold"})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func releaseSlotHold(ctx context.Context, queries *db.Queries, token string) error {
+	hold, err := queries.GetSlotHoldByToken(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	released, err := queries.ReleaseSlotHold(ctx, token)
+	if err != nil {
+		return err
+	}
+	if released > 0 {
+		publishSlotChange("released", hold.SlotStart)
+	}
+	return nil
+}
+
+func randomHoldToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
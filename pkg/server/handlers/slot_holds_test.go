@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"detailingpass/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+// rush sends n visitors at handle at once, each from its own address, and
+// returns the status each got.
+func rush(t *testing.T, n int, method, path, body string, handle echo.HandlerFunc) []int {
+	t.Helper()
+	e := echo.New()
+	codes := make([]int, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+			req.Header.Set(echo.HeaderXRealIP, fmt.Sprintf("203.0.113.%d", i+1))
+			rec := httptest.NewRecorder()
+			if err := handle(e.NewContext(req, rec)); err != nil {
+				t.Error(err)
+			}
+			codes[i] = rec.Code
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return codes
+}
+
+// winners counts the visitors who got want, failing on any status other
+// than want or a conflict.
+func winners(t *testing.T, codes []int, want int) int {
+	t.Helper()
+	won := 0
+	for _, code := range codes {
+		switch code {
+		case want:
+			won++
+		case http.StatusConflict:
+		default:
+			t.Errorf("status %d, want %d or %d", code, want, http.StatusConflict)
+		}
+	}
+	return won
+}
+
+func TestPlaceSlotHoldLastPlace(t *testing.T) {
+	// One technician, so the slot has a single place. The database is on
+	// disk with a connection per visitor, as in production.
+	conn := newTestFileDB(t)
+	slot := addInteriorTech(t, conn)
+	h := &Handler{db: conn}
+
+	body := `{"date": "` + slot.Format("2006-01-02") + `", "slot_id": "morning-detail"}`
+	codes := rush(t, 8, http.MethodPost, "/api/bookings/holds", body, h.PlaceSlotHold)
+	if held := winners(t, codes, http.StatusCreated); held != 1 {
+		t.Errorf("%d visitors held the slot, want 1", held)
+	}
+	holds, err := db.New(conn).CountSlotHoldsAt(context.Background(), db.CountSlotHoldsAtParams{
+		ExpiresAt: time.Now().UTC(),
+		SlotStart: slot.UTC(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if holds != 1 {
+		t.Errorf("%d holds on the slot, want 1", holds)
+	}
+}
+
+func TestCreateBookingRequestLastPlace(t *testing.T) {
+	conn := newTestFileDB(t)
+	slot := addInteriorTech(t, conn)
+	h := &Handler{db: conn}
+
+	body := `{"name": "Dana", "email": "dana@example.com", "service": "interior", "date": "` +
+		slot.Format("2006-01-02") + `", "slot_id": "morning-detail"}`
+	codes := rush(t, 8, http.MethodPost, "/api/bookings", body, h.CreateBookingRequest)
+	if booked := winners(t, codes, http.StatusCreated); booked != 1 {
+		t.Errorf("%d visitors booked the slot, want 1", booked)
+	}
+	var bookings int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM bookings WHERE requested_start = ?`, slot.UTC()).Scan(&bookings); err != nil {
+		t.Fatal(err)
+	}
+	if bookings != 1 {
+		t.Errorf("%d bookings in the slot, want 1", bookings)
+	}
+}
+
+func TestCreateBookingRequestHold(t *testing.T) {
+	tests := []struct {
+		name    string
+		release bool
+		want    int
+	}{
+		{name: "held", want: http.StatusCreated},
+		{name: "released", release: true, want: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, slot := waitlistFixture(t)
+			h := &Handler{db: conn}
+			e := echo.New()
+			date := slot.Format("2006-01-02")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/bookings/holds", bytes.NewBufferString(`{"date": "`+date+`", "slot_id": "morning-detail"}`))
+			rec := httptest.NewRecorder()
+			if err := h.PlaceSlotHold(e.NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			var hold slotHoldResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &hold); err != nil || hold.HoldToken == "" {
+				t.Fatalf("hold = %s, %v", rec.Body, err)
+			}
+			if tt.release {
+				if err := releaseSlotHold(context.Background(), db.New(conn), hold.HoldToken); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			body := fmt.Sprintf(`{"name": "Dana", "email": "dana@example.com", "service": "interior", "date": %q, "slot_id": "morning-detail", "hold_token": %q}`, date, hold.HoldToken)
+			req = httptest.NewRequest(http.MethodPost, "/api/bookings", bytes.NewBufferString(body))
+			rec = httptest.NewRecorder()
+			if err := h.CreateBookingRequest(e.NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.want {
+				t.Fatalf("CreateBookingRequest = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+
+			var bookings, converted int
+			if err := conn.QueryRow(`SELECT COUNT(*) FROM bookings`).Scan(&bookings); err != nil {
+				t.Fatal(err)
+			}
+			if err := conn.QueryRow(`SELECT COUNT(*) FROM slot_holds WHERE status = 'converted' AND booking_id IS NOT NULL`).Scan(&converted); err != nil {
+				t.Fatal(err)
+			}
+			if want := btoi(tt.want == http.StatusCreated); bookings != want || converted != want {
+				t.Errorf("%d bookings and %d converted holds, want %d of each", bookings, converted, want)
+			}
+		})
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,634 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/auth"
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/staffing"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+// How far ahead a technician's schedule is shown
+const staffScheduleDays = 14
+
+func (h *Handler) AdminStaff(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	rows, err := queries.ListStaff(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch staff")
+	}
+	hours, err := queries.ListJobStaffHours(ctx)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch staff hours: %v", err)
+	}
+
+	data := pages.AdminStaffData{
+		Form:         pages.StaffForm{Role: "technician", Active: true},
+		Scheduling:   len(hours) > 0,
+		ErrorMessage: c.QueryParam("error"),
+	}
+	for _, row := range rows {
+		data.Staff = append(data.Staff, staffListItem(row, hours))
+	}
+
+	return pages.AdminStaff(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) CreateStaff(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	params, errMsg := parseStaffForm(c)
+	if errMsg != "" {
+		return staffListRedirect(c, errMsg)
+	}
+	member, err := db.New(h.db).CreateStaff(ctx, params)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to add staff: %v", err))
+	}
+
+	// Hours come next; until they're set the new hire takes no jobs
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/staff/%d", member.ID))
+}
+
+func (h *Handler) AdminStaffMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid staff ID")
+	}
+	member, err := queries.GetStaffByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Staff member not found")
+	}
+	hours, err := queries.ListStaffHours(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch hours")
+	}
+	schedule, timeOff, err := staffSchedule(ctx, queries, member, time.Now())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch schedule")
+	}
+
+	tech := staffTech(member, hours)
+	data := pages.AdminStaffMemberData{
+		Member: staffListItem(member, hours),
+		Form: pages.StaffForm{
+			ID:     member.ID,
+			Name:   member.Name,
+			Email:  nullableString(member.Email),
+			Phone:  nullableString(member.Phone),
+			Role:   member.Role,
+			Skills: staffing.ParseSkills(member.Skills.String),
+			Active: member.IsActive.Valid && member.IsActive.Bool,
+		},
+		Schedule:     schedule,
+		TimeOff:      timeOff,
+		ErrorMessage: c.QueryParam("error"),
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		row := pages.StaffHoursRow{Weekday: int(day), Label: day.String(), Start: "08:00", End: "17:00"}
+		if shift, ok := tech.Hours[day]; ok {
+			row.Working = true
+			row.Start = staffing.Clock(shift.Start)
+			row.End = staffing.Clock(shift.End)
+		}
+		data.Hours = append(data.Hours, row)
+	}
+
+	return pages.AdminStaffMember(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) UpdateStaff(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid staff ID")
+	}
+	params, errMsg := parseStaffForm(c)
+	if errMsg != "" {
+		return staffRedirect(c, id, errMsg)
+	}
+	_, err = db.New(h.db).UpdateStaff(ctx, db.UpdateStaffParams{
+		Name:     params.Name,
+		Email:    params.Email,
+		Phone:    params.Phone,
+		Role:     params.Role,
+		Skills:   params.Skills,
+		IsActive: params.IsActive,
+		ID:       id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.String(http.StatusNotFound, "Staff member not found")
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update staff: %v", err))
+	}
+
+	return staffRedirect(c, id, "")
+}
+
+// DeleteStaff removes someone who never should have been added. Their
+// jobs go back to being unassigned; to keep their history, mark them
+// inactive instead.
+func (h *Handler) DeleteStaff(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid staff ID")
+	}
+	if err := db.New(h.db).DeleteStaff(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete staff: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/staff")
+}
+
+// UpdateStaffHours replaces someone's weekly shifts. Jobs already
+// assigned outside the new hours are kept; the schedule shows them.
+func (h *Handler) UpdateStaffHours(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid staff ID")
+	}
+	if _, err := queries.GetStaffByID(ctx, id); err != nil {
+		return c.String(http.StatusNotFound, "Staff member not found")
+	}
+
+	var shifts []db.CreateStaffHoursParams
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		n := strconv.Itoa(int(day))
+		if c.FormValue("works_"+n) != "true" {
+			continue
+		}
+		start, err := staffing.ParseClock(c.FormValue("start_" + n))
+		if err != nil {
+			return staffRedirect(c, id, "Enter when "+day.String()+"'s shift starts")
+		}
+		end, err := staffing.ParseClock(c.FormValue("end_" + n))
+		if err != nil {
+			return staffRedirect(c, id, "Enter when "+day.String()+"'s shift ends")
+		}
+		if end <= start {
+			return staffRedirect(c, id, day.String()+"'s shift has to end after it starts")
+		}
+		shifts = append(shifts, db.CreateStaffHoursParams{
+			StaffID:     id,
+			Weekday:     int64(day),
+			StartMinute: int64(start),
+			EndMinute:   int64(end),
+		})
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save hours")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	if err := qtx.DeleteStaffHours(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save hours")
+	}
+	for _, shift := range shifts {
+		if err := qtx.CreateStaffHours(ctx, shift); err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to save hours")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save hours")
+	}
+
+	return staffRedirect(c, id, "")
+}
+
+// CreateStaffTimeOff books time off. Jobs already assigned in it stay
+// assigned, and the admin is told to move them.
+func (h *Handler) CreateStaffTimeOff(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid staff ID")
+	}
+	if _, err := queries.GetStaffByID(ctx, id); err != nil {
+		return c.String(http.StatusNotFound, "Staff member not found")
+	}
+
+	startsAt, err := time.ParseInLocation("2006-01-02T15:04", c.FormValue("starts_at"), bookingLocation)
+	if err != nil {
+		return staffRedirect(c, id, "Enter when the time off starts")
+	}
+	endsAt, err := time.ParseInLocation("2006-01-02T15:04", c.FormValue("ends_at"), bookingLocation)
+	if err != nil {
+		return staffRedirect(c, id, "Enter when the time off ends")
+	}
+	if !endsAt.After(startsAt) {
+		return staffRedirect(c, id, "Time off has to end after it starts")
+	}
+	reason := strings.TrimSpace(c.FormValue("reason"))
+	_, err = queries.CreateStaffTimeOff(ctx, db.CreateStaffTimeOffParams{
+		StaffID:  id,
+		StartsAt: startsAt.UTC(),
+		EndsAt:   endsAt.UTC(),
+		Reason:   sql.NullString{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save time off")
+	}
+
+	clashes, err := queries.CountStaffBookingsOverlapping(ctx, db.CountStaffBookingsOverlappingParams{
+		StaffID:        sql.NullInt64{Int64: id, Valid: true},
+		RequestedStart: endsAt.UTC(),
+		RequestedEnd:   startsAt.UTC(),
+	})
+	if err != nil {
+		c.Logger().Warnf("Failed to check jobs during time off: %v", err)
+	}
+	if clashes > 0 {
+		return staffRedirect(c, id, fmt.Sprintf("Time off saved. They have %d %s in it to reassign from the bookings page.", clashes, pluralJobs(clashes)))
+	}
+	return staffRedirect(c, id, "")
+}
+
+func (h *Handler) DeleteStaffTimeOff(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid staff ID")
+	}
+	offID, err := strconv.ParseInt(c.Param("offID"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid time off ID")
+	}
+	if err := db.New(h.db).DeleteStaffTimeOff(ctx, db.DeleteStaffTimeOffParams{ID: offID, StaffID: id}); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to delete time off")
+	}
+
+	return staffRedirect(c, id, "")
+}
+
+// AssignBooking gives a booking to a technician, or takes it off them
+// when staff_id is empty. The technician has to have the skills the
+// service needs, and be working and free for the whole job.
+func (h *Handler) AssignBooking(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid booking ID")
+	}
+	booking, err := queries.GetBookingByID(ctx, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Booking not found")
+	}
+
+	var staffID sql.NullInt64
+	if raw := strings.TrimSpace(c.FormValue("staff_id")); raw != "" {
+		staffID.Int64, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid staff ID")
+		}
+		staffID.Valid = true
+	}
+
+	if staffID.Valid && staffID.Int64 != booking.StaffID.Int64 {
+		member, err := queries.GetStaffByID(ctx, staffID.Int64)
+		if err != nil || !member.IsActive.Bool {
+			return bookingsErrorRedirect(c, "That technician isn't on the team any more.")
+		}
+		if err := checkAssignment(ctx, queries, member, booking); err != nil {
+			if msg := staffingErrorMessage(err); msg != "" {
+				return bookingsErrorRedirect(c, fmt.Sprintf("Can't give %s's job to %s. %s", booking.CustomerName, member.Name, msg))
+			}
+			return c.String(http.StatusInternalServerError, "Failed to assign booking")
+		}
+	}
+
+	if err := queries.AssignBooking(ctx, db.AssignBookingParams{StaffID: staffID, ID: id}); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to assign booking")
+	}
+
+	// Assigning a job can leave its slot with room for another, or none
+	if bookingStatusBlocks(normalizeBookingStatus(booking.Status.String)) {
+		publishSlotChange("assigned", booking.RequestedStart)
+	}
+
+	return c.Redirect(http.StatusSeeOther, bookingsRedirect(c))
+}
+
+// checkAssignment returns why member can't take booking, or nil if they
+// can.
+func checkAssignment(ctx context.Context, queries *db.Queries, member db.Staff, booking db.Booking) error {
+	skills, err := serviceSkills(ctx, queries, booking.ServiceInterest.String)
+	if err != nil {
+		return err
+	}
+	hours, err := queries.ListStaffHours(ctx, member.ID)
+	if err != nil {
+		return err
+	}
+	job := staffing.Span{Start: booking.RequestedStart, End: booking.RequestedEnd}
+	timeOff, err := queries.ListTimeOffBetween(ctx, db.ListTimeOffBetweenParams{
+		EndsAt:   job.Start.UTC(),
+		StartsAt: job.End.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	from, to := slotDay(booking.RequestedStart)
+	jobs, err := queries.ListStaffBookings(ctx, db.ListStaffBookingsParams{
+		StaffID:          sql.NullInt64{Int64: member.ID, Valid: true},
+		RequestedStart:   from.UTC(),
+		RequestedStart_2: to.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	tech := staffTech(member, hours)
+	for _, off := range timeOff {
+		if off.StaffID == member.ID {
+			tech.Off = append(tech.Off, staffing.Span{Start: off.StartsAt, End: off.EndsAt})
+		}
+	}
+	for _, other := range jobs {
+		if other.ID != booking.ID {
+			tech.Jobs = append(tech.Jobs, staffing.Span{Start: other.RequestedStart, End: other.RequestedEnd})
+		}
+	}
+	return tech.Check(job, skills, bookingLocation)
+}
+
+// staffingErrorMessages words the staffing errors for the admin.
+var staffingErrorMessages = []struct {
+	err     error
+	message string
+}{
+	{staffing.ErrRole, "Only technicians and leads are assigned jobs."},
+	{staffing.ErrSkills, "They don't have the skills this service needs."},
+	{staffing.ErrHours, "They aren't working then."},
+	{staffing.ErrOff, "They have time off then."},
+	{staffing.ErrBusy, "They're on another job then."},
+}
+
+// staffingErrorMessage is what to tell the admin when err is a staffing
+// error, or "" for database failures.
+func staffingErrorMessage(err error) string {
+	for _, m := range staffingErrorMessages {
+		if errors.Is(err, m.err) {
+			return m.message
+		}
+	}
+	return ""
+}
+
+// MySchedule shows signed-in staff the jobs they're assigned over the
+// next two weeks, with their hours and time off. Staff are matched to
+// their account by email.
+func (h *Handler) MySchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	data := pages.MySchedulePageData{}
+	info := auth.GetUserInfo(ctx)
+	if info != nil {
+		data.FirstName = info.FirstName
+		data.Email = info.Email
+	}
+	member, ok := currentStaff(ctx, queries)
+	if !ok {
+		return pages.MySchedulePage(data).Render(ctx, c.Response().Writer)
+	}
+
+	schedule, timeOff, err := staffSchedule(ctx, queries, member, time.Now())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load your schedule")
+	}
+	hours, err := queries.ListStaffHours(ctx, member.ID)
+	if err != nil {
+		c.Logger().Warnf("Failed to fetch staff hours: %v", err)
+	}
+	item := staffListItem(member, hours)
+	data.Staff = &item
+	data.Schedule = schedule
+	data.TimeOff = timeOff
+
+	return pages.MySchedulePage(data).Render(ctx, c.Response().Writer)
+}
+
+// currentStaff is the active staff record for the signed-in account.
+func currentStaff(ctx context.Context, queries *db.Queries) (db.Staff, bool) {
+	info := auth.GetUserInfo(ctx)
+	if info == nil || info.Email == "" {
+		return db.Staff{}, false
+	}
+	member, err := queries.GetActiveStaffByEmail(ctx, sql.NullString{String: strings.ToLower(info.Email), Valid: true})
+	if err != nil {
+		return db.Staff{}, false
+	}
+	return member, true
+}
+
+// staffSchedule lays out member's next staffScheduleDays days from now:
+// their shift, any time off and the jobs assigned to them. It also
+// returns their time off still to come.
+func staffSchedule(ctx context.Context, queries *db.Queries, member db.Staff, now time.Time) ([]pages.StaffScheduleDay, []pages.StaffTimeOffView, error) {
+	hours, err := queries.ListStaffHours(ctx, member.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	timeOff, err := queries.ListStaffTimeOff(ctx, db.ListStaffTimeOffParams{StaffID: member.ID, EndsAt: now.UTC()})
+	if err != nil {
+		return nil, nil, err
+	}
+	from, _ := slotDay(now)
+	to := from.AddDate(0, 0, staffScheduleDays)
+	jobs, err := queries.ListStaffBookings(ctx, db.ListStaffBookingsParams{
+		StaffID:          sql.NullInt64{Int64: member.ID, Valid: true},
+		RequestedStart:   from.UTC(),
+		RequestedStart_2: to.UTC(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tech := staffTech(member, hours)
+	var days []pages.StaffScheduleDay
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		view := pages.StaffScheduleDay{Label: day.Format("Monday, Jan 2"), Hours: "Not working"}
+		if shift, ok := tech.Hours[day.Weekday()]; ok {
+			view.Working = true
+			view.Hours = shift.Label()
+		}
+		for _, off := range timeOff {
+			if (staffing.Span{Start: off.StartsAt, End: off.EndsAt}).Overlaps(staffing.Span{Start: day, End: next}) {
+				view.TimeOff = timeOffLabel(off.Reason)
+			}
+		}
+		for _, job := range jobs {
+			if !job.RequestedStart.Before(day) && job.RequestedStart.Before(next) {
+				view.Jobs = append(view.Jobs, staffJobView(job))
+			}
+		}
+		days = append(days, view)
+	}
+
+	views := make([]pages.StaffTimeOffView, 0, len(timeOff))
+	for _, off := range timeOff {
+		views = append(views, pages.StaffTimeOffView{
+			ID:     off.ID,
+			Range:  timeOffRangeLabel(off.StartsAt, off.EndsAt),
+			Reason: nullableString(off.Reason),
+		})
+	}
+	return days, views, nil
+}
+
+func staffJobView(row db.Booking) pages.StaffJobView {
+	_, window := resolveSlotDetails(row.RequestedStart, row.RequestedEnd)
+	view := pages.StaffJobView{
+		BookingID: row.ID,
+		Window:    window,
+		Customer:  row.CustomerName,
+		Phone:     nullableString(row.Phone),
+		Service:   nullableString(row.ServiceInterest),
+		Vehicle:   nullableString(row.VehicleDetails),
+		Notes:     nullableString(row.Notes),
+		Status:    normalizeBookingStatus(row.Status.String),
+	}
+	if row.ServiceAddress.Valid {
+		view.Location = row.ServiceAddress.String + " " + row.ServiceZip.String
+	}
+	return view
+}
+
+func staffListItem(member db.Staff, hours []db.StaffHour) pages.StaffListItem {
+	item := pages.StaffListItem{
+		ID:       member.ID,
+		Name:     member.Name,
+		Email:    nullableString(member.Email),
+		Phone:    nullableString(member.Phone),
+		Role:     staffing.RoleLabel(member.Role),
+		TakesJob: staffing.DoesJobs(member.Role),
+		Active:   member.IsActive.Valid && member.IsActive.Bool,
+	}
+	for _, skill := range staffing.ParseSkills(member.Skills.String) {
+		item.Skills = append(item.Skills, staffing.SkillLabel(skill))
+	}
+	var days, minutes int64
+	for _, h := range hours {
+		if h.StaffID == member.ID {
+			days++
+			minutes += h.EndMinute - h.StartMinute
+		}
+	}
+	if days > 0 {
+		item.Hours = fmt.Sprintf("%d %s · %g hrs a week", days, pluralDays(days), float64(minutes)/60)
+	}
+	return item
+}
+
+func parseStaffForm(c echo.Context) (db.CreateStaffParams, string) {
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return db.CreateStaffParams{}, "Enter their name"
+	}
+	role := c.FormValue("role")
+	if !staffing.ValidRole(role) {
+		return db.CreateStaffParams{}, "Choose a role"
+	}
+	email := strings.ToLower(strings.TrimSpace(c.FormValue("email")))
+	if email != "" && !strings.Contains(email, "@") {
+		return db.CreateStaffParams{}, "Enter a valid email, or leave it blank"
+	}
+	phone := strings.TrimSpace(c.FormValue("phone"))
+	var skills string
+	if form, err := c.FormParams(); err == nil {
+		skills = staffing.JoinSkills(form["skills"])
+	}
+	return db.CreateStaffParams{
+		Name:     name,
+		Email:    sql.NullString{String: email, Valid: email != ""},
+		Phone:    sql.NullString{String: phone, Valid: phone != ""},
+		Role:     role,
+		Skills:   sql.NullString{String: skills, Valid: skills != ""},
+		IsActive: sql.NullBool{Bool: c.FormValue("is_active") == "true", Valid: true},
+	}, ""
+}
+
+func timeOffRangeLabel(start, end time.Time) string {
+	start, end = start.In(bookingLocation), end.In(bookingLocation)
+	if start.Format("2006-01-02") == end.Format("2006-01-02") {
+		return start.Format("Mon, Jan 2 3:04 PM") + " – " + end.Format("3:04 PM")
+	}
+	return start.Format("Mon, Jan 2 3:04 PM") + " – " + end.Format("Mon, Jan 2 3:04 PM")
+}
+
+func timeOffLabel(reason sql.NullString) string {
+	if reason.Valid && reason.String != "" {
+		return reason.String
+	}
+	return "Time off"
+}
+
+func pluralJobs(n int64) string {
+	if n == 1 {
+		return "job"
+	}
+	return "jobs"
+}
+
+func pluralDays(n int64) string {
+	if n == 1 {
+		return "day"
+	}
+	return "days"
+}
+
+func staffListRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/staff?error="+url.QueryEscape(errMsg))
+}
+
+func staffRedirect(c echo.Context, id int64, errMsg string) error {
+	target := fmt.Sprintf("/admin/staff/%d", id)
+	if errMsg != "" {
+		target += "?error=" + url.QueryEscape(errMsg)
+	}
+	return c.Redirect(http.StatusSeeOther, target)
+}
+
+// bookingsErrorRedirect returns to the bookings page the form was posted
+// from with errMsg shown.
+func bookingsErrorRedirect(c echo.Context, errMsg string) error {
+	target := bookingsRedirect(c)
+	if strings.Contains(target, "?") {
+		target += "&"
+	} else {
+		target += "?"
+	}
+	return c.Redirect(http.StatusSeeOther, target+"error="+url.QueryEscape(errMsg))
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"detailingpass/pkg/db"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminTaxRates manages the tax rates offered on invoices. Invoices copy
+// the rate when it is chosen, so editing one here doesn't change invoices
+// already written.
+func (h *Handler) AdminTaxRates(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	rates, err := queries.ListTaxRates(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch tax rates")
+	}
+
+	data := pages.AdminTaxRatesData{
+		Rates:        rates,
+		ErrorMessage: c.QueryParam("error"),
+	}
+	if id, err := strconv.ParseInt(c.QueryParam("edit"), 10, 64); err == nil {
+		for _, r := range rates {
+			if r.ID == id {
+				rate := r
+				data.Editing = &rate
+			}
+		}
+	}
+
+	return pages.AdminTaxRates(data).Render(ctx, c.Response().Writer)
+}
+
+func (h *Handler) CreateTaxRate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	params, errMsg := parseTaxRateForm(c)
+	if errMsg != "" {
+		return taxRatesRedirect(c, errMsg)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create tax rate")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	rate, err := qtx.CreateTaxRate(ctx, params)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to create tax rate: %v", err))
+	}
+	if rate.IsDefault.Bool {
+		if err := qtx.ClearDefaultTaxRate(ctx, rate.ID); err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to create tax rate")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create tax rate")
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/tax-rates")
+}
+
+func (h *Handler) UpdateTaxRate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid tax rate ID")
+	}
+	params, errMsg := parseTaxRateForm(c)
+	if errMsg != "" {
+		return taxRatesRedirect(c, errMsg)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update tax rate")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	err = qtx.UpdateTaxRate(ctx, db.UpdateTaxRateParams{
+		Name:      params.Name,
+		Rate:      params.Rate,
+		IsDefault: params.IsDefault,
+		IsActive:  params.IsActive,
+		ID:        id,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to update tax rate: %v", err))
+	}
+	if params.IsDefault.Bool {
+		if err := qtx.ClearDefaultTaxRate(ctx, id); err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to update tax rate")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update tax rate")
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/tax-rates")
+}
+
+func (h *Handler) DeleteTaxRate(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid tax rate ID")
+	}
+	if err := queries.DeleteTaxRate(ctx, id); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Failed to delete tax rate: %v", err))
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/admin/tax-rates")
+}
+
+func parseTaxRateForm(c echo.Context) (db.CreateTaxRateParams, string) {
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return db.CreateTaxRateParams{}, "Name is required"
+	}
+	rate, err := parsePercent(c.FormValue("rate"))
+	if err != nil || rate == 0 {
+		return db.CreateTaxRateParams{}, "Enter the rate as a percentage, e.g. 8.25"
+	}
+	active := c.FormValue("is_active") == "true"
+
+	return db.CreateTaxRateParams{
+		Name:      name,
+		Rate:      rate,
+		IsDefault: sql.NullBool{Bool: active && c.FormValue("is_default") == "true", Valid: true},
+		IsActive:  sql.NullBool{Bool: active, Valid: true},
+	}, ""
+}
+
+func taxRatesRedirect(c echo.Context, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/admin/tax-rates?error="+url.QueryEscape(errMsg))
+}
@@ -0,0 +1,505 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"detailingpass/pkg/auth"
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+	"detailingpass/web/templates/pages"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	waitlistLinkPurpose = "waitlist"
+	// How long a freed slot is held for each customer in turn
+	waitlistOfferTTL = 2 * time.Hour
+	// The longest range of days one entry can wait on
+	waitlistMaxDays = 14
+)
+
+type waitlistRequest struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Phone   string `json:"phone"`
+	Vehicle string `json:"vehicle"`
+	Service string `json:"service"`
+	Notes   string `json:"notes"`
+	// DateFrom and DateTo are the first and last days wanted; DateTo
+	// defaults to DateFrom
+	DateFrom string `json:"date_from"`
+	DateTo   string `json:"date_to"`
+	// SlotID is empty for any slot
+	SlotID string `json:"slot_id"`
+}
+
+// offerFreedSlot offers a slot that has just opened up to the first
+// customer waiting for it whose service it can take, holding it for them
+// until the offer expires. Customers needing a technician who isn't free
+// then are passed over, as are those whose email can't be sent. Nothing
+// happens if the slot is past or already offered, or if customer links are
+// off.
+func (h *Handler) offerFreedSlot(ctx context.Context, queries *db.Queries, start time.Time, now time.Time) error {
+	if h.links == nil || !start.After(now) {
+		return nil
+	}
+	slot, ok := matchSlotDefinition(start)
+	if !ok {
+		return nil
+	}
+	entries, err := queries.ListWaitlistEntriesForSlot(ctx, db.ListWaitlistEntriesForSlotParams{
+		SlotStart: start.UTC(),
+		SlotID:    slot.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	end := start.Add(slot.Duration)
+	expires := now.Add(waitlistOfferTTL).UTC().Truncate(time.Second)
+	if expires.After(start) {
+		expires = start.UTC()
+	}
+	var sendErr error
+	for _, entry := range entries {
+		skills, err := serviceSkills(ctx, queries, entry.ServiceInterest.String)
+		if err != nil {
+			return err
+		}
+		taken, err := h.slotTakenFor(ctx, queries, start, end, now, "", jobNeeds{skills: skills})
+		if err != nil {
+			return err
+		}
+		if taken {
+			continue
+		}
+		offer, err := queries.CreateWaitlistOffer(ctx, db.CreateWaitlistOfferParams{
+			EntryID:   entry.ID,
+			SlotStart: start.UTC(),
+			SlotEnd:   end.UTC(),
+			ExpiresAt: expires,
+		})
+		if err != nil {
+			return err
+		}
+		sendErr = h.sendWaitlistOffer(ctx, entry, offer)
+		if sendErr == nil {
+			return nil
+		}
+		if _, err := queries.CloseWaitlistOffer(ctx, db.CloseWaitlistOfferParams{Status: "expired", ID: offer.ID}); err != nil {
+			return err
+		}
+	}
+	return sendErr
+}
+
+func (h *Handler) sendWaitlistOffer(ctx context.Context, entry db.WaitlistEntry, offer db.WaitlistOffer) error {
+	slotLabel, slotWindow := resolveSlotDetails(offer.SlotStart, offer.SlotEnd)
+	// The link outlives the hold so the customer can still see what
+	// happened to the offer
+	link := h.customerLink("/waitlist/claim/", waitlistLinkPurpose, offer.ID, offer.SlotEnd)
+	text := fmt.Sprintf(
+		"Hi %s,\n\nGood news: a spot has opened up for %s on %s (%s).\n\n"+
+			"We're holding it for you until %s. Book it here:\n%s\n\n"+
+			"If you can't make it, the same link lets you pass so we can offer it to the next person. "+
+			"Either way you'll stay on the waitlist for your other dates.\n",
+		customerFirstName(entry.CustomerName),
+		slotLabel,
+		offer.SlotStart.In(bookingLocation).Format("Monday, January 2"),
+		slotWindow,
+		offer.ExpiresAt.In(bookingLocation).Format("3:04 PM on Jan 2"),
+		link,
+	)
+	return h.mailer.Send(ctx, mailer.Message{
+		To:      []string{entry.Email},
+		Subject: "A spot has opened up",
+		Text:    text,
+	})
+}
+
+// offerFreedSlots offers each freed slot in turn, then tells open booking
+// pages. It works in the background so the request that freed the slots
+// doesn't wait on email. Failures are logged; the slot is still free to
+// book.
+func (h *Handler) offerFreedSlots(starts []time.Time) {
+	h.goBackground(func(ctx context.Context) {
+		queries := db.New(h.db)
+		now := time.Now()
+		for _, start := range starts {
+			if err := h.offerFreedSlot(ctx, queries, start, now); err != nil {
+				log.Printf("Failed to offer %s to the waitlist: %v", slotKey(start), err)
+			}
+		}
+		publishSlotChange("freed", starts...)
+	})
+}
+
+// advanceWaitlist closes offers that ran out without an answer and offers
+// their slots to the next customer waiting. RunJobs calls it; a lapsed
+// offer stops holding its slot when it expires, so the slot is bookable
+// in the meantime.
+func (h *Handler) advanceWaitlist(ctx context.Context) error {
+	queries := db.New(h.db)
+	now := time.Now()
+	lapsed, err := queries.ListLapsedWaitlistOffers(ctx, now.UTC())
+	if err != nil {
+		return err
+	}
+	for _, offer := range lapsed {
+		closed, err := queries.CloseWaitlistOffer(ctx, db.CloseWaitlistOfferParams{Status: "expired", ID: offer.ID})
+		if err != nil {
+			return err
+		}
+		if closed == 0 {
+			continue
+		}
+		if err := h.offerFreedSlot(ctx, queries, offer.SlotStart, now); err != nil {
+			return fmt.Errorf("offer %d: %w", offer.ID, err)
+		}
+		publishSlotChange("released", offer.SlotStart)
+	}
+	return nil
+}
+
+// JoinWaitlist adds a customer to the waitlist for a range of days, and
+// optionally one slot. It's closed while customer links are off, since
+// there'd be no way to send an offer.
+func (h *Handler) JoinWaitlist(c echo.Context) error {
+	if h.links == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "The waitlist isn't open at the moment"})
+	}
+	var req waitlistRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
+	req.Phone = strings.TrimSpace(req.Phone)
+	if req.Name == "" || req.Email == "" || req.DateFrom == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name, email, and date are required"})
+	}
+	if strings.TrimSpace(req.DateTo) == "" {
+		req.DateTo = req.DateFrom
+	}
+
+	var slotLabel string
+	if req.SlotID != "" {
+		slot, ok := slotLookup[req.SlotID]
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid slot selection"})
+		}
+		slotLabel = slot.Label
+	}
+
+	from, errFrom := time.ParseInLocation("2006-01-02", req.DateFrom, bookingLocation)
+	to, errTo := time.ParseInLocation("2006-01-02", req.DateTo, bookingLocation)
+	if errFrom != nil || errTo != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid date format"})
+	}
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	// Customers can wait on the days they could book, so members can wait
+	// further out
+	now := time.Now().In(bookingLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, bookingLocation)
+	switch {
+	case from.Before(today) || to.Before(from):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Choose dates from today onwards"})
+	case to.After(today.AddDate(0, 0, bookingHorizon(ctx, queries))):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Selected dates are outside our booking window"})
+	case to.After(from.AddDate(0, 0, waitlistMaxDays-1)):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("You can wait on up to %d days at a time", waitlistMaxDays)})
+	}
+
+	clerkUserID := auth.GetUserID(ctx)
+	_, err := queries.CreateWaitlistEntry(ctx, db.CreateWaitlistEntryParams{
+		CustomerName:    req.Name,
+		Email:           req.Email,
+		Phone:           sql.NullString{String: req.Phone, Valid: req.Phone != ""},
+		VehicleDetails:  sql.NullString{String: strings.TrimSpace(req.Vehicle), Valid: strings.TrimSpace(req.Vehicle) != ""},
+		ServiceInterest: sql.NullString{String: strings.TrimSpace(req.Service), Valid: strings.TrimSpace(req.Service) != ""},
+		Notes:           sql.NullString{String: strings.TrimSpace(req.Notes), Valid: strings.TrimSpace(req.Notes) != ""},
+		DateFrom:        from.UTC(),
+		DateTo:          to.AddDate(0, 0, 1).UTC(),
+		SlotID:          sql.NullString{String: req.SlotID, Valid: req.SlotID != ""},
+		ClerkUserID:     sql.NullString{String: clerkUserID, Valid: clerkUserID != ""},
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to join the waitlist"})
+	}
+
+	message := "You're on the waitlist for " + waitlistRangeLabel(from, to, slotLabel) +
+		". If a spot opens up we'll email you a link to claim it."
+	return c.JSON(http.StatusCreated, map[string]string{"message": message})
+}
+
+func waitlistRangeLabel(from, to time.Time, slotLabel string) string {
+	label := from.Format("Mon, Jan 2")
+	if !to.Equal(from) {
+		label += " – " + to.Format("Mon, Jan 2")
+	}
+	if slotLabel != "" {
+		label += " (" + slotLabel + ")"
+	}
+	return label
+}
+
+// WaitlistClaim shows a waitlist offer, for the customer to book or pass.
+func (h *Handler) WaitlistClaim(c echo.Context) error {
+	ctx := c.Request().Context()
+	data, _, _ := h.loadWaitlistOffer(ctx, db.New(h.db), c.Param("token"))
+	data.Error = c.QueryParam("error")
+	return pages.WaitlistClaim(data).Render(ctx, c.Response().Writer)
+}
+
+// ClaimWaitlistOffer books the held slot as a request, like one made from
+// the booking page, with a deposit when one is taken.
+func (h *Handler) ClaimWaitlistOffer(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	token := c.Param("token")
+	data, offer, entry := h.loadWaitlistOffer(ctx, queries, token)
+	if data.State != pages.WaitlistOfferOpen {
+		return c.Redirect(http.StatusSeeOther, "/waitlist/claim/"+token)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to book slot")
+	}
+	defer tx.Rollback()
+	qtx := queries.WithTx(tx)
+
+	// The offer holds the slot against the booking page, but not against
+	// a booking the shop made by hand
+	from, to := slotDay(offer.SlotStart)
+	book, err := loadSlotBook(ctx, qtx, from, to, time.Now(), "")
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to book slot")
+	}
+	book.release(offer.SlotStart)
+	if book.full(offer.SlotStart, offer.SlotEnd) {
+		return waitlistClaimRedirect(c, token, "Sorry, that time has been taken. You're still on the waitlist.")
+	}
+
+	booking, err := qtx.CreateBooking(ctx, db.CreateBookingParams{
+		CustomerName:    entry.CustomerName,
+		Email:           entry.Email,
+		Phone:           entry.Phone,
+		VehicleDetails:  entry.VehicleDetails,
+		ServiceInterest: entry.ServiceInterest,
+		Notes:           entry.Notes,
+		RequestedStart:  offer.SlotStart,
+		RequestedEnd:    offer.SlotEnd,
+		Status:          sql.NullString{String: "pending", Valid: true},
+		Source:          sql.NullString{String: "waitlist", Valid: true},
+		ClerkUserID:     entry.ClerkUserID,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to book slot")
+	}
+	claimed, err := qtx.ClaimWaitlistOffer(ctx, db.ClaimWaitlistOfferParams{
+		BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+		ID:        offer.ID,
+		ExpiresAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to book slot")
+	}
+	if claimed == 0 {
+		return c.Redirect(http.StatusSeeOther, "/waitlist/claim/"+token)
+	}
+	err = qtx.MarkWaitlistEntryBooked(ctx, db.MarkWaitlistEntryBookedParams{
+		BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+		ID:        entry.ID,
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to book slot")
+	}
+
+	var deposit db.Payment
+	if h.bookingDeposit > 0 {
+		deposit, err = qtx.CreatePayment(ctx, db.CreatePaymentParams{
+			BookingID: sql.NullInt64{Int64: booking.ID, Valid: true},
+			Kind:      "deposit",
+			Provider:  h.payments.Name(),
+			Amount:    h.bookingDeposit,
+			Currency:  "usd",
+		})
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to book slot")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to book slot")
+	}
+	publishSlotChange("booked", offer.SlotStart)
+
+	if deposit.ID == 0 {
+		return c.Redirect(http.StatusSeeOther, "/waitlist/claim/"+token)
+	}
+	// Unlike a request from the booking page, the customer can't just try
+	// again, so a checkout that won't start leaves the request pending for
+	// them to pay from the deposit page.
+	paymentURL, err := h.startDepositCheckout(ctx, queries, booking, deposit)
+	if err != nil {
+		c.Logger().Warnf("Failed to start deposit checkout for booking %d: %v", booking.ID, err)
+		return c.Redirect(http.StatusSeeOther, h.depositLink(booking)+"?error="+
+			url.QueryEscape("We couldn't reach our payment provider. Please try again in a moment."))
+	}
+	if paymentURL == "" {
+		return c.Redirect(http.StatusSeeOther, h.depositLink(booking))
+	}
+	return c.Redirect(http.StatusSeeOther, paymentURL)
+}
+
+// PassWaitlistOffer turns an offer down and offers the slot to the next
+// customer. The customer stays on the waitlist for their other dates.
+func (h *Handler) PassWaitlistOffer(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	token := c.Param("token")
+	data, offer, _ := h.loadWaitlistOffer(ctx, queries, token)
+	if data.State != pages.WaitlistOfferOpen {
+		return c.Redirect(http.StatusSeeOther, "/waitlist/claim/"+token)
+	}
+	passed, err := queries.CloseWaitlistOffer(ctx, db.CloseWaitlistOfferParams{Status: "passed", ID: offer.ID})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to pass on slot")
+	}
+	if passed > 0 {
+		h.offerFreedSlots([]time.Time{offer.SlotStart})
+	}
+	return c.Redirect(http.StatusSeeOther, "/waitlist/claim/"+token)
+}
+
+// loadWaitlistOffer resolves a claim token to its offer and entry,
+// reporting why the link can't be used when it can't.
+func (h *Handler) loadWaitlistOffer(ctx context.Context, queries *db.Queries, token string) (pages.WaitlistClaimData, db.WaitlistOffer, db.WaitlistEntry) {
+	data := pages.WaitlistClaimData{Token: token, State: pages.WaitlistOfferInvalid}
+
+	id, err := h.links.Verify(waitlistLinkPurpose, token, time.Now())
+	if errors.Is(err, linksign.ErrExpired) {
+		data.State = pages.WaitlistOfferExpired
+		return data, db.WaitlistOffer{}, db.WaitlistEntry{}
+	}
+	if err != nil {
+		return data, db.WaitlistOffer{}, db.WaitlistEntry{}
+	}
+	offer, err := queries.GetWaitlistOfferByID(ctx, id)
+	if err != nil {
+		return data, db.WaitlistOffer{}, db.WaitlistEntry{}
+	}
+	entry, err := queries.GetWaitlistEntryByID(ctx, offer.EntryID)
+	if err != nil {
+		return data, db.WaitlistOffer{}, db.WaitlistEntry{}
+	}
+
+	slotLabel, slotWindow := resolveSlotDetails(offer.SlotStart, offer.SlotEnd)
+	data.FirstName = customerFirstName(entry.CustomerName)
+	data.Appointment = fmt.Sprintf("%s, %s (%s)", slotLabel, offer.SlotStart.In(bookingLocation).Format("Monday, January 2"), slotWindow)
+	data.HeldUntil = offer.ExpiresAt.In(bookingLocation).Format("3:04 PM on Monday, January 2")
+	data.Deposit = h.depositPolicy()
+
+	switch {
+	case offer.Status == "claimed":
+		data.State = pages.WaitlistOfferClaimed
+	case offer.Status == "passed":
+		data.State = pages.WaitlistOfferPassed
+	case offer.Status != "open" || !offer.ExpiresAt.After(time.Now()):
+		data.State = pages.WaitlistOfferExpired
+	default:
+		data.State = pages.WaitlistOfferOpen
+	}
+	return data, offer, entry
+}
+
+func waitlistClaimRedirect(c echo.Context, token string, errMsg string) error {
+	return c.Redirect(http.StatusSeeOther, "/waitlist/claim/"+token+"?error="+url.QueryEscape(errMsg))
+}
+
+// AdminWaitlist lists the customers waiting for a slot and the slots
+// currently held for them.
+func (h *Handler) AdminWaitlist(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	now := time.Now()
+	entries, err := queries.ListWaitlistEntries(ctx, now.UTC())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch waitlist")
+	}
+	offers, err := queries.ListOpenWaitlistOffers(ctx, now.UTC())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch waitlist")
+	}
+
+	data := pages.AdminWaitlistData{ErrorMessage: c.QueryParam("error")}
+	for _, entry := range entries {
+		var slotLabel string
+		if slot, ok := slotLookup[entry.SlotID.String]; ok {
+			slotLabel = slot.Label
+		}
+		item := pages.WaitlistEntryItem{
+			ID:      entry.ID,
+			Name:    entry.CustomerName,
+			Email:   entry.Email,
+			Phone:   nullableString(entry.Phone),
+			Vehicle: nullableString(entry.VehicleDetails),
+			Service: nullableString(entry.ServiceInterest),
+			Notes:   nullableString(entry.Notes),
+			Wants: waitlistRangeLabel(entry.DateFrom.In(bookingLocation),
+				entry.DateTo.In(bookingLocation).AddDate(0, 0, -1), slotLabel),
+			Offers: entry.Offers,
+		}
+		item.Joined = formatAdminTime(entry.CreatedAt)
+		data.Entries = append(data.Entries, item)
+	}
+	for _, offer := range offers {
+		slotLabel, slotWindow := resolveSlotDetails(offer.SlotStart, offer.SlotEnd)
+		data.Offers = append(data.Offers, pages.WaitlistOfferItem{
+			Name:      offer.CustomerName,
+			Email:     offer.Email,
+			Slot:      fmt.Sprintf("%s, %s (%s)", slotLabel, offer.SlotStart.In(bookingLocation).Format("Mon, Jan 2"), slotWindow),
+			HeldUntil: formatAdminTime(sql.NullTime{Time: offer.ExpiresAt, Valid: true}),
+		})
+	}
+
+	return pages.AdminWaitlist(data).Render(ctx, c.Response().Writer)
+}
+
+// RemoveWaitlistEntry takes a customer off the waitlist. An offer already
+// sent to them still stands until it expires.
+func (h *Handler) RemoveWaitlistEntry(c echo.Context) error {
+	ctx := c.Request().Context()
+	queries := db.New(h.db)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid waitlist entry ID")
+	}
+	removed, err := queries.RemoveWaitlistEntry(ctx, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to remove waitlist entry")
+	}
+	if removed == 0 {
+		return c.Redirect(http.StatusSeeOther, "/admin/bookings/waitlist?error="+url.QueryEscape("That customer is no longer waiting"))
+	}
+	return c.Redirect(http.StatusSeeOther, "/admin/bookings/waitlist")
+}
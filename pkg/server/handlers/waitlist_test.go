@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"detailingpass/pkg/auth"
+	"detailingpass/pkg/db"
+	"detailingpass/pkg/linksign"
+	"detailingpass/pkg/mailer"
+
+	"github.com/labstack/echo/v4"
+)
+
+// bouncingMailer fails to send to one address.
+type bouncingMailer struct {
+	recordingMailer
+	bounce string
+}
+
+func (m *bouncingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if msg.To[0] == m.bounce {
+		return errors.New("550 mailbox unavailable")
+	}
+	return m.recordingMailer.Send(ctx, msg)
+}
+
+// blockingMailer holds every send until release is closed.
+type blockingMailer struct {
+	recordingMailer
+	release chan struct{}
+}
+
+func (m *blockingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	<-m.release
+	return m.recordingMailer.Send(ctx, msg)
+}
+
+// waitlistFixture has a technician who only does interiors, and a morning
+// slot two days out for customers to wait on.
+func waitlistFixture(t *testing.T) (*sql.DB, time.Time) {
+	t.Helper()
+	conn := newTestDB(t)
+	return conn, addInteriorTech(t, conn)
+}
+
+// addInteriorTech sets up waitlistFixture on conn and returns its slot.
+func addInteriorTech(t *testing.T, conn *sql.DB) time.Time {
+	t.Helper()
+	queries := db.New(conn)
+	ctx := context.Background()
+
+	for _, p := range []db.CreatePackageParams{
+		{Slug: "interior", Name: "Interior Detail", RequiredSkills: sql.NullString{String: "interior", Valid: true}},
+		{Slug: "ceramic", Name: "Ceramic Coating", RequiredSkills: sql.NullString{String: "ceramic", Valid: true}},
+	} {
+		p.IsActive = sql.NullBool{Bool: true, Valid: true}
+		if _, err := queries.CreatePackage(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tech, err := queries.CreateStaff(ctx, db.CreateStaffParams{
+		Name:     "Alex",
+		Role:     "technician",
+		Skills:   sql.NullString{String: "interior", Valid: true},
+		IsActive: sql.NullBool{Bool: true, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for day := 0; day < 7; day++ {
+		err := queries.CreateStaffHours(ctx, db.CreateStaffHoursParams{StaffID: tech.ID, Weekday: int64(day), StartMinute: 7 * 60, EndMinute: 20 * 60})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	day := time.Now().In(bookingLocation).AddDate(0, 0, 2)
+	return time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, bookingLocation)
+}
+
+func addWaitlistEntry(t *testing.T, queries *db.Queries, email, service string, slot time.Time) {
+	t.Helper()
+	from, _ := slotDay(slot)
+	_, err := queries.CreateWaitlistEntry(context.Background(), db.CreateWaitlistEntryParams{
+		CustomerName:    "Customer " + email,
+		Email:           email,
+		ServiceInterest: sql.NullString{String: service, Valid: service != ""},
+		DateFrom:        from.UTC(),
+		DateTo:          from.AddDate(0, 0, 1).UTC(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOfferFreedSlot(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries [][2]string // email, service, in the order they joined
+		bounce  string
+		want    string // who gets the offer
+	}{
+		{name: "first in line", entries: [][2]string{{"first@example.com", "interior"}, {"second@example.com", ""}}, want: "first@example.com"},
+		{name: "nobody free to do the work", entries: [][2]string{{"ceramic@example.com", "ceramic"}, {"interior@example.com", "interior"}}, want: "interior@example.com"},
+		{name: "email bounces", entries: [][2]string{{"gone@example.com", ""}, {"next@example.com", ""}}, bounce: "gone@example.com", want: "next@example.com"},
+		{name: "nobody can take it", entries: [][2]string{{"ceramic@example.com", "ceramic"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, slot := waitlistFixture(t)
+			queries := db.New(conn)
+			for _, entry := range tt.entries {
+				addWaitlistEntry(t, queries, entry[0], entry[1], slot)
+			}
+			mail := &bouncingMailer{bounce: tt.bounce}
+			h := &Handler{db: conn, mailer: mail, links: linksign.New([]byte("test")), siteURL: "https://example.com"}
+
+			if err := h.offerFreedSlot(context.Background(), queries, slot, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+
+			offers, err := queries.ListOpenWaitlistOffers(context.Background(), time.Now().UTC())
+			if err != nil {
+				t.Fatal(err)
+			}
+			sent := mail.messages()
+			if tt.want == "" {
+				if len(offers) != 0 || len(sent) != 0 {
+					t.Fatalf("offered to %+v, sent %d emails; want nobody", offers, len(sent))
+				}
+				return
+			}
+			if len(offers) != 1 || offers[0].Email != tt.want {
+				t.Fatalf("open offers %+v, want one to %s", offers, tt.want)
+			}
+			if len(sent) != 1 || sent[0].To[0] != tt.want {
+				t.Fatalf("sent %+v, want one email to %s", sent, tt.want)
+			}
+			if !offers[0].SlotStart.Equal(slot) {
+				t.Errorf("offer for %s, want %s", offers[0].SlotStart, slot)
+			}
+
+			// A second call doesn't offer the held slot again
+			if err := h.offerFreedSlot(context.Background(), queries, slot, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			if n := len(mail.messages()); n != 1 {
+				t.Errorf("held slot offered again: %d emails", n)
+			}
+		})
+	}
+}
+
+func TestOfferFreedSlotsDoesntWaitOnEmail(t *testing.T) {
+	conn, slot := waitlistFixture(t)
+	queries := db.New(conn)
+	addWaitlistEntry(t, queries, "dana@example.com", "", slot)
+	mail := &blockingMailer{release: make(chan struct{})}
+	h := &Handler{db: conn, mailer: mail, links: linksign.New([]byte("test")), siteURL: "https://example.com"}
+
+	done := make(chan struct{})
+	go func() {
+		h.offerFreedSlots([]time.Time{slot})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("offerFreedSlots waited for the mail server")
+	}
+
+	close(mail.release)
+	h.background.Wait()
+	if sent := mail.messages(); len(sent) != 1 || sent[0].To[0] != "dana@example.com" {
+		t.Errorf("sent %+v, want the offer to dana@example.com", sent)
+	}
+}
+
+func TestAdvanceWaitlist(t *testing.T) {
+	conn, slot := waitlistFixture(t)
+	queries := db.New(conn)
+	ctx := context.Background()
+	addWaitlistEntry(t, queries, "first@example.com", "", slot)
+	addWaitlistEntry(t, queries, "second@example.com", "", slot)
+	mail := &recordingMailer{}
+	h := &Handler{db: conn, mailer: mail, links: linksign.New([]byte("test")), siteURL: "https://example.com"}
+
+	// The first customer's offer ran out without an answer
+	if err := h.offerFreedSlot(ctx, queries, slot, time.Now().Add(-3*waitlistOfferTTL)); err != nil {
+		t.Fatal(err)
+	}
+	h.runJobs(ctx)
+
+	sent := mail.messages()
+	if len(sent) != 2 || sent[1].To[0] != "second@example.com" {
+		t.Fatalf("sent %+v, want the lapsed offer passed to second@example.com", sent)
+	}
+	offers, err := queries.ListOpenWaitlistOffers(ctx, time.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offers) != 1 || offers[0].Email != "second@example.com" {
+		t.Errorf("open offers %+v, want one to second@example.com", offers)
+	}
+}
+
+func TestJoinWaitlistHorizon(t *testing.T) {
+	conn := newTestDB(t)
+	queries := db.New(conn)
+	ctx := context.Background()
+
+	plan, err := queries.CreateMembershipPlan(ctx, db.CreateMembershipPlanParams{
+		Name: "Monthly", ServicesPerPeriod: 1, PeriodMonths: 1, Price: 9900, IsActive: sql.NullBool{Bool: true, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := queries.CreateMembership(ctx, db.CreateMembershipParams{
+		PlanID:       plan.ID,
+		CustomerName: "Morgan Lee",
+		Email:        "morgan@example.com",
+		StartedAt:    time.Now().AddDate(0, -1, 0).UTC(),
+		PaidThrough:  time.Now().AddDate(0, 1, 0).UTC(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = queries.LinkMembershipUser(ctx, db.LinkMembershipUserParams{ClerkUserID: sql.NullString{String: "user_member", Valid: true}, ID: member.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &Handler{db: conn, links: linksign.New([]byte("test"))}
+	e := echo.New()
+	today := time.Now().In(bookingLocation)
+	tests := []struct {
+		name   string
+		user   string
+		daysIn int
+		want   int
+	}{
+		{name: "inside everyone's window", daysIn: defaultBookingHorizon, want: http.StatusCreated},
+		{name: "past everyone's window", daysIn: defaultBookingHorizon + 1, want: http.StatusBadRequest},
+		{name: "member past everyone's window", user: "user_member", daysIn: defaultBookingHorizon + 1, want: http.StatusCreated},
+		{name: "member at the end of theirs", user: "user_member", daysIn: maxBookingHorizon, want: http.StatusCreated},
+		{name: "member past theirs", user: "user_member", daysIn: maxBookingHorizon + 1, want: http.StatusBadRequest},
+		{name: "signed in without a membership", user: "user_other", daysIn: defaultBookingHorizon + 1, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := today.AddDate(0, 0, tt.daysIn).Format("2006-01-02")
+			body := `{"name": "Pat Kim", "email": "pat@example.com", "date_from": "` + day + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/waitlist", bytes.NewBufferString(body))
+			if tt.user != "" {
+				req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, tt.user))
+			}
+			rec := httptest.NewRecorder()
+			if err := h.JoinWaitlist(e.NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.want {
+				t.Errorf("JoinWaitlist for %s = %d, want %d: %s", day, rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -10,32 +10,73 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func SetupRoutes(e *echo.Echo, db *sql.DB) {
+// SetupRoutes registers every route on e. It returns the handlers so the
+// caller can run their scheduled jobs.
+func SetupRoutes(e *echo.Echo, db *sql.DB) (*handlers.Handler, error) {
 	// Initialize Clerk SDK
 	clerkSecretKey := os.Getenv("CLERK_SECRET_KEY")
 	if clerkSecretKey != "" {
 		auth.Init(clerkSecretKey)
 	}
 
-	h := handlers.New(db)
+	h, err := handlers.New(db)
+	if err != nil {
+		return nil, err
+	}
 
 	// Health check endpoint for Vercel uptime probes
 	e.GET("/health", func(c echo.Context) error {
 		return c.JSON(200, map[string]string{"status": "ok"})
 	})
 
+	// Built from the route table on each request
+	e.GET("/sitemap.xml", h.Sitemap)
+
+	// Uploaded media (local storage backend)
+	e.GET("/uploads/*", h.ServeUpload)
+
 	// Public pages
 	e.GET("/", h.Home)
+	e.GET("/services", h.Services)
+	e.GET("/services/:slug", h.ServiceDetail)
 	e.GET("/gallery", h.Gallery)
+	e.GET("/gallery/:slug", h.GalleryDetail)
 	e.GET("/about", h.About)
-	e.GET("/booking", h.BookingPage)
+	e.GET("/booking", h.BookingPage, auth.OptionalAuth())
+	e.GET("/contact", h.Contact)
+	e.POST("/contact", h.SubmitContact)
 	e.GET("/privacy", h.Privacy)
 	e.GET("/terms", h.Terms)
+	e.GET("/review/:token", h.ReviewForm)
+	e.POST("/review/:token", h.SubmitReview)
+	e.GET("/quote/:token", h.QuotePage)
+	e.POST("/quote/:token/accept", h.AcceptQuote)
+	e.POST("/quote/:token/decline", h.DeclineQuote)
+	e.GET("/invoice/:token", h.InvoicePage)
+	e.GET("/invoice/:token/pdf", h.InvoicePDF)
+	e.GET("/booking/deposit/:token", h.DepositPage)
+	e.POST("/booking/deposit/:token/pay", h.PayDeposit)
+	e.GET("/waitlist/claim/:token", h.WaitlistClaim)
+	e.POST("/waitlist/claim/:token", h.ClaimWaitlistOffer)
+	e.POST("/waitlist/claim/:token/pass", h.PassWaitlistOffer)
+	e.GET("/gift-certificates", h.GiftCertificates)
+	e.POST("/gift-certificates", h.BuyGiftCertificate)
+	e.GET("/gift-certificates/:token", h.GiftCertificatePage)
+	e.GET("/gift-certificates/:token/pdf", h.GiftCertificatePDF)
+	e.GET("/memberships", h.Memberships)
 
 	// Auth pages
 	e.GET("/sign-in", h.SignIn)
 	e.GET("/sign-up", h.SignUp)
 
+	// Customer portal
+	account := e.Group("/account")
+	account.Use(auth.RequireAuth())
+	account.GET("", h.Account)
+	account.GET("/invoices/:id", h.AccountInvoice)
+	account.GET("/invoices/:id/pdf", h.AccountInvoicePDF)
+	account.GET("/schedule", h.MySchedule)
+
 	// Admin routes (protected with Clerk middleware - requires authorized admin email)
 	admin := e.Group("/admin")
 	admin.Use(auth.RequireAdmin())
@@ -44,16 +85,126 @@ func SetupRoutes(e *echo.Echo, db *sql.DB) {
 	admin.POST("/packages", h.CreatePackage)
 	admin.POST("/packages/:id", h.UpdatePackage)
 	admin.POST("/packages/:id/delete", h.DeletePackage)
+	admin.POST("/packages/:id/faqs", h.CreatePackageFAQ)
+	admin.POST("/packages/:id/faqs/:faqID", h.UpdatePackageFAQ)
+	admin.POST("/packages/:id/faqs/:faqID/delete", h.DeletePackageFAQ)
+	admin.POST("/packages/:id/projects", h.UpdatePackageProjects)
+	admin.GET("/addons", h.AdminAddons)
+	admin.POST("/addons", h.CreateAddon)
+	admin.POST("/addons/:id", h.UpdateAddon)
+	admin.POST("/addons/:id/delete", h.DeleteAddon)
 	admin.GET("/bookings", h.AdminBookings)
+	admin.GET("/bookings/series", h.AdminBookingSeriesList)
+	admin.POST("/bookings/series", h.CreateBookingSeries)
+	admin.GET("/bookings/series/:id", h.AdminBookingSeries)
+	admin.POST("/bookings/series/:id/update", h.UpdateBookingSeries)
+	admin.POST("/bookings/series/:id/cancel", h.CancelBookingSeries)
+	admin.POST("/bookings/series/:id/occurrences/:occurrence/move", h.MoveSeriesOccurrence)
+	admin.POST("/bookings/series/:id/occurrences/:occurrence/skip", h.SkipSeriesOccurrence)
+	admin.GET("/bookings/waitlist", h.AdminWaitlist)
+	admin.POST("/bookings/waitlist/:id/remove", h.RemoveWaitlistEntry)
 	admin.POST("/bookings/:id/status", h.UpdateBookingStatus)
+	admin.POST("/bookings/:id/review-request", h.SendReviewRequest)
+	admin.POST("/bookings/:id/invoice", h.CreateInvoiceFromBooking)
+	admin.POST("/bookings/:id/deposit/paid", h.MarkDepositPaid)
+	admin.POST("/bookings/:id/deposit/refund", h.RefundDeposit)
+	admin.POST("/bookings/:id/assign", h.AssignBooking)
+	admin.GET("/messages", h.AdminMessages)
+	admin.POST("/messages/:id/reply", h.ReplyToContactMessage)
+	admin.POST("/messages/:id/read", h.UpdateContactMessageRead)
+	admin.POST("/messages/:id/booking", h.ConvertContactMessageToBooking)
+	admin.POST("/messages/:id/quote", h.CreateQuoteFromMessage)
+	admin.POST("/messages/:id/delete", h.DeleteContactMessage)
+	admin.GET("/quotes", h.AdminQuotes)
+	admin.POST("/quotes", h.CreateQuote)
+	admin.GET("/quotes/:id", h.AdminQuote)
+	admin.POST("/quotes/:id", h.UpdateQuote)
+	admin.POST("/quotes/:id/items", h.AddQuoteItem)
+	admin.POST("/quotes/:id/items/:itemID/delete", h.DeleteQuoteItem)
+	admin.POST("/quotes/:id/promo", h.ApplyQuotePromo)
+	admin.POST("/quotes/:id/send", h.SendQuote)
+	admin.POST("/quotes/:id/delete", h.DeleteQuote)
+	admin.GET("/invoices", h.AdminInvoices)
+	admin.POST("/invoices", h.CreateInvoice)
+	admin.GET("/invoices/:id", h.AdminInvoice)
+	admin.POST("/invoices/:id", h.UpdateInvoice)
+	admin.GET("/invoices/:id/pdf", h.AdminInvoicePDF)
+	admin.POST("/invoices/:id/items", h.AddInvoiceItem)
+	admin.POST("/invoices/:id/items/:itemID/delete", h.DeleteInvoiceItem)
+	admin.POST("/invoices/:id/send", h.SendInvoice)
+	admin.POST("/invoices/:id/paid", h.MarkInvoicePaid)
+	admin.POST("/invoices/:id/gift-certificate", h.RedeemGiftCertificate)
+	admin.POST("/invoices/:id/void", h.VoidInvoice)
+	admin.POST("/invoices/:id/delete", h.DeleteInvoice)
+	admin.GET("/promo-codes", h.AdminPromoCodes)
+	admin.POST("/promo-codes", h.CreatePromoCode)
+	admin.GET("/promo-codes/:id", h.AdminPromoCode)
+	admin.POST("/promo-codes/:id", h.UpdatePromoCode)
+	admin.POST("/promo-codes/:id/delete", h.DeletePromoCode)
+	admin.GET("/gift-certificates", h.AdminGiftCertificates)
+	admin.POST("/gift-certificates", h.IssueGiftCertificate)
+	admin.GET("/gift-certificates/:id", h.AdminGiftCertificate)
+	admin.GET("/gift-certificates/:id/pdf", h.AdminGiftCertificatePDF)
+	admin.POST("/gift-certificates/:id/adjust", h.AdjustGiftCertificate)
+	admin.POST("/gift-certificates/:id/void", h.VoidGiftCertificate)
+	admin.POST("/gift-certificates/:id/send", h.SendGiftCertificateEmail)
+	admin.GET("/memberships", h.AdminMemberships)
+	admin.POST("/memberships", h.CreateMembership)
+	admin.GET("/memberships/plans", h.AdminMembershipPlans)
+	admin.POST("/memberships/plans", h.CreateMembershipPlan)
+	admin.POST("/memberships/plans/:id", h.UpdateMembershipPlan)
+	admin.POST("/memberships/plans/:id/delete", h.DeleteMembershipPlan)
+	admin.GET("/memberships/:id", h.AdminMembership)
+	admin.POST("/memberships/:id/renew", h.RenewMembership)
+	admin.POST("/memberships/:id/cancel", h.CancelMembership)
+	admin.POST("/memberships/:id/resume", h.ResumeMembership)
+	admin.POST("/memberships/:id/notes", h.UpdateMembershipNotes)
+	admin.GET("/staff", h.AdminStaff)
+	admin.POST("/staff", h.CreateStaff)
+	admin.GET("/staff/:id", h.AdminStaffMember)
+	admin.POST("/staff/:id", h.UpdateStaff)
+	admin.POST("/staff/:id/delete", h.DeleteStaff)
+	admin.POST("/staff/:id/hours", h.UpdateStaffHours)
+	admin.POST("/staff/:id/time-off", h.CreateStaffTimeOff)
+	admin.POST("/staff/:id/time-off/:offID/delete", h.DeleteStaffTimeOff)
+	admin.GET("/tax-rates", h.AdminTaxRates)
+	admin.POST("/tax-rates", h.CreateTaxRate)
+	admin.POST("/tax-rates/:id", h.UpdateTaxRate)
+	admin.POST("/tax-rates/:id/delete", h.DeleteTaxRate)
 	admin.GET("/gallery", h.AdminGallery)
 	admin.POST("/gallery", h.CreateGalleryGroup)
 	admin.POST("/gallery/:id", h.UpdateGalleryGroup)
 	admin.POST("/gallery/:id/delete", h.DeleteGalleryGroup)
+	admin.GET("/gallery/:id/media", h.AdminGalleryMedia)
+	admin.POST("/gallery/:id/media", h.UploadGalleryMedia)
+	admin.POST("/gallery/:id/media/:mediaID", h.UpdateGalleryMedia)
+	admin.POST("/gallery/:id/media/:mediaID/pair", h.PairGalleryMedia)
+	admin.POST("/gallery/:id/media/:mediaID/delete", h.DeleteGalleryMedia)
+	admin.GET("/reviews", h.AdminReviews)
+	admin.GET("/reviews/follow-ups", h.AdminReviewFollowUps)
+	admin.GET("/reviews/import", h.AdminReviewImport)
+	admin.POST("/reviews/import", h.ImportReviews)
+	admin.POST("/reviews/import/feature", h.FeatureImportedReviews)
+	admin.POST("/reviews", h.CreateReview)
+	admin.POST("/reviews/:id", h.UpdateReview)
+	admin.POST("/reviews/:id/status", h.UpdateReviewStatus)
+	admin.POST("/reviews/:id/feature", h.ToggleReviewFeatured)
+	admin.POST("/reviews/:id/delete", h.DeleteReview)
+	admin.POST("/reviews/:id/follow-up", h.ResolveReviewFollowUp)
+
+	// Signed by the payment provider, not a signed-in user
+	e.POST("/api/payments/webhook", h.PaymentWebhook)
 
 	// API routes (with optional auth to capture user ID if logged in)
 	api := e.Group("/api")
 	api.Use(auth.OptionalAuth())
 	api.GET("/bookings/availability", h.BookingAvailability)
 	api.POST("/bookings", h.CreateBookingRequest)
+	api.POST("/bookings/holds", h.PlaceSlotHold)
+	api.POST("/bookings/holds/:token/release", h.ReleaseSlotHold)
+	api.GET("/bookings/stream", h.SlotStream)
+	api.GET("/bookings/service-area", h.CheckServiceArea)
+	api.POST("/waitlist", h.JoinWaitlist)
+
+	return h, nil
 }
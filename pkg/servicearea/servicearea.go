@@ -0,0 +1,341 @@
+// Package servicearea decides whether we travel to a customer, what the
+// trip adds to their bill, and how long the drive between jobs takes. It
+// works from ZIP codes: an offline table of ZIP centroids stands in for
+// geocoding, so addresses never leave the server.
+package servicearea
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reasons an address can't be served. The messages are shown to customers.
+var (
+	ErrOff       = errors.New("We're not taking mobile bookings right now.")
+	ErrBadZIP    = errors.New("Enter a 5-digit ZIP code.")
+	ErrUnknown   = errors.New("We couldn't find that ZIP code. Check it, or get in touch and we'll help.")
+	ErrOutside   = errors.New("Sorry, that address is outside the area we travel to.")
+	errNoCenters = errors.New("no ZIP centroids loaded")
+)
+
+const (
+	// Straight-line miles are stretched by this much to approximate roads
+	roadFactor = 1.3
+	// Average driving speed between jobs, in miles per hour
+	averageSpeed = 30
+	// Travel times are rounded up to this, so buffers read naturally
+	travelRounding = 5 * time.Minute
+)
+
+// Point is a latitude and longitude in degrees.
+type Point struct {
+	Lat, Lng float64
+}
+
+// Area is where we travel to and what it costs. A ZIP is served when it
+// is on the ZIP list, within RadiusMiles of the shop, or inside Polygon;
+// rules left empty don't apply. The radius and polygon need the ZIP's
+// centroid.
+type Area struct {
+	Shop        Point
+	ZIPs        map[string]bool
+	RadiusMiles float64
+	Polygon     []Point
+	Centroids   map[string]Point
+	// Every trip costs BaseFee, plus PerMile for each mile from the shop
+	// past FreeMiles. Both in cents.
+	BaseFee   int64
+	PerMile   int64
+	FreeMiles float64
+	// DefaultTravel is the one-way drive assumed when a ZIP's location
+	// isn't known
+	DefaultTravel time.Duration
+}
+
+// Stop is where a job happens: the shop, or a customer's address. The
+// zero Stop is the shop.
+type Stop struct {
+	Mobile   bool
+	Location Point
+	Located  bool
+	// Travel is the one-way drive from the shop
+	Travel time.Duration
+}
+
+// Trip is what going to a ZIP involves.
+type Trip struct {
+	Stop
+	ZIP   string
+	Miles float64 // from the shop, by road; 0 when the ZIP isn't located
+	Fee   int64
+}
+
+// Enabled reports whether any service area is set up, and so whether
+// mobile bookings are taken at all.
+func (a Area) Enabled() bool {
+	return len(a.ZIPs) > 0 || a.RadiusMiles > 0 || len(a.Polygon) > 0
+}
+
+// NormalizeZIP returns the 5-digit ZIP in raw, which may be a ZIP+4.
+func NormalizeZIP(raw string) (string, bool) {
+	zip := strings.TrimSpace(raw)
+	if len(zip) == 10 && zip[5] == '-' {
+		zip = zip[:5]
+	}
+	if len(zip) != 5 {
+		return "", false
+	}
+	for _, r := range zip {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return zip, true
+}
+
+// Quote returns the trip to zip, or why we don't go there.
+func (a Area) Quote(raw string) (Trip, error) {
+	if !a.Enabled() {
+		return Trip{}, ErrOff
+	}
+	zip, ok := NormalizeZIP(raw)
+	if !ok {
+		return Trip{}, ErrBadZIP
+	}
+	center, located := a.Centroids[zip]
+	listed := a.ZIPs[zip]
+	if !listed && !located {
+		return Trip{}, ErrUnknown
+	}
+
+	trip := Trip{ZIP: zip, Stop: Stop{Mobile: true, Location: center, Located: located, Travel: a.DefaultTravel}}
+	if located && a.Shop != (Point{}) {
+		trip.Miles = Miles(a.Shop, center) * roadFactor
+		trip.Travel = driveTime(trip.Miles)
+	}
+	served := listed ||
+		(located && a.RadiusMiles > 0 && a.Shop != (Point{}) && Miles(a.Shop, center) <= a.RadiusMiles) ||
+		(located && len(a.Polygon) > 2 && Contains(a.Polygon, center))
+	if !served {
+		return Trip{}, ErrOutside
+	}
+
+	trip.Fee = a.BaseFee
+	if extra := trip.Miles - a.FreeMiles; extra > 0 && a.PerMile > 0 {
+		trip.Fee += int64(math.Ceil(extra)) * a.PerMile
+	}
+	return trip, nil
+}
+
+// Between is the drive from one job to the next. Shop jobs are zero
+// apart; a job at a customer's is its own travel time from the shop; two
+// customers are the drive between them, when both are located.
+func (a Area) Between(from, to Stop) time.Duration {
+	switch {
+	case !from.Mobile && !to.Mobile:
+		return 0
+	case !from.Mobile:
+		return to.Travel
+	case !to.Mobile:
+		return from.Travel
+	case from.Located && to.Located:
+		return driveTime(Miles(from.Location, to.Location) * roadFactor)
+	default:
+		return a.DefaultTravel
+	}
+}
+
+func driveTime(miles float64) time.Duration {
+	d := time.Duration(miles / averageSpeed * float64(time.Hour))
+	if rem := d % travelRounding; rem != 0 {
+		d += travelRounding - rem
+	}
+	return d
+}
+
+// Miles is the great-circle distance between two points.
+func Miles(a, b Point) float64 {
+	const earthRadius = 3958.8
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Contains reports whether p is inside polygon, whose last point joins
+// back to its first. Service areas are small enough to treat degrees as
+// flat.
+func Contains(polygon []Point, p Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// LoadCentroids reads a CSV of zip,lat,lng rows, such as one cut from the
+// Census Bureau's ZCTA gazetteer. A header row and extra columns are
+// ignored.
+func LoadCentroids(r io.Reader) (map[string]Point, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	centroids := make(map[string]Point)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("line %d: want zip,lat,lng", line)
+		}
+		zip, ok := NormalizeZIP(record[0])
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		lng, lngErr := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if !ok || latErr != nil || lngErr != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: want zip,lat,lng", line)
+		}
+		centroids[zip] = Point{Lat: lat, Lng: lng}
+	}
+	if len(centroids) == 0 {
+		return nil, errNoCenters
+	}
+	return centroids, nil
+}
+
+// ParsePoint reads "lat,lng".
+func ParsePoint(raw string) (Point, error) {
+	lat, lng, ok := strings.Cut(raw, ",")
+	if !ok {
+		return Point{}, fmt.Errorf("%q is not lat,lng", raw)
+	}
+	p := Point{}
+	var err error
+	if p.Lat, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil {
+		return Point{}, fmt.Errorf("%q is not lat,lng", raw)
+	}
+	if p.Lng, err = strconv.ParseFloat(strings.TrimSpace(lng), 64); err != nil {
+		return Point{}, fmt.Errorf("%q is not lat,lng", raw)
+	}
+	return p, nil
+}
+
+// FromEnv builds the area from:
+//
+//	SERVICE_AREA_ZIPS          comma-separated ZIPs we travel to
+//	SERVICE_AREA_RADIUS_MILES  straight-line miles from SHOP_LOCATION
+//	SERVICE_AREA_POLYGON       "lat,lng;lat,lng;..." boundary
+//	SHOP_LOCATION              "lat,lng" where trips start
+//	ZIP_CENTROIDS_FILE         CSV of zip,lat,lng for locating ZIPs
+//	TRAVEL_FEE                 dollars added to every mobile job
+//	TRAVEL_FEE_PER_MILE        dollars per mile past TRAVEL_FREE_MILES
+//	TRAVEL_FREE_MILES          miles covered by TRAVEL_FEE alone
+//	MOBILE_TRAVEL_MINUTES      one-way drive assumed for unlocated ZIPs
+//
+// Mobile bookings are off when none of the first three are set.
+// Misconfigured settings are logged and left out.
+func FromEnv() Area {
+	area := Area{DefaultTravel: 30 * time.Minute}
+
+	if raw := strings.TrimSpace(os.Getenv("SERVICE_AREA_ZIPS")); raw != "" {
+		area.ZIPs = make(map[string]bool)
+		for _, part := range strings.Split(raw, ",") {
+			if zip, ok := NormalizeZIP(part); ok {
+				area.ZIPs[zip] = true
+			} else if strings.TrimSpace(part) != "" {
+				log.Printf("⚠️  SERVICE_AREA_ZIPS: %q is not a ZIP code; skipping it", part)
+			}
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("SHOP_LOCATION")); raw != "" {
+		if p, err := ParsePoint(raw); err == nil {
+			area.Shop = p
+		} else {
+			log.Printf("⚠️  SHOP_LOCATION: %v", err)
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("SERVICE_AREA_RADIUS_MILES")); raw != "" {
+		if miles, err := strconv.ParseFloat(raw, 64); err == nil && miles > 0 && area.Shop != (Point{}) {
+			area.RadiusMiles = miles
+		} else {
+			log.Printf("⚠️  SERVICE_AREA_RADIUS_MILES %q needs a positive number and SHOP_LOCATION; ignoring it", raw)
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("SERVICE_AREA_POLYGON")); raw != "" {
+		var polygon []Point
+		for _, part := range strings.Split(raw, ";") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			p, err := ParsePoint(part)
+			if err != nil {
+				log.Printf("⚠️  SERVICE_AREA_POLYGON: %v; ignoring the polygon", err)
+				polygon = nil
+				break
+			}
+			polygon = append(polygon, p)
+		}
+		if len(polygon) > 2 {
+			area.Polygon = polygon
+		}
+	}
+	if path := strings.TrimSpace(os.Getenv("ZIP_CENTROIDS_FILE")); path != "" {
+		if f, err := os.Open(path); err != nil {
+			log.Printf("⚠️  ZIP_CENTROIDS_FILE: %v", err)
+		} else {
+			if area.Centroids, err = LoadCentroids(f); err != nil {
+				log.Printf("⚠️  ZIP_CENTROIDS_FILE %s: %v", path, err)
+			}
+			f.Close()
+		}
+	}
+	if (area.RadiusMiles > 0 || len(area.Polygon) > 0) && len(area.Centroids) == 0 {
+		log.Println("⚠️  A service area radius or polygon needs ZIP_CENTROIDS_FILE to locate ZIPs; only SERVICE_AREA_ZIPS will be served")
+	}
+
+	area.BaseFee = envCents("TRAVEL_FEE")
+	area.PerMile = envCents("TRAVEL_FEE_PER_MILE")
+	if raw := strings.TrimSpace(os.Getenv("TRAVEL_FREE_MILES")); raw != "" {
+		if miles, err := strconv.ParseFloat(raw, 64); err == nil && miles >= 0 {
+			area.FreeMiles = miles
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOBILE_TRAVEL_MINUTES")); raw != "" {
+		if minutes, err := strconv.Atoi(raw); err == nil && minutes >= 0 {
+			area.DefaultTravel = time.Duration(minutes) * time.Minute
+		}
+	}
+	return area
+}
+
+func envCents(name string) int64 {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return 0
+	}
+	dollars, err := strconv.ParseFloat(strings.TrimPrefix(raw, "$"), 64)
+	if err != nil || dollars < 0 {
+		log.Printf("⚠️  %s %q is not a dollar amount; ignoring it", name, raw)
+		return 0
+	}
+	return int64(math.Round(dollars * 100))
+}
@@ -0,0 +1,209 @@
+package servicearea
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// wausau is served within 12 miles of the shop, inside a box around
+// Stevens Point, and at two listed ZIPs, one of which isn't located.
+func wausau() Area {
+	return Area{
+		Shop:        Point{Lat: 44.9591, Lng: -89.6301},
+		ZIPs:        map[string]bool{"53703": true, "54499": true},
+		RadiusMiles: 12,
+		Polygon: []Point{
+			{Lat: 44.4, Lng: -89.7}, {Lat: 44.6, Lng: -89.7},
+			{Lat: 44.6, Lng: -89.4}, {Lat: 44.4, Lng: -89.4},
+		},
+		Centroids: map[string]Point{
+			"54401": {Lat: 44.96, Lng: -89.70}, // 3.4 miles out
+			"54476": {Lat: 44.89, Lng: -89.52}, // 7.2
+			"54455": {Lat: 44.80, Lng: -89.60}, // 11.1
+			"54481": {Lat: 44.52, Lng: -89.57}, // 30.5, inside the polygon
+			"53703": {Lat: 43.08, Lng: -89.38}, // 130.4
+			"54300": {Lat: 44.00, Lng: -88.00}, // nowhere we go
+		},
+		BaseFee:       2500,
+		PerMile:       150,
+		FreeMiles:     10,
+		DefaultTravel: 30 * time.Minute,
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name    string
+		area    Area
+		zip     string
+		wantZIP string
+		fee     int64
+		travel  time.Duration
+		located bool
+		err     error
+	}{
+		{name: "inside the radius", area: wausau(), zip: "54401", wantZIP: "54401", fee: 2500, travel: 10 * time.Minute, located: true},
+		{name: "ZIP+4", area: wausau(), zip: " 54401-1234 ", wantZIP: "54401", fee: 2500, travel: 10 * time.Minute, located: true},
+		{name: "just inside the free miles", area: wausau(), zip: "54476", wantZIP: "54476", fee: 2500, travel: 20 * time.Minute, located: true},
+		{name: "part miles past the free ones round up", area: wausau(), zip: "54455", wantZIP: "54455", fee: 2500 + 5*150, travel: 30 * time.Minute, located: true},
+		{name: "inside the polygon", area: wausau(), zip: "54481", wantZIP: "54481", fee: 2500 + 30*150, travel: 80 * time.Minute, located: true},
+		{name: "listed far away", area: wausau(), zip: "53703", wantZIP: "53703", fee: 2500 + 160*150, travel: 340 * time.Minute, located: true},
+		{name: "listed but not located", area: wausau(), zip: "54499", wantZIP: "54499", fee: 2500, travel: 30 * time.Minute},
+		{name: "located but outside", area: wausau(), zip: "54300", err: ErrOutside},
+		{name: "unknown", area: wausau(), zip: "99999", err: ErrUnknown},
+		{name: "too short", area: wausau(), zip: "5440", err: ErrBadZIP},
+		{name: "not digits", area: wausau(), zip: "5440a", err: ErrBadZIP},
+		{name: "empty", area: wausau(), zip: "", err: ErrBadZIP},
+		{name: "no area", area: Area{}, zip: "54401", err: ErrOff},
+		{name: "no fee", area: Area{ZIPs: map[string]bool{"54401": true}, DefaultTravel: 30 * time.Minute}, zip: "54401", wantZIP: "54401", travel: 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip, err := tt.area.Quote(tt.zip)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Quote(%q) error = %v, want %v", tt.zip, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if trip.ZIP != tt.wantZIP || trip.Fee != tt.fee || trip.Travel != tt.travel || trip.Located != tt.located || !trip.Mobile {
+				t.Errorf("Quote(%q) = %s fee %d travel %v located %v mobile %v, want %s fee %d travel %v located %v mobile true",
+					tt.zip, trip.ZIP, trip.Fee, trip.Travel, trip.Located, trip.Mobile, tt.wantZIP, tt.fee, tt.travel, tt.located)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	area := wausau()
+	quote := func(zip string) Stop {
+		t.Helper()
+		trip, err := area.Quote(zip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return trip.Stop
+	}
+	shop := Stop{}
+	near, far, unlocated := quote("54401"), quote("54455"), quote("54499")
+
+	tests := []struct {
+		name     string
+		from, to Stop
+		want     time.Duration
+	}{
+		{name: "shop to shop", from: shop, to: shop, want: 0},
+		{name: "shop to a customer", from: shop, to: far, want: 30 * time.Minute},
+		{name: "a customer to the shop", from: near, to: shop, want: 10 * time.Minute},
+		{name: "customer to customer", from: near, to: far, want: 35 * time.Minute},
+		{name: "customer to customer, back again", from: far, to: near, want: 35 * time.Minute},
+		{name: "to a customer not located", from: near, to: unlocated, want: 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := area.Between(tt.from, tt.to); got != tt.want {
+				t.Errorf("Between() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeZIP(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+		ok   bool
+	}{
+		{raw: "54401", want: "54401", ok: true},
+		{raw: " 54401 ", want: "54401", ok: true},
+		{raw: "54401-1234", want: "54401", ok: true},
+		{raw: "544011234"},
+		{raw: "54401 1234"},
+		{raw: "5440"},
+		{raw: "ABCDE"},
+		{raw: ""},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeZIP(tt.raw)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizeZIP(%q) = %q, %v, want %q, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	box := wausau().Polygon
+	tests := []struct {
+		name string
+		p    Point
+		want bool
+	}{
+		{name: "middle", p: Point{Lat: 44.5, Lng: -89.5}, want: true},
+		{name: "north", p: Point{Lat: 44.7, Lng: -89.5}},
+		{name: "east", p: Point{Lat: 44.5, Lng: -89.3}},
+		{name: "south west", p: Point{Lat: 44.3, Lng: -89.8}},
+	}
+	for _, tt := range tests {
+		if got := Contains(box, tt.p); got != tt.want {
+			t.Errorf("%s: Contains(%v) = %v, want %v", tt.name, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCentroids(t *testing.T) {
+	got, err := LoadCentroids(strings.NewReader("GEOID,INTPTLAT,INTPTLONG\n54401,44.96,-89.70\n54476, 44.89, -89.52,extra\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["54401"] != (Point{Lat: 44.96, Lng: -89.70}) || got["54476"] != (Point{Lat: 44.89, Lng: -89.52}) {
+		t.Errorf("LoadCentroids() = %v", got)
+	}
+
+	for _, bad := range []string{
+		"54401,44.96,-89.70\n54476,north,-89.52\n",
+		"54401,44.96\n",
+		"zip,lat,lng\n",
+	} {
+		if _, err := LoadCentroids(strings.NewReader(bad)); err == nil {
+			t.Errorf("LoadCentroids(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	centroids := filepath.Join(t.TempDir(), "zips.csv")
+	if err := os.WriteFile(centroids, []byte("54401,44.96,-89.70\n54455,44.80,-89.60\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SERVICE_AREA_ZIPS", "54499, nope,")
+	t.Setenv("SERVICE_AREA_RADIUS_MILES", "12")
+	t.Setenv("SERVICE_AREA_POLYGON", "")
+	t.Setenv("SHOP_LOCATION", "44.9591,-89.6301")
+	t.Setenv("ZIP_CENTROIDS_FILE", centroids)
+	t.Setenv("TRAVEL_FEE", "$25")
+	t.Setenv("TRAVEL_FEE_PER_MILE", "1.50")
+	t.Setenv("TRAVEL_FREE_MILES", "10")
+	t.Setenv("MOBILE_TRAVEL_MINUTES", "45")
+
+	area := FromEnv()
+	if !area.Enabled() || len(area.ZIPs) != 1 || !area.ZIPs["54499"] || area.RadiusMiles != 12 || len(area.Centroids) != 2 {
+		t.Errorf("FromEnv() area = %+v", area)
+	}
+	if area.BaseFee != 2500 || area.PerMile != 150 || area.FreeMiles != 10 || area.DefaultTravel != 45*time.Minute {
+		t.Errorf("FromEnv() fees = %d + %d/mile past %v, travel %v", area.BaseFee, area.PerMile, area.FreeMiles, area.DefaultTravel)
+	}
+	if trip, err := area.Quote("54455"); err != nil || trip.Fee != 3250 {
+		t.Errorf("Quote(54455) = %+v, %v, want a 3250 fee", trip, err)
+	}
+
+	// A radius means nothing without the shop to measure from
+	t.Setenv("SERVICE_AREA_ZIPS", "")
+	t.Setenv("SHOP_LOCATION", "")
+	if area := FromEnv(); area.Enabled() {
+		t.Errorf("FromEnv() without SHOP_LOCATION = %+v, want mobile bookings off", area)
+	}
+}
@@ -0,0 +1,219 @@
+// Package staffing works out who can take a job: whether a technician is
+// working then, isn't off, has the skills the service needs and isn't on
+// another job. Like membership, it knows nothing about storage; callers
+// load the staff, their hours and their jobs.
+package staffing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reasons a technician can't take a job. Callers word them for the admin
+// assigning it.
+var (
+	ErrRole   = errors.New("staffing: role doesn't take jobs")
+	ErrSkills = errors.New("staffing: missing skills")
+	ErrHours  = errors.New("staffing: not working then")
+	ErrOff    = errors.New("staffing: time off then")
+	ErrBusy   = errors.New("staffing: on another job then")
+)
+
+// Option is a value a staff form offers, with the label it shows.
+type Option struct {
+	ID    string
+	Label string
+}
+
+// Skills a technician can have, in the order forms list them. Someone who
+// only does interiors has just the interior skill.
+var Skills = []Option{
+	{ID: "exterior", Label: "Exterior"},
+	{ID: "interior", Label: "Interior"},
+	{ID: "paint-correction", Label: "Paint correction"},
+	{ID: "ceramic", Label: "Ceramic certified"},
+}
+
+// Roles staff can have. Managers and office staff don't take jobs.
+var Roles = []Option{
+	{ID: "technician", Label: "Technician"},
+	{ID: "lead", Label: "Lead technician"},
+	{ID: "manager", Label: "Manager"},
+	{ID: "office", Label: "Office"},
+}
+
+// DoesJobs reports whether staff in role are assigned jobs.
+func DoesJobs(role string) bool {
+	return role == "technician" || role == "lead"
+}
+
+// ValidRole reports whether role is one of Roles.
+func ValidRole(role string) bool {
+	return label(Roles, role) != ""
+}
+
+// RoleLabel is how role is shown, or role itself when it isn't known.
+func RoleLabel(role string) string {
+	if l := label(Roles, role); l != "" {
+		return l
+	}
+	return role
+}
+
+// SkillLabel is how skill is shown, or skill itself when it isn't known.
+func SkillLabel(skill string) string {
+	if l := label(Skills, skill); l != "" {
+		return l
+	}
+	return skill
+}
+
+func label(options []Option, id string) string {
+	for _, o := range options {
+		if o.ID == id {
+			return o.Label
+		}
+	}
+	return ""
+}
+
+// ParseSkills reads skills stored comma-separated, dropping any that
+// aren't known. They come back in the order of Skills.
+func ParseSkills(raw string) []string {
+	have := make(map[string]bool)
+	for _, s := range strings.Split(raw, ",") {
+		have[strings.TrimSpace(s)] = true
+	}
+	var skills []string
+	for _, s := range Skills {
+		if have[s.ID] {
+			skills = append(skills, s.ID)
+		}
+	}
+	return skills
+}
+
+// JoinSkills is skills as stored, the reverse of ParseSkills.
+func JoinSkills(skills []string) string {
+	return strings.Join(ParseSkills(strings.Join(skills, ",")), ",")
+}
+
+// Shift is the part of a day someone works, in minutes after midnight.
+type Shift struct {
+	Start int
+	End   int
+}
+
+// Label is the shift as the shop writes it, e.g. "8:00 AM – 5:00 PM".
+func (s Shift) Label() string {
+	return clockLabel(s.Start) + " – " + clockLabel(s.End)
+}
+
+func clockLabel(minutes int) string {
+	day := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return day.Add(time.Duration(minutes) * time.Minute).Format("3:04 PM")
+}
+
+// ParseClock reads a time of day as a time input gives it, "08:30", in
+// minutes after midnight. "24:00" is allowed for a shift that runs to
+// midnight.
+func ParseClock(raw string) (int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(strings.TrimSpace(raw), "%d:%d", &h, &m); err != nil {
+		return 0, fmt.Errorf("invalid time %q", raw)
+	}
+	if h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q", raw)
+	}
+	return h*60 + m, nil
+}
+
+// Clock is minutes after midnight as a time input takes it, the reverse
+// of ParseClock.
+func Clock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Span is a stretch of time, such as a job or time off.
+type Span struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Overlaps reports whether s and o share any time. Spans that only touch
+// don't overlap.
+func (s Span) Overlaps(o Span) bool {
+	return s.Start.Before(o.End) && o.Start.Before(s.End)
+}
+
+// Tech is a technician as far as scheduling goes.
+type Tech struct {
+	ID     int64
+	Role   string
+	Skills []string
+	// Hours are the shifts worked each weekday, in the shop's time zone.
+	// Days without one are days off.
+	Hours map[time.Weekday]Shift
+	Off   []Span
+	// Jobs are the jobs already assigned to them.
+	Jobs []Span
+}
+
+// Has reports whether t has every skill in need.
+func (t Tech) Has(need []string) bool {
+	have := make(map[string]bool, len(t.Skills))
+	for _, s := range t.Skills {
+		have[s] = true
+	}
+	for _, s := range need {
+		if !have[s] {
+			return false
+		}
+	}
+	return true
+}
+
+// Working reports whether job falls within t's shift on its day in loc.
+func (t Tech) Working(job Span, loc *time.Location) bool {
+	start := job.Start.In(loc)
+	shift, ok := t.Hours[start.Weekday()]
+	if !ok {
+		return false
+	}
+	// Built from the wall clock so shifts keep their times across DST
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, shift.Start, 0, 0, loc)
+	to := time.Date(start.Year(), start.Month(), start.Day(), 0, shift.End, 0, 0, loc)
+	return !job.Start.Before(from) && !job.End.After(to)
+}
+
+// Check returns why t can't take job, which needs the skills in need, or
+// nil if they can. Hours are read in loc.
+func (t Tech) Check(job Span, need []string, loc *time.Location) error {
+	if !DoesJobs(t.Role) {
+		return ErrRole
+	}
+	if !t.Has(need) {
+		return ErrSkills
+	}
+	if !t.Working(job, loc) {
+		return ErrHours
+	}
+	for _, off := range t.Off {
+		if off.Overlaps(job) {
+			return ErrOff
+		}
+	}
+	for _, other := range t.Jobs {
+		if other.Overlaps(job) {
+			return ErrBusy
+		}
+	}
+	return nil
+}
+
+// Free reports whether t can take job.
+func (t Tech) Free(job Span, need []string, loc *time.Location) bool {
+	return t.Check(job, need, loc) == nil
+}
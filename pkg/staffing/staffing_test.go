@@ -0,0 +1,142 @@
+package staffing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestCheck(t *testing.T) {
+	loc := newYork(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.June, day, hour, minute, 0, 0, loc)
+	}
+	// Monday June 1 and Tuesday June 2 are working days
+	lead := Tech{
+		ID:     1,
+		Role:   "lead",
+		Skills: []string{"exterior", "interior"},
+		Hours: map[time.Weekday]Shift{
+			time.Monday:  {Start: 8 * 60, End: 17 * 60},
+			time.Tuesday: {Start: 8 * 60, End: 17 * 60},
+		},
+		Off:  []Span{{Start: at(2, 12, 0), End: at(2, 17, 0)}},
+		Jobs: []Span{{Start: at(1, 8, 0), End: at(1, 11, 0)}},
+	}
+	manager := lead
+	manager.Role = "manager"
+
+	tests := []struct {
+		name string
+		tech Tech
+		job  Span
+		need []string
+		want error
+	}{
+		{name: "free", tech: lead, job: Span{Start: at(1, 12, 0), End: at(1, 15, 0)}, need: []string{"interior"}},
+		{name: "right after another job", tech: lead, job: Span{Start: at(1, 11, 0), End: at(1, 14, 0)}},
+		{name: "right up to time off", tech: lead, job: Span{Start: at(2, 9, 0), End: at(2, 12, 0)}},
+		{name: "manager", tech: manager, job: Span{Start: at(1, 12, 0), End: at(1, 15, 0)}, want: ErrRole},
+		{name: "missing a skill", tech: lead, job: Span{Start: at(1, 12, 0), End: at(1, 15, 0)}, need: []string{"interior", "ceramic"}, want: ErrSkills},
+		{name: "skills before hours", tech: lead, job: Span{Start: at(7, 12, 0), End: at(7, 15, 0)}, need: []string{"ceramic"}, want: ErrSkills},
+		{name: "day off", tech: lead, job: Span{Start: at(7, 12, 0), End: at(7, 15, 0)}, want: ErrHours},
+		{name: "runs past the shift", tech: lead, job: Span{Start: at(1, 15, 0), End: at(1, 18, 0)}, want: ErrHours},
+		{name: "time off", tech: lead, job: Span{Start: at(2, 11, 0), End: at(2, 14, 0)}, want: ErrOff},
+		{name: "another job", tech: lead, job: Span{Start: at(1, 10, 0), End: at(1, 13, 0)}, want: ErrBusy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tech.Check(tt.job, tt.need, loc)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Check() = %v, want %v", err, tt.want)
+			}
+			if free := tt.tech.Free(tt.job, tt.need, loc); free != (tt.want == nil) {
+				t.Errorf("Free() = %v, want %v", free, tt.want == nil)
+			}
+		})
+	}
+}
+
+func TestWorking(t *testing.T) {
+	loc := newYork(t)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, loc)
+	}
+	// Clocks go forward on Sunday March 8 and back on Sunday November 1
+	tech := Tech{Hours: map[time.Weekday]Shift{
+		time.Sunday:   {Start: 0, End: 24 * 60},
+		time.Monday:   {Start: 8 * 60, End: 17 * 60},
+		time.Saturday: {Start: 18 * 60, End: 24 * 60},
+	}}
+
+	tests := []struct {
+		name string
+		job  Span
+		want bool
+	}{
+		{name: "within the shift", job: Span{Start: at(time.March, 2, 8, 0), End: at(time.March, 2, 17, 0)}, want: true},
+		{name: "starts before the shift", job: Span{Start: at(time.March, 2, 7, 30), End: at(time.March, 2, 10, 0)}},
+		{name: "ends after the shift", job: Span{Start: at(time.March, 2, 15, 0), End: at(time.March, 2, 17, 30)}},
+		{name: "day off", job: Span{Start: at(time.March, 3, 8, 0), End: at(time.March, 3, 11, 0)}},
+		{name: "to midnight", job: Span{Start: at(time.March, 7, 21, 0), End: at(time.March, 8, 0, 0)}, want: true},
+		{name: "past midnight", job: Span{Start: at(time.March, 7, 22, 0), End: at(time.March, 8, 0, 30)}},
+		{name: "morning after clocks go forward", job: Span{Start: at(time.March, 9, 8, 0), End: at(time.March, 9, 17, 0)}, want: true},
+		{name: "end of the short day", job: Span{Start: at(time.March, 8, 21, 0), End: at(time.March, 9, 0, 0)}, want: true},
+		{name: "across the skipped hour", job: Span{Start: at(time.March, 8, 1, 0), End: at(time.March, 8, 4, 0)}, want: true},
+		{name: "end of the long day", job: Span{Start: at(time.November, 1, 21, 0), End: at(time.November, 2, 0, 0)}, want: true},
+		{name: "morning after clocks go back", job: Span{Start: at(time.November, 2, 8, 0), End: at(time.November, 2, 17, 0)}, want: true},
+		{name: "an hour early after clocks go back", job: Span{Start: at(time.November, 2, 7, 0), End: at(time.November, 2, 16, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tech.Working(tt.job, loc); got != tt.want {
+				t.Errorf("Working(%v – %v) = %v, want %v", tt.job.Start, tt.job.End, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "00:00", want: 0},
+		{raw: "08:30", want: 8*60 + 30},
+		{raw: "8:30", want: 8*60 + 30},
+		{raw: " 17:00 ", want: 17 * 60},
+		{raw: "23:59", want: 23*60 + 59},
+		{raw: "24:00", want: 24 * 60},
+		{raw: "24:01", wantErr: true},
+		{raw: "25:00", wantErr: true},
+		{raw: "12:60", wantErr: true},
+		{raw: "-1:00", wantErr: true},
+		{raw: "noon", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseClock(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseClock(%q) = %d, %v, want %d, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+		if back, err := ParseClock(Clock(got)); err == nil && back != got {
+			t.Errorf("ParseClock(Clock(%d)) = %d", got, back)
+		}
+	}
+}
+
+func TestShiftLabel(t *testing.T) {
+	if got := (Shift{Start: 8 * 60, End: 17*60 + 30}).Label(); got != "8:00 AM – 5:30 PM" {
+		t.Errorf("Label() = %q", got)
+	}
+}
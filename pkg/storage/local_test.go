@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCleanKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+		err  bool
+	}{
+		{key: "gallery/12/a.jpg", want: "gallery/12/a.jpg"},
+		{key: "gallery//12/./a.jpg", want: "gallery/12/a.jpg"},
+		{key: "gallery/../a.jpg", want: "a.jpg"},
+		{key: "", err: true},
+		{key: "/etc/passwd", err: true},
+		{key: "../secret", err: true},
+		{key: "gallery/../../secret", err: true},
+		{key: "..", err: true},
+		{key: ".", err: true},
+		{key: `gallery\a.jpg`, err: true},
+	}
+	for _, tt := range tests {
+		got, err := CleanKey(tt.key)
+		if tt.err {
+			if !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("CleanKey(%q) = %q, %v; want ErrInvalidKey", tt.key, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("CleanKey(%q) = %q, %v; want %q", tt.key, got, err, tt.want)
+		}
+	}
+}
+
+func TestLocalPutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	l := NewLocal(t.TempDir(), "/uploads/", []byte("key"))
+
+	if err := l.Put(ctx, "gallery/1/a.jpg", []byte("jpeg bytes"), "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	r, err := l.Get(ctx, "gallery/1/a.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "jpeg bytes" {
+		t.Errorf("Get = %q", data)
+	}
+
+	if err := l.Delete(ctx, "gallery/1/a.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Get(ctx, "gallery/1/a.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	if err := l.Delete(ctx, "gallery/1/a.jpg"); err != nil {
+		t.Errorf("Delete of a missing object: %v", err)
+	}
+	if err := l.Put(ctx, "../escape.jpg", nil, ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Put outside the root: err = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestLocalURL(t *testing.T) {
+	l := NewLocal(t.TempDir(), "/uploads/", []byte("key"))
+	if got, want := l.URL("gallery/1/my photo.jpg"), "/uploads/gallery/1/my%20photo.jpg"; got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	l := NewLocal(t.TempDir(), "/uploads", []byte("key"))
+	key := PrivatePrefix + "gallery/1/a.jpg"
+
+	signed, err := l.SignedURL(key, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(signed, "/uploads/"+key+"?") {
+		t.Errorf("SignedURL = %q", signed)
+	}
+	query := u.Query()
+	now := time.Now()
+
+	tampered := url.Values{"expires": {query.Get("expires") + "0"}, "signature": {query.Get("signature")}}
+	tests := []struct {
+		name  string
+		key   string
+		query url.Values
+		now   time.Time
+		want  bool
+	}{
+		{name: "valid", key: key, query: query, now: now, want: true},
+		{name: "just before expiry", key: key, query: query, now: now.Add(time.Hour - time.Second), want: true},
+		{name: "expired", key: key, query: query, now: now.Add(time.Hour + 2*time.Second), want: false},
+		{name: "other key", key: PrivatePrefix + "gallery/1/b.jpg", query: query, now: now, want: false},
+		{name: "extended expiry", key: key, query: tampered, now: now, want: false},
+		{name: "no signature", key: key, query: url.Values{"expires": {query.Get("expires")}}, now: now, want: false},
+		{name: "no query", key: key, query: url.Values{}, now: now, want: false},
+	}
+	for _, tt := range tests {
+		if got := l.Verify(tt.key, tt.query, tt.now); got != tt.want {
+			t.Errorf("%s: Verify = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	other := NewLocal(t.TempDir(), "/uploads", []byte("other key"))
+	if other.Verify(key, query, now) {
+		t.Error("Verify accepted a URL signed with a different key")
+	}
+}
+
+func TestFromEnvSigningKey(t *testing.T) {
+	tests := []struct {
+		driver, key string
+		signs       bool
+	}{
+		// Without a key the site still runs; only private links fail
+		{driver: "local", key: ""},
+		{driver: "", key: "change_me"},
+		{driver: "local", key: "a-real-secret", signs: true},
+		// S3 presigns with its own credentials
+		{driver: "s3", key: "", signs: true},
+	}
+	for _, tt := range tests {
+		t.Setenv("STORAGE_DRIVER", tt.driver)
+		t.Setenv("STORAGE_SIGNING_KEY", tt.key)
+		t.Setenv("S3_BUCKET", "media")
+		t.Setenv("S3_ACCESS_KEY_ID", "id")
+		t.Setenv("S3_SECRET_ACCESS_KEY", "secret")
+		s, err := FromEnv()
+		if err != nil {
+			t.Errorf("driver %q, key %q: err = %v", tt.driver, tt.key, err)
+			continue
+		}
+		if _, err := s.SignedURL("private/a.jpg", time.Hour); (err == nil) != tt.signs {
+			t.Errorf("driver %q, key %q: SignedURL err = %v", tt.driver, tt.key, err)
+		}
+	}
+}
+
+func TestLocalWithoutSigningKey(t *testing.T) {
+	l := NewLocal(t.TempDir(), "/uploads", nil)
+	if _, err := l.SignedURL("private/a.jpg", time.Hour); !errors.Is(err, ErrNoSigning) {
+		t.Errorf("SignedURL err = %v, want ErrNoSigning", err)
+	}
+	// A signature made with an empty key must not be accepted
+	expires := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {l.sign("private/a.jpg", expires)}}
+	if l.Verify("private/a.jpg", query, time.Now()) {
+		t.Error("Verify accepted a link without a signing key")
+	}
+}
@@ -0,0 +1,14 @@
+// ========================================
+// Before/After Comparison Slider
+// ========================================
+// Each [data-before-after] figure holds a transparent range input; moving it
+// updates --position, which clips the before image and places the divider.
+document.addEventListener('input', (event) => {
+  const input = event.target;
+  if (!(input instanceof HTMLInputElement) || input.type !== 'range') return;
+
+  const slider = input.closest('[data-before-after]');
+  if (slider) {
+    slider.style.setProperty('--position', `${input.value}%`);
+  }
+});
@@ -3,13 +3,20 @@ class BookingApp {
 		this.root = root;
 		this.availabilityEndpoint = root.dataset.availabilityEndpoint || '/api/bookings/availability';
 		this.submitEndpoint = root.dataset.submitEndpoint || '/api/bookings';
+		this.waitlistEndpoint = root.dataset.waitlistEndpoint || '/api/waitlist';
+		this.holdEndpoint = root.dataset.holdEndpoint || '/api/bookings/holds';
+		this.streamEndpoint = root.dataset.streamEndpoint || '/api/bookings/stream';
+		this.serviceAreaEndpoint = root.dataset.serviceAreaEndpoint || '/api/bookings/service-area';
 		this.daysContainer = root.querySelector('[data-calendar-days]');
 		this.rangeLabel = root.querySelector('[data-calendar-range]');
 		this.slotContainer = root.querySelector('[data-slot-list]');
 		this.selectionPill = root.querySelector('[data-selection-pill]');
 		this.form = document.getElementById('booking-form');
 		this.feedback = document.getElementById('booking-feedback');
+		this.waitlistForm = document.getElementById('waitlist-form');
+		this.waitlistFeedback = document.getElementById('waitlist-feedback');
 		this.navButtons = root.querySelectorAll('[data-month-nav]');
+		this.locationPicker = root.querySelector('[data-location-picker]');
 		this.state = {
 			days: [],
 			range: null,
@@ -17,11 +24,28 @@ class BookingApp {
 			selectedSlotId: null,
 			selectedSlotWindow: '',
 			selectedSlotLabel: '',
+			holdToken: null,
+			holdExpiresAt: null,
+			mobile: false,
+			zip: '',
 		};
+		this.holdTimer = null;
+		this.refreshTimer = null;
 
 		this.bindNav();
 		this.bindForm();
+		this.bindWaitlist();
+		this.bindLocation();
+		this.bindService();
 		this.loadAvailability();
+		this.bindStream();
+
+		// Let the slot go as soon as the visitor leaves
+		window.addEventListener('pagehide', () => {
+			if (this.state.holdToken) {
+				navigator.sendBeacon(`${this.holdEndpoint}/${encodeURIComponent(this.state.holdToken)}/release`);
+			}
+		});
 	}
 
 	bindNav() {
@@ -56,8 +80,14 @@ class BookingApp {
 				vehicle: (formData.get('vehicle') || '').trim(),
 				service: (formData.get('service') || '').trim(),
 				notes: (formData.get('notes') || '').trim(),
+				promo_code: (formData.get('promo_code') || '').trim(),
+				use_membership: formData.get('use_membership') === 'true',
+				mobile: this.state.mobile,
+				address: (formData.get('address') || '').trim(),
+				zip: this.state.zip,
 				date: this.state.selectedDate,
 				slot_id: this.state.selectedSlotId,
+				hold_token: this.state.holdToken || '',
 			};
 
 			// Validate required fields
@@ -73,6 +103,17 @@ class BookingApp {
 				return;
 			}
 
+			if (payload.mobile && !payload.zip) {
+				this.showFeedback('Check your ZIP code so we know we can come to you.', true);
+				this.locationPicker?.querySelector('input[name="zip"]')?.focus();
+				return;
+			}
+			if (payload.mobile && !payload.address) {
+				this.showFeedback('Please enter the address you\'d like us to come to.', true);
+				this.form.querySelector('input[name="address"]')?.focus();
+				return;
+			}
+
 			// Basic email format validation
 			const emailRegex = /^[^\s@]+@[^\s@]+\.[^\s@]+$/;
 			if (!emailRegex.test(payload.email)) {
@@ -100,9 +141,18 @@ class BookingApp {
 				});
 				const data = await response.json();
 				if (!response.ok) {
+					if (data.waitlist) {
+						this.suggestWaitlist();
+					}
 					throw new Error(data.error || 'Unable to submit booking right now.');
 				}
 				this.showFeedback(data.message || 'Request received!', false);
+				// The hold became the booking
+				this.forgetHold();
+				if (data.payment_url) {
+					window.location.assign(data.payment_url);
+					return;
+				}
 				this.form.reset();
 				this.clearSelection();
 				this.loadAvailability(this.state.range ? this.state.range.start : undefined);
@@ -114,6 +164,175 @@ class BookingApp {
 		});
 	}
 
+	bindWaitlist() {
+		if (!this.waitlistForm || !this.form) return;
+		this.waitlistForm.addEventListener('submit', async (event) => {
+			event.preventDefault();
+
+			// Contact details come from the booking form
+			const contact = new FormData(this.form);
+			const formData = new FormData(this.waitlistForm);
+			const payload = {
+				name: (contact.get('name') || '').trim(),
+				email: (contact.get('email') || '').trim(),
+				phone: (contact.get('phone') || '').trim(),
+				vehicle: (contact.get('vehicle') || '').trim(),
+				service: (contact.get('service') || '').trim(),
+				notes: (contact.get('notes') || '').trim(),
+				date_from: formData.get('date_from') || '',
+				date_to: formData.get('date_to') || '',
+				slot_id: formData.get('slot_id') || '',
+			};
+
+			if (!payload.name || !payload.email) {
+				this.showFeedback('Enter your name and email in the booking form so we can reach you.', true, this.waitlistFeedback);
+				this.form.querySelector(payload.name ? 'input[name="email"]' : 'input[name="name"]')?.focus();
+				return;
+			}
+
+			const button = this.waitlistForm.querySelector('button[type="submit"]');
+			if (button) button.disabled = true;
+			try {
+				const response = await fetch(this.waitlistEndpoint, {
+					method: 'POST',
+					headers: { 'Content-Type': 'application/json' },
+					body: JSON.stringify(payload),
+				});
+				const data = await response.json();
+				if (!response.ok) {
+					throw new Error(data.error || 'Unable to join the waitlist right now.');
+				}
+				this.showFeedback(data.message || "You're on the waitlist.", false, this.waitlistFeedback);
+				this.waitlistForm.reset();
+			} catch (error) {
+				this.showFeedback(error.message || 'Unable to join the waitlist right now.', true, this.waitlistFeedback);
+			} finally {
+				if (button) button.disabled = false;
+			}
+		});
+	}
+
+	// suggestWaitlist fills the waitlist form with the slot that was just
+	// taken, so the customer can wait on it in one click.
+	suggestWaitlist() {
+		if (!this.waitlistForm || !this.state.selectedDate) return;
+		this.waitlistForm.querySelector('input[name="date_from"]').value = this.state.selectedDate;
+		this.waitlistForm.querySelector('input[name="date_to"]').value = this.state.selectedDate;
+		this.waitlistForm.querySelector('select[name="slot_id"]').value = this.state.selectedSlotId || '';
+		this.waitlistForm.scrollIntoView({ behavior: 'smooth', block: 'center' });
+	}
+
+	// bindLocation lets the visitor ask us to come to them. Once their ZIP
+	// is in our service area the calendar is reloaded for it, since the
+	// drive between jobs rules some times out.
+	bindLocation() {
+		if (!this.locationPicker) return;
+		const zipField = this.locationPicker.querySelector('[data-zip-field]');
+		const zipInput = this.locationPicker.querySelector('input[name="zip"]');
+		const result = this.locationPicker.querySelector('[data-zip-result]');
+		const addressField = this.form ? this.form.querySelector('[data-address-field]') : null;
+
+		const setZip = (zip) => {
+			if (zip === this.state.zip) return;
+			this.state.zip = zip;
+			this.refreshAvailability('We can\'t get there in time for the slot you picked. Please choose another.');
+		};
+
+		const check = async () => {
+			const zip = zipInput ? zipInput.value.trim() : '';
+			if (!zip) return;
+			try {
+				const response = await fetch(`${this.serviceAreaEndpoint}?${new URLSearchParams({ zip })}`);
+				const data = await response.json();
+				if (!response.ok) {
+					throw new Error(data.error || 'We couldn\'t check that ZIP code.');
+				}
+				if (result) {
+					result.textContent = `${data.message} Times we can't reach in time are hidden.`;
+					result.classList.remove('text-rose-300');
+				}
+				setZip(data.zip);
+			} catch (error) {
+				if (result) {
+					result.textContent = error.message;
+					result.classList.add('text-rose-300');
+				}
+				setZip('');
+			}
+		};
+
+		this.locationPicker.querySelectorAll('input[name="service_location"]').forEach((radio) => {
+			radio.addEventListener('change', () => {
+				this.state.mobile = radio.value === 'mobile' && radio.checked;
+				zipField?.classList.toggle('hidden', !this.state.mobile);
+				addressField?.classList.toggle('hidden', !this.state.mobile);
+				if (!this.state.mobile) {
+					setZip('');
+				} else if (zipInput && zipInput.value.trim()) {
+					check();
+				}
+			});
+		});
+		this.locationPicker.querySelector('[data-zip-check]')?.addEventListener('click', check);
+		zipInput?.addEventListener('keydown', (event) => {
+			if (event.key === 'Enter') {
+				event.preventDefault();
+				check();
+			}
+		});
+		zipInput?.addEventListener('change', check);
+	}
+
+	// bindService reloads the calendar when the service changes, since
+	// only technicians with the skills for it can take it.
+	bindService() {
+		const select = this.form ? this.form.querySelector('select[name="service"]') : null;
+		if (!select) return;
+		select.addEventListener('change', () => {
+			this.refreshAvailability('Nobody who does that service is free at the time you picked. Please choose another.');
+		});
+	}
+
+	// bindStream refreshes the calendar whenever a slot on show is booked,
+	// freed or held by someone else, so the page never offers a stale time.
+	bindStream() {
+		if (!window.EventSource) return;
+		const stream = new EventSource(this.streamEndpoint);
+		stream.addEventListener('slot', (message) => {
+			let event;
+			try {
+				event = JSON.parse(message.data);
+			} catch (error) {
+				return;
+			}
+			if (!this.state.days.some((day) => day.date === event.date)) return;
+			this.scheduleRefresh(0);
+			// Holds lapse without an event, so look again when this one would
+			if (event.until) {
+				const wait = new Date(event.until) - Date.now();
+				if (wait > 0) setTimeout(() => this.scheduleRefresh(0), wait + 1000);
+			}
+		});
+		window.addEventListener('pagehide', () => stream.close());
+	}
+
+	// scheduleRefresh batches bursts of events, such as a series being
+	// booked, into one fetch.
+	scheduleRefresh(delay) {
+		clearTimeout(this.refreshTimer);
+		this.refreshTimer = setTimeout(() => this.refreshAvailability(), delay + 250);
+	}
+
+	async refreshAvailability(lostMessage = 'The time you picked has just been taken. Please choose another slot.') {
+		await this.loadAvailability(this.state.range ? this.state.range.start : undefined);
+		if (!this.state.selectedDate || !this.state.selectedSlotId) return;
+		const day = this.state.days.find((d) => d.date === this.state.selectedDate);
+		const slot = day && day.slots ? day.slots.find((s) => s.id === this.state.selectedSlotId) : null;
+		if (slot && slot.available) return;
+		this.clearSelection();
+		this.showFeedback(lostMessage, true);
+	}
+
 	async loadAvailability(startDate) {
 		if (this.rangeLabel) {
 			this.rangeLabel.textContent = 'Refreshing…';
@@ -123,6 +342,16 @@ class BookingApp {
 		if (startDate) {
 			params.set('start', startDate);
 		}
+		if (this.state.holdToken) {
+			params.set('hold', this.state.holdToken);
+		}
+		if (this.state.mobile && this.state.zip) {
+			params.set('zip', this.state.zip);
+		}
+		const service = this.form ? this.form.querySelector('select[name="service"]') : null;
+		if (service && service.value) {
+			params.set('service', service.value);
+		}
 
 		try {
 			const response = await fetch(`${this.availabilityEndpoint}?${params.toString()}`);
@@ -305,7 +534,7 @@ class BookingApp {
 					>
 						<span class="text-sm uppercase tracking-wide text-muted">${slot.label}</span>
 						<span class="text-lg font-heading font-semibold text-brand-fg">${slot.window}</span>
-						${disabled ? '<span class="text-xs text-muted">Reserved</span>' : ''}
+						${disabled ? `<span class="text-xs text-muted">${slot.members_only ? 'Members only' : 'Reserved'}</span>` : ''}
 					</button>
 				`;
 			})
@@ -325,6 +554,7 @@ class BookingApp {
 			this.state.selectedSlotId = null;
 			this.state.selectedSlotLabel = '';
 			this.state.selectedSlotWindow = '';
+			this.releaseHold();
 		}
 		this.state.selectedDate = date;
 		this.renderCalendar();
@@ -332,11 +562,20 @@ class BookingApp {
 		this.updateFormSelections();
 	}
 
-	handleSlotSelect(date, slotId) {
+	async handleSlotSelect(date, slotId) {
 		const day = this.state.days.find((d) => d.date === date);
 		if (!day) return;
 		const slot = day.slots.find((s) => s.id === slotId);
 		if (!slot || !slot.available) return;
+		if (date === this.state.selectedDate && slotId === this.state.selectedSlotId && this.state.holdToken) return;
+
+		try {
+			await this.placeHold(date, slotId);
+		} catch (error) {
+			this.showFeedback(error.message, true);
+			this.loadAvailability(this.state.range ? this.state.range.start : undefined);
+			return;
+		}
 
 		this.state.selectedDate = date;
 		this.state.selectedSlotId = slotId;
@@ -347,6 +586,40 @@ class BookingApp {
 		this.updateFormSelections();
 	}
 
+	// placeHold keeps the slot from other visitors while the form is filled
+	// in, giving up any slot held before.
+	async placeHold(date, slotId) {
+		const response = await fetch(this.holdEndpoint, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ date, slot_id: slotId, release: this.state.holdToken || '' }),
+		});
+		const data = await response.json();
+		this.forgetHold();
+		if (!response.ok) {
+			throw new Error(data.error || 'That time is no longer available.');
+		}
+		this.state.holdToken = data.hold_token;
+		this.state.holdExpiresAt = new Date(data.expires_at);
+		this.holdTimer = setTimeout(() => {
+			this.forgetHold();
+			this.updateFormSelections();
+		}, this.state.holdExpiresAt - Date.now());
+	}
+
+	releaseHold() {
+		if (!this.state.holdToken) return;
+		fetch(`${this.holdEndpoint}/${encodeURIComponent(this.state.holdToken)}/release`, { method: 'POST' }).catch(() => {});
+		this.forgetHold();
+	}
+
+	forgetHold() {
+		clearTimeout(this.holdTimer);
+		this.holdTimer = null;
+		this.state.holdToken = null;
+		this.state.holdExpiresAt = null;
+	}
+
 	updateFormSelections() {
 		if (!this.form) return;
 		const dateInput = this.form.querySelector('input[name="selected_date"]');
@@ -356,7 +629,12 @@ class BookingApp {
 
 		if (this.selectionPill) {
 			if (this.state.selectedDate && this.state.selectedSlotId) {
-				this.selectionPill.textContent = `${this.formatHumanDate(this.state.selectedDate)} • ${this.state.selectedSlotWindow}`;
+				let text = `${this.formatHumanDate(this.state.selectedDate)} • ${this.state.selectedSlotWindow}`;
+				if (this.state.holdExpiresAt) {
+					const until = this.state.holdExpiresAt.toLocaleTimeString(undefined, { hour: 'numeric', minute: '2-digit' });
+					text += ` — held for you until ${until}`;
+				}
+				this.selectionPill.textContent = text;
 				this.selectionPill.classList.remove('hidden');
 			} else if (this.state.selectedDate) {
 				this.selectionPill.textContent = `Great — now choose a slot on ${this.formatHumanDate(this.state.selectedDate)}.`;
@@ -368,6 +646,7 @@ class BookingApp {
 	}
 
 	clearSelection() {
+		this.releaseHold();
 		this.state.selectedDate = null;
 		this.state.selectedSlotId = null;
 		this.state.selectedSlotLabel = '';
@@ -440,18 +719,18 @@ class BookingApp {
 		button.querySelector('span')?.classList.toggle('animate-pulse', isSubmitting);
 	}
 
-	showFeedback(message, isError) {
-		if (!this.feedback) return;
-		this.feedback.textContent = message;
-		this.feedback.classList.remove('hidden');
-		this.feedback.classList.remove('border-rose-400/60', 'bg-rose-500/10', 'text-rose-100', 'border-brand-accent/60', 'bg-brand-accent/10', 'text-brand-fg');
+	showFeedback(message, isError, target = this.feedback) {
+		if (!target) return;
+		target.textContent = message;
+		target.classList.remove('hidden');
+		target.classList.remove('border-rose-400/60', 'bg-rose-500/10', 'text-rose-100', 'border-brand-accent/60', 'bg-brand-accent/10', 'text-brand-fg');
 		if (isError) {
-			this.feedback.classList.add('border-rose-400/60', 'bg-rose-500/10', 'text-rose-100');
+			target.classList.add('border-rose-400/60', 'bg-rose-500/10', 'text-rose-100');
 		} else {
-			this.feedback.classList.add('border-brand-accent/60', 'bg-brand-accent/10', 'text-brand-fg');
+			target.classList.add('border-brand-accent/60', 'bg-brand-accent/10', 'text-brand-fg');
 		}
 		setTimeout(() => {
-			this.feedback.classList.add('hidden');
+			target.classList.add('hidden');
 		}, 6000);
 	}
 }
@@ -147,6 +147,7 @@ document.addEventListener('DOMContentLoaded', () => {
           body: JSON.stringify(data),
         });
 
+        const result = await response.json().catch(() => ({}));
         if (response.ok) {
           // Show success message
           const successMsg = document.createElement('div');
@@ -156,7 +157,9 @@ document.addEventListener('DOMContentLoaded', () => {
           contactForm.reset();
           setTimeout(() => successMsg.remove(), 5000);
         } else {
-          throw new Error('Server error');
+          const err = new Error('Server error');
+          err.detail = result.error;
+          throw err;
         }
       } catch (error) {
         console.error('Error:', error);
@@ -164,6 +167,9 @@ document.addEventListener('DOMContentLoaded', () => {
         const errorMsg = document.createElement('div');
         errorMsg.className = 'bg-red-500/10 border border-red-500/20 text-red-400 px-4 py-3 rounded-lg mb-4';
         errorMsg.innerHTML = '<p class="font-semibold">Oops! Something went wrong.</p><p class="text-sm mt-1">Please try again or call us directly at (555) 123-4567.</p>';
+        if (error.detail) {
+          errorMsg.querySelector('.text-sm').textContent = error.detail;
+        }
         contactForm.insertAdjacentElement('beforebegin', errorMsg);
         setTimeout(() => errorMsg.remove(), 5000);
       } finally {
@@ -29,7 +29,7 @@ func Footer() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<!-- Mobile Sticky Bottom Bar - Optimized for 48px minimum touch targets --><div class=\"mobile-sticky-bar\"><div class=\"btn-group\"><a href=\"/booking\" class=\"btn-primary text-center text-sm min-h-[48px] flex items-center justify-center font-semibold\">Book a Detail</a> <a href=\"tel:+15551234567\" class=\"btn-green text-center text-sm min-h-[48px] flex items-center justify-center gap-2 font-semibold\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 5a2 2 0 012-2h3.28a1 1 0 01.948.684l1.498 4.493a1 1 0 01-.502 1.21l-2.257 1.13a11.042 11.042 0 005.516 5.516l1.13-2.257a1 1 0 011.21-.502l4.493 1.498a1 1 0 01.684.949V19a2 2 0 01-2 2h-1C9.716 21 3 14.284 3 6V5z\"></path></svg> Call/Text</a></div></div><footer class=\"bg-brand-secondary border-t-2 border-border mt-20 mb-20 md:mb-0\"><!-- Call-to-Action Banner --><div class=\"bg-gradient-to-r from-brand-primary/20 to-brand-accent/20 border-b border-brand-accent/30\"><div class=\"container mx-auto px-4 py-8\"><div class=\"flex flex-col md:flex-row items-center justify-between gap-6\"><div><p class=\"text-brand-accent font-script text-xl mb-1\">Get Started Today</p><h3 class=\"text-2xl md:text-3xl font-heading font-bold\">Ready for the Ultimate Detail?</h3></div><div class=\"flex flex-col sm:flex-row gap-3\"><a href=\"/booking\" class=\"btn-primary whitespace-nowrap\">Book Now</a> <a href=\"tel:+15551234567\" class=\"btn-green whitespace-nowrap\"><svg class=\"w-5 h-5 inline-block mr-2\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 5a2 2 0 012-2h3.28a1 1 0 01.948.684l1.498 4.493a1 1 0 01-.502 1.21l-2.257 1.13a11.042 11.042 0 005.516 5.516l1.13-2.257a1 1 0 011.21-.502l4.493 1.498a1 1 0 01.684.949V19a2 2 0 01-2 2h-1C9.716 21 3 14.284 3 6V5z\"></path></svg> Call Now</a></div></div></div></div><div class=\"container mx-auto px-4 py-12\"><div class=\"grid grid-cols-1 md:grid-cols-3 gap-8\"><!-- Brand --><div><h3 class=\"text-xl font-heading font-bold text-brand-accent mb-4\">C Auto Detailing Studio</h3><p class=\"text-muted text-sm mb-4\">Premium automotive detailing services with meticulous care for every vehicle.</p><p class=\"text-brand-accent font-script text-lg\">Excellence in Every Detail</p></div><!-- Quick Links --><div><h4 class=\"font-heading font-bold mb-4 border-l-4 border-brand-accent pl-3\">Quick Links</h4><ul class=\"space-y-2 text-sm\"><li><a href=\"/\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Home</a></li><li><a href=\"/gallery\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Gallery</a></li><li><a href=\"/about\" class=\"text-muted hover:text-brand-accent transition font-semibold\">About Us</a></li><li><a href=\"/booking\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Book Now</a></li></ul></div><!-- Contact --><div><h4 class=\"font-heading font-bold mb-4 border-l-4 border-brand-accent pl-3\">Get in Touch</h4><ul class=\"space-y-3 text-sm\"><li><a href=\"tel:+15551234567\" class=\"flex items-center gap-2 text-muted hover:text-brand-accent transition group font-semibold\"><svg class=\"w-4 h-4 group-hover:scale-110 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 5a2 2 0 012-2h3.28a1 1 0 01.948.684l1.498 4.493a1 1 0 01-.502 1.21l-2.257 1.13a11.042 11.042 0 005.516 5.516l1.13-2.257a1 1 0 011.21-.502l4.493 1.498a1 1 0 01.684.949V19a2 2 0 01-2 2h-1C9.716 21 3 14.284 3 6V5z\"></path></svg> (555) 123-4567</a></li><li><a href=\"mailto:info@cautodetailingstudio.com\" class=\"flex items-center gap-2 text-muted hover:text-brand-accent transition group font-semibold\"><svg class=\"w-4 h-4 group-hover:scale-110 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 8l7.89 5.26a2 2 0 002.22 0L21 8M5 19h14a2 2 0 002-2V7a2 2 0 00-2-2H5a2 2 0 00-2 2v10a2 2 0 002 2z\"></path></svg> info@cautodetailingstudio.com</a></li><li class=\"flex items-center gap-2 text-muted font-semibold\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M17.657 16.657L13.414 20.9a1.998 1.998 0 01-2.827 0l-4.244-4.243a8 8 0 1111.314 0z\"></path> <path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15 11a3 3 0 11-6 0 3 3 0 016 0z\"></path></svg> Your City, State</li><li class=\"flex items-center gap-2 text-muted font-semibold\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v4l3 3m6-3a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Mon-Sat: 8AM-6PM</li></ul><div class=\"flex gap-3 mt-6\"><a href=\"https://facebook.com\" target=\"_blank\" rel=\"noopener noreferrer\" class=\"w-9 h-9 bg-brand-bg hover:bg-brand-accent rounded-full flex items-center justify-center text-brand-fg hover:text-white transition-all hover:scale-110\" aria-label=\"Facebook\"><svg class=\"w-4 h-4\" fill=\"currentColor\" viewBox=\"0 0 24 24\"><path d=\"M24 12.073c0-6.627-5.373-12-12-12s-12 5.373-12 12c0 5.99 4.388 10.954 10.125 11.854v-8.385H7.078v-3.47h3.047V9.43c0-3.007 1.792-4.669 4.533-4.669 1.312 0 2.686.235 2.686.235v2.953H15.83c-1.491 0-1.956.925-1.956 1.874v2.25h3.328l-.532 3.47h-2.796v8.385C19.612 23.027 24 18.062 24 12.073z\"></path></svg></a> <a href=\"https://instagram.com\" target=\"_blank\" rel=\"noopener noreferrer\" class=\"w-9 h-9 bg-brand-bg hover:bg-brand-accent rounded-full flex items-center justify-center text-brand-fg hover:text-white transition-all hover:scale-110\" aria-label=\"Instagram\"><svg class=\"w-4 h-4\" fill=\"currentColor\" viewBox=\"0 0 24 24\"><path d=\"M12 2.163c3.204 0 3.584.012 4.85.07 3.252.148 4.771 1.691 4.919 4.919.058 1.265.069 1.645.069 4.849 0 3.205-.012 3.584-.069 4.849-.149 3.225-1.664 4.771-4.919 4.919-1.266.058-1.644.07-4.85.07-3.204 0-3.584-.012-4.849-.07-3.26-.149-4.771-1.699-4.919-4.92-.058-1.265-.07-1.644-.07-4.849 0-3.204.013-3.583.07-4.849.149-3.227 1.664-4.771 4.919-4.919 1.266-.057 1.645-.069 4.849-.069zm0-2.163c-3.259 0-3.667.014-4.947.072-4.358.2-6.78 2.618-6.98 6.98-.059 1.281-.073 1.689-.073 4.948 0 3.259.014 3.668.072 4.948.2 4.358 2.618 6.78 6.98 6.98 1.281.058 1.689.072 4.948.072 3.259 0 3.668-.014 4.948-.072 4.354-.2 6.782-2.618 6.979-6.98.059-1.28.073-1.689.073-4.948 0-3.259-.014-3.667-.072-4.947-.196-4.354-2.617-6.78-6.979-6.98-1.281-.059-1.69-.073-4.949-.073zm0 5.838c-3.403 0-6.162 2.759-6.162 6.162s2.759 6.163 6.162 6.163 6.162-2.759 6.162-6.163c0-3.403-2.759-6.162-6.162-6.162zm0 10.162c-2.209 0-4-1.79-4-4 0-2.209 1.791-4 4-4s4 1.791 4 4c0 2.21-1.791 4-4 4zm6.406-11.845c-.796 0-1.441.645-1.441 1.44s.645 1.44 1.441 1.44c.795 0 1.439-.645 1.439-1.44s-.644-1.44-1.439-1.44z\"></path></svg></a></div></div></div><div class=\"border-t border-border mt-8 pt-8 flex flex-col md:flex-row justify-between items-center text-sm text-muted\"><p>&copy; 2025 C Auto Detailing Studio. All rights reserved.</p><div class=\"flex gap-6 mt-4 md:mt-0\"><a href=\"/privacy\" class=\"hover:text-brand-accent transition\">Privacy Policy</a> <a href=\"/terms\" class=\"hover:text-brand-accent transition\">Terms of Service</a></div></div></div></footer>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<!-- Mobile Sticky Bottom Bar - Optimized for 48px minimum touch targets --><div class=\"mobile-sticky-bar\"><div class=\"btn-group\"><a href=\"/booking\" class=\"btn-primary text-center text-sm min-h-[48px] flex items-center justify-center font-semibold\">Book a Detail</a> <a href=\"tel:+15551234567\" class=\"btn-green text-center text-sm min-h-[48px] flex items-center justify-center gap-2 font-semibold\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 5a2 2 0 012-2h3.28a1 1 0 01.948.684l1.498 4.493a1 1 0 01-.502 1.21l-2.257 1.13a11.042 11.042 0 005.516 5.516l1.13-2.257a1 1 0 011.21-.502l4.493 1.498a1 1 0 01.684.949V19a2 2 0 01-2 2h-1C9.716 21 3 14.284 3 6V5z\"></path></svg> Call/Text</a></div></div><footer class=\"bg-brand-secondary border-t-2 border-border mt-20 mb-20 md:mb-0\"><!-- Call-to-Action Banner --><div class=\"bg-gradient-to-r from-brand-primary/20 to-brand-accent/20 border-b border-brand-accent/30\"><div class=\"container mx-auto px-4 py-8\"><div class=\"flex flex-col md:flex-row items-center justify-between gap-6\"><div><p class=\"text-brand-accent font-script text-xl mb-1\">Get Started Today</p><h3 class=\"text-2xl md:text-3xl font-heading font-bold\">Ready for the Ultimate Detail?</h3></div><div class=\"flex flex-col sm:flex-row gap-3\"><a href=\"/booking\" class=\"btn-primary whitespace-nowrap\">Book Now</a> <a href=\"tel:+15551234567\" class=\"btn-green whitespace-nowrap\"><svg class=\"w-5 h-5 inline-block mr-2\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 5a2 2 0 012-2h3.28a1 1 0 01.948.684l1.498 4.493a1 1 0 01-.502 1.21l-2.257 1.13a11.042 11.042 0 005.516 5.516l1.13-2.257a1 1 0 011.21-.502l4.493 1.498a1 1 0 01.684.949V19a2 2 0 01-2 2h-1C9.716 21 3 14.284 3 6V5z\"></path></svg> Call Now</a></div></div></div></div><div class=\"container mx-auto px-4 py-12\"><div class=\"grid grid-cols-1 md:grid-cols-3 gap-8\"><!-- Brand --><div><h3 class=\"text-xl font-heading font-bold text-brand-accent mb-4\">C Auto Detailing Studio</h3><p class=\"text-muted text-sm mb-4\">Premium automotive detailing services with meticulous care for every vehicle.</p><p class=\"text-brand-accent font-script text-lg\">Excellence in Every Detail</p></div><!-- Quick Links --><div><h4 class=\"font-heading font-bold mb-4 border-l-4 border-brand-accent pl-3\">Quick Links</h4><ul class=\"space-y-2 text-sm\"><li><a href=\"/\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Home</a></li><li><a href=\"/services\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Services</a></li><li><a href=\"/gallery\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Gallery</a></li><li><a href=\"/about\" class=\"text-muted hover:text-brand-accent transition font-semibold\">About Us</a></li><li><a href=\"/contact\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Contact</a></li><li><a href=\"/booking\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Book Now</a></li><li><a href=\"/gift-certificates\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Gift Certificates</a></li><li><a href=\"/memberships\" class=\"text-muted hover:text-brand-accent transition font-semibold\">Memberships</a></li></ul></div><!-- Contact --><div><h4 class=\"font-heading font-bold mb-4 border-l-4 border-brand-accent pl-3\">Get in Touch</h4><ul class=\"space-y-3 text-sm\"><li><a href=\"tel:+15551234567\" class=\"flex items-center gap-2 text-muted hover:text-brand-accent transition group font-semibold\"><svg class=\"w-4 h-4 group-hover:scale-110 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 5a2 2 0 012-2h3.28a1 1 0 01.948.684l1.498 4.493a1 1 0 01-.502 1.21l-2.257 1.13a11.042 11.042 0 005.516 5.516l1.13-2.257a1 1 0 011.21-.502l4.493 1.498a1 1 0 01.684.949V19a2 2 0 01-2 2h-1C9.716 21 3 14.284 3 6V5z\"></path></svg> (555) 123-4567</a></li><li><a href=\"mailto:info@cautodetailingstudio.com\" class=\"flex items-center gap-2 text-muted hover:text-brand-accent transition group font-semibold\"><svg class=\"w-4 h-4 group-hover:scale-110 transition-transform\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 8l7.89 5.26a2 2 0 002.22 0L21 8M5 19h14a2 2 0 002-2V7a2 2 0 00-2-2H5a2 2 0 00-2 2v10a2 2 0 002 2z\"></path></svg> info@cautodetailingstudio.com</a></li><li class=\"flex items-center gap-2 text-muted font-semibold\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M17.657 16.657L13.414 20.9a1.998 1.998 0 01-2.827 0l-4.244-4.243a8 8 0 1111.314 0z\"></path> <path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15 11a3 3 0 11-6 0 3 3 0 016 0z\"></path></svg> Your City, State</li><li class=\"flex items-center gap-2 text-muted font-semibold\"><svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v4l3 3m6-3a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg> Mon-Sat: 8AM-6PM</li></ul><div class=\"flex gap-3 mt-6\"><a href=\"https://facebook.com\" target=\"_blank\" rel=\"noopener noreferrer\" class=\"w-9 h-9 bg-brand-bg hover:bg-brand-accent rounded-full flex items-center justify-center text-brand-fg hover:text-white transition-all hover:scale-110\" aria-label=\"Facebook\"><svg class=\"w-4 h-4\" fill=\"currentColor\" viewBox=\"0 0 24 24\"><path d=\"M24 12.073c0-6.627-5.373-12-12-12s-12 5.373-12 12c0 5.99 4.388 10.954 10.125 11.854v-8.385H7.078v-3.47h3.047V9.43c0-3.007 1.792-4.669 4.533-4.669 1.312 0 2.686.235 2.686.235v2.953H15.83c-1.491 0-1.956.925-1.956 1.874v2.25h3.328l-.532 3.47h-2.796v8.385C19.612 23.027 24 18.062 24 12.073z\"></path></svg></a> <a href=\"https://instagram.com\" target=\"_blank\" rel=\"noopener noreferrer\" class=\"w-9 h-9 bg-brand-bg hover:bg-brand-accent rounded-full flex items-center justify-center text-brand-fg hover:text-white transition-all hover:scale-110\" aria-label=\"Instagram\"><svg class=\"w-4 h-4\" fill=\"currentColor\" viewBox=\"0 0 24 24\"><path d=\"M12 2.163c3.204 0 3.584.012 4.85.07 3.252.148 4.771 1.691 4.919 4.919.058 1.265.069 1.645.069 4.849 0 3.205-.012 3.584-.069 4.849-.149 3.225-1.664 4.771-4.919 4.919-1.266.058-1.644.07-4.85.07-3.204 0-3.584-.012-4.849-.07-3.26-.149-4.771-1.699-4.919-4.92-.058-1.265-.07-1.644-.07-4.849 0-3.204.013-3.583.07-4.849.149-3.227 1.664-4.771 4.919-4.919 1.266-.057 1.645-.069 4.849-.069zm0-2.163c-3.259 0-3.667.014-4.947.072-4.358.2-6.78 2.618-6.98 6.98-.059 1.281-.073 1.689-.073 4.948 0 3.259.014 3.668.072 4.948.2 4.358 2.618 6.78 6.98 6.98 1.281.058 1.689.072 4.948.072 3.259 0 3.668-.014 4.948-.072 4.354-.2 6.782-2.618 6.979-6.98.059-1.28.073-1.689.073-4.948 0-3.259-.014-3.667-.072-4.947-.196-4.354-2.617-6.78-6.979-6.98-1.281-.059-1.69-.073-4.949-.073zm0 5.838c-3.403 0-6.162 2.759-6.162 6.162s2.759 6.163 6.162 6.163 6.162-2.759 6.162-6.163c0-3.403-2.759-6.162-6.162-6.162zm0 10.162c-2.209 0-4-1.79-4-4 0-2.209 1.791-4 4-4s4 1.791 4 4c0 2.21-1.791 4-4 4zm6.406-11.845c-.796 0-1.441.645-1.441 1.44s.645 1.44 1.441 1.44c.795 0 1.439-.645 1.439-1.44s-.644-1.44-1.439-1.44z\"></path></svg></a></div></div></div><div class=\"border-t border-border mt-8 pt-8 flex flex-col md:flex-row justify-between items-center text-sm text-muted\"><p>&copy; 2025 C Auto Detailing Studio. All rights reserved.</p><div class=\"flex gap-6 mt-4 md:mt-0\"><a href=\"/privacy\" class=\"hover:text-brand-accent transition\">Privacy Policy</a> <a href=\"/terms\" class=\"hover:text-brand-accent transition\">Terms of Service</a></div></div></div></footer>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -8,7 +8,11 @@ package pages
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "detailingpass/web/templates"
+import (
+	"detailingpass/pkg/db"
+	"detailingpass/web/templates"
+	"fmt"
+)
 
 type BookingSlot struct {
 	ID          string
@@ -18,7 +22,28 @@ type BookingSlot struct {
 }
 
 type BookingPageData struct {
-	Slots []BookingSlot
+	Slots           []BookingSlot
+	Packages        []db.Package // active packages for the service select
+	SelectedPackage string       // slug preselected from ?package=
+	DepositPolicy   string       // set when a deposit is taken with each request
+	Membership      *BookingMembership
+	MobileService   bool // we'll come to customers in the service area
+}
+
+// BookingMembership is the signed-in customer's membership, when it's in
+// good standing
+type BookingMembership struct {
+	Plan      string
+	Service   string // the package included; empty for any
+	Remaining int64  // services left this period
+}
+
+func bookingMembershipLabel(m *BookingMembership) string {
+	label := fmt.Sprintf("Use my %s membership (%d left this period)", m.Plan, m.Remaining)
+	if m.Service != "" {
+		label += " · " + m.Service + " only"
+	}
+	return label
 }
 
 func Booking(data BookingPageData) templ.Component {
@@ -54,56 +79,234 @@ func Booking(data BookingPageData) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"bg-brand-bg text-brand-fg py-10 sm:py-16\"><div class=\"container mx-auto px-4\"><div class=\"max-w-5xl mx-auto mb-8 sm:mb-12 text-center\"><p class=\"text-xs sm:text-sm uppercase tracking-[0.2em] sm:tracking-[0.3em] text-brand-accent mb-2 sm:mb-4\">Schedule</p><h1 class=\"text-3xl sm:text-4xl md:text-5xl font-heading font-bold mb-3 sm:mb-4\">Lock In Your Detailing Session</h1><p class=\"text-base sm:text-lg text-muted max-w-3xl mx-auto\">Choose an available date and time that works for you. Once we receive your request we'll confirm all of the details and follow up with any prep instructions.</p></div><div id=\"booking-app\" class=\"grid lg:grid-cols-3 gap-6 sm:gap-8\" data-availability-endpoint=\"/api/bookings/availability\" data-submit-endpoint=\"/api/bookings\"><div class=\"lg:col-span-2 space-y-6 sm:space-y-8\"><section class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6\"><div class=\"flex flex-col gap-4 mb-4 sm:mb-6\"><div><p class=\"text-xs uppercase tracking-[0.25em] sm:tracking-[0.35em] text-muted mb-1 sm:mb-2\">Step 1</p><h2 class=\"text-xl sm:text-2xl font-heading font-semibold\">Select a Date</h2><p class=\"text-sm text-muted mt-1\">We'll disable any dates or times as soon as they are claimed.</p></div><div class=\"flex items-center justify-center sm:justify-start gap-3\"><button type=\"button\" data-month-nav=\"prev\" class=\"p-2.5 rounded-full border border-border hover:border-brand-accent transition active:scale-95\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15 19l-7-7 7-7\"></path></svg></button><div class=\"text-center min-w-[140px]\"><p class=\"text-xs sm:text-sm text-muted\">Viewing</p><p data-calendar-range class=\"font-semibold text-sm sm:text-base\">Loading…</p></div><button type=\"button\" data-month-nav=\"next\" class=\"p-2.5 rounded-full border border-border hover:border-brand-accent transition active:scale-95\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 5l7 7-7 7\"></path></svg></button></div></div><div class=\"hidden sm:grid grid-cols-7 gap-2 text-xs font-semibold text-muted uppercase tracking-wide mb-3\"><span>Sun</span> <span>Mon</span> <span>Tue</span> <span>Wed</span> <span>Thu</span> <span>Fri</span> <span>Sat</span></div><div data-calendar-days class=\"grid grid-cols-4 sm:grid-cols-7 gap-1.5 sm:gap-2 text-sm\"><!-- Populated via booking.js --><div class=\"col-span-full flex items-center justify-center text-muted text-sm py-6\">Loading availability…</div></div></section><section class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6\"><div class=\"mb-4 sm:mb-6\"><p class=\"text-xs uppercase tracking-[0.25em] sm:tracking-[0.35em] text-muted mb-1 sm:mb-2\">Step 2</p><h2 class=\"text-xl sm:text-2xl font-heading font-semibold\">Pick a Time Slot</h2><p class=\"text-sm text-muted mt-1\">Slots refresh automatically when a booking comes in, so you always see the live schedule.</p></div><div data-slot-list class=\"grid gap-2 sm:gap-3 sm:grid-cols-2\"><div class=\"border border-border rounded-lg sm:rounded-xl p-3 sm:p-4 text-muted text-sm col-span-full\">Select a date to see available times.</div></div></section></div><div class=\"space-y-6 sm:space-y-8\"><section class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6\"><div class=\"mb-3 sm:mb-4\"><p class=\"text-xs uppercase tracking-[0.25em] sm:tracking-[0.35em] text-muted mb-1 sm:mb-2\">Step 3</p><h2 class=\"text-xl sm:text-2xl font-heading font-semibold\">Tell Us About the Vehicle</h2><p class=\"text-sm text-muted\">Share a few quick details so we can prepare the right game plan.</p></div><div id=\"booking-feedback\" class=\"hidden mb-3 sm:mb-4 rounded-lg sm:rounded-xl border border-brand-accent/40 bg-brand-accent/10 p-2.5 sm:p-3 text-sm\"></div><form id=\"booking-form\" class=\"space-y-3 sm:space-y-4\"><input type=\"hidden\" name=\"selected_date\"> <input type=\"hidden\" name=\"slot_id\"><div data-selection-pill class=\"hidden rounded-lg sm:rounded-xl border border-brand-accent/40 bg-brand-accent/5 px-3 sm:px-4 py-2.5 sm:py-3 text-sm text-brand-fg/80\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Full Name *</label> <input name=\"name\" type=\"text\" required class=\"input text-base\" placeholder=\"Logan Lanou\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Email *</label> <input name=\"email\" type=\"email\" required class=\"input text-base\" placeholder=\"hello@detailingpass.com\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Phone</label> <input name=\"phone\" type=\"tel\" class=\"input text-base\" placeholder=\"(704) 555-0118\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Vehicle / Notes</label> <textarea name=\"vehicle\" rows=\"2\" class=\"input text-base\" placeholder=\"2023 Rivian R1S • daily driver\"></textarea></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Service Focus</label> <select name=\"service\" class=\"input text-base\"><option value=\"\">Select a package</option> <option value=\"full-detail\">Full Detail</option> <option value=\"interior-reset\">Interior Reset</option> <option value=\"paint-correction\">Paint Correction</option> <option value=\"coating\">Ceramic Coating</option> <option value=\"fleet\">Fleet / Multi-Vehicle</option></select></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Anything Else?</label> <textarea name=\"notes\" rows=\"3\" class=\"input text-base\" placeholder=\"Add specific concerns or requests\"></textarea></div><button type=\"submit\" class=\"btn-primary w-full flex items-center justify-center gap-2 py-3.5 sm:py-3 text-base active:scale-[0.98]\"><span>Submit Booking Request</span> <svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M17 8l4 4m0 0l-4 4m4-4H3\"></path></svg></button><p class=\"text-xs text-muted text-center\">No charges today — we'll confirm and send checkout options once approved.</p></form></section><section class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6 space-y-3 sm:space-y-4\"><h3 class=\"text-base sm:text-lg font-heading font-semibold\">Sessions Offered</h3>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"bg-brand-bg text-brand-fg py-10 sm:py-16\"><div class=\"container mx-auto px-4\"><div class=\"max-w-5xl mx-auto mb-8 sm:mb-12 text-center\"><p class=\"text-xs sm:text-sm uppercase tracking-[0.2em] sm:tracking-[0.3em] text-brand-accent mb-2 sm:mb-4\">Schedule</p><h1 class=\"text-3xl sm:text-4xl md:text-5xl font-heading font-bold mb-3 sm:mb-4\">Lock In Your Detailing Session</h1><p class=\"text-base sm:text-lg text-muted max-w-3xl mx-auto\">Choose an available date and time that works for you. Once we receive your request we'll confirm all of the details and follow up with any prep instructions.</p></div><div id=\"booking-app\" class=\"grid lg:grid-cols-3 gap-6 sm:gap-8\" data-availability-endpoint=\"/api/bookings/availability\" data-submit-endpoint=\"/api/bookings\" data-waitlist-endpoint=\"/api/waitlist\" data-hold-endpoint=\"/api/bookings/holds\" data-stream-endpoint=\"/api/bookings/stream\" data-service-area-endpoint=\"/api/bookings/service-area\"><div class=\"lg:col-span-2 space-y-6 sm:space-y-8\"><section class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6\"><div class=\"flex flex-col gap-4 mb-4 sm:mb-6\"><div><p class=\"text-xs uppercase tracking-[0.25em] sm:tracking-[0.35em] text-muted mb-1 sm:mb-2\">Step 1</p><h2 class=\"text-xl sm:text-2xl font-heading font-semibold\">Select a Date</h2><p class=\"text-sm text-muted mt-1\">We'll disable any dates or times as soon as they are claimed.</p></div><div class=\"flex items-center justify-center sm:justify-start gap-3\"><button type=\"button\" data-month-nav=\"prev\" class=\"p-2.5 rounded-full border border-border hover:border-brand-accent transition active:scale-95\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15 19l-7-7 7-7\"></path></svg></button><div class=\"text-center min-w-[140px]\"><p class=\"text-xs sm:text-sm text-muted\">Viewing</p><p data-calendar-range class=\"font-semibold text-sm sm:text-base\">Loading…</p></div><button type=\"button\" data-month-nav=\"next\" class=\"p-2.5 rounded-full border border-border hover:border-brand-accent transition active:scale-95\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 5l7 7-7 7\"></path></svg></button></div></div><div class=\"hidden sm:grid grid-cols-7 gap-2 text-xs font-semibold text-muted uppercase tracking-wide mb-3\"><span>Sun</span> <span>Mon</span> <span>Tue</span> <span>Wed</span> <span>Thu</span> <span>Fri</span> <span>Sat</span></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			for _, slot := range data.Slots {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div class=\"border border-border rounded-lg sm:rounded-xl p-3 sm:p-4 bg-brand-bg/40\"><div class=\"flex items-center justify-between mb-1.5 sm:mb-2 gap-2\"><p class=\"font-semibold text-sm sm:text-base\">")
+			if data.MobileService {
+				templ_7745c5c3_Err = bookingLocationPicker().Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div data-calendar-days class=\"grid grid-cols-4 sm:grid-cols-7 gap-1.5 sm:gap-2 text-sm\"><!-- Populated via booking.js --><div class=\"col-span-full flex items-center justify-center text-muted text-sm py-6\">Loading availability…</div></div></section><section class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6\"><div class=\"mb-4 sm:mb-6\"><p class=\"text-xs uppercase tracking-[0.25em] sm:tracking-[0.35em] text-muted mb-1 sm:mb-2\">Step 2</p><h2 class=\"text-xl sm:text-2xl font-heading font-semibold\">Pick a Time Slot</h2><p class=\"text-sm text-muted mt-1\">Picking a time holds it for you for 10 minutes while you fill in your details.</p></div><div data-slot-list class=\"grid gap-2 sm:gap-3 sm:grid-cols-2\"><div class=\"border border-border rounded-lg sm:rounded-xl p-3 sm:p-4 text-muted text-sm col-span-full\">Select a date to see available times.</div></div></section></div><div class=\"space-y-6 sm:space-y-8\"><section class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6\"><div class=\"mb-3 sm:mb-4\"><p class=\"text-xs uppercase tracking-[0.25em] sm:tracking-[0.35em] text-muted mb-1 sm:mb-2\">Step 3</p><h2 class=\"text-xl sm:text-2xl font-heading font-semibold\">Tell Us About the Vehicle</h2><p class=\"text-sm text-muted\">Share a few quick details so we can prepare the right game plan.</p></div><div id=\"booking-feedback\" class=\"hidden mb-3 sm:mb-4 rounded-lg sm:rounded-xl border border-brand-accent/40 bg-brand-accent/10 p-2.5 sm:p-3 text-sm\"></div><form id=\"booking-form\" class=\"space-y-3 sm:space-y-4\"><input type=\"hidden\" name=\"selected_date\"> <input type=\"hidden\" name=\"slot_id\"><div data-selection-pill class=\"hidden rounded-lg sm:rounded-xl border border-brand-accent/40 bg-brand-accent/5 px-3 sm:px-4 py-2.5 sm:py-3 text-sm text-brand-fg/80\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Full Name *</label> <input name=\"name\" type=\"text\" required class=\"input text-base\" placeholder=\"Logan Lanou\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Email *</label> <input name=\"email\" type=\"email\" required class=\"input text-base\" placeholder=\"hello@detailingpass.com\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Phone</label> <input name=\"phone\" type=\"tel\" class=\"input text-base\" placeholder=\"(704) 555-0118\"></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if data.MobileService {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div data-address-field class=\"hidden\"><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Address *</label> <input name=\"address\" type=\"text\" class=\"input text-base\" autocomplete=\"street-address\" placeholder=\"Street, city\"><p class=\"text-xs text-muted mt-1\">We'll need room to work and access to water and power.</p></div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Vehicle / Notes</label> <textarea name=\"vehicle\" rows=\"2\" class=\"input text-base\" placeholder=\"2023 Rivian R1S • daily driver\"></textarea></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Service Focus</label> <select name=\"service\" class=\"input text-base\"><option value=\"\">Select a package</option> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, pkg := range data.Packages {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var3 string
-				templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(slot.Label)
+				templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(pkg.Slug)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 151, Col: 68}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 165, Col: 35}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</p><span class=\"text-xs text-brand-accent whitespace-nowrap\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				if pkg.Slug == data.SelectedPackage {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, " selected")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, ">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var4 string
-				templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(slot.Duration)
+				templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(pkg.Name)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 152, Col: 83}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 165, Col: 95}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</span></div><p class=\"text-xs sm:text-sm text-muted\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</option> ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<option value=\"fleet\">Fleet / Multi-Vehicle</option> <option value=\"other\">Not sure yet</option></select></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if data.Membership != nil {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<label class=\"flex items-start gap-3 rounded-lg sm:rounded-xl border border-brand-accent/40 bg-brand-accent/5 px-3 sm:px-4 py-2.5 sm:py-3 text-sm cursor-pointer\"><input type=\"checkbox\" name=\"use_membership\" value=\"true\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				if data.Membership.Remaining > 0 {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, " checked")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, " class=\"mt-0.5 h-4 w-4\"> <span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var5 string
-				templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(slot.Description)
+				templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(bookingMembershipLabel(data.Membership))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 154, Col: 68}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 175, Col: 52}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</p></div>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, " <span class=\"block text-xs text-muted\">Members can book up to 90 days ahead.</span></span></label>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Promo Code</label> <input name=\"promo_code\" type=\"text\" class=\"input text-base uppercase\" autocomplete=\"off\" placeholder=\"Optional\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Anything Else?</label> <textarea name=\"notes\" rows=\"3\" class=\"input text-base\" placeholder=\"Add specific concerns or requests\"></textarea></div><button type=\"submit\" class=\"btn-primary w-full flex items-center justify-center gap-2 py-3.5 sm:py-3 text-base active:scale-[0.98]\"><span>Submit Booking Request</span> <svg class=\"w-4 h-4\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M17 8l4 4m0 0l-4 4m4-4H3\"></path></svg></button> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if data.DepositPolicy != "" {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<p class=\"text-xs text-muted text-center\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var6 string
+				templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(data.DepositPolicy)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 196, Col: 71}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "</p>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			} else {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "<p class=\"text-xs text-muted text-center\">No charges today — we'll confirm and send checkout options once approved.</p>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "</form></section><section id=\"booking-waitlist\" class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6\"><div class=\"mb-3 sm:mb-4\"><h3 class=\"text-base sm:text-lg font-heading font-semibold\">Time You Want Is Taken?</h3><p class=\"text-sm text-muted\">Join the waitlist with your details above. If a booking is cancelled we'll email you in turn, and hold the time for you while you decide.</p></div><div id=\"waitlist-feedback\" class=\"hidden mb-3 sm:mb-4 rounded-lg sm:rounded-xl border border-brand-accent/40 bg-brand-accent/10 p-2.5 sm:p-3 text-sm\"></div><form id=\"waitlist-form\" class=\"space-y-3 sm:space-y-4\"><div class=\"grid grid-cols-2 gap-3\"><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">From *</label> <input name=\"date_from\" type=\"date\" required class=\"input text-base\"></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">To</label> <input name=\"date_to\" type=\"date\" class=\"input text-base\"></div></div><div><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Session</label> <select name=\"slot_id\" class=\"input text-base\"><option value=\"\">Any time</option> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, slot := range data.Slots {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "<option value=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var7 string
+				templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(slot.ID)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 227, Col: 34}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var8 string
+				templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(slot.Label)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 227, Col: 49}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "</option>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "</select></div><button type=\"submit\" class=\"btn-secondary w-full py-3 text-base active:scale-[0.98]\">Join the Waitlist</button></form></section><section class=\"bg-brand-secondary border border-border rounded-xl sm:rounded-2xl p-4 sm:p-6 space-y-3 sm:space-y-4\"><h3 class=\"text-base sm:text-lg font-heading font-semibold\">Sessions Offered</h3>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, slot := range data.Slots {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "<div class=\"border border-border rounded-lg sm:rounded-xl p-3 sm:p-4 bg-brand-bg/40\"><div class=\"flex items-center justify-between mb-1.5 sm:mb-2 gap-2\"><p class=\"font-semibold text-sm sm:text-base\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var9 string
+				templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(slot.Label)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 240, Col: 68}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</p><span class=\"text-xs text-brand-accent whitespace-nowrap\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var10 string
+				templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(slot.Duration)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 241, Col: 83}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "</span></div><p class=\"text-xs sm:text-sm text-muted\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var11 string
+				templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(slot.Description)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/booking.templ`, Line: 243, Col: 68}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "</p></div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "<div class=\"rounded-lg sm:rounded-xl border border-brand-accent/30 bg-brand-accent/10 p-3 sm:p-4 text-sm text-muted\"><p class=\"font-semibold text-brand-fg mb-1\">Need a custom window?</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if data.MobileService {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "<p class=\"text-xs sm:text-sm\">Leave a note in the form and we'll accommodate after-hours requests whenever possible.</p>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			} else {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "<p class=\"text-xs sm:text-sm\">Leave a note in the form and we'll accommodate mobile or after-hours requests whenever possible.</p>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<div class=\"rounded-lg sm:rounded-xl border border-brand-accent/30 bg-brand-accent/10 p-3 sm:p-4 text-sm text-muted\"><p class=\"font-semibold text-brand-fg mb-1\">Need a custom window?</p><p class=\"text-xs sm:text-sm\">Leave a note in the form and we'll accommodate mobile or after-hours requests whenever possible.</p></div></section></div></div></div></div><script defer src=\"/static/js/booking.js\"></script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "</div></section></div></div></div></div><script defer src=\"/static/js/booking.js\"></script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -117,4 +320,36 @@ func Booking(data BookingPageData) templ.Component {
 	})
 }
 
+// bookingLocationPicker asks whether we should come to the customer. Their
+// ZIP is checked against the service area, and the calendar then leaves
+// out times we couldn't drive to.
+func bookingLocationPicker() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var12 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var12 == nil {
+			templ_7745c5c3_Var12 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "<div data-location-picker class=\"mb-4 sm:mb-6 rounded-lg sm:rounded-xl border border-border bg-brand-bg/40 p-3 sm:p-4 space-y-3\"><div class=\"flex flex-wrap gap-4 text-sm\"><label class=\"flex items-center gap-2 cursor-pointer\"><input type=\"radio\" name=\"service_location\" value=\"shop\" checked class=\"h-4 w-4\"> <span>Bring it to our shop</span></label> <label class=\"flex items-center gap-2 cursor-pointer\"><input type=\"radio\" name=\"service_location\" value=\"mobile\" class=\"h-4 w-4\"> <span>Come to me</span></label></div><div data-zip-field class=\"hidden\"><label class=\"text-sm font-medium block mb-1.5 sm:mb-2\">Your ZIP code</label><div class=\"flex gap-2\"><input name=\"zip\" type=\"text\" inputmode=\"numeric\" maxlength=\"10\" autocomplete=\"postal-code\" class=\"input text-base\" placeholder=\"54401\"> <button type=\"button\" data-zip-check class=\"btn-secondary whitespace-nowrap px-4\">Check</button></div><p data-zip-result class=\"text-xs text-muted mt-1.5\">We'll show the times we can get to you.</p></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
 var _ = templruntime.GeneratedTemplate
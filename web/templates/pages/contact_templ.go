@@ -8,9 +8,29 @@ package pages
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "detailingpass/web/templates"
+import (
+	"detailingpass/pkg/db"
+	"detailingpass/web/templates"
+)
 
-func Contact() templ.Component {
+// ContactFormValues refills the form after a rejected submission
+type ContactFormValues struct {
+	Name    string
+	Email   string
+	Phone   string
+	Service string
+	Message string
+}
+
+type ContactData struct {
+	Packages     []db.Package
+	Token        string // signed render time for the timing trap
+	Form         ContactFormValues
+	Sent         bool
+	ErrorMessage string
+}
+
+func Contact(data ContactData) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -43,7 +63,157 @@ func Contact() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"container mx-auto px-4 py-16\"><div class=\"max-w-4xl mx-auto\"><h1 class=\"text-4xl font-heading font-bold mb-8 text-center\">Get in Touch</h1><div class=\"grid grid-cols-1 md:grid-cols-2 gap-12\"><!-- Contact Form --><div><form id=\"contact-form\" class=\"space-y-6\"><div><label for=\"name\" class=\"block text-sm font-medium mb-2\">Name *</label> <input type=\"text\" id=\"name\" name=\"name\" required class=\"input\"></div><div><label for=\"email\" class=\"block text-sm font-medium mb-2\">Email *</label> <input type=\"email\" id=\"email\" name=\"email\" required class=\"input\"></div><div><label for=\"phone\" class=\"block text-sm font-medium mb-2\">Phone</label> <input type=\"tel\" id=\"phone\" name=\"phone\" class=\"input\"></div><div><label for=\"service\" class=\"block text-sm font-medium mb-2\">Service Interested In</label> <select id=\"service\" name=\"service\" class=\"input\"><option value=\"\">Select a package</option> <option value=\"exterior\">Exterior Detail</option> <option value=\"interior\">Interior Detail</option> <option value=\"full\">Full Detail</option> <option value=\"other\">Other</option></select></div><div><label for=\"message\" class=\"block text-sm font-medium mb-2\">Message *</label> <textarea id=\"message\" name=\"message\" rows=\"4\" required class=\"input\"></textarea></div><!-- Honeypot spam trap --><input type=\"text\" name=\"website\" style=\"display:none\" tabindex=\"-1\" autocomplete=\"off\"> <button type=\"submit\" class=\"btn-primary w-full\">Send Message</button></form></div><!-- Contact Info & Booking --><div><div class=\"bg-brand-secondary p-8 rounded-lg mb-6\"><h3 class=\"text-xl font-heading font-semibold mb-4\">Contact Information</h3><div class=\"space-y-4\"><div><p class=\"text-sm text-muted mb-1\">Phone</p><a href=\"tel:+15551234567\" class=\"font-semibold hover:text-brand-accent transition\">(555) 123-4567</a></div><div><p class=\"text-sm text-muted mb-1\">Email</p><a href=\"mailto:info@detailingpass.com\" class=\"font-semibold hover:text-brand-accent transition\">info@detailingpass.com</a></div><div><p class=\"text-sm text-muted mb-1\">Location</p><p class=\"font-semibold\">Serving the Greater Metro Area</p><p class=\"text-sm text-muted mt-1\">Contact us for exact address</p></div><div><p class=\"text-sm text-muted mb-1\">Business Hours</p><p class=\"font-semibold\">Monday - Friday: 8:00 AM - 6:00 PM</p><p class=\"font-semibold\">Saturday: 9:00 AM - 4:00 PM</p><p class=\"font-semibold\">Sunday: Closed</p></div></div></div><div class=\"bg-gradient-to-r from-brand-primary to-brand-accent p-8 rounded-lg text-center\"><h3 class=\"text-xl font-heading font-semibold mb-2\">Quick Response</h3><p class=\"mb-4 opacity-90 text-sm\">We typically respond within 2-4 hours during business hours</p><div class=\"flex items-center justify-center gap-2 text-sm opacity-90\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> <span>Fast & Professional Service</span></div><a href=\"/booking\" class=\"btn-secondary w-full mt-6\">Check Live Availability</a></div></div></div></div></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"container mx-auto px-4 py-16\"><div class=\"max-w-4xl mx-auto\"><h1 class=\"text-4xl font-heading font-bold mb-8 text-center\">Get in Touch</h1><div class=\"grid grid-cols-1 md:grid-cols-2 gap-12\"><!-- Contact Form --><div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if data.Sent {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div class=\"bg-green-500/10 border border-green-500/20 text-green-400 px-4 py-3 rounded-lg mb-6\"><p class=\"font-semibold\">Thank you for contacting us!</p><p class=\"text-sm mt-1\">We'll get back to you within 2-4 business hours.</p></div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			if data.ErrorMessage != "" {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div class=\"bg-red-500/10 border border-red-500/20 text-red-400 px-4 py-3 rounded-lg mb-6\"><p class=\"font-semibold\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var3 string
+				templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(data.ErrorMessage)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/contact.templ`, Line: 42, Col: 52}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</p></div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<form id=\"contact-form\" method=\"POST\" action=\"/contact\" class=\"space-y-6\"><div><label for=\"name\" class=\"block text-sm font-medium mb-2\">Name *</label> <input type=\"text\" id=\"name\" name=\"name\" value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var4 string
+			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(data.Form.Name)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/contact.templ`, Line: 48, Col: 71}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\" required class=\"input\"></div><div><label for=\"email\" class=\"block text-sm font-medium mb-2\">Email *</label> <input type=\"email\" id=\"email\" name=\"email\" value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var5 string
+			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(data.Form.Email)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/contact.templ`, Line: 53, Col: 75}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\" required class=\"input\"></div><div><label for=\"phone\" class=\"block text-sm font-medium mb-2\">Phone</label> <input type=\"tel\" id=\"phone\" name=\"phone\" value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var6 string
+			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(data.Form.Phone)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/contact.templ`, Line: 58, Col: 73}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\" class=\"input\"></div><div><label for=\"service\" class=\"block text-sm font-medium mb-2\">Service Interested In</label> <select id=\"service\" name=\"service\" class=\"input\"><option value=\"\">Select a package</option> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, pkg := range data.Packages {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<option value=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var7 string
+				templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(pkg.Slug)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/contact.templ`, Line: 66, Col: 34}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				if pkg.Slug == data.Form.Service {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, " selected")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, ">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var8 string
+				templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(pkg.Name)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/contact.templ`, Line: 66, Col: 91}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "</option> ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<option value=\"other\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if data.Form.Service == "other" {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, " selected")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, ">Other</option></select></div><div><label for=\"message\" class=\"block text-sm font-medium mb-2\">Message *</label> <textarea id=\"message\" name=\"message\" rows=\"4\" maxlength=\"5000\" required class=\"input\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var9 string
+			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(data.Form.Message)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/contact.templ`, Line: 74, Col: 114}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "</textarea></div><!-- Spam traps: a honeypot people never see, and the time the form was loaded --><input type=\"text\" name=\"website\" style=\"display:none\" tabindex=\"-1\" autocomplete=\"off\"> <input type=\"hidden\" name=\"form_token\" value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var10 string
+			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(data.Token)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/pages/contact.templ`, Line: 79, Col: 64}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\"> <button type=\"submit\" class=\"btn-primary w-full\">Send Message</button></form></div><!-- Contact Info & Booking --><div><div class=\"bg-brand-secondary p-8 rounded-lg mb-6\"><h3 class=\"text-xl font-heading font-semibold mb-4\">Contact Information</h3><div class=\"space-y-4\"><div><p class=\"text-sm text-muted mb-1\">Phone</p><a href=\"tel:+15551234567\" class=\"font-semibold hover:text-brand-accent transition\">(555) 123-4567</a></div><div><p class=\"text-sm text-muted mb-1\">Email</p><a href=\"mailto:info@detailingpass.com\" class=\"font-semibold hover:text-brand-accent transition\">info@detailingpass.com</a></div><div><p class=\"text-sm text-muted mb-1\">Location</p><p class=\"font-semibold\">Serving the Greater Metro Area</p><p class=\"text-sm text-muted mt-1\">Contact us for exact address</p></div><div><p class=\"text-sm text-muted mb-1\">Business Hours</p><p class=\"font-semibold\">Monday - Friday: 8:00 AM - 6:00 PM</p><p class=\"font-semibold\">Saturday: 9:00 AM - 4:00 PM</p><p class=\"font-semibold\">Sunday: Closed</p></div></div></div><div class=\"bg-gradient-to-r from-brand-primary to-brand-accent p-8 rounded-lg text-center\"><h3 class=\"text-xl font-heading font-semibold mb-2\">Quick Response</h3><p class=\"mb-4 opacity-90 text-sm\">We typically respond within 2-4 hours during business hours</p><div class=\"flex items-center justify-center gap-2 text-sm opacity-90\"><svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> <span>Fast & Professional Service</span></div><a href=\"/booking\" class=\"btn-secondary w-full mt-6\">Check Live Availability</a></div></div></div></div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -0,0 +1,97 @@
+package pages
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"detailingpass/pkg/db"
+)
+
+func TestFormatDollars(t *testing.T) {
+	tests := []struct {
+		cents int64
+		want  string
+	}{
+		{0, "$0"},
+		{15000, "$150"},
+		{15050, "$150.50"},
+		{15005, "$150.05"},
+		{99, "$0.99"},
+		{123456700, "$1234567"},
+	}
+	for _, tt := range tests {
+		if got := formatDollars(tt.cents); got != tt.want {
+			t.Errorf("formatDollars(%d) = %q, want %q", tt.cents, got, tt.want)
+		}
+	}
+}
+
+func TestPackagePriceLabel(t *testing.T) {
+	cents := func(v int64) sql.NullInt64 { return sql.NullInt64{Int64: v, Valid: true} }
+
+	tests := []struct {
+		name     string
+		min, max sql.NullInt64
+		want     string
+	}{
+		{name: "range", min: cents(15000), max: cents(25000), want: "Starting at $150"},
+		{name: "fixed price", min: cents(15000), max: cents(15000), want: "$150"},
+		{name: "no max", min: cents(8950), want: "$89.50"},
+		{name: "max below min", min: cents(15000), max: cents(10000), want: "$150"},
+		{name: "no price", want: "Call for pricing"},
+		{name: "zero price", min: cents(0), max: cents(5000), want: "Call for pricing"},
+	}
+	for _, tt := range tests {
+		got := PackagePriceLabel(db.Package{PriceMin: tt.min, PriceMax: tt.max})
+		if got != tt.want {
+			t.Errorf("%s: PackagePriceLabel = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPackageDurationLabel(t *testing.T) {
+	tests := []struct {
+		minutes sql.NullInt64
+		want    string
+	}{
+		{sql.NullInt64{}, ""},
+		{sql.NullInt64{Int64: 0, Valid: true}, ""},
+		{sql.NullInt64{Int64: 45, Valid: true}, "About 45 min"},
+		{sql.NullInt64{Int64: 60, Valid: true}, "About 1 hr"},
+		{sql.NullInt64{Int64: 90, Valid: true}, "About 1.5 hrs"},
+		{sql.NullInt64{Int64: 180, Valid: true}, "About 3 hrs"},
+		{sql.NullInt64{Int64: 200, Valid: true}, "About 3.3 hrs"},
+	}
+	for _, tt := range tests {
+		if got := PackageDurationLabel(db.Package{DurationEst: tt.minutes}); got != tt.want {
+			t.Errorf("PackageDurationLabel(%v) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"Hand wash\nClay bar\nWax", []string{"Hand wash", "Clay bar", "Wax"}},
+		{"  Hand wash \r\n\n\n  Wax\n", []string{"Hand wash", "Wax"}},
+		{"\n \n", nil},
+	}
+	for _, tt := range tests {
+		if got := splitLines(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitLines(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBookingURL(t *testing.T) {
+	if got, want := string(bookingURL("full-detail")), "/booking?package=full-detail"; got != want {
+		t.Errorf("bookingURL = %q, want %q", got, want)
+	}
+	if got, want := string(bookingURL("a&b c")), "/booking?package=a%26b+c"; got != want {
+		t.Errorf("bookingURL = %q, want %q", got, want)
+	}
+}